@@ -79,6 +79,8 @@ type A2AAgent struct {
 	buildMessageHook     BuildMessageHook       // Hook called after A2A message is built but before it is sent
 	userIDHeader         string                 // HTTP header name to send UserID to A2A server
 	enableStreaming      *bool                  // Explicitly set streaming mode; nil means use agent card capability
+	resubscribeAttempts  int                    // Resubscribe attempts after a dropped stream; 0 disables resuming
+	resubscribeBackoff   time.Duration          // Delay before each resubscribe attempt
 
 	requireAnonymousIdentityCoordination bool
 
@@ -1782,6 +1784,9 @@ func (r *A2AAgent) executeStreaming(
 		return
 	}
 
+	if r.resubscribeAttempts > 0 {
+		streamChan = r.resumeStream(streamCtx, a2aClient, streamChan, requestOpts)
+	}
 	streamResult := r.processStreamingEvents(
 		streamCtx,
 		invocation,
@@ -12,6 +12,7 @@ package a2aagent
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/client"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
@@ -357,3 +358,16 @@ func WithRequireAnonymousIdentityCoordination(enabled bool) Option {
 		a.requireAnonymousIdentityCoordination = enabled
 	}
 }
+
+// WithTaskResubscribe lets streaming runs resume a remote task when the
+// connection drops before the task finished. The agent calls
+// tasks/resubscribe up to maxAttempts times in a row, waiting backoff before
+// each attempt, and skips events it has already received. The remote server
+// must record task events, for example with the WithTaskStore option of
+// server/a2a. The default is no resubscription.
+func WithTaskResubscribe(maxAttempts int, backoff time.Duration) Option {
+	return func(a *A2AAgent) {
+		a.resubscribeAttempts = maxAttempts
+		a.resubscribeBackoff = backoff
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2aagent
+
+import (
+	"context"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	ia2a "trpc.group/trpc-go/trpc-agent-go/internal/a2a"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+// resumeStream forwards the events of stream and, when it ends before the
+// task finished, resubscribes to the task and continues after the last
+// received event. Attempts reset whenever a resubscription makes progress.
+func (r *A2AAgent) resumeStream(
+	ctx context.Context,
+	a2aClient *client.A2AClient,
+	stream <-chan protocol.StreamingMessageEvent,
+	requestOpts []client.RequestOption,
+) <-chan protocol.StreamingMessageEvent {
+	out := make(chan protocol.StreamingMessageEvent, r.streamingBufSize)
+	go func() {
+		defer close(out)
+		var taskID string
+		var lastSeq int64
+		attempts := 0
+		for {
+			progressed := false
+			for evt := range stream {
+				if id := ia2a.StreamingEventTaskID(evt); id != "" {
+					taskID = id
+				}
+				if seq := ia2a.TaskEventSeq(evt); seq > 0 {
+					if seq <= lastSeq {
+						continue
+					}
+					lastSeq = seq
+				}
+				progressed = true
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					return
+				}
+				if ia2a.IsFinalStreamingEvent(evt) {
+					return
+				}
+			}
+			if progressed {
+				attempts = 0
+			}
+			if taskID == "" || attempts >= r.resubscribeAttempts {
+				return
+			}
+			attempts++
+			select {
+			case <-time.After(r.resubscribeBackoff):
+			case <-ctx.Done():
+				return
+			}
+			log.DebugfContext(ctx, "a2aagent: resubscribing to task %s after event %d (attempt %d)",
+				taskID, lastSeq, attempts)
+			next, err := a2aClient.ResubscribeTask(ctx, protocol.TaskIDParams{
+				ID: taskID,
+				Metadata: map[string]any{
+					ia2a.ResubscribeMetadataLastEventSeqKey: lastSeq,
+				},
+			}, requestOpts...)
+			if err != nil {
+				log.WarnfContext(ctx, "a2aagent: failed to resubscribe to task %s: %v", taskID, err)
+				next = closedStream()
+			}
+			stream = next
+		}
+	}()
+	return out
+}
+
+func closedStream() <-chan protocol.StreamingMessageEvent {
+	ch := make(chan protocol.StreamingMessageEvent)
+	close(ch)
+	return ch
+}
@@ -0,0 +1,143 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2aagent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	ia2a "trpc.group/trpc-go/trpc-agent-go/internal/a2a"
+)
+
+func seqStatus(seq int64, state protocol.TaskState, final bool) sseEvent {
+	evt := protocol.NewTaskStatusUpdateEvent("task-1", "ctx-1",
+		protocol.TaskStatus{State: state}, final)
+	evt.Metadata = map[string]any{ia2a.MessageMetadataTaskEventSeqKey: seq}
+	return sseEvent{eventType: protocol.KindTaskStatusUpdate, payload: &evt}
+}
+
+func seqArtifact(seq int64, text string) sseEvent {
+	evt := protocol.NewTaskArtifactUpdateEvent("task-1", "ctx-1", protocol.Artifact{
+		ArtifactID: "a",
+		Parts:      []protocol.Part{protocol.NewTextPart(text)},
+	}, false)
+	evt.Metadata = map[string]any{ia2a.MessageMetadataTaskEventSeqKey: seq}
+	return sseEvent{eventType: protocol.KindTaskArtifactUpdate, payload: &evt}
+}
+
+func TestA2AAgent_ResumeStream(t *testing.T) {
+	var resubscribes atomic.Int32
+	var lastSeq atomic.Int64
+	handler := httpReqHandlerFunc(func(
+		_ context.Context, _ *http.Client, req *http.Request,
+	) (*http.Response, error) {
+		raw, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var rpc struct {
+			Method string                `json:"method"`
+			Params protocol.TaskIDParams `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &rpc))
+		var body string
+		switch rpc.Method {
+		case protocol.MethodTasksResubscribe:
+			n := resubscribes.Add(1)
+			lastSeq.Store(ia2a.LastEventSeq(rpc.Params.Metadata))
+			if n == 1 {
+				// The first attempt drops again without progress.
+				body = mustBuildSSEBody(t, []sseEvent{seqArtifact(2, "two")})
+				break
+			}
+			body = mustBuildSSEBody(t, []sseEvent{
+				seqArtifact(2, "two"),
+				seqArtifact(3, "three"),
+				seqStatus(4, protocol.TaskStateCompleted, true),
+				seqArtifact(5, "after final"),
+			})
+		default:
+			t.Fatalf("unexpected method %s", rpc.Method)
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		resp.Header.Set("Content-Type", "text/event-stream")
+		return resp, nil
+	})
+	cli, err := client.NewA2AClient("http://stream.test/", client.WithHTTPReqHandler(handler))
+	require.NoError(t, err)
+
+	a := &A2AAgent{
+		streamingBufSize:    8,
+		resubscribeAttempts: 2,
+		resubscribeBackoff:  time.Millisecond,
+	}
+	first := make(chan protocol.StreamingMessageEvent, 2)
+	for _, e := range []sseEvent{
+		seqStatus(1, protocol.TaskStateSubmitted, false),
+		seqArtifact(2, "two"),
+	} {
+		first <- protocol.StreamingMessageEvent{Result: e.payload.(protocol.StreamingMessageResult)}
+	}
+	close(first)
+
+	var seqs []int64
+	for evt := range a.resumeStream(context.Background(), cli, first, nil) {
+		seqs = append(seqs, ia2a.TaskEventSeq(evt))
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, seqs)
+	require.EqualValues(t, 2, resubscribes.Load())
+	require.EqualValues(t, 2, lastSeq.Load())
+}
+
+func TestA2AAgent_ResumeStreamGivesUp(t *testing.T) {
+	var resubscribes atomic.Int32
+	handler := httpReqHandlerFunc(func(
+		context.Context, *http.Client, *http.Request,
+	) (*http.Response, error) {
+		resubscribes.Add(1)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+	cli, err := client.NewA2AClient("http://stream.test/", client.WithHTTPReqHandler(handler))
+	require.NoError(t, err)
+	a := &A2AAgent{resubscribeAttempts: 3, resubscribeBackoff: time.Millisecond}
+
+	first := make(chan protocol.StreamingMessageEvent, 1)
+	e := seqStatus(1, protocol.TaskStateWorking, false)
+	first <- protocol.StreamingMessageEvent{Result: e.payload.(protocol.StreamingMessageResult)}
+	close(first)
+
+	var n int
+	for range a.resumeStream(context.Background(), cli, first, nil) {
+		n++
+	}
+	require.Equal(t, 1, n)
+	require.EqualValues(t, 3, resubscribes.Load())
+
+	// Streams without a task cannot be resumed.
+	resubscribes.Store(0)
+	for range a.resumeStream(context.Background(), cli, closedStream(), nil) {
+	}
+	require.Zero(t, resubscribes.Load())
+}
@@ -16,6 +16,7 @@ import (
 
 	"trpc.group/trpc-go/trpc-agent-go/codeexecutor"
 	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
 )
 
@@ -95,6 +96,30 @@ type SubAgentSetter interface {
 	SetSubAgents(subAgents []Agent)
 }
 
+// RealtimeAgent is implemented by agents that can hold realtime sessions,
+// such as an LLMAgent created with llmagent.WithRealtimeModel. The runner
+// detects it in RunRealtime.
+type RealtimeAgent interface {
+	Agent
+	// RealtimeRequest returns the realtime model of the agent and the
+	// request that opens a session for the invocation: its instructions,
+	// conversation history and tools.
+	RealtimeRequest(ctx context.Context, invocation *Invocation) (
+		model.RealtimeModel, *model.RealtimeRequest, error,
+	)
+	// RunRealtimeTools runs the tool calls of rsp, a finished response of
+	// a realtime session, with tools the way the tool calls of model runs
+	// are run, including tool callbacks and plugins, and sends the
+	// resulting events to ch. It returns when the calls are done.
+	RunRealtimeTools(
+		ctx context.Context,
+		invocation *Invocation,
+		tools map[string]tool.Tool,
+		rsp *model.Response,
+		ch chan<- *event.Event,
+	)
+}
+
 // CodeExecutor may move to Agent interface, will cause large scale change, consider later.
 // or move to codeexecutor package
 type CodeExecutor interface {
@@ -76,6 +76,7 @@ type LLMAgent struct {
 	modelGlobalInstructions map[string]prompt.Text
 	genConfig               model.GenerationConfig
 	flow                    flow.Flow
+	toolCallProcessor       flow.ResponseProcessor
 	tools                   []tool.Tool     // All tools (user tools + framework tools)
 	userToolNames           map[string]bool // Names of tools explicitly registered
 	// via WithTools and WithToolSets.
@@ -263,6 +264,7 @@ func New(name string, opts ...Option) *LLMAgent {
 		processor.SetDefaultTransferMessage(*options.DefaultTransferMessage)
 	}
 	responseProcessors = append(responseProcessors, toolcallProcessor)
+	a.toolCallProcessor = toolcallProcessor
 
 	// Always install the transfer processor so dynamic sub-agent updates
 	// (for example, via SubAgentSetter) can enable transfer_to_agent later.
@@ -301,6 +303,9 @@ func buildRequestProcessorsWithAgent(a *LLMAgent, options *Options) []flow.Reque
 	basicOptions := []processor.BasicOption{
 		processor.WithGenerationConfig(options.GenerationConfig),
 	}
+	if options.PromptCache != nil {
+		basicOptions = append(basicOptions, processor.WithPromptCache(*options.PromptCache))
+	}
 	basicProcessor := processor.NewBasicRequestProcessor(basicOptions...)
 	requestProcessors = append(requestProcessors, basicProcessor)
 
@@ -323,6 +328,8 @@ func buildRequestProcessorsWithAgent(a *LLMAgent, options *Options) []flow.Reque
 	instructionOpts = append(instructionOpts,
 		processor.WithInstructionResolver(a.instructionForInvocation),
 		processor.WithSystemPromptResolver(a.systemPromptForInvocation),
+		processor.WithLogicTemplates(options.InstructionLogic),
+		processor.WithCompiledTemplates(mustCompileInstructionTemplates(options)),
 	)
 	instructionProcessor := processor.NewInstructionRequestProcessor(
 		"", // static value unused when resolver is present
@@ -661,6 +668,44 @@ func buildRequestProcessors(name string, options *Options) []flow.RequestProcess
 	return buildRequestProcessorsWithAgent(dummy, options)
 }
 
+// mustCompileInstructionTemplates returns the compiled instruction and
+// global instruction templates by their text. Static instructions are
+// compiled when instruction logic is enabled. With InstructionStateKeys
+// set, each template is checked against them. It panics on templates that
+// do not parse or reference unknown state.
+func mustCompileInstructionTemplates(options *Options) map[string]*prompt.Template {
+	templates := make(map[string]*prompt.Template)
+	for _, instruction := range []struct {
+		kind     string
+		text     string
+		compiled *prompt.Template
+	}{
+		{"instruction", options.Instruction, options.InstructionTemplate},
+		{"global instruction", options.GlobalInstruction, options.GlobalInstructionTemplate},
+	} {
+		t := instruction.compiled
+		if t == nil || t.Text().Template != instruction.text {
+			if !options.InstructionLogic || instruction.text == "" {
+				continue
+			}
+			var err error
+			t, err = newTextPrompt(instruction.text).Compile(context.Background())
+			if err != nil {
+				panic(fmt.Sprintf("Invalid LLMAgent configuration: %s: %v",
+					instruction.kind, err))
+			}
+		}
+		if len(options.InstructionStateKeys) > 0 {
+			if err := t.Validate(options.InstructionStateKeys...); err != nil {
+				panic(fmt.Sprintf("Invalid LLMAgent configuration: %s: %v",
+					instruction.kind, err))
+			}
+		}
+		templates[instruction.text] = t
+	}
+	return templates
+}
+
 func newTextPrompt(template string) prompt.Text {
 	return prompt.Text{Template: template}
 }
@@ -993,6 +1038,14 @@ func appendSkillToolsWithRepoAndFlags(
 				),
 			)
 		}
+		if options.skillCapabilityPolicy != nil {
+			loadOpts = append(
+				loadOpts,
+				toolskill.WithLoadToolCapabilityPolicy(
+					*options.skillCapabilityPolicy,
+				),
+			)
+		}
 		allTools = append(
 			allTools,
 			toolskill.NewLoadToolWithOptions(
@@ -1399,6 +1452,12 @@ func buildSkillRunToolWithRepo(
 			toolskill.WithSkillStager(options.skillRunStager),
 		)
 	}
+	if options.skillCapabilityPolicy != nil {
+		runOpts = append(
+			runOpts,
+			toolskill.WithCapabilityPolicy(*options.skillCapabilityPolicy),
+		)
+	}
 	if reg != nil {
 		runOpts = append(runOpts, toolskill.WithWorkspaceRegistry(reg))
 	}
@@ -1550,6 +1609,7 @@ func (a *LLMAgent) Run(ctx context.Context, invocation *agent.Invocation) (e <-c
 		}
 	}
 	ctx = a.withWorkspace(ctx, invocation)
+	a.fetchPromptSources(agent.NewInvocationContext(ctx, invocation), invocation)
 	ctx, span, startedSpan := itrace.StartSpan(
 		ctx,
 		invocation,
@@ -1571,6 +1631,7 @@ func (a *LLMAgent) Run(ctx context.Context, invocation *agent.Invocation) (e <-c
 			promptText,
 			&effectiveGenConfig,
 		)
+		tracePromptMetas(span, a.promptMetasForInvocation(invocation))
 	}
 	tracker := itelemetry.NewInvokeAgentTracker(
 		ctx,
@@ -1813,6 +1874,7 @@ func (a *LLMAgent) wrapEventChannelWithTelemetry(
 	// Create a new channel with the same capacity as the original channel
 	wrappedChan := make(chan *event.Event, cap(originalChan))
 	runCtx := agent.CloneContext(ctx)
+	promptMetas := a.promptMetasForInvocation(invocation)
 	go func(ctx context.Context) {
 		var fullRespEvent *event.Event
 		var traceOutput *atrace.Snapshot
@@ -1845,6 +1907,7 @@ func (a *LLMAgent) wrapEventChannelWithTelemetry(
 		}()
 		// Forward all events from the original channel
 		for evt := range originalChan {
+			a.stampPromptMetas(evt, promptMetas)
 			if trackedEvent := recordWrappedEventTelemetry(
 				evt,
 				tracker,
@@ -2464,6 +2527,9 @@ func (a *LLMAgent) instructionPromptForInvocation(inv *agent.Invocation) prompt.
 			return ins
 		}
 	}
+	if ins, ok := a.sourcedInstruction(inv); ok {
+		return ins
+	}
 	return a.instruction
 }
 
@@ -2493,5 +2559,8 @@ func (a *LLMAgent) systemPromptTextForInvocation(inv *agent.Invocation) prompt.T
 			return prompt
 		}
 	}
+	if prompt, ok := a.sourcedGlobalInstruction(inv); ok {
+		return prompt
+	}
 	return a.systemPrompt
 }
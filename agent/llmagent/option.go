@@ -25,6 +25,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/knowledge/searchfilter"
 	"trpc.group/trpc-go/trpc-agent-go/model"
 	"trpc.group/trpc-go/trpc-agent-go/planner"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 	"trpc.group/trpc-go/trpc-agent-go/skill"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
@@ -260,8 +261,35 @@ type Options struct {
 	// `internal/prompt/adapter/state`. See `Render` there for supported
 	// placeholder forms and resolution rules.
 	ModelGlobalInstructions map[string]string
+	// InstructionSource fetches the instruction template per invocation,
+	// e.g. from a versioned prompt registry. It takes precedence over
+	// Instruction, which is used when the fetch fails.
+	InstructionSource prompt.Source
+	// GlobalInstructionSource fetches the global instruction template per
+	// invocation. It takes precedence over GlobalInstruction, which is used
+	// when the fetch fails.
+	GlobalInstructionSource prompt.Source
+	// InstructionLogic enables conditionals and loops over state in the
+	// instruction templates. See prompt.Template for the syntax.
+	InstructionLogic bool
+	// InstructionTemplate is the compiled instruction template. Its text
+	// is the instruction.
+	InstructionTemplate *prompt.Template
+	// GlobalInstructionTemplate is the compiled global instruction
+	// template. Its text is the global instruction.
+	GlobalInstructionTemplate *prompt.Template
+	// InstructionStateKeys lists the state keys the instruction templates
+	// may reference. When set, the templates are checked against it when
+	// the agent is built.
+	InstructionStateKeys []string
 	// GenerationConfig contains the generation configuration.
 	GenerationConfig model.GenerationConfig
+	// PromptCache is the prompt cache hint attached to every model request.
+	PromptCache *model.PromptCache
+	// RealtimeModel is the model of the realtime sessions of the agent.
+	RealtimeModel model.RealtimeModel
+	// RealtimeConfig configures the realtime sessions of the agent.
+	RealtimeConfig model.RealtimeRequest
 	// ChannelBufferSize is the buffer size for event channels (default: 256).
 	ChannelBufferSize int
 	codeExecutor      codeexecutor.CodeExecutor
@@ -565,6 +593,8 @@ type Options struct {
 	// skillRunStager overrides how skill_run materializes a skill in
 	// the workspace.
 	skillRunStager toolskill.SkillStager
+	// skillCapabilityPolicy bounds the capabilities skills may declare.
+	skillCapabilityPolicy *skill.CapabilityPolicy
 	// workspaceExecAllowedCommands restricts workspace_exec to
 	// allowlisted commands. When non-empty the user's command is
 	// parsed by internal/shellsafe before execution and only simple
@@ -778,6 +808,78 @@ func WithGlobalInstruction(instruction string) Option {
 	}
 }
 
+// WithInstructionSource fetches the instruction template from source at the
+// start of every run, so prompt rollouts and reloads apply without
+// recreating the agent. The name and version in the fetched prompt.Meta are
+// recorded on the agent span and on the events of the run. Run options,
+// surface patches and model-specific instructions still take precedence,
+// and WithInstruction is used when the fetch fails.
+func WithInstructionSource(source prompt.Source) Option {
+	return func(opts *Options) {
+		opts.InstructionSource = source
+	}
+}
+
+// WithGlobalInstructionSource is like WithInstructionSource for the global
+// instruction. WithGlobalInstruction is used when the fetch fails.
+func WithGlobalInstructionSource(source prompt.Source) Option {
+	return func(opts *Options) {
+		opts.GlobalInstructionSource = source
+	}
+}
+
+// WithInstructionLogic enables {{#if}}, {{#unless}} and {{#each}} in the
+// instruction and global instruction templates, evaluated against the same
+// state as placeholders, e.g. {{#each user:orders as o}}{o.id}{{/each}}.
+// Lists and objects stored as JSON in state can be iterated and navigated.
+// The instruction and global instruction are compiled when the agent is
+// built, and New panics if they do not parse. Partials are not available;
+// use WithInstructionTemplate for those. Instructions from run options,
+// sources or SetInstruction that fail to parse are logged and sent without
+// state injection.
+func WithInstructionLogic(enabled bool) Option {
+	return func(opts *Options) {
+		opts.InstructionLogic = enabled
+	}
+}
+
+// WithInstructionTemplate sets the instruction to a compiled template,
+// rendered against state like WithInstructionLogic but with the partials
+// given to prompt.Text.Compile. It replaces WithInstruction.
+func WithInstructionTemplate(template *prompt.Template) Option {
+	return func(opts *Options) {
+		opts.InstructionTemplate = template
+		opts.Instruction = ""
+		if template != nil {
+			opts.Instruction = template.Text().Template
+		}
+	}
+}
+
+// WithGlobalInstructionTemplate is like WithInstructionTemplate for the
+// global instruction. It replaces WithGlobalInstruction.
+func WithGlobalInstructionTemplate(template *prompt.Template) Option {
+	return func(opts *Options) {
+		opts.GlobalInstructionTemplate = template
+		opts.GlobalInstruction = ""
+		if template != nil {
+			opts.GlobalInstruction = template.Text().Template
+		}
+	}
+}
+
+// WithInstructionStateKeys lists the state keys the instruction templates
+// reference, written as in the templates, e.g. "user:tier" or
+// "runtime:locale". New then panics if a logic or compiled instruction
+// template references any other value, so typos surface before a run.
+// Optional placeholders such as {name?} are not checked, and a key covers
+// the paths below it: "user:orders" covers user:orders.0.id.
+func WithInstructionStateKeys(keys ...string) Option {
+	return func(opts *Options) {
+		opts.InstructionStateKeys = append([]string(nil), keys...)
+	}
+}
+
 // WithModelInstructions sets model-specific instruction template overrides.
 // Values use the same placeholder subset as the internal prompt state adapter
 // in `internal/prompt/adapter/state`. See `Render` there for supported
@@ -807,6 +909,36 @@ func WithGenerationConfig(config model.GenerationConfig) Option {
 	}
 }
 
+// WithPromptCache asks the model to cache the stable prefix of each request:
+// the system prompt, the tools and the conversation history as marked by
+// cache. Each provider maps the hint to its native mechanism, see
+// model.PromptCache. When cache.Key is empty the agent name is used, so
+// requests of the same agent share a cache.
+func WithPromptCache(cache model.PromptCache) Option {
+	return func(opts *Options) {
+		opts.PromptCache = &cache
+	}
+}
+
+// WithRealtimeModel sets the model of the realtime sessions that
+// runner.RunRealtime opens for the agent. Sessions use the instruction,
+// conversation history and tools of the agent. The model of normal runs is
+// not affected.
+func WithRealtimeModel(m model.RealtimeModel) Option {
+	return func(opts *Options) {
+		opts.RealtimeModel = m
+	}
+}
+
+// WithRealtimeConfig configures the realtime sessions of the agent, e.g.
+// the voice, output modalities and turn detection. Instructions, Messages
+// and Tools of config are ignored; the agent provides them.
+func WithRealtimeConfig(config model.RealtimeRequest) Option {
+	return func(opts *Options) {
+		opts.RealtimeConfig = config
+	}
+}
+
 // WithMaxLLMCalls sets the optional upper bound on the number of LLM calls
 // allowed per invocation for this agent. When limit is:
 //   - > 0: the limit is enforced per invocation.
@@ -1309,6 +1441,16 @@ func WithSkillRunStager(stager toolskill.SkillStager) Option {
 	}
 }
 
+// WithSkillCapabilityPolicy enforces the capabilities skills declare in
+// their SKILL.md front matter. skill_load fails for skills that declare
+// more than policy allows, and skill_run turns the declaration into the
+// per-run sandbox permissions. See skill.Capabilities.
+func WithSkillCapabilityPolicy(policy skill.CapabilityPolicy) Option {
+	return func(opts *Options) {
+		opts.skillCapabilityPolicy = &policy
+	}
+}
+
 // WithWorkspaceExecAllowedCommands restricts workspace_exec to
 // commands matching cmds.
 //
@@ -19,6 +19,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/internal/flow/processor"
 	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 	"trpc.group/trpc-go/trpc-agent-go/skill"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
@@ -83,6 +84,65 @@ func TestWithSyncSummaryIntraRun(t *testing.T) {
 	require.False(t, opts.SyncSummaryIntraRun)
 }
 
+func TestWithInstructionLogic(t *testing.T) {
+	opts := &Options{}
+	WithInstructionLogic(true)(opts)
+	require.True(t, opts.InstructionLogic)
+
+	procs := buildRequestProcessors("agent", opts)
+	var found bool
+	for _, p := range procs {
+		if ip, ok := p.(*processor.InstructionRequestProcessor); ok {
+			require.True(t, ip.LogicTemplates)
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
+func TestWithInstructionTemplate(t *testing.T) {
+	tmpl, err := prompt.Text{
+		Template: "{{> rules}}\n{{#each user:orders as o}}\n- {o.id}\n{{/each}}",
+	}.Compile(context.Background(), prompt.WithPartial("rules", prompt.Text{
+		Template: "{{#if user:tier == \"gold\"}}Offer priority support.{{/if}}\n",
+	}))
+	require.NoError(t, err)
+	opts := &Options{}
+	WithInstructionTemplate(tmpl)(opts)
+	WithInstructionStateKeys("user:tier", "user:orders")(opts)
+	require.Equal(t, tmpl.Text().Template, opts.Instruction)
+
+	var ip *processor.InstructionRequestProcessor
+	for _, p := range buildRequestProcessors("agent", opts) {
+		if p, ok := p.(*processor.InstructionRequestProcessor); ok {
+			ip = p
+		}
+	}
+	require.NotNil(t, ip)
+	inv := &agent.Invocation{Session: &session.Session{State: session.StateMap{
+		"user:tier":   []byte(`"gold"`),
+		"user:orders": []byte(`[{"id":"A1"},{"id":"B2"}]`),
+	}}}
+	req := &model.Request{}
+	ip.ProcessRequest(context.Background(), inv, req, make(chan *event.Event, 1))
+	require.Len(t, req.Messages, 1)
+	require.Equal(t, "Offer priority support.\n- A1\n- B2\n", req.Messages[0].Content)
+
+	// Unknown state and templates that do not parse fail when the agent is
+	// built.
+	require.PanicsWithValue(t,
+		"Invalid LLMAgent configuration: instruction: prompt: undefined variables: {user:orders}",
+		func() { New("agent", WithInstructionTemplate(tmpl), WithInstructionStateKeys("user:tier")) })
+	require.Panics(t, func() {
+		New("agent", WithInstruction("{{#if user:tier}}"), WithInstructionLogic(true))
+	})
+	require.NotPanics(t, func() { New("agent", WithInstruction("{{#if user:tier}}")) })
+	require.NotPanics(t, func() {
+		New("agent", WithInstruction("{{#if user:tier}}{user:name?}{{/if}}"),
+			WithInstructionLogic(true), WithInstructionStateKeys("user:tier"))
+	})
+}
+
 func TestWithSessionSummaryInjectionMode(t *testing.T) {
 	opts := &Options{}
 	// Default should be zero value (empty string, treated as system).
@@ -511,6 +571,24 @@ func TestBuildRequestProcessors_DefaultGenerationConfigUsesZeroValue(
 	require.False(t, basicProc.GenerationConfig.Stream)
 }
 
+func TestWithPromptCache_SetsBasicProcessor(t *testing.T) {
+	opts := &Options{}
+	WithPromptCache(model.PromptCache{System: true, Tools: true})(opts)
+	procs := buildRequestProcessors("test-agent", opts)
+	var basicProc *processor.BasicRequestProcessor
+	for _, proc := range procs {
+		if candidate, ok := proc.(*processor.BasicRequestProcessor); ok {
+			basicProc = candidate
+			break
+		}
+	}
+	require.NotNil(t, basicProc)
+	require.NotNil(t, basicProc.PromptCache)
+	require.True(t, basicProc.PromptCache.System)
+	require.True(t, basicProc.PromptCache.Tools)
+	require.False(t, basicProc.PromptCache.History)
+}
+
 func TestWithMaxLimits_OnOptions(t *testing.T) {
 	opts := &Options{}
 
@@ -0,0 +1,114 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package llmagent
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/trace"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
+	semconvtrace "trpc.group/trpc-go/trpc-agent-go/telemetry/semconv/trace"
+)
+
+func (a *LLMAgent) instructionSourceStateKey() string {
+	return "agent:" + a.name + ":instruction_source"
+}
+
+func (a *LLMAgent) globalInstructionSourceStateKey() string {
+	return "agent:" + a.name + ":global_instruction_source"
+}
+
+// fetchPromptSources resolves the configured prompt sources once per run
+// and keeps the results in invocation state, so every model call of the
+// run sees the same prompt versions.
+func (a *LLMAgent) fetchPromptSources(ctx context.Context, inv *agent.Invocation) {
+	if inv == nil {
+		return
+	}
+	fetch := func(source prompt.Source, key, kind string) {
+		if source == nil {
+			return
+		}
+		text, err := source.FetchPrompt(ctx)
+		if err != nil {
+			log.Warnf("llmagent %s: fetch %s failed, using the static one: %v",
+				a.name, kind, err)
+			return
+		}
+		inv.SetState(key, text)
+	}
+	fetch(a.option.InstructionSource, a.instructionSourceStateKey(), "instruction")
+	fetch(a.option.GlobalInstructionSource,
+		a.globalInstructionSourceStateKey(), "global instruction")
+}
+
+func (a *LLMAgent) sourcedInstruction(inv *agent.Invocation) (prompt.Text, bool) {
+	if inv == nil || a.option.InstructionSource == nil {
+		return prompt.Text{}, false
+	}
+	return agent.GetStateValue[prompt.Text](inv, a.instructionSourceStateKey())
+}
+
+func (a *LLMAgent) sourcedGlobalInstruction(inv *agent.Invocation) (prompt.Text, bool) {
+	if inv == nil || a.option.GlobalInstructionSource == nil {
+		return prompt.Text{}, false
+	}
+	return agent.GetStateValue[prompt.Text](inv, a.globalInstructionSourceStateKey())
+}
+
+// promptMetasForInvocation returns the metadata of the versioned prompts
+// effectively used by inv.
+func (a *LLMAgent) promptMetasForInvocation(inv *agent.Invocation) []prompt.Meta {
+	var metas []prompt.Meta
+	for _, text := range []prompt.Text{
+		a.systemPromptTextForInvocation(inv),
+		a.instructionPromptForInvocation(inv),
+	} {
+		if text.Meta.Name != "" {
+			metas = append(metas, text.Meta)
+		}
+	}
+	return metas
+}
+
+func tracePromptMetas(span sdktrace.Span, metas []prompt.Meta) {
+	if len(metas) == 0 {
+		return
+	}
+	names := make([]string, len(metas))
+	versions := make([]string, len(metas))
+	for i, m := range metas {
+		names[i] = m.Name
+		versions[i] = m.Version
+	}
+	span.SetAttributes(
+		attribute.StringSlice(semconvtrace.KeyPromptNames, names),
+		attribute.StringSlice(semconvtrace.KeyPromptVersions, versions),
+	)
+}
+
+// stampPromptMetas records metas on the model responses authored by the
+// agent, so evaluation results can be attributed to prompt versions.
+func (a *LLMAgent) stampPromptMetas(evt *event.Event, metas []prompt.Meta) {
+	if len(metas) == 0 || evt == nil || evt.Response == nil ||
+		evt.Response.IsPartial || evt.Author != a.name {
+		return
+	}
+	if _, ok := evt.Extensions[prompt.EventExtensionKey]; ok {
+		return
+	}
+	if err := event.SetExtension(evt, prompt.EventExtensionKey, metas); err != nil {
+		log.Warnf("llmagent %s: record prompt versions: %v", a.name, err)
+	}
+}
@@ -0,0 +1,123 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
+	semconvtrace "trpc.group/trpc-go/trpc-agent-go/telemetry/semconv/trace"
+)
+
+type stubPromptSource struct {
+	text  prompt.Text
+	err   error
+	calls int
+}
+
+func (s *stubPromptSource) FetchPrompt(context.Context) (prompt.Text, error) {
+	s.calls++
+	return s.text, s.err
+}
+
+// instructionCapturingFlow records the instructions seen by the flow and
+// emits a partial and a final response.
+type instructionCapturingFlow struct {
+	agent       *LLMAgent
+	instruction string
+	system      string
+}
+
+func (f *instructionCapturingFlow) Run(
+	_ context.Context,
+	inv *agent.Invocation,
+) (<-chan *event.Event, error) {
+	f.instruction = f.agent.instructionForInvocation(inv)
+	f.system = f.agent.systemPromptForInvocation(inv)
+	ch := make(chan *event.Event, 3)
+	ch <- event.NewResponseEvent(inv.InvocationID, inv.AgentName,
+		&model.Response{IsPartial: true})
+	ch <- event.NewResponseEvent(inv.InvocationID, inv.AgentName,
+		&model.Response{Done: true})
+	ch <- event.NewResponseEvent(inv.InvocationID, "other",
+		&model.Response{Done: true})
+	close(ch)
+	return ch, nil
+}
+
+func TestLLMAgent_InstructionSource(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	instruction := &stubPromptSource{text: prompt.Text{
+		Template: "sourced instruction",
+		Meta:     prompt.Meta{Name: "support/triage", Version: "2"},
+	}}
+	global := &stubPromptSource{err: errors.New("unavailable")}
+	a := New("agent",
+		WithInstruction("static instruction"),
+		WithGlobalInstruction("static global"),
+		WithInstructionSource(instruction),
+		WithGlobalInstructionSource(global),
+	)
+	flow := &instructionCapturingFlow{agent: a}
+	a.flow = flow
+
+	evts, err := a.Run(context.Background(),
+		&agent.Invocation{InvocationID: "id", AgentName: "agent"})
+	require.NoError(t, err)
+	var got []*event.Event
+	for e := range evts {
+		got = append(got, e)
+	}
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "sourced instruction", flow.instruction)
+	assert.Equal(t, "static global", flow.system, "fetch errors fall back")
+	assert.Equal(t, 1, instruction.calls)
+
+	want := []prompt.Meta{{Name: "support/triage", Version: "2"}}
+	metas, ok, err := event.GetExtension[[]prompt.Meta](got[1], prompt.EventExtensionKey)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, want, metas)
+	_, ok, _ = event.GetExtension[[]prompt.Meta](got[0], prompt.EventExtensionKey)
+	assert.False(t, ok, "partial events are not stamped")
+	_, ok, _ = event.GetExtension[[]prompt.Meta](got[2], prompt.EventExtensionKey)
+	assert.False(t, ok, "events of other authors are not stamped")
+
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+	var found bool
+	for _, s := range spans {
+		for _, kv := range s.Attributes() {
+			if string(kv.Key) == semconvtrace.KeyPromptVersions {
+				assert.Equal(t, []string{"2"}, kv.Value.AsStringSlice())
+				found = true
+			}
+		}
+	}
+	assert.True(t, found)
+
+	// Run options still override sourced instructions.
+	inv := &agent.Invocation{InvocationID: "id2", AgentName: "agent"}
+	inv.RunOptions.Instruction = "run instruction"
+	evts, err = a.Run(context.Background(), inv)
+	require.NoError(t, err)
+	for range evts {
+	}
+	assert.Equal(t, "run instruction", flow.instruction)
+	assert.Empty(t, a.promptMetasForInvocation(inv))
+}
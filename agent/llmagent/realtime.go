@@ -0,0 +1,75 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// errRealtimeModelNotSet is returned by RealtimeRequest for agents created
+// without WithRealtimeModel.
+var errRealtimeModelNotSet = errors.New("llmagent: realtime model is not set")
+
+// RealtimeRequest implements the agent.RealtimeAgent interface. The
+// instructions and conversation history are built by the request
+// processors of normal runs, so a session picks up the conversation where
+// the last run or session left it.
+func (a *LLMAgent) RealtimeRequest(
+	ctx context.Context,
+	invocation *agent.Invocation,
+) (model.RealtimeModel, *model.RealtimeRequest, error) {
+	a.mu.RLock()
+	options := a.option
+	a.mu.RUnlock()
+	if options.RealtimeModel == nil {
+		return nil, nil, errRealtimeModelNotSet
+	}
+
+	req := &model.Request{Tools: make(map[string]tool.Tool)}
+	for _, t := range a.FilterTools(ctx) {
+		req.Tools[t.Declaration().Name] = t
+	}
+	for _, p := range buildRequestProcessorsWithAgent(a, &options) {
+		p.ProcessRequest(ctx, invocation, req, nil)
+	}
+
+	rtReq := options.RealtimeConfig
+	var instructions []string
+	rtReq.Messages = nil
+	for _, msg := range req.Messages {
+		if msg.Role == model.RoleSystem {
+			instructions = append(instructions, msg.Content)
+			continue
+		}
+		rtReq.Messages = append(rtReq.Messages, msg)
+	}
+	rtReq.Instructions = strings.Join(instructions, "\n\n")
+	rtReq.Tools = req.Tools
+	return options.RealtimeModel, &rtReq, nil
+}
+
+// RunRealtimeTools implements the agent.RealtimeAgent interface with the
+// tool call processor of the agent's flow.
+func (a *LLMAgent) RunRealtimeTools(
+	ctx context.Context,
+	invocation *agent.Invocation,
+	tools map[string]tool.Tool,
+	rsp *model.Response,
+	ch chan<- *event.Event,
+) {
+	a.toolCallProcessor.ProcessResponse(ctx, invocation, &model.Request{Tools: tools}, rsp, ch)
+}
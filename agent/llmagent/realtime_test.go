@@ -0,0 +1,63 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package llmagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+type stubRealtimeModel struct{}
+
+func (stubRealtimeModel) Connect(context.Context, *model.RealtimeRequest) (model.RealtimeSession, error) {
+	return nil, nil
+}
+
+func (stubRealtimeModel) Info() model.Info { return model.Info{Name: "stub-realtime"} }
+
+func TestRealtimeRequest(t *testing.T) {
+	var _ agent.RealtimeAgent = (*LLMAgent)(nil)
+	rm := stubRealtimeModel{}
+	config := model.RealtimeRequest{Voice: "marin", Messages: []model.Message{model.NewUserMessage("dropped")}}
+	a := New(
+		"helper",
+		WithDescription("A helpful assistant."),
+		WithInstruction("Be brief."),
+		WithTools([]tool.Tool{echoTool("echo")}),
+		WithRealtimeModel(rm),
+		WithRealtimeConfig(config),
+		WithAddCurrentTime(true),
+	)
+
+	got, req, err := a.RealtimeRequest(context.Background(), agent.NewInvocation(
+		agent.WithInvocationAgent(a),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, rm, got)
+	assert.Contains(t, req.Instructions, "Be brief.")
+	assert.Contains(t, req.Instructions, "A helpful assistant.")
+	assert.Contains(t, req.Instructions, "The current date is")
+	assert.Empty(t, req.Messages)
+	assert.Equal(t, "marin", req.Voice)
+	assert.Contains(t, req.Tools, "echo")
+	assert.Len(t, config.Messages, 1, "the configured request must not be modified")
+}
+
+func TestRealtimeRequest_ModelNotSet(t *testing.T) {
+	_, _, err := New("helper").RealtimeRequest(context.Background(), agent.NewInvocation())
+	assert.ErrorIs(t, err, errRealtimeModelNotSet)
+}
@@ -0,0 +1,153 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Comparison puts the reports of two configurations side by side.
+type Comparison struct {
+	// Baseline is the report compared against.
+	Baseline *Report `json:"baseline,omitempty"`
+	// Candidate is the report being evaluated.
+	Candidate *Report `json:"candidate,omitempty"`
+	// Delta is Candidate.Mean minus Baseline.Mean.
+	Delta Metrics `json:"delta"`
+	// Queries holds the per-query deltas in dataset order.
+	Queries []*QueryComparison `json:"queries,omitempty"`
+	// Improved lists the queries whose nDCG at the largest cutoff rose.
+	Improved []string `json:"improved,omitempty"`
+	// Regressed lists the queries whose nDCG at the largest cutoff fell.
+	Regressed []string `json:"regressed,omitempty"`
+}
+
+// QueryComparison is the per-query part of a Comparison.
+type QueryComparison struct {
+	// QueryID is the ID of the query.
+	QueryID string `json:"queryId,omitempty"`
+	// Baseline holds the baseline scores.
+	Baseline Metrics `json:"baseline"`
+	// Candidate holds the candidate scores.
+	Candidate Metrics `json:"candidate"`
+	// Delta is Candidate minus Baseline.
+	Delta Metrics `json:"delta"`
+}
+
+// Compare compares two reports produced from the same dataset with the same
+// cutoffs. Reports without cutoffs cannot be compared.
+func Compare(baseline, candidate *Report) (*Comparison, error) {
+	if baseline == nil || candidate == nil {
+		return nil, errors.New("reports must not be nil")
+	}
+	if len(baseline.Ks) == 0 || len(candidate.Ks) == 0 {
+		return nil, errors.New("reports must have cutoffs")
+	}
+	if !equalInts(baseline.Ks, candidate.Ks) {
+		return nil, fmt.Errorf("cutoffs differ: %v vs %v", baseline.Ks, candidate.Ks)
+	}
+	if len(baseline.Queries) != len(candidate.Queries) {
+		return nil, fmt.Errorf("query counts differ: %d vs %d", len(baseline.Queries), len(candidate.Queries))
+	}
+	ks := baseline.Ks
+	top := ks[len(ks)-1]
+	c := &Comparison{
+		Baseline:  baseline,
+		Candidate: candidate,
+		Delta:     subtract(baseline.Mean, candidate.Mean, ks),
+	}
+	for i, b := range baseline.Queries {
+		a := candidate.Queries[i]
+		if b.QueryID != a.QueryID {
+			return nil, fmt.Errorf("query %d differs: %s vs %s", i, b.QueryID, a.QueryID)
+		}
+		qc := &QueryComparison{
+			QueryID:   b.QueryID,
+			Baseline:  b.Metrics,
+			Candidate: a.Metrics,
+			Delta:     subtract(b.Metrics, a.Metrics, ks),
+		}
+		c.Queries = append(c.Queries, qc)
+		switch d := qc.Delta.NDCG[top]; {
+		case d > 0:
+			c.Improved = append(c.Improved, qc.QueryID)
+		case d < 0:
+			c.Regressed = append(c.Regressed, qc.QueryID)
+		}
+	}
+	return c, nil
+}
+
+// WriteText writes the report as an aligned text table with one row for the
+// mean followed by one row per query.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n", strings.Join(header("query", r.Ks), "\t"))
+	fmt.Fprintf(tw, "%s\n", strings.Join(row("MEAN", r.Mean, r.Ks, "%.4f"), "\t"))
+	for _, q := range r.Queries {
+		cells := row(q.QueryID, q.Metrics, r.Ks, "%.4f")
+		if q.Error != "" {
+			cells = append(cells, "error: "+q.Error)
+		}
+		fmt.Fprintf(tw, "%s\n", strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteText writes the baseline, candidate and delta means followed by the
+// per-query deltas as an aligned text table.
+func (c *Comparison) WriteText(w io.Writer) error {
+	ks := c.Baseline.Ks
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n", strings.Join(header("", ks), "\t"))
+	fmt.Fprintf(tw, "%s\n", strings.Join(row(c.Baseline.Name, c.Baseline.Mean, ks, "%.4f"), "\t"))
+	fmt.Fprintf(tw, "%s\n", strings.Join(row(c.Candidate.Name, c.Candidate.Mean, ks, "%.4f"), "\t"))
+	fmt.Fprintf(tw, "%s\n", strings.Join(row("delta", c.Delta, ks, "%+.4f"), "\t"))
+	for _, q := range c.Queries {
+		fmt.Fprintf(tw, "%s\n", strings.Join(row(q.QueryID, q.Delta, ks, "%+.4f"), "\t"))
+	}
+	return tw.Flush()
+}
+
+func header(first string, ks []int) []string {
+	cells := []string{first, "mrr"}
+	for _, name := range []string{"recall", "precision", "ndcg"} {
+		for _, k := range ks {
+			cells = append(cells, fmt.Sprintf("%s@%d", name, k))
+		}
+	}
+	return cells
+}
+
+func row(first string, m Metrics, ks []int, format string) []string {
+	cells := []string{first, fmt.Sprintf(format, m.MRR)}
+	for _, values := range []map[int]float64{m.Recall, m.Precision, m.NDCG} {
+		for _, k := range ks {
+			cells = append(cells, fmt.Sprintf(format, values[k]))
+		}
+	}
+	return cells
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/source"
+)
+
+// Dataset is a labelled set of retrieval queries.
+type Dataset struct {
+	// ID identifies the dataset in reports.
+	ID string `json:"id,omitempty"`
+	// Queries contains the labelled queries.
+	Queries []*Query `json:"queries,omitempty"`
+}
+
+// Query is a single labelled retrieval query.
+type Query struct {
+	// ID uniquely identifies the query within the dataset.
+	ID string `json:"id,omitempty"`
+	// Text is the query text sent to the retrieval target.
+	Text string `json:"text,omitempty"`
+	// Relevant lists the items a good retrieval returns for this query.
+	Relevant []*Judgement `json:"relevant,omitempty"`
+}
+
+// Judgement marks one relevant item of a query.
+//
+// An item is identified either by DocumentID, which must equal the ID of a
+// retrieved chunk, or by a source span: Source matches the chunk URI or
+// source name metadata, and Text, when set, is a passage the chunk must
+// overlap. A chunk overlaps a passage when either contains the other, which
+// keeps span labels valid when the chunker changes.
+type Judgement struct {
+	// DocumentID is the ID of a relevant chunk.
+	DocumentID string `json:"documentId,omitempty"`
+	// Source is the URI or source name of a relevant source.
+	Source string `json:"source,omitempty"`
+	// Text is a relevant passage within Source.
+	Text string `json:"text,omitempty"`
+	// Grade is the graded relevance used by nDCG. Zero means 1.
+	Grade float64 `json:"grade,omitempty"`
+}
+
+// LoadDataset reads a JSON encoded Dataset from path.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dataset %s: %w", path, err)
+	}
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("unmarshal dataset %s: %w", path, err)
+	}
+	if err := ds.Validate(); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// Validate reports the first structural problem of the dataset.
+func (d *Dataset) Validate() error {
+	if d == nil || len(d.Queries) == 0 {
+		return errors.New("dataset has no queries")
+	}
+	seen := make(map[string]struct{}, len(d.Queries))
+	for i, q := range d.Queries {
+		if q == nil {
+			return fmt.Errorf("query %d is nil", i)
+		}
+		if q.ID == "" {
+			return fmt.Errorf("query %d has no id", i)
+		}
+		if _, ok := seen[q.ID]; ok {
+			return fmt.Errorf("duplicate query id %s", q.ID)
+		}
+		seen[q.ID] = struct{}{}
+		if strings.TrimSpace(q.Text) == "" {
+			return fmt.Errorf("query %s has no text", q.ID)
+		}
+		if len(q.Relevant) == 0 {
+			return fmt.Errorf("query %s has no relevant items", q.ID)
+		}
+		for j, r := range q.Relevant {
+			if r == nil || (r.DocumentID == "" && r.Source == "") {
+				return fmt.Errorf("query %s relevant item %d needs a documentId or source", q.ID, j)
+			}
+			if r.Grade < 0 {
+				return fmt.Errorf("query %s relevant item %d has a negative grade", q.ID, j)
+			}
+		}
+	}
+	return nil
+}
+
+func (j *Judgement) grade() float64 {
+	if j.Grade == 0 {
+		return 1
+	}
+	return j.Grade
+}
+
+// matches reports whether a retrieved chunk satisfies the judgement.
+func (j *Judgement) matches(doc *document.Document) bool {
+	if doc == nil {
+		return false
+	}
+	if j.DocumentID != "" {
+		return doc.ID == j.DocumentID
+	}
+	if !matchesSource(doc, j.Source) {
+		return false
+	}
+	if j.Text == "" {
+		return true
+	}
+	want := normalizeSpace(j.Text)
+	got := normalizeSpace(doc.Content)
+	if want == "" || got == "" {
+		return false
+	}
+	return strings.Contains(got, want) || strings.Contains(want, got)
+}
+
+func matchesSource(doc *document.Document, src string) bool {
+	for _, key := range []string{source.MetaURI, source.MetaSourceName, source.MetaFilePath, source.MetaURL} {
+		if v, ok := doc.Metadata[key].(string); ok && v == src {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
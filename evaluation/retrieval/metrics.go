@@ -0,0 +1,129 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"math"
+	"sort"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+)
+
+// Metrics holds retrieval scores. Cutoff-dependent metrics are keyed by k.
+type Metrics struct {
+	// Recall is the fraction of relevant items found in the top k.
+	Recall map[int]float64 `json:"recall,omitempty"`
+	// Precision is the fraction of the top k that is relevant.
+	Precision map[int]float64 `json:"precision,omitempty"`
+	// NDCG is the normalized discounted cumulative gain of the top k.
+	NDCG map[int]float64 `json:"ndcg,omitempty"`
+	// MRR is the reciprocal rank of the first relevant result.
+	MRR float64 `json:"mrr"`
+}
+
+func newMetrics() Metrics {
+	return Metrics{
+		Recall:    make(map[int]float64),
+		Precision: make(map[int]float64),
+		NDCG:      make(map[int]float64),
+	}
+}
+
+// score computes the metrics of one query.
+//
+// Each judgement is credited at most once, to the highest ranked document
+// that matches it, so several chunks of one relevant source do not inflate
+// recall or nDCG. Such extra chunks still count as relevant for precision and
+// MRR because they do carry relevant content.
+func score(q *Query, docs []*document.Document, ks []int) (Metrics, int) {
+	m := newMetrics()
+	gains := make([]float64, len(docs))
+	relevant := make([]bool, len(docs))
+	covered := make([]int, len(docs))
+	used := make([]bool, len(q.Relevant))
+	firstRank := 0
+	for i, doc := range docs {
+		for j, r := range q.Relevant {
+			if !r.matches(doc) {
+				continue
+			}
+			relevant[i] = true
+			if !used[j] {
+				used[j] = true
+				gains[i] = r.grade()
+				covered[i] = 1
+				break
+			}
+		}
+		if relevant[i] && firstRank == 0 {
+			firstRank = i + 1
+		}
+	}
+	if firstRank > 0 {
+		m.MRR = 1 / float64(firstRank)
+	}
+
+	ideal := make([]float64, len(q.Relevant))
+	for j, r := range q.Relevant {
+		ideal[j] = r.grade()
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ideal)))
+
+	for _, k := range ks {
+		var found, hits int
+		var dcg, idcg float64
+		for i := 0; i < k && i < len(docs); i++ {
+			found += covered[i]
+			if relevant[i] {
+				hits++
+			}
+			dcg += gains[i] / math.Log2(float64(i+2))
+		}
+		for i := 0; i < k && i < len(ideal); i++ {
+			idcg += ideal[i] / math.Log2(float64(i+2))
+		}
+		m.Recall[k] = float64(found) / float64(len(q.Relevant))
+		m.Precision[k] = float64(hits) / float64(k)
+		if idcg > 0 {
+			m.NDCG[k] = dcg / idcg
+		}
+	}
+	return m, firstRank
+}
+
+// mean averages metrics over queries.
+func mean(all []Metrics, ks []int) Metrics {
+	m := newMetrics()
+	if len(all) == 0 {
+		return m
+	}
+	n := float64(len(all))
+	for _, q := range all {
+		m.MRR += q.MRR / n
+		for _, k := range ks {
+			m.Recall[k] += q.Recall[k] / n
+			m.Precision[k] += q.Precision[k] / n
+			m.NDCG[k] += q.NDCG[k] / n
+		}
+	}
+	return m
+}
+
+// subtract returns b - a.
+func subtract(a, b Metrics, ks []int) Metrics {
+	m := newMetrics()
+	m.MRR = b.MRR - a.MRR
+	for _, k := range ks {
+		m.Recall[k] = b.Recall[k] - a.Recall[k]
+		m.Precision[k] = b.Precision[k] - a.Precision[k]
+		m.NDCG[k] = b.NDCG[k] - a.NDCG[k]
+	}
+	return m
+}
@@ -0,0 +1,78 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/source"
+)
+
+func docs(ids ...string) []*document.Document {
+	out := make([]*document.Document, len(ids))
+	for i, id := range ids {
+		out[i] = &document.Document{ID: id}
+	}
+	return out
+}
+
+func TestScore_BinaryRelevance(t *testing.T) {
+	q := &Query{ID: "q", Relevant: []*Judgement{{DocumentID: "a"}, {DocumentID: "b"}}}
+	m, first := score(q, docs("x", "a", "y", "b"), []int{1, 2, 4})
+
+	assert.Equal(t, 2, first)
+	assert.Equal(t, 0.5, m.MRR)
+	assert.Equal(t, 0.0, m.Recall[1])
+	assert.Equal(t, 0.5, m.Recall[2])
+	assert.Equal(t, 1.0, m.Recall[4])
+	assert.Equal(t, 0.0, m.Precision[1])
+	assert.Equal(t, 0.5, m.Precision[2])
+	assert.Equal(t, 0.5, m.Precision[4])
+
+	dcg := 1/math.Log2(3) + 1/math.Log2(5)
+	idcg := 1 + 1/math.Log2(3)
+	assert.InDelta(t, dcg/idcg, m.NDCG[4], 1e-12)
+	assert.Equal(t, 0.0, m.NDCG[1])
+}
+
+func TestScore_GradedAndNoHits(t *testing.T) {
+	q := &Query{Relevant: []*Judgement{{DocumentID: "a", Grade: 3}, {DocumentID: "b"}}}
+	m, _ := score(q, docs("b", "a"), []int{2})
+	dcg := 1 + 3/math.Log2(3)
+	idcg := 3 + 1/math.Log2(3)
+	assert.InDelta(t, dcg/idcg, m.NDCG[2], 1e-12)
+
+	m, first := score(q, nil, []int{2})
+	assert.Zero(t, first)
+	assert.Zero(t, m.MRR)
+	assert.Zero(t, m.Recall[2])
+	assert.Zero(t, m.NDCG[2])
+}
+
+func TestScore_SpanCreditedOnce(t *testing.T) {
+	q := &Query{Relevant: []*Judgement{{Source: "guide.md", Text: "install   the cli"}}}
+	chunk := func(id, content string) *document.Document {
+		return &document.Document{ID: id, Content: content, Metadata: map[string]any{source.MetaURI: "guide.md"}}
+	}
+	retrieved := []*document.Document{
+		chunk("1", "First install the\ncli, then run it."),
+		chunk("2", "the cli"),
+		{ID: "3", Content: "install the cli", Metadata: map[string]any{source.MetaURI: "other.md"}},
+	}
+	m, first := score(q, retrieved, []int{3})
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 1.0, m.Recall[3])
+	assert.InDelta(t, 2.0/3.0, m.Precision[3], 1e-12)
+	assert.Equal(t, 1.0, m.NDCG[3])
+}
@@ -0,0 +1,70 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import "sort"
+
+var defaultKs = []int{1, 3, 5, 10}
+
+// defaultConcurrency is the default number of queries evaluated at once.
+const defaultConcurrency = 4
+
+// options holds the configuration of an evaluation run.
+type options struct {
+	ks          []int
+	concurrency int
+}
+
+func newOptions(opt ...Option) *options {
+	opts := &options{
+		ks:          defaultKs,
+		concurrency: defaultConcurrency,
+	}
+	for _, o := range opt {
+		o(opts)
+	}
+	return opts
+}
+
+// Option configures an evaluation run.
+type Option func(*options)
+
+// WithKs sets the cutoffs of recall@k, precision@k and nDCG@k. Non-positive
+// and duplicate values are ignored. The default is 1, 3, 5 and 10. The target
+// is asked for as many documents as the largest cutoff.
+func WithKs(ks ...int) Option {
+	return func(o *options) {
+		seen := make(map[int]struct{}, len(ks))
+		var out []int
+		for _, k := range ks {
+			if _, ok := seen[k]; ok || k <= 0 {
+				continue
+			}
+			seen[k] = struct{}{}
+			out = append(out, k)
+		}
+		if len(out) == 0 {
+			return
+		}
+		sort.Ints(out)
+		o.ks = out
+	}
+}
+
+// WithConcurrency sets how many queries are retrieved at once.
+// Values below 1 are treated as 1.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.concurrency = n
+	}
+}
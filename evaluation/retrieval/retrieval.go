@@ -0,0 +1,151 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package retrieval evaluates the retrieval layer on its own.
+//
+// A labelled Dataset of queries and relevant documents or source spans is run
+// through a Target built from a knowledge.Knowledge or retriever.Retriever,
+// and the Report carries recall@k, precision@k, MRR and nDCG@k per query and
+// averaged over the dataset. Compare puts two reports side by side, which is
+// how chunker, embedder, reranker or query enhancer changes are measured.
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/source"
+)
+
+// Report is the outcome of evaluating one target on one dataset.
+type Report struct {
+	// Name identifies the evaluated configuration.
+	Name string `json:"name,omitempty"`
+	// DatasetID is the ID of the evaluated dataset.
+	DatasetID string `json:"datasetId,omitempty"`
+	// Ks lists the cutoffs in ascending order.
+	Ks []int `json:"ks,omitempty"`
+	// Mean averages the query metrics. Failed queries score zero.
+	Mean Metrics `json:"mean"`
+	// Failed counts the queries whose retrieval returned an error.
+	Failed int `json:"failed,omitempty"`
+	// Queries holds the per-query breakdown in dataset order.
+	Queries []*QueryResult `json:"queries,omitempty"`
+}
+
+// QueryResult is the breakdown of one query.
+type QueryResult struct {
+	// QueryID is the ID of the query.
+	QueryID string `json:"queryId,omitempty"`
+	// Retrieved lists the retrieved documents, best first.
+	Retrieved []*RetrievedDocument `json:"retrieved,omitempty"`
+	// FirstRelevantRank is the 1-based rank of the first relevant result,
+	// or zero when none was retrieved.
+	FirstRelevantRank int `json:"firstRelevantRank,omitempty"`
+	// Metrics holds the query scores.
+	Metrics Metrics `json:"metrics"`
+	// Latency is the retrieval time of the query.
+	Latency time.Duration `json:"latency,omitempty"`
+	// Error is the retrieval error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// RetrievedDocument identifies a retrieved chunk in a report.
+type RetrievedDocument struct {
+	// ID is the chunk ID.
+	ID string `json:"id,omitempty"`
+	// Source is the chunk URI or source name, if known.
+	Source string `json:"source,omitempty"`
+}
+
+// Evaluate runs every query of ds through target and scores the results.
+//
+// A failing query does not abort the run; it is recorded with its error and
+// scores zero. Only an invalid dataset, a nil target or a cancelled context
+// make Evaluate return an error.
+func Evaluate(ctx context.Context, name string, ds *Dataset, target Target, opt ...Option) (*Report, error) {
+	if target == nil {
+		return nil, errors.New("target is nil")
+	}
+	if err := ds.Validate(); err != nil {
+		return nil, err
+	}
+	opts := newOptions(opt...)
+	limit := opts.ks[len(opts.ks)-1]
+
+	results := make([]*QueryResult, len(ds.Queries))
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+	for i, q := range ds.Queries {
+		wg.Add(1)
+		go func(i int, q *Query) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = evaluateQuery(ctx, q, target, limit, opts.ks)
+		}(i, q)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Name:      name,
+		DatasetID: ds.ID,
+		Ks:        opts.ks,
+		Queries:   results,
+	}
+	all := make([]Metrics, len(results))
+	for i, r := range results {
+		all[i] = r.Metrics
+		if r.Error != "" {
+			report.Failed++
+		}
+	}
+	report.Mean = mean(all, opts.ks)
+	return report, nil
+}
+
+func evaluateQuery(ctx context.Context, q *Query, target Target, limit int, ks []int) *QueryResult {
+	result := &QueryResult{QueryID: q.ID}
+	start := time.Now()
+	docs, err := target.Retrieve(ctx, q.Text, limit)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		result.Metrics, _ = score(q, nil, ks)
+		return result
+	}
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	for _, d := range docs {
+		result.Retrieved = append(result.Retrieved, describe(d))
+	}
+	result.Metrics, result.FirstRelevantRank = score(q, docs, ks)
+	return result
+}
+
+func describe(doc *document.Document) *RetrievedDocument {
+	if doc == nil {
+		return &RetrievedDocument{}
+	}
+	rd := &RetrievedDocument{ID: doc.ID}
+	for _, key := range []string{source.MetaURI, source.MetaSourceName} {
+		if v, ok := doc.Metadata[key].(string); ok && v != "" {
+			rd.Source = v
+			break
+		}
+	}
+	return rd
+}
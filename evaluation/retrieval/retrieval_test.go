@@ -0,0 +1,183 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/retriever"
+)
+
+type fakeKnowledge struct {
+	results map[string][]string
+	mu      sync.Mutex
+	lastReq *knowledge.SearchRequest
+}
+
+func (f *fakeKnowledge) Search(_ context.Context, req *knowledge.SearchRequest) (*knowledge.SearchResult, error) {
+	f.mu.Lock()
+	f.lastReq = req
+	f.mu.Unlock()
+	if req.Query == "fail" {
+		return nil, errors.New("search failed")
+	}
+	res := &knowledge.SearchResult{}
+	for _, id := range f.results[req.Query] {
+		res.Documents = append(res.Documents, &knowledge.Result{Document: &document.Document{ID: id}})
+	}
+	return res, nil
+}
+
+type fakeRetriever struct {
+	results map[string][]string
+}
+
+func (f *fakeRetriever) Retrieve(_ context.Context, q *retriever.Query) (*retriever.Result, error) {
+	res := &retriever.Result{}
+	for _, id := range f.results[q.Text] {
+		res.Documents = append(res.Documents, &retriever.RelevantDocument{Document: &document.Document{ID: id}})
+	}
+	return res, nil
+}
+
+func (f *fakeRetriever) Close() error { return nil }
+
+func testDataset() *Dataset {
+	return &Dataset{
+		ID: "ds",
+		Queries: []*Query{
+			{ID: "q1", Text: "one", Relevant: []*Judgement{{DocumentID: "a"}}},
+			{ID: "q2", Text: "two", Relevant: []*Judgement{{DocumentID: "b"}}},
+		},
+	}
+}
+
+func TestEvaluate_Knowledge(t *testing.T) {
+	k := &fakeKnowledge{results: map[string][]string{
+		"one": {"a", "x", "y"},
+		"two": {"x", "b"},
+	}}
+	template := &knowledge.SearchRequest{MinScore: 0.3, MaxResults: 100}
+	report, err := Evaluate(context.Background(), "base", testDataset(), FromKnowledge(k, template), WithKs(2, 1, 2))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2}, report.Ks)
+	assert.Equal(t, "ds", report.DatasetID)
+	k.mu.Lock()
+	last := k.lastReq
+	k.mu.Unlock()
+	assert.Equal(t, 2, last.MaxResults)
+	assert.Equal(t, 0.3, last.MinScore)
+	assert.Equal(t, 0.75, report.Mean.MRR)
+	assert.Equal(t, 0.5, report.Mean.Recall[1])
+	assert.Equal(t, 1.0, report.Mean.Recall[2])
+	require.Len(t, report.Queries, 2)
+	assert.Len(t, report.Queries[0].Retrieved, 2)
+	assert.Equal(t, 2, report.Queries[1].FirstRelevantRank)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "recall@2")
+	assert.Contains(t, buf.String(), "MEAN")
+}
+
+func TestEvaluate_FailedQueryScoresZero(t *testing.T) {
+	ds := testDataset()
+	ds.Queries[1].Text = "fail"
+	k := &fakeKnowledge{results: map[string][]string{"one": {"a"}}}
+	report, err := Evaluate(context.Background(), "base", ds, FromKnowledge(k, nil), WithConcurrency(0))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "search failed", report.Queries[1].Error)
+	assert.Equal(t, 0.5, report.Mean.MRR)
+}
+
+func TestEvaluate_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	_, err := Evaluate(ctx, "x", testDataset(), nil)
+	assert.Error(t, err)
+	_, err = Evaluate(ctx, "x", &Dataset{}, FromRetriever(&fakeRetriever{}, nil))
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	ds := testDataset()
+	base, err := Evaluate(ctx, "base", ds, FromRetriever(&fakeRetriever{results: map[string][]string{
+		"one": {"a"}, "two": {"x", "b"},
+	}}, nil), WithKs(2))
+	require.NoError(t, err)
+	cand, err := Evaluate(ctx, "cand", ds, FromRetriever(&fakeRetriever{results: map[string][]string{
+		"one": {"x", "a"}, "two": {"b"},
+	}}, &retriever.Query{MinScore: 0.1}), WithKs(2))
+	require.NoError(t, err)
+
+	c, err := Compare(base, cand)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"q2"}, c.Improved)
+	assert.Equal(t, []string{"q1"}, c.Regressed)
+	assert.InDelta(t, 0, c.Delta.MRR, 1e-12)
+	require.Len(t, c.Queries, 2)
+	assert.Equal(t, -0.5, c.Queries[0].Delta.MRR)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.WriteText(&buf))
+	assert.Contains(t, buf.String(), "cand")
+
+	other, err := Evaluate(ctx, "other", ds, FromRetriever(&fakeRetriever{}, nil), WithKs(3))
+	require.NoError(t, err)
+	_, err = Compare(base, other)
+	assert.Error(t, err)
+	_, err = Compare(nil, base)
+	assert.Error(t, err)
+	_, err = Compare(&Report{Queries: base.Queries}, &Report{Queries: cand.Queries})
+	assert.EqualError(t, err, "reports must have cutoffs")
+}
+
+func TestLoadDataset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ds.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"id": "faq",
+		"queries": [
+			{"id": "q1", "text": "how to install", "relevant": [{"source": "guide.md", "text": "go get", "grade": 2}]}
+		]
+	}`), 0o644))
+	ds, err := LoadDataset(path)
+	require.NoError(t, err)
+	assert.Equal(t, "faq", ds.ID)
+	assert.Equal(t, 2.0, ds.Queries[0].Relevant[0].Grade)
+
+	bad := []*Dataset{
+		{Queries: []*Query{nil}},
+		{Queries: []*Query{{Text: "t", Relevant: []*Judgement{{DocumentID: "a"}}}}},
+		{Queries: []*Query{{ID: "a", Text: "t", Relevant: []*Judgement{{DocumentID: "a"}}}, {ID: "a", Text: "t", Relevant: []*Judgement{{DocumentID: "a"}}}}},
+		{Queries: []*Query{{ID: "a", Text: " ", Relevant: []*Judgement{{DocumentID: "a"}}}}},
+		{Queries: []*Query{{ID: "a", Text: "t"}}},
+		{Queries: []*Query{{ID: "a", Text: "t", Relevant: []*Judgement{{}}}}},
+		{Queries: []*Query{{ID: "a", Text: "t", Relevant: []*Judgement{{DocumentID: "a", Grade: -1}}}}},
+	}
+	for i, ds := range bad {
+		assert.Error(t, ds.Validate(), i)
+	}
+	_, err = LoadDataset(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package retrieval
+
+import (
+	"context"
+	"errors"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/document"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/retriever"
+)
+
+// Target is a retrieval configuration under evaluation.
+type Target interface {
+	// Retrieve returns at most limit documents for text, best first.
+	Retrieve(ctx context.Context, text string, limit int) ([]*document.Document, error)
+}
+
+// TargetFunc adapts a function to Target.
+type TargetFunc func(ctx context.Context, text string, limit int) ([]*document.Document, error)
+
+// Retrieve implements Target.
+func (f TargetFunc) Retrieve(ctx context.Context, text string, limit int) ([]*document.Document, error) {
+	return f(ctx, text, limit)
+}
+
+// FromKnowledge evaluates a knowledge.Knowledge. The template request, when
+// not nil, supplies the filter, search mode and score threshold; its Query
+// and MaxResults are replaced for every query.
+func FromKnowledge(k knowledge.Knowledge, template *knowledge.SearchRequest) Target {
+	return TargetFunc(func(ctx context.Context, text string, limit int) ([]*document.Document, error) {
+		if k == nil {
+			return nil, errors.New("knowledge is nil")
+		}
+		req := &knowledge.SearchRequest{}
+		if template != nil {
+			*req = *template
+		}
+		req.Query = text
+		req.MaxResults = limit
+		res, err := k.Search(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return nil, nil
+		}
+		docs := make([]*document.Document, 0, len(res.Documents))
+		for _, r := range res.Documents {
+			if r != nil && r.Document != nil {
+				docs = append(docs, r.Document)
+			}
+		}
+		if len(docs) == 0 && res.Document != nil {
+			docs = append(docs, res.Document)
+		}
+		return docs, nil
+	})
+}
+
+// FromRetriever evaluates a retriever.Retriever. The template query, when not
+// nil, supplies the filter, search mode and score threshold; its Text and
+// Limit are replaced for every query.
+func FromRetriever(r retriever.Retriever, template *retriever.Query) Target {
+	return TargetFunc(func(ctx context.Context, text string, limit int) ([]*document.Document, error) {
+		if r == nil {
+			return nil, errors.New("retriever is nil")
+		}
+		q := &retriever.Query{}
+		if template != nil {
+			*q = *template
+		}
+		q.Text = text
+		q.Limit = limit
+		res, err := r.Retrieve(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return nil, nil
+		}
+		docs := make([]*document.Document, 0, len(res.Documents))
+		for _, d := range res.Documents {
+			if d != nil && d.Document != nil {
+				docs = append(docs, d.Document)
+			}
+		}
+		return docs, nil
+	})
+}
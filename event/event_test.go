@@ -143,6 +143,22 @@ func TestEvent_SetAndGetExtension(t *testing.T) {
 	require.Equal(t, "bob", got["speaker"])
 }
 
+func TestEvent_Progress(t *testing.T) {
+	evt := New("inv-1", "planner", WithProgress("plan.progress", map[string]any{
+		"agent": "planner",
+		"plan":  json.RawMessage(`{"goal":"g"}`),
+	}))
+	p, ok := GetProgress(evt)
+	require.True(t, ok)
+	require.Equal(t, "plan.progress", p.Name)
+	require.JSONEq(t, `"planner"`, string(p.Data["agent"]))
+	require.JSONEq(t, `{"goal":"g"}`, string(p.Data["plan"]))
+
+	_, ok = GetProgress(New("inv-1", "author"))
+	require.False(t, ok)
+	require.Error(t, SetProgress(evt, "bad", map[string]any{"f": func() {}}))
+}
+
 func TestEvent_ExtensionHelpers_EdgeCases(t *testing.T) {
 	t.Parallel()
 
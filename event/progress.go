@@ -0,0 +1,57 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package event
+
+import "encoding/json"
+
+// ProgressExtensionKey is the event extension key under which agents
+// publish progress snapshots.
+const ProgressExtensionKey = "trpc.progress"
+
+// Progress is a snapshot of the progress of a long running agent, such as
+// the plan of a planner or the board of a team. Servers forward it to
+// frontends without knowing the agent, e.g. the AG-UI server emits it as a
+// custom event named Name whose value holds the fields of Data.
+type Progress struct {
+	// Name identifies the kind of progress, e.g. "plan.progress".
+	Name string `json:"name"`
+	// Data holds the serialized fields of the snapshot.
+	Data map[string]json.RawMessage `json:"data,omitempty"`
+}
+
+// WithProgress attaches a progress snapshot named name to the event. Each
+// field of data is serialized to JSON.
+func WithProgress(name string, data map[string]any) Option {
+	return func(e *Event) {
+		_ = SetProgress(e, name, data)
+	}
+}
+
+// SetProgress attaches a progress snapshot named name to the event.
+func SetProgress(e *Event, name string, data map[string]any) error {
+	p := Progress{Name: name, Data: make(map[string]json.RawMessage, len(data))}
+	for k, v := range data {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		p.Data[k] = raw
+	}
+	return SetExtension(e, ProgressExtensionKey, p)
+}
+
+// GetProgress returns the progress snapshot of the event, if any.
+func GetProgress(e *Event) (Progress, bool) {
+	p, ok, err := GetExtension[Progress](e, ProgressExtensionKey)
+	if err != nil || !ok || p.Name == "" {
+		return Progress{}, false
+	}
+	return p, true
+}
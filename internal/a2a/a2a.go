@@ -115,6 +115,15 @@ const (
 	// It carries a decoded form of Event.StateDelta so A2A peers can restore structured state updates.
 	MessageMetadataStateDeltaKey = "state_delta"
 
+	// MessageMetadataTaskEventSeqKey stores the position of a streaming event
+	// in the persisted task event log. Clients pass the last seen value back
+	// on tasks/resubscribe to skip events they already received.
+	MessageMetadataTaskEventSeqKey = "task_event_seq"
+
+	// ResubscribeMetadataLastEventSeqKey is the tasks/resubscribe metadata key
+	// carrying the last task event sequence the client received.
+	ResubscribeMetadataLastEventSeqKey = "last_event_seq"
+
 	// TextPartMetadataThoughtKey is the metadata key for thought/reasoning content in TextPart.
 	TextPartMetadataThoughtKey = "thought"
 
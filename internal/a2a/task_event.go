@@ -0,0 +1,113 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2a
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// StreamingEventTaskID returns the task ID carried by a streaming event, or
+// "" when the event does not belong to a task.
+func StreamingEventTaskID(evt protocol.StreamingMessageEvent) string {
+	switch r := evt.Result.(type) {
+	case *protocol.Task:
+		return r.ID
+	case *protocol.TaskStatusUpdateEvent:
+		return r.TaskID
+	case *protocol.TaskArtifactUpdateEvent:
+		return r.TaskID
+	case *protocol.Message:
+		if r.TaskID != nil {
+			return *r.TaskID
+		}
+	}
+	return ""
+}
+
+// IsFinalStreamingEvent reports whether evt ends the event stream of a task.
+func IsFinalStreamingEvent(evt protocol.StreamingMessageEvent) bool {
+	switch r := evt.Result.(type) {
+	case *protocol.TaskStatusUpdateEvent:
+		return r.Final || isTerminalTaskState(r.Status.State)
+	case *protocol.Task:
+		return isTerminalTaskState(r.Status.State)
+	}
+	return false
+}
+
+func isTerminalTaskState(state protocol.TaskState) bool {
+	switch state {
+	case protocol.TaskStateCompleted, protocol.TaskStateCanceled,
+		protocol.TaskStateFailed, protocol.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// SetTaskEventSeq stores the task event log position of evt in its metadata.
+func SetTaskEventSeq(evt protocol.StreamingMessageEvent, seq int64) {
+	if md := streamingEventMetadata(evt, true); md != nil {
+		md[MessageMetadataTaskEventSeqKey] = seq
+	}
+}
+
+// TaskEventSeq returns the task event log position of evt, or 0 when the
+// event carries none.
+func TaskEventSeq(evt protocol.StreamingMessageEvent) int64 {
+	return metadataInt64(streamingEventMetadata(evt, false), MessageMetadataTaskEventSeqKey)
+}
+
+// LastEventSeq returns the last received task event sequence from
+// tasks/resubscribe metadata.
+func LastEventSeq(metadata map[string]any) int64 {
+	return metadataInt64(metadata, ResubscribeMetadataLastEventSeqKey)
+}
+
+func streamingEventMetadata(evt protocol.StreamingMessageEvent, create bool) map[string]any {
+	var md *map[string]any
+	switch r := evt.Result.(type) {
+	case *protocol.Task:
+		md = &r.Metadata
+	case *protocol.TaskStatusUpdateEvent:
+		md = &r.Metadata
+	case *protocol.TaskArtifactUpdateEvent:
+		md = &r.Metadata
+	case *protocol.Message:
+		md = &r.Metadata
+	default:
+		return nil
+	}
+	if *md == nil && create {
+		*md = make(map[string]any)
+	}
+	return *md
+}
+
+// metadataInt64 reads an integer that may have been decoded from JSON.
+func metadataInt64(metadata map[string]any, key string) int64 {
+	switch v := metadata[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
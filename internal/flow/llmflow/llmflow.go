@@ -1882,6 +1882,10 @@ func cloneRequestForContextCompaction(req *model.Request) *model.Request {
 		req.StructuredOutput,
 	)
 	cloned.ExtraFields = cloneJSONMapForContextCompaction(req.ExtraFields)
+	if req.PromptCache != nil {
+		cache := *req.PromptCache
+		cloned.PromptCache = &cache
+	}
 	if req.Tools != nil {
 		cloned.Tools = make(map[string]tool.Tool, len(req.Tools))
 		for name, t := range req.Tools {
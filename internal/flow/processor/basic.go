@@ -23,6 +23,8 @@ import (
 type BasicRequestProcessor struct {
 	// GenerationConfig contains the default generation configuration.
 	GenerationConfig model.GenerationConfig
+	// PromptCache is the prompt cache hint set on every request.
+	PromptCache *model.PromptCache
 }
 
 // NewBasicRequestProcessor creates a new basic request processor with default settings.
@@ -51,6 +53,14 @@ func WithGenerationConfig(config model.GenerationConfig) BasicOption {
 	}
 }
 
+// WithPromptCache sets the prompt cache hint. An empty key defaults to the
+// agent name.
+func WithPromptCache(cache model.PromptCache) BasicOption {
+	return func(p *BasicRequestProcessor) {
+		p.PromptCache = &cache
+	}
+}
+
 // ProcessRequest implements the flow.RequestProcessor interface.
 // It handles setting generation parameters.
 func (p *BasicRequestProcessor) ProcessRequest(
@@ -105,6 +115,14 @@ func (p *BasicRequestProcessor) ProcessRequest(
 		}
 	}
 
+	if p.PromptCache != nil {
+		cache := *p.PromptCache
+		if cache.Key == "" {
+			cache.Key = invocation.AgentName
+		}
+		req.PromptCache = &cache
+	}
+
 	// Propagate structured output from invocation to request if present.
 	if invocation.StructuredOutput != nil {
 		req.StructuredOutput = invocation.StructuredOutput
@@ -192,6 +192,35 @@ func TestBasicReqProc_ModelRequestHeaders(t *testing.T) {
 	}
 }
 
+func TestBasicReqProc_PromptCache(t *testing.T) {
+	inv := &agent.Invocation{
+		AgentName:    "test-agent",
+		InvocationID: "test-123",
+	}
+	p := NewBasicRequestProcessor(WithPromptCache(model.PromptCache{System: true, Tools: true}))
+	req := &model.Request{}
+	p.ProcessRequest(context.Background(), inv, req, make(chan *event.Event, 1))
+	if req.PromptCache == nil || !req.PromptCache.System || req.PromptCache.Key != "test-agent" {
+		t.Fatalf("ProcessRequest() got prompt cache %+v, want system with key test-agent", req.PromptCache)
+	}
+	if p.PromptCache.Key != "" {
+		t.Fatalf("ProcessRequest() changed the processor prompt cache key to %q", p.PromptCache.Key)
+	}
+
+	p = NewBasicRequestProcessor(WithPromptCache(model.PromptCache{History: true, Key: "shared"}))
+	req = &model.Request{}
+	p.ProcessRequest(context.Background(), inv, req, make(chan *event.Event, 1))
+	if req.PromptCache == nil || req.PromptCache.Key != "shared" {
+		t.Fatalf("ProcessRequest() got prompt cache %+v, want key shared", req.PromptCache)
+	}
+
+	req = &model.Request{}
+	NewBasicRequestProcessor().ProcessRequest(context.Background(), inv, req, make(chan *event.Event, 1))
+	if req.PromptCache != nil {
+		t.Fatalf("ProcessRequest() got prompt cache %+v, want nil", req.PromptCache)
+	}
+}
+
 // Helper functions for test data
 func intPtr(i int) *int {
 	return &i
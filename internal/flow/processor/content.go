@@ -3672,12 +3672,13 @@ func (p *ContentRequestProcessor) getPreloadMemoryMessage(
 	if reader == nil || inv.Session == nil {
 		return nil
 	}
+	reader = scopedPreloadMemoryReader(ctx, inv, reader)
 	userKey := memory.UserKey{
 		AppName: inv.Session.AppName,
 		UserID:  inv.Session.UserID,
 	}
 	// Validate user key.
-	if userKey.AppName == "" || userKey.UserID == "" {
+	if userKey.AppName == "" || memory.CheckUserID(userKey.UserID) != nil {
 		return nil
 	}
 	// Handle PreloadMemory: 0 = disabled, -1 = all, N > 0 = adaptive budget.
@@ -3773,6 +3774,25 @@ func preloadMemoryReader(inv *agent.Invocation) memory.Reader {
 	return inv.MemoryService
 }
 
+// scopedPreloadMemoryReader reads through the shared memory scopes permitted
+// by the run context. The agent scope defaults to the current agent.
+func scopedPreloadMemoryReader(
+	ctx context.Context,
+	inv *agent.Invocation,
+	reader memory.Reader,
+) memory.Reader {
+	access := memory.ScopeAccessFromContext(ctx)
+	if access == nil {
+		return reader
+	}
+	if access.AgentName == "" && inv.AgentName != "" {
+		resolved := *access
+		resolved.AgentName = inv.AgentName
+		access = &resolved
+	}
+	return memory.NewScopedReader(reader, access)
+}
+
 func newPreloadMemoryMessage(
 	memories []*memory.Entry,
 	playbookOverride string,
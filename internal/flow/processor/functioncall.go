@@ -2972,12 +2972,16 @@ func (p *FunctionCallResponseProcessor) resolveToolCallTarget(
 				tools,
 				p.toolNameSuggestionOptions,
 			)
+			var modelName string
+			if invocation.Model != nil {
+				modelName = invocation.Model.Info().Name
+			}
 			log.ErrorfContext(
 				ctx,
 				"CallableTool %s not found (agent=%s, model=%s)",
 				toolCall.Function.Name,
 				invocation.AgentName,
-				invocation.Model.Info().Name,
+				modelName,
 			)
 			return toolCall, nil, true, fmt.Errorf(
 				"executeToolCall: %s",
@@ -20,6 +20,7 @@ import (
 	promptstate "trpc.group/trpc-go/trpc-agent-go/internal/prompt/adapter/state"
 	"trpc.group/trpc-go/trpc-agent-go/log"
 	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
 )
 
 // InstructionRequestProcessor implements instruction processing logic.
@@ -50,6 +51,13 @@ type InstructionRequestProcessor struct {
 	// StructuredOutputSchema is the JSON schema generated from structured_output.
 	// When provided, it takes precedence over OutputSchema for instruction injection.
 	StructuredOutputSchema map[string]any
+	// LogicTemplates renders instructions and system prompts with
+	// conditionals and loops over state.
+	LogicTemplates bool
+	// CompiledTemplates holds compiled templates by their text. An
+	// instruction or system prompt with the text of one is rendered with
+	// it, partials included, whether or not LogicTemplates is set.
+	CompiledTemplates map[string]*prompt.Template
 }
 
 const (
@@ -99,6 +107,22 @@ func WithStructuredOutputSchema(schema map[string]any) InstructionRequestProcess
 	}
 }
 
+// WithLogicTemplates enables conditionals and loops in instructions and
+// system prompts.
+func WithLogicTemplates(enabled bool) InstructionRequestProcessorOption {
+	return func(p *InstructionRequestProcessor) {
+		p.LogicTemplates = enabled
+	}
+}
+
+// WithCompiledTemplates renders instructions and system prompts whose text
+// matches a compiled template with that template.
+func WithCompiledTemplates(templates map[string]*prompt.Template) InstructionRequestProcessorOption {
+	return func(p *InstructionRequestProcessor) {
+		p.CompiledTemplates = templates
+	}
+}
+
 // WithInstructionResolver configures a dynamic resolver for instruction
 // content based on the current invocation.
 func WithInstructionResolver(
@@ -303,7 +327,19 @@ func (p *InstructionRequestProcessor) injectStateIntoContent(
 		return content
 	}
 
-	processedContent, err := promptstate.Render(content, invocation)
+	var (
+		processedContent string
+		err              error
+	)
+	if compiled, ok := p.CompiledTemplates[content]; ok {
+		processedContent, err = promptstate.RenderTemplate(compiled, invocation)
+	} else {
+		var opts []promptstate.Option
+		if p.LogicTemplates {
+			opts = append(opts, promptstate.WithLogic())
+		}
+		processedContent, err = promptstate.Render(content, invocation, opts...)
+	}
 	if err != nil {
 		log.ErrorfContext(
 			ctx,
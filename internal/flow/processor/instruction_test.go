@@ -201,6 +201,36 @@ func TestInstructionProcessor_RuntimeStatePlaceholders(t *testing.T) {
 	)
 }
 
+func TestInstructionProcessor_LogicTemplates(t *testing.T) {
+	invocation := &agent.Invocation{
+		AgentName:    testAgentName,
+		InvocationID: testInvocationID,
+		RunOptions: agent.RunOptions{RuntimeState: map[string]any{
+			"tools": []string{"search", "calculator"},
+		}},
+	}
+	req := &model.Request{}
+	processor := NewInstructionRequestProcessor(
+		"{{#each runtime:tools}}Use {{this}}.{{#unless @last}} {{/unless}}{{/each}}",
+		"{{#if runtime:premium}}Premium.{{else}}Standard.{{/if}}",
+		WithLogicTemplates(true),
+	)
+
+	processor.ProcessRequest(
+		context.Background(),
+		invocation,
+		req,
+		make(chan *event.Event, 1),
+	)
+
+	require.Len(t, req.Messages, 1)
+	require.Equal(
+		t,
+		"Standard.\n\nUse search. Use calculator.",
+		req.Messages[0].Content,
+	)
+}
+
 func TestFindSystemMessageIndex(t *testing.T) {
 	tests := []struct {
 		name     string
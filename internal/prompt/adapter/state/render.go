@@ -17,6 +17,7 @@ import (
 
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	promptcore "trpc.group/trpc-go/trpc-agent-go/internal/prompt/core"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -32,6 +33,7 @@ type Option func(*renderConfig)
 
 type renderConfig struct {
 	session *session.Session
+	logic   bool
 }
 
 // WithSession overrides the session used for session-backed placeholders.
@@ -43,6 +45,15 @@ func WithSession(sess *session.Session) Option {
 	}
 }
 
+// WithLogic renders the template with the conditionals and loops of
+// promptcore.LogicTemplate, so instructions can branch on state and iterate
+// lists stored as JSON in state. Partials are not available.
+func WithLogic() Option {
+	return func(cfg *renderConfig) {
+		cfg.logic = true
+	}
+}
+
 // Render replaces supported placeholders in template with values from
 // invocation state, runtime state, and session state.
 //
@@ -78,9 +89,81 @@ func Render(
 			opt(&cfg)
 		}
 	}
+	if cfg.logic {
+		return renderLogic(template, invocation, cfg.session)
+	}
 	return render(template, invocation, cfg.session)
 }
 
+func renderLogic(
+	template string,
+	invocation *agent.Invocation,
+	sess *session.Session,
+) (string, error) {
+	if template == "" {
+		return template, nil
+	}
+	parsed, err := promptcore.ParseLogic(template, promptcore.SyntaxModeMixedBrace)
+	if err != nil {
+		return template, err
+	}
+	resolver := stateResolver{
+		invocation: invocation,
+		session:    sess,
+	}
+	rendered, err := parsed.RenderLogic(
+		promptcore.Env{Resolve: resolver.Resolve},
+		promptcore.PreserveUnknown,
+		promptcore.WithAcceptName(isValidStateName),
+	)
+	if err != nil {
+		return template, err
+	}
+	return rendered, nil
+}
+
+// RenderTemplate renders a compiled template, partials included, with
+// values from invocation state, runtime state and session state as Render
+// does with WithLogic. Only placeholders of the state subset are resolved.
+// On error the source text of the template is returned with the error.
+func RenderTemplate(
+	template *prompt.Template,
+	invocation *agent.Invocation,
+	opts ...Option,
+) (string, error) {
+	cfg := renderConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	text := template.Text().Template
+	rendered, err := template.Render(
+		prompt.RenderEnv{Resolver: templateResolver{stateResolver{
+			invocation: invocation,
+			session:    cfg.session,
+		}}},
+		prompt.WithUnknownBehavior(prompt.PreserveUnknown),
+	)
+	if err != nil {
+		return text, err
+	}
+	return rendered, nil
+}
+
+// templateResolver adapts stateResolver to prompt.Resolver, leaving names
+// outside the state subset unresolved.
+type templateResolver struct {
+	stateResolver
+}
+
+func (r templateResolver) Resolve(ref prompt.Ref) (string, bool, error) {
+	if !isValidStateName(ref.Name) {
+		return "", false, nil
+	}
+	return r.stateResolver.Resolve(ref.Name)
+}
+
 func render(
 	template string,
 	invocation *agent.Invocation,
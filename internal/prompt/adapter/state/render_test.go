@@ -9,11 +9,13 @@
 package state
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 
 	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -707,3 +709,68 @@ func newTestSession(values map[string]any) *session.Session {
 	}
 	return &session.Session{State: stateMap}
 }
+
+func TestRender_WithLogic(t *testing.T) {
+	invocation := &agent.Invocation{
+		Session: &session.Session{
+			State: session.StateMap{
+				"user:tier": []byte(`"gold"`),
+				"user:orders": []byte(
+					`[{"id":"A1","total":12.5},{"id":"B2","total":3}]`),
+				"premium": []byte(`false`),
+			},
+		},
+	}
+	invocation.RunOptions.RuntimeState = map[string]any{
+		"locales": []string{"en", "fr"},
+	}
+	template := "{{#if user:tier == \"gold\"}}Gold customer.{{/if}}\n" +
+		"{{#each user:orders as order}}\n" +
+		"- {order.id}: {order.total}\n" +
+		"{{/each}}\n" +
+		"{#each runtime:locales}{this} {/each}\n" +
+		"{{#if premium}}premium{{else}}standard{{/if}} {unknown} {user:orders.0.id}"
+
+	result, err := Render(template, invocation, WithLogic())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "Gold customer.\n- A1: 12.5\n- B2: 3\nen fr \nstandard {unknown} A1"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Without WithLogic the tags stay literal.
+	result, err = Render("{{#if premium}}x{{/if}}", invocation)
+	if err != nil || result != "{{#if premium}}x{{/if}}" {
+		t.Errorf("Unexpected legacy result %q, %v", result, err)
+	}
+
+	result, err = Render("{{#if premium}}", invocation, WithLogic())
+	if err == nil || result != "{{#if premium}}" {
+		t.Errorf("Expected parse error with the original template, got %q, %v", result, err)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	invocation := &agent.Invocation{
+		Session: &session.Session{State: session.StateMap{
+			"user:tier":  []byte(`"gold"`),
+			"bad-name":   []byte(`"x"`),
+			"user:items": []byte(`["a","b"]`),
+		}},
+	}
+	tmpl, err := prompt.Text{Template: "{{> header}}{{#each user:items}}{this}{{/each}} {bad-name} {missing}"}.
+		Compile(context.Background(), prompt.WithPartial("header", prompt.Text{Template: "{user:tier}: "}))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	result, err := RenderTemplate(tmpl, invocation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "gold: ab {bad-name} {missing}"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
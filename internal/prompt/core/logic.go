@@ -0,0 +1,929 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package promptcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPartialDepth bounds partial nesting at render time as a guard against
+// cycles linked by mistake.
+const maxPartialDepth = 32
+
+const (
+	keywordIf     = "if"
+	keywordUnless = "unless"
+	keywordEach   = "each"
+	keywordElse   = "else"
+	defaultAlias  = "this"
+)
+
+// LogicTemplate is a parsed template of the logic dialect. On top of the
+// placeholders of its syntax mode it supports these tags, written with the
+// same delimiters as placeholders:
+//
+//	{{#if cond}} ... {{else}} ... {{/if}}
+//	{{#unless cond}} ... {{else}} ... {{/unless}}
+//	{{#each list}} {{this}} {{@index}} {{/each}}
+//	{{#each list as item}} {{item.name}} {{/each}}
+//	{{> partial}}
+//
+// A condition is a value path, optionally prefixed with "not", or a
+// comparison of a path with a literal or another path using == or !=.
+// Tags alone on their line remove the whole line from the output.
+//
+// Templates are sandboxed: they can only read the values of their
+// environment and cannot call functions.
+type LogicTemplate struct {
+	syntax SyntaxMode
+	nodes  []logicNode
+}
+
+// Reference is a value referenced by a logic template.
+type Reference struct {
+	// Name is the referenced path, such as "user:tier" or "order.id".
+	Name string
+	// Optional is true when every reference is an optional placeholder.
+	Optional bool
+}
+
+type logicNodeKind int
+
+const (
+	textLogicNode logicNodeKind = iota
+	ifLogicNode
+	eachLogicNode
+	partialLogicNode
+)
+
+type logicNode struct {
+	kind logicNodeKind
+	// text is the literal template text of a text node.
+	text string
+	// cond and negate describe if and unless nodes.
+	cond   condition
+	negate bool
+	// path and alias describe each nodes.
+	path  string
+	alias string
+	body  []logicNode
+	// elseBody is rendered when the condition is false or the list is
+	// empty.
+	elseBody []logicNode
+	// name and partial describe partial nodes.
+	name    string
+	partial *LogicTemplate
+}
+
+type condition struct {
+	left   operand
+	op     string
+	right  operand
+	negate bool
+}
+
+type operand struct {
+	path    string
+	literal string
+}
+
+type logicTag struct {
+	keyword string
+	closing bool
+	arg     string
+	line    int
+}
+
+// ParseLogic parses template in the logic dialect.
+func ParseLogic(template string, syntax SyntaxMode) (*LogicTemplate, error) {
+	p := logicParser{template: template, syntax: syntax}
+	nodes, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &LogicTemplate{syntax: syntax, nodes: nodes}, nil
+}
+
+type logicParser struct {
+	template string
+	syntax   SyntaxMode
+}
+
+type openBlock struct {
+	node     logicNode
+	tag      logicTag
+	inElse   bool
+	previous []logicNode
+}
+
+func (p *logicParser) parse() ([]logicNode, error) {
+	var (
+		nodes []logicNode
+		stack []openBlock
+		last  int
+	)
+	template := p.template
+	for i := 0; i < len(template); {
+		span, inner, isTag := scanLogicTag(template, i, p.syntax)
+		if span == 0 {
+			i++
+			continue
+		}
+		if !isTag {
+			i += span
+			continue
+		}
+		line := strings.Count(template[:i], "\n") + 1
+		tag, err := parseLogicTag(inner, line)
+		if err != nil {
+			return nil, err
+		}
+
+		textEnd, next := i, i+span
+		if lineStart, lineEnd, ok := standaloneLine(template, i, i+span); ok &&
+			lineStart >= last {
+			textEnd, next = lineStart, lineEnd
+		}
+		if textEnd > last {
+			nodes = append(nodes, logicNode{
+				kind: textLogicNode,
+				text: template[last:textEnd],
+			})
+		}
+		i, last = next, next
+
+		switch {
+		case tag.keyword == ">":
+			nodes = append(nodes, logicNode{kind: partialLogicNode, name: tag.arg})
+		case tag.keyword == keywordElse:
+			if len(stack) == 0 || stack[len(stack)-1].inElse {
+				return nil, fmt.Errorf("prompt: line %d: unexpected else", line)
+			}
+			top := &stack[len(stack)-1]
+			top.node.body = nodes
+			top.inElse = true
+			nodes = nil
+		case tag.closing:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("prompt: line %d: unexpected /%s",
+					line, tag.keyword)
+			}
+			top := stack[len(stack)-1]
+			if top.tag.keyword != tag.keyword {
+				return nil, fmt.Errorf(
+					"prompt: line %d: /%s closes #%s opened on line %d",
+					line, tag.keyword, top.tag.keyword, top.tag.line,
+				)
+			}
+			stack = stack[:len(stack)-1]
+			if top.inElse {
+				top.node.elseBody = nodes
+			} else {
+				top.node.body = nodes
+			}
+			nodes = append(top.previous, top.node)
+		default:
+			node, err := newBlockNode(tag)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, openBlock{node: node, tag: tag, previous: nodes})
+			nodes = nil
+		}
+	}
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("prompt: line %d: #%s is not closed",
+			top.tag.line, top.tag.keyword)
+	}
+	if last < len(template) {
+		nodes = append(nodes, logicNode{kind: textLogicNode, text: template[last:]})
+	}
+	return nodes, nil
+}
+
+func newBlockNode(tag logicTag) (logicNode, error) {
+	switch tag.keyword {
+	case keywordEach:
+		fields := strings.Fields(tag.arg)
+		node := logicNode{kind: eachLogicNode, alias: defaultAlias}
+		switch {
+		case len(fields) == 1:
+		case len(fields) == 3 && fields[1] == "as" && isValidName(fields[2]) &&
+			!strings.ContainsAny(fields[2], ".@"):
+			node.alias = fields[2]
+		default:
+			return logicNode{}, fmt.Errorf(
+				"prompt: line %d: #each expects \"list\" or \"list as name\"",
+				tag.line,
+			)
+		}
+		node.path = fields[0]
+		return node, nil
+	default:
+		cond, err := parseCondition(tag.arg)
+		if err != nil {
+			return logicNode{}, fmt.Errorf("prompt: line %d: #%s: %w",
+				tag.line, tag.keyword, err)
+		}
+		return logicNode{
+			kind:   ifLogicNode,
+			cond:   cond,
+			negate: tag.keyword == keywordUnless,
+		}, nil
+	}
+}
+
+// scanLogicTag reports the span of the delimited token at start and whether
+// it is a logic tag. A non-zero span without a tag marks literal text that
+// must be skipped as a whole.
+func scanLogicTag(template string, start int, syntax SyntaxMode) (int, string, bool) {
+	if template[start] != '{' {
+		return 0, "", false
+	}
+	double := strings.HasPrefix(template[start:], "{{")
+	switch {
+	case double && syntax == SyntaxModeSingleBrace:
+		return literalDoubleCurlySpan(template, start), "", false
+	case double:
+		end := strings.Index(template[start+2:], "}}")
+		if end < 0 {
+			return 0, "", false
+		}
+		inner := strings.TrimSpace(template[start+2 : start+2+end])
+		if isLogicTag(inner) {
+			return end + 4, inner, true
+		}
+		return 0, "", false
+	case syntax == SyntaxModeDoubleBrace:
+		return 0, "", false
+	default:
+		end := strings.IndexByte(template[start+1:], '}')
+		if end < 0 {
+			return 0, "", false
+		}
+		inner := strings.TrimSpace(template[start+1 : start+1+end])
+		if isLogicTag(inner) {
+			return end + 2, inner, true
+		}
+		return 0, "", false
+	}
+}
+
+func isLogicTag(inner string) bool {
+	if inner == keywordElse {
+		return true
+	}
+	if strings.HasPrefix(inner, ">") {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(inner, "/"); ok {
+		switch strings.TrimSpace(rest) {
+		case keywordIf, keywordUnless, keywordEach:
+			return true
+		}
+		return false
+	}
+	if rest, ok := strings.CutPrefix(inner, "#"); ok {
+		keyword, _, _ := strings.Cut(rest, " ")
+		switch keyword {
+		case keywordIf, keywordUnless, keywordEach:
+			return true
+		}
+	}
+	return false
+}
+
+func parseLogicTag(inner string, line int) (logicTag, error) {
+	switch {
+	case inner == keywordElse:
+		return logicTag{keyword: keywordElse, line: line}, nil
+	case strings.HasPrefix(inner, ">"):
+		name := strings.TrimSpace(inner[1:])
+		if !isValidName(name) {
+			return logicTag{}, fmt.Errorf("prompt: line %d: invalid partial name %q",
+				line, name)
+		}
+		return logicTag{keyword: ">", arg: name, line: line}, nil
+	case strings.HasPrefix(inner, "/"):
+		return logicTag{
+			keyword: strings.TrimSpace(inner[1:]),
+			closing: true,
+			line:    line,
+		}, nil
+	default:
+		keyword, arg, _ := strings.Cut(inner[1:], " ")
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			return logicTag{}, fmt.Errorf("prompt: line %d: #%s needs an argument",
+				line, keyword)
+		}
+		return logicTag{keyword: keyword, arg: arg, line: line}, nil
+	}
+}
+
+// standaloneLine reports the line bounds of a tag spanning [start, end)
+// when only whitespace surrounds it on its line.
+func standaloneLine(template string, start, end int) (int, int, bool) {
+	lineStart := strings.LastIndexByte(template[:start], '\n') + 1
+	if strings.TrimLeft(template[lineStart:start], " \t") != "" {
+		return 0, 0, false
+	}
+	rest := end
+	for rest < len(template) && (template[rest] == ' ' || template[rest] == '\t') {
+		rest++
+	}
+	switch {
+	case rest == len(template):
+		return lineStart, rest, true
+	case template[rest] == '\n':
+		return lineStart, rest + 1, true
+	case strings.HasPrefix(template[rest:], "\r\n"):
+		return lineStart, rest + 2, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func parseCondition(s string) (condition, error) {
+	var c condition
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "not "); ok {
+		c.negate = true
+		s = strings.TrimSpace(rest)
+	}
+	left, op, right := s, "", ""
+	for _, candidate := range []string{"==", "!="} {
+		if idx := strings.Index(s, candidate); idx >= 0 {
+			left, op, right = s[:idx], candidate, s[idx+len(candidate):]
+			break
+		}
+	}
+	left = strings.TrimSpace(left)
+	if !isValidName(left) || isLiteral(left) {
+		return condition{}, fmt.Errorf("invalid value path %q", left)
+	}
+	c.left = operand{path: left}
+	if op == "" {
+		return c, nil
+	}
+	right = strings.TrimSpace(right)
+	r, err := parseOperand(right)
+	if err != nil {
+		return condition{}, err
+	}
+	c.op, c.right = op, r
+	return c, nil
+}
+
+func parseOperand(s string) (operand, error) {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '\'' {
+			return operand{literal: s[1 : len(s)-1]}, nil
+		}
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return operand{}, fmt.Errorf("invalid string literal %s", s)
+		}
+		return operand{literal: unquoted}, nil
+	}
+	if isLiteral(s) {
+		return operand{literal: s}, nil
+	}
+	if !isValidName(s) {
+		return operand{}, fmt.Errorf("invalid operand %q", s)
+	}
+	return operand{path: s}, nil
+}
+
+func isLiteral(s string) bool {
+	switch s {
+	case "true", "false", "null":
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// Partials returns the names of the partials included by the template.
+func (t *LogicTemplate) Partials() []string {
+	var names []string
+	walkLogicNodes(t.nodes, func(n *logicNode) {
+		if n.kind == partialLogicNode {
+			names = append(names, n.name)
+		}
+	})
+	return uniqueSortedStrings(names)
+}
+
+// Link sets the template rendered for the partial name.
+func (t *LogicTemplate) Link(name string, partial *LogicTemplate) {
+	walkLogicNodes(t.nodes, func(n *logicNode) {
+		if n.kind == partialLogicNode && n.name == name {
+			n.partial = partial
+		}
+	})
+}
+
+func walkLogicNodes(nodes []logicNode, fn func(*logicNode)) {
+	for i := range nodes {
+		fn(&nodes[i])
+		walkLogicNodes(nodes[i].body, fn)
+		walkLogicNodes(nodes[i].elseBody, fn)
+	}
+}
+
+// References returns the values referenced by the template and its linked
+// partials, excluding loop variables.
+func (t *LogicTemplate) References(opts ...Option) []Reference {
+	cfg := buildConfig(opts...)
+	optional := make(map[string]bool)
+	add := func(name string, opt bool) {
+		if prev, ok := optional[name]; ok {
+			optional[name] = prev && opt
+			return
+		}
+		optional[name] = opt
+	}
+	t.collectReferences(t.nodes, nil, cfg, add, 0)
+	refs := make([]Reference, 0, len(optional))
+	for name, opt := range optional {
+		refs = append(refs, Reference{Name: name, Optional: opt})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs
+}
+
+func (t *LogicTemplate) collectReferences(
+	nodes []logicNode,
+	scoped []string,
+	cfg config,
+	add func(string, bool),
+	depth int,
+) {
+	inScope := func(name string) bool {
+		root, _, _ := strings.Cut(name, ".")
+		if strings.HasPrefix(root, "@") || (root == defaultAlias && len(scoped) > 0) {
+			return true
+		}
+		for _, s := range scoped {
+			if s == root {
+				return true
+			}
+		}
+		return false
+	}
+	for _, n := range nodes {
+		switch n.kind {
+		case textLogicNode:
+			for _, part := range analyzeText(n.text, t.syntax, cfg) {
+				if part.placeholder == nil || inScope(part.placeholder.name) {
+					continue
+				}
+				if !acceptsPath(cfg.acceptName, part.placeholder.name) {
+					continue
+				}
+				add(part.placeholder.name, part.placeholder.optional)
+			}
+		case ifLogicNode:
+			for _, o := range []operand{n.cond.left, n.cond.right} {
+				if o.path != "" && !inScope(o.path) {
+					add(o.path, false)
+				}
+			}
+			t.collectReferences(n.body, scoped, cfg, add, depth)
+			t.collectReferences(n.elseBody, scoped, cfg, add, depth)
+		case eachLogicNode:
+			if !inScope(n.path) {
+				add(n.path, false)
+			}
+			inner := append(append([]string(nil), scoped...), n.alias)
+			t.collectReferences(n.body, inner, cfg, add, depth)
+			t.collectReferences(n.elseBody, scoped, cfg, add, depth)
+		case partialLogicNode:
+			if n.partial != nil && depth < maxPartialDepth {
+				n.partial.collectReferences(n.partial.nodes, scoped, cfg, add, depth+1)
+			}
+		}
+	}
+}
+
+// RenderLogic renders t with values from env. Values are looked up in
+// env.Data, then env.Vars, then env.Resolve. String values holding JSON
+// arrays or objects are decoded, so lists stored as JSON can be iterated.
+func (t *LogicTemplate) RenderLogic(
+	env Env,
+	unknown UnknownBehavior,
+	opts ...Option,
+) (string, error) {
+	r := logicRenderer{env: env, unknown: unknown, cfg: buildConfig(opts...)}
+	var b strings.Builder
+	if err := r.render(&b, t, t.nodes, nil, 0); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type loopScope struct {
+	parent *loopScope
+	alias  string
+	value  any
+	key    string
+	index  int
+	length int
+}
+
+type logicRenderer struct {
+	env     Env
+	unknown UnknownBehavior
+	cfg     config
+}
+
+func (r *logicRenderer) render(
+	b *strings.Builder,
+	t *LogicTemplate,
+	nodes []logicNode,
+	scope *loopScope,
+	depth int,
+) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case textLogicNode:
+			if err := r.renderText(b, t.syntax, n.text, scope); err != nil {
+				return err
+			}
+		case ifLogicNode:
+			ok, err := r.evaluate(n.cond, scope)
+			if err != nil {
+				return err
+			}
+			body := n.body
+			if ok == n.negate {
+				body = n.elseBody
+			}
+			if err := r.render(b, t, body, scope, depth); err != nil {
+				return err
+			}
+		case eachLogicNode:
+			v, _, err := r.lookup(n.path, scope)
+			if err != nil {
+				return err
+			}
+			items, keys := iterate(v)
+			if len(items) == 0 {
+				if err := r.render(b, t, n.elseBody, scope, depth); err != nil {
+					return err
+				}
+				continue
+			}
+			for i, item := range items {
+				inner := &loopScope{
+					parent: scope,
+					alias:  n.alias,
+					value:  item,
+					index:  i,
+					length: len(items),
+				}
+				if keys != nil {
+					inner.key = keys[i]
+				}
+				if err := r.render(b, t, n.body, inner, depth); err != nil {
+					return err
+				}
+			}
+		case partialLogicNode:
+			if n.partial == nil {
+				return fmt.Errorf("prompt: partial %q is not defined", n.name)
+			}
+			if depth >= maxPartialDepth {
+				return fmt.Errorf("prompt: partial %q nested too deeply", n.name)
+			}
+			if err := r.render(b, n.partial, n.partial.nodes, scope, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *logicRenderer) renderText(
+	b *strings.Builder,
+	syntax SyntaxMode,
+	text string,
+	scope *loopScope,
+) error {
+	accept := r.cfg.acceptName
+	out, err := Render(
+		text,
+		syntax,
+		Env{Resolve: func(name string) (string, bool, error) {
+			v, ok, err := r.lookup(name, scope)
+			if err != nil || !ok {
+				return "", false, err
+			}
+			return stringify(v), true, nil
+		}},
+		r.unknown,
+		WithAcceptName(func(name string) bool {
+			if _, ok := scopeValue(name, scope); ok {
+				return true
+			}
+			return acceptsPath(accept, name)
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	b.WriteString(out)
+	return nil
+}
+
+func (r *logicRenderer) evaluate(c condition, scope *loopScope) (bool, error) {
+	left, _, err := r.lookup(c.left.path, scope)
+	if err != nil {
+		return false, err
+	}
+	var result bool
+	if c.op == "" {
+		result = truthy(left)
+	} else {
+		right, err := r.operandValue(c.right, scope)
+		if err != nil {
+			return false, err
+		}
+		equal := stringify(normalize(left)) == stringify(normalize(right))
+		result = equal == (c.op == "==")
+	}
+	return result != c.negate, nil
+}
+
+func (r *logicRenderer) operandValue(o operand, scope *loopScope) (any, error) {
+	if o.path == "" {
+		if o.literal == "null" {
+			return nil, nil
+		}
+		return o.literal, nil
+	}
+	v, _, err := r.lookup(o.path, scope)
+	return v, err
+}
+
+// lookup resolves a value path against loop variables first and the
+// environment second.
+func (r *logicRenderer) lookup(path string, scope *loopScope) (any, bool, error) {
+	if v, ok := scopeValue(path, scope); ok {
+		return v, true, nil
+	}
+	if v, ok, err := r.lookupEnv(path); err != nil || ok {
+		return v, ok, err
+	}
+	root, rest, found := strings.Cut(path, ".")
+	if !found {
+		return nil, false, nil
+	}
+	v, ok, err := r.lookupEnv(root)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	v, ok = navigate(v, rest)
+	return v, ok, nil
+}
+
+func (r *logicRenderer) lookupEnv(name string) (any, bool, error) {
+	if v, ok := r.env.Data[name]; ok {
+		return v, true, nil
+	}
+	if v, ok := r.env.Vars[name]; ok {
+		return v, true, nil
+	}
+	if r.env.Resolve == nil {
+		return nil, false, nil
+	}
+	if r.cfg.acceptName != nil && !r.cfg.acceptName(name) {
+		return nil, false, nil
+	}
+	v, ok, err := r.env.Resolve(name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// acceptsPath reports whether accept allows name or, for dotted paths, its
+// root value.
+func acceptsPath(accept func(string) bool, name string) bool {
+	if accept == nil || accept(name) {
+		return true
+	}
+	root, _, found := strings.Cut(name, ".")
+	return found && accept(root)
+}
+
+// scopeValue resolves path against the loop variables in scope.
+func scopeValue(path string, scope *loopScope) (any, bool) {
+	if scope == nil {
+		return nil, false
+	}
+	root, rest, _ := strings.Cut(path, ".")
+	switch root {
+	case "@index":
+		return scope.index, rest == ""
+	case "@number":
+		return scope.index + 1, rest == ""
+	case "@first":
+		return scope.index == 0, rest == ""
+	case "@last":
+		return scope.index == scope.length-1, rest == ""
+	case "@key":
+		return scope.key, rest == ""
+	case defaultAlias:
+		if rest == "" {
+			return scope.value, true
+		}
+		return navigate(scope.value, rest)
+	}
+	for s := scope; s != nil; s = s.parent {
+		if s.alias != root {
+			continue
+		}
+		if rest == "" {
+			return s.value, true
+		}
+		return navigate(s.value, rest)
+	}
+	return nil, false
+}
+
+// normalize decodes JSON arrays and objects held in strings or bytes.
+func normalize(v any) any {
+	var raw []byte
+	switch s := v.(type) {
+	case string:
+		raw = []byte(strings.TrimSpace(s))
+	case []byte:
+		raw = bytes.TrimSpace(s)
+	case json.RawMessage:
+		raw = bytes.TrimSpace(s)
+	default:
+		return v
+	}
+	if len(raw) == 0 || (raw[0] != '[' && raw[0] != '{') || !json.Valid(raw) {
+		if b, ok := v.([]byte); ok {
+			return string(b)
+		}
+		if b, ok := v.(json.RawMessage); ok {
+			return string(b)
+		}
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return v
+	}
+	return decoded
+}
+
+func navigate(v any, path string) (any, bool) {
+	for _, seg := range strings.Split(path, ".") {
+		v = normalize(v)
+		rv := reflect.ValueOf(v)
+		switch {
+		case !rv.IsValid():
+			return nil, false
+		case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+			elem := rv.MapIndex(reflect.ValueOf(seg).Convert(rv.Type().Key()))
+			if !elem.IsValid() {
+				return nil, false
+			}
+			v = elem.Interface()
+		case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= rv.Len() {
+				return nil, false
+			}
+			v = rv.Index(i).Interface()
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// iterate returns the elements of a list, or the values of a map in key
+// order together with the keys.
+func iterate(v any) ([]any, []string) {
+	v = normalize(v)
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if _, isString := v.(string); isString {
+			return nil, nil
+		}
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, nil
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		items := make([]any, len(keys))
+		for i, k := range keys {
+			items[i] = rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface()
+		}
+		return items, keys
+	default:
+		return nil, nil
+	}
+}
+
+// truthy reports whether v counts as true in a condition. Missing values,
+// false, zero, empty strings and lists, and the strings "false", "0" and
+// "null" are false.
+func truthy(v any) bool {
+	v = normalize(v)
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		switch strings.TrimSpace(x) {
+		case "", "false", "0", "null":
+			return false
+		}
+		return true
+	case json.Number:
+		f, err := x.Float64()
+		return err != nil || f != 0
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() != 0
+	case reflect.Pointer, reflect.Interface:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+func stringify(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case fmt.Stringer:
+		return x.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
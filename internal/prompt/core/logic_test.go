@@ -0,0 +1,259 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package promptcore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogicTemplate_RenderLogic(t *testing.T) {
+	data := map[string]any{
+		"tier":    "gold",
+		"other":   "gold",
+		"count":   0,
+		"premium": true,
+		"empty":   []string{},
+		"orders": []map[string]any{
+			{"id": "A1", "total": 12.5},
+			{"id": "B2", "total": 3},
+		},
+		"prices":      map[string]int{"b": 2, "a": 1},
+		"json_list":   `[{"id":"J1"},{"id":"J2"}]`,
+		"json_map":    `{"y":"2","x":"1"}`,
+		"json_empty":  `[]`,
+		"json_object": `{"user":{"name":"Ann"},"tags":["t0","t1"]}`,
+		"off":         "false",
+		"zero":        "0",
+		"null_string": "null",
+	}
+	tests := []struct {
+		name     string
+		template string
+		syntax   SyntaxMode
+		want     string
+	}{
+		{"if true", "{{#if premium}}yes{{/if}}", SyntaxModeMixedBrace, "yes"},
+		{"if else", "{{#if missing}}yes{{else}}no{{/if}}", SyntaxModeMixedBrace, "no"},
+		{"unless", "{{#unless count}}none{{else}}some{{/unless}}", SyntaxModeMixedBrace, "none"},
+		{"not", "{{#if not premium}}a{{else}}b{{/if}}", SyntaxModeMixedBrace, "b"},
+		{"equals literal", `{{#if tier == "gold"}}gold{{/if}}`, SyntaxModeMixedBrace, "gold"},
+		{"equals single quoted", `{{#if tier == 'silver'}}x{{else}}y{{/if}}`, SyntaxModeMixedBrace, "y"},
+		{"not equals", `{{#if tier != "gold"}}x{{else}}y{{/if}}`, SyntaxModeMixedBrace, "y"},
+		{"equals path", "{{#if tier == other}}same{{/if}}", SyntaxModeMixedBrace, "same"},
+		{"equals number", "{{#if count == 0}}zero{{/if}}", SyntaxModeMixedBrace, "zero"},
+		{"equals null", "{{#if missing == null}}unset{{/if}}", SyntaxModeMixedBrace, "unset"},
+		{
+			"falsy values",
+			"{{#if empty}}1{{/if}}{{#if json_empty}}2{{/if}}{{#if off}}3{{/if}}" +
+				"{{#if zero}}4{{/if}}{{#if null_string}}5{{/if}}{{#if missing}}6{{/if}}",
+			SyntaxModeMixedBrace,
+			"",
+		},
+		{
+			"each list",
+			"{{#each orders}}{{@index}}/{{@number}}:{{this.id}}" +
+				"{{#if @first}}<{{/if}}{{#if @last}}>{{/if}} {{/each}}",
+			SyntaxModeMixedBrace,
+			"0/1:A1< 1/2:B2> ",
+		},
+		{
+			"each alias",
+			"{{#each orders as o}}{o.id}={o.total};{{/each}}",
+			SyntaxModeMixedBrace,
+			"A1=12.5;B2=3;",
+		},
+		{"each map in key order", "{{#each prices}}{{@key}}={{this}} {{/each}}", SyntaxModeMixedBrace, "a=1 b=2 "},
+		{"each JSON list", "{{#each json_list as item}}{item.id},{{/each}}", SyntaxModeMixedBrace, "J1,J2,"},
+		{"each JSON map", "{{#each json_map}}{{@key}}{{this}}{{/each}}", SyntaxModeMixedBrace, "x1y2"},
+		{"each else", "{{#each json_empty}}x{{else}}none{{/each}}", SyntaxModeMixedBrace, "none"},
+		{
+			"nested each reads the outer alias",
+			"{{#each orders as o}}{{#each json_map as v}}{o.id}{v}{{/each}} {{/each}}",
+			SyntaxModeMixedBrace,
+			"A11A12 B21B22 ",
+		},
+		{
+			"navigation into JSON",
+			"{json_object.user.name} {json_object.tags.1} {orders.0.id}",
+			SyntaxModeMixedBrace,
+			"Ann t1 A1",
+		},
+		{"unknown placeholders stay", "{missing} {{#if premium}}{{missing}}{{/if}}", SyntaxModeMixedBrace, "{missing} {{missing}}"},
+		{
+			"standalone lines are removed",
+			"a\n  {{#if premium}}  \nb\n{{else}}\nc\n{{/if}}\nd",
+			SyntaxModeMixedBrace,
+			"a\nb\nd",
+		},
+		{"standalone CRLF lines", "a\r\n{{#if premium}}\r\nb\r\n{{/if}}\r\n", SyntaxModeMixedBrace, "a\r\nb\r\n"},
+		{"inline tags keep the line", "a {{#if premium}}b{{/if}} c\n", SyntaxModeMixedBrace, "a b c\n"},
+		{"single brace tags", "{#if premium}{tier}{/if}", SyntaxModeSingleBrace, "gold"},
+		{"single brace mode leaves double braces", "{{#if premium}}x{{/if}}", SyntaxModeSingleBrace, "{{#if premium}}x{{/if}}"},
+		{"double brace mode leaves single braces", "{#if premium}x{/if}", SyntaxModeDoubleBrace, "{#if premium}x{/if}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseLogic(tt.template, tt.syntax)
+			if err != nil {
+				t.Fatalf("ParseLogic: unexpected error: %v", err)
+			}
+			got, err := tmpl.RenderLogic(Env{Data: data}, PreserveUnknown)
+			if err != nil {
+				t.Fatalf("RenderLogic: unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("RenderLogic: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogicTemplate_ResolveAndVars(t *testing.T) {
+	tmpl, err := ParseLogic("{{#if flag}}{name}: {{#each list}}{{this}}{{/each}}{{/if}}",
+		SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	var resolved []string
+	got, err := tmpl.RenderLogic(Env{
+		Vars: map[string]string{"name": "Ann"},
+		Resolve: func(name string) (string, bool, error) {
+			resolved = append(resolved, name)
+			switch name {
+			case "flag":
+				return "true", true, nil
+			case "list":
+				return `["a","b"]`, true, nil
+			}
+			return "", false, nil
+		},
+	}, PreserveUnknown, WithAcceptName(func(name string) bool { return name != "hidden" }))
+	if err != nil {
+		t.Fatalf("RenderLogic: unexpected error: %v", err)
+	}
+	if got != "Ann: ab" {
+		t.Fatalf("RenderLogic: got %q, want %q", got, "Ann: ab")
+	}
+	if !reflect.DeepEqual(resolved, []string{"flag", "list"}) {
+		t.Fatalf("resolved %v", resolved)
+	}
+
+	tmpl, err = ParseLogic("{{#if hidden}}x{{else}}y{{/if}} {missing}", SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	got, err = tmpl.RenderLogic(Env{Resolve: func(string) (string, bool, error) {
+		return "true", true, nil
+	}}, PreserveUnknown, WithAcceptName(func(name string) bool { return name != "hidden" }))
+	if err != nil || got != "y true" {
+		t.Fatalf("RenderLogic: got %q, %v", got, err)
+	}
+
+	tmpl, err = ParseLogic("{{#if premium}}{missing}{{/if}}", SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	if _, err := tmpl.RenderLogic(Env{Data: map[string]any{"premium": true}}, ErrorOnUnknown); err == nil {
+		t.Fatal("RenderLogic: expected an error for an unknown placeholder")
+	}
+}
+
+func TestParseLogic_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"unclosed", "{{#if x}}", "line 1: #if is not closed"},
+		{"unexpected close", "a\nb\n{{/if}}", "line 3: unexpected /if"},
+		{"mismatched close", "{{#if x}}\n{{/each}}", "line 2: /each closes #if opened on line 1"},
+		{"else outside a block", "{{else}}", "unexpected else"},
+		{"second else", "{{#if x}}{{else}}{{else}}{{/if}}", "unexpected else"},
+		{"missing argument", "{{#each}}{{/each}}", "#each needs an argument"},
+		{"bad each", "{{#each list as}}{{/each}}", `#each expects "list" or "list as name"`},
+		{"bad alias", "{{#each list as a.b}}{{/each}}", `#each expects "list" or "list as name"`},
+		{"literal condition", "{{#if 1}}{{/if}}", `invalid value path "1"`},
+		{"bad operand", `{{#if x == "y}}{{/if}}`, "invalid operand"},
+		{"bad string literal", `{{#if x == "\q"}}{{/if}}`, "invalid string literal"},
+		{"bad partial name", "{{> two words}}", "invalid partial name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLogic(tt.template, SyntaxModeMixedBrace)
+			if err == nil {
+				t.Fatalf("ParseLogic(%q): expected an error", tt.template)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseLogic(%q): got %q, want it to contain %q", tt.template, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogicTemplate_PartialsAndReferences(t *testing.T) {
+	tmpl, err := ParseLogic(
+		"{{#each orders as o}}{{> line}}{{/each}}{{#if tier == other}}{name?}{{/if}}{{> footer}}",
+		SyntaxModeMixedBrace,
+	)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	if got := tmpl.Partials(); !reflect.DeepEqual(got, []string{"footer", "line"}) {
+		t.Fatalf("Partials: got %v", got)
+	}
+	if _, err := tmpl.RenderLogic(Env{}, PreserveUnknown); err == nil ||
+		!strings.Contains(err.Error(), `partial "footer" is not defined`) {
+		t.Fatalf("RenderLogic: expected an undefined partial error, got %v", err)
+	}
+
+	line, err := ParseLogic("{o.id}{{#unless @last}},{{/unless}}", SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	footer, err := ParseLogic(" ({currency})", SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	tmpl.Link("line", line)
+	tmpl.Link("footer", footer)
+	got, err := tmpl.RenderLogic(Env{Data: map[string]any{
+		"orders":   []any{map[string]any{"id": "A1"}, map[string]any{"id": "B2"}},
+		"currency": "EUR",
+	}}, PreserveUnknown)
+	if err != nil {
+		t.Fatalf("RenderLogic: unexpected error: %v", err)
+	}
+	if got != "A1,B2 (EUR)" {
+		t.Fatalf("RenderLogic: got %q", got)
+	}
+
+	want := []Reference{
+		{Name: "currency"},
+		{Name: "name", Optional: true},
+		{Name: "orders"},
+		{Name: "other"},
+		{Name: "tier"},
+	}
+	if got := tmpl.References(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("References: got %+v, want %+v", got, want)
+	}
+
+	// A partial that includes itself stops at the depth limit.
+	loop, err := ParseLogic("{{> loop}}", SyntaxModeMixedBrace)
+	if err != nil {
+		t.Fatalf("ParseLogic: unexpected error: %v", err)
+	}
+	loop.Link("loop", loop)
+	if _, err := loop.RenderLogic(Env{}, PreserveUnknown); err == nil ||
+		!strings.Contains(err.Error(), "nested too deeply") {
+		t.Fatalf("RenderLogic: expected a depth error, got %v", err)
+	}
+}
@@ -56,6 +56,9 @@ type ResolveFunc func(name string) (string, bool, error)
 type Env struct {
 	Vars    map[string]string
 	Resolve ResolveFunc
+	// Data holds structured values for conditions and loops. It is only
+	// read by LogicTemplate and takes precedence over Vars.
+	Data map[string]any
 }
 
 // Option customizes promptcore parsing or rendering behavior.
@@ -521,6 +521,8 @@ func addUsage(total *model.Usage, next *model.Usage) *model.Usage {
 	total.PromptTokensDetails.CachedTokens += next.PromptTokensDetails.CachedTokens
 	total.PromptTokensDetails.CacheCreationTokens += next.PromptTokensDetails.CacheCreationTokens
 	total.PromptTokensDetails.CacheReadTokens += next.PromptTokensDetails.CacheReadTokens
+	total.PromptTokensDetails.CacheTokensExcluded = total.PromptTokensDetails.CacheTokensExcluded ||
+		next.PromptTokensDetails.CacheTokensExcluded
 	total.CompletionTokensDetails.ReasoningTokens += next.CompletionTokensDetails.ReasoningTokens
 	return total
 }
@@ -0,0 +1,265 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package cache provides an embedder wrapper that caches vectors so that
+// reloading knowledge, re-running evaluations or repeating memory searches do
+// not re-embed identical text.
+//
+// Vectors are keyed by model name, dimensions and a SHA-256 hash of the
+// normalized text, and are kept in a pluggable Store. Concurrent requests for
+// the same key are coalesced into a single call to the underlying embedder,
+// and batches are split to respect a configured maximum size.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/embedder"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+var (
+	_ embedder.Embedder      = (*Embedder)(nil)
+	_ embedder.BatchEmbedder = (*batchEmbedder)(nil)
+)
+
+// Embedder wraps an embedder.Embedder with a vector cache.
+type Embedder struct {
+	base embedder.Embedder
+	opts *options
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+// batchEmbedder is returned by New when the wrapped embedder implements
+// embedder.BatchEmbedder, so callers discover the batch capability exactly
+// when the underlying provider supports it.
+type batchEmbedder struct {
+	*Embedder
+	batch embedder.BatchEmbedder
+}
+
+// call is an in-flight embedding shared by every request for the same key.
+type call struct {
+	done   chan struct{}
+	vector []float64
+	err    error
+}
+
+// New wraps base with a vector cache.
+//
+// The returned embedder implements embedder.BatchEmbedder if and only if base
+// does. Cache read and write failures are logged and treated as misses, so a
+// broken store degrades to uncached embedding instead of failing the caller.
+func New(base embedder.Embedder, opts ...Option) embedder.Embedder {
+	e := &Embedder{
+		base:     base,
+		opts:     newOptions(opts...),
+		inflight: make(map[string]*call),
+	}
+	if b, ok := base.(embedder.BatchEmbedder); ok {
+		return &batchEmbedder{Embedder: e, batch: b}
+	}
+	return e
+}
+
+// Key returns the cache key of text for the given model name and dimensions.
+// It is exported so that callers can pre-populate or inspect a Store.
+func Key(modelName string, dimensions int, normalizedText string) string {
+	sum := sha256.Sum256([]byte(normalizedText))
+	return modelName + ":" + strconv.Itoa(dimensions) + ":" + hex.EncodeToString(sum[:])
+}
+
+// GetEmbedding implements embedder.Embedder.
+func (e *Embedder) GetEmbedding(ctx context.Context, text string) ([]float64, error) {
+	vector, _, err := e.GetEmbeddingWithUsage(ctx, text)
+	return vector, err
+}
+
+// GetEmbeddingWithUsage implements embedder.Embedder.
+//
+// Usage is only reported for requests that reached the underlying embedder;
+// cache hits and coalesced requests return nil usage, so summing usage over
+// calls counts each provider request once.
+func (e *Embedder) GetEmbeddingWithUsage(ctx context.Context, text string) ([]float64, map[string]any, error) {
+	key := e.key(text)
+	if vector, ok := e.lookup(ctx, key); ok {
+		return vector, nil, nil
+	}
+	c, owner := e.claim(key)
+	if !owner {
+		vector, err := e.wait(ctx, c)
+		return vector, nil, err
+	}
+	vector, usage, err := e.base.GetEmbeddingWithUsage(ctx, text)
+	if err == nil {
+		e.save(ctx, key, vector)
+	}
+	e.release(key, c, vector, err)
+	return cloneVector(vector), usage, err
+}
+
+// GetDimensions implements embedder.Embedder.
+func (e *Embedder) GetDimensions() int {
+	return e.base.GetDimensions()
+}
+
+// GetEmbeddings implements embedder.BatchEmbedder.
+//
+// Cached texts are answered from the store, duplicates within the batch and
+// texts already being embedded by a concurrent call are embedded once, and the
+// remaining misses are forwarded in sub-batches of at most the configured
+// maximum batch size. embeddings[i] always corresponds to texts[i].
+func (b *batchEmbedder) GetEmbeddings(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts must not be empty")
+	}
+	keys := make([]string, len(texts))
+	vectors := make(map[string][]float64, len(texts))
+	waiting := make(map[string]*call)
+	var ownedKeys []string
+	var ownedTexts []string
+	owned := make(map[string]*call)
+	// Every owned call must be released, whichever way this function returns,
+	// or concurrent waiters on the same key would block until cancelled.
+	defer func() {
+		for key, c := range owned {
+			b.release(key, c, nil, errors.New("batch embedding aborted"))
+		}
+	}()
+
+	for i, text := range texts {
+		key := b.key(text)
+		keys[i] = key
+		if _, seen := vectors[key]; seen {
+			continue
+		}
+		if _, seen := waiting[key]; seen {
+			continue
+		}
+		if _, seen := owned[key]; seen {
+			continue
+		}
+		if vector, ok := b.lookup(ctx, key); ok {
+			vectors[key] = vector
+			continue
+		}
+		c, owner := b.claim(key)
+		if !owner {
+			waiting[key] = c
+			continue
+		}
+		owned[key] = c
+		ownedKeys = append(ownedKeys, key)
+		ownedTexts = append(ownedTexts, text)
+	}
+
+	size := b.opts.maxBatchSize
+	if size <= 0 {
+		size = len(ownedTexts)
+	}
+	for start := 0; start < len(ownedTexts); start += size {
+		end := min(start+size, len(ownedTexts))
+		result, err := b.batch.GetEmbeddings(ctx, ownedTexts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(result) != end-start {
+			return nil, fmt.Errorf("embedder returned %d embeddings for %d texts", len(result), end-start)
+		}
+		for i, vector := range result {
+			key := ownedKeys[start+i]
+			b.save(ctx, key, vector)
+			vectors[key] = vector
+			b.release(key, owned[key], vector, nil)
+			delete(owned, key)
+		}
+	}
+
+	for key, c := range waiting {
+		vector, err := b.wait(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		vectors[key] = vector
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for i, key := range keys {
+		embeddings[i] = cloneVector(vectors[key])
+	}
+	return embeddings, nil
+}
+
+func (e *Embedder) key(text string) string {
+	return Key(e.opts.modelName, e.base.GetDimensions(), e.opts.normalize(text))
+}
+
+func (e *Embedder) lookup(ctx context.Context, key string) ([]float64, bool) {
+	vector, ok, err := e.opts.store.Get(ctx, key)
+	if err != nil {
+		log.WarnfContext(ctx, "embedding cache: get %s failed: %v", key, err)
+		return nil, false
+	}
+	if !ok || len(vector) == 0 {
+		return nil, false
+	}
+	return vector, true
+}
+
+// save stores a vector. Empty vectors signal API-level failures and are
+// never cached, so the next request retries the provider.
+func (e *Embedder) save(ctx context.Context, key string, vector []float64) {
+	if len(vector) == 0 {
+		return
+	}
+	if err := e.opts.store.Set(ctx, key, vector); err != nil {
+		log.WarnfContext(ctx, "embedding cache: set %s failed: %v", key, err)
+	}
+}
+
+// claim registers the caller as the owner of key, or returns the in-flight
+// call of another owner.
+func (e *Embedder) claim(key string) (*call, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if c, ok := e.inflight[key]; ok {
+		return c, false
+	}
+	c := &call{done: make(chan struct{})}
+	e.inflight[key] = c
+	return c, true
+}
+
+func (e *Embedder) release(key string, c *call, vector []float64, err error) {
+	c.vector, c.err = vector, err
+	e.mu.Lock()
+	delete(e.inflight, key)
+	e.mu.Unlock()
+	close(c.done)
+}
+
+// wait blocks until the owner of c finishes or ctx is done. The owner's
+// context governs the shared request, so an owner cancellation surfaces as an
+// error to every waiter.
+func (e *Embedder) wait(ctx context.Context, c *call) ([]float64, error) {
+	select {
+	case <-c.done:
+		return cloneVector(c.vector), c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
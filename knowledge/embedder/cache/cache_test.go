@@ -0,0 +1,244 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/embedder"
+)
+
+type fakeEmbedder struct {
+	calls   atomic.Int32
+	batches [][]string
+	mu      sync.Mutex
+	gate    chan struct{}
+	err     error
+}
+
+func (f *fakeEmbedder) vector(text string) []float64 {
+	return []float64{float64(len(text)), 1}
+}
+
+func (f *fakeEmbedder) GetEmbedding(ctx context.Context, text string) ([]float64, error) {
+	v, _, err := f.GetEmbeddingWithUsage(ctx, text)
+	return v, err
+}
+
+func (f *fakeEmbedder) GetEmbeddingWithUsage(_ context.Context, text string) ([]float64, map[string]any, error) {
+	f.calls.Add(1)
+	if f.gate != nil {
+		<-f.gate
+	}
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	if text == "empty" {
+		return nil, nil, nil
+	}
+	return f.vector(text), map[string]any{"total_tokens": 1}, nil
+}
+
+func (f *fakeEmbedder) GetDimensions() int { return 2 }
+
+type fakeBatchEmbedder struct {
+	fakeEmbedder
+}
+
+func (f *fakeBatchEmbedder) GetEmbeddings(_ context.Context, texts []string) ([][]float64, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, append([]string(nil), texts...))
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([][]float64, len(texts))
+	for i, text := range texts {
+		out[i] = f.vector(text)
+	}
+	return out, nil
+}
+
+func TestNew_BatchCapabilityFollowsBase(t *testing.T) {
+	_, ok := New(&fakeEmbedder{}).(embedder.BatchEmbedder)
+	assert.False(t, ok)
+	_, ok = New(&fakeBatchEmbedder{}).(embedder.BatchEmbedder)
+	assert.True(t, ok)
+}
+
+func TestEmbedder_CachesByNormalizedText(t *testing.T) {
+	base := &fakeEmbedder{}
+	e := New(base, WithModelName("m"))
+	ctx := context.Background()
+
+	v1, usage, err := e.GetEmbeddingWithUsage(ctx, "hello  world")
+	require.NoError(t, err)
+	assert.NotNil(t, usage)
+	v2, usage, err := e.GetEmbeddingWithUsage(ctx, " hello\nworld ")
+	require.NoError(t, err)
+	assert.Nil(t, usage)
+	assert.Equal(t, v1, v2)
+	assert.Equal(t, int32(1), base.calls.Load())
+	assert.Equal(t, 2, e.GetDimensions())
+
+	// Returned vectors are copies and cannot corrupt the cache.
+	v2[0] = -1
+	v3, err := e.GetEmbedding(ctx, "hello world")
+	require.NoError(t, err)
+	assert.Equal(t, v1, v3)
+}
+
+func TestEmbedder_ModelNameSeparatesKeys(t *testing.T) {
+	store := NewMemoryStore(10)
+	base := &fakeEmbedder{}
+	ctx := context.Background()
+	_, err := New(base, WithStore(store), WithModelName("a")).GetEmbedding(ctx, "x")
+	require.NoError(t, err)
+	_, err = New(base, WithStore(store), WithModelName("b")).GetEmbedding(ctx, "x")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), base.calls.Load())
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestEmbedder_DoesNotCacheFailures(t *testing.T) {
+	ctx := context.Background()
+	base := &fakeEmbedder{}
+	e := New(base)
+	v, err := e.GetEmbedding(ctx, "empty")
+	require.NoError(t, err)
+	assert.Empty(t, v)
+	_, err = e.GetEmbedding(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), base.calls.Load())
+
+	base.err = errors.New("boom")
+	_, err = e.GetEmbedding(ctx, "x")
+	require.Error(t, err)
+	base.err = nil
+	_, err = e.GetEmbedding(ctx, "x")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), base.calls.Load())
+}
+
+func TestEmbedder_CoalescesConcurrentRequests(t *testing.T) {
+	base := &fakeEmbedder{gate: make(chan struct{})}
+	e := New(base)
+	ctx := context.Background()
+
+	const n = 8
+	var wg sync.WaitGroup
+	results := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := e.GetEmbedding(ctx, "same")
+			assert.NoError(t, err)
+			results[i] = v
+		}(i)
+	}
+	require.Eventually(t, func() bool { return base.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(base.gate)
+	wg.Wait()
+	assert.Equal(t, int32(1), base.calls.Load())
+	for _, v := range results {
+		assert.Equal(t, []float64{4, 1}, v)
+	}
+}
+
+func TestEmbedder_WaiterHonoursContext(t *testing.T) {
+	base := &fakeEmbedder{gate: make(chan struct{})}
+	e := New(base)
+	go func() { _, _ = e.GetEmbedding(context.Background(), "slow") }()
+	require.Eventually(t, func() bool { return base.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := e.GetEmbedding(ctx, "slow")
+	assert.ErrorIs(t, err, context.Canceled)
+	close(base.gate)
+}
+
+func TestBatchEmbedder_SplitsAndDeduplicates(t *testing.T) {
+	base := &fakeBatchEmbedder{}
+	e := New(base, WithMaxBatchSize(2)).(embedder.BatchEmbedder)
+	ctx := context.Background()
+
+	_, err := e.GetEmbedding(ctx, "cached")
+	require.NoError(t, err)
+
+	texts := []string{"a", "bb", "a", "cached", "ccc", "dddd", "bb "}
+	got, err := e.GetEmbeddings(ctx, texts)
+	require.NoError(t, err)
+	require.Len(t, got, len(texts))
+	for i, text := range texts {
+		assert.Equal(t, base.vector(NormalizeText(text)), got[i], text)
+	}
+	assert.Equal(t, [][]string{{"a", "bb"}, {"ccc", "dddd"}}, base.batches)
+
+	base.batches = nil
+	_, err = e.GetEmbeddings(ctx, []string{"a", "dddd"})
+	require.NoError(t, err)
+	assert.Empty(t, base.batches)
+}
+
+func TestBatchEmbedder_Errors(t *testing.T) {
+	base := &fakeBatchEmbedder{}
+	e := New(base).(embedder.BatchEmbedder)
+	ctx := context.Background()
+
+	_, err := e.GetEmbeddings(ctx, nil)
+	require.Error(t, err)
+
+	base.err = errors.New("boom")
+	_, err = e.GetEmbeddings(ctx, []string{"a", "b"})
+	require.Error(t, err)
+
+	// A failed batch must release its keys so later calls are not blocked.
+	base.err = nil
+	got, err := e.GetEmbeddings(ctx, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1, 1}}, got)
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) ([]float64, bool, error) {
+	return nil, false, errors.New("get failed")
+}
+
+func (failingStore) Set(context.Context, string, []float64) error {
+	return errors.New("set failed")
+}
+
+func TestEmbedder_StoreFailuresDegradeToMisses(t *testing.T) {
+	base := &fakeEmbedder{}
+	e := New(base, WithStore(failingStore{}))
+	v, err := e.GetEmbedding(context.Background(), "x")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 1}, v)
+}
+
+func TestKeyAndNormalizeText(t *testing.T) {
+	assert.Equal(t, "a b", NormalizeText("  a \t\n b  "))
+	assert.Equal(t, "café", NormalizeText("café"))
+	assert.NotEqual(t, Key("m", 1, "x"), Key("m", 2, "x"))
+	assert.NotEqual(t, Key("m", 1, "x"), Key("n", 1, "x"))
+	assert.Equal(t, Key("m", 1, "x"), Key("m", 1, "x"))
+}
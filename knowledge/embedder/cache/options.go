@@ -0,0 +1,104 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package cache
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultMemoryCapacity is the number of vectors kept by the default store.
+const defaultMemoryCapacity = 10000
+
+// Option configures the caching embedder.
+type Option func(*options)
+
+type options struct {
+	store        Store
+	modelName    string
+	normalize    func(string) string
+	maxBatchSize int
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		normalize: NormalizeText,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.store == nil {
+		o.store = NewMemoryStore(defaultMemoryCapacity)
+	}
+	if o.normalize == nil {
+		o.normalize = NormalizeText
+	}
+	return o
+}
+
+// WithStore sets the store that holds cached vectors.
+// The default is an in-memory LRU store with 10000 entries.
+func WithStore(store Store) Option {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// WithModelName sets the model name that is part of every cache key.
+// Embedders do not expose their model, so set this whenever several models
+// share one store; vectors of different models are never interchangeable.
+func WithModelName(name string) Option {
+	return func(o *options) {
+		o.modelName = name
+	}
+}
+
+// WithNormalizer replaces the function applied to a text before it is hashed
+// into a cache key. The text sent to the underlying embedder is never
+// modified. The default is NormalizeText.
+func WithNormalizer(normalize func(string) string) Option {
+	return func(o *options) {
+		o.normalize = normalize
+	}
+}
+
+// WithMaxBatchSize sets the largest number of texts forwarded to the
+// underlying embedder in one GetEmbeddings call. Larger batches are split
+// into consecutive sub-batches. Zero or a negative value forwards every cache
+// miss of a call in one batch.
+func WithMaxBatchSize(size int) Option {
+	return func(o *options) {
+		o.maxBatchSize = size
+	}
+}
+
+// NormalizeText is the default key normalizer. It applies Unicode NFC,
+// trims leading and trailing white space and collapses internal white space
+// runs into a single space, so texts that differ only in layout share a key.
+func NormalizeText(text string) string {
+	text = norm.NFC.String(text)
+	var b strings.Builder
+	b.Grow(len(text))
+	space := false
+	for _, r := range strings.TrimSpace(text) {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+module trpc.group/trpc-go/trpc-agent-go/knowledge/embedder/cache/redis
+
+go 1.21
+
+replace (
+	trpc.group/trpc-go/trpc-agent-go => ../../../../
+	trpc.group/trpc-go/trpc-agent-go/storage/redis => ../../../../storage/redis
+)
+
+require (
+	github.com/alicebob/miniredis/v2 v2.35.0
+	github.com/redis/go-redis/v9 v9.11.0
+	github.com/stretchr/testify v1.11.1
+	trpc.group/trpc-go/trpc-agent-go v0.6.0
+	trpc.group/trpc-go/trpc-agent-go/storage/redis v0.6.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
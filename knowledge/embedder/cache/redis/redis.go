@@ -0,0 +1,138 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package redis provides a Redis-backed store for the embedding cache, so
+// several processes can share cached vectors.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/embedder/cache"
+	storage "trpc.group/trpc-go/trpc-agent-go/storage/redis"
+)
+
+const defaultKeyPrefix = "embcache:"
+
+var _ cache.Store = (*Store)(nil)
+
+// Options is the options for the redis embedding cache store.
+type Options struct {
+	url          string
+	instanceName string
+	extraOptions []any
+	keyPrefix    string
+	ttl          time.Duration
+}
+
+// Option is the option for the redis embedding cache store.
+type Option func(*Options)
+
+// WithRedisClientURL creates a redis client from URL and sets it to the store.
+func WithRedisClientURL(url string) Option {
+	return func(opts *Options) {
+		opts.url = url
+	}
+}
+
+// WithRedisInstance uses a redis instance from storage.
+// Note: WithRedisClientURL has higher priority than WithRedisInstance.
+// If both are specified, WithRedisClientURL will be used.
+func WithRedisInstance(instanceName string) Option {
+	return func(opts *Options) {
+		opts.instanceName = instanceName
+	}
+}
+
+// WithExtraOptions sets the extra options passed to the redis client builder.
+func WithExtraOptions(extraOptions ...any) Option {
+	return func(opts *Options) {
+		opts.extraOptions = append(opts.extraOptions, extraOptions...)
+	}
+}
+
+// WithKeyPrefix sets the prefix of every redis key. The default is "embcache:".
+func WithKeyPrefix(prefix string) Option {
+	return func(opts *Options) {
+		opts.keyPrefix = prefix
+	}
+}
+
+// WithTTL sets the expiration of cached vectors. Zero, the default, keeps
+// vectors until redis evicts them.
+func WithTTL(ttl time.Duration) Option {
+	return func(opts *Options) {
+		opts.ttl = ttl
+	}
+}
+
+// Store is a cache.Store persisting vectors in redis.
+type Store struct {
+	opts   Options
+	client redis.UniversalClient
+}
+
+// NewStore creates a redis-backed embedding cache store.
+func NewStore(options ...Option) (*Store, error) {
+	opts := Options{keyPrefix: defaultKeyPrefix}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	builderOpts := []storage.ClientBuilderOpt{
+		storage.WithClientBuilderURL(opts.url),
+		storage.WithExtraOptions(opts.extraOptions...),
+	}
+	// if instance name set, and url not set, use instance name to create redis client
+	if opts.url == "" && opts.instanceName != "" {
+		var ok bool
+		if builderOpts, ok = storage.GetRedisInstance(opts.instanceName); !ok {
+			return nil, fmt.Errorf("redis instance %s not found", opts.instanceName)
+		}
+	}
+	client, err := storage.GetClientBuilder()(builderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create redis client from url failed: %w", err)
+	}
+	return &Store{opts: opts, client: client}, nil
+}
+
+// Get implements cache.Store.
+func (s *Store) Get(ctx context.Context, key string) ([]float64, bool, error) {
+	data, err := s.client.Get(ctx, s.opts.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis get: %w", err)
+	}
+	vector, err := cache.UnmarshalVector(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return vector, true, nil
+}
+
+// Set implements cache.Store.
+func (s *Store) Set(ctx context.Context, key string, vector []float64) error {
+	if err := s.client.Set(ctx, s.opts.keyPrefix+key, cache.MarshalVector(vector), s.opts.ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying redis client.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,55 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storage "trpc.group/trpc-go/trpc-agent-go/storage/redis"
+)
+
+func TestStore_SetGet(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s, err := NewStore(WithRedisClientURL("redis://"+mr.Addr()), WithTTL(time.Minute))
+	require.NoError(t, err)
+	defer s.Close()
+	ctx := context.Background()
+
+	_, ok, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Set(ctx, "k", []float64{1, 2.5}))
+	v, ok, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1, 2.5}, v)
+	assert.True(t, mr.Exists(defaultKeyPrefix+"k"))
+	assert.Equal(t, time.Minute, mr.TTL(defaultKeyPrefix+"k"))
+}
+
+func TestNewStore_Instance(t *testing.T) {
+	mr := miniredis.RunT(t)
+	storage.RegisterRedisInstance("embcache-test", storage.WithClientBuilderURL("redis://"+mr.Addr()))
+	s, err := NewStore(WithRedisInstance("embcache-test"), WithKeyPrefix("p:"))
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Set(context.Background(), "k", []float64{1}))
+	assert.True(t, mr.Exists("p:k"))
+
+	_, err = NewStore(WithRedisInstance("missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package sqlite provides a SQLite-backed store for the embedding cache, so
+// cached vectors survive process restarts in a single local file.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/embedder/cache"
+)
+
+const defaultTableName = "embedding_cache"
+
+var (
+	_ cache.Store = (*Store)(nil)
+
+	tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Option configures the SQLite store.
+type Option func(*Store)
+
+// WithTableName sets the table that holds cached vectors.
+// The default is "embedding_cache".
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.table = name
+	}
+}
+
+// Store is a cache.Store persisting vectors in a SQLite table.
+// It expects an initialized *sql.DB and creates the table if needed.
+type Store struct {
+	db    *sql.DB
+	table string
+}
+
+// NewStore creates a store using the provided DB.
+// The DB must use a SQLite driver.
+func NewStore(db *sql.DB, opts ...Option) (*Store, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	s := &Store{db: db, table: defaultTableName}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !tableNamePattern.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + s.table + " (" +
+		"cache_key TEXT PRIMARY KEY, " +
+		"vector BLOB NOT NULL, " +
+		"updated_at INTEGER NOT NULL" +
+		")"
+	if _, err := db.Exec(create); err != nil {
+		return nil, fmt.Errorf("create %s table: %w", s.table, err)
+	}
+	return s, nil
+}
+
+// Get implements cache.Store.
+func (s *Store) Get(ctx context.Context, key string) ([]float64, bool, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT vector FROM "+s.table+" WHERE cache_key = ?", key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("select vector: %w", err)
+	}
+	vector, err := cache.UnmarshalVector(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return vector, true, nil
+}
+
+// Set implements cache.Store.
+func (s *Store) Set(ctx context.Context, key string, vector []float64) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO "+s.table+" (cache_key, vector, updated_at) VALUES (?, ?, ?)",
+		key, cache.MarshalVector(vector), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("insert vector: %w", err)
+	}
+	return nil
+}
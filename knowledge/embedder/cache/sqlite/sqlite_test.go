@@ -0,0 +1,60 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // Import SQLite driver.
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStore_SetGet(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewStore(openDB(t))
+	require.NoError(t, err)
+
+	_, ok, err := s.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Set(ctx, "k", []float64{1.5, -2}))
+	v, ok, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1.5, -2}, v)
+
+	require.NoError(t, s.Set(ctx, "k", []float64{3}))
+	v, _, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3}, v)
+}
+
+func TestNewStore_Validation(t *testing.T) {
+	_, err := NewStore(nil)
+	assert.Error(t, err)
+	_, err = NewStore(openDB(t), WithTableName("bad name;"))
+	assert.Error(t, err)
+	s, err := NewStore(openDB(t), WithTableName("vectors"))
+	require.NoError(t, err)
+	assert.Equal(t, "vectors", s.table)
+}
@@ -0,0 +1,128 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Store persists embedding vectors by cache key.
+//
+// Keys are produced by the caching embedder and already encode the model
+// name, the dimensions and a hash of the normalized text, so a store only
+// needs exact-match lookups. Implementations must be safe for concurrent use
+// and must not retain or modify the slices they are given or return.
+type Store interface {
+	// Get returns the vector stored under key. The boolean reports whether
+	// the key was found; a miss is not an error.
+	Get(ctx context.Context, key string) ([]float64, bool, error)
+	// Set stores vector under key, replacing any previous value.
+	Set(ctx context.Context, key string, vector []float64) error
+}
+
+// MemoryStore is an in-process Store that evicts the least recently used
+// vector once it holds more than its capacity.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key    string
+	vector []float64
+}
+
+// NewMemoryStore creates an LRU store holding at most capacity vectors.
+// A capacity of zero or less uses the default of 10000.
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+	return &MemoryStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]float64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	s.ll.MoveToFront(elem)
+	return cloneVector(elem.Value.(*memoryEntry).vector), true, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(_ context.Context, key string, vector []float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		elem.Value.(*memoryEntry).vector = cloneVector(vector)
+		s.ll.MoveToFront(elem)
+		return nil
+	}
+	s.items[key] = s.ll.PushFront(&memoryEntry{key: key, vector: cloneVector(vector)})
+	for s.ll.Len() > s.capacity {
+		oldest := s.ll.Back()
+		s.ll.Remove(oldest)
+		delete(s.items, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached vectors.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+// MarshalVector encodes a vector as little-endian IEEE 754 float64 values.
+// Persistent stores use it so that cached vectors round-trip bit for bit.
+func MarshalVector(vector []float64) []byte {
+	buf := make([]byte, 8*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+	}
+	return buf
+}
+
+// UnmarshalVector decodes a vector produced by MarshalVector.
+func UnmarshalVector(data []byte) ([]float64, error) {
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("invalid vector encoding: %d bytes is not a multiple of 8", len(data))
+	}
+	vector := make([]float64, len(data)/8)
+	for i := range vector {
+		vector[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return vector, nil
+}
+
+func cloneVector(vector []float64) []float64 {
+	if vector == nil {
+		return nil
+	}
+	out := make([]float64, len(vector))
+	copy(out, vector)
+	return out
+}
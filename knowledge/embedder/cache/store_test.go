@@ -0,0 +1,56 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package cache
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+	require.NoError(t, s.Set(ctx, "a", []float64{1}))
+	require.NoError(t, s.Set(ctx, "b", []float64{2}))
+	_, ok, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, s.Set(ctx, "c", []float64{3}))
+
+	_, ok, _ = s.Get(ctx, "b")
+	assert.False(t, ok)
+	v, ok, _ := s.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1}, v)
+	assert.Equal(t, 2, s.Len())
+
+	require.NoError(t, s.Set(ctx, "a", []float64{4}))
+	v, _, _ = s.Get(ctx, "a")
+	assert.Equal(t, []float64{4}, v)
+	assert.Equal(t, 2, s.Len())
+}
+
+func TestMemoryStore_DefaultCapacity(t *testing.T) {
+	assert.Equal(t, defaultMemoryCapacity, NewMemoryStore(0).capacity)
+}
+
+func TestMarshalVector_RoundTrip(t *testing.T) {
+	in := []float64{0, -1.5, math.Pi, math.SmallestNonzeroFloat64}
+	out, err := UnmarshalVector(MarshalVector(in))
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+
+	_, err = UnmarshalVector([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
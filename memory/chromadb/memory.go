@@ -336,9 +336,13 @@ func (svc *Service) UpdateMemory(
 	if err != nil {
 		return err
 	}
-	embedding, err := svc.embed(ctx, content)
-	if err != nil {
-		return err
+	// A metadata-only update embeds the content once the stored memory
+	// shows that it changed.
+	var embedding []float32
+	if !memory.ResolveMetadataOnlyUpdate(opts) {
+		if embedding, err = svc.embed(ctx, content); err != nil {
+			return err
+		}
 	}
 	scope := recordScope{appName: memoryKey.AppName, userID: memoryKey.UserID}
 	lock := svc.writeLock(scope)
@@ -346,7 +350,7 @@ func (svc *Service) UpdateMemory(
 		return err
 	}
 	defer lock.release()
-	effectiveID, err := svc.applyUpdate(ctx, command, embedding, token)
+	effectiveID, err := svc.applyUpdate(ctx, command, embedding, token, opts)
 	if err != nil {
 		return err
 	}
@@ -362,6 +366,7 @@ func (svc *Service) applyUpdate(
 	command updateCommand,
 	embedding []float32,
 	token string,
+	opts []memory.UpdateOption,
 ) (string, error) {
 	scope := recordScope{appName: command.key.AppName, userID: command.key.UserID}
 	old, err := svc.fetchRecordByID(ctx, command.key.MemoryID, activeScopeWhere(scope))
@@ -372,7 +377,11 @@ func (svc *Service) applyUpdate(
 		return svc.resolveCompletedUpdate(ctx, scope, command.key.MemoryID, token)
 	}
 
+	keep := imemory.KeepsContent(old.entry, command.content, command.topics, opts)
 	now := time.Now().UTC()
+	if keep {
+		now = old.entry.UpdatedAt
+	}
 	newID := imemory.ApplyMemoryUpdate(
 		old.entry,
 		command.key.AppName,
@@ -382,6 +391,12 @@ func (svc *Service) applyUpdate(
 		command.metadata,
 		now,
 	)
+	if embedding == nil && (!keep || newID != command.key.MemoryID) {
+		if embedding, err = svc.embed(ctx, command.content); err != nil {
+			return "", err
+		}
+	}
+	// A nil embedding leaves the stored one in place.
 	old.embedding = embedding
 	if newID == command.key.MemoryID {
 		if err := svc.updateActiveAndVerify(ctx, scope, old); err != nil {
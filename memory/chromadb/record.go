@@ -36,6 +36,7 @@ const (
 	metadataEventTimeKey     = "event_time_ns"
 	metadataParticipantsKey  = "participants"
 	metadataLocationKey      = "location"
+	metadataConsolidationKey = "consolidation"
 	metadataCreatedAtKey     = "created_at_ns"
 	metadataUpdatedAtKey     = "updated_at_ns"
 	metadataDeletedAtKey     = "deleted_at_ns"
@@ -141,6 +142,9 @@ func decodeStoredRecord(id string, document *string, metadata map[string]any) (*
 		Participants: decoded.participants,
 		Location:     decoded.location,
 	}
+	if err := imemory.UnmarshalConsolidation(mem, decoded.consolidation); err != nil {
+		return nil, fmt.Errorf("decode memory %s: metadata %s: %w", id, metadataConsolidationKey, err)
+	}
 	entry := &memory.Entry{
 		ID:        id,
 		AppName:   decoded.appName,
@@ -189,18 +193,19 @@ func decodeGetResponse(response *getRecordsResponse) ([]*storedRecord, error) {
 }
 
 type decodedMetadata struct {
-	appName      string
-	userID       string
-	kind         memory.Kind
-	topics       []string
-	eventTime    *time.Time
-	participants []string
-	location     string
-	createdAt    time.Time
-	updatedAt    time.Time
-	deletedAtNS  int64
-	updateToken  string
-	replacesID   string
+	appName       string
+	userID        string
+	kind          memory.Kind
+	topics        []string
+	eventTime     *time.Time
+	participants  []string
+	location      string
+	consolidation string
+	createdAt     time.Time
+	updatedAt     time.Time
+	deletedAtNS   int64
+	updateToken   string
+	replacesID    string
 }
 
 // decodeRecordMetadata validates required fields and decodes optional memory attributes.
@@ -237,6 +242,10 @@ func decodeRecordMetadata(metadata map[string]any) (*decodedMetadata, error) {
 	if err != nil {
 		return nil, err
 	}
+	consolidation, err := optionalString(metadata, metadataConsolidationKey)
+	if err != nil {
+		return nil, err
+	}
 	updateToken, err := optionalString(metadata, metadataUpdateTokenKey)
 	if err != nil {
 		return nil, err
@@ -246,18 +255,19 @@ func decodeRecordMetadata(metadata map[string]any) (*decodedMetadata, error) {
 		return nil, err
 	}
 	return &decodedMetadata{
-		appName:      appName,
-		userID:       userID,
-		kind:         memory.Kind(kindValue),
-		topics:       topics,
-		eventTime:    eventTime,
-		participants: participants,
-		location:     location,
-		createdAt:    createdAt,
-		updatedAt:    updatedAt,
-		deletedAtNS:  deletedAtNS,
-		updateToken:  updateToken,
-		replacesID:   replacesID,
+		appName:       appName,
+		userID:        userID,
+		kind:          memory.Kind(kindValue),
+		topics:        topics,
+		eventTime:     eventTime,
+		participants:  participants,
+		location:      location,
+		consolidation: consolidation,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+		deletedAtNS:   deletedAtNS,
+		updateToken:   updateToken,
+		replacesID:    replacesID,
 	}, nil
 }
 
@@ -314,14 +324,18 @@ func addRequest(record *storedRecord) addRecordsRequest {
 }
 
 // updateRequest encodes one stored record with explicit optional-field clearing.
+// A record without an embedding keeps the stored one.
 func updateRequest(record *storedRecord) updateRecordsRequest {
 	document := record.entry.Memory.Memory
-	return updateRecordsRequest{
-		IDs:        []string{record.entry.ID},
-		Embeddings: [][]float32{record.embedding},
-		Documents:  []*string{&document},
-		Metadatas:  []map[string]any{updateMetadata(record)},
+	request := updateRecordsRequest{
+		IDs:       []string{record.entry.ID},
+		Documents: []*string{&document},
+		Metadatas: []map[string]any{updateMetadata(record)},
+	}
+	if record.embedding != nil {
+		request.Embeddings = [][]float32{record.embedding}
 	}
+	return request
 }
 
 // addMetadata encodes a new record while omitting absent optional fields.
@@ -340,6 +354,9 @@ func addMetadata(record *storedRecord) map[string]any {
 	if mem.Location != "" {
 		metadata[metadataLocationKey] = mem.Location
 	}
+	if consolidation := consolidationMetadata(mem); consolidation != "" {
+		metadata[metadataConsolidationKey] = consolidation
+	}
 	if record.updateToken != "" {
 		metadata[metadataUpdateTokenKey] = record.updateToken
 	}
@@ -368,6 +385,8 @@ func updateMetadata(record *storedRecord) map[string]any {
 		len(mem.Participants) > 0,
 	)
 	setNullableMetadata(metadata, metadataLocationKey, mem.Location, mem.Location != "")
+	consolidation := consolidationMetadata(mem)
+	setNullableMetadata(metadata, metadataConsolidationKey, consolidation, consolidation != "")
 	setNullableMetadata(metadata, metadataUpdateTokenKey, record.updateToken, record.updateToken != "")
 	setNullableMetadata(metadata, metadataReplacesIDKey, record.replacesID, record.replacesID != "")
 	return metadata
@@ -389,6 +408,13 @@ func requiredMetadata(record *storedRecord) map[string]any {
 	}
 }
 
+// consolidationMetadata encodes consolidation bookkeeping as one JSON string because
+// Chroma metadata values are limited to scalars and flat arrays.
+func consolidationMetadata(mem *memory.Memory) string {
+	encoded, _ := imemory.MarshalConsolidation(mem)
+	return encoded
+}
+
 // setNullableMetadata writes null for an absent value so Chroma removes the existing key.
 func setNullableMetadata(metadata map[string]any, key string, value any, present bool) {
 	if present {
@@ -53,6 +53,40 @@ func TestRecordRoundTripPreservesMetadata(t *testing.T) {
 	assert.Equal(t, eventTime, *decoded.entry.Memory.EventTime)
 }
 
+func TestRecordRoundTripPreservesConsolidationFields(t *testing.T) {
+	now := time.Unix(0, 1730400123456789012).UTC()
+	record := newAddRecord(
+		recordScope{appName: "app", userID: "user"},
+		"Alice likes tea",
+		nil,
+		&memory.Metadata{
+			Provenance:     []memory.Provenance{{SessionID: "s1", EventID: "e1", Time: now}},
+			Supersedes:     []memory.Supersession{{MemoryID: "old", Memory: "Alice likes coffee", Time: now}},
+			AccessCount:    2,
+			LastAccessedAt: &now,
+		},
+		now,
+	)
+	metadata := addMetadata(record)
+	require.IsType(t, "", metadata[metadataConsolidationKey])
+	document := record.entry.Memory.Memory
+
+	decoded, err := decodeStoredRecord(record.entry.ID, &document, metadata)
+	require.NoError(t, err)
+
+	mem := decoded.entry.Memory
+	assert.Equal(t, record.entry.Memory.Provenance, mem.Provenance)
+	assert.Equal(t, record.entry.Memory.Supersedes, mem.Supersedes)
+	assert.Equal(t, 2, mem.AccessCount)
+	require.NotNil(t, mem.LastAccessedAt)
+	assert.True(t, now.Equal(*mem.LastAccessedAt))
+
+	metadata[metadataConsolidationKey] = "{"
+	_, err = decodeStoredRecord(record.entry.ID, &document, metadata)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), metadataConsolidationKey)
+}
+
 func TestRecordRoundTripPreservesNanosecondBoundaryEventTimes(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -107,6 +141,7 @@ func TestRecordCodecUpdateClearsOptionalMetadataWithNull(t *testing.T) {
 		metadataEventTimeKey,
 		metadataParticipantsKey,
 		metadataLocationKey,
+		metadataConsolidationKey,
 		metadataUpdateTokenKey,
 		metadataReplacesIDKey,
 	} {
@@ -0,0 +1,141 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package consolidation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+)
+
+// defaultMaxPendingAccesses bounds the memories an AccessTracker holds
+// accesses for.
+const defaultMaxPendingAccesses = 100000
+
+// AccessTracker counts memory retrievals between consolidation runs.
+//
+// Accesses are kept in process and saved on the memories as AccessCount and
+// LastAccessedAt by the next Consolidate call for the user, so writes to the
+// backend are batched instead of happening on every search.
+type AccessTracker struct {
+	maxPending int
+
+	mu      sync.Mutex
+	pending map[memory.UserKey]map[string]accessStat
+	size    int // number of memories in pending
+}
+
+type accessStat struct {
+	count int
+	last  time.Time
+}
+
+// TrackerOption configures an AccessTracker.
+type TrackerOption func(*AccessTracker)
+
+// WithMaxPendingAccesses caps the number of memories the tracker holds
+// accesses for, 100000 by default. Once the cap is reached, accesses to
+// memories not yet tracked are dropped until a consolidation run takes the
+// accesses of a user.
+func WithMaxPendingAccesses(n int) TrackerOption {
+	return func(t *AccessTracker) {
+		if n > 0 {
+			t.maxPending = n
+		}
+	}
+}
+
+// NewAccessTracker creates an empty AccessTracker.
+func NewAccessTracker(opts ...TrackerOption) *AccessTracker {
+	t := &AccessTracker{
+		maxPending: defaultMaxPendingAccesses,
+		pending:    make(map[memory.UserKey]map[string]accessStat),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Record counts one access to the memory identified by key at time at.
+func (t *AccessTracker) Record(key memory.Key, at time.Time) {
+	t.add(memory.UserKey{AppName: key.AppName, UserID: key.UserID},
+		key.MemoryID, accessStat{count: 1, last: at})
+}
+
+func (t *AccessTracker) add(userKey memory.UserKey, memoryID string, stat accessStat) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.pending[userKey]
+	cur, ok := stats[memoryID]
+	if !ok {
+		if t.size >= t.maxPending {
+			return
+		}
+		if stats == nil {
+			stats = make(map[string]accessStat)
+			t.pending[userKey] = stats
+		}
+		t.size++
+	}
+	cur.count += stat.count
+	if stat.last.After(cur.last) {
+		cur.last = stat.last
+	}
+	stats[memoryID] = cur
+}
+
+// take removes and returns the pending accesses of a user.
+func (t *AccessTracker) take(userKey memory.UserKey) map[string]accessStat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.pending[userKey]
+	delete(t.pending, userKey)
+	t.size -= len(stats)
+	return stats
+}
+
+// TrackAccess wraps service so that every memory returned by SearchMemories
+// is recorded in tracker. Reads without a query, such as memory preload, are
+// not counted because they return memories regardless of relevance.
+func TrackAccess(service memory.Service, tracker *AccessTracker) memory.Service {
+	return &trackedService{Service: service, tracker: tracker}
+}
+
+type trackedService struct {
+	memory.Service
+	tracker *AccessTracker
+}
+
+// SearchMemories implements memory.Reader.
+func (s *trackedService) SearchMemories(ctx context.Context, userKey memory.UserKey,
+	query string, opts ...memory.SearchOption) ([]*memory.Entry, error) {
+	entries, err := s.Service.SearchMemories(ctx, userKey, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		key := memory.Key{AppName: e.AppName, UserID: e.UserID, MemoryID: e.ID}
+		if key.AppName == "" {
+			key.AppName = userKey.AppName
+		}
+		if key.UserID == "" {
+			key.UserID = userKey.UserID
+		}
+		s.tracker.Record(key, now)
+	}
+	return entries, nil
+}
@@ -0,0 +1,503 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package consolidation keeps long-lived memory stores healthy.
+//
+// A Consolidator reads all memories of a user, clusters similar ones and asks
+// a Judge to merge duplicates or let newer memories supersede contradicted
+// ones. Merged and superseded memories are recorded in the Supersedes history
+// of the surviving memory together with their provenance. Access statistics
+// collected by TrackAccess are saved on the memories, and memories whose
+// importance has decayed below a threshold can be archived.
+//
+// Consolidation works on any memory.Service. Backends that store the memory
+// record as a whole keep provenance, history and access statistics; backends
+// with fixed columns only keep the consolidated content.
+package consolidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+	imemory "trpc.group/trpc-go/trpc-agent-go/memory/internal/memory"
+)
+
+// Archiver keeps memories that decayed below the archive threshold before
+// they are deleted from the memory service.
+type Archiver interface {
+	Archive(ctx context.Context, userKey memory.UserKey, entries []*memory.Entry) error
+}
+
+// ArchiverFunc adapts a function to the Archiver interface.
+type ArchiverFunc func(ctx context.Context, userKey memory.UserKey, entries []*memory.Entry) error
+
+// Archive implements Archiver.
+func (f ArchiverFunc) Archive(ctx context.Context, userKey memory.UserKey, entries []*memory.Entry) error {
+	return f(ctx, userKey, entries)
+}
+
+// Report summarizes one consolidation run.
+type Report struct {
+	Scanned    int // Scanned is the number of memories read.
+	Clusters   int // Clusters is the number of clusters sent to the judge.
+	Merged     int // Merged is the number of memories merged into another one.
+	Superseded int // Superseded is the number of memories replaced by a newer one.
+	Accessed   int // Accessed is the number of memories whose access statistics were saved.
+	Archived   int // Archived is the number of decayed memories archived.
+}
+
+// Consolidator merges, supersedes and archives the memories of a user.
+type Consolidator struct {
+	service memory.Service
+	opts    *options
+
+	startOnce sync.Once
+	mu        sync.Mutex
+	closed    bool
+	queue     chan memory.UserKey
+	queued    map[memory.UserKey]struct{}
+	wg        sync.WaitGroup
+}
+
+// New creates a Consolidator for the memories of service.
+func New(service memory.Service, opts ...Option) *Consolidator {
+	return &Consolidator{
+		service: service,
+		opts:    newOptions(opts...),
+		queued:  make(map[memory.UserKey]struct{}),
+	}
+}
+
+// Consolidate runs one consolidation pass over the memories of userKey.
+// Failures of individual clusters or memories are logged and skipped, so a
+// report is returned whenever the memories could be read.
+func (c *Consolidator) Consolidate(ctx context.Context, userKey memory.UserKey) (*Report, error) {
+	if err := userKey.CheckUserKey(); err != nil {
+		return nil, err
+	}
+	stored, err := c.service.ReadMemories(ctx, userKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read memories: %w", err)
+	}
+	run := &run{
+		Consolidator: c,
+		userKey:      userKey,
+		now:          c.opts.now(),
+		entries:      make(map[string]*memory.Entry, len(stored)),
+		accessed:     make(map[string]accessStat),
+		removed:      make(map[string]struct{}),
+		report:       &Report{Scanned: len(stored)},
+	}
+	// Entries are copied because in-process backends return their own
+	// records, which must only change through the service.
+	ordered := make([]*memory.Entry, 0, len(stored))
+	for _, e := range stored {
+		if e == nil || e.Memory == nil {
+			continue
+		}
+		cp := *e
+		mem := *e.Memory
+		cp.Memory = &mem
+		run.entries[cp.ID] = &cp
+		ordered = append(ordered, &cp)
+	}
+	run.applyAccesses()
+	if c.opts.judge != nil {
+		for _, cluster := range c.cluster(ordered) {
+			run.judge(ctx, cluster)
+		}
+	}
+	run.saveAccesses(ctx)
+	run.archive(ctx, ordered)
+	return run.report, nil
+}
+
+// Enqueue schedules a background consolidation of userKey. It does not
+// block: it returns an error when the queue is full or the consolidator is
+// closed, and ignores users that are already queued.
+func (c *Consolidator) Enqueue(ctx context.Context, userKey memory.UserKey) error {
+	if err := userKey.CheckUserKey(); err != nil {
+		return err
+	}
+	c.startOnce.Do(c.start)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("consolidator is closed")
+	}
+	if _, ok := c.queued[userKey]; ok {
+		return nil
+	}
+	select {
+	case c.queue <- userKey:
+		c.queued[userKey] = struct{}{}
+		return nil
+	default:
+		return fmt.Errorf("consolidation queue is full, dropping user %s/%s",
+			userKey.AppName, userKey.UserID)
+	}
+}
+
+// Close stops the background workers after the queued jobs finish.
+func (c *Consolidator) Close() error {
+	c.startOnce.Do(c.start)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.queue)
+	c.mu.Unlock()
+	c.wg.Wait()
+	return nil
+}
+
+func (c *Consolidator) start() {
+	c.queue = make(chan memory.UserKey, c.opts.queueSize)
+	for i := 0; i < c.opts.asyncWorkers; i++ {
+		c.wg.Add(1)
+		go c.work()
+	}
+}
+
+func (c *Consolidator) work() {
+	defer c.wg.Done()
+	for userKey := range c.queue {
+		c.mu.Lock()
+		delete(c.queued, userKey)
+		c.mu.Unlock()
+		c.runJob(userKey)
+	}
+}
+
+func (c *Consolidator) runJob(userKey memory.UserKey) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.jobTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			log.ErrorfContext(ctx, log.PanicPrefix+" panic in memory consolidation: %v", r)
+		}
+	}()
+	if _, err := c.Consolidate(ctx, userKey); err != nil {
+		log.WarnfContext(ctx, "memory consolidation failed for user %s/%s: %v",
+			userKey.AppName, userKey.UserID, err)
+	}
+}
+
+// cluster groups memories of the same kind whose similarity reaches the
+// threshold, linking transitively. Clusters larger than the maximum size are
+// split, keeping the most recently updated memories together.
+func (c *Consolidator) cluster(entries []*memory.Entry) [][]*memory.Entry {
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			if imemory.EffectiveKind(entries[i].Memory) != imemory.EffectiveKind(entries[j].Memory) {
+				continue
+			}
+			if c.opts.similarity(entries[i], entries[j]) >= c.opts.similarityThreshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+	groups := make(map[int][]*memory.Entry)
+	var roots []int
+	for i, e := range entries {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], e)
+	}
+	var clusters [][]*memory.Entry
+	for _, root := range roots {
+		group := groups[root]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].UpdatedAt.After(group[j].UpdatedAt)
+		})
+		for start := 0; start < len(group); start += c.opts.maxClusterSize {
+			chunk := group[start:min(start+c.opts.maxClusterSize, len(group))]
+			if len(chunk) > 1 {
+				clusters = append(clusters, chunk)
+			}
+		}
+	}
+	return clusters
+}
+
+// run holds the state of one Consolidate call.
+type run struct {
+	*Consolidator
+	userKey  memory.UserKey
+	now      time.Time
+	entries  map[string]*memory.Entry
+	accessed map[string]accessStat // Accesses not yet saved, by memory ID.
+	removed  map[string]struct{}   // Memories deleted or rewritten in this run.
+	report   *Report
+}
+
+// applyAccesses folds tracked accesses into the copied entries so that
+// importance and merges see them.
+func (r *run) applyAccesses() {
+	if r.opts.tracker == nil {
+		return
+	}
+	for id, stat := range r.opts.tracker.take(r.userKey) {
+		e, ok := r.entries[id]
+		if !ok {
+			continue
+		}
+		e.Memory.AccessCount += stat.count
+		if last := e.Memory.LastAccessedAt; last == nil || stat.last.After(*last) {
+			t := stat.last
+			e.Memory.LastAccessedAt = &t
+		}
+		r.accessed[id] = stat
+	}
+}
+
+func (r *run) judge(ctx context.Context, cluster []*memory.Entry) {
+	r.report.Clusters++
+	actions, err := r.opts.judge.Judge(ctx, cluster)
+	if err != nil {
+		log.WarnfContext(ctx, "memory consolidation: judge failed for user %s/%s: %v",
+			r.userKey.AppName, r.userKey.UserID, err)
+		return
+	}
+	members := make(map[string]*memory.Entry, len(cluster))
+	for _, e := range cluster {
+		members[e.ID] = e
+	}
+	for _, action := range actions {
+		var err error
+		switch action.Type {
+		case ActionMerge:
+			err = r.merge(ctx, members, action)
+		case ActionSupersede:
+			err = r.supersede(ctx, members, action)
+		}
+		if err != nil {
+			log.WarnfContext(ctx, "memory consolidation: %s failed for user %s/%s: %v",
+				action.Type, r.userKey.AppName, r.userKey.UserID, err)
+		}
+	}
+}
+
+// pick returns the cluster members named by ids, rejecting unknown IDs and
+// memories already changed in this run.
+func (r *run) pick(members map[string]*memory.Entry, ids []string) ([]*memory.Entry, error) {
+	var picked []*memory.Entry
+	for _, id := range ids {
+		e, ok := members[id]
+		if !ok {
+			return nil, fmt.Errorf("memory %s is not in the cluster", id)
+		}
+		if _, ok := r.removed[id]; ok {
+			return nil, fmt.Errorf("memory %s was already consolidated", id)
+		}
+		if !slices.Contains(picked, e) {
+			picked = append(picked, e)
+		}
+	}
+	return picked, nil
+}
+
+// merge rewrites one source memory with the merged content and deletes the
+// others. The source whose content already equals the merge is kept, so its
+// ID does not change; otherwise the most recently updated source is kept.
+func (r *run) merge(ctx context.Context, members map[string]*memory.Entry, action Action) error {
+	sources, err := r.pick(members, action.MemoryIDs)
+	if err != nil {
+		return err
+	}
+	if len(sources) < 2 || action.Memory == "" {
+		return errors.New("merge needs two memories and the merged content")
+	}
+	keeper := sources[0]
+	for _, e := range sources {
+		if e.Memory.Memory == action.Memory {
+			keeper = e
+			break
+		}
+		if e.UpdatedAt.After(keeper.UpdatedAt) {
+			keeper = e
+		}
+	}
+	topics := action.Topics
+	if len(topics) == 0 {
+		for _, e := range sources {
+			for _, topic := range e.Memory.Topics {
+				if !slices.Contains(topics, topic) {
+					topics = append(topics, topic)
+				}
+			}
+		}
+	}
+	var replaced []*memory.Entry
+	for _, e := range sources {
+		if e != keeper || e.Memory.Memory != action.Memory {
+			replaced = append(replaced, e)
+		}
+	}
+	others := slices.DeleteFunc(slices.Clone(sources), func(e *memory.Entry) bool { return e == keeper })
+	if err := r.rewrite(ctx, keeper, action.Memory, topics, replaced, others, action.Reason); err != nil {
+		return err
+	}
+	r.report.Merged += len(others)
+	return nil
+}
+
+// supersede keeps action.KeepID and deletes the memories it supersedes.
+func (r *run) supersede(ctx context.Context, members map[string]*memory.Entry, action Action) error {
+	kept, err := r.pick(members, []string{action.KeepID})
+	if err != nil {
+		return err
+	}
+	keeper := kept[0]
+	losers, err := r.pick(members, slices.DeleteFunc(slices.Clone(action.MemoryIDs),
+		func(id string) bool { return id == keeper.ID }))
+	if err != nil {
+		return err
+	}
+	if len(losers) == 0 {
+		return errors.New("supersede names no memory to replace")
+	}
+	if err := r.rewrite(ctx, keeper, keeper.Memory.Memory, keeper.Memory.Topics,
+		losers, losers, action.Reason); err != nil {
+		return err
+	}
+	r.report.Superseded += len(losers)
+	return nil
+}
+
+// rewrite updates keeper with content, records replaced memories in its
+// history, folds in the provenance and accesses of the deleted memories and
+// then deletes them.
+func (r *run) rewrite(ctx context.Context, keeper *memory.Entry, content string,
+	topics []string, replaced, deleted []*memory.Entry, reason string) error {
+	ep := &memory.Metadata{
+		AccessCount:    keeper.Memory.AccessCount,
+		LastAccessedAt: keeper.Memory.LastAccessedAt,
+	}
+	for _, e := range replaced {
+		if e != keeper {
+			ep.Supersedes = append(ep.Supersedes, e.Memory.Supersedes...)
+		}
+		ep.Supersedes = append(ep.Supersedes, memory.Supersession{
+			MemoryID: e.ID,
+			Memory:   e.Memory.Memory,
+			Reason:   reason,
+			Time:     r.now,
+		})
+	}
+	for _, e := range deleted {
+		ep.Provenance = imemory.AppendProvenance(ep.Provenance, e.Memory.Provenance...)
+		ep.AccessCount += e.Memory.AccessCount
+		if last := e.Memory.LastAccessedAt; last != nil &&
+			(ep.LastAccessedAt == nil || last.After(*ep.LastAccessedAt)) {
+			ep.LastAccessedAt = last
+		}
+	}
+	key := memory.Key{AppName: r.userKey.AppName, UserID: r.userKey.UserID, MemoryID: keeper.ID}
+	if err := r.service.UpdateMemory(ctx, key, content, topics,
+		memory.WithUpdateMetadata(ep)); err != nil {
+		return fmt.Errorf("update memory %s: %w", keeper.ID, err)
+	}
+	r.removed[keeper.ID] = struct{}{}
+	delete(r.accessed, keeper.ID)
+	for _, e := range deleted {
+		key.MemoryID = e.ID
+		if err := r.service.DeleteMemory(ctx, key); err != nil {
+			return fmt.Errorf("delete memory %s: %w", e.ID, err)
+		}
+		r.removed[e.ID] = struct{}{}
+		delete(r.accessed, e.ID)
+	}
+	return nil
+}
+
+// saveAccesses writes the access statistics of memories not rewritten by a
+// merge or supersede, as metadata-only updates that neither re-embed the
+// memories nor change their update time. Accesses that fail to save are
+// returned to the tracker.
+func (r *run) saveAccesses(ctx context.Context) {
+	for id, stat := range r.accessed {
+		e := r.entries[id]
+		key := memory.Key{AppName: r.userKey.AppName, UserID: r.userKey.UserID, MemoryID: id}
+		ep := &memory.Metadata{
+			AccessCount:    e.Memory.AccessCount,
+			LastAccessedAt: e.Memory.LastAccessedAt,
+		}
+		if err := r.service.UpdateMemory(ctx, key, e.Memory.Memory, e.Memory.Topics,
+			memory.WithUpdateMetadata(ep), memory.WithMetadataOnlyUpdate()); err != nil {
+			log.WarnfContext(ctx, "memory consolidation: save accesses of %s failed: %v", id, err)
+			r.opts.tracker.add(r.userKey, id, stat)
+			continue
+		}
+		r.report.Accessed++
+	}
+}
+
+// archive removes memories older than the minimum age whose importance
+// decayed below the archive threshold.
+func (r *run) archive(ctx context.Context, entries []*memory.Entry) {
+	if r.opts.archiveThreshold <= 0 {
+		return
+	}
+	var stale []*memory.Entry
+	for _, e := range entries {
+		if _, ok := r.removed[e.ID]; ok {
+			continue
+		}
+		if r.now.Sub(e.CreatedAt) < r.opts.minAge {
+			continue
+		}
+		if r.opts.importance(e, r.now) < r.opts.archiveThreshold {
+			stale = append(stale, e)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+	if r.opts.archiver != nil {
+		if err := r.opts.archiver.Archive(ctx, r.userKey, stale); err != nil {
+			log.WarnfContext(ctx, "memory consolidation: archive failed for user %s/%s: %v",
+				r.userKey.AppName, r.userKey.UserID, err)
+			return
+		}
+	}
+	for _, e := range stale {
+		key := memory.Key{AppName: r.userKey.AppName, UserID: r.userKey.UserID, MemoryID: e.ID}
+		if err := r.service.DeleteMemory(ctx, key); err != nil {
+			log.WarnfContext(ctx, "memory consolidation: delete archived memory %s failed: %v", e.ID, err)
+			continue
+		}
+		r.report.Archived++
+	}
+}
@@ -0,0 +1,322 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package consolidation
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+	"trpc.group/trpc-go/trpc-agent-go/memory/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+var testUser = memory.UserKey{AppName: "app", UserID: "u"}
+
+// addMemories adds contents and returns their IDs by content.
+func addMemories(t *testing.T, svc memory.Service, contents ...string) map[string]string {
+	t.Helper()
+	ctx := context.Background()
+	for _, content := range contents {
+		require.NoError(t, svc.AddMemory(ctx, testUser, content, []string{"t"},
+			memory.WithMetadata(&memory.Metadata{
+				Provenance: []memory.Provenance{{SessionID: "s-" + content}},
+			})))
+		time.Sleep(time.Millisecond)
+	}
+	ids := make(map[string]string, len(contents))
+	for content, e := range readAll(t, svc) {
+		ids[content] = e.ID
+	}
+	return ids
+}
+
+func readAll(t *testing.T, svc memory.Service) map[string]*memory.Entry {
+	t.Helper()
+	entries, err := svc.ReadMemories(context.Background(), testUser, 0)
+	require.NoError(t, err)
+	byContent := make(map[string]*memory.Entry, len(entries))
+	for _, e := range entries {
+		byContent[e.Memory.Memory] = e
+	}
+	return byContent
+}
+
+func TestConsolidate_Merge(t *testing.T) {
+	svc := inmemory.NewMemoryService()
+	stored := addMemories(t, svc,
+		"User likes hiking in the mountains",
+		"User likes hiking in the mountains on weekends",
+		"User works at Acme as an engineer",
+	)
+	var judged [][]string
+	judge := JudgeFunc(func(_ context.Context, cluster []*memory.Entry) ([]Action, error) {
+		var contents []string
+		var ids []string
+		for _, e := range cluster {
+			contents = append(contents, e.Memory.Memory)
+			ids = append(ids, e.ID)
+		}
+		sort.Strings(contents)
+		judged = append(judged, contents)
+		return []Action{{
+			Type:      ActionMerge,
+			MemoryIDs: ids,
+			Memory:    "User likes hiking in the mountains, usually on weekends",
+			Reason:    "duplicate",
+		}}, nil
+	})
+
+	report, err := New(svc, WithJudge(judge)).Consolidate(context.Background(), testUser)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{Scanned: 3, Clusters: 1, Merged: 1}, report)
+	assert.Equal(t, [][]string{{
+		"User likes hiking in the mountains",
+		"User likes hiking in the mountains on weekends",
+	}}, judged)
+
+	after := readAll(t, svc)
+	require.Len(t, after, 2)
+	merged := after["User likes hiking in the mountains, usually on weekends"]
+	require.NotNil(t, merged)
+	assert.Equal(t, []string{"t"}, merged.Memory.Topics)
+	superseded := map[string]string{}
+	for _, s := range merged.Memory.Supersedes {
+		superseded[s.MemoryID] = s.Memory
+		assert.Equal(t, "duplicate", s.Reason)
+	}
+	assert.Equal(t, map[string]string{
+		stored["User likes hiking in the mountains"]:             "User likes hiking in the mountains",
+		stored["User likes hiking in the mountains on weekends"]: "User likes hiking in the mountains on weekends",
+	}, superseded)
+	assert.Len(t, merged.Memory.Provenance, 2)
+}
+
+func TestConsolidate_Supersede(t *testing.T) {
+	svc := inmemory.NewMemoryService()
+	stored := addMemories(t, svc, "User lives in Paris, France", "User lives in Berlin, Germany")
+	oldID := stored["User lives in Paris, France"]
+	newID := stored["User lives in Berlin, Germany"]
+	judge := JudgeFunc(func(context.Context, []*memory.Entry) ([]Action, error) {
+		return []Action{
+			{Type: ActionSupersede, KeepID: newID, MemoryIDs: []string{oldID, newID}, Reason: "moved"},
+			{Type: ActionMerge, MemoryIDs: []string{oldID, newID}, Memory: "stale"},
+			{Type: ActionSupersede, KeepID: "unknown", MemoryIDs: []string{oldID}},
+		}, nil
+	})
+
+	report, err := New(svc, WithJudge(judge), WithSimilarity(nil, 0.3)).
+		Consolidate(context.Background(), testUser)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Superseded)
+	assert.Zero(t, report.Merged, "actions on consolidated memories are rejected")
+
+	after := readAll(t, svc)
+	require.Len(t, after, 1)
+	kept := after["User lives in Berlin, Germany"]
+	require.NotNil(t, kept)
+	assert.Equal(t, newID, kept.ID)
+	require.Len(t, kept.Memory.Supersedes, 1)
+	assert.Equal(t, memory.Supersession{
+		MemoryID: oldID, Memory: "User lives in Paris, France", Reason: "moved",
+		Time: kept.Memory.Supersedes[0].Time,
+	}, kept.Memory.Supersedes[0])
+}
+
+func TestConsolidate_JudgeErrorKeepsMemories(t *testing.T) {
+	svc := inmemory.NewMemoryService()
+	addMemories(t, svc, "User likes green tea", "User likes green tea a lot")
+	judge := JudgeFunc(func(context.Context, []*memory.Entry) ([]Action, error) {
+		return nil, errors.New("boom")
+	})
+	report, err := New(svc, WithJudge(judge)).Consolidate(context.Background(), testUser)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Clusters)
+	assert.Len(t, readAll(t, svc), 2)
+
+	_, err = New(svc).Consolidate(context.Background(), memory.UserKey{AppName: "app"})
+	assert.ErrorIs(t, err, memory.ErrUserIDRequired)
+}
+
+func TestConsolidate_AccessTrackingAndArchive(t *testing.T) {
+	base := inmemory.NewMemoryService()
+	addMemories(t, base, "User prefers dark mode", "User once asked about llamas")
+	tracker := NewAccessTracker()
+	svc := TrackAccess(base, tracker)
+	for i := 0; i < 3; i++ {
+		_, err := svc.SearchMemories(context.Background(), testUser, "dark mode")
+		require.NoError(t, err)
+	}
+
+	before := readAll(t, base)["User prefers dark mode"]
+	require.NotNil(t, before)
+
+	var archived []string
+	now := time.Now().Add(365 * 24 * time.Hour)
+	c := New(base,
+		WithAccessTracker(tracker),
+		WithArchiveThreshold(0.2, 24*time.Hour),
+		WithArchiver(ArchiverFunc(func(_ context.Context, _ memory.UserKey, entries []*memory.Entry) error {
+			for _, e := range entries {
+				archived = append(archived, e.Memory.Memory)
+			}
+			return nil
+		})),
+		WithClock(func() time.Time { return now }),
+	)
+	report, err := c.Consolidate(context.Background(), testUser)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accessed)
+	assert.Equal(t, 1, report.Archived)
+	assert.Equal(t, []string{"User once asked about llamas"}, archived)
+
+	after := readAll(t, base)
+	require.Len(t, after, 1)
+	kept := after["User prefers dark mode"]
+	require.NotNil(t, kept)
+	assert.Equal(t, 3, kept.Memory.AccessCount)
+	assert.NotNil(t, kept.Memory.LastAccessedAt)
+	assert.True(t, before.UpdatedAt.Equal(kept.UpdatedAt), "saving accesses keeps the update time")
+
+	report, err = c.Consolidate(context.Background(), testUser)
+	require.NoError(t, err)
+	assert.Zero(t, report.Accessed, "accesses are saved once")
+}
+
+func TestAccessTracker_MaxPending(t *testing.T) {
+	tracker := NewAccessTracker(WithMaxPendingAccesses(2))
+	other := memory.UserKey{AppName: "app", UserID: "other"}
+	now := time.Now()
+	tracker.Record(memory.Key{AppName: "app", UserID: "u", MemoryID: "a"}, now)
+	tracker.Record(memory.Key{AppName: "app", UserID: "other", MemoryID: "b"}, now)
+	tracker.Record(memory.Key{AppName: "app", UserID: "u", MemoryID: "c"}, now)
+	tracker.Record(memory.Key{AppName: "app", UserID: "u", MemoryID: "a"}, now)
+
+	stats := tracker.take(testUser)
+	assert.Len(t, stats, 1, "c is dropped at the cap")
+	assert.Equal(t, 2, stats["a"].count, "tracked memories keep counting")
+	tracker.Record(memory.Key{AppName: "app", UserID: "u", MemoryID: "c"}, now)
+	assert.Len(t, tracker.take(testUser), 1, "taking frees room")
+	assert.Len(t, tracker.take(other), 1)
+}
+
+func TestDecayImportance(t *testing.T) {
+	now := time.Now()
+	importance := DecayImportance(24 * time.Hour)
+	fresh := &memory.Entry{CreatedAt: now, Memory: &memory.Memory{}}
+	old := &memory.Entry{CreatedAt: now.Add(-48 * time.Hour), Memory: &memory.Memory{}}
+	accessed := &memory.Entry{CreatedAt: now.Add(-48 * time.Hour), Memory: &memory.Memory{AccessCount: 3}}
+	assert.InDelta(t, 0.7, importance(fresh, now), 1e-9)
+	assert.InDelta(t, 0.175, importance(old, now), 1e-9)
+	assert.Greater(t, importance(accessed, now), importance(old, now))
+	last := now.Add(-time.Hour)
+	accessed.Memory.LastAccessedAt = &last
+	assert.Greater(t, importance(accessed, now), importance(fresh, now))
+}
+
+func TestLexicalSimilarity(t *testing.T) {
+	a := &memory.Entry{Memory: &memory.Memory{Memory: "User likes green tea"}}
+	b := &memory.Entry{Memory: &memory.Memory{Memory: "user likes GREEN tea"}}
+	c := &memory.Entry{Memory: &memory.Memory{Memory: "Completely unrelated"}}
+	assert.InDelta(t, 1, LexicalSimilarity(a, b), 1e-9)
+	assert.Zero(t, LexicalSimilarity(a, c))
+	assert.Zero(t, LexicalSimilarity(a, nil))
+}
+
+func TestEnqueue(t *testing.T) {
+	svc := inmemory.NewMemoryService()
+	addMemories(t, svc, "User likes green tea", "User likes green tea a lot")
+	done := make(chan struct{}, 1)
+	judge := JudgeFunc(func(context.Context, []*memory.Entry) ([]Action, error) {
+		done <- struct{}{}
+		return nil, nil
+	})
+	c := New(svc, WithJudge(judge))
+	require.NoError(t, c.Enqueue(context.Background(), testUser))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("consolidation did not run")
+	}
+	require.NoError(t, c.Close())
+	assert.Error(t, c.Enqueue(context.Background(), testUser))
+	assert.Error(t, c.Enqueue(context.Background(), memory.UserKey{}))
+}
+
+type replyModel struct {
+	reply string
+	req   *model.Request
+}
+
+func (m *replyModel) GenerateContent(_ context.Context, req *model.Request) (<-chan *model.Response, error) {
+	m.req = req
+	ch := make(chan *model.Response, 1)
+	ch <- &model.Response{Choices: []model.Choice{{Message: model.NewAssistantMessage(m.reply)}}}
+	close(ch)
+	return ch, nil
+}
+
+func (m *replyModel) Info() model.Info { return model.Info{Name: "reply"} }
+
+func TestModelJudge(t *testing.T) {
+	m := &replyModel{reply: "```json\n{\"actions\":[{\"type\":\"merge\",\"memory_ids\":[\"a\",\"b\"],\"memory\":\"m\"}]}\n```"}
+	cluster := []*memory.Entry{
+		{ID: "a", Memory: &memory.Memory{Memory: "x"}},
+		{ID: "b", Memory: &memory.Memory{Memory: "y"}},
+	}
+	actions, err := NewModelJudge(m, WithJudgePrompt("custom")).Judge(context.Background(), cluster)
+	require.NoError(t, err)
+	assert.Equal(t, []Action{{Type: ActionMerge, MemoryIDs: []string{"a", "b"}, Memory: "m"}}, actions)
+	assert.Equal(t, "custom", m.req.Messages[0].Content)
+	assert.Contains(t, m.req.Messages[1].Content, `"id":"a"`)
+
+	m.reply = "no json here"
+	_, err = NewModelJudge(m).Judge(context.Background(), cluster)
+	assert.Error(t, err)
+}
+
+// errorModel sends an error and then more responses without watching its
+// context, and closes done when it returns.
+type errorModel struct {
+	done chan struct{}
+}
+
+func (m *errorModel) GenerateContent(context.Context, *model.Request) (<-chan *model.Response, error) {
+	ch := make(chan *model.Response)
+	go func() {
+		defer close(m.done)
+		defer close(ch)
+		ch <- &model.Response{Error: &model.ResponseError{Message: "overloaded"}}
+		for i := 0; i < 3; i++ {
+			ch <- &model.Response{Choices: []model.Choice{{Message: model.NewAssistantMessage("late")}}}
+		}
+	}()
+	return ch, nil
+}
+
+func (m *errorModel) Info() model.Info { return model.Info{Name: "error"} }
+
+func TestModelJudge_DrainsOnError(t *testing.T) {
+	m := &errorModel{done: make(chan struct{})}
+	cluster := []*memory.Entry{{ID: "a", Memory: &memory.Memory{Memory: "x"}}}
+	_, err := NewModelJudge(m).Judge(context.Background(), cluster)
+	assert.ErrorContains(t, err, "overloaded")
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("model goroutine is blocked")
+	}
+}
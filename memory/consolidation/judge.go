@@ -0,0 +1,181 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package consolidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// ActionType is the kind of change a judge asks for.
+type ActionType string
+
+const (
+	// ActionKeep leaves the listed memories unchanged.
+	ActionKeep ActionType = "keep"
+	// ActionMerge replaces the listed memories with one memory holding
+	// the merged content.
+	ActionMerge ActionType = "merge"
+	// ActionSupersede keeps KeepID and deletes the listed memories, which
+	// are outdated or contradicted by it.
+	ActionSupersede ActionType = "supersede"
+)
+
+// Action is a judge decision about memories of one cluster.
+type Action struct {
+	Type      ActionType `json:"type"`
+	MemoryIDs []string   `json:"memory_ids"`        // Memories the action applies to.
+	KeepID    string     `json:"keep_id,omitempty"` // Surviving memory of a supersede.
+	Memory    string     `json:"memory,omitempty"`  // Merged content of a merge.
+	Topics    []string   `json:"topics,omitempty"`  // Topics of a merge; empty keeps the union.
+	Reason    string     `json:"reason,omitempty"`  // Why the action was taken.
+}
+
+// Judge decides how a cluster of similar memories is consolidated.
+// Memories not named by any returned action are kept.
+type Judge interface {
+	Judge(ctx context.Context, cluster []*memory.Entry) ([]Action, error)
+}
+
+// JudgeFunc adapts a function to the Judge interface.
+type JudgeFunc func(ctx context.Context, cluster []*memory.Entry) ([]Action, error)
+
+// Judge implements Judge.
+func (f JudgeFunc) Judge(ctx context.Context, cluster []*memory.Entry) ([]Action, error) {
+	return f(ctx, cluster)
+}
+
+const defaultJudgePrompt = `You maintain the long-term memory of an assistant about a user.
+You are given a group of similar memories as a JSON array. Decide how to consolidate them.
+
+Rules:
+- Merge memories that state the same or overlapping facts into one concise memory that keeps every distinct detail. Use action "merge" with all merged ids in "memory_ids" and the merged text in "memory".
+- When memories contradict each other, the most recently updated one usually reflects the current state. Use action "supersede" with the surviving id in "keep_id" and the outdated ids in "memory_ids".
+- Memories about different things must stay separate; leave them out of every action.
+- Never invent facts, and never merge episodes that happened at different times.
+- Give a short "reason" for every action.
+
+Reply with JSON only, in the form:
+{"actions":[{"type":"merge","memory_ids":["id1","id2"],"memory":"...","topics":["..."],"reason":"..."},{"type":"supersede","keep_id":"id3","memory_ids":["id4"],"reason":"..."}]}
+Reply {"actions":[]} when nothing should change.`
+
+// JudgeOption configures a model judge.
+type JudgeOption func(*modelJudge)
+
+// WithJudgePrompt replaces the system prompt of the model judge.
+func WithJudgePrompt(prompt string) JudgeOption {
+	return func(j *modelJudge) {
+		if prompt != "" {
+			j.prompt = prompt
+		}
+	}
+}
+
+// NewModelJudge returns a Judge that asks m to merge duplicates and resolve
+// conflicts within a cluster.
+func NewModelJudge(m model.Model, opts ...JudgeOption) Judge {
+	j := &modelJudge{model: m, prompt: defaultJudgePrompt}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+type modelJudge struct {
+	model  model.Model
+	prompt string
+}
+
+type judgedMemory struct {
+	ID        string   `json:"id"`
+	Memory    string   `json:"memory"`
+	Topics    []string `json:"topics,omitempty"`
+	Kind      string   `json:"kind,omitempty"`
+	EventTime string   `json:"event_time,omitempty"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// Judge implements Judge.
+func (j *modelJudge) Judge(ctx context.Context, cluster []*memory.Entry) ([]Action, error) {
+	items := make([]judgedMemory, 0, len(cluster))
+	for _, e := range cluster {
+		item := judgedMemory{
+			ID:        e.ID,
+			Memory:    e.Memory.Memory,
+			Topics:    e.Memory.Topics,
+			Kind:      string(e.Memory.Kind),
+			UpdatedAt: e.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if e.Memory.EventTime != nil {
+			item.EventTime = e.Memory.EventTime.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("marshal memories: %w", err)
+	}
+	req := &model.Request{
+		Messages: []model.Message{
+			model.NewSystemMessage(j.prompt),
+			model.NewUserMessage(string(payload)),
+		},
+		GenerationConfig: model.GenerationConfig{Stream: false},
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rspChan, err := j.model.GenerateContent(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("model call failed: %w", err)
+	}
+	var content string
+	for rsp := range rspChan {
+		if rsp == nil {
+			continue
+		}
+		if rsp.Error != nil {
+			// Stop the model and drain the channel so that it does not
+			// block on a send nobody receives.
+			cancel()
+			for range rspChan {
+			}
+			return nil, fmt.Errorf("model error: %s", rsp.Error.Message)
+		}
+		if rsp.IsPartial || len(rsp.Choices) == 0 {
+			continue
+		}
+		content = rsp.Choices[0].Message.Content
+	}
+	return parseActions(content)
+}
+
+// parseActions decodes a judge reply, tolerating code fences and text
+// around the JSON object.
+func parseActions(content string) ([]Action, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("judge reply contains no JSON object")
+	}
+	var reply struct {
+		Actions []Action `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &reply); err != nil {
+		return nil, fmt.Errorf("decode judge reply: %w", err)
+	}
+	return reply.Actions, nil
+}
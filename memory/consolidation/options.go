@@ -0,0 +1,170 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package consolidation
+
+import (
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+)
+
+const (
+	defaultSimilarityThreshold = 0.5
+	defaultMaxClusterSize      = 8
+	defaultHalfLife            = 30 * 24 * time.Hour
+	defaultMinAge              = 7 * 24 * time.Hour
+	defaultAsyncWorkers        = 1
+	defaultQueueSize           = 64
+	defaultJobTimeout          = 2 * time.Minute
+)
+
+// SimilarityFunc returns the similarity of two memories in [0, 1].
+type SimilarityFunc func(a, b *memory.Entry) float64
+
+// ImportanceFunc scores how worth keeping a memory is at now, in [0, 1].
+type ImportanceFunc func(entry *memory.Entry, now time.Time) float64
+
+// Option configures a Consolidator.
+type Option func(*options)
+
+type options struct {
+	judge               Judge
+	similarity          SimilarityFunc
+	similarityThreshold float64
+	maxClusterSize      int
+	importance          ImportanceFunc
+	archiveThreshold    float64
+	minAge              time.Duration
+	archiver            Archiver
+	tracker             *AccessTracker
+	asyncWorkers        int
+	queueSize           int
+	jobTimeout          time.Duration
+	now                 func() time.Time
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		similarity:          LexicalSimilarity,
+		similarityThreshold: defaultSimilarityThreshold,
+		maxClusterSize:      defaultMaxClusterSize,
+		importance:          DecayImportance(defaultHalfLife),
+		minAge:              defaultMinAge,
+		asyncWorkers:        defaultAsyncWorkers,
+		queueSize:           defaultQueueSize,
+		jobTimeout:          defaultJobTimeout,
+		now:                 time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithJudge sets the judge that decides how clusters of similar memories
+// are merged or superseded. Without a judge, clustering is skipped and only
+// access statistics and decay are processed.
+func WithJudge(judge Judge) Option {
+	return func(o *options) {
+		o.judge = judge
+	}
+}
+
+// WithSimilarity sets the function used to cluster memories, for example an
+// embedding-based cosine similarity. The default is LexicalSimilarity.
+func WithSimilarity(fn SimilarityFunc, threshold float64) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.similarity = fn
+		}
+		if threshold > 0 {
+			o.similarityThreshold = threshold
+		}
+	}
+}
+
+// WithMaxClusterSize caps the number of memories sent to the judge at once.
+func WithMaxClusterSize(size int) Option {
+	return func(o *options) {
+		if size > 1 {
+			o.maxClusterSize = size
+		}
+	}
+}
+
+// WithImportance sets the importance function. The default is
+// DecayImportance with a 30 day half-life.
+func WithImportance(fn ImportanceFunc) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.importance = fn
+		}
+	}
+}
+
+// WithArchiveThreshold enables archiving of memories whose importance falls
+// below threshold once they are older than minAge. Archiving is disabled by
+// default because it removes memories from the service.
+func WithArchiveThreshold(threshold float64, minAge time.Duration) Option {
+	return func(o *options) {
+		o.archiveThreshold = threshold
+		if minAge > 0 {
+			o.minAge = minAge
+		}
+	}
+}
+
+// WithArchiver sets where archived memories are kept before they are
+// deleted from the service. Without an archiver they are only deleted, which
+// backends with soft deletion keep as tombstones.
+func WithArchiver(archiver Archiver) Option {
+	return func(o *options) {
+		o.archiver = archiver
+	}
+}
+
+// WithAccessTracker sets the tracker whose recorded accesses are saved on the
+// memories during consolidation. Use the same tracker with TrackAccess.
+func WithAccessTracker(tracker *AccessTracker) Option {
+	return func(o *options) {
+		o.tracker = tracker
+	}
+}
+
+// WithAsyncWorkers sets the number of background workers and the queue size
+// used by Enqueue.
+func WithAsyncWorkers(workers, queueSize int) Option {
+	return func(o *options) {
+		if workers > 0 {
+			o.asyncWorkers = workers
+		}
+		if queueSize > 0 {
+			o.queueSize = queueSize
+		}
+	}
+}
+
+// WithJobTimeout sets the timeout of a background consolidation job.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.jobTimeout = timeout
+		}
+	}
+}
+
+// WithClock sets the clock used for decay and history timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		if now != nil {
+			o.now = now
+		}
+	}
+}
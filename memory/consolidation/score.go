@@ -0,0 +1,70 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package consolidation
+
+import (
+	"math"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/memory"
+	imemory "trpc.group/trpc-go/trpc-agent-go/memory/internal/memory"
+)
+
+// recencyWeight is the share of recency in the default importance score;
+// the rest comes from access frequency.
+const recencyWeight = 0.7
+
+// LexicalSimilarity returns the Jaccard similarity of the search tokens of
+// two memories. Stopwords are dropped and CJK text is segmented the same way
+// as for keyword search.
+func LexicalSimilarity(a, b *memory.Entry) float64 {
+	if a == nil || b == nil || a.Memory == nil || b.Memory == nil {
+		return 0
+	}
+	ta := imemory.BuildSearchTokens(a.Memory.Memory)
+	tb := imemory.BuildSearchTokens(b.Memory.Memory)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	set := make(map[string]struct{}, len(ta))
+	for _, token := range ta {
+		set[token] = struct{}{}
+	}
+	shared := 0
+	for _, token := range tb {
+		if _, ok := set[token]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// DecayImportance returns an importance function that halves the recency of
+// a memory every halfLife since it was created or last accessed, and blends
+// it with a saturating access frequency so that memories retrieved often
+// decay more slowly.
+func DecayImportance(halfLife time.Duration) ImportanceFunc {
+	if halfLife <= 0 {
+		halfLife = defaultHalfLife
+	}
+	return func(entry *memory.Entry, now time.Time) float64 {
+		if entry == nil || entry.Memory == nil {
+			return 0
+		}
+		last := entry.CreatedAt
+		if t := entry.Memory.LastAccessedAt; t != nil && t.After(last) {
+			last = *t
+		}
+		age := max(now.Sub(last), 0)
+		recency := math.Exp2(-float64(age) / float64(halfLife))
+		frequency := 1 - 1/(1+float64(entry.Memory.AccessCount))
+		return recencyWeight*recency + (1-recencyWeight)*frequency
+	}
+}
@@ -71,6 +71,11 @@ type Operation struct {
 	EventTime    *time.Time  // When the event occurred.
 	Participants []string    // People involved in the event.
 	Location     string      // Where the event took place.
+
+	// Scope selects the memory scope the operation targets. Empty means the
+	// default writable scope for adds and the user scope for updates,
+	// deletes and clears.
+	Scope memory.ScopeKind
 }
 
 // OperationType defines the type of memory operation.
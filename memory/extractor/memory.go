@@ -287,7 +287,8 @@ func (e *memoryExtractor) buildMessages(
 
 	// Add system prompt with existing memories.
 	result = append(result, model.NewSystemMessage(
-		e.buildSystemPrompt(refDate, existing),
+		e.buildSystemPrompt(refDate, existing)+
+			scopePromptBlock(memory.ScopeAccessFromContext(ctx)),
 	))
 
 	// Add conversation messages.
@@ -488,6 +489,34 @@ func (e *memoryExtractor) runAfterModelCallbacks(
 	return ctx, response, nil
 }
 
+// scopePromptBlock tells the extractor which shared scopes it may write.
+// It is empty when only the user scope is writable, which keeps the prompt
+// unchanged for callers that do not use shared scopes.
+func scopePromptBlock(access *memory.ScopeAccess) string {
+	if access == nil {
+		return ""
+	}
+	var shared []string
+	for _, kind := range access.Write {
+		if kind != memory.ScopeUser {
+			shared = append(shared, string(kind))
+		}
+	}
+	if len(shared) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n<memory_scopes>\n")
+	sb.WriteString("Memories are stored for the current user unless you set the scope argument.\n")
+	sb.WriteString("Writable shared scopes: " + strings.Join(shared, ", ") + ".\n")
+	sb.WriteString("- agent: knowledge every user of this assistant benefits from.\n")
+	sb.WriteString("- group: knowledge about the user's team, organisation or tenant.\n")
+	sb.WriteString("- app: knowledge that holds for every user of the application.\n")
+	sb.WriteString("Never put personal or sensitive details in a shared scope. When updating or deleting a memory, pass the scope shown for it in existing memories.\n")
+	sb.WriteString("</memory_scopes>\n")
+	return sb.String()
+}
+
 // formatExistingMemory formats a single memory entry for inclusion in the
 // system prompt. For episodic memories it appends kind, event_time,
 // participants and location so the LLM can properly deduplicate.
@@ -518,6 +547,10 @@ func formatExistingMemory(entry *memory.Entry) string {
 		meta = append(meta,
 			fmt.Sprintf("location=%s", m.Location))
 	}
+	if scope := memory.ScopeFromUserID(entry.UserID); scope.Kind != memory.ScopeUser {
+		meta = append(meta,
+			fmt.Sprintf("scope=%s", scope.Kind))
+	}
 	if len(meta) == 0 {
 		return base + "\n"
 	}
@@ -11,6 +11,7 @@ package extractor
 import (
 	"encoding/json"
 	"slices"
+	"strings"
 
 	"trpc.group/trpc-go/trpc-agent-go/memory"
 	memorytool "trpc.group/trpc-go/trpc-agent-go/memory/tool"
@@ -188,6 +189,7 @@ const (
 	argKeyEventTime    = "event_time"
 	argKeyParticipants = "participants"
 	argKeyLocation     = "location"
+	argKeyScope        = "scope"
 )
 
 // parseToolCallArgs parses tool call arguments and returns a memory operation.
@@ -202,6 +204,7 @@ func parseToolCallArgs(toolName string, args map[string]any) *Operation {
 			Type:   OperationAdd,
 			Memory: mem,
 			Topics: toStringSlice(args[argKeyTopics]),
+			Scope:  scopeArg(args),
 		}
 		parseEpisodicArgs(op, args)
 		return op
@@ -217,6 +220,7 @@ func parseToolCallArgs(toolName string, args map[string]any) *Operation {
 			MemoryID: id,
 			Memory:   mem,
 			Topics:   toStringSlice(args[argKeyTopics]),
+			Scope:    scopeArg(args),
 		}
 		parseEpisodicArgs(op, args)
 		return op
@@ -229,11 +233,13 @@ func parseToolCallArgs(toolName string, args map[string]any) *Operation {
 		return &Operation{
 			Type:     OperationDelete,
 			MemoryID: id,
+			Scope:    scopeArg(args),
 		}
 
 	case memory.ClearToolName:
 		return &Operation{
-			Type: OperationClear,
+			Type:  OperationClear,
+			Scope: scopeArg(args),
 		}
 
 	default:
@@ -259,6 +265,12 @@ func parseEpisodicArgs(op *Operation, args map[string]any) {
 	}
 }
 
+// scopeArg reads the optional scope argument of a memory tool call.
+func scopeArg(args map[string]any) memory.ScopeKind {
+	scope, _ := args[argKeyScope].(string)
+	return memory.ScopeKind(strings.TrimSpace(scope))
+}
+
 // toStringSlice converts an any value to []string.
 // Always returns an empty slice instead of nil for consistent downstream handling.
 func toStringSlice(v any) []string {
@@ -206,6 +206,9 @@ func (s *MemoryService) UpdateMemory(ctx context.Context, memoryKey memory.Key,
 	}
 
 	now := time.Now()
+	if imemory.KeepsContent(memoryEntry, memoryStr, topics, opts) {
+		now = memoryEntry.UpdatedAt
+	}
 	ep := memory.ResolveUpdateOptions(opts)
 	candidate := *memoryEntry
 	if memoryEntry.Memory != nil {
@@ -355,6 +355,10 @@ func (w *AutoMemoryWorker) EnqueueJob(ctx context.Context, sess *session.Session
 		log.DebugfContext(ctx, "auto_memory: skipped due to empty userKey")
 		return nil
 	}
+	if err := memory.CheckUserID(userKey.UserID); err != nil {
+		log.DebugfContext(ctx, "auto_memory: skipped: %v", err)
+		return nil
+	}
 
 	since := readLastExtractAt(sess)
 	latestTs, messages := scanDeltaSince(
@@ -367,6 +371,7 @@ func (w *AutoMemoryWorker) EnqueueJob(ctx context.Context, sess *session.Session
 			userKey.AppName, userKey.UserID)
 		return nil
 	}
+	ctx = withSessionProvenance(ctx, sess, latestTs)
 
 	var lastExtractAtPtr *time.Time
 	if !since.IsZero() {
@@ -572,11 +577,15 @@ func (w *AutoMemoryWorker) searchRelevantMemories(
 	if query == "" {
 		return nil, nil
 	}
-	entries, err := w.operator.SearchMemories(ctx, userKey, query)
+	var reader memory.Reader = w.operator
+	if access := memory.ScopeAccessFromContext(ctx); access != nil {
+		reader = memory.NewScopedReader(w.operator, access)
+	}
+	entries, err := reader.SearchMemories(ctx, userKey, query)
 	if err == nil {
 		return entries, nil
 	}
-	fallback, readErr := w.operator.ReadMemories(
+	fallback, readErr := reader.ReadMemories(
 		ctx, userKey, DefaultMaxSearchResults,
 	)
 	if readErr != nil {
@@ -678,9 +687,16 @@ func (w *AutoMemoryWorker) executeOperation(
 		}
 	}
 
+	userKey, err := operationUserKey(ctx, userKey, op)
+	if err != nil {
+		return fmt.Errorf("auto_memory: %s operation for user %s/%s: %w",
+			op.Type, userKey.AppName, userKey.UserID, err)
+	}
+
 	switch op.Type {
 	case extractor.OperationAdd:
 		ep := opToMetadata(op)
+		ep.Provenance = provenanceFromContext(ctx)
 		if err := w.operator.AddMemory(ctx, userKey,
 			op.Memory, op.Topics,
 			memory.WithMetadata(ep)); err != nil {
@@ -694,6 +710,7 @@ func (w *AutoMemoryWorker) executeOperation(
 			MemoryID: op.MemoryID,
 		}
 		ep := opToMetadata(op)
+		ep.Provenance = provenanceFromContext(ctx)
 		if err := w.operator.UpdateMemory(ctx, memKey,
 			op.Memory, op.Topics,
 			memory.WithUpdateMetadata(ep)); err != nil {
@@ -750,6 +767,56 @@ func (w *AutoMemoryWorker) executeOperation(
 	return nil
 }
 
+// operationUserKey routes an operation to the storage key of its scope, see
+// memory.ScopeAccess.WriteScope for adds and ModifyScope for the others.
+// Without a scope access only the user scope is writable.
+func operationUserKey(
+	ctx context.Context,
+	userKey memory.UserKey,
+	op *extractor.Operation,
+) (memory.UserKey, error) {
+	access := memory.ScopeAccessFromContext(ctx)
+	resolve := access.ModifyScope
+	if op.Type == extractor.OperationAdd {
+		resolve = access.WriteScope
+	}
+	scope, err := resolve(op.Scope, userKey.UserID)
+	if err != nil {
+		return userKey, err
+	}
+	return scope.UserKey(userKey.AppName), nil
+}
+
+type provenanceKey struct{}
+
+// withSessionProvenance records the session and its last event up to latestTs
+// as the source of memories written by the job.
+func withSessionProvenance(
+	ctx context.Context,
+	sess *session.Session,
+	latestTs time.Time,
+) context.Context {
+	source := memory.Provenance{SessionID: sess.ID, Time: time.Now()}
+	sess.EventMu.RLock()
+	for i := len(sess.Events) - 1; i >= 0; i-- {
+		if !sess.Events[i].Timestamp.After(latestTs) {
+			source.EventID = sess.Events[i].ID
+			break
+		}
+	}
+	sess.EventMu.RUnlock()
+	return context.WithValue(ctx, provenanceKey{}, source)
+}
+
+// provenanceFromContext returns the source recorded by withSessionProvenance.
+func provenanceFromContext(ctx context.Context) []memory.Provenance {
+	source, ok := ctx.Value(provenanceKey{}).(memory.Provenance)
+	if !ok {
+		return nil
+	}
+	return []memory.Provenance{source}
+}
+
 // opToMetadata converts extractor.Operation episodic
 // fields to memory.Metadata. Always returns a non-nil
 // value; defaults to Kind=KindFact when no episodic data
@@ -2883,3 +2883,50 @@ func TestReconcileOps_PrefersHigherTierCandidate(t *testing.T) {
 	require.Empty(t, out,
 		"reconcile should drop the Add based on the tier-skip candidate rather than keep it based on a tier-none Jaccard winner")
 }
+
+func TestOperationUserKey_Scopes(t *testing.T) {
+	userKey := memory.UserKey{AppName: "app", UserID: "u"}
+	add := &extractor.Operation{Type: extractor.OperationAdd}
+	update := &extractor.Operation{Type: extractor.OperationUpdate}
+
+	key, err := operationUserKey(context.Background(), userKey, add)
+	require.NoError(t, err)
+	assert.Equal(t, userKey, key)
+	_, err = operationUserKey(context.Background(), userKey,
+		&extractor.Operation{Type: extractor.OperationAdd, Scope: memory.ScopeApp})
+	assert.Error(t, err)
+
+	ctx := memory.WithScopeAccess(context.Background(), &memory.ScopeAccess{
+		Write:   []memory.ScopeKind{memory.ScopeGroup, memory.ScopeUser},
+		GroupID: "g",
+	})
+	key, err = operationUserKey(ctx, userKey, add)
+	require.NoError(t, err)
+	assert.Equal(t, memory.UserKey{AppName: "app", UserID: "scope:group:g"}, key)
+
+	key, err = operationUserKey(ctx, userKey, update)
+	require.NoError(t, err)
+	assert.Equal(t, userKey, key, "operations on existing memories default to the user scope")
+
+	key, err = operationUserKey(ctx, userKey, &extractor.Operation{Type: extractor.OperationClear})
+	require.NoError(t, err)
+	assert.Equal(t, userKey, key, "a clear without a scope never clears shared memories")
+}
+
+func TestSessionProvenance(t *testing.T) {
+	assert.Nil(t, provenanceFromContext(context.Background()))
+
+	sess := newTestSession("app", "u")
+	base := time.Now()
+	sess.Events = []event.Event{
+		{ID: "e1", Timestamp: base},
+		{ID: "e2", Timestamp: base.Add(time.Second)},
+		{ID: "e3", Timestamp: base.Add(2 * time.Second)},
+	}
+	ctx := withSessionProvenance(context.Background(), sess, base.Add(time.Second))
+	sources := provenanceFromContext(ctx)
+	require.Len(t, sources, 1)
+	assert.Equal(t, sess.ID, sources[0].SessionID)
+	assert.Equal(t, "e2", sources[0].EventID)
+	assert.False(t, sources[0].Time.IsZero())
+}
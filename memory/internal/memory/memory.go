@@ -12,6 +12,7 @@ package memory
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"math"
 	"slices"
@@ -822,6 +823,10 @@ func ApplyMetadata(mem *memory.Memory, ep *memory.Metadata) {
 		mem.EventTime = ep.EventTime
 		mem.Participants = ep.Participants
 		mem.Location = ep.Location
+		mem.Provenance = ep.Provenance
+		mem.Supersedes = ep.Supersedes
+		mem.AccessCount = ep.AccessCount
+		mem.LastAccessedAt = ep.LastAccessedAt
 	}
 	NormalizeMemory(mem)
 }
@@ -847,10 +852,80 @@ func ApplyMetadataPatch(mem *memory.Memory, ep *memory.Metadata) {
 		if ep.Location != "" {
 			mem.Location = ep.Location
 		}
+		mem.Provenance = AppendProvenance(mem.Provenance, ep.Provenance...)
+		mem.Supersedes = append(mem.Supersedes, ep.Supersedes...)
+		if ep.AccessCount > 0 {
+			mem.AccessCount = ep.AccessCount
+		}
+		if ep.LastAccessedAt != nil {
+			mem.LastAccessedAt = ep.LastAccessedAt
+		}
 	}
 	NormalizeMemory(mem)
 }
 
+// AppendProvenance appends sources to history, skipping sources that name a
+// session and event already recorded.
+func AppendProvenance(
+	history []memory.Provenance,
+	sources ...memory.Provenance,
+) []memory.Provenance {
+	for _, source := range sources {
+		if slices.ContainsFunc(history, func(p memory.Provenance) bool {
+			return p.SessionID == source.SessionID && p.EventID == source.EventID
+		}) {
+			continue
+		}
+		history = append(history, source)
+	}
+	return history
+}
+
+// consolidationRecord is the stored form of the consolidation fields for
+// backends that keep memory metadata in separate columns.
+type consolidationRecord struct {
+	Provenance     []memory.Provenance   `json:"provenance,omitempty"`
+	Supersedes     []memory.Supersession `json:"supersedes,omitempty"`
+	AccessCount    int                   `json:"access_count,omitempty"`
+	LastAccessedAt *time.Time            `json:"last_accessed_at,omitempty"`
+}
+
+// MarshalConsolidation encodes the consolidation fields of mem as JSON for
+// column-based backends. It returns an empty string when none is set.
+func MarshalConsolidation(mem *memory.Memory) (string, error) {
+	if mem == nil || (len(mem.Provenance) == 0 && len(mem.Supersedes) == 0 &&
+		mem.AccessCount == 0 && mem.LastAccessedAt == nil) {
+		return "", nil
+	}
+	data, err := json.Marshal(consolidationRecord{
+		Provenance:     mem.Provenance,
+		Supersedes:     mem.Supersedes,
+		AccessCount:    mem.AccessCount,
+		LastAccessedAt: mem.LastAccessedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// UnmarshalConsolidation decodes a value produced by MarshalConsolidation
+// into mem. An empty value leaves mem unchanged.
+func UnmarshalConsolidation(mem *memory.Memory, raw string) error {
+	if mem == nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var rec consolidationRecord
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return err
+	}
+	mem.Provenance = rec.Provenance
+	mem.Supersedes = rec.Supersedes
+	mem.AccessCount = rec.AccessCount
+	mem.LastAccessedAt = rec.LastAccessedAt
+	return nil
+}
+
 func normalizeAddMetadata(ep *memory.Metadata) *memory.Metadata {
 	if ep == nil {
 		return nil
@@ -860,6 +935,11 @@ func normalizeAddMetadata(ep *memory.Metadata) *memory.Metadata {
 		EventTime:    ep.EventTime,
 		Participants: metadataIdentityParticipants(&memory.Memory{Participants: ep.Participants}),
 		Location:     strings.TrimSpace(ep.Location),
+
+		Provenance:     ep.Provenance,
+		Supersedes:     ep.Supersedes,
+		AccessCount:    ep.AccessCount,
+		LastAccessedAt: ep.LastAccessedAt,
 	}
 	if normalized.Kind == "" && (normalized.EventTime != nil ||
 		len(normalized.Participants) > 0 ||
@@ -878,6 +958,11 @@ func normalizeUpdateMetadata(ep *memory.Metadata) *memory.Metadata {
 		EventTime:    ep.EventTime,
 		Participants: metadataIdentityParticipants(&memory.Memory{Participants: ep.Participants}),
 		Location:     strings.TrimSpace(ep.Location),
+
+		Provenance:     ep.Provenance,
+		Supersedes:     ep.Supersedes,
+		AccessCount:    ep.AccessCount,
+		LastAccessedAt: ep.LastAccessedAt,
 	}
 }
 
@@ -925,6 +1010,28 @@ func ApplyMemoryUpdate(
 	return entry.ID
 }
 
+// KeepsContent reports whether an update marked with
+// memory.WithMetadataOnlyUpdate leaves the content, topics and identity of
+// entry unchanged, so that the stored embedding and update time stay valid.
+func KeepsContent(
+	entry *memory.Entry,
+	memoryStr string,
+	topics []string,
+	opts []memory.UpdateOption,
+) bool {
+	if entry == nil || entry.Memory == nil ||
+		!memory.ResolveMetadataOnlyUpdate(opts) {
+		return false
+	}
+	if entry.Memory.Memory != memoryStr ||
+		!slices.Equal(entry.Memory.Topics, topics) {
+		return false
+	}
+	ep := memory.ResolveUpdateOptions(opts)
+	return ep == nil || (ep.Kind == "" && ep.EventTime == nil &&
+		len(ep.Participants) == 0 && ep.Location == "")
+}
+
 // MatchMemoryEntry checks if a memory entry matches the given query.
 // Kept for backward compatibility; returns true when the relevance
 // score is greater than zero.
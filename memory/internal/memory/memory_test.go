@@ -394,6 +394,37 @@ func TestApplyMemoryUpdate(t *testing.T) {
 	assert.Equal(t, []string{"new"}, entry.Memory.Topics)
 }
 
+func TestKeepsContent(t *testing.T) {
+	entry := &memory.Entry{Memory: &memory.Memory{Memory: "m", Topics: []string{"t"}}}
+	access := &memory.Metadata{AccessCount: 2}
+	tests := []struct {
+		name   string
+		memory string
+		topics []string
+		opts   []memory.UpdateOption
+		want   bool
+	}{
+		{"metadata only", "m", []string{"t"},
+			[]memory.UpdateOption{memory.WithUpdateMetadata(access), memory.WithMetadataOnlyUpdate()}, true},
+		{"not marked", "m", []string{"t"},
+			[]memory.UpdateOption{memory.WithUpdateMetadata(access)}, false},
+		{"content changed", "n", []string{"t"},
+			[]memory.UpdateOption{memory.WithMetadataOnlyUpdate()}, false},
+		{"topics changed", "m", []string{"u"},
+			[]memory.UpdateOption{memory.WithMetadataOnlyUpdate()}, false},
+		{"identity changed", "m", []string{"t"}, []memory.UpdateOption{
+			memory.WithUpdateMetadata(&memory.Metadata{Location: "Kyoto"}),
+			memory.WithMetadataOnlyUpdate(),
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KeepsContent(entry, tt.memory, tt.topics, tt.opts))
+		})
+	}
+	assert.False(t, KeepsContent(nil, "m", nil, []memory.UpdateOption{memory.WithMetadataOnlyUpdate()}))
+}
+
 func TestApplyMemoryUpdate_InitializesNilMemoryAndMetadata(t *testing.T) {
 	now := time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
 	entry := &memory.Entry{}
@@ -1884,3 +1915,58 @@ func newSearchTestEntry(
 		},
 	}
 }
+
+func TestApplyMetadataPatch_ConsolidationFields(t *testing.T) {
+	accessed := time.Now()
+	mem := &memory.Memory{
+		Provenance: []memory.Provenance{{SessionID: "s1", EventID: "e1"}},
+		Supersedes: []memory.Supersession{{MemoryID: "old"}},
+	}
+	ApplyMetadataPatch(mem, &memory.Metadata{
+		Provenance: []memory.Provenance{
+			{SessionID: "s1", EventID: "e1"},
+			{SessionID: "s2", EventID: "e2"},
+		},
+		Supersedes:     []memory.Supersession{{MemoryID: "older"}},
+		AccessCount:    4,
+		LastAccessedAt: &accessed,
+	})
+	assert.Equal(t, []memory.Provenance{
+		{SessionID: "s1", EventID: "e1"},
+		{SessionID: "s2", EventID: "e2"},
+	}, mem.Provenance)
+	assert.Equal(t, []memory.Supersession{{MemoryID: "old"}, {MemoryID: "older"}}, mem.Supersedes)
+	assert.Equal(t, 4, mem.AccessCount)
+	assert.Equal(t, &accessed, mem.LastAccessedAt)
+
+	ApplyMetadataPatch(mem, &memory.Metadata{Kind: memory.KindFact})
+	assert.Equal(t, 4, mem.AccessCount, "unset access fields keep stored values")
+	assert.Len(t, mem.Provenance, 2)
+}
+
+func TestConsolidationRoundTrip(t *testing.T) {
+	raw, err := MarshalConsolidation(&memory.Memory{Memory: "m"})
+	require.NoError(t, err)
+	assert.Empty(t, raw, "no consolidation fields are encoded as empty")
+
+	accessed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	src := &memory.Memory{
+		Provenance:     []memory.Provenance{{SessionID: "s1", EventID: "e1", Time: accessed}},
+		Supersedes:     []memory.Supersession{{MemoryID: "old", Memory: "old fact", Time: accessed}},
+		AccessCount:    3,
+		LastAccessedAt: &accessed,
+	}
+	raw, err = MarshalConsolidation(src)
+	require.NoError(t, err)
+
+	got := &memory.Memory{Memory: "m"}
+	require.NoError(t, UnmarshalConsolidation(got, raw))
+	assert.Equal(t, src.Provenance, got.Provenance)
+	assert.Equal(t, src.Supersedes, got.Supersedes)
+	assert.Equal(t, 3, got.AccessCount)
+	assert.True(t, accessed.Equal(*got.LastAccessedAt))
+
+	require.NoError(t, UnmarshalConsolidation(got, ""))
+	assert.Equal(t, 3, got.AccessCount, "empty value keeps fields")
+	assert.Error(t, UnmarshalConsolidation(got, "{"))
+}
@@ -48,6 +48,9 @@ var (
 	ErrUserIDRequired = errors.New("userID is required")
 	// ErrMemoryIDRequired is the error for memory id required.
 	ErrMemoryIDRequired = errors.New("memoryID is required")
+	// ErrReservedUserID is the error for user ids that use the prefix
+	// reserved for shared memory scopes.
+	ErrReservedUserID = errors.New(`userID must not start with "` + scopeSubjectPrefix + `"`)
 )
 
 // Metadata holds optional memory metadata.
@@ -57,6 +60,14 @@ type Metadata struct {
 	EventTime    *time.Time // When the event occurred (required for episodes).
 	Participants []string   // People involved in the event.
 	Location     string     // Where the event took place.
+
+	// Consolidation bookkeeping. On update, Provenance and Supersedes are
+	// appended to the stored history while the access fields replace the
+	// stored values when set.
+	Provenance     []Provenance   // Sources the memory was derived from.
+	Supersedes     []Supersession // Memories the memory replaced.
+	AccessCount    int            // Number of times the memory was retrieved.
+	LastAccessedAt *time.Time     // Last time the memory was retrieved.
 }
 
 // AddOption configures optional parameters for AddMemory.
@@ -88,8 +99,9 @@ func ResolveAddOptions(opts []AddOption) *Metadata {
 type UpdateOption func(*updateOptions)
 
 type updateOptions struct {
-	metadata *Metadata
-	result   *UpdateResult
+	metadata     *Metadata
+	result       *UpdateResult
+	metadataOnly bool
 }
 
 // WithUpdateMetadata attaches episodic metadata to an
@@ -111,6 +123,19 @@ func WithUpdateResult(result *UpdateResult) UpdateOption {
 	return func(o *updateOptions) { o.result = result }
 }
 
+// WithMetadataOnlyUpdate marks an UpdateMemory call that only changes
+// metadata, such as the access statistics saved by consolidation. When the
+// content and topics are unchanged, services keep the stored embedding and
+// the update time of the memory.
+func WithMetadataOnlyUpdate() UpdateOption {
+	return func(o *updateOptions) { o.metadataOnly = true }
+}
+
+// ResolveMetadataOnlyUpdate reports whether WithMetadataOnlyUpdate is set.
+func ResolveMetadataOnlyUpdate(opts []UpdateOption) bool {
+	return resolveUpdateConfig(opts).metadataOnly
+}
+
 // ResolveUpdateOptions applies UpdateOption funcs and
 // returns the aggregated metadata pointer (may be nil).
 func ResolveUpdateOptions(
@@ -238,6 +263,29 @@ type Memory struct {
 	EventTime    *time.Time `json:"event_time,omitempty"`   // When the event occurred.
 	Participants []string   `json:"participants,omitempty"` // People involved in the event.
 	Location     string     `json:"location,omitempty"`     // Where the event took place.
+
+	// Consolidation fields. Column-based vector backends store them together
+	// in one JSON column or metadata value.
+	Provenance     []Provenance   `json:"provenance,omitempty"`       // Sources the memory was derived from.
+	Supersedes     []Supersession `json:"supersedes,omitempty"`       // Memories this memory replaced.
+	AccessCount    int            `json:"access_count,omitempty"`     // Number of times the memory was retrieved.
+	LastAccessedAt *time.Time     `json:"last_accessed_at,omitempty"` // Last time the memory was retrieved.
+}
+
+// Provenance records where a memory came from.
+type Provenance struct {
+	SessionID string    `json:"session_id,omitempty"` // Session the memory was extracted from.
+	EventID   string    `json:"event_id,omitempty"`   // Last event included in the extraction.
+	Time      time.Time `json:"time"`                 // When the memory was recorded.
+}
+
+// Supersession records a memory that was merged into or replaced by another
+// one, keeping its content for audit after the original is deleted.
+type Supersession struct {
+	MemoryID string    `json:"memory_id"`        // ID of the superseded memory.
+	Memory   string    `json:"memory"`           // Content of the superseded memory.
+	Reason   string    `json:"reason,omitempty"` // Why the memory was superseded.
+	Time     time.Time `json:"time"`             // When the memory was superseded.
 }
 
 // Entry represents a memory entry stored in the system.
@@ -331,6 +379,9 @@ func checkMemoryKey(appName, userID, memoryID string) error {
 	if userID == "" {
 		return ErrUserIDRequired
 	}
+	if err := checkStoredUserID(userID); err != nil {
+		return err
+	}
 	if memoryID == "" {
 		return ErrMemoryIDRequired
 	}
@@ -344,5 +395,5 @@ func checkUserKey(appName, userID string) error {
 	if userID == "" {
 		return ErrUserIDRequired
 	}
-	return nil
+	return checkStoredUserID(userID)
 }
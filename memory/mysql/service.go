@@ -212,6 +212,9 @@ func (s *Service) UpdateMemory(ctx context.Context, memoryKey memory.Key,
 	imemory.NormalizeEntry(entry)
 
 	now := time.Now()
+	if imemory.KeepsContent(entry, memoryStr, topics, opts) {
+		now = entry.UpdatedAt
+	}
 	newID := imemory.ApplyMemoryUpdate(
 		entry,
 		memoryKey.AppName,
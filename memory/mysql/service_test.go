@@ -2515,3 +2515,27 @@ func TestService_UpdateMemory_HardDeleteRollsBackWhenSourceBecomesTombstone(
 	require.ErrorContains(t, err, "not found")
 	require.Equal(t, "unchanged", result.MemoryID)
 }
+
+// TestAddMemory_SharedScopes tests that shared scopes are stored under
+// their subject ID and that other reserved user IDs are rejected.
+func TestAddMemory_SharedScopes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	s := setupMockService(t, db)
+
+	ctx := context.Background()
+	groupKey := memory.Scope{Kind: memory.ScopeGroup, ID: "team"}.UserKey("app")
+
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs("app", "scope:group:team").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO").
+		WithArgs("app", "scope:group:team", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, s.AddMemory(ctx, groupKey, "team memory", nil))
+
+	err := s.AddMemory(ctx, memory.UserKey{AppName: "app", UserID: "scope:other"}, "x", nil)
+	assert.ErrorIs(t, err, memory.ErrReservedUserID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
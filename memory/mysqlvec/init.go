@@ -33,6 +33,7 @@ const (
 			event_time TIMESTAMP(6) NULL,
 			participants JSON,
 			location VARCHAR(1024) NULL,
+			consolidation JSON,
 			created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
 			updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
 			deleted_at TIMESTAMP(6) NULL DEFAULT NULL,
@@ -57,6 +58,7 @@ const (
 			event_time TIMESTAMP(6) NULL,
 			participants JSON,
 			location VARCHAR(1024) NULL,
+			consolidation JSON,
 			created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
 			updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
 			deleted_at TIMESTAMP(6) NULL DEFAULT NULL,
@@ -101,19 +103,20 @@ func (s *Service) initDB(ctx context.Context) error {
 	}
 	log.InfofContext(ctx, "created table: %s", s.tableName)
 
-	// Add episodic columns for migration from older schemas.
+	// Add episodic and consolidation columns for migration from older schemas.
 	// MySQL does not support ADD COLUMN IF NOT EXISTS, so we use
 	// plain ADD COLUMN and silently ignore error 1060 (Duplicate column name).
-	episodicColumns := []string{
+	migrationColumns := []string{
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN memory_kind VARCHAR(32) NOT NULL DEFAULT 'fact'", s.tableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN event_time TIMESTAMP(6) NULL", s.tableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN participants JSON", s.tableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN location VARCHAR(1024) NULL", s.tableName),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN consolidation JSON", s.tableName),
 	}
-	for _, ddl := range episodicColumns {
+	for _, ddl := range migrationColumns {
 		if _, err := s.db.Exec(ctx, ddl); err != nil {
 			if !isDuplicateColumnError(err) {
-				return fmt.Errorf("add episodic column on table %s failed: %w", s.tableName, err)
+				return fmt.Errorf("add column on table %s failed: %w", s.tableName, err)
 			}
 		}
 	}
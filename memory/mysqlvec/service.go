@@ -35,7 +35,8 @@ var _ memory.Service = (*Service)(nil)
 //
 //	Table: memories (configurable).
 //	Columns: memory_id, app_name, user_id, memory_content, topics, embedding,
-//	         memory_kind, event_time, participants, location, created_at, updated_at, deleted_at.
+//	         memory_kind, event_time, participants, location, consolidation,
+//	         created_at, updated_at, deleted_at.
 //	Primary key: memory_id.
 //	Indexes: (app_name, user_id), updated_at, deleted_at, event_time, kind, fulltext(memory_content).
 type Service struct {
@@ -212,9 +213,9 @@ func (s *Service) AddMemory(
 
 	insertQuery := fmt.Sprintf(
 		"INSERT INTO %s (memory_id, app_name, user_id, memory_content, topics, "+
-			"embedding, memory_kind, event_time, participants, location, "+
+			"embedding, memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at) "+
-			"VALUES (?, ?, ?, ?, ?, "+embeddingExpr+", ?, ?, ?, ?, ?, ?) "+
+			"VALUES (?, ?, ?, ?, ?, "+embeddingExpr+", ?, ?, ?, ?, ?, ?, ?) "+
 			"ON DUPLICATE KEY UPDATE "+
 			"memory_content = VALUES(memory_content), "+
 			"topics = VALUES(topics), "+
@@ -223,6 +224,7 @@ func (s *Service) AddMemory(
 			"event_time = VALUES(event_time), "+
 			"participants = VALUES(participants), "+
 			"location = VALUES(location), "+
+			"consolidation = VALUES(consolidation), "+
 			"deleted_at = NULL, "+
 			"updated_at = VALUES(updated_at)",
 		s.tableName,
@@ -239,6 +241,7 @@ func (s *Service) AddMemory(
 		ef.eventTime,
 		ef.participants,
 		ef.location,
+		ef.consolidation,
 		now,
 		now,
 	)
@@ -264,7 +267,7 @@ func (s *Service) UpdateMemory(
 
 	selectQuery := fmt.Sprintf(
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at FROM %s WHERE memory_id = ? AND app_name = ? AND user_id = ? "+
 			"AND deleted_at IS NULL",
 		s.tableName,
@@ -288,16 +291,7 @@ func (s *Service) UpdateMemory(
 		return fmt.Errorf("memory with id %s not found", memoryKey.MemoryID)
 	}
 
-	// Generate new embedding for the updated content.
-	embedding, err := s.opts.embedder.GetEmbedding(ctx, memoryStr)
-	if err != nil {
-		return fmt.Errorf("generate embedding failed: %w", err)
-	}
-	if len(embedding) != s.opts.indexDimension {
-		return fmt.Errorf("embedding dimension mismatch: expected %d, got %d",
-			s.opts.indexDimension, len(embedding))
-	}
-
+	keep := imemory.KeepsContent(entry, memoryStr, topics, opts)
 	now := time.Now()
 	newID := imemory.ApplyMemoryUpdate(
 		entry,
@@ -308,12 +302,31 @@ func (s *Service) UpdateMemory(
 		ep,
 		now,
 	)
+	ef := resolveMetadata(entry.Memory)
+	if keep && newID == memoryKey.MemoryID {
+		if err := s.updateConsolidation(ctx, memoryKey, ef.consolidation); err != nil {
+			return err
+		}
+		if result := memory.ResolveUpdateResult(opts); result != nil {
+			result.MemoryID = newID
+		}
+		return nil
+	}
+
+	// Generate new embedding for the updated content.
+	embedding, err := s.opts.embedder.GetEmbedding(ctx, memoryStr)
+	if err != nil {
+		return fmt.Errorf("generate embedding failed: %w", err)
+	}
+	if len(embedding) != s.opts.indexDimension {
+		return fmt.Errorf("embedding dimension mismatch: expected %d, got %d",
+			s.opts.indexDimension, len(embedding))
+	}
 
 	topicsJSON, err := json.Marshal(topics)
 	if err != nil {
 		return fmt.Errorf("marshal topics failed: %w", err)
 	}
-	ef := resolveMetadata(entry.Memory)
 
 	var embeddingExpr string
 	var embeddingArg any
@@ -354,13 +367,13 @@ func (s *Service) updateInPlace(
 ) error {
 	updateQuery := fmt.Sprintf(
 		"UPDATE %s SET memory_content = ?, topics = ?, embedding = "+embeddingExpr+", "+
-			"memory_kind = ?, event_time = ?, participants = ?, location = ?, updated_at = ? "+
+			"memory_kind = ?, event_time = ?, participants = ?, location = ?, consolidation = ?, updated_at = ? "+
 			"WHERE memory_id = ? AND app_name = ? AND user_id = ? AND deleted_at IS NULL",
 		s.tableName,
 	)
 	res, err := s.db.Exec(ctx, updateQuery,
 		memoryStr, string(topicsJSON), embeddingArg,
-		ef.kind, ef.eventTime, ef.participants, ef.location, now,
+		ef.kind, ef.eventTime, ef.participants, ef.location, ef.consolidation, now,
 		memoryKey.MemoryID, memoryKey.AppName, memoryKey.UserID,
 	)
 	if err != nil {
@@ -376,6 +389,33 @@ func (s *Service) updateInPlace(
 	return nil
 }
 
+// updateConsolidation saves the consolidation fields of a memory whose
+// content is unchanged, keeping its embedding and update time.
+func (s *Service) updateConsolidation(
+	ctx context.Context,
+	memoryKey memory.Key,
+	consolidation *string,
+) error {
+	updateQuery := fmt.Sprintf(
+		"UPDATE %s SET consolidation = ? "+
+			"WHERE memory_id = ? AND app_name = ? AND user_id = ? AND deleted_at IS NULL",
+		s.tableName,
+	)
+	res, err := s.db.Exec(ctx, updateQuery, consolidation,
+		memoryKey.MemoryID, memoryKey.AppName, memoryKey.UserID)
+	if err != nil {
+		return fmt.Errorf("update memory consolidation failed: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update memory consolidation rows affected failed: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("memory with id %s not found", memoryKey.MemoryID)
+	}
+	return nil
+}
+
 // rotateMemory replaces a memory entry with a new ID in a transaction.
 //
 //nolint:gosec // All interpolated table names are validated by WithTableName.
@@ -418,7 +458,7 @@ func (s *Service) rotateMemory(
 			updateTargetQuery := fmt.Sprintf(
 				"UPDATE %s SET memory_content = ?, topics = ?, embedding = "+embeddingExpr+", "+
 					"memory_kind = ?, event_time = ?, participants = ?, location = ?, "+
-					"deleted_at = NULL, updated_at = ? "+
+					"consolidation = ?, deleted_at = NULL, updated_at = ? "+
 					"WHERE memory_id = ? AND app_name = ? AND user_id = ? "+
 					"AND deleted_at IS NOT NULL",
 				s.tableName,
@@ -433,6 +473,7 @@ func (s *Service) rotateMemory(
 				ef.eventTime,
 				ef.participants,
 				ef.location,
+				ef.consolidation,
 				now,
 				newID,
 				memoryKey.AppName,
@@ -477,9 +518,9 @@ func (s *Service) rotateMemory(
 		if insertTarget {
 			insertQuery := fmt.Sprintf(
 				"INSERT INTO %s (memory_id, app_name, user_id, memory_content, topics, "+
-					"embedding, memory_kind, event_time, participants, location, "+
+					"embedding, memory_kind, event_time, participants, location, consolidation, "+
 					"created_at, updated_at) "+
-					"VALUES (?, ?, ?, ?, ?, "+embeddingExpr+", ?, ?, ?, ?, ?, ?)",
+					"VALUES (?, ?, ?, ?, ?, "+embeddingExpr+", ?, ?, ?, ?, ?, ?, ?)",
 				s.tableName,
 			)
 			if _, err := tx.ExecContext(
@@ -495,6 +536,7 @@ func (s *Service) rotateMemory(
 				ef.eventTime,
 				ef.participants,
 				ef.location,
+				ef.consolidation,
 				createdAt,
 				now,
 			); err != nil {
@@ -613,7 +655,7 @@ func (s *Service) ReadMemories(
 	var query strings.Builder
 	fmt.Fprintf(&query,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at FROM %s WHERE app_name = ? AND user_id = ?",
 		s.tableName,
 	)
@@ -802,7 +844,7 @@ func (s *Service) vectorSearch(
 
 	fmt.Fprintf(&searchQuery,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at, "+
 			"(1 - DISTANCE(embedding, STRING_TO_VECTOR(?), 'COSINE')) AS similarity "+
 			"FROM %s WHERE app_name = ? AND user_id = ?",
@@ -866,7 +908,7 @@ func (s *Service) bruteForceSearch(
 
 	fmt.Fprintf(&searchQuery,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at, embedding FROM %s "+
 			"WHERE app_name = ? AND user_id = ?",
 		s.tableName,
@@ -955,7 +997,7 @@ func (s *Service) executeKeywordSearch(
 
 	fmt.Fprintf(&searchQuery,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at, "+
 			"MATCH(memory_content) AGAINST(? IN NATURAL LANGUAGE MODE) AS relevance "+
 			"FROM %s WHERE app_name = ? AND user_id = ? "+
@@ -1045,6 +1087,7 @@ func scanEntryFromRows(rows *sql.Rows) (*memory.Entry, error) {
 		eventTime        sql.NullTime
 		participantsJSON sql.NullString
 		location         sql.NullString
+		consolidation    sql.NullString
 		createdAt        sql.NullTime
 		updatedAt        sql.NullTime
 	)
@@ -1052,7 +1095,7 @@ func scanEntryFromRows(rows *sql.Rows) (*memory.Entry, error) {
 	if err := rows.Scan(
 		&memoryID, &appName, &userID, &memoryContent,
 		&topicsJSON, &memoryKind, &eventTime,
-		&participantsJSON, &location,
+		&participantsJSON, &location, &consolidation,
 		&createdAt, &updatedAt,
 	); err != nil {
 		return nil, fmt.Errorf("scan memory entry failed: %w", err)
@@ -1060,7 +1103,7 @@ func scanEntryFromRows(rows *sql.Rows) (*memory.Entry, error) {
 
 	return buildEntry(memoryID, appName, userID, memoryContent,
 		topicsJSON, memoryKind, eventTime, participantsJSON,
-		location, createdAt, updatedAt), nil
+		location, consolidation, createdAt, updatedAt), nil
 }
 
 // scanEntryWithSimilarityFromRows scans a memory entry with a similarity score.
@@ -1075,6 +1118,7 @@ func scanEntryWithSimilarityFromRows(rows *sql.Rows) (*memory.Entry, error) {
 		eventTime        sql.NullTime
 		participantsJSON sql.NullString
 		location         sql.NullString
+		consolidation    sql.NullString
 		createdAt        sql.NullTime
 		updatedAt        sql.NullTime
 		similarity       float64
@@ -1083,7 +1127,7 @@ func scanEntryWithSimilarityFromRows(rows *sql.Rows) (*memory.Entry, error) {
 	if err := rows.Scan(
 		&memoryID, &appName, &userID, &memoryContent,
 		&topicsJSON, &memoryKind, &eventTime,
-		&participantsJSON, &location,
+		&participantsJSON, &location, &consolidation,
 		&createdAt, &updatedAt,
 		&similarity,
 	); err != nil {
@@ -1092,7 +1136,7 @@ func scanEntryWithSimilarityFromRows(rows *sql.Rows) (*memory.Entry, error) {
 
 	entry := buildEntry(memoryID, appName, userID, memoryContent,
 		topicsJSON, memoryKind, eventTime, participantsJSON,
-		location, createdAt, updatedAt)
+		location, consolidation, createdAt, updatedAt)
 	entry.Score = similarity
 	return entry, nil
 }
@@ -1109,6 +1153,7 @@ func scanEntryWithEmbeddingFromRows(rows *sql.Rows) (*memory.Entry, []byte, erro
 		eventTime        sql.NullTime
 		participantsJSON sql.NullString
 		location         sql.NullString
+		consolidation    sql.NullString
 		createdAt        sql.NullTime
 		updatedAt        sql.NullTime
 		embedding        []byte
@@ -1117,7 +1162,7 @@ func scanEntryWithEmbeddingFromRows(rows *sql.Rows) (*memory.Entry, []byte, erro
 	if err := rows.Scan(
 		&memoryID, &appName, &userID, &memoryContent,
 		&topicsJSON, &memoryKind, &eventTime,
-		&participantsJSON, &location,
+		&participantsJSON, &location, &consolidation,
 		&createdAt, &updatedAt,
 		&embedding,
 	); err != nil {
@@ -1126,7 +1171,7 @@ func scanEntryWithEmbeddingFromRows(rows *sql.Rows) (*memory.Entry, []byte, erro
 
 	entry := buildEntry(memoryID, appName, userID, memoryContent,
 		topicsJSON, memoryKind, eventTime, participantsJSON,
-		location, createdAt, updatedAt)
+		location, consolidation, createdAt, updatedAt)
 	return entry, embedding, nil
 }
 
@@ -1138,6 +1183,7 @@ func buildEntry(
 	eventTime sql.NullTime,
 	participantsJSON sql.NullString,
 	location sql.NullString,
+	consolidation sql.NullString,
 	createdAt, updatedAt sql.NullTime,
 ) *memory.Entry {
 	topics := parseJSONStringSlice(topicsJSON.String)
@@ -1164,6 +1210,7 @@ func buildEntry(
 	if location.Valid {
 		mem.Location = location.String
 	}
+	_ = imemory.UnmarshalConsolidation(mem, consolidation.String)
 	imemory.NormalizeMemory(mem)
 
 	return &memory.Entry{
@@ -1178,10 +1225,11 @@ func buildEntry(
 
 // metadataSQLFields holds metadata field values resolved for SQL parameters.
 type metadataSQLFields struct {
-	kind         string
-	eventTime    *time.Time
-	participants *string
-	location     *string
+	kind          string
+	eventTime     *time.Time
+	participants  *string
+	location      *string
+	consolidation *string
 }
 
 // resolveMetadata converts a stored memory object to SQL-ready metadata values.
@@ -1202,6 +1250,9 @@ func resolveMetadata(mem *memory.Memory) metadataSQLFields {
 		location := mem.Location
 		f.location = &location
 	}
+	if data, _ := imemory.MarshalConsolidation(mem); data != "" {
+		f.consolidation = &data
+	}
 	return f
 }
 
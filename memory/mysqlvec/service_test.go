@@ -151,7 +151,7 @@ func setupMockService(t *testing.T, db *sql.DB, mock sqlmock.Sqlmock, opts ...Se
 	if !testOpts.skipDBInit {
 		mock.ExpectQuery("SELECT 1 FROM").WillReturnError(fmt.Errorf("no vector"))
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
-		for i := 0; i < 4; i++ {
+		for i := 0; i < 5; i++ {
 			mock.ExpectExec("ALTER TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
 		}
 	} else {
@@ -178,7 +178,7 @@ func setupMockService(t *testing.T, db *sql.DB, mock sqlmock.Sqlmock, opts ...Se
 // standard mock row columns
 var memCols = []string{
 	"memory_id", "app_name", "user_id", "memory_content", "topics",
-	"memory_kind", "event_time", "participants", "location",
+	"memory_kind", "event_time", "participants", "location", "consolidation",
 	"created_at", "updated_at",
 }
 
@@ -584,7 +584,7 @@ func expectUpdateLoad(mock sqlmock.Sqlmock, key memory.Key, softDelete bool) tim
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "old memory", `["old"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	return now
 }
@@ -628,6 +628,7 @@ func TestService_UpdateMemory(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 			updateTimeMatcher{want: sourceCreatedAt},
 			sqlmock.AnyArg(),
 		).
@@ -653,7 +654,7 @@ func TestService_UpdateMemory_IDChanged_RotateMemory(t *testing.T) {
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "old content", `["old"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	// Content changed → new ID → rotateMemory: BEGIN + pre-check + INSERT + DELETE + COMMIT.
 	mock.ExpectBegin()
@@ -736,7 +737,7 @@ func TestService_UpdateMemory_SameID_UpdateInPlace(t *testing.T) {
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "same content", `["same"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -785,7 +786,7 @@ func TestService_UpdateMemory_SoftDelete_RotateMemory_ReviveDeletedRow(t *testin
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	// Content changes → new ID "mem-B" → rotateMemory:
 	// BEGIN + pre-check finds B soft-deleted (not active) + revive B +
@@ -812,6 +813,7 @@ func TestService_UpdateMemory_SoftDelete_RotateMemory_ReviveDeletedRow(t *testin
 			nil,
 			nil,
 			nil,
+			nil,
 			sqlmock.AnyArg(),
 			targetID,
 			key.AppName,
@@ -846,7 +848,7 @@ func TestService_UpdateMemory_SoftDelete_RotateMemory_TargetNotExist(t *testing.
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	// Content changes → new ID "mem-B" → rotateMemory:
 	// BEGIN + pre-check finds B does not exist (sql.ErrNoRows) + INSERT B +
@@ -880,7 +882,7 @@ func TestService_UpdateMemory_RotateMemory_ConcurrentTargetInsertRollsBack(t *te
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT deleted_at IS NULL FROM memories").
@@ -927,7 +929,7 @@ func TestService_UpdateMemory_SoftDelete_RotateMemory_ActiveRowConflict(t *testi
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	// Content changes → new ID "mem-B" → rotateMemory:
 	// BEGIN + pre-check finds target is active → error → ROLLBACK.
@@ -963,7 +965,7 @@ func TestService_UpdateMemory_HardDelete_RotateMemory_DeletedTargetCleanup(t *te
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	targetID := imemory.GenerateMemoryID(
 		&memory.Memory{
@@ -993,6 +995,7 @@ func TestService_UpdateMemory_HardDelete_RotateMemory_DeletedTargetCleanup(t *te
 			nil,
 			nil,
 			nil,
+			nil,
 			updateTimeMatcher{want: now},
 			sqlmock.AnyArg(),
 		).
@@ -1027,7 +1030,7 @@ func TestService_UpdateMemory_HardDelete_RotateMemory_ActiveRowConflict(t *testi
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "content A", `["topic"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	targetID := imemory.GenerateMemoryID(
 		&memory.Memory{
@@ -1208,7 +1211,7 @@ func TestService_UpdateMemory_InPlace_SQLError(t *testing.T) {
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "same", `["s"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	mock.ExpectExec("UPDATE").WillReturnError(fmt.Errorf("update fail"))
 
@@ -1230,7 +1233,7 @@ func TestService_UpdateMemory_InPlace_RowsAffectedError(t *testing.T) {
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "same", `["s"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("rows err")))
 
@@ -1252,7 +1255,7 @@ func TestService_UpdateMemory_InPlace_ZeroRowsAffected(t *testing.T) {
 		WithArgs(key.MemoryID, key.AppName, key.UserID).
 		WillReturnRows(sqlmock.NewRows(memCols).AddRow(
 			key.MemoryID, key.AppName, key.UserID, "same", `["s"]`,
-			"fact", nil, nil, nil, now, now,
+			"fact", nil, nil, nil, nil, now, now,
 		))
 	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -1367,8 +1370,8 @@ func TestService_ReadMemories(t *testing.T) {
 	now := time.Now()
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memCols).
-			AddRow("m1", "app", "u1", "memory 1", `["t1"]`, "fact", nil, nil, nil, now, now).
-			AddRow("m2", "app", "u1", "memory 2", `["t2"]`, "fact", nil, nil, nil, now, now),
+			AddRow("m1", "app", "u1", "memory 1", `["t1"]`, "fact", nil, nil, nil, nil, now, now).
+			AddRow("m2", "app", "u1", "memory 2", `["t2"]`, "fact", nil, nil, nil, nil, now, now),
 	)
 
 	entries, err := svc.ReadMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, 10)
@@ -1429,7 +1432,7 @@ func TestService_ReadMemories_ScanError(t *testing.T) {
 	defer svc.Close()
 
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
-		sqlmock.NewRows(memCols).AddRow("m", "a", "u", "x", nil, "fact", nil, nil, nil, "bad-time", "bad"),
+		sqlmock.NewRows(memCols).AddRow("m", "a", "u", "x", nil, "fact", nil, nil, nil, nil, "bad-time", "bad"),
 	)
 	_, err := svc.ReadMemories(context.Background(), memory.UserKey{AppName: "a", UserID: "u"}, 10)
 	assert.Contains(t, err.Error(), "list memories failed")
@@ -1449,8 +1452,8 @@ func TestService_SearchMemories(t *testing.T) {
 	emb := serializeVector(svc.opts.embedder.(*mockEmbedder).embedding)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "coffee tips", `["hobby"]`, "fact", nil, nil, nil, now, now, emb).
-			AddRow("m2", "app", "u1", "likes coffee", `["profile"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "coffee tips", `["hobby"]`, "fact", nil, nil, nil, nil, now, now, emb).
+			AddRow("m2", "app", "u1", "likes coffee", `["profile"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "coffee")
@@ -1532,9 +1535,9 @@ func TestService_SearchMemories_ThresholdAndDeduplicate(t *testing.T) {
 	blob2 := serializeVector(emb2)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "Alice hiking", `["t"]`, "fact", nil, nil, nil, now, now, blob1).
-			AddRow("m2", "app", "u1", "Alice hiking", `["t"]`, "fact", nil, nil, nil, now, now, blob1). // duplicate content
-			AddRow("m3", "app", "u1", "Different", `["t"]`, "fact", nil, nil, nil, now, now, blob2),
+			AddRow("m1", "app", "u1", "Alice hiking", `["t"]`, "fact", nil, nil, nil, nil, now, now, blob1).
+			AddRow("m2", "app", "u1", "Alice hiking", `["t"]`, "fact", nil, nil, nil, nil, now, now, blob1). // duplicate content
+			AddRow("m3", "app", "u1", "Different", `["t"]`, "fact", nil, nil, nil, nil, now, now, blob2),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "hiking",
@@ -1559,13 +1562,13 @@ func TestService_SearchMemories_KindFallback(t *testing.T) {
 	// First query (filtered by kind) returns 1 result (< minKindFallbackResults).
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "Episode", `["t"]`, "episode", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "Episode", `["t"]`, "episode", nil, nil, nil, nil, now, now, emb),
 	)
 	// Fallback query (no kind filter) returns more.
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m2", "app", "u1", "Fact", `["t"]`, "fact", nil, nil, nil, now, now, emb).
-			AddRow("m3", "app", "u1", "Another fact", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m2", "app", "u1", "Fact", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb).
+			AddRow("m3", "app", "u1", "Another fact", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1591,12 +1594,12 @@ func TestService_SearchMemories_HybridSearch(t *testing.T) {
 	// Vector search.
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "Vector result", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "Vector result", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 	// Keyword search.
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m2", "app", "u1", "Keyword result", `["t"]`, "fact", nil, nil, nil, now, now, 0.5),
+			AddRow("m2", "app", "u1", "Keyword result", `["t"]`, "fact", nil, nil, nil, nil, now, now, 0.5),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "coffee",
@@ -1620,7 +1623,7 @@ func TestService_SearchMemories_KindFilter(t *testing.T) {
 	emb := serializeVector(svc.opts.embedder.(*mockEmbedder).embedding)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "Fact", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "Fact", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1646,7 +1649,7 @@ func TestService_SearchMemories_TimeFilters(t *testing.T) {
 	emb := serializeVector(svc.opts.embedder.(*mockEmbedder).embedding)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1670,7 +1673,7 @@ func TestService_SearchMemories_SoftDelete(t *testing.T) {
 	emb := serializeVector(svc.opts.embedder.(*mockEmbedder).embedding)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q")
@@ -1688,9 +1691,9 @@ func TestService_SearchMemories_MaxResults(t *testing.T) {
 	emb := serializeVector(svc.opts.embedder.(*mockEmbedder).embedding)
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "app", "u1", "r1", `["t"]`, "fact", nil, nil, nil, now, now, emb).
-			AddRow("m2", "app", "u1", "r2", `["t"]`, "fact", nil, nil, nil, now, now, emb).
-			AddRow("m3", "app", "u1", "r3", `["t"]`, "fact", nil, nil, nil, now, now, emb),
+			AddRow("m1", "app", "u1", "r1", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb).
+			AddRow("m2", "app", "u1", "r2", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb).
+			AddRow("m3", "app", "u1", "r3", `["t"]`, "fact", nil, nil, nil, nil, now, now, emb),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1740,7 +1743,7 @@ func TestExecuteKeywordSearch_WithKindAndTimeFilters(t *testing.T) {
 	now := time.Now()
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m1", "a", "u", "result", `["t"]`, "episode", now, nil, nil, now, now, 0.8),
+			AddRow("m1", "a", "u", "result", `["t"]`, "episode", now, nil, nil, nil, now, now, 0.8),
 	)
 
 	results, err := svc.executeKeywordSearch(context.Background(),
@@ -1857,7 +1860,7 @@ func TestBuildEntry(t *testing.T) {
 	now := time.Now()
 	entry := buildEntry("m1", "app", "user", "content",
 		sql.NullString{}, "fact",
-		sql.NullTime{}, sql.NullString{}, sql.NullString{},
+		sql.NullTime{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 		sql.NullTime{Valid: true, Time: now}, sql.NullTime{Valid: true, Time: now})
 	assert.Equal(t, "m1", entry.ID)
 	assert.Equal(t, "content", entry.Memory.Memory)
@@ -1871,6 +1874,7 @@ func TestBuildEntry_WithEpisodicFields(t *testing.T) {
 		sql.NullTime{Valid: true, Time: now},
 		sql.NullString{Valid: true, String: `["Alice"]`},
 		sql.NullString{Valid: true, String: "Kyoto"},
+		sql.NullString{},
 		sql.NullTime{Valid: true, Time: now}, sql.NullTime{Valid: true, Time: now})
 	assert.Equal(t, memory.KindEpisode, entry.Memory.Kind)
 	assert.Equal(t, "Kyoto", entry.Memory.Location)
@@ -1878,6 +1882,28 @@ func TestBuildEntry_WithEpisodicFields(t *testing.T) {
 	assert.Equal(t, []string{"Alice"}, entry.Memory.Participants)
 }
 
+func TestConsolidationFieldsRoundTrip(t *testing.T) {
+	now := time.Now()
+	assert.Nil(t, resolveMetadata(&memory.Memory{Memory: "m"}).consolidation)
+
+	f := resolveMetadata(&memory.Memory{
+		Memory:      "m",
+		Provenance:  []memory.Provenance{{SessionID: "s1", EventID: "e1"}},
+		Supersedes:  []memory.Supersession{{MemoryID: "old", Memory: "older"}},
+		AccessCount: 2,
+	})
+	require.NotNil(t, f.consolidation)
+
+	entry := buildEntry("m3", "app", "user", "m",
+		sql.NullString{}, "fact",
+		sql.NullTime{}, sql.NullString{}, sql.NullString{},
+		sql.NullString{Valid: true, String: *f.consolidation},
+		sql.NullTime{Valid: true, Time: now}, sql.NullTime{Valid: true, Time: now})
+	assert.Equal(t, []memory.Provenance{{SessionID: "s1", EventID: "e1"}}, entry.Memory.Provenance)
+	assert.Equal(t, []memory.Supersession{{MemoryID: "old", Memory: "older"}}, entry.Memory.Supersedes)
+	assert.Equal(t, 2, entry.Memory.AccessCount)
+}
+
 // ---------------------------------------------------------------------------
 // vectorSearch path tests (supportsVector=true)
 // ---------------------------------------------------------------------------
@@ -1891,7 +1917,7 @@ func TestService_VectorSearch_Path(t *testing.T) {
 	now := time.Now()
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, now, now, 0.9),
+			AddRow("m1", "app", "u1", "result", `["t"]`, "fact", nil, nil, nil, nil, now, now, 0.9),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q")
@@ -1922,7 +1948,7 @@ func TestService_VectorSearch_WithFilters(t *testing.T) {
 	now := time.Now()
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m1", "app", "u1", "episode", `["t"]`, "episode", now, nil, nil, now, now, 0.85),
+			AddRow("m1", "app", "u1", "episode", `["t"]`, "episode", now, nil, nil, nil, now, now, 0.85),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1946,7 +1972,7 @@ func TestService_VectorSearch_KindFact(t *testing.T) {
 	now := time.Now()
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m1", "app", "u1", "fact", `["t"]`, "fact", nil, nil, nil, now, now, 0.8),
+			AddRow("m1", "app", "u1", "fact", `["t"]`, "fact", nil, nil, nil, nil, now, now, 0.8),
 	)
 
 	results, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "app", UserID: "u1"}, "q",
@@ -1967,7 +1993,7 @@ func TestService_VectorSearch_ScanError(t *testing.T) {
 
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithSimilarity).
-			AddRow("m1", "a", "u", "x", nil, "fact", nil, nil, nil, "bad-time", "bad", 0.9),
+			AddRow("m1", "a", "u", "x", nil, "fact", nil, nil, nil, nil, "bad-time", "bad", 0.9),
 	)
 	_, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "a", UserID: "u"}, "q")
 	assert.Error(t, err)
@@ -1992,7 +2018,7 @@ func TestNewService_InitDB_VectorPath(t *testing.T) {
 	mock.ExpectQuery("SELECT 1 FROM").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 	// initDB with VECTOR type.
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 5; i++ {
 		mock.ExpectExec("ALTER TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
 	}
 
@@ -2029,7 +2055,7 @@ func TestNewService_InitDB_AlterTableError(t *testing.T) {
 		WithIndexDimension(16),
 	)
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "add episodic column")
+	assert.Contains(t, err.Error(), "add column")
 }
 
 // ---------------------------------------------------------------------------
@@ -2059,7 +2085,7 @@ func TestService_ScanEntryWithEmbedding_Error(t *testing.T) {
 
 	mock.ExpectQuery("SELECT memory_id").WillReturnRows(
 		sqlmock.NewRows(memColsWithEmbedding).
-			AddRow("m1", "a", "u", "x", nil, "fact", nil, nil, nil, "bad-time", "bad", []byte{1, 2}),
+			AddRow("m1", "a", "u", "x", nil, "fact", nil, nil, nil, nil, "bad-time", "bad", []byte{1, 2}),
 	)
 	_, err := svc.SearchMemories(context.Background(), memory.UserKey{AppName: "a", UserID: "u"}, "q")
 	assert.Contains(t, err.Error(), "brute force search memories failed")
@@ -33,6 +33,7 @@ const (
 		"event_time TIMESTAMP NULL," +
 		"participants TEXT[]," +
 		"location TEXT NULL," +
+		"consolidation JSONB NULL," +
 		"created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP," +
 		"updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP," +
 		"deleted_at TIMESTAMP NULL DEFAULT NULL" +
@@ -158,18 +159,20 @@ func (s *Service) initDB(ctx context.Context) error {
 	}
 	log.InfofContext(ctx, "created table: %s", fullTableName)
 
-	// Migrate existing tables: add episodic columns if they don't exist.
+	// Migrate existing tables: add episodic and consolidation columns if they
+	// don't exist.
 	// This is safe to run on both new and existing tables because
 	// ADD COLUMN IF NOT EXISTS is a no-op when the column already exists.
-	episodicColumns := []string{
+	migrationColumns := []string{
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS memory_kind TEXT NOT NULL DEFAULT 'fact'", fullTableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS event_time TIMESTAMP NULL", fullTableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS participants TEXT[]", fullTableName),
 		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS location TEXT NULL", fullTableName),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS consolidation JSONB NULL", fullTableName),
 	}
-	for _, ddl := range episodicColumns {
+	for _, ddl := range migrationColumns {
 		if _, err := s.db.ExecContext(ctx, ddl); err != nil {
-			return fmt.Errorf("add episodic column on table %s failed: %w", fullTableName, err)
+			return fmt.Errorf("add column on table %s failed: %w", fullTableName, err)
 		}
 	}
 
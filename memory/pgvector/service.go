@@ -225,8 +225,9 @@ func (s *Service) AddMemory(
 		ef.eventTime,              // $8
 		pq.Array(ef.participants), // $9
 		ef.location,               // $10
-		now,                       // $11
+		ef.consolidation,          // $11
 		now,                       // $12
+		now,                       // $13
 	}
 	if s.opts.memoryLimit > 0 {
 		deletedFilter := ""
@@ -237,7 +238,7 @@ func (s *Service) AddMemory(
 		// remove the least-recently-updated entry to make room.
 		var evictAction string
 		if s.opts.softDelete {
-			evictAction = fmt.Sprintf("UPDATE %s SET deleted_at = $11", s.tableName)
+			evictAction = fmt.Sprintf("UPDATE %s SET deleted_at = $12", s.tableName)
 		} else {
 			evictAction = fmt.Sprintf("DELETE FROM %s", s.tableName)
 		}
@@ -248,7 +249,7 @@ func (s *Service) AddMemory(
 				"WHERE app_name = $2 AND user_id = $3%s "+
 				"ORDER BY updated_at ASC LIMIT 1) "+
 				"AND NOT EXISTS (SELECT 1 FROM existing) "+
-				"AND (SELECT c FROM cnt) >= $13 "+
+				"AND (SELECT c FROM cnt) >= $14 "+
 				"RETURNING memory_id)",
 			evictAction, s.tableName, deletedFilter,
 		)
@@ -261,11 +262,11 @@ func (s *Service) AddMemory(
 				"WHERE app_name = $2 AND user_id = $3%s"+
 				")%s "+
 				"INSERT INTO %s (memory_id, app_name, user_id, memory_content, topics, "+
-				"embedding, memory_kind, event_time, participants, location, "+
+				"embedding, memory_kind, event_time, participants, location, consolidation, "+
 				"created_at, updated_at) "+
-				"SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12 "+
+				"SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13 "+
 				"WHERE (EXISTS (SELECT 1 FROM existing) OR "+
-				"(SELECT c FROM cnt) < $13 OR "+
+				"(SELECT c FROM cnt) < $14 OR "+
 				"EXISTS (SELECT 1 FROM evict)) "+
 				"ON CONFLICT (memory_id) DO UPDATE SET "+
 				"memory_content = EXCLUDED.memory_content, "+
@@ -275,6 +276,7 @@ func (s *Service) AddMemory(
 				"event_time = EXCLUDED.event_time, "+
 				"participants = EXCLUDED.participants, "+
 				"location = EXCLUDED.location, "+
+				"consolidation = EXCLUDED.consolidation, "+
 				"deleted_at = NULL, "+
 				"updated_at = EXCLUDED.updated_at",
 			s.tableName,
@@ -288,9 +290,9 @@ func (s *Service) AddMemory(
 	} else {
 		insertQuery = fmt.Sprintf(
 			"INSERT INTO %s (memory_id, app_name, user_id, memory_content, topics, "+
-				"embedding, memory_kind, event_time, participants, location, "+
+				"embedding, memory_kind, event_time, participants, location, consolidation, "+
 				"created_at, updated_at) "+
-				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) "+
 				"ON CONFLICT (memory_id) DO UPDATE SET "+
 				"memory_content = EXCLUDED.memory_content, "+
 				"topics = EXCLUDED.topics, "+
@@ -299,6 +301,7 @@ func (s *Service) AddMemory(
 				"event_time = EXCLUDED.event_time, "+
 				"participants = EXCLUDED.participants, "+
 				"location = EXCLUDED.location, "+
+				"consolidation = EXCLUDED.consolidation, "+
 				"deleted_at = NULL, "+
 				"updated_at = EXCLUDED.updated_at",
 			s.tableName,
@@ -339,7 +342,7 @@ func (s *Service) UpdateMemory(
 
 	selectQuery := fmt.Sprintf(
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at FROM %s WHERE memory_id = $1 AND app_name = $2 AND user_id = $3 "+
 			"AND deleted_at IS NULL",
 		s.tableName,
@@ -359,18 +362,8 @@ func (s *Service) UpdateMemory(
 		return fmt.Errorf("load memory entry failed: %w", err)
 	}
 
-	// Generate new embedding for the updated memory content.
-	embedding, err := s.opts.embedder.GetEmbedding(ctx, memoryStr)
-	if err != nil {
-		return fmt.Errorf("generate embedding failed: %w", err)
-	}
-	if len(embedding) != s.opts.indexDimension {
-		return fmt.Errorf("embedding dimension mismatch: expected %d, got %d",
-			s.opts.indexDimension, len(embedding))
-	}
-
+	keep := imemory.KeepsContent(entry, memoryStr, topics, opts)
 	now := time.Now()
-	vector := pgvector.NewVector(convertToFloat32(embedding))
 	newID := imemory.ApplyMemoryUpdate(
 		entry,
 		memoryKey.AppName,
@@ -381,11 +374,32 @@ func (s *Service) UpdateMemory(
 		now,
 	)
 	ef := resolveMetadata(entry.Memory)
+	if keep && newID == memoryKey.MemoryID {
+		if err := s.updateConsolidation(ctx, memoryKey, ef.consolidation); err != nil {
+			return err
+		}
+		if result := memory.ResolveUpdateResult(opts); result != nil {
+			result.MemoryID = newID
+		}
+		return nil
+	}
+
+	// Generate new embedding for the updated memory content.
+	embedding, err := s.opts.embedder.GetEmbedding(ctx, memoryStr)
+	if err != nil {
+		return fmt.Errorf("generate embedding failed: %w", err)
+	}
+	if len(embedding) != s.opts.indexDimension {
+		return fmt.Errorf("embedding dimension mismatch: expected %d, got %d",
+			s.opts.indexDimension, len(embedding))
+	}
+	vector := pgvector.NewVector(convertToFloat32(embedding))
 
 	updateQuery := fmt.Sprintf(
 		"UPDATE %s SET memory_id = $1, memory_content = $2, topics = $3, embedding = $4, "+
-			"memory_kind = $5, event_time = $6, participants = $7, location = $8, updated_at = $9 "+
-			"WHERE memory_id = $10 AND app_name = $11 AND user_id = $12 AND deleted_at IS NULL",
+			"memory_kind = $5, event_time = $6, participants = $7, location = $8, "+
+			"consolidation = $9, updated_at = $10 "+
+			"WHERE memory_id = $11 AND app_name = $12 AND user_id = $13 AND deleted_at IS NULL",
 		s.tableName,
 	)
 	if newID == memoryKey.MemoryID {
@@ -400,6 +414,7 @@ func (s *Service) UpdateMemory(
 			ef.eventTime,
 			pq.Array(ef.participants),
 			ef.location,
+			ef.consolidation,
 			now,
 			memoryKey.MemoryID,
 			memoryKey.AppName,
@@ -438,6 +453,33 @@ func (s *Service) UpdateMemory(
 	return nil
 }
 
+// updateConsolidation saves the consolidation fields of a memory whose
+// content is unchanged, keeping its embedding and update time.
+func (s *Service) updateConsolidation(
+	ctx context.Context,
+	memoryKey memory.Key,
+	consolidation *string,
+) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET consolidation = $1 "+
+			"WHERE memory_id = $2 AND app_name = $3 AND user_id = $4 AND deleted_at IS NULL",
+		s.tableName,
+	)
+	res, err := s.db.ExecContext(ctx, query, consolidation,
+		memoryKey.MemoryID, memoryKey.AppName, memoryKey.UserID)
+	if err != nil {
+		return fmt.Errorf("update memory consolidation failed: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update memory consolidation rows affected failed: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("memory with id %s not found", memoryKey.MemoryID)
+	}
+	return nil
+}
+
 // rotateMemory replaces a memory entry with a new ID in a transaction.
 //
 //nolint:gosec // All interpolated table names are validated by WithTableName.
@@ -479,8 +521,8 @@ func (s *Service) rotateMemory(
 			updateTargetQuery := fmt.Sprintf(
 				"UPDATE %s SET memory_content = $1, topics = $2, embedding = $3, "+
 					"memory_kind = $4, event_time = $5, participants = $6, location = $7, "+
-					"deleted_at = NULL, updated_at = $8 "+
-					"WHERE memory_id = $9 AND app_name = $10 AND user_id = $11 "+
+					"consolidation = $8, deleted_at = NULL, updated_at = $9 "+
+					"WHERE memory_id = $10 AND app_name = $11 AND user_id = $12 "+
 					"AND deleted_at IS NOT NULL",
 				s.tableName,
 			)
@@ -494,6 +536,7 @@ func (s *Service) rotateMemory(
 				ef.eventTime,
 				pq.Array(ef.participants),
 				ef.location,
+				ef.consolidation,
 				now,
 				newID,
 				memoryKey.AppName,
@@ -538,9 +581,9 @@ func (s *Service) rotateMemory(
 		if insertTarget {
 			insertQuery := fmt.Sprintf(
 				"INSERT INTO %s (memory_id, app_name, user_id, memory_content, topics, "+
-					"embedding, memory_kind, event_time, participants, location, "+
+					"embedding, memory_kind, event_time, participants, location, consolidation, "+
 					"created_at, updated_at) "+
-					"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+					"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
 				s.tableName,
 			)
 			if _, err := tx.ExecContext(
@@ -556,6 +599,7 @@ func (s *Service) rotateMemory(
 				ef.eventTime,
 				pq.Array(ef.participants),
 				ef.location,
+				ef.consolidation,
 				createdAt,
 				now,
 			); err != nil {
@@ -683,7 +727,7 @@ func (s *Service) ReadMemories(
 	var query strings.Builder
 	fmt.Fprintf(&query,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at FROM %s WHERE app_name = $1 AND user_id = $2",
 		s.tableName,
 	)
@@ -840,7 +884,7 @@ func (s *Service) executeVectorSearch(
 
 	fmt.Fprintf(&searchQuery,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at, 1 - (embedding <=> $1) AS similarity "+
 			"FROM %s WHERE app_name = $2 AND user_id = $3",
 		s.tableName,
@@ -916,7 +960,7 @@ func (s *Service) executeKeywordSearch(
 
 	fmt.Fprintf(&searchQuery,
 		"SELECT memory_id, app_name, user_id, memory_content, topics, "+
-			"memory_kind, event_time, participants, location, "+
+			"memory_kind, event_time, participants, location, consolidation, "+
 			"created_at, updated_at, "+
 			"ts_rank(search_vector, plainto_tsquery('english', $1)) AS similarity "+
 			"FROM %s WHERE app_name = $2 AND user_id = $3 "+
@@ -1137,13 +1181,14 @@ func scanMemoryEntry(rows *sql.Rows) (*memory.Entry, error) {
 		eventTime     sql.NullTime
 		participants  pq.StringArray
 		location      sql.NullString
+		consolidation sql.NullString
 		createdAt     time.Time
 		updatedAt     time.Time
 	)
 
 	if err := rows.Scan(
 		&memoryID, &appName, &userID, &memoryContent, &topics,
-		&memoryKind, &eventTime, &participants, &location,
+		&memoryKind, &eventTime, &participants, &location, &consolidation,
 		&createdAt, &updatedAt,
 	); err != nil {
 		return nil, fmt.Errorf("scan memory entry failed: %w", err)
@@ -1151,7 +1196,7 @@ func scanMemoryEntry(rows *sql.Rows) (*memory.Entry, error) {
 
 	return buildEntry(memoryID, appName, userID, memoryContent,
 		topics, memoryKind, eventTime, participants, location,
-		createdAt, updatedAt), nil
+		consolidation, createdAt, updatedAt), nil
 }
 
 // scanMemoryEntryWithSimilarity scans a memory entry with similarity score.
@@ -1167,6 +1212,7 @@ func scanMemoryEntryWithSimilarity(rows *sql.Rows) (*memory.Entry, error) {
 		eventTime     sql.NullTime
 		participants  pq.StringArray
 		location      sql.NullString
+		consolidation sql.NullString
 		createdAt     time.Time
 		updatedAt     time.Time
 		similarity    float64
@@ -1174,7 +1220,7 @@ func scanMemoryEntryWithSimilarity(rows *sql.Rows) (*memory.Entry, error) {
 
 	if err := rows.Scan(
 		&memoryID, &appName, &userID, &memoryContent, &topics,
-		&memoryKind, &eventTime, &participants, &location,
+		&memoryKind, &eventTime, &participants, &location, &consolidation,
 		&createdAt, &updatedAt, &similarity,
 	); err != nil {
 		return nil, fmt.Errorf("scan memory entry with similarity failed: %w", err)
@@ -1182,7 +1228,7 @@ func scanMemoryEntryWithSimilarity(rows *sql.Rows) (*memory.Entry, error) {
 
 	entry := buildEntry(memoryID, appName, userID, memoryContent,
 		topics, memoryKind, eventTime, participants, location,
-		createdAt, updatedAt)
+		consolidation, createdAt, updatedAt)
 	entry.Score = similarity
 	return entry, nil
 }
@@ -1195,6 +1241,7 @@ func buildEntry(
 	eventTime sql.NullTime,
 	participants pq.StringArray,
 	location sql.NullString,
+	consolidation sql.NullString,
 	createdAt, updatedAt time.Time,
 ) *memory.Entry {
 	mem := &memory.Memory{
@@ -1212,6 +1259,7 @@ func buildEntry(
 	if location.Valid {
 		mem.Location = location.String
 	}
+	_ = imemory.UnmarshalConsolidation(mem, consolidation.String)
 	imemory.NormalizeMemory(mem)
 
 	return &memory.Entry{
@@ -1236,10 +1284,11 @@ func convertToFloat32(embedding []float64) []float32 {
 // metadataSQLFields holds metadata field values resolved
 // for SQL parameters.
 type metadataSQLFields struct {
-	kind         string
-	eventTime    *time.Time
-	participants []string
-	location     *string
+	kind          string
+	eventTime     *time.Time
+	participants  []string
+	location      *string
+	consolidation *string
 }
 
 // resolveMetadata converts a stored memory object to SQL-ready metadata values.
@@ -1260,5 +1309,8 @@ func resolveMetadata(mem *memory.Memory) metadataSQLFields {
 		location := mem.Location
 		f.location = &location
 	}
+	if data, _ := imemory.MarshalConsolidation(mem); data != "" {
+		f.consolidation = &data
+	}
 	return f
 }
@@ -80,7 +80,7 @@ func expectUpdateLoad(
 	participants []string,
 	location any,
 ) time.Time {
-	query := "SELECT memory_id, app_name, user_id, memory_content, topics, memory_kind, event_time, participants, location, created_at, updated_at FROM memories WHERE memory_id = \\$1 AND app_name = \\$2 AND user_id = \\$3"
+	query := "SELECT memory_id, app_name, user_id, memory_content, topics, memory_kind, event_time, participants, location, consolidation, created_at, updated_at FROM memories WHERE memory_id = \\$1 AND app_name = \\$2 AND user_id = \\$3"
 	if softDelete {
 		query += " AND deleted_at IS NULL"
 	}
@@ -90,7 +90,7 @@ func expectUpdateLoad(
 		WillReturnRows(sqlmock.NewRows(
 			[]string{
 				"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at",
 			},
 		).AddRow(
@@ -102,7 +102,7 @@ func expectUpdateLoad(
 			kind,
 			eventTime,
 			pq.Array(participants),
-			location,
+			location, nil,
 			now,
 			now,
 		))
@@ -899,6 +899,7 @@ func TestService_UpdateMemory(t *testing.T) {
 			nil,
 			sqlmock.AnyArg(),
 			nil,
+			nil,
 			updateTimeMatcher{want: sourceCreatedAt},
 			sqlmock.AnyArg(),
 		).
@@ -1055,6 +1056,7 @@ func TestService_UpdateMemory_SoftDeletedTargetIsRevived(t *testing.T) {
 			nil,
 			sqlmock.AnyArg(),
 			nil,
+			nil,
 			sqlmock.AnyArg(),
 			targetID,
 			memKey.AppName,
@@ -1120,6 +1122,7 @@ func TestService_UpdateMemory_HardDeleteReplacesSoftDeletedTarget(t *testing.T)
 			nil,
 			sqlmock.AnyArg(),
 			nil,
+			nil,
 			updateTimeMatcher{want: sourceCreatedAt},
 			sqlmock.AnyArg(),
 		).
@@ -1339,7 +1342,7 @@ func TestService_UpdateMemory_WithoutMetadataDoesNotOverwriteMetadataColumns(t *
 		"Kyoto",
 	)
 
-	mock.ExpectExec(`UPDATE .* SET memory_id = \$1, memory_content = \$2, topics = \$3, embedding = \$4, memory_kind = \$5, event_time = \$6, participants = \$7, location = \$8, updated_at = \$9 WHERE memory_id = \$10 AND app_name = \$11 AND user_id = \$12`).
+	mock.ExpectExec(`UPDATE .* SET memory_id = \$1, memory_content = \$2, topics = \$3, embedding = \$4, memory_kind = \$5, event_time = \$6, participants = \$7, location = \$8, consolidation = \$9, updated_at = \$10 WHERE memory_id = \$11 AND app_name = \$12 AND user_id = \$13`).
 		WithArgs(
 			sqlmock.AnyArg(),
 			"updated memory",
@@ -1349,6 +1352,7 @@ func TestService_UpdateMemory_WithoutMetadataDoesNotOverwriteMetadataColumns(t *
 			eventTime,
 			pq.Array([]string{"Alice"}),
 			"Kyoto",
+			nil,
 			sqlmock.AnyArg(),
 			memKey.MemoryID,
 			memKey.AppName,
@@ -1396,7 +1400,7 @@ func TestService_UpdateMemory_PartialMetadataPatchOnlyUpdatesProvidedColumns(t *
 		"Kyoto",
 	)
 
-	mock.ExpectExec(`UPDATE .* SET memory_id = \$1, memory_content = \$2, topics = \$3, embedding = \$4, memory_kind = \$5, event_time = \$6, participants = \$7, location = \$8, updated_at = \$9 WHERE memory_id = \$10 AND app_name = \$11 AND user_id = \$12`).
+	mock.ExpectExec(`UPDATE .* SET memory_id = \$1, memory_content = \$2, topics = \$3, embedding = \$4, memory_kind = \$5, event_time = \$6, participants = \$7, location = \$8, consolidation = \$9, updated_at = \$10 WHERE memory_id = \$11 AND app_name = \$12 AND user_id = \$13`).
 		WithArgs(
 			sqlmock.AnyArg(),
 			"updated memory",
@@ -1406,6 +1410,7 @@ func TestService_UpdateMemory_PartialMetadataPatchOnlyUpdatesProvidedColumns(t *
 			eventTime,
 			pq.Array([]string{"Alice"}),
 			"Kyoto",
+			nil,
 			sqlmock.AnyArg(),
 			memKey.MemoryID,
 			memKey.AppName,
@@ -1440,7 +1445,7 @@ func TestService_UpdateMemory_NotFound(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows(
 			[]string{
 				"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at",
 			},
 		))
@@ -1610,13 +1615,13 @@ func TestService_ReadMemories(t *testing.T) {
 		WithArgs(userKey.AppName, userKey.UserID).
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at"},
 		).
 			AddRow("mem-1", "test-app", "u1", "memory 1", pq.Array([]string{"topic1"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now).
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now).
 			AddRow("mem-2", "test-app", "u1", "memory 2", pq.Array([]string{"topic2"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now))
 
 	entries, err := svc.ReadMemories(ctx, userKey, 10)
 	require.NoError(t, err)
@@ -1667,7 +1672,7 @@ func TestService_ReadMemories_Empty(t *testing.T) {
 		WithArgs(userKey.AppName, userKey.UserID).
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at"},
 		))
 
@@ -1693,13 +1698,13 @@ func TestService_SearchMemories(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).
 			AddRow("mem-1", "test-app", "u1", "coffee brewing tips", pq.Array([]string{"hobby"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.95).
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.95).
 			AddRow("mem-2", "test-app", "u1", "Alice likes coffee", pq.Array([]string{"profile"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.85))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.85))
 
 	results, err := svc.SearchMemories(ctx, userKey, "coffee")
 	require.NoError(t, err)
@@ -2085,8 +2090,8 @@ func TestService_InitDB_IndexCreateError(t *testing.T) {
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	// Mock episodic column migrations.
-	for i := 0; i < 4; i++ {
+	// Mock episodic and consolidation column migrations.
+	for i := 0; i < 5; i++ {
 		mock.ExpectExec("ALTER TABLE").
 			WillReturnResult(sqlmock.NewResult(0, 0))
 	}
@@ -2131,8 +2136,8 @@ func TestService_InitDB_HNSWIndexError(t *testing.T) {
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	// Mock episodic column migrations.
-	for i := 0; i < 4; i++ {
+	// Mock episodic and consolidation column migrations.
+	for i := 0; i < 5; i++ {
 		mock.ExpectExec("ALTER TABLE").
 			WillReturnResult(sqlmock.NewResult(0, 0))
 	}
@@ -2177,7 +2182,7 @@ func TestService_InitDB_Success(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"has_schema_privilege"}).AddRow(true))
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").
 		WillReturnResult(sqlmock.NewResult(0, 0))
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 5; i++ {
 		mock.ExpectExec("ALTER TABLE").
 			WillReturnResult(sqlmock.NewResult(0, 0))
 	}
@@ -2231,7 +2236,7 @@ func TestService_InitDB_BackfillSearchVectorErrorIsNonFatal(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"has_schema_privilege"}).AddRow(true))
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").
 		WillReturnResult(sqlmock.NewResult(0, 0))
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 5; i++ {
 		mock.ExpectExec("ALTER TABLE").
 			WillReturnResult(sqlmock.NewResult(0, 0))
 	}
@@ -2477,7 +2482,7 @@ func TestService_ReadMemories_SoftDelete(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at"},
 		))
 
@@ -2541,11 +2546,11 @@ func TestService_SearchMemories_SoftDelete(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).
 			AddRow("mem-1", "test-app", "u1", "test", pq.Array([]string{"t"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.9))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.9))
 
 	results, err := svc.SearchMemories(ctx, userKey, "query")
 	require.NoError(t, err)
@@ -2737,10 +2742,10 @@ func TestService_ScanMemoryEntry_Error(t *testing.T) {
 		WithArgs(userKey.AppName, userKey.UserID).
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at"},
 		).AddRow("mem-1", "test-app", "u1", "memory", nil,
-			"fact", nil, pq.Array([]string{}), nil, "invalid-time", "invalid"))
+			"fact", nil, pq.Array([]string{}), nil, nil, "invalid-time", "invalid"))
 
 	_, err := svc.ReadMemories(ctx, userKey, 10)
 	require.Error(t, err)
@@ -2761,10 +2766,10 @@ func TestService_ScanMemoryEntryWithSimilarity_Error(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).AddRow("mem-1", "test-app", "u1", "memory", nil,
-			"fact", nil, pq.Array([]string{}), nil, "invalid-time", "invalid", 0.9))
+			"fact", nil, pq.Array([]string{}), nil, nil, "invalid-time", "invalid", 0.9))
 
 	_, err := svc.SearchMemories(ctx, userKey, "query")
 	require.Error(t, err)
@@ -2824,6 +2829,7 @@ func TestResolveMetadata(t *testing.T) {
 		assert.Nil(t, got.eventTime)
 		assert.Empty(t, got.participants)
 		assert.Nil(t, got.location)
+		assert.Nil(t, got.consolidation)
 	})
 
 	t.Run("memory metadata is converted for SQL", func(t *testing.T) {
@@ -2840,6 +2846,22 @@ func TestResolveMetadata(t *testing.T) {
 		assert.Equal(t, []string{"Alice", "Bob"}, got.participants)
 		require.NotNil(t, got.location)
 		assert.Equal(t, "Kyoto", *got.location)
+		assert.Nil(t, got.consolidation)
+	})
+
+	t.Run("consolidation fields are encoded as JSON", func(t *testing.T) {
+		got := resolveMetadata(&memory.Memory{
+			Provenance:     []memory.Provenance{{SessionID: "s1", Time: now}},
+			AccessCount:    2,
+			LastAccessedAt: &now,
+		})
+
+		require.NotNil(t, got.consolidation)
+		assert.JSONEq(t,
+			`{"provenance":[{"session_id":"s1","time":"2024-05-07T10:00:00Z"}],`+
+				`"access_count":2,"last_accessed_at":"2024-05-07T10:00:00Z"}`,
+			*got.consolidation,
+		)
 	})
 }
 
@@ -2858,6 +2880,7 @@ func TestBuildEntry_PopulatesEpisodicFields(t *testing.T) {
 		sql.NullTime{Time: eventTime, Valid: true},
 		pq.StringArray([]string{"Alice", "Bob"}),
 		sql.NullString{String: "Kyoto", Valid: true},
+		sql.NullString{String: `{"provenance":[{"session_id":"s1","time":"0001-01-01T00:00:00Z"}],"access_count":2}`, Valid: true},
 		createdAt,
 		updatedAt,
 	)
@@ -2872,6 +2895,8 @@ func TestBuildEntry_PopulatesEpisodicFields(t *testing.T) {
 	assert.Equal(t, eventTime, *entry.Memory.EventTime)
 	assert.Equal(t, []string{"Alice", "Bob"}, entry.Memory.Participants)
 	assert.Equal(t, "Kyoto", entry.Memory.Location)
+	assert.Equal(t, []memory.Provenance{{SessionID: "s1"}}, entry.Memory.Provenance)
+	assert.Equal(t, 2, entry.Memory.AccessCount)
 	require.NotNil(t, entry.Memory.LastUpdated)
 	assert.Equal(t, updatedAt, *entry.Memory.LastUpdated)
 	assert.Equal(t, createdAt, entry.CreatedAt)
@@ -2895,11 +2920,11 @@ func TestExecuteVectorSearch_WithAdvancedOptions(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).AddRow(
 			"mem-1", "test-app", "u1", "Alice hiked in Kyoto", pq.Array([]string{"travel"}),
-			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.93,
+			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.93,
 		))
 
 	results, err := svc.executeVectorSearch(
@@ -2976,11 +3001,11 @@ func TestExecuteVectorSearch_OrderByEventTimeUsesSimilarityFirst(t *testing.T) {
 	mock.ExpectQuery("ORDER BY embedding <=> \\$1, event_time ASC NULLS LAST").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).AddRow(
 			"mem-1", "test-app", "u1", "Alice hiked in Kyoto", pq.Array([]string{"travel"}),
-			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.93,
+			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.93,
 		))
 
 	results, err := svc.executeVectorSearch(
@@ -3064,15 +3089,15 @@ func TestService_SearchMemories_ThresholdAndDeduplicate(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).
 			AddRow("mem-1", "test-app", "u1", "Alice hiking in Kyoto", pq.Array([]string{"travel"}),
-				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.95).
+				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.95).
 			AddRow("mem-2", "test-app", "u1", "Alice hiking in Kyoto", pq.Array([]string{"travel"}),
-				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.92).
+				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.92).
 			AddRow("mem-3", "test-app", "u1", "Low relevance result", pq.Array([]string{"misc"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.20))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.20))
 
 	results, err := svc.SearchMemories(
 		context.Background(),
@@ -3103,13 +3128,13 @@ func TestService_SearchMemories_OrderByEventTimeKeepsHigherSimilarityFirst(t *te
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		).
 			AddRow("mem-low", "test-app", "u1", "Older but weaker", pq.Array([]string{"travel"}),
-				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.60).
+				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.60).
 			AddRow("mem-high", "test-app", "u1", "Later and stronger", pq.Array([]string{"travel"}),
-				"episode", later, pq.Array([]string{"Alice"}), "Kyoto", later, later, 0.95))
+				"episode", later, pq.Array([]string{"Alice"}), "Kyoto", nil, later, later, 0.95))
 
 	results, err := svc.SearchMemories(
 		context.Background(),
@@ -3138,7 +3163,7 @@ func TestService_SearchMemories_KindFallbackKeepsRequestedKindFirst(t *testing.T
 	rows := func() *sqlmock.Rows {
 		return sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		)
 	}
@@ -3146,14 +3171,14 @@ func TestService_SearchMemories_KindFallbackKeepsRequestedKindFirst(t *testing.T
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(rows().AddRow(
 			"mem-1", "test-app", "u1", "Episode primary", pq.Array([]string{"travel"}),
-			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.60,
+			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.60,
 		))
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(rows().
 			AddRow("mem-2", "test-app", "u1", "Episode fallback", pq.Array([]string{"travel"}),
-				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.70).
+				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.70).
 			AddRow("mem-3", "test-app", "u1", "Fact fallback", pq.Array([]string{"profile"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.95))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.95))
 
 	results, err := svc.SearchMemories(
 		context.Background(),
@@ -3185,7 +3210,7 @@ func TestService_SearchMemories_KindFallbackAndHybridSearch(t *testing.T) {
 	rows := func() *sqlmock.Rows {
 		return sqlmock.NewRows(
 			[]string{"memory_id", "app_name", "user_id", "memory_content", "topics",
-				"memory_kind", "event_time", "participants", "location",
+				"memory_kind", "event_time", "participants", "location", "consolidation",
 				"created_at", "updated_at", "similarity"},
 		)
 	}
@@ -3193,18 +3218,18 @@ func TestService_SearchMemories_KindFallbackAndHybridSearch(t *testing.T) {
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(rows().AddRow(
 			"mem-1", "test-app", "u1", "Alice hiked in Kyoto", pq.Array([]string{"travel"}),
-			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.95,
+			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.95,
 		))
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(rows().
 			AddRow("mem-2", "test-app", "u1", "Alice planned a Kyoto trip", pq.Array([]string{"travel"}),
-				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.89).
+				"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.89).
 			AddRow("mem-3", "test-app", "u1", "Alice likes coffee", pq.Array([]string{"profile"}),
-				"fact", nil, pq.Array([]string{}), nil, now, now, 0.88))
+				"fact", nil, pq.Array([]string{}), nil, nil, now, now, 0.88))
 	mock.ExpectQuery("SELECT memory_id, app_name, user_id, memory_content, topics").
 		WillReturnRows(rows().AddRow(
 			"mem-1", "test-app", "u1", "Alice hiked in Kyoto", pq.Array([]string{"travel"}),
-			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", now, now, 0.50,
+			"episode", now, pq.Array([]string{"Alice"}), "Kyoto", nil, now, now, 0.50,
 		))
 
 	results, err := svc.SearchMemories(
@@ -3275,6 +3300,7 @@ func TestService_UpdateMemory_HardDeleteRejectsTombstoneSource(t *testing.T) {
 					"event_time",
 					"participants",
 					"location",
+					"consolidation",
 					"created_at",
 					"updated_at",
 				}))
@@ -4278,3 +4278,24 @@ func TestService_UpdateMemory_HardDeleteRollsBackWhenSourceBecomesTombstone(
 	require.ErrorContains(t, err, "not found")
 	require.Equal(t, "unchanged", result.MemoryID)
 }
+
+func TestService_AddMemory_SharedScopes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	svc := setupMockService(t, db, mock, WithMemoryLimit(0))
+	defer svc.Close()
+
+	ctx := context.Background()
+	groupKey := memory.Scope{Kind: memory.ScopeGroup, ID: "team"}.UserKey("app")
+
+	mock.ExpectExec("INSERT INTO").
+		WithArgs(sqlmock.AnyArg(), "app", "scope:group:team", sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, svc.AddMemory(ctx, groupKey, "team memory", nil))
+
+	err := svc.AddMemory(ctx, memory.UserKey{AppName: "app", UserID: "scope:other"}, "x", nil)
+	assert.ErrorIs(t, err, memory.ErrReservedUserID)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
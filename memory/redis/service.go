@@ -215,6 +215,9 @@ func (s *Service) UpdateMemory(ctx context.Context, memoryKey memory.Key, memory
 	}
 	imemory.NormalizeEntry(entry)
 	now := time.Now()
+	if imemory.KeepsContent(entry, memoryStr, topics, opts) {
+		now = entry.UpdatedAt
+	}
 	ep := memory.ResolveUpdateOptions(opts)
 	newID := imemory.ApplyMemoryUpdate(
 		entry,
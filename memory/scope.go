@@ -0,0 +1,316 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ScopeKind identifies who a memory belongs to.
+type ScopeKind string
+
+const (
+	// ScopeUser holds memories of a single user. It is the default scope and
+	// the only one used when no ScopeAccess is configured.
+	ScopeUser ScopeKind = "user"
+	// ScopeAgent holds memories shared by every user of one agent.
+	ScopeAgent ScopeKind = "agent"
+	// ScopeGroup holds memories shared by a team, organisation or tenant.
+	ScopeGroup ScopeKind = "group"
+	// ScopeApp holds memories shared by every user of the application.
+	ScopeApp ScopeKind = "app"
+)
+
+// scopeSubjectPrefix marks the reserved subject IDs of shared scopes.
+// User IDs must not start with it, see CheckUserID.
+const scopeSubjectPrefix = "scope:"
+
+// Scope is a resolved memory scope.
+//
+// Backends key memories by UserKey. A shared scope is stored under a
+// reserved subject ID in the UserID field ("scope:agent:<name>",
+// "scope:group:<id>" or "scope:app"), so every backend supports scopes
+// without schema changes and a user scope maps to the plain user ID.
+type Scope struct {
+	Kind ScopeKind // Kind is the scope kind.
+	ID   string    // ID is the user ID, agent name or group ID; empty for app.
+}
+
+// UserKey returns the storage key of the scope within appName.
+func (s Scope) UserKey(appName string) UserKey {
+	return UserKey{AppName: appName, UserID: s.subjectID()}
+}
+
+// Key returns the storage key of a memory within the scope.
+func (s Scope) Key(appName, memoryID string) Key {
+	return Key{AppName: appName, UserID: s.subjectID(), MemoryID: memoryID}
+}
+
+func (s Scope) subjectID() string {
+	switch s.Kind {
+	case ScopeAgent, ScopeGroup:
+		return scopeSubjectPrefix + string(s.Kind) + ":" + s.ID
+	case ScopeApp:
+		return scopeSubjectPrefix + string(ScopeApp)
+	default:
+		return s.ID
+	}
+}
+
+// CheckUserID checks that userID identifies a user rather than a shared
+// scope. Memory tools, preload and automatic extraction check the user ID of
+// the session with it, so a user cannot reach shared memories by picking a
+// reserved ID.
+func CheckUserID(userID string) error {
+	if userID == "" {
+		return ErrUserIDRequired
+	}
+	if strings.HasPrefix(userID, scopeSubjectPrefix) {
+		return ErrReservedUserID
+	}
+	return nil
+}
+
+// checkStoredUserID accepts the user IDs a backend stores: plain user IDs
+// and the subject IDs of shared scopes. Other IDs with the reserved prefix
+// are rejected.
+func checkStoredUserID(userID string) error {
+	if !strings.HasPrefix(userID, scopeSubjectPrefix) {
+		return nil
+	}
+	if s := ScopeFromUserID(userID); s.Kind != ScopeUser && s.subjectID() == userID &&
+		(s.Kind == ScopeApp || s.ID != "") {
+		return nil
+	}
+	return ErrReservedUserID
+}
+
+// ScopeFromUserID returns the scope a stored UserID belongs to. Plain user
+// IDs map to the user scope.
+func ScopeFromUserID(userID string) Scope {
+	rest, ok := strings.CutPrefix(userID, scopeSubjectPrefix)
+	if !ok {
+		return Scope{Kind: ScopeUser, ID: userID}
+	}
+	if rest == string(ScopeApp) {
+		return Scope{Kind: ScopeApp}
+	}
+	kind, id, _ := strings.Cut(rest, ":")
+	switch ScopeKind(kind) {
+	case ScopeAgent, ScopeGroup:
+		return Scope{Kind: ScopeKind(kind), ID: id}
+	default:
+		return Scope{Kind: ScopeUser, ID: userID}
+	}
+}
+
+// ScopeAccess declares the memory scopes a caller may read and write.
+//
+// Reads fall through Read in order: results of earlier scopes come first and
+// win over memories with the same content in later scopes, so a user memory
+// overrides a group memory that says the same thing. An empty Read means the
+// user scope only; an empty Write means writes go to the user scope only.
+// The first Write entry is the default target of adds that name no scope;
+// updates, deletes and clears that name no scope target the user scope.
+type ScopeAccess struct {
+	// Read lists the readable scopes in fall-through order.
+	Read []ScopeKind
+	// Write lists the writable scopes. The first entry is the default.
+	Write []ScopeKind
+	// AgentName is the ID of the agent scope. Memory tools default it to the
+	// name of the running agent.
+	AgentName string
+	// GroupID is the ID of the group scope.
+	GroupID string
+}
+
+type scopeAccessKey struct{}
+
+// WithScopeAccess returns a copy of ctx carrying access. Pass the returned
+// context to Runner.Run so that memory tools, memory preload and automatic
+// memory extraction of the run honour the permitted scopes.
+func WithScopeAccess(ctx context.Context, access *ScopeAccess) context.Context {
+	return context.WithValue(ctx, scopeAccessKey{}, access)
+}
+
+// ScopeAccessFromContext returns the ScopeAccess stored in ctx, or nil.
+func ScopeAccessFromContext(ctx context.Context) *ScopeAccess {
+	if ctx == nil {
+		return nil
+	}
+	access, _ := ctx.Value(scopeAccessKey{}).(*ScopeAccess)
+	return access
+}
+
+// ReadScopes resolves the readable scopes for userID in fall-through order.
+// Scopes whose ID is unknown, such as an agent scope without AgentName, are
+// skipped. A nil receiver reads the user scope only.
+func (a *ScopeAccess) ReadScopes(userID string) []Scope {
+	if a == nil || len(a.Read) == 0 {
+		if CheckUserID(userID) != nil {
+			return nil
+		}
+		return []Scope{{Kind: ScopeUser, ID: userID}}
+	}
+	scopes := make([]Scope, 0, len(a.Read))
+	for _, kind := range a.Read {
+		scope, err := a.resolve(kind, userID)
+		if err != nil || slices.Contains(scopes, scope) {
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// WriteScope resolves the scope a write for userID targets. An empty kind
+// selects the default write scope. It returns an error when kind is not
+// writable or its ID is unknown.
+func (a *ScopeAccess) WriteScope(kind ScopeKind, userID string) (Scope, error) {
+	var writable []ScopeKind
+	if a != nil {
+		writable = a.Write
+	}
+	if len(writable) == 0 {
+		writable = []ScopeKind{ScopeUser}
+	}
+	if kind == "" {
+		kind = writable[0]
+	}
+	if !slices.Contains(writable, kind) {
+		return Scope{}, fmt.Errorf("memory scope %q is not writable", kind)
+	}
+	return a.resolve(kind, userID)
+}
+
+// ModifyScope resolves the scope an update, delete or clear for userID
+// targets. An empty kind selects the user scope rather than the default
+// write scope, so an operation that names no scope never modifies shared
+// memories. It returns an error when kind is not writable or its ID is
+// unknown.
+func (a *ScopeAccess) ModifyScope(kind ScopeKind, userID string) (Scope, error) {
+	if kind == "" {
+		kind = ScopeUser
+	}
+	return a.WriteScope(kind, userID)
+}
+
+func (a *ScopeAccess) resolve(kind ScopeKind, userID string) (Scope, error) {
+	switch kind {
+	case ScopeUser:
+		if err := CheckUserID(userID); err != nil {
+			return Scope{}, err
+		}
+		return Scope{Kind: ScopeUser, ID: userID}, nil
+	case ScopeApp:
+		return Scope{Kind: ScopeApp}, nil
+	case ScopeAgent:
+		if a == nil || a.AgentName == "" {
+			return Scope{}, fmt.Errorf("memory scope %q has no agent name", kind)
+		}
+		return Scope{Kind: ScopeAgent, ID: a.AgentName}, nil
+	case ScopeGroup:
+		if a == nil || a.GroupID == "" {
+			return Scope{}, fmt.Errorf("memory scope %q has no group id", kind)
+		}
+		return Scope{Kind: ScopeGroup, ID: a.GroupID}, nil
+	default:
+		return Scope{}, fmt.Errorf("unknown memory scope %q", kind)
+	}
+}
+
+// NewScopedReader returns a Reader that reads through the scopes permitted
+// by access. The UserID of the keys passed to it identifies the caller and
+// resolves the user scope; AppName is kept for every scope.
+//
+// ReadMemories fills the limit scope by scope in fall-through order.
+// SearchMemories searches every scope with the same options, merges the
+// results by score and caps them at MaxResults when it is set. Both drop
+// memories whose content repeats one from an earlier scope.
+func NewScopedReader(base Reader, access *ScopeAccess) Reader {
+	return &scopedReader{base: base, access: access}
+}
+
+type scopedReader struct {
+	base   Reader
+	access *ScopeAccess
+}
+
+// ReadMemories implements Reader.
+func (r *scopedReader) ReadMemories(ctx context.Context, userKey UserKey,
+	limit int) ([]*Entry, error) {
+	if err := userKey.CheckUserKey(); err != nil {
+		return nil, err
+	}
+	var merged []*Entry
+	seen := make(map[string]struct{})
+	for _, scope := range r.access.ReadScopes(userKey.UserID) {
+		if limit > 0 && len(merged) >= limit {
+			break
+		}
+		// Each scope is asked for the full limit because memories repeated
+		// from earlier scopes are dropped and must not use up its share.
+		entries, err := r.base.ReadMemories(ctx, scope.UserKey(userKey.AppName), limit)
+		if err != nil {
+			return nil, fmt.Errorf("read %s memories: %w", scope.Kind, err)
+		}
+		merged = appendUnseen(merged, entries, seen)
+	}
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+// SearchMemories implements Reader.
+func (r *scopedReader) SearchMemories(ctx context.Context, userKey UserKey,
+	query string, opts ...SearchOption) ([]*Entry, error) {
+	if err := userKey.CheckUserKey(); err != nil {
+		return nil, err
+	}
+	resolved := ResolveSearchOptions(query, opts)
+	var merged []*Entry
+	seen := make(map[string]struct{})
+	for _, scope := range r.access.ReadScopes(userKey.UserID) {
+		entries, err := r.base.SearchMemories(ctx, scope.UserKey(userKey.AppName),
+			query, WithSearchOptions(resolved))
+		if err != nil {
+			return nil, fmt.Errorf("search %s memories: %w", scope.Kind, err)
+		}
+		merged = appendUnseen(merged, entries, seen)
+	}
+	// The stable sort keeps fall-through order among equal scores.
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	if resolved.MaxResults > 0 && len(merged) > resolved.MaxResults {
+		merged = merged[:resolved.MaxResults]
+	}
+	return merged, nil
+}
+
+func appendUnseen(dst, entries []*Entry, seen map[string]struct{}) []*Entry {
+	for _, e := range entries {
+		if e == nil || e.Memory == nil {
+			continue
+		}
+		content := strings.ToLower(strings.Join(strings.Fields(e.Memory.Memory), " "))
+		if _, ok := seen[content]; ok {
+			continue
+		}
+		seen[content] = struct{}{}
+		dst = append(dst, e)
+	}
+	return dst
+}
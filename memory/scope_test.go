@@ -0,0 +1,187 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_UserKeyRoundTrip(t *testing.T) {
+	scopes := []Scope{
+		{Kind: ScopeUser, ID: "u1"},
+		{Kind: ScopeAgent, ID: "helper"},
+		{Kind: ScopeGroup, ID: "team:a"},
+		{Kind: ScopeApp},
+	}
+	for _, s := range scopes {
+		key := s.UserKey("app")
+		assert.Equal(t, "app", key.AppName)
+		assert.Equal(t, s, ScopeFromUserID(key.UserID))
+	}
+	assert.Equal(t, "u1", Scope{Kind: ScopeUser, ID: "u1"}.UserKey("app").UserID)
+	assert.Equal(t, "scope:app", Scope{Kind: ScopeApp}.Key("app", "m").UserID)
+	assert.Equal(t, Scope{Kind: ScopeUser, ID: "scope:other"}, ScopeFromUserID("scope:other"))
+}
+
+func TestScopeAccess_Resolve(t *testing.T) {
+	var none *ScopeAccess
+	assert.Equal(t, []Scope{{Kind: ScopeUser, ID: "u"}}, none.ReadScopes("u"))
+	s, err := none.WriteScope("", "u")
+	require.NoError(t, err)
+	assert.Equal(t, Scope{Kind: ScopeUser, ID: "u"}, s)
+	_, err = none.WriteScope(ScopeApp, "u")
+	assert.Error(t, err)
+
+	access := &ScopeAccess{
+		Read:    []ScopeKind{ScopeUser, ScopeAgent, ScopeGroup, ScopeApp, ScopeUser},
+		Write:   []ScopeKind{ScopeGroup, ScopeUser},
+		GroupID: "g",
+	}
+	assert.Equal(t, []Scope{
+		{Kind: ScopeUser, ID: "u"},
+		{Kind: ScopeGroup, ID: "g"},
+		{Kind: ScopeApp},
+	}, access.ReadScopes("u"), "agent scope without a name is skipped")
+
+	s, err = access.WriteScope("", "u")
+	require.NoError(t, err)
+	assert.Equal(t, Scope{Kind: ScopeGroup, ID: "g"}, s)
+	_, err = access.WriteScope(ScopeApp, "u")
+	assert.Error(t, err)
+	_, err = (&ScopeAccess{Write: []ScopeKind{ScopeAgent}}).WriteScope("", "u")
+	assert.Error(t, err)
+	_, err = (&ScopeAccess{Write: []ScopeKind{"team"}}).WriteScope("", "u")
+	assert.Error(t, err)
+}
+
+func TestCheckUserID(t *testing.T) {
+	assert.NoError(t, CheckUserID("u"))
+	assert.ErrorIs(t, CheckUserID(""), ErrUserIDRequired)
+	assert.ErrorIs(t, CheckUserID("scope:app"), ErrReservedUserID)
+
+	for _, id := range []string{"u", "scope:app", "scope:agent:a", "scope:group:g"} {
+		key := UserKey{AppName: "app", UserID: id}
+		assert.NoError(t, key.CheckUserKey(), id)
+	}
+	for _, id := range []string{"scope:", "scope:user", "scope:agent:", "scope:group", "scope:app:x"} {
+		key := UserKey{AppName: "app", UserID: id}
+		assert.ErrorIs(t, key.CheckUserKey(), ErrReservedUserID, id)
+		memKey := Key{AppName: "app", UserID: id, MemoryID: "m"}
+		assert.ErrorIs(t, memKey.CheckMemoryKey(), ErrReservedUserID, id)
+	}
+
+	_, err := (*ScopeAccess)(nil).WriteScope("", "scope:app")
+	assert.ErrorIs(t, err, ErrReservedUserID)
+	assert.Empty(t, (*ScopeAccess)(nil).ReadScopes("scope:app"))
+	access := &ScopeAccess{Read: []ScopeKind{ScopeUser, ScopeApp}}
+	assert.Equal(t, []Scope{{Kind: ScopeApp}}, access.ReadScopes("scope:group:g"))
+}
+
+func TestScopeAccess_ModifyScope(t *testing.T) {
+	var none *ScopeAccess
+	s, err := none.ModifyScope("", "u")
+	require.NoError(t, err)
+	assert.Equal(t, Scope{Kind: ScopeUser, ID: "u"}, s)
+
+	access := &ScopeAccess{Write: []ScopeKind{ScopeGroup, ScopeUser}, GroupID: "g"}
+	s, err = access.ModifyScope("", "u")
+	require.NoError(t, err)
+	assert.Equal(t, Scope{Kind: ScopeUser, ID: "u"}, s, "no scope never targets shared memories")
+	s, err = access.ModifyScope(ScopeGroup, "u")
+	require.NoError(t, err)
+	assert.Equal(t, Scope{Kind: ScopeGroup, ID: "g"}, s)
+
+	_, err = (&ScopeAccess{Write: []ScopeKind{ScopeApp}}).ModifyScope("", "u")
+	assert.Error(t, err, "the user scope is not writable")
+}
+
+func TestScopeAccess_Context(t *testing.T) {
+	assert.Nil(t, ScopeAccessFromContext(context.Background()))
+	access := &ScopeAccess{GroupID: "g"}
+	ctx := WithScopeAccess(context.Background(), access)
+	assert.Same(t, access, ScopeAccessFromContext(ctx))
+}
+
+type scopeTestReader struct {
+	entries map[string][]*Entry
+	err     error
+}
+
+func (r *scopeTestReader) ReadMemories(_ context.Context, key UserKey, limit int) ([]*Entry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	entries := r.entries[key.UserID]
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (r *scopeTestReader) SearchMemories(_ context.Context, key UserKey, _ string, _ ...SearchOption) ([]*Entry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.entries[key.UserID], nil
+}
+
+func entry(id, content string, score float64) *Entry {
+	return &Entry{ID: id, Memory: &Memory{Memory: content}, Score: score}
+}
+
+func TestScopedReader_FallThrough(t *testing.T) {
+	base := &scopeTestReader{entries: map[string][]*Entry{
+		"u":             {entry("u1", "Prefers Go", 0.5), entry("u2", "Lives in Paris", 0.2)},
+		"scope:group:g": {entry("g1", "prefers  go", 0.9), entry("g2", "Team uses Jira", 0.7)},
+		"scope:app":     {entry("a1", "Support hours are 9-5", 0.1)},
+	}}
+	access := &ScopeAccess{Read: []ScopeKind{ScopeUser, ScopeGroup, ScopeApp}, GroupID: "g"}
+	r := NewScopedReader(base, access)
+	ctx := context.Background()
+	key := UserKey{AppName: "app", UserID: "u"}
+
+	read, err := r.ReadMemories(ctx, key, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2", "g2"}, ids(read))
+
+	read, err = r.ReadMemories(ctx, key, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2", "g2", "a1"}, ids(read))
+
+	found, err := r.SearchMemories(ctx, key, "q")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g2", "u1", "u2", "a1"}, ids(found), "merged by score")
+
+	found, err = r.SearchMemories(ctx, key, "q", WithSearchOptions(SearchOptions{Query: "q", MaxResults: 2}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g2", "u1"}, ids(found))
+
+	_, err = r.ReadMemories(ctx, UserKey{AppName: "app"}, 1)
+	assert.ErrorIs(t, err, ErrUserIDRequired)
+
+	base.err = errors.New("boom")
+	_, err = r.SearchMemories(ctx, key, "q")
+	assert.Error(t, err)
+	_, err = r.ReadMemories(ctx, key, 1)
+	assert.Error(t, err)
+}
+
+func ids(entries []*Entry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.ID
+	}
+	return out
+}
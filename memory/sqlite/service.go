@@ -261,6 +261,9 @@ func (s *Service) UpdateMemory(
 	}
 
 	now := time.Now()
+	if imemory.KeepsContent(entry, memoryStr, topics, opts) {
+		now = entry.UpdatedAt
+	}
 	newID := imemory.ApplyMemoryUpdate(
 		entry,
 		memoryKey.AppName,
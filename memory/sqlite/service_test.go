@@ -1181,3 +1181,40 @@ END`)
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 }
+
+func TestService_SharedScopes(t *testing.T) {
+	db, cleanup := openTempSQLiteDB(t)
+	defer cleanup()
+
+	svc, err := NewService(db)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	ctx := context.Background()
+	userKey := memory.UserKey{AppName: "app", UserID: "u1"}
+	group := memory.Scope{Kind: memory.ScopeGroup, ID: "team"}
+	require.NoError(t, svc.AddMemory(ctx, userKey, "Alice likes Go", nil))
+	require.NoError(t, svc.AddMemory(ctx, group.UserKey("app"), "Team deploys on Fridays", nil))
+
+	got, err := svc.ReadMemories(ctx, group.UserKey("app"), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "Team deploys on Fridays", got[0].Memory.Memory)
+
+	reader := memory.NewScopedReader(svc, &memory.ScopeAccess{
+		Read:    []memory.ScopeKind{memory.ScopeUser, memory.ScopeGroup},
+		GroupID: "team",
+	})
+	got, err = reader.ReadMemories(ctx, userKey, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	require.NoError(t, svc.ClearMemories(ctx, group.UserKey("app")))
+	got, err = svc.ReadMemories(ctx, userKey, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.ErrorIs(t, svc.AddMemory(ctx,
+		memory.UserKey{AppName: "app", UserID: "scope:other"}, "x", nil),
+		memory.ErrReservedUserID)
+}
@@ -37,7 +37,8 @@ CREATE VIRTUAL TABLE IF NOT EXISTS {{TABLE_NAME}} USING vec0(
   +memory_kind text,
   +event_time integer,
   +participants text,
-  +location text
+  +location text,
+  +consolidation text
 );`
 
 	sqlCreateSchemaBackupTable = `
@@ -54,7 +55,8 @@ CREATE TABLE %s (
   memory_kind text,
   event_time integer,
   participants text,
-  location text
+  location text,
+  consolidation text
 );`
 )
 
@@ -72,6 +74,7 @@ var requiredSchemaColumns = []string{
 	"event_time",
 	"participants",
 	"location",
+	"consolidation",
 }
 
 var legacySchemaColumns = []string{
@@ -207,18 +210,19 @@ func (s *Service) createSchemaBackup(
 memory_id, embedding, app_name, user_id,
 created_at, updated_at, deleted_at,
 memory_content, topics, memory_kind,
-event_time, participants, location
+event_time, participants, location, consolidation
 )
 SELECT
 memory_id, embedding, app_name, user_id,
 created_at, updated_at, deleted_at,
-memory_content, topics, %s, %s, %s, %s
+memory_content, topics, %s, %s, %s, %s, %s
 FROM %s`,
 		backupTable,
 		optionalColumnExpr(found, "memory_kind"),
 		optionalColumnExpr(found, "event_time"),
 		optionalColumnExpr(found, "participants"),
 		optionalColumnExpr(found, "location"),
+		optionalColumnExpr(found, "consolidation"),
 		s.tableName,
 	)
 	if _, err := tx.ExecContext(ctx, query); err != nil {
@@ -239,13 +243,13 @@ func (s *Service) restoreSchemaBackup(
 memory_id, embedding, app_name, user_id,
 created_at, updated_at, deleted_at,
 memory_content, topics, memory_kind,
-event_time, participants, location
+event_time, participants, location, consolidation
 )
 SELECT
 memory_id, vec_f32(embedding), app_name, user_id,
 created_at, updated_at, deleted_at,
 memory_content, topics, memory_kind,
-event_time, participants, location
+event_time, participants, location, consolidation
 FROM %s`,
 		s.tableName,
 		backupTable,
@@ -177,6 +177,10 @@ func (s *Service) AddMemory(
 	if err != nil {
 		return fmt.Errorf("marshal participants: %w", err)
 	}
+	consolidationJSON, err := imemory.MarshalConsolidation(mem)
+	if err != nil {
+		return fmt.Errorf("marshal consolidation: %w", err)
+	}
 	eventTimeNs := metadataEventTimeNS(mem.EventTime)
 	location := metadataLocationValue(mem.Location)
 
@@ -216,7 +220,8 @@ func (s *Service) AddMemory(
 embedding = ` + sqlVectorFromBlob + `,
 updated_at = ?, deleted_at = ?,
 memory_content = ?, topics = ?,
-memory_kind = ?, event_time = ?, participants = ?, location = ?
+memory_kind = ?, event_time = ?, participants = ?, location = ?,
+consolidation = ?
 WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 		query := fmt.Sprintf(updateSQL, s.tableName)
 		res, err := tx.ExecContext(
@@ -231,6 +236,7 @@ WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 			eventTimeNs,
 			participantsJSON,
 			location,
+			consolidationJSON,
 			userKey.AppName,
 			userKey.UserID,
 			memoryID,
@@ -253,8 +259,8 @@ WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 memory_id, embedding, app_name, user_id,
 created_at, updated_at, deleted_at,
 memory_content, topics, memory_kind, event_time,
-participants, location
-) VALUES (?, ` + sqlVectorFromBlob + `, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+participants, location, consolidation
+) VALUES (?, ` + sqlVectorFromBlob + `, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	query := fmt.Sprintf(insertSQL, s.tableName)
 	_, err = tx.ExecContext(
 		ctx,
@@ -272,6 +278,7 @@ participants, location
 		eventTimeNs,
 		participantsJSON,
 		location,
+		consolidationJSON,
 	)
 	if err != nil {
 		return fmt.Errorf("insert memory: %w", err)
@@ -364,7 +371,7 @@ func (s *Service) UpdateMemory(
 
 	const selectSQL = `SELECT
 memory_id, memory_content, topics, memory_kind, event_time,
-participants, location, created_at, updated_at
+participants, location, consolidation, created_at, updated_at
 FROM %s WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 	selectQuery := fmt.Sprintf(selectSQL, s.tableName)
 	selectArgs := []any{memoryKey.AppName, memoryKey.UserID, memoryKey.MemoryID}
@@ -388,6 +395,36 @@ FROM %s WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 		return fmt.Errorf("memory with id %s not found", memoryKey.MemoryID)
 	}
 
+	keep := imemory.KeepsContent(entry, memoryStr, topics, opts)
+	now := time.Now()
+	updatedAtNs := now.UTC().UnixNano()
+	newID := imemory.ApplyMemoryUpdate(
+		entry,
+		memoryKey.AppName,
+		memoryKey.UserID,
+		memoryStr,
+		topics,
+		ep,
+		now,
+	)
+	consolidationJSON, err := imemory.MarshalConsolidation(entry.Memory)
+	if err != nil {
+		return fmt.Errorf("marshal consolidation: %w", err)
+	}
+	if keep && newID == memoryKey.MemoryID {
+		if err := s.updateConsolidation(ctx, memoryKey, consolidationJSON); err != nil {
+			return err
+		}
+		if result := memory.ResolveUpdateResult(opts); result != nil {
+			result.MemoryID = newID
+		}
+		return nil
+	}
+	participantsJSON, err := marshalStringSlice(entry.Memory.Participants)
+	if err != nil {
+		return fmt.Errorf("marshal participants: %w", err)
+	}
+
 	embedding, err := s.opts.embedder.GetEmbedding(ctx, memoryStr)
 	if err != nil {
 		return fmt.Errorf("generate embedding: %w", err)
@@ -409,27 +446,12 @@ FROM %s WHERE app_name = ? AND user_id = ? AND memory_id = ?`
 	if err != nil {
 		return fmt.Errorf("marshal topics: %w", err)
 	}
-
-	now := time.Now()
-	updatedAtNs := now.UTC().UnixNano()
-	newID := imemory.ApplyMemoryUpdate(
-		entry,
-		memoryKey.AppName,
-		memoryKey.UserID,
-		memoryStr,
-		topics,
-		ep,
-		now,
-	)
-	participantsJSON, err := marshalStringSlice(entry.Memory.Participants)
-	if err != nil {
-		return fmt.Errorf("marshal participants: %w", err)
-	}
 	query := fmt.Sprintf(
 		`UPDATE %s SET
 embedding = `+sqlVectorFromBlob+`,
 updated_at = ?, memory_content = ?, topics = ?,
-memory_kind = ?, event_time = ?, participants = ?, location = ?
+memory_kind = ?, event_time = ?, participants = ?, location = ?,
+consolidation = ?
 WHERE app_name = ? AND user_id = ? AND memory_id = ?`,
 		s.tableName,
 	)
@@ -442,6 +464,7 @@ WHERE app_name = ? AND user_id = ? AND memory_id = ?`,
 		metadataEventTimeNS(entry.Memory.EventTime),
 		participantsJSON,
 		metadataLocationValue(entry.Memory.Location),
+		consolidationJSON,
 		memoryKey.AppName,
 		memoryKey.UserID,
 		memoryKey.MemoryID,
@@ -469,6 +492,7 @@ WHERE app_name = ? AND user_id = ? AND memory_id = ?`,
 			blob,
 			topicsJSON,
 			participantsJSON,
+			consolidationJSON,
 			updatedAtNs,
 		)
 		if err != nil {
@@ -482,6 +506,34 @@ WHERE app_name = ? AND user_id = ? AND memory_id = ?`,
 	return nil
 }
 
+// updateConsolidation saves the consolidation fields of a memory whose
+// content is unchanged, keeping its embedding and update time.
+func (s *Service) updateConsolidation(
+	ctx context.Context,
+	memoryKey memory.Key,
+	consolidationJSON string,
+) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET consolidation = ?
+WHERE app_name = ? AND user_id = ? AND memory_id = ?`,
+		s.tableName,
+	)
+	query += fmt.Sprintf(" AND deleted_at = %d", notDeletedAtNs)
+	res, err := s.db.ExecContext(ctx, query, consolidationJSON,
+		memoryKey.AppName, memoryKey.UserID, memoryKey.MemoryID)
+	if err != nil {
+		return fmt.Errorf("update memory consolidation: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update memory consolidation rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("memory with id %s not found", memoryKey.MemoryID)
+	}
+	return nil
+}
+
 // rotateMemory replaces a memory entry with a new ID in a transaction.
 //
 //nolint:gosec // All interpolated table names are validated by WithTableName.
@@ -494,6 +546,7 @@ func (s *Service) rotateMemory(
 	blob []byte,
 	topicsJSON []byte,
 	participantsJSON string,
+	consolidationJSON string,
 	updatedAtNs int64,
 ) error {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -530,7 +583,8 @@ func (s *Service) rotateMemory(
 embedding = `+sqlVectorFromBlob+`,
 updated_at = ?, deleted_at = ?,
 memory_content = ?, topics = ?, memory_kind = ?,
-event_time = ?, participants = ?, location = ?
+event_time = ?, participants = ?, location = ?,
+consolidation = ?
 WHERE app_name = ? AND user_id = ? AND memory_id = ?
 AND deleted_at != %d`,
 			s.tableName,
@@ -548,6 +602,7 @@ AND deleted_at != %d`,
 			metadataEventTimeNS(entry.Memory.EventTime),
 			participantsJSON,
 			metadataLocationValue(entry.Memory.Location),
+			consolidationJSON,
 			memoryKey.AppName,
 			memoryKey.UserID,
 			newID,
@@ -595,8 +650,8 @@ AND deleted_at != %d`,
 memory_id, embedding, app_name, user_id,
 created_at, updated_at, deleted_at,
 memory_content, topics, memory_kind, event_time,
-participants, location
-) VALUES (?, `+sqlVectorFromBlob+`, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+participants, location, consolidation
+) VALUES (?, `+sqlVectorFromBlob+`, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			s.tableName,
 		)
 		if _, err := tx.ExecContext(
@@ -615,6 +670,7 @@ participants, location
 			metadataEventTimeNS(entry.Memory.EventTime),
 			participantsJSON,
 			metadataLocationValue(entry.Memory.Location),
+			consolidationJSON,
 		); err != nil {
 			return fmt.Errorf("insert rotated memory target: %w", err)
 		}
@@ -754,7 +810,7 @@ func (s *Service) ReadMemories(
 
 	const selectSQL = `SELECT
 memory_id, memory_content, topics, memory_kind, event_time,
-participants, location, created_at, updated_at
+participants, location, consolidation, created_at, updated_at
 FROM %s WHERE app_name = ? AND user_id = ?`
 	query := fmt.Sprintf(selectSQL, s.tableName)
 	args := []any{userKey.AppName, userKey.UserID}
@@ -904,6 +960,7 @@ func scanEntry(
 		eventTimeNs   sql.NullInt64
 		participants  sql.NullString
 		location      sql.NullString
+		consolidation sql.NullString
 		createdAtNs   int64
 		updatedAtNs   int64
 	)
@@ -915,6 +972,7 @@ func scanEntry(
 		&eventTimeNs,
 		&participants,
 		&location,
+		&consolidation,
 		&createdAtNs,
 		&updatedAtNs,
 	); err != nil {
@@ -930,6 +988,7 @@ func scanEntry(
 		eventTimeNs,
 		participants,
 		location,
+		consolidation,
 		createdAtNs,
 		updatedAtNs,
 	)
@@ -948,6 +1007,7 @@ func scanSearchEntryWithSimilarity(
 		eventTimeNs   sql.NullInt64
 		participants  sql.NullString
 		location      sql.NullString
+		consolidation sql.NullString
 		createdAtNs   int64
 		updatedAtNs   int64
 		distance      float64
@@ -960,6 +1020,7 @@ func scanSearchEntryWithSimilarity(
 		&eventTimeNs,
 		&participants,
 		&location,
+		&consolidation,
 		&createdAtNs,
 		&updatedAtNs,
 		&distance,
@@ -976,6 +1037,7 @@ func scanSearchEntryWithSimilarity(
 		eventTimeNs,
 		participants,
 		location,
+		consolidation,
 		createdAtNs,
 		updatedAtNs,
 	)
@@ -996,6 +1058,7 @@ func buildScannedEntry(
 	eventTimeNs sql.NullInt64,
 	participants sql.NullString,
 	location sql.NullString,
+	consolidation sql.NullString,
 	createdAtNs int64,
 	updatedAtNs int64,
 ) (*memory.Entry, error) {
@@ -1032,6 +1095,12 @@ func buildScannedEntry(
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
 	}
+	if err := imemory.UnmarshalConsolidation(
+		entry.Memory,
+		consolidation.String,
+	); err != nil {
+		return nil, fmt.Errorf("unmarshal consolidation: %w", err)
+	}
 	imemory.NormalizeEntry(entry)
 	return entry, nil
 }
@@ -1176,7 +1245,7 @@ func (s *Service) searchWithOptions(
 
 	const searchSQL = `SELECT
 memory_id, memory_content, topics, memory_kind, event_time,
-participants, location, created_at, updated_at, distance
+participants, location, consolidation, created_at, updated_at, distance
 FROM %s
 WHERE embedding MATCH ` + sqlVectorFromBlob + `
 AND k = ?
@@ -163,7 +163,8 @@ CREATE TABLE memories (
 	memory_kind TEXT,
 	event_time INTEGER,
 	participants TEXT,
-	location TEXT
+	location TEXT,
+	consolidation TEXT
 )`)
 	require.NoError(t, err)
 
@@ -520,6 +521,76 @@ func TestService_EpisodicMetadataRoundTrip(t *testing.T) {
 	require.Equal(t, "Kyoto", got[0].Memory.Location)
 }
 
+func TestService_ConsolidationMetadataRoundTrip(t *testing.T) {
+	db, cleanup := openTempSQLiteDB(t)
+	defer cleanup()
+
+	svc, err := NewService(
+		db,
+		WithEmbedder(&mockEmbedder{dimension: 2}),
+		WithIndexDimension(2),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	ctx := context.Background()
+	userKey := memory.UserKey{AppName: "app", UserID: "u1"}
+	recorded := time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.AddMemory(
+		ctx,
+		userKey,
+		"alpha",
+		nil,
+		memory.WithMetadata(&memory.Metadata{
+			Provenance: []memory.Provenance{
+				{SessionID: "s1", EventID: "e1", Time: recorded},
+			},
+		}),
+	))
+
+	got, err := svc.ReadMemories(ctx, userKey, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, []memory.Provenance{
+		{SessionID: "s1", EventID: "e1", Time: recorded},
+	}, got[0].Memory.Provenance)
+
+	accessed := recorded.Add(time.Hour)
+	memKey := memory.Key{
+		AppName:  userKey.AppName,
+		UserID:   userKey.UserID,
+		MemoryID: got[0].ID,
+	}
+	require.NoError(t, svc.UpdateMemory(
+		ctx,
+		memKey,
+		"alpha merged",
+		nil,
+		memory.WithUpdateMetadata(&memory.Metadata{
+			Provenance: []memory.Provenance{
+				{SessionID: "s2", EventID: "e2", Time: recorded},
+			},
+			Supersedes: []memory.Supersession{
+				{MemoryID: "old", Memory: "beta", Time: recorded},
+			},
+			AccessCount:    3,
+			LastAccessedAt: &accessed,
+		}),
+	))
+
+	got, err = svc.SearchMemories(ctx, userKey, "alpha merged")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Memory.Provenance, 2)
+	require.Equal(t, []memory.Supersession{
+		{MemoryID: "old", Memory: "beta", Time: recorded},
+	}, got[0].Memory.Supersedes)
+	require.Equal(t, 3, got[0].Memory.AccessCount)
+	require.NotNil(t, got[0].Memory.LastAccessedAt)
+	require.True(t, accessed.Equal(*got[0].Memory.LastAccessedAt))
+}
+
 func TestService_UpdateMemory_PreservesMetadataWhenNotProvided(t *testing.T) {
 	db, cleanup := openTempSQLiteDB(t)
 	defer cleanup()
@@ -615,6 +686,55 @@ func TestService_UpdateMemory_SameIdentityKeepsID(t *testing.T) {
 	require.Equal(t, []string{"new"}, got[0].Memory.Topics)
 }
 
+// countingEmbedder counts the embeddings it generates.
+type countingEmbedder struct {
+	mockEmbedder
+	calls int
+}
+
+func (c *countingEmbedder) GetEmbedding(ctx context.Context, text string) ([]float64, error) {
+	c.calls++
+	return c.mockEmbedder.GetEmbedding(ctx, text)
+}
+
+func TestService_UpdateMemory_MetadataOnly(t *testing.T) {
+	db, cleanup := openTempSQLiteDB(t)
+	defer cleanup()
+
+	emb := &countingEmbedder{mockEmbedder: mockEmbedder{dimension: 2}}
+	svc, err := NewService(db, WithEmbedder(emb), WithIndexDimension(2))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	ctx := context.Background()
+	userKey := memory.UserKey{AppName: "app", UserID: "u1"}
+	require.NoError(t, svc.AddMemory(ctx, userKey, "alpha", []string{"t"}))
+	got, err := svc.ReadMemories(ctx, userKey, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	before := got[0]
+	calls := emb.calls
+
+	memKey := memory.Key{AppName: userKey.AppName, UserID: userKey.UserID, MemoryID: before.ID}
+	accessed := time.Now().UTC()
+	require.NoError(t, svc.UpdateMemory(ctx, memKey, "alpha", []string{"t"},
+		memory.WithUpdateMetadata(&memory.Metadata{AccessCount: 3, LastAccessedAt: &accessed}),
+		memory.WithMetadataOnlyUpdate()))
+	require.Equal(t, calls, emb.calls, "unchanged content is not embedded again")
+
+	got, err = svc.ReadMemories(ctx, userKey, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, before.ID, got[0].ID)
+	require.Equal(t, 3, got[0].Memory.AccessCount)
+	require.True(t, before.UpdatedAt.Equal(got[0].UpdatedAt))
+
+	// Changed content is embedded and updated as usual.
+	require.NoError(t, svc.UpdateMemory(ctx, memKey, "beta", []string{"t"},
+		memory.WithMetadataOnlyUpdate()))
+	require.Equal(t, calls+1, emb.calls)
+}
+
 func TestService_UpdateMemory_ActiveIDConflictPreservesEntries(t *testing.T) {
 	db, cleanup := openTempSQLiteDB(t)
 	defer cleanup()
@@ -1696,7 +1816,8 @@ CREATE TABLE memories (
   memory_kind TEXT,
   event_time INTEGER,
   participants TEXT,
-  location TEXT
+  location TEXT,
+  consolidation TEXT
 )`)
 		require.NoError(t, err)
 
@@ -1774,6 +1895,60 @@ func TestNewService_MigratesLegacySchema(t *testing.T) {
 	)
 }
 
+func TestNewService_MigratesEpisodicSchema(t *testing.T) {
+	db, cleanup := openTempSQLiteDB(t)
+	defer cleanup()
+
+	vecAuto()
+	_, err := db.Exec(`
+CREATE VIRTUAL TABLE memories USING vec0(
+  memory_id text primary key,
+  embedding float[2] distance_metric=cosine,
+  app_name text,
+  user_id text,
+  created_at integer,
+  updated_at integer,
+  deleted_at integer,
+  +memory_content text,
+  +topics text,
+  +memory_kind text,
+  +event_time integer,
+  +participants text,
+  +location text
+)`)
+	require.NoError(t, err)
+	blob, err := vecSerializeFloat32([]float32{1, 0})
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO memories (
+memory_id, embedding, app_name, user_id,
+created_at, updated_at, deleted_at,
+memory_content, topics, memory_kind, location
+) VALUES ('m1', vec_f32(?), 'app', 'u1', 1, 2, 0, 'alpha', '["pref"]', 'fact', 'Kyoto')`,
+		blob,
+	)
+	require.NoError(t, err)
+
+	svc, err := NewService(
+		db,
+		WithEmbedder(&mockEmbedder{dimension: 2}),
+		WithIndexDimension(2),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	entries, err := svc.ReadMemories(
+		context.Background(),
+		memory.UserKey{AppName: "app", UserID: "u1"},
+		10,
+	)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "m1", entries[0].ID)
+	require.Equal(t, "Kyoto", entries[0].Memory.Location)
+	require.Empty(t, entries[0].Memory.Provenance)
+	require.NoError(t, svc.ensureSchemaColumns(context.Background()))
+}
+
 func TestNewService_RestoresSchemaBackup(t *testing.T) {
 	db, cleanup := openTempSQLiteDB(t)
 	defer cleanup()
@@ -1882,7 +2057,8 @@ CREATE VIRTUAL TABLE memories USING vec0(
   +memory_kind text,
   +event_time integer,
   +participants text,
-  +location text
+  +location text,
+  +consolidation text
 )`)
 	require.NoError(t, err)
 }
@@ -2036,7 +2212,7 @@ func TestScanEntryAndStringSliceHelpers(t *testing.T) {
 	defer cleanup()
 
 	rows, err := db.Query(
-		`SELECT 'id', 'alpha', '["topic"]', '', NULL, '[" Bob ","bob"]', ' Kyoto ', 0, 0`,
+		`SELECT 'id', 'alpha', '["topic"]', '', NULL, '[" Bob ","bob"]', ' Kyoto ', NULL, 0, 0`,
 	)
 	require.NoError(t, err)
 	defer rows.Close()
@@ -2049,7 +2225,7 @@ func TestScanEntryAndStringSliceHelpers(t *testing.T) {
 	require.Equal(t, "Kyoto", entry.Memory.Location)
 
 	rows, err = db.Query(
-		`SELECT 'id', 'alpha', '["topic"]', '', NULL, 'not-json', 'Kyoto', 0, 0`,
+		`SELECT 'id', 'alpha', '["topic"]', '', NULL, 'not-json', 'Kyoto', NULL, 0, 0`,
 	)
 	require.NoError(t, err)
 	defer rows.Close()
@@ -2308,3 +2484,44 @@ func TestNewService_InitDBError(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, svc)
 }
+
+func TestService_SharedScopes(t *testing.T) {
+	db, cleanup := openTempSQLiteDB(t)
+	defer cleanup()
+
+	svc, err := NewService(
+		db,
+		WithEmbedder(&mockEmbedder{dimension: 2}),
+		WithIndexDimension(2),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	ctx := context.Background()
+	userKey := memory.UserKey{AppName: "app", UserID: "u1"}
+	group := memory.Scope{Kind: memory.ScopeGroup, ID: "team"}
+	require.NoError(t, svc.AddMemory(ctx, userKey, "alpha", nil))
+	require.NoError(t, svc.AddMemory(ctx, group.UserKey("app"), "beta", nil))
+
+	got, err := svc.ReadMemories(ctx, group.UserKey("app"), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "beta", got[0].Memory.Memory)
+
+	reader := memory.NewScopedReader(svc, &memory.ScopeAccess{
+		Read:    []memory.ScopeKind{memory.ScopeUser, memory.ScopeGroup},
+		GroupID: "team",
+	})
+	results, err := reader.SearchMemories(ctx, userKey, "beta")
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+
+	require.NoError(t, svc.ClearMemories(ctx, group.UserKey("app")))
+	got, err = svc.ReadMemories(ctx, userKey, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.ErrorIs(t, svc.AddMemory(ctx,
+		memory.UserKey{AppName: "app", UserID: "scope:other"}, "x", nil),
+		memory.ErrReservedUserID)
+}
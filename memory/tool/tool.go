@@ -24,7 +24,7 @@ import (
 )
 
 const (
-	memoryToolScopeNote       = "All memory tools operate only on memories already scoped to the current app and current user."
+	memoryToolScopeNote       = "All memory tools operate only on memories already scoped to the current app and current user, plus any shared scopes this conversation is permitted to use."
 	memoryReadDirectUseNote   = "If the current request depends on remembered context call the tool directly instead of adding an extra permission round trip."
 	memoryWriteDirectUseNote  = "If the user is clearly asking to remember correct or forget something carry out the memory operation directly."
 	memoryCaptureGuidance     = "Store concise factual memories that help future conversations feel contextual and avoid asking the same question again. Avoid guesses duplicates trivial one off details and sensitive data unless it is needed for the task."
@@ -46,6 +46,8 @@ const (
 	timeBeforeDescription        = "Optional upper bound for episode event_time in ISO 8601 date format."
 	orderByEventTimeDescription  = "When true order results by event time instead of relevance. Useful for sequence or timeline questions."
 	loadLimitDescription         = "Maximum number of recent memories to load. Defaults to 10."
+	writeScopeDescription        = "Optional scope to store the memory in. Use 'user' for the current user and 'agent' 'group' or 'app' only for knowledge that should be shared and only when that scope is permitted. Leave empty for the default."
+	targetScopeDescription       = "Scope of the stored memory as returned by memory_search or memory_load. Leave empty for user memories."
 )
 
 // Memory function implementations using function.NewFunctionTool.
@@ -75,7 +77,11 @@ func NewAddTool() tool.CallableTool {
 			req.Topics = []string{}
 		}
 
-		userKey := memory.UserKey{AppName: appName, UserID: userID}
+		scope, err := scopeAccessFromContext(ctx).WriteScope(memory.ScopeKind(req.Scope), userID)
+		if err != nil {
+			return nil, fmt.Errorf("memory add tool: %v", err)
+		}
+		userKey := scope.UserKey(appName)
 		ep := buildMetadata(req.MemoryKind, req.EventTime, req.Participants, req.Location)
 		var opts []memory.AddOption
 		if ep != nil {
@@ -90,6 +96,7 @@ func NewAddTool() tool.CallableTool {
 			Message: "Memory added successfully",
 			Memory:  req.Memory,
 			Topics:  req.Topics,
+			Scope:   sharedScopeName(scope),
 		}, nil
 	}
 
@@ -133,7 +140,11 @@ func NewUpdateTool() tool.CallableTool {
 			req.Topics = []string{}
 		}
 
-		memoryKey := memory.Key{AppName: appName, UserID: userID, MemoryID: req.MemoryID}
+		scope, err := scopeAccessFromContext(ctx).ModifyScope(memory.ScopeKind(req.Scope), userID)
+		if err != nil {
+			return nil, fmt.Errorf("memory update tool: %v", err)
+		}
+		memoryKey := scope.Key(appName, req.MemoryID)
 		ep := buildMetadata(req.MemoryKind, req.EventTime, req.Participants, req.Location)
 		result := &memory.UpdateResult{MemoryID: req.MemoryID}
 		var opts []memory.UpdateOption
@@ -185,7 +196,11 @@ func NewDeleteTool() tool.CallableTool {
 			return nil, fmt.Errorf("memory delete tool: memory ID is required for app %s and user %s", appName, userID)
 		}
 
-		memoryKey := memory.Key{AppName: appName, UserID: userID, MemoryID: req.MemoryID}
+		scope, err := scopeAccessFromContext(ctx).ModifyScope(memory.ScopeKind(req.Scope), userID)
+		if err != nil {
+			return nil, fmt.Errorf("memory delete tool: %v", err)
+		}
+		memoryKey := scope.Key(appName, req.MemoryID)
 		err = memoryService.DeleteMemory(ctx, memoryKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to delete memory: %v", err)
@@ -211,7 +226,7 @@ func NewDeleteTool() tool.CallableTool {
 
 // NewClearTool creates a function tool for clearing all memories.
 func NewClearTool() tool.CallableTool {
-	clearFunc := func(ctx context.Context, _ *ClearMemoryRequest) (*ClearMemoryResponse, error) {
+	clearFunc := func(ctx context.Context, req *ClearMemoryRequest) (*ClearMemoryResponse, error) {
 		// Get MemoryService from context.
 		memoryService, err := GetMemoryServiceFromContext(ctx)
 		if err != nil {
@@ -224,8 +239,15 @@ func NewClearTool() tool.CallableTool {
 			return nil, fmt.Errorf("memory clear tool: failed to get app and user from context: %v", err)
 		}
 
-		userKey := memory.UserKey{AppName: appName, UserID: userID}
-		err = memoryService.ClearMemories(ctx, userKey)
+		var scopeKind memory.ScopeKind
+		if req != nil {
+			scopeKind = memory.ScopeKind(req.Scope)
+		}
+		scope, err := scopeAccessFromContext(ctx).ModifyScope(scopeKind, userID)
+		if err != nil {
+			return nil, fmt.Errorf("memory clear tool: %v", err)
+		}
+		err = memoryService.ClearMemories(ctx, scope.UserKey(appName))
 		if err != nil {
 			return nil, fmt.Errorf("memory clear tool: failed to clear memories: %v", err)
 		}
@@ -272,7 +294,7 @@ func NewSearchTool() tool.CallableTool {
 
 		userKey := memory.UserKey{AppName: appName, UserID: userID}
 		opts := buildSearchOptions(req)
-		memories, err := memoryService.SearchMemories(ctx, userKey,
+		memories, err := scopedReader(ctx, memoryService).SearchMemories(ctx, userKey,
 			opts.Query, memory.WithSearchOptions(opts))
 		if err != nil {
 			return nil, fmt.Errorf("failed to search memories: %v", err)
@@ -332,7 +354,7 @@ func NewLoadTool() tool.CallableTool {
 		}
 
 		userKey := memory.UserKey{AppName: appName, UserID: userID}
-		memories, err := memoryService.ReadMemories(ctx, userKey, limit)
+		memories, err := scopedReader(ctx, memoryService).ReadMemories(ctx, userKey, limit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load memories: %v", err)
 		}
@@ -373,6 +395,7 @@ func addMemoryInputSchema() *tool.Schema {
 			participantsDescription,
 		),
 		"location": stringSchema(locationDescription),
+		"scope":    scopeSchema(writeScopeDescription),
 	}, "memory")
 }
 
@@ -387,18 +410,21 @@ func updateMemoryInputSchema() *tool.Schema {
 			participantsDescription,
 		),
 		"location": stringSchema(locationDescription),
+		"scope":    scopeSchema(targetScopeDescription),
 	}, "memory_id", "memory")
 }
 
 func deleteMemoryInputSchema() *tool.Schema {
 	return objectSchema(map[string]*tool.Schema{
 		"memory_id": stringSchema(deleteMemoryIDDescription),
+		"scope":     scopeSchema(targetScopeDescription),
 	}, "memory_id")
 }
 
 func clearMemoryInputSchema() *tool.Schema {
 	return objectSchema(map[string]*tool.Schema{
 		"reason": stringSchema(clearReasonDescription),
+		"scope":  scopeSchema(writeScopeDescription),
 	})
 }
 
@@ -457,6 +483,15 @@ func stringArraySchema(description string) *tool.Schema {
 	}
 }
 
+func scopeSchema(description string) *tool.Schema {
+	return stringEnumSchema(description,
+		string(memory.ScopeUser),
+		string(memory.ScopeAgent),
+		string(memory.ScopeGroup),
+		string(memory.ScopeApp),
+	)
+}
+
 func stringEnumSchema(description string, values ...string) *tool.Schema {
 	enum := make([]any, 0, len(values))
 	for _, value := range values {
@@ -508,6 +543,9 @@ func GetAppAndUserFromContext(ctx context.Context) (string, string, error) {
 
 	// Session has AppName and UserID fields.
 	if invocation.Session.AppName != "" && invocation.Session.UserID != "" {
+		if err := memory.CheckUserID(invocation.Session.UserID); err != nil {
+			return "", "", err
+		}
 		return invocation.Session.AppName, invocation.Session.UserID, nil
 	}
 
@@ -516,6 +554,41 @@ func GetAppAndUserFromContext(ctx context.Context) (string, string, error) {
 		invocation.Session.AppName, invocation.Session.UserID)
 }
 
+// scopeAccessFromContext returns the caller's memory scope access. When the
+// access leaves the agent scope unnamed, it defaults to the running agent.
+func scopeAccessFromContext(ctx context.Context) *memory.ScopeAccess {
+	access := memory.ScopeAccessFromContext(ctx)
+	if access == nil || access.AgentName != "" {
+		return access
+	}
+	invocation, ok := agent.InvocationFromContext(ctx)
+	if !ok || invocation == nil || invocation.AgentName == "" {
+		return access
+	}
+	resolved := *access
+	resolved.AgentName = invocation.AgentName
+	return &resolved
+}
+
+// scopedReader reads through the permitted scopes when the caller has a
+// scope access, and through the user scope only otherwise.
+func scopedReader(ctx context.Context, reader memory.Reader) memory.Reader {
+	access := scopeAccessFromContext(ctx)
+	if access == nil {
+		return reader
+	}
+	return memory.NewScopedReader(reader, access)
+}
+
+// sharedScopeName returns the scope name reported to the model, which is
+// empty for user memories to keep results unchanged without shared scopes.
+func sharedScopeName(scope memory.Scope) string {
+	if scope.Kind == memory.ScopeUser {
+		return ""
+	}
+	return string(scope.Kind)
+}
+
 // buildMetadata constructs MemoryMetadata from tool
 // request strings. Returns nil if no episodic data is
 // provided (backward compatible).
@@ -645,5 +718,6 @@ func entryToResult(e *memory.Entry) Result {
 		r.Location = e.Memory.Location
 	}
 	r.Score = e.Score
+	r.Scope = sharedScopeName(memory.ScopeFromUserID(e.UserID))
 	return r
 }
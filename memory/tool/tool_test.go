@@ -1373,3 +1373,72 @@ func TestEntryToResult(t *testing.T) {
 	assert.Equal(t, "Kyoto", got.Location)
 	assert.Equal(t, 0.91, got.Score)
 }
+
+func TestMemoryTool_SharedScopes(t *testing.T) {
+	service := newMockMemoryService()
+	ctx := memory.WithScopeAccess(
+		createMockContext("test-app", "test-user", service),
+		&memory.ScopeAccess{
+			Read:    []memory.ScopeKind{memory.ScopeUser, memory.ScopeAgent, memory.ScopeGroup},
+			Write:   []memory.ScopeKind{memory.ScopeUser, memory.ScopeGroup},
+			GroupID: "team",
+		},
+	)
+	call := func(tl tool.CallableTool, args map[string]any) (any, error) {
+		jsonArgs, err := json.Marshal(args)
+		require.NoError(t, err)
+		return tl.Call(ctx, jsonArgs)
+	}
+
+	require.NoError(t, service.AddMemory(context.Background(),
+		memory.UserKey{AppName: "test-app", UserID: "scope:agent:test-agent"},
+		"Escalate billing issues to finance", nil))
+
+	result, err := call(NewAddTool(), map[string]any{"memory": "Team deploys on Fridays", "scope": "group"})
+	require.NoError(t, err)
+	added := result.(*AddMemoryResponse)
+	assert.Equal(t, "group", added.Scope)
+
+	_, err = call(NewAddTool(), map[string]any{"memory": "Team uses Jira", "scope": "app"})
+	assert.Error(t, err, "app scope is not writable")
+
+	result, err = call(NewAddTool(), map[string]any{"memory": "User deploys on Mondays"})
+	require.NoError(t, err)
+	assert.Empty(t, result.(*AddMemoryResponse).Scope)
+
+	result, err = call(NewSearchTool(), map[string]any{"query": "deploys"})
+	require.NoError(t, err)
+	search := result.(*SearchMemoryResponse)
+	require.Len(t, search.Results, 2)
+	scopes := []string{search.Results[0].Scope, search.Results[1].Scope}
+	assert.ElementsMatch(t, []string{"", "group"}, scopes)
+
+	result, err = call(NewLoadTool(), map[string]any{"limit": 10})
+	require.NoError(t, err)
+	assert.Len(t, result.(*LoadMemoryResponse).Results, 3, "agent scope defaults to the running agent")
+
+	groupKey := memory.UserKey{AppName: "test-app", UserID: "scope:group:team"}
+	groupMemories, err := service.ReadMemories(context.Background(), groupKey, 0)
+	require.NoError(t, err)
+	require.Len(t, groupMemories, 1)
+
+	_, err = call(NewDeleteTool(), map[string]any{"memory_id": groupMemories[0].ID, "scope": "group"})
+	require.NoError(t, err)
+	groupMemories, err = service.ReadMemories(context.Background(), groupKey, 0)
+	require.NoError(t, err)
+	assert.Empty(t, groupMemories)
+
+	_, err = call(NewClearTool(), map[string]any{"scope": "agent"})
+	assert.Error(t, err, "agent scope is read-only")
+
+	require.NoError(t, service.AddMemory(context.Background(), groupKey, "Team uses Go", nil))
+	_, err = call(NewClearTool(), map[string]any{})
+	require.NoError(t, err)
+	groupMemories, err = service.ReadMemories(context.Background(), groupKey, 0)
+	require.NoError(t, err)
+	assert.Len(t, groupMemories, 1, "a clear without a scope keeps shared memories")
+	userMemories, err := service.ReadMemories(context.Background(),
+		memory.UserKey{AppName: "test-app", UserID: "test-user"}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, userMemories)
+}
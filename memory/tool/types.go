@@ -25,6 +25,7 @@ type Result struct {
 	Participants []string  `json:"participants,omitempty"` // Participants involved in the event.
 	Location     string    `json:"location,omitempty"`     // Location where the event took place.
 	Score        float64   `json:"score,omitempty"`        // Score is the similarity score from vector search (0-1).
+	Scope        string    `json:"scope,omitempty"`        // Scope is the shared memory scope; empty for user memories.
 }
 
 // AddMemoryRequest represents the input for the add memory tool.
@@ -35,13 +36,15 @@ type AddMemoryRequest struct {
 	EventTime    string   `json:"event_time,omitempty" description:"When the event occurred (ISO 8601: YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS). Required for episodes. Must be an absolute date - never use relative time words."`
 	Participants []string `json:"participants,omitempty" description:"People involved in the event. Used for episodes."`
 	Location     string   `json:"location,omitempty" description:"Where the event took place. Used for episodes."`
+	Scope        string   `json:"scope,omitempty" description:"Optional memory scope: user, agent, group or app. Empty selects the default writable scope."`
 }
 
 // AddMemoryResponse represents the response from memory_add tool.
 type AddMemoryResponse struct {
-	Message string   `json:"message"` // Message is the success message.
-	Memory  string   `json:"memory"`  // Memory is the memory content that was added.
-	Topics  []string `json:"topics"`  // Topics is the topics associated with the memory.
+	Message string   `json:"message"`         // Message is the success message.
+	Memory  string   `json:"memory"`          // Memory is the memory content that was added.
+	Topics  []string `json:"topics"`          // Topics is the topics associated with the memory.
+	Scope   string   `json:"scope,omitempty"` // Scope is the shared scope written to; empty for the user scope.
 }
 
 // UpdateMemoryRequest represents the input for the update memory tool.
@@ -53,6 +56,7 @@ type UpdateMemoryRequest struct {
 	EventTime    string   `json:"event_time,omitempty" description:"When the event occurred (ISO 8601). Required for episodes."`
 	Participants []string `json:"participants,omitempty" description:"People involved in the event."`
 	Location     string   `json:"location,omitempty" description:"Where the event took place."`
+	Scope        string   `json:"scope,omitempty" description:"Scope of the memory to update, as returned by memory_search or memory_load."`
 }
 
 // UpdateMemoryResponse represents the response from memory_update tool.
@@ -66,6 +70,7 @@ type UpdateMemoryResponse struct {
 // DeleteMemoryRequest represents the input for the delete memory tool.
 type DeleteMemoryRequest struct {
 	MemoryID string `json:"memory_id" description:"The ID of the memory to delete"`
+	Scope    string `json:"scope,omitempty" description:"Scope of the memory to delete, as returned by memory_search or memory_load."`
 }
 
 // DeleteMemoryResponse represents the response from memory_delete tool.
@@ -79,6 +84,7 @@ type DeleteMemoryResponse struct {
 // a non-empty properties object for compatibility with strict validators.
 type ClearMemoryRequest struct {
 	Reason string `json:"reason,omitempty" description:"Optional reason for clearing all memories"`
+	Scope  string `json:"scope,omitempty" description:"Optional memory scope to clear. Empty clears the memories of the current user only."`
 }
 
 // ClearMemoryResponse represents the response from memory_clear tool.
@@ -83,7 +83,9 @@ type Model struct {
 	cacheSystemPrompt bool
 	cacheTools        bool
 	cacheMessages     bool
-	showToolCallDelta bool
+	// cacheMessagesBreakpoints is the number of history breakpoints.
+	cacheMessagesBreakpoints int
+	showToolCallDelta        bool
 }
 
 // New creates a new Anthropic model adapter.
@@ -133,6 +135,7 @@ func New(name string, opts ...Option) *Model {
 		cacheSystemPrompt:          o.cacheSystemPrompt,
 		cacheTools:                 o.cacheTools,
 		cacheMessages:              o.cacheMessages,
+		cacheMessagesBreakpoints:   o.cacheMessagesBreakpoints,
 		showToolCallDelta:          o.showToolCallDelta,
 	}
 }
@@ -346,13 +349,14 @@ func (m *Model) buildChatRequest(request *model.Request) (*anthropic.MessageNewP
 	// Convert tools
 	tools := convertTools(request.Tools)
 
-	// Apply cache control breakpoints if any cache option is enabled.
+	// Apply cache control breakpoints if any cache option or the request
+	// cache hint is enabled.
 	// Uses multiple independent breakpoints (up to 4 allowed by Anthropic) for optimal caching:
 	// - System prompt breakpoint: caches stable system instructions
 	// - Tools breakpoint: caches stable tool definitions
-	// - Messages breakpoint: caches conversation history at last assistant message
-	if m.cacheSystemPrompt || m.cacheTools || m.cacheMessages {
-		systemPrompts, tools, messages = m.applyCacheControl(systemPrompts, tools, messages)
+	// - Messages breakpoints: cache conversation history at the last assistant message
+	if cache := m.promptCache(request.PromptCache); cache.Enabled() {
+		systemPrompts, tools, messages = m.applyCacheControl(cache, systemPrompts, tools, messages)
 	}
 
 	// Build chat request.
@@ -461,6 +465,34 @@ func modelNameMatches(modelName string, targets ...string) bool {
 	return false
 }
 
+// promptCache merges the cache options of the model with the cache hint of
+// a request. A part is cached when either of them marks it.
+func (m *Model) promptCache(hint *model.PromptCache) *model.PromptCache {
+	cache := &model.PromptCache{
+		System:  m.cacheSystemPrompt,
+		Tools:   m.cacheTools,
+		History: m.cacheMessages,
+	}
+	if hint != nil {
+		cache.System = cache.System || hint.System
+		cache.Tools = cache.Tools || hint.Tools
+		cache.History = cache.History || hint.History
+		cache.TTL = hint.TTL
+	}
+	return cache
+}
+
+// cacheControl returns the cache control breakpoint for ttl. Anthropic
+// keeps entries for 5 minutes or 1 hour, so a TTL of at least an hour
+// selects the longer one.
+func cacheControl(ttl time.Duration) anthropic.CacheControlEphemeralParam {
+	cc := anthropic.NewCacheControlEphemeralParam()
+	if ttl >= time.Hour {
+		cc.TTL = anthropic.CacheControlEphemeralTTLTTL1h
+	}
+	return cc
+}
+
 // applyCacheControl applies independent cache control breakpoints.
 // Unlike the previous single-breakpoint strategy, this sets multiple breakpoints
 // independently (Anthropic supports up to 4). This ensures stable content like
@@ -468,45 +500,54 @@ func modelNameMatches(modelName string, targets ...string) bool {
 // message caching is also enabled.
 //
 // Breakpoints applied (each independent):
-//   - System prompt: always cached when cacheSystemPrompt is true (stable across turns)
-//   - Tools: always cached when cacheTools is true (stable across turns)
-//   - Last assistant message: cached when cacheMessages is true (opt-in, benefits multi-turn)
+//   - System prompt: cached when cache.System is true (stable across turns)
+//   - Tools: cached when cache.Tools is true (stable across turns)
+//   - Last assistant message: cached when cache.History is true (benefits multi-turn),
+//     plus the one before it when WithCacheMessagesBreakpoints(2) is set
 func (m *Model) applyCacheControl(
+	cache *model.PromptCache,
 	systemPrompts []anthropic.TextBlockParam,
 	tools []anthropic.ToolUnionParam,
 	messages []anthropic.MessageParam,
 ) ([]anthropic.TextBlockParam, []anthropic.ToolUnionParam, []anthropic.MessageParam) {
-	if m.cacheSystemPrompt && len(systemPrompts) > 0 {
-		systemPrompts = m.applyCacheControlToSystem(systemPrompts)
+	cc := cacheControl(cache.TTL)
+	if cache.System && len(systemPrompts) > 0 {
+		systemPrompts = m.applyCacheControlToSystem(systemPrompts, cc)
 	}
-	if m.cacheTools && len(tools) > 0 {
-		tools = m.applyCacheControlToTools(tools)
+	if cache.Tools && len(tools) > 0 {
+		tools = m.applyCacheControlToTools(tools, cc)
 	}
-	if m.cacheMessages && len(messages) > 1 {
-		if idx := m.findLastAssistantMessageIndex(messages); idx >= 0 {
-			messages = m.applyCacheControlToMessages(messages, idx)
+	if cache.History && len(messages) > 1 {
+		for _, idx := range m.historyCacheBreakpoints(messages) {
+			messages = m.applyCacheControlToMessages(messages, idx, cc)
 		}
 	}
 	return systemPrompts, tools, messages
 }
 
-// findLastAssistantMessageIndex finds the index of the last assistant message
-// that is not the final message (we want to cache up to but not including the current turn).
-func (m *Model) findLastAssistantMessageIndex(messages []anthropic.MessageParam) int {
-	// We look for the last assistant message before the final user message
+// historyCacheBreakpoints returns the indexes of the assistant messages that
+// get a breakpoint. The final message is never cached since it is the
+// current turn.
+func (m *Model) historyCacheBreakpoints(messages []anthropic.MessageParam) []int {
 	// In a typical conversation: [user, assistant, user, assistant, user]
-	// We want to cache at the second-to-last assistant (index 3)
-	for i := len(messages) - 2; i >= 0; i-- {
-		if messages[i].Role == "assistant" {
-			return i
-		}
+	// the breakpoint is on the assistant message at index 3, plus index 1
+	// when two breakpoints are configured.
+	idx := model.HistoryCacheBreakpoints(len(messages), func(i int) bool {
+		return messages[i].Role == anthropic.MessageParamRoleAssistant
+	})
+	if n := max(m.cacheMessagesBreakpoints, 1); len(idx) > n {
+		idx = idx[len(idx)-n:]
 	}
-	return -1
+	return idx
 }
 
 // applyCacheControlToMessages adds cache control to a specific message.
 // This is used for multi-turn conversation caching.
-func (m *Model) applyCacheControlToMessages(messages []anthropic.MessageParam, index int) []anthropic.MessageParam {
+func (m *Model) applyCacheControlToMessages(
+	messages []anthropic.MessageParam,
+	index int,
+	cc anthropic.CacheControlEphemeralParam,
+) []anthropic.MessageParam {
 	if index < 0 || index >= len(messages) {
 		return messages
 	}
@@ -526,17 +567,17 @@ func (m *Model) applyCacheControlToMessages(messages []anthropic.MessageParam, i
 		// Apply cache control based on content type
 		if content.OfText != nil {
 			newContent := *content.OfText
-			newContent.CacheControl = anthropic.NewCacheControlEphemeralParam()
+			newContent.CacheControl = cc
 			msg.Content[i] = anthropic.ContentBlockParamUnion{OfText: &newContent}
 			cacheApplied = true
 		} else if content.OfToolResult != nil {
 			newContent := *content.OfToolResult
-			newContent.CacheControl = anthropic.NewCacheControlEphemeralParam()
+			newContent.CacheControl = cc
 			msg.Content[i] = anthropic.ContentBlockParamUnion{OfToolResult: &newContent}
 			cacheApplied = true
 		} else if content.OfToolUse != nil {
 			newContent := *content.OfToolUse
-			newContent.CacheControl = anthropic.NewCacheControlEphemeralParam()
+			newContent.CacheControl = cc
 			msg.Content[i] = anthropic.ContentBlockParamUnion{OfToolUse: &newContent}
 			cacheApplied = true
 		}
@@ -551,7 +592,10 @@ func (m *Model) applyCacheControlToMessages(messages []anthropic.MessageParam, i
 }
 
 // applyCacheControlToSystem adds cache control to the last system prompt block.
-func (m *Model) applyCacheControlToSystem(systemPrompts []anthropic.TextBlockParam) []anthropic.TextBlockParam {
+func (m *Model) applyCacheControlToSystem(
+	systemPrompts []anthropic.TextBlockParam,
+	cc anthropic.CacheControlEphemeralParam,
+) []anthropic.TextBlockParam {
 	if len(systemPrompts) == 0 {
 		return systemPrompts
 	}
@@ -561,13 +605,16 @@ func (m *Model) applyCacheControlToSystem(systemPrompts []anthropic.TextBlockPar
 	copy(result, systemPrompts)
 
 	lastIdx := len(result) - 1
-	result[lastIdx].CacheControl = anthropic.NewCacheControlEphemeralParam()
+	result[lastIdx].CacheControl = cc
 
 	return result
 }
 
 // applyCacheControlToTools adds cache control to the last tool definition.
-func (m *Model) applyCacheControlToTools(tools []anthropic.ToolUnionParam) []anthropic.ToolUnionParam {
+func (m *Model) applyCacheControlToTools(
+	tools []anthropic.ToolUnionParam,
+	cc anthropic.CacheControlEphemeralParam,
+) []anthropic.ToolUnionParam {
 	if len(tools) == 0 {
 		return tools
 	}
@@ -579,13 +626,30 @@ func (m *Model) applyCacheControlToTools(tools []anthropic.ToolUnionParam) []ant
 	lastIdx := len(result) - 1
 	if result[lastIdx].OfTool != nil {
 		toolCopy := *result[lastIdx].OfTool
-		toolCopy.CacheControl = anthropic.NewCacheControlEphemeralParam()
+		toolCopy.CacheControl = cc
 		result[lastIdx].OfTool = &toolCopy
 	}
 
 	return result
 }
 
+// convertUsage converts Anthropic usage to model usage. Anthropic counts
+// the tokens read from and written to the cache apart from the input
+// tokens, so they are only reported in the prompt token details.
+func convertUsage(usage anthropic.Usage) *model.Usage {
+	return &model.Usage{
+		PromptTokens:     int(usage.InputTokens),
+		CompletionTokens: int(usage.OutputTokens),
+		TotalTokens:      int(usage.InputTokens + usage.OutputTokens),
+		PromptTokensDetails: model.PromptTokensDetails{
+			CachedTokens:        int(usage.CacheReadInputTokens),
+			CacheCreationTokens: int(usage.CacheCreationInputTokens),
+			CacheReadTokens:     int(usage.CacheReadInputTokens),
+			CacheTokensExcluded: true,
+		},
+	}
+}
+
 // handleNonStreamingResponse sends a non-streaming request to the Anthropic API and emits exactly one final response.
 func (m *Model) handleNonStreamingResponse(
 	ctx context.Context,
@@ -623,16 +687,7 @@ func (m *Model) handleNonStreamingResponse(
 	}
 	// Set usage.
 	if message.Usage.InputTokens > 0 || message.Usage.OutputTokens > 0 {
-		response.Usage = &model.Usage{
-			PromptTokens:     int(message.Usage.InputTokens),
-			CompletionTokens: int(message.Usage.OutputTokens),
-			TotalTokens:      int(message.Usage.InputTokens + message.Usage.OutputTokens),
-			PromptTokensDetails: model.PromptTokensDetails{
-				CachedTokens:        int(message.Usage.CacheReadInputTokens),
-				CacheCreationTokens: int(message.Usage.CacheCreationInputTokens),
-				CacheReadTokens:     int(message.Usage.CacheReadInputTokens),
-			},
-		}
+		response.Usage = convertUsage(message.Usage)
 	}
 	// Emit final response.
 	select {
@@ -1017,16 +1072,7 @@ func buildStreamingFinalResponse(acc anthropic.Message) *model.Response {
 				},
 			},
 		},
-		Usage: &model.Usage{
-			PromptTokens:     int(acc.Usage.InputTokens),
-			CompletionTokens: int(acc.Usage.OutputTokens),
-			TotalTokens:      int(acc.Usage.InputTokens + acc.Usage.OutputTokens),
-			PromptTokensDetails: model.PromptTokensDetails{
-				CachedTokens:        int(acc.Usage.CacheReadInputTokens),
-				CacheCreationTokens: int(acc.Usage.CacheCreationInputTokens),
-				CacheReadTokens:     int(acc.Usage.CacheReadInputTokens),
-			},
-		},
+		Usage:     convertUsage(acc.Usage),
 		Timestamp: now,
 		Done:      true,
 		IsPartial: false,
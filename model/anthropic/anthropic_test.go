@@ -1662,8 +1662,13 @@ func Test_HandleNonStreamingResponse_EndToEnd_NoNetwork(t *testing.T) {
 	assert.Nil(t, got.Error)
 	assert.Equal(t, "hello", got.Choices[0].Message.Content)
 	assert.NotNil(t, got.Usage)
+	// Prompt tokens exclude the tokens read from and written to the cache.
 	assert.Equal(t, 3, got.Usage.PromptTokens)
 	assert.Equal(t, 4, got.Usage.CompletionTokens)
+	assert.Equal(t, 7, got.Usage.TotalTokens)
+	assert.Equal(t, 2, got.Usage.PromptTokensDetails.CacheReadTokens)
+	assert.Equal(t, 1, got.Usage.PromptTokensDetails.CacheCreationTokens)
+	assert.True(t, got.Usage.PromptTokensDetails.CacheTokensExcluded)
 	assert.True(t, calledRequest)
 	assert.True(t, calledResponse)
 }
@@ -3079,7 +3084,7 @@ func TestApplyCacheControlToSystem(t *testing.T) {
 
 	t.Run("empty prompts", func(t *testing.T) {
 		prompts := []anthropic.TextBlockParam{}
-		result := m.applyCacheControlToSystem(prompts)
+		result := m.applyCacheControlToSystem(prompts, anthropic.NewCacheControlEphemeralParam())
 		assert.Empty(t, result)
 	})
 
@@ -3087,7 +3092,7 @@ func TestApplyCacheControlToSystem(t *testing.T) {
 		prompts := []anthropic.TextBlockParam{
 			{Type: "text", Text: "System prompt"},
 		}
-		result := m.applyCacheControlToSystem(prompts)
+		result := m.applyCacheControlToSystem(prompts, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 1)
 		// Check that cache control is set on the last (and only) item
 		assert.NotEmpty(t, result[0].CacheControl.Type)
@@ -3100,7 +3105,7 @@ func TestApplyCacheControlToSystem(t *testing.T) {
 			{Type: "text", Text: "System 2"},
 			{Type: "text", Text: "System 3"},
 		}
-		result := m.applyCacheControlToSystem(prompts)
+		result := m.applyCacheControlToSystem(prompts, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 3)
 		// Check that cache control is set on the last item
 		assert.NotEmpty(t, result[2].CacheControl.Type)
@@ -3113,7 +3118,7 @@ func TestApplyCacheControlToTools(t *testing.T) {
 
 	t.Run("empty tools", func(t *testing.T) {
 		tools := []anthropic.ToolUnionParam{}
-		result := m.applyCacheControlToTools(tools)
+		result := m.applyCacheControlToTools(tools, anthropic.NewCacheControlEphemeralParam())
 		assert.Empty(t, result)
 	})
 
@@ -3121,7 +3126,7 @@ func TestApplyCacheControlToTools(t *testing.T) {
 		tools := []anthropic.ToolUnionParam{
 			{OfTool: &anthropic.ToolParam{Name: "calc"}},
 		}
-		result := m.applyCacheControlToTools(tools)
+		result := m.applyCacheControlToTools(tools, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 1)
 		// Check that cache control is set
 		assert.NotNil(t, result[0].OfTool.CacheControl)
@@ -3133,7 +3138,7 @@ func TestApplyCacheControlToTools(t *testing.T) {
 			{OfTool: &anthropic.ToolParam{Name: "time"}},
 			{OfTool: &anthropic.ToolParam{Name: "search"}},
 		}
-		result := m.applyCacheControlToTools(tools)
+		result := m.applyCacheControlToTools(tools, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 3)
 		// Check that cache control is set on the last item only
 		assert.NotEmpty(t, result[2].OfTool.CacheControl.Type)
@@ -3145,7 +3150,7 @@ func TestApplyCacheControlToTools(t *testing.T) {
 		original := []anthropic.ToolUnionParam{
 			{OfTool: &anthropic.ToolParam{Name: "calc"}},
 		}
-		_ = m.applyCacheControlToTools(original)
+		_ = m.applyCacheControlToTools(original, anthropic.NewCacheControlEphemeralParam())
 		// Original should not be modified
 		assert.Empty(t, original[0].OfTool.CacheControl.Type)
 	})
@@ -3177,26 +3182,26 @@ func TestCacheMessages_DefaultDisabled(t *testing.T) {
 	assert.False(t, m.cacheMessages, "cache messages should be disabled by default")
 }
 
-// TestFindLastAssistantMessageIndex tests the findLastAssistantMessageIndex method.
-func TestFindLastAssistantMessageIndex(t *testing.T) {
+// TestHistoryCacheBreakpoints tests the historyCacheBreakpoints method.
+func TestHistoryCacheBreakpoints(t *testing.T) {
 	m := New("claude-3-5-sonnet")
 
 	tests := []struct {
 		name     string
 		messages []anthropic.MessageParam
-		expected int
+		expected []int
 	}{
 		{
 			name:     "empty messages",
 			messages: []anthropic.MessageParam{},
-			expected: -1,
+			expected: nil,
 		},
 		{
 			name: "only user message",
 			messages: []anthropic.MessageParam{
 				{Role: "user"},
 			},
-			expected: -1,
+			expected: nil,
 		},
 		{
 			name: "user then assistant then user",
@@ -3205,7 +3210,7 @@ func TestFindLastAssistantMessageIndex(t *testing.T) {
 				{Role: "assistant"},
 				{Role: "user"},
 			},
-			expected: 1, // Index of assistant message
+			expected: []int{1}, // Index of assistant message
 		},
 		{
 			name: "multiple assistant messages",
@@ -3216,7 +3221,7 @@ func TestFindLastAssistantMessageIndex(t *testing.T) {
 				{Role: "assistant"},
 				{Role: "user"},
 			},
-			expected: 3, // Index of last assistant before final user
+			expected: []int{3}, // Last assistant before final user
 		},
 		{
 			name: "ends with assistant - no valid index",
@@ -3224,25 +3229,45 @@ func TestFindLastAssistantMessageIndex(t *testing.T) {
 				{Role: "user"},
 				{Role: "assistant"},
 			},
-			expected: -1, // We don't cache the final assistant message
+			expected: nil, // We don't cache the final assistant message
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := m.findLastAssistantMessageIndex(tt.messages)
+			result := m.historyCacheBreakpoints(tt.messages)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+// TestWithCacheMessagesBreakpoints tests the WithCacheMessagesBreakpoints option.
+func TestWithCacheMessagesBreakpoints(t *testing.T) {
+	messages := []anthropic.MessageParam{
+		{Role: "user"},
+		{Role: "assistant"},
+		{Role: "user"},
+		{Role: "assistant"},
+		{Role: "user"},
+	}
+
+	m := New("claude-3-5-sonnet", WithCacheMessagesBreakpoints(2))
+	assert.Equal(t, []int{1, 3}, m.historyCacheBreakpoints(messages))
+
+	m = New("claude-3-5-sonnet", WithCacheMessagesBreakpoints(10))
+	assert.Equal(t, model.MaxHistoryCacheBreakpoints, m.cacheMessagesBreakpoints)
+
+	m = New("claude-3-5-sonnet", WithCacheMessagesBreakpoints(0))
+	assert.Equal(t, []int{3}, m.historyCacheBreakpoints(messages))
+}
+
 // TestApplyCacheControlToMessages tests the applyCacheControlToMessages method.
 func TestApplyCacheControlToMessages(t *testing.T) {
 	m := New("claude-3-5-sonnet")
 
 	t.Run("empty messages", func(t *testing.T) {
 		messages := []anthropic.MessageParam{}
-		result := m.applyCacheControlToMessages(messages, 0)
+		result := m.applyCacheControlToMessages(messages, 0, anthropic.NewCacheControlEphemeralParam())
 		assert.Empty(t, result)
 	})
 
@@ -3250,7 +3275,7 @@ func TestApplyCacheControlToMessages(t *testing.T) {
 		messages := []anthropic.MessageParam{
 			{Role: "user"},
 		}
-		result := m.applyCacheControlToMessages(messages, -1)
+		result := m.applyCacheControlToMessages(messages, -1, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 1)
 	})
 
@@ -3258,7 +3283,7 @@ func TestApplyCacheControlToMessages(t *testing.T) {
 		messages := []anthropic.MessageParam{
 			{Role: "user"},
 		}
-		result := m.applyCacheControlToMessages(messages, 5)
+		result := m.applyCacheControlToMessages(messages, 5, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 1)
 	})
 
@@ -3274,7 +3299,7 @@ func TestApplyCacheControlToMessages(t *testing.T) {
 				{OfText: &anthropic.TextBlockParam{Text: "How are you?"}},
 			}},
 		}
-		result := m.applyCacheControlToMessages(messages, 1)
+		result := m.applyCacheControlToMessages(messages, 1, anthropic.NewCacheControlEphemeralParam())
 		assert.Len(t, result, 3)
 		// Check that cache control is applied to the assistant message
 		assert.NotNil(t, result[1].Content[0].OfText.CacheControl)
@@ -3308,7 +3333,7 @@ func TestApplyCacheControl(t *testing.T) {
 			{OfTool: &anthropic.ToolParam{Name: "calc"}},
 		}
 
-		resultSys, resultTools, resultMsgs := m.applyCacheControl(systemPrompts, tools, messages)
+		resultSys, resultTools, resultMsgs := m.applyCacheControl(m.promptCache(nil), systemPrompts, tools, messages)
 
 		// All three breakpoints should be set independently
 		assert.NotEmpty(t, resultSys[0].CacheControl.Type, "system should have cache control")
@@ -3341,7 +3366,7 @@ func TestApplyCacheControl(t *testing.T) {
 			{OfTool: &anthropic.ToolParam{Name: "calc"}},
 		}
 
-		resultSys, resultTools, resultMsgs := m.applyCacheControl(systemPrompts, tools, messages)
+		resultSys, resultTools, resultMsgs := m.applyCacheControl(m.promptCache(nil), systemPrompts, tools, messages)
 
 		// System and tools should have cache control
 		assert.NotEmpty(t, resultSys[0].CacheControl.Type, "system should have cache control")
@@ -3367,7 +3392,7 @@ func TestApplyCacheControl(t *testing.T) {
 		}
 		tools := []anthropic.ToolUnionParam{}
 
-		resultSys, resultTools, _ := m.applyCacheControl(systemPrompts, tools, messages)
+		resultSys, resultTools, _ := m.applyCacheControl(m.promptCache(nil), systemPrompts, tools, messages)
 
 		assert.NotEmpty(t, resultSys[0].CacheControl.Type, "system should have cache control")
 		assert.Empty(t, resultTools)
@@ -3394,7 +3419,7 @@ func TestApplyCacheControl(t *testing.T) {
 			{OfTool: &anthropic.ToolParam{Name: "calc"}},
 		}
 
-		resultSys, resultTools, resultMsgs := m.applyCacheControl(systemPrompts, tools, messages)
+		resultSys, resultTools, resultMsgs := m.applyCacheControl(m.promptCache(nil), systemPrompts, tools, messages)
 
 		assert.Empty(t, resultSys[0].CacheControl.Type, "system should not have cache control")
 		assert.Empty(t, resultTools[0].OfTool.CacheControl.Type, "tools should not have cache control")
@@ -3402,6 +3427,35 @@ func TestApplyCacheControl(t *testing.T) {
 	})
 }
 
+// TestBuildChatRequest_PromptCache tests that the request cache hint is mapped to breakpoints.
+func TestBuildChatRequest_PromptCache(t *testing.T) {
+	m := New("claude-3-5-sonnet")
+	req := &model.Request{
+		Messages: []model.Message{
+			model.NewSystemMessage("You are a helpful assistant."),
+			model.NewUserMessage("Hello"),
+			model.NewAssistantMessage("Hi there"),
+			model.NewUserMessage("How are you?"),
+			model.NewAssistantMessage("Fine"),
+			model.NewUserMessage("Bye"),
+		},
+		PromptCache: &model.PromptCache{System: true, History: true, TTL: 2 * time.Hour},
+	}
+	chatReq, err := m.buildChatRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, anthropic.CacheControlEphemeralTTLTTL1h, chatReq.System[0].CacheControl.TTL)
+	assert.Empty(t, chatReq.Messages[0].Content[0].OfText.CacheControl.Type)
+	assert.Empty(t, chatReq.Messages[1].Content[0].OfText.CacheControl.Type)
+	assert.Equal(t, anthropic.CacheControlEphemeralTTLTTL1h, chatReq.Messages[3].Content[0].OfText.CacheControl.TTL)
+	assert.Empty(t, chatReq.Messages[4].Content[0].OfText.CacheControl.Type)
+
+	req.PromptCache = nil
+	chatReq, err = m.buildChatRequest(req)
+	require.NoError(t, err)
+	assert.Empty(t, chatReq.System[0].CacheControl.Type)
+	assert.Empty(t, chatReq.Messages[3].Content[0].OfText.CacheControl.Type)
+}
+
 // TestIntegration_AutoOptimalCacheStrategy tests the full integration of auto cache optimization.
 func TestIntegration_AutoOptimalCacheStrategy(t *testing.T) {
 	largeSystemContent := strings.Repeat("This is a large system prompt. ", 200)
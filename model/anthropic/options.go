@@ -24,6 +24,9 @@ const (
 	defaultCacheSystemPrompt = false // Disabled by default; opt-in for system prompt caching
 	defaultCacheTools        = false // Disabled by default; opt-in for tools caching
 	defaultCacheMessages     = false // Disabled by default; opt-in for multi-turn conversation caching
+	// defaultCacheMessagesBreakpoints keeps a single history breakpoint on the
+	// last assistant message.
+	defaultCacheMessagesBreakpoints = 1
 )
 
 // ChatRequestCallbackFunc is the function type for the chat request callback.
@@ -99,6 +102,9 @@ type options struct {
 	// When enabled, cache control will be applied to the last assistant message
 	// to maximize cache reuse in subsequent turns.
 	cacheMessages bool
+	// cacheMessagesBreakpoints is the number of assistant messages that get a
+	// history breakpoint when message caching is enabled.
+	cacheMessagesBreakpoints int
 	// showToolCallDelta controls whether to expose tool call argument deltas in
 	// streaming responses.
 	showToolCallDelta bool
@@ -119,6 +125,8 @@ var (
 		cacheSystemPrompt: defaultCacheSystemPrompt,
 		cacheTools:        defaultCacheTools,
 		cacheMessages:     defaultCacheMessages,
+
+		cacheMessagesBreakpoints: defaultCacheMessagesBreakpoints,
 	}
 )
 
@@ -354,9 +362,24 @@ func WithCacheTools(cache bool) Option {
 // on historical messages outweigh the creation cost. For short-lived conversations
 // (1-2 turns), the creation cost may not be recouped.
 //
+// The same breakpoints can be requested per request with model.Request.PromptCache.
+//
 // Default: false (disabled, opt-in for multi-turn scenarios)
 func WithCacheMessages(cache bool) Option {
 	return func(opts *options) {
 		opts.cacheMessages = cache
 	}
 }
+
+// WithCacheMessagesBreakpoints sets how many assistant messages get a history
+// breakpoint when message caching is enabled. With 2, a second breakpoint stays
+// on the assistant message where the previous turn wrote its cache entry, so
+// the cache is still read after a tool loop adds many messages in one turn.
+// Values are clamped to between 1 and model.MaxHistoryCacheBreakpoints.
+//
+// Default: 1
+func WithCacheMessagesBreakpoints(n int) Option {
+	return func(opts *options) {
+		opts.cacheMessagesBreakpoints = max(1, min(n, model.MaxHistoryCacheBreakpoints))
+	}
+}
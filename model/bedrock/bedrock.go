@@ -314,16 +314,7 @@ func (m *Model) processStreamEvents(
 			case *types.ConverseStreamOutputMemberMetadata:
 				// Metadata event, parse usage information
 				if ev.Value.Usage != nil {
-					usage = &model.Usage{
-						PromptTokens:     int(aws.ToInt32(ev.Value.Usage.InputTokens)),
-						CompletionTokens: int(aws.ToInt32(ev.Value.Usage.OutputTokens)),
-						TotalTokens:      int(aws.ToInt32(ev.Value.Usage.TotalTokens)),
-						PromptTokensDetails: model.PromptTokensDetails{
-							CachedTokens:        int(aws.ToInt32(ev.Value.Usage.CacheReadInputTokens)),
-							CacheCreationTokens: int(aws.ToInt32(ev.Value.Usage.CacheWriteInputTokens)),
-							CacheReadTokens:     int(aws.ToInt32(ev.Value.Usage.CacheReadInputTokens)),
-						},
-					}
+					usage = convertUsage(ev.Value.Usage)
 				}
 			}
 		}
@@ -373,6 +364,9 @@ func (m *Model) buildConverseInput(request *model.Request) (*bedrockruntime.Conv
 		input.ToolConfig = buildToolConfig(request.Tools)
 	}
 
+	// Mark the cached prefix
+	input.Messages, input.System = applyPromptCache(request.PromptCache, input.Messages, input.System, input.ToolConfig)
+
 	// Set additional model request fields (thinking/reasoning configuration)
 	input.AdditionalModelRequestFields = buildAdditionalModelRequestFields(request.GenerationConfig)
 
@@ -403,6 +397,9 @@ func (m *Model) buildConverseStreamInput(request *model.Request) (*bedrockruntim
 		input.ToolConfig = buildToolConfig(request.Tools)
 	}
 
+	// Mark the cached prefix
+	input.Messages, input.System = applyPromptCache(request.PromptCache, input.Messages, input.System, input.ToolConfig)
+
 	// Set additional model request fields (thinking/reasoning configuration)
 	input.AdditionalModelRequestFields = buildAdditionalModelRequestFields(request.GenerationConfig)
 
@@ -438,21 +435,30 @@ func (m *Model) buildNonStreamingResponse(output *bedrockruntime.ConverseOutput)
 
 	// Set usage
 	if output.Usage != nil {
-		response.Usage = &model.Usage{
-			PromptTokens:     int(aws.ToInt32(output.Usage.InputTokens)),
-			CompletionTokens: int(aws.ToInt32(output.Usage.OutputTokens)),
-			TotalTokens:      int(aws.ToInt32(output.Usage.TotalTokens)),
-			PromptTokensDetails: model.PromptTokensDetails{
-				CachedTokens:        int(aws.ToInt32(output.Usage.CacheReadInputTokens)),
-				CacheCreationTokens: int(aws.ToInt32(output.Usage.CacheWriteInputTokens)),
-				CacheReadTokens:     int(aws.ToInt32(output.Usage.CacheReadInputTokens)),
-			},
-		}
+		response.Usage = convertUsage(output.Usage)
 	}
 
 	return response
 }
 
+// convertUsage converts Bedrock token usage to model.Usage. Bedrock counts
+// the tokens read from and written to the cache apart from the input
+// tokens, so they are only reported in the prompt token details.
+func convertUsage(usage *types.TokenUsage) *model.Usage {
+	read := int(aws.ToInt32(usage.CacheReadInputTokens))
+	return &model.Usage{
+		PromptTokens:     int(aws.ToInt32(usage.InputTokens)),
+		CompletionTokens: int(aws.ToInt32(usage.OutputTokens)),
+		TotalTokens:      int(aws.ToInt32(usage.TotalTokens)),
+		PromptTokensDetails: model.PromptTokensDetails{
+			CachedTokens:        read,
+			CacheCreationTokens: int(aws.ToInt32(usage.CacheWriteInputTokens)),
+			CacheReadTokens:     read,
+			CacheTokensExcluded: true,
+		},
+	}
+}
+
 // convertOutputMessage converts a Bedrock message to model.Message.
 func convertOutputMessage(msg types.Message) model.Message {
 	result := model.Message{
@@ -775,6 +781,38 @@ func inferImageFormat(format string) string {
 	}
 }
 
+// applyPromptCache adds cache points after the parts of the request that
+// cache marks as a stable prefix: the system prompt, the tools and the
+// history up to the assistant messages chosen by
+// model.HistoryCacheBreakpoints. That is at most four cache points, the
+// most Bedrock allows per request.
+func applyPromptCache(
+	cache *model.PromptCache,
+	messages []types.Message,
+	system []types.SystemContentBlock,
+	toolConfig *types.ToolConfiguration,
+) ([]types.Message, []types.SystemContentBlock) {
+	if !cache.Enabled() {
+		return messages, system
+	}
+	point := types.CachePointBlock{Type: types.CachePointTypeDefault}
+	if cache.System && len(system) > 0 {
+		system = append(system, &types.SystemContentBlockMemberCachePoint{Value: point})
+	}
+	if cache.Tools && toolConfig != nil && len(toolConfig.Tools) > 0 {
+		toolConfig.Tools = append(toolConfig.Tools, &types.ToolMemberCachePoint{Value: point})
+	}
+	if cache.History {
+		isAssistant := func(i int) bool { return messages[i].Role == types.ConversationRoleAssistant }
+		for _, i := range model.HistoryCacheBreakpoints(len(messages), isAssistant) {
+			content := make([]types.ContentBlock, 0, len(messages[i].Content)+1)
+			content = append(content, messages[i].Content...)
+			messages[i].Content = append(content, &types.ContentBlockMemberCachePoint{Value: point})
+		}
+	}
+	return messages, system
+}
+
 // mergeConsecutiveMessages merges consecutive messages with the same role.
 // The Bedrock API requires messages to alternate (user/assistant).
 func mergeConsecutiveMessages(messages []types.Message) []types.Message {
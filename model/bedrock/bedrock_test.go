@@ -1858,6 +1858,40 @@ func TestBuildNonStreamingResponse_WithCacheTokens(t *testing.T) {
 	resp := m.buildNonStreamingResponse(output)
 	require.NotNil(t, resp.Usage)
 	assert.Equal(t, 80, resp.Usage.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 80, resp.Usage.PromptTokensDetails.CacheReadTokens)
+	// Prompt tokens exclude the tokens read from the cache.
+	assert.Equal(t, 100, resp.Usage.PromptTokens)
+	assert.Equal(t, 120, resp.Usage.TotalTokens)
+	assert.True(t, resp.Usage.PromptTokensDetails.CacheTokensExcluded)
+}
+
+func TestApplyPromptCache(t *testing.T) {
+	m := &Model{modelID: "test-model"}
+	req := &model.Request{
+		Messages: []model.Message{
+			model.NewSystemMessage("You are a helpful assistant."),
+			model.NewUserMessage("Hello"),
+			model.NewAssistantMessage("Hi there"),
+			model.NewUserMessage("How are you?"),
+		},
+		Tools:       map[string]tool.Tool{"calc": stubTool{decl: &tool.Declaration{Name: "calc", InputSchema: &tool.Schema{Type: "object"}}}},
+		PromptCache: &model.PromptCache{System: true, Tools: true, History: true},
+	}
+	input, err := m.buildConverseInput(req)
+	require.NoError(t, err)
+	require.Len(t, input.System, 2)
+	assert.IsType(t, &types.SystemContentBlockMemberCachePoint{}, input.System[1])
+	require.Len(t, input.ToolConfig.Tools, 2)
+	assert.IsType(t, &types.ToolMemberCachePoint{}, input.ToolConfig.Tools[1])
+	require.Len(t, input.Messages, 3)
+	assert.IsType(t, &types.ContentBlockMemberCachePoint{}, input.Messages[1].Content[len(input.Messages[1].Content)-1])
+	assert.Len(t, input.Messages[2].Content, 1)
+
+	req.PromptCache = nil
+	input, err = m.buildConverseInput(req)
+	require.NoError(t, err)
+	assert.Len(t, input.System, 1)
+	assert.Len(t, input.ToolConfig.Tools, 1)
 }
 
 // ============================================================================
@@ -52,6 +52,8 @@ func (a *Accumulator) Accumulate(resp *model.Response) {
 		a.Usage.PromptTokens += resp.Usage.PromptTokens
 		a.Usage.CompletionTokens += resp.Usage.CompletionTokens
 		a.Usage.TotalTokens += resp.Usage.TotalTokens
+		a.Usage.PromptTokensDetails.CachedTokens += resp.Usage.PromptTokensDetails.CachedTokens
+		a.Usage.PromptTokensDetails.CacheReadTokens += resp.Usage.PromptTokensDetails.CacheReadTokens
 	}
 }
 
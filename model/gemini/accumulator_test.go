@@ -26,6 +26,10 @@ func TestAccumulator_BuildResponse(t *testing.T) {
 			PromptTokens:     1,
 			CompletionTokens: 1,
 			TotalTokens:      2,
+			PromptTokensDetails: model.PromptTokensDetails{
+				CachedTokens:    1,
+				CacheReadTokens: 1,
+			},
 		},
 		Choices: []model.Choice{
 			{
@@ -0,0 +1,167 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/genai"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// defaultCachedContentTTL is how long a cached content lives when the
+// request does not set a TTL. It matches the default of the Gemini API.
+const defaultCachedContentTTL = time.Hour
+
+// cachedContent is a cached content created for a request prefix. An empty
+// name records that the creation failed, so it is not retried on every
+// request.
+type cachedContent struct {
+	name     string
+	expireAt time.Time
+}
+
+// applyPromptCache moves the prefix marked by the cache hint of request into
+// an explicit cached content and points config at it. The leading system
+// messages are cached when the hint marks the system prompt. The tools and
+// their config are always part of the cached content, since Gemini does not
+// accept them next to one. It returns the contents that are still to be
+// sent; without a usable cached content they are returned unchanged.
+//
+// Cached contents are reused while they live and are left to expire.
+func (m *Model) applyPromptCache(
+	ctx context.Context,
+	request *model.Request,
+	contents []*genai.Content,
+	config *genai.GenerateContentConfig,
+) []*genai.Content {
+	hint := request.PromptCache
+	if hint == nil || !(hint.System || hint.Tools) {
+		return contents
+	}
+	client, ok := m.client.(CacheClient)
+	if !ok {
+		return contents
+	}
+	ttl := hint.TTL
+	if ttl <= 0 {
+		ttl = defaultCachedContentTTL
+	}
+	create := &genai.CreateCachedContentConfig{
+		TTL:        ttl,
+		Tools:      config.Tools,
+		ToolConfig: config.ToolConfig,
+	}
+	rest := contents
+	if hint.System {
+		n := 0
+		for n < len(request.Messages) && request.Messages[n].Role == model.RoleSystem {
+			n++
+		}
+		create.Contents = m.convertMessages(request.Messages[:n])
+		rest = contents[len(create.Contents):]
+	}
+	if len(rest) == 0 || (len(create.Contents) == 0 && len(create.Tools) == 0) {
+		return contents
+	}
+
+	key, err := m.cachedContentKey(create)
+	if err != nil {
+		return contents
+	}
+	entry, ok := m.cachedContent(key)
+	if !ok {
+		// Concurrent requests for the same prefix share one creation.
+		v, _, _ := m.cacheGroup.Do(key, func() (any, error) {
+			if entry, ok := m.cachedContent(key); ok {
+				return entry, nil
+			}
+			return m.createCachedContent(context.WithoutCancel(ctx), client, key, create), nil
+		})
+		entry = v.(cachedContent)
+	}
+	if entry.name == "" {
+		return contents
+	}
+	config.CachedContent = entry.name
+	config.Tools = nil
+	config.ToolConfig = nil
+	return rest
+}
+
+// cachedContentKey identifies the prefix a cached content holds.
+func (m *Model) cachedContentKey(create *genai.CreateCachedContentConfig) (string, error) {
+	b, err := json.Marshal(struct {
+		Model      string            `json:"model"`
+		TTL        time.Duration     `json:"ttl"`
+		Contents   []*genai.Content  `json:"contents"`
+		Tools      []*genai.Tool     `json:"tools"`
+		ToolConfig *genai.ToolConfig `json:"tool_config"`
+	}{m.name, create.TTL, create.Contents, create.Tools, create.ToolConfig})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cachedContent returns the live cached content recorded under key.
+func (m *Model) cachedContent(key string) (cachedContent, bool) {
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+	entry, ok := m.cachedContents[key]
+	if !ok || time.Now().After(entry.expireAt) {
+		return cachedContent{}, false
+	}
+	return entry, true
+}
+
+// createCachedContent creates a cached content and records it under key.
+// A cached content stops being used when a tenth of its TTL is left, so
+// requests in flight do not refer to an expired one.
+func (m *Model) createCachedContent(
+	ctx context.Context,
+	client CacheClient,
+	key string,
+	create *genai.CreateCachedContentConfig,
+) cachedContent {
+	now := time.Now()
+	entry := cachedContent{expireAt: now.Add(create.TTL)}
+	cached, err := client.Caches().Create(ctx, m.name, create)
+	if err != nil {
+		// Prefixes below the minimum size of the model, among others,
+		// cannot be cached.
+		log.WarnfContext(ctx, "gemini: create cached content: %v", err)
+	} else {
+		entry.name = cached.Name
+		if !cached.ExpireTime.IsZero() {
+			entry.expireAt = cached.ExpireTime
+		}
+		entry.expireAt = entry.expireAt.Add(-create.TTL / 10)
+	}
+
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+	if m.cachedContents == nil {
+		m.cachedContents = make(map[string]cachedContent)
+	}
+	for k, e := range m.cachedContents {
+		if now.After(e.expireAt) {
+			delete(m.cachedContents, k)
+		}
+	}
+	m.cachedContents[key] = entry
+	return entry
+}
@@ -0,0 +1,168 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+type fakeCacheClient struct {
+	mu      sync.Mutex
+	creates []*genai.CreateCachedContentConfig
+	err     error
+	// release, when set, blocks Create until it is closed.
+	release chan struct{}
+}
+
+func (c *fakeCacheClient) Models() Models { return nil }
+
+func (c *fakeCacheClient) Caches() Caches { return c }
+
+func (c *fakeCacheClient) Create(
+	_ context.Context,
+	_ string,
+	config *genai.CreateCachedContentConfig,
+) (*genai.CachedContent, error) {
+	c.mu.Lock()
+	c.creates = append(c.creates, config)
+	c.mu.Unlock()
+	if c.release != nil {
+		<-c.release
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &genai.CachedContent{
+		Name:       "cachedContents/1",
+		ExpireTime: time.Now().Add(config.TTL),
+	}, nil
+}
+
+func promptCacheRequest() *model.Request {
+	return &model.Request{
+		Messages: []model.Message{
+			model.NewSystemMessage("You are a helpful assistant."),
+			model.NewUserMessage("Hello"),
+		},
+		PromptCache: &model.PromptCache{System: true, Tools: true},
+	}
+}
+
+func TestApplyPromptCache(t *testing.T) {
+	client := &fakeCacheClient{}
+	m := &Model{name: "gemini-2.5-flash", client: client}
+	ctx := context.Background()
+
+	req := promptCacheRequest()
+	contents := m.convertMessages(req.Messages)
+	config := &genai.GenerateContentConfig{
+		Tools:      []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "calc"}}}},
+		ToolConfig: &genai.ToolConfig{},
+	}
+	rest := m.applyPromptCache(ctx, req, contents, config)
+	require.Len(t, client.creates, 1)
+	assert.Equal(t, defaultCachedContentTTL, client.creates[0].TTL)
+	assert.Len(t, client.creates[0].Contents, 1)
+	assert.Len(t, client.creates[0].Tools, 1)
+	assert.Equal(t, contents[1:], rest)
+	assert.Equal(t, "cachedContents/1", config.CachedContent)
+	assert.Nil(t, config.Tools)
+	assert.Nil(t, config.ToolConfig)
+
+	// The same prefix reuses the cached content.
+	config = &genai.GenerateContentConfig{
+		Tools:      []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "calc"}}}},
+		ToolConfig: &genai.ToolConfig{},
+	}
+	m.applyPromptCache(ctx, promptCacheRequest(), contents, config)
+	assert.Len(t, client.creates, 1)
+	assert.Equal(t, "cachedContents/1", config.CachedContent)
+
+	// Retries keep the tool config of the cached content.
+	assert.Nil(t, retryConfigForMalformed(config).ToolConfig)
+}
+
+func TestApplyPromptCache_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creation fails", func(t *testing.T) {
+		client := &fakeCacheClient{err: errors.New("too few tokens")}
+		m := &Model{name: "gemini-2.5-flash", client: client}
+		req := promptCacheRequest()
+		contents := m.convertMessages(req.Messages)
+		for i := 0; i < 2; i++ {
+			config := &genai.GenerateContentConfig{}
+			assert.Equal(t, contents, m.applyPromptCache(ctx, req, contents, config))
+			assert.Empty(t, config.CachedContent)
+		}
+		// The failure is remembered until the TTL passes.
+		assert.Len(t, client.creates, 1)
+	})
+
+	t.Run("no hint", func(t *testing.T) {
+		client := &fakeCacheClient{}
+		m := &Model{name: "gemini-2.5-flash", client: client}
+		req := promptCacheRequest()
+		req.PromptCache = nil
+		contents := m.convertMessages(req.Messages)
+		assert.Equal(t, contents, m.applyPromptCache(ctx, req, contents, &genai.GenerateContentConfig{}))
+		assert.Empty(t, client.creates)
+	})
+
+	t.Run("nothing left to send", func(t *testing.T) {
+		client := &fakeCacheClient{}
+		m := &Model{name: "gemini-2.5-flash", client: client}
+		req := promptCacheRequest()
+		req.Messages = req.Messages[:1]
+		contents := m.convertMessages(req.Messages)
+		assert.Equal(t, contents, m.applyPromptCache(ctx, req, contents, &genai.GenerateContentConfig{}))
+		assert.Empty(t, client.creates)
+	})
+}
+
+func TestApplyPromptCache_ConcurrentCreateOnce(t *testing.T) {
+	client := &fakeCacheClient{release: make(chan struct{})}
+	m := &Model{name: "gemini-2.5-flash", client: client}
+
+	const n = 8
+	names := make([]string, n)
+	var started, done sync.WaitGroup
+	started.Add(n)
+	done.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer done.Done()
+			config := &genai.GenerateContentConfig{}
+			req := promptCacheRequest()
+			contents := m.convertMessages(req.Messages)
+			started.Done()
+			m.applyPromptCache(context.Background(), req, contents, config)
+			names[i] = config.CachedContent
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(client.release)
+	done.Wait()
+
+	assert.Len(t, client.creates, 1)
+	for _, name := range names {
+		assert.Equal(t, "cachedContents/1", name)
+	}
+}
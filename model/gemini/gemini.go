@@ -18,9 +18,11 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/genai"
 	imodelrequest "trpc.group/trpc-go/trpc-agent-go/internal/modelrequest"
 	"trpc.group/trpc-go/trpc-agent-go/internal/toolorder"
@@ -69,6 +71,10 @@ type Model struct {
 	outputTokensFloor      int
 	safetyMarginRatio      float64
 	maxInputTokensRatio    float64
+
+	cacheMu        sync.Mutex
+	cachedContents map[string]cachedContent // Keyed by cachedContentKey.
+	cacheGroup     singleflight.Group       // Dedupes cached content creation per key.
 }
 
 // New creates a new Gemini-like model.
@@ -186,6 +192,7 @@ func (m *Model) GenerateContent(
 		request,
 		imodelrequest.ToolsDisabled(ctx),
 	)
+	chatRequest = m.applyPromptCache(ctx, request, chatRequest, generateConfig)
 	// Execute callback synchronously before starting the goroutine
 	// to avoid a race where the runner and HTTP handler finish
 	// (closing the SSE writer) while the callback is still running.
@@ -214,6 +221,11 @@ const malformedFunctionCallRetries = 2
 func retryConfigForMalformed(cfg *genai.GenerateContentConfig) *genai.GenerateContentConfig {
 	retry := *cfg // shallow copy — sufficient for scalar fields
 	retry.Temperature = genai.Ptr(float32(0))
+	if cfg.CachedContent != "" {
+		// The tool config is part of the cached content and cannot be
+		// overridden per request.
+		return &retry
+	}
 
 	// Clone ToolConfig so we don't mutate the caller's config, then only
 	// override FunctionCallingConfig.Mode.  All other ToolConfig fields
@@ -597,7 +609,8 @@ func (m *Model) completionUsageToModelUsage(usage *genai.GenerateContentResponse
 		CompletionTokens: int(usage.CandidatesTokenCount),
 		TotalTokens:      int(usage.TotalTokenCount),
 		PromptTokensDetails: model.PromptTokensDetails{
-			CachedTokens: int(usage.CachedContentTokenCount),
+			CachedTokens:    int(usage.CachedContentTokenCount),
+			CacheReadTokens: int(usage.CachedContentTokenCount),
 		},
 	}
 }
@@ -860,7 +860,8 @@ func TestModel_buildFinalResponse(t *testing.T) {
 					TotalTokens:      2,
 					CompletionTokens: 1,
 					PromptTokensDetails: model.PromptTokensDetails{
-						CachedTokens: 1,
+						CachedTokens:    1,
+						CacheReadTokens: 1,
 					},
 				},
 			},
@@ -1030,7 +1031,8 @@ func TestModel_buildChunkResponse(t *testing.T) {
 					TotalTokens:      2,
 					CompletionTokens: 1,
 					PromptTokensDetails: model.PromptTokensDetails{
-						CachedTokens: 1,
+						CachedTokens:    1,
+						CacheReadTokens: 1,
 					},
 				},
 			},
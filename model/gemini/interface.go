@@ -34,6 +34,43 @@ type Models interface {
 		config *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error]
 }
 
+// CacheClient is implemented by clients that can create cached contents.
+// The client created by New implements it. Models whose client does not
+// skip the explicit caching asked for by model.Request.PromptCache.
+type CacheClient interface {
+	Caches() Caches
+}
+
+// Caches provides methods for managing cached contents.
+type Caches interface {
+	// Create creates a cached content for the model.
+	Create(ctx context.Context, model string,
+		config *genai.CreateCachedContentConfig) (*genai.CachedContent, error)
+}
+
+// LiveClient is implemented by clients that can open Live API sessions.
+// The client created by New implements it. Model.Connect fails for models
+// whose client does not.
+type LiveClient interface {
+	Live() Live
+}
+
+// Live opens Live API sessions.
+type Live interface {
+	// Connect opens a Live API session for the model.
+	Connect(ctx context.Context, model string,
+		config *genai.LiveConnectConfig) (LiveSession, error)
+}
+
+// LiveSession is an open Live API session. See genai.Session.
+type LiveSession interface {
+	SendClientContent(input genai.LiveClientContentInput) error
+	SendRealtimeInput(input genai.LiveRealtimeInput) error
+	SendToolResponse(input genai.LiveToolResponseInput) error
+	Receive() (*genai.LiveServerMessage, error)
+	Close() error
+}
+
 // clientWrapper implements Client
 type clientWrapper struct {
 	client *genai.Client
@@ -44,6 +81,31 @@ func (c *clientWrapper) Models() Models {
 	return &modelsWrapper{models: c.client.Models}
 }
 
+// Caches implements CacheClient.Caches
+func (c *clientWrapper) Caches() Caches {
+	return c.client.Caches
+}
+
+// Live implements LiveClient.Live
+func (c *clientWrapper) Live() Live {
+	return &liveWrapper{live: c.client.Live}
+}
+
+// liveWrapper implements Live
+type liveWrapper struct {
+	live *genai.Live
+}
+
+// Connect implements Live.Connect
+func (l *liveWrapper) Connect(ctx context.Context, model string,
+	config *genai.LiveConnectConfig) (LiveSession, error) {
+	session, err := l.live.Connect(ctx, model, config)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
 // modelsWrapper implements Models 结构体
 type modelsWrapper struct {
 	models *genai.Models
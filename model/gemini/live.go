@@ -0,0 +1,472 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/genai"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// liveInputAudioMIMEType is the format of the user's audio, 16-bit PCM at
+// 16kHz.
+const liveInputAudioMIMEType = "audio/pcm;rate=16000"
+
+// liveResponseSeq numbers the responses of Live sessions, which the Live
+// API does not identify.
+var liveResponseSeq uint64
+
+// Connect implements the model.RealtimeModel interface with the Live API.
+// The model must be a Live model, e.g. gemini-live-2.5-flash-preview.
+//
+// The Live API reports user speech only by interrupting the model. The
+// session then sends model.RealtimeEventSpeechStarted before the
+// interrupted response ends. Cancel drops the rest of the response in
+// progress; the Live API keeps generating it until the turn completes.
+func (m *Model) Connect(
+	ctx context.Context,
+	request *model.RealtimeRequest,
+) (model.RealtimeSession, error) {
+	if request == nil {
+		return nil, errors.New("gemini: request is nil")
+	}
+	client, ok := m.client.(LiveClient)
+	if !ok {
+		return nil, errors.New("gemini: client does not support the Live API")
+	}
+	ls, err := client.Live().Connect(ctx, m.name, m.liveConnectConfig(request))
+	if err != nil {
+		return nil, fmt.Errorf("gemini: live connect: %w", err)
+	}
+	s := &liveSession{
+		session:        ls,
+		events:         make(chan *model.RealtimeEvent, m.channelBufferSize),
+		done:           make(chan struct{}),
+		manualActivity: request.TurnDetection != nil && request.TurnDetection.Disabled,
+	}
+	if history := m.convertMessages(liveHistory(request.Messages)); len(history) > 0 {
+		if err := s.send(func() error {
+			return ls.SendClientContent(genai.LiveClientContentInput{
+				Turns:        history,
+				TurnComplete: genai.Ptr(false),
+			})
+		}); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("gemini: live send history: %w", err)
+		}
+	}
+	go s.readLoop()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// liveConnectConfig builds the session config of request.
+func (m *Model) liveConnectConfig(request *model.RealtimeRequest) *genai.LiveConnectConfig {
+	config := &genai.LiveConnectConfig{
+		ResponseModalities: []genai.Modality{genai.ModalityAudio},
+		Tools:              m.convertTools(request.Tools),
+	}
+	// The Live API produces either audio or text.
+	if len(request.Modalities) > 0 {
+		config.ResponseModalities = []genai.Modality{genai.ModalityText}
+		for _, modality := range request.Modalities {
+			if modality == model.RealtimeModalityAudio {
+				config.ResponseModalities = []genai.Modality{genai.ModalityAudio}
+			}
+		}
+	}
+	if config.ResponseModalities[0] == genai.ModalityAudio {
+		// Transcripts are what the conversation history keeps of the audio.
+		config.OutputAudioTranscription = &genai.AudioTranscriptionConfig{}
+	}
+	if !request.DisableInputTranscription {
+		config.InputAudioTranscription = &genai.AudioTranscriptionConfig{}
+	}
+	if request.Instructions != "" {
+		config.SystemInstruction = genai.NewContentFromText(request.Instructions, genai.RoleUser)
+	}
+	if request.Voice != "" {
+		config.SpeechConfig = &genai.SpeechConfig{
+			VoiceConfig: &genai.VoiceConfig{
+				PrebuiltVoiceConfig: &genai.PrebuiltVoiceConfig{VoiceName: request.Voice},
+			},
+		}
+	}
+	if request.Temperature != nil {
+		config.Temperature = genai.Ptr(float32(*request.Temperature))
+	}
+	if request.MaxTokens != nil {
+		config.MaxOutputTokens = int32(min(*request.MaxTokens, math.MaxInt32))
+	}
+	if td := request.TurnDetection; td != nil {
+		detection := &genai.AutomaticActivityDetection{Disabled: td.Disabled}
+		if td.PrefixPadding > 0 {
+			detection.PrefixPaddingMs = genai.Ptr(int32(td.PrefixPadding.Milliseconds()))
+		}
+		if td.SilenceDuration > 0 {
+			detection.SilenceDurationMs = genai.Ptr(int32(td.SilenceDuration.Milliseconds()))
+		}
+		config.RealtimeInputConfig = &genai.RealtimeInputConfig{AutomaticActivityDetection: detection}
+	}
+	return config
+}
+
+// liveHistory keeps the text of the user and assistant messages.
+func liveHistory(messages []model.Message) []model.Message {
+	var history []model.Message
+	for _, msg := range messages {
+		if msg.Content == "" || (msg.Role != model.RoleUser && msg.Role != model.RoleAssistant) {
+			continue
+		}
+		history = append(history, model.Message{Role: msg.Role, Content: msg.Content})
+	}
+	return history
+}
+
+// liveSession implements model.RealtimeSession over a Live API session.
+type liveSession struct {
+	session LiveSession
+	writeMu sync.Mutex // The Live API session does not support concurrent writes.
+	events  chan *model.RealtimeEvent
+
+	// manualActivity marks sessions without voice activity detection. The
+	// user's audio turn is then framed by activity start and end.
+	manualActivity bool
+
+	mu           sync.Mutex
+	activityOpen bool // An activity start was sent without its end.
+	cancelled    bool // Cancel was called during the response in progress.
+
+	// Fields used by the read loop only.
+	responseID string // The response in progress.
+	dropping   bool   // Output is dropped until the turn completes.
+	text       strings.Builder
+	input      strings.Builder
+	usage      *model.Usage
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (s *liveSession) send(fn func() error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	select {
+	case <-s.done:
+		return net.ErrClosed
+	default:
+	}
+	return fn()
+}
+
+// SendAudio implements the model.RealtimeSession interface.
+func (s *liveSession) SendAudio(_ context.Context, chunk []byte) error {
+	if s.manualActivity {
+		s.mu.Lock()
+		start := !s.activityOpen
+		s.activityOpen = true
+		s.mu.Unlock()
+		if start {
+			if err := s.send(func() error {
+				return s.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityStart: &genai.ActivityStart{}})
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return s.send(func() error {
+		return s.session.SendRealtimeInput(genai.LiveRealtimeInput{
+			Audio: &genai.Blob{MIMEType: liveInputAudioMIMEType, Data: chunk},
+		})
+	})
+}
+
+// CommitAudio implements the model.RealtimeSession interface.
+func (s *liveSession) CommitAudio(_ context.Context) error {
+	if !s.manualActivity {
+		return s.send(func() error {
+			return s.session.SendRealtimeInput(genai.LiveRealtimeInput{AudioStreamEnd: true})
+		})
+	}
+	s.mu.Lock()
+	end := s.activityOpen
+	s.activityOpen = false
+	s.mu.Unlock()
+	if !end {
+		return nil
+	}
+	return s.send(func() error {
+		return s.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityEnd: &genai.ActivityEnd{}})
+	})
+}
+
+// SendText implements the model.RealtimeSession interface.
+func (s *liveSession) SendText(_ context.Context, text string) error {
+	return s.send(func() error {
+		return s.session.SendClientContent(genai.LiveClientContentInput{
+			Turns:        []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)},
+			TurnComplete: genai.Ptr(true),
+		})
+	})
+}
+
+// SendToolResults implements the model.RealtimeSession interface.
+func (s *liveSession) SendToolResults(_ context.Context, results []model.Message) error {
+	responses := make([]*genai.FunctionResponse, 0, len(results))
+	for _, result := range results {
+		var output map[string]any
+		if err := json.Unmarshal([]byte(result.Content), &output); err != nil {
+			output = map[string]any{"output": result.Content}
+		}
+		responses = append(responses, &genai.FunctionResponse{
+			ID:       result.ToolID,
+			Name:     result.ToolName,
+			Response: output,
+		})
+	}
+	return s.send(func() error {
+		return s.session.SendToolResponse(genai.LiveToolResponseInput{FunctionResponses: responses})
+	})
+}
+
+// Cancel implements the model.RealtimeSession interface. The output of the
+// response in progress is dropped until its turn completes. With manual
+// activity detection, Cancel also starts a user activity, which makes the
+// server stop generating; the audio sent next belongs to that activity.
+// With automatic activity detection, the server stops generating only once
+// its voice activity detection hears the user.
+func (s *liveSession) Cancel(_ context.Context) error {
+	s.mu.Lock()
+	s.cancelled = true
+	start := s.manualActivity && !s.activityOpen
+	if start {
+		s.activityOpen = true
+	}
+	s.mu.Unlock()
+	if !start {
+		return nil
+	}
+	return s.send(func() error {
+		return s.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityStart: &genai.ActivityStart{}})
+	})
+}
+
+// Events implements the model.RealtimeSession interface.
+func (s *liveSession) Events() <-chan *model.RealtimeEvent {
+	return s.events
+}
+
+// Close implements the model.RealtimeSession interface.
+func (s *liveSession) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.session.Close()
+	})
+	return err
+}
+
+// readLoop turns server messages into session events until the session
+// ends.
+func (s *liveSession) readLoop() {
+	defer close(s.events)
+	for {
+		msg, err := s.session.Receive()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.emit(&model.RealtimeEvent{
+					Type: model.RealtimeEventError,
+					Error: &model.ResponseError{
+						Type:    model.ErrorTypeStreamError,
+						Message: fmt.Sprintf("gemini: live receive: %v", err),
+					},
+				})
+				s.Close()
+			}
+			return
+		}
+		s.handle(msg)
+	}
+}
+
+func (s *liveSession) emit(ev *model.RealtimeEvent) {
+	select {
+	case s.events <- ev:
+	case <-s.done:
+	}
+}
+
+// handle converts a server message.
+func (s *liveSession) handle(msg *genai.LiveServerMessage) {
+	s.mu.Lock()
+	cancelled := s.cancelled
+	s.cancelled = false
+	s.mu.Unlock()
+	if cancelled && s.responseID != "" {
+		s.finish(nil, true)
+		s.dropping = true
+	}
+
+	if msg.SetupComplete != nil {
+		s.emit(&model.RealtimeEvent{Type: model.RealtimeEventSessionCreated})
+	}
+	if msg.UsageMetadata != nil {
+		s.usage = liveUsage(msg.UsageMetadata)
+	}
+	if sc := msg.ServerContent; sc != nil {
+		s.handleServerContent(sc)
+	}
+	if msg.ToolCall != nil && len(msg.ToolCall.FunctionCalls) > 0 {
+		s.flushInput()
+		s.start()
+		s.finish(liveToolCalls(msg.ToolCall.FunctionCalls), false)
+	}
+}
+
+func (s *liveSession) handleServerContent(sc *genai.LiveServerContent) {
+	if sc.InputTranscription != nil {
+		s.input.WriteString(sc.InputTranscription.Text)
+		if sc.InputTranscription.Finished {
+			s.flushInput()
+		}
+	}
+	if sc.Interrupted {
+		s.flushInput()
+		s.emit(&model.RealtimeEvent{Type: model.RealtimeEventSpeechStarted})
+		if s.responseID != "" {
+			s.finish(nil, true)
+		}
+		s.dropping = false
+		return
+	}
+	if sc.ModelTurn != nil && !s.dropping {
+		for _, part := range sc.ModelTurn.Parts {
+			switch {
+			case part.InlineData != nil && len(part.InlineData.Data) > 0:
+				s.flushInput()
+				s.start()
+				s.emit(&model.RealtimeEvent{
+					Type:       model.RealtimeEventAudioDelta,
+					ResponseID: s.responseID,
+					Audio:      part.InlineData.Data,
+				})
+			case part.Text != "" && !part.Thought:
+				s.flushInput()
+				s.start()
+				s.text.WriteString(part.Text)
+				s.emit(&model.RealtimeEvent{
+					Type:       model.RealtimeEventTextDelta,
+					ResponseID: s.responseID,
+					Text:       part.Text,
+				})
+			}
+		}
+	}
+	if sc.OutputTranscription != nil && sc.OutputTranscription.Text != "" && !s.dropping {
+		s.flushInput()
+		s.start()
+		s.text.WriteString(sc.OutputTranscription.Text)
+		s.emit(&model.RealtimeEvent{
+			Type:       model.RealtimeEventTranscriptDelta,
+			ResponseID: s.responseID,
+			Text:       sc.OutputTranscription.Text,
+		})
+	}
+	if sc.TurnComplete {
+		s.flushInput()
+		if s.responseID != "" {
+			s.finish(nil, false)
+		}
+		s.dropping = false
+	}
+}
+
+// start starts a response unless one is in progress.
+func (s *liveSession) start() {
+	if s.responseID == "" {
+		s.responseID = fmt.Sprintf("gemini_live_%d", atomic.AddUint64(&liveResponseSeq, 1))
+	}
+}
+
+// finish ends the response in progress.
+func (s *liveSession) finish(toolCalls []model.ToolCall, interrupted bool) {
+	s.emit(&model.RealtimeEvent{
+		Type:        model.RealtimeEventResponseDone,
+		ResponseID:  s.responseID,
+		Text:        s.text.String(),
+		ToolCalls:   toolCalls,
+		Usage:       s.usage,
+		Interrupted: interrupted,
+	})
+	s.responseID = ""
+	s.text.Reset()
+	s.usage = nil
+}
+
+// flushInput sends the transcript of the user's turn once the turn ends.
+func (s *liveSession) flushInput() {
+	if s.input.Len() == 0 {
+		return
+	}
+	s.emit(&model.RealtimeEvent{
+		Type: model.RealtimeEventInputTranscript,
+		Text: strings.TrimSpace(s.input.String()),
+	})
+	s.input.Reset()
+}
+
+func liveToolCalls(calls []*genai.FunctionCall) []model.ToolCall {
+	toolCalls := make([]model.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		args, _ := json.Marshal(call.Args)
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("gemini_call_%d", atomic.AddUint64(&geminiCallSeq, 1))
+		}
+		toolCalls = append(toolCalls, model.ToolCall{
+			Type: "function",
+			ID:   id,
+			Function: model.FunctionDefinitionParam{
+				Name:      call.Name,
+				Arguments: args,
+			},
+		})
+	}
+	return toolCalls
+}
+
+func liveUsage(usage *genai.UsageMetadata) *model.Usage {
+	return &model.Usage{
+		PromptTokens:     int(usage.PromptTokenCount),
+		CompletionTokens: int(usage.ResponseTokenCount),
+		TotalTokens:      int(usage.TotalTokenCount),
+		PromptTokensDetails: model.PromptTokensDetails{
+			CachedTokens:    int(usage.CachedContentTokenCount),
+			CacheReadTokens: int(usage.CachedContentTokenCount),
+		},
+	}
+}
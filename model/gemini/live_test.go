@@ -0,0 +1,235 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const livePath = "/ws/google.ai.generativelanguage.v1beta.GenerativeService.BidiGenerateContent"
+
+// newLiveModel creates a model whose Live API is a fake server that hands
+// the connection to script.
+func newLiveModel(t *testing.T, script func(conn *websocket.Conn)) *Model {
+	t.Helper()
+	var upgrader websocket.Upgrader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, livePath, r.URL.Path)
+		assert.Equal(t, "key", r.Header.Get("x-goog-api-key"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		script(conn)
+	}))
+	t.Cleanup(srv.Close)
+	m, err := New(context.Background(), "gemini-live-2.5-flash-preview", WithGeminiClientConfig(&genai.ClientConfig{
+		APIKey:  "key",
+		Backend: genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{
+			BaseURL:    "ws" + strings.TrimPrefix(srv.URL, "http"),
+			APIVersion: "v1beta",
+		},
+	}))
+	require.NoError(t, err)
+	return m
+}
+
+func readLiveMessage(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	var msg map[string]any
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func writeLiveMessage(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func nextLiveEvent(t *testing.T, s model.RealtimeSession) *model.RealtimeEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-s.Events():
+		require.True(t, ok, "events closed")
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func TestConnect_Live(t *testing.T) {
+	m := newLiveModel(t, func(conn *websocket.Conn) {
+		setup := readLiveMessage(t, conn)["setup"].(map[string]any)
+		assert.Equal(t, "models/gemini-live-2.5-flash-preview", setup["model"])
+		assert.Equal(t, "Be brief.",
+			setup["systemInstruction"].(map[string]any)["parts"].([]any)[0].(map[string]any)["text"])
+		assert.Equal(t, []any{"AUDIO"}, setup["generationConfig"].(map[string]any)["responseModalities"])
+		assert.Contains(t, setup, "inputAudioTranscription")
+		assert.Contains(t, setup, "outputAudioTranscription")
+		assert.Len(t, setup["tools"], 1)
+
+		history := readLiveMessage(t, conn)["clientContent"].(map[string]any)
+		assert.Len(t, history["turns"], 2)
+		assert.NotEqual(t, true, history["turnComplete"])
+		writeLiveMessage(t, conn, `{"setupComplete":{}}`)
+
+		audio := readLiveMessage(t, conn)["realtimeInput"].(map[string]any)["audio"].(map[string]any)
+		assert.Equal(t, liveInputAudioMIMEType, audio["mimeType"])
+		assert.Equal(t, "AQIDBA==", audio["data"])
+		writeLiveMessage(t, conn, `{"serverContent":{"inputTranscription":{"text":"Weather in"}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"inputTranscription":{"text":" Paris?"}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"modelTurn":{"parts":[{"inlineData":{"mimeType":"audio/pcm;rate=24000","data":"AQIDBA=="}}]}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"outputTranscription":{"text":"Let me check."}}}`)
+		writeLiveMessage(t, conn, `{"toolCall":{"functionCalls":[{"id":"c1","name":"weather","args":{"city":"Paris"}}]}}`)
+
+		responses := readLiveMessage(t, conn)["toolResponse"].(map[string]any)["functionResponses"].([]any)
+		assert.Equal(t, map[string]any{"id": "c1", "name": "weather", "response": map[string]any{"output": "sunny"}},
+			responses[0])
+		writeLiveMessage(t, conn, `{"serverContent":{"outputTranscription":{"text":"It is sunny."}}}`)
+		writeLiveMessage(t, conn, `{"usageMetadata":{"promptTokenCount":20,"responseTokenCount":10,"totalTokenCount":30}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"turnComplete":true}}`)
+
+		// The response is cancelled and its rest is dropped.
+		writeLiveMessage(t, conn, `{"serverContent":{"outputTranscription":{"text":"Anything"}}}`)
+		text := readLiveMessage(t, conn)["clientContent"].(map[string]any)
+		assert.Equal(t, true, text["turnComplete"])
+		writeLiveMessage(t, conn, `{"serverContent":{"outputTranscription":{"text":" else?"}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"turnComplete":true}}`)
+
+		// The user barges in.
+		writeLiveMessage(t, conn, `{"serverContent":{"outputTranscription":{"text":"Bye"}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"interrupted":true}}`)
+		conn.ReadMessage()
+	})
+
+	ctx := context.Background()
+	s, err := m.Connect(ctx, &model.RealtimeRequest{
+		Instructions: "Be brief.",
+		Messages: []model.Message{
+			model.NewSystemMessage("ignored"),
+			model.NewUserMessage("Hi"),
+			model.NewAssistantMessage("Hello!"),
+		},
+		Tools: map[string]tool.Tool{"weather": &namedTool{name: "weather"}},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, model.RealtimeEventSessionCreated, nextLiveEvent(t, s).Type)
+	require.NoError(t, s.SendAudio(ctx, []byte{1, 2, 3, 4}))
+	assert.Equal(t, &model.RealtimeEvent{Type: model.RealtimeEventInputTranscript, Text: "Weather in Paris?"},
+		nextLiveEvent(t, s))
+	audio := nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventAudioDelta, audio.Type)
+	assert.Equal(t, []byte{1, 2, 3, 4}, audio.Audio)
+	delta := nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventTranscriptDelta, delta.Type)
+	assert.Equal(t, audio.ResponseID, delta.ResponseID)
+
+	done := nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.Equal(t, audio.ResponseID, done.ResponseID)
+	assert.Equal(t, "Let me check.", done.Text)
+	require.Len(t, done.ToolCalls, 1)
+	assert.Equal(t, "c1", done.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(done.ToolCalls[0].Function.Arguments))
+
+	require.NoError(t, s.SendToolResults(ctx, []model.Message{model.NewToolMessage("c1", "weather", "sunny")}))
+	assert.Equal(t, "It is sunny.", nextLiveEvent(t, s).Text)
+	done = nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.NotEqual(t, audio.ResponseID, done.ResponseID)
+	assert.Equal(t, "It is sunny.", done.Text)
+	assert.Equal(t, 20, done.Usage.PromptTokens)
+
+	assert.Equal(t, "Anything", nextLiveEvent(t, s).Text)
+	require.NoError(t, s.Cancel(ctx))
+	require.NoError(t, s.SendText(ctx, "Stop"))
+	done = nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.True(t, done.Interrupted)
+	assert.Equal(t, "Anything", done.Text)
+
+	assert.Equal(t, "Bye", nextLiveEvent(t, s).Text)
+	assert.Equal(t, model.RealtimeEventSpeechStarted, nextLiveEvent(t, s).Type)
+	done = nextLiveEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.True(t, done.Interrupted)
+	assert.Equal(t, "Bye", done.Text)
+}
+
+func TestConnect_LiveManualActivity(t *testing.T) {
+	m := newLiveModel(t, func(conn *websocket.Conn) {
+		setup := readLiveMessage(t, conn)["setup"].(map[string]any)
+		assert.Equal(t, []any{"TEXT"}, setup["generationConfig"].(map[string]any)["responseModalities"])
+		assert.NotContains(t, setup, "outputAudioTranscription")
+		assert.NotContains(t, setup, "inputAudioTranscription")
+		assert.Equal(t, map[string]any{"automaticActivityDetection": map[string]any{"disabled": true}},
+			setup["realtimeInputConfig"])
+
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "activityStart")
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "audio")
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "audio")
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "activityEnd")
+		writeLiveMessage(t, conn, `{"serverContent":{"modelTurn":{"parts":[{"text":"Hi","thought":true},{"text":"Hello"}]}}}`)
+		writeLiveMessage(t, conn, `{"serverContent":{"turnComplete":true}}`)
+
+		// Cancel starts the activity that interrupts the server.
+		writeLiveMessage(t, conn, `{"serverContent":{"modelTurn":{"parts":[{"text":"Long"}]}}}`)
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "activityStart")
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "audio")
+		assert.Contains(t, readLiveMessage(t, conn)["realtimeInput"], "activityEnd")
+		conn.ReadMessage()
+	})
+
+	ctx := context.Background()
+	s, err := m.Connect(ctx, &model.RealtimeRequest{
+		Modalities:                []string{model.RealtimeModalityText},
+		TurnDetection:             &model.TurnDetection{Disabled: true},
+		DisableInputTranscription: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.SendAudio(ctx, []byte{1}))
+	require.NoError(t, s.SendAudio(ctx, []byte{2}))
+	require.NoError(t, s.CommitAudio(ctx))
+	assert.Equal(t, model.RealtimeEventTextDelta, nextLiveEvent(t, s).Type)
+	assert.Equal(t, "Hello", nextLiveEvent(t, s).Text)
+
+	assert.Equal(t, "Long", nextLiveEvent(t, s).Text)
+	require.NoError(t, s.Cancel(ctx))
+	require.NoError(t, s.SendAudio(ctx, []byte{3}))
+	require.NoError(t, s.CommitAudio(ctx))
+
+	require.NoError(t, s.Close())
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+	assert.Error(t, s.SendText(ctx, "Again"))
+}
+
+func TestConnect_LiveUnsupportedClient(t *testing.T) {
+	m := &Model{name: "gemini-live", client: &fakeCacheClient{}}
+	_, err := m.Connect(context.Background(), &model.RealtimeRequest{})
+	assert.Error(t, err)
+}
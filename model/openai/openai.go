@@ -1005,6 +1005,11 @@ func (m *Model) buildChatRequestWithToolControl(
 	if request.ReasoningEffort != nil {
 		chatRequest.ReasoningEffort = shared.ReasoningEffort(*request.ReasoningEffort)
 	}
+	// OpenAI caches prompt prefixes automatically; the key only routes
+	// requests that share a prefix to the same cache.
+	if request.PromptCache.Enabled() && request.PromptCache.Key != "" && m.variant == VariantOpenAI {
+		chatRequest.PromptCacheKey = openai.String(request.PromptCache.Key)
+	}
 	opts := m.buildThinkingOption(request)
 	// Add model-level extra fields to the request.
 	for key, value := range imodelrequest.FilterToolControlFields(
@@ -2133,7 +2138,7 @@ func applyOpenAISDKTokenDetailsAccumulationFix(
 	acc.Usage.CompletionTokensDetails.ReasoningTokens += chunk.Usage.CompletionTokensDetails.ReasoningTokens
 	acc.Usage.CompletionTokensDetails.RejectedPredictionTokens += chunk.Usage.CompletionTokensDetails.RejectedPredictionTokens
 	acc.Usage.PromptTokensDetails.AudioTokens += chunk.Usage.PromptTokensDetails.AudioTokens
-	acc.Usage.PromptTokensDetails.CachedTokens += chunk.Usage.PromptTokensDetails.CachedTokens
+	acc.Usage.PromptTokensDetails.CachedTokens += cachedPromptTokens(chunk.Usage)
 }
 
 // accumulateChunk accumulates non-reasoning deltas into the SDK accumulator and
@@ -6143,6 +6143,45 @@ func TestCompletionUsageToModelUsage(t *testing.T) {
 	})
 }
 
+func TestCompletionUsageToModelUsage_CacheHits(t *testing.T) {
+	var usage openai.CompletionUsage
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"prompt_tokens": 100,
+		"completion_tokens": 10,
+		"total_tokens": 110,
+		"prompt_cache_hit_tokens": 64,
+		"prompt_cache_miss_tokens": 36
+	}`), &usage))
+	result := completionUsageToModelUsage(usage)
+	assert.Equal(t, 64, result.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 64, result.PromptTokensDetails.CacheReadTokens)
+	assert.InDelta(t, 0.64, result.CacheHitRate(), 1e-9)
+
+	usage = openai.CompletionUsage{
+		PromptTokens:        100,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{CachedTokens: 80},
+	}
+	result = completionUsageToModelUsage(usage)
+	assert.Equal(t, 80, result.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 80, result.PromptTokensDetails.CacheReadTokens)
+}
+
+func TestBuildChatRequest_PromptCacheKey(t *testing.T) {
+	req := &model.Request{
+		Messages:    []model.Message{model.NewUserMessage("hi")},
+		PromptCache: &model.PromptCache{System: true, Key: "assistant"},
+	}
+	chatReq, _ := New("gpt-4o", WithAPIKey("test-key")).buildChatRequest(req)
+	assert.Equal(t, "assistant", chatReq.PromptCacheKey.Value)
+
+	chatReq, _ = New("deepseek-chat", WithAPIKey("test-key"), WithVariant(VariantDeepSeek)).buildChatRequest(req)
+	assert.False(t, chatReq.PromptCacheKey.Valid())
+
+	req.PromptCache = &model.PromptCache{Key: "assistant"}
+	chatReq, _ = New("gpt-4o", WithAPIKey("test-key")).buildChatRequest(req)
+	assert.False(t, chatReq.PromptCacheKey.Valid())
+}
+
 // TestWithChannelBufferSize_EdgeCases tests WithChannelBufferSize with edge cases.
 func TestWithChannelBufferSize_EdgeCases(t *testing.T) {
 	t.Run("zero size should use default", func(t *testing.T) {
@@ -12,6 +12,7 @@ package openai
 
 import (
 	"context"
+	"strconv"
 
 	openai "github.com/openai/openai-go"
 	openaiopt "github.com/openai/openai-go/option"
@@ -414,7 +415,8 @@ func inverseOpenAISDKAddChunkUsage(u model.Usage, delta model.Usage) model.Usage
 		CompletionTokens: u.CompletionTokens - delta.CompletionTokens,
 		TotalTokens:      u.TotalTokens - delta.TotalTokens,
 		PromptTokensDetails: model.PromptTokensDetails{
-			CachedTokens: int(u.PromptTokensDetails.CachedTokens - delta.PromptTokensDetails.CachedTokens),
+			CachedTokens:    int(u.PromptTokensDetails.CachedTokens - delta.PromptTokensDetails.CachedTokens),
+			CacheReadTokens: int(u.PromptTokensDetails.CacheReadTokens - delta.PromptTokensDetails.CacheReadTokens),
 		},
 		CompletionTokensDetails: model.CompletionTokensDetails{
 			ReasoningTokens: int(u.CompletionTokensDetails.ReasoningTokens - delta.CompletionTokensDetails.ReasoningTokens),
@@ -424,12 +426,14 @@ func inverseOpenAISDKAddChunkUsage(u model.Usage, delta model.Usage) model.Usage
 
 // completionUsageToModelUsage converts openai.CompletionUsage to model.Usage.
 func completionUsageToModelUsage(usage openai.CompletionUsage) model.Usage {
+	cached := int(cachedPromptTokens(usage))
 	return model.Usage{
 		PromptTokens:     int(usage.PromptTokens),
 		CompletionTokens: int(usage.CompletionTokens),
 		TotalTokens:      int(usage.TotalTokens),
 		PromptTokensDetails: model.PromptTokensDetails{
-			CachedTokens: int(usage.PromptTokensDetails.CachedTokens),
+			CachedTokens:    cached,
+			CacheReadTokens: cached,
 		},
 		CompletionTokensDetails: model.CompletionTokensDetails{
 			ReasoningTokens: int(usage.CompletionTokensDetails.ReasoningTokens),
@@ -437,6 +441,24 @@ func completionUsageToModelUsage(usage openai.CompletionUsage) model.Usage {
 	}
 }
 
+// promptCacheHitTokensKey is the usage field DeepSeek reports cache hits in.
+const promptCacheHitTokensKey = "prompt_cache_hit_tokens"
+
+// cachedPromptTokens returns the number of prompt tokens read from the cache.
+// Besides the standard prompt token details it understands the
+// prompt_cache_hit_tokens field of DeepSeek.
+func cachedPromptTokens(usage openai.CompletionUsage) int64 {
+	if usage.PromptTokensDetails.CachedTokens > 0 {
+		return usage.PromptTokensDetails.CachedTokens
+	}
+	if field, ok := usage.JSON.ExtraFields[promptCacheHitTokensKey]; ok {
+		if n, err := strconv.ParseInt(field.Raw(), 10, 64); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
 // modelUsageToCompletionUsage converts model.Usage to openai.CompletionUsage.
 func modelUsageToCompletionUsage(usage model.Usage) openai.CompletionUsage {
 	return openai.CompletionUsage{
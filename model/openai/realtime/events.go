@@ -0,0 +1,125 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package realtime
+
+import "trpc.group/trpc-go/trpc-agent-go/tool"
+
+// clientEvent is an event sent to the Realtime API. Only the fields of its
+// type are set.
+type clientEvent struct {
+	Type    string            `json:"type"`
+	Session *sessionConfig    `json:"session,omitempty"`
+	Item    *conversationItem `json:"item,omitempty"`
+	Audio   string            `json:"audio,omitempty"`
+}
+
+type sessionConfig struct {
+	Type             string      `json:"type"`
+	Model            string      `json:"model,omitempty"`
+	Instructions     string      `json:"instructions,omitempty"`
+	OutputModalities []string    `json:"output_modalities,omitempty"`
+	Audio            audioConfig `json:"audio"`
+	Tools            []toolParam `json:"tools,omitempty"`
+	MaxOutputTokens  *int        `json:"max_output_tokens,omitempty"`
+}
+
+type audioConfig struct {
+	Input  audioInput  `json:"input"`
+	Output audioOutput `json:"output"`
+}
+
+type audioInput struct {
+	Format        audioFormat    `json:"format"`
+	Transcription *transcription `json:"transcription,omitempty"`
+	// TurnDetection is sent as null to disable voice activity detection.
+	TurnDetection *turnDetection `json:"turn_detection"`
+}
+
+type audioOutput struct {
+	Format audioFormat `json:"format"`
+	Voice  string      `json:"voice,omitempty"`
+}
+
+type audioFormat struct {
+	Type string `json:"type"`
+	Rate int    `json:"rate"`
+}
+
+type transcription struct {
+	Model string `json:"model"`
+}
+
+type turnDetection struct {
+	Type              string `json:"type"`
+	PrefixPaddingMs   int    `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int    `json:"silence_duration_ms,omitempty"`
+}
+
+type toolParam struct {
+	Type        string       `json:"type"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Parameters  *tool.Schema `json:"parameters"`
+}
+
+type conversationItem struct {
+	Type    string        `json:"type"`
+	Role    string        `json:"role,omitempty"`
+	Content []contentPart `json:"content,omitempty"`
+	CallID  string        `json:"call_id,omitempty"`
+	Output  string        `json:"output,omitempty"`
+}
+
+type contentPart struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// serverEvent is an event received from the Realtime API. Only the fields
+// of its type are set.
+type serverEvent struct {
+	Type       string          `json:"type"`
+	ResponseID string          `json:"response_id,omitempty"`
+	Delta      string          `json:"delta,omitempty"`
+	Transcript string          `json:"transcript,omitempty"`
+	Response   *responseObject `json:"response,omitempty"`
+	Error      *errorObject    `json:"error,omitempty"`
+}
+
+type responseObject struct {
+	ID     string         `json:"id"`
+	Status string         `json:"status"`
+	Output []outputItem   `json:"output,omitempty"`
+	Usage  *responseUsage `json:"usage,omitempty"`
+}
+
+type outputItem struct {
+	Type      string        `json:"type"`
+	Content   []contentPart `json:"content,omitempty"`
+	CallID    string        `json:"call_id,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	Arguments string        `json:"arguments,omitempty"`
+}
+
+type responseUsage struct {
+	TotalTokens       int `json:"total_tokens"`
+	InputTokens       int `json:"input_tokens"`
+	OutputTokens      int `json:"output_tokens"`
+	InputTokenDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_token_details"`
+}
+
+type errorObject struct {
+	Type    string  `json:"type"`
+	Code    *string `json:"code,omitempty"`
+	Message string  `json:"message"`
+}
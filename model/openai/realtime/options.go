@@ -0,0 +1,110 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package realtime
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultBaseURL            = "wss://api.openai.com/v1/realtime"
+	defaultChannelBufferSize  = 256
+	defaultTranscriptionModel = "gpt-4o-mini-transcribe"
+	// apiKeyEnv is the environment variable the API key is read from when
+	// WithAPIKey is not used.
+	apiKeyEnv = "OPENAI_API_KEY"
+)
+
+// options contains configuration options for creating a realtime model.
+type options struct {
+	// apiKey is sent as a bearer token.
+	apiKey string
+	// baseURL is the websocket endpoint of the Realtime API.
+	baseURL string
+	// headers are added to the websocket handshake.
+	headers http.Header
+	// dialer dials the websocket connection.
+	dialer *websocket.Dialer
+	// channelBufferSize is the buffer size of the event channel.
+	channelBufferSize int
+	// transcriptionModel transcribes the user's audio.
+	transcriptionModel string
+}
+
+// Option configures a realtime model.
+type Option func(*options)
+
+func newOptions(opts ...Option) options {
+	o := options{
+		apiKey:             os.Getenv(apiKeyEnv),
+		baseURL:            defaultBaseURL,
+		headers:            http.Header{},
+		dialer:             websocket.DefaultDialer,
+		channelBufferSize:  defaultChannelBufferSize,
+		transcriptionModel: defaultTranscriptionModel,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithAPIKey sets the API key. It defaults to the OPENAI_API_KEY environment
+// variable.
+func WithAPIKey(key string) Option {
+	return func(o *options) {
+		o.apiKey = key
+	}
+}
+
+// WithBaseURL sets the websocket endpoint of the Realtime API, e.g. for
+// Azure OpenAI or a proxy. The model name is added as the model query
+// parameter.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithHeader adds a header to the websocket handshake.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.headers.Add(key, value)
+	}
+}
+
+// WithDialer sets the websocket dialer, e.g. to use a proxy.
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(o *options) {
+		if dialer != nil {
+			o.dialer = dialer
+		}
+	}
+}
+
+// WithChannelBufferSize sets the buffer size of the event channel.
+func WithChannelBufferSize(size int) Option {
+	return func(o *options) {
+		if size <= 0 {
+			size = defaultChannelBufferSize
+		}
+		o.channelBufferSize = size
+	}
+}
+
+// WithTranscriptionModel sets the model that transcribes the user's audio.
+func WithTranscriptionModel(name string) Option {
+	return func(o *options) {
+		o.transcriptionModel = name
+	}
+}
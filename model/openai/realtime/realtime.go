@@ -0,0 +1,473 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package realtime provides a model.RealtimeModel for the OpenAI Realtime
+// API.
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"trpc.group/trpc-go/trpc-agent-go/internal/toolorder"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	functionToolType = "function"
+	// audioSampleRate is the only PCM sample rate the API accepts.
+	audioSampleRate = 24000
+	// responseStatusCancelled is the status of a response cut short by the
+	// user speaking or by response.cancel.
+	responseStatusCancelled = "cancelled"
+	// closeTimeout bounds sending the close frame on Close.
+	closeTimeout = time.Second
+)
+
+// Model is an OpenAI Realtime model, e.g. gpt-realtime.
+//
+// Temperature of model.RealtimeRequest is not supported by the Realtime API
+// and is ignored.
+type Model struct {
+	name string
+	options
+}
+
+// New creates a realtime model.
+func New(name string, opts ...Option) *Model {
+	return &Model{name: name, options: newOptions(opts...)}
+}
+
+// Info implements the model.RealtimeModel interface.
+func (m *Model) Info() model.Info {
+	return model.Info{Name: m.name}
+}
+
+// Connect implements the model.RealtimeModel interface.
+func (m *Model) Connect(
+	ctx context.Context,
+	request *model.RealtimeRequest,
+) (model.RealtimeSession, error) {
+	if request == nil {
+		return nil, errors.New("realtime: request is nil")
+	}
+	u, err := url.Parse(m.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("realtime: parse base URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("model", m.name)
+	u.RawQuery = q.Encode()
+	header := m.headers.Clone()
+	if m.apiKey != "" {
+		header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+	conn, _, err := m.dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		return nil, fmt.Errorf("realtime: connect: %w", err)
+	}
+	s := &session{
+		conn:   conn,
+		events: make(chan *model.RealtimeEvent, m.channelBufferSize),
+		done:   make(chan struct{}),
+	}
+	if err := s.setup(ctx, m.sessionConfig(request), request.Messages); err != nil {
+		s.Close()
+		return nil, err
+	}
+	go s.readLoop()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// sessionConfig builds the session.update payload of request.
+func (m *Model) sessionConfig(request *model.RealtimeRequest) sessionConfig {
+	cfg := sessionConfig{
+		Type:             "realtime",
+		Model:            m.name,
+		Instructions:     request.Instructions,
+		OutputModalities: []string{model.RealtimeModalityAudio},
+		Audio: audioConfig{
+			Input: audioInput{
+				Format:        audioFormat{Type: "audio/pcm", Rate: audioSampleRate},
+				TurnDetection: &turnDetection{Type: "server_vad"},
+			},
+			Output: audioOutput{
+				Format: audioFormat{Type: "audio/pcm", Rate: audioSampleRate},
+				Voice:  request.Voice,
+			},
+		},
+		MaxOutputTokens: request.MaxTokens,
+	}
+	// The API produces either audio, with its transcript, or text.
+	if len(request.Modalities) > 0 {
+		cfg.OutputModalities = []string{model.RealtimeModalityText}
+		for _, modality := range request.Modalities {
+			if modality == model.RealtimeModalityAudio {
+				cfg.OutputModalities = []string{model.RealtimeModalityAudio}
+			}
+		}
+	}
+	if !request.DisableInputTranscription {
+		cfg.Audio.Input.Transcription = &transcription{Model: m.transcriptionModel}
+	}
+	if td := request.TurnDetection; td != nil {
+		if td.Disabled {
+			cfg.Audio.Input.TurnDetection = nil
+		} else {
+			cfg.Audio.Input.TurnDetection.PrefixPaddingMs = int(td.PrefixPadding.Milliseconds())
+			cfg.Audio.Input.TurnDetection.SilenceDurationMs = int(td.SilenceDuration.Milliseconds())
+		}
+	}
+	for _, t := range toolorder.SortedTools(request.Tools) {
+		cfg.Tools = append(cfg.Tools, convertTool(t))
+	}
+	return cfg
+}
+
+func convertTool(t tool.Tool) toolParam {
+	decl := t.Declaration()
+	p := toolParam{
+		Type:        functionToolType,
+		Name:        decl.Name,
+		Description: decl.Description,
+		Parameters:  decl.InputSchema,
+	}
+	if p.Parameters == nil {
+		p.Parameters = &tool.Schema{Type: "object"}
+	}
+	return p
+}
+
+// session implements model.RealtimeSession over a Realtime API websocket.
+type session struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	events  chan *model.RealtimeEvent
+
+	mu         sync.Mutex
+	responseID string // The response in progress.
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// setup configures the session and replays the conversation so far.
+func (s *session) setup(
+	ctx context.Context,
+	cfg sessionConfig,
+	messages []model.Message,
+) error {
+	if err := s.send(ctx, clientEvent{Type: "session.update", Session: &cfg}); err != nil {
+		return err
+	}
+	for _, msg := range messages {
+		item := messageItem(msg)
+		if item == nil {
+			continue
+		}
+		if err := s.send(ctx, clientEvent{Type: "conversation.item.create", Item: item}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageItem converts a history message to a conversation item. Only the
+// text of user and assistant messages is replayed.
+func messageItem(msg model.Message) *conversationItem {
+	if msg.Content == "" {
+		return nil
+	}
+	switch msg.Role {
+	case model.RoleUser:
+		return &conversationItem{
+			Type:    "message",
+			Role:    string(model.RoleUser),
+			Content: []contentPart{{Type: "input_text", Text: msg.Content}},
+		}
+	case model.RoleAssistant:
+		return &conversationItem{
+			Type:    "message",
+			Role:    string(model.RoleAssistant),
+			Content: []contentPart{{Type: "output_text", Text: msg.Content}},
+		}
+	default:
+		return nil
+	}
+}
+
+func (s *session) send(ctx context.Context, ev clientEvent) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	select {
+	case <-s.done:
+		return net.ErrClosed
+	default:
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		s.conn.SetWriteDeadline(deadline)
+		defer s.conn.SetWriteDeadline(time.Time{})
+	}
+	if err := s.conn.WriteJSON(ev); err != nil {
+		return fmt.Errorf("realtime: send %s: %w", ev.Type, err)
+	}
+	return nil
+}
+
+// SendAudio implements the model.RealtimeSession interface.
+func (s *session) SendAudio(ctx context.Context, chunk []byte) error {
+	return s.send(ctx, clientEvent{
+		Type:  "input_audio_buffer.append",
+		Audio: base64.StdEncoding.EncodeToString(chunk),
+	})
+}
+
+// CommitAudio implements the model.RealtimeSession interface.
+func (s *session) CommitAudio(ctx context.Context) error {
+	if err := s.send(ctx, clientEvent{Type: "input_audio_buffer.commit"}); err != nil {
+		return err
+	}
+	return s.send(ctx, clientEvent{Type: "response.create"})
+}
+
+// SendText implements the model.RealtimeSession interface.
+func (s *session) SendText(ctx context.Context, text string) error {
+	item := messageItem(model.NewUserMessage(text))
+	if item == nil {
+		return nil
+	}
+	if err := s.send(ctx, clientEvent{Type: "conversation.item.create", Item: item}); err != nil {
+		return err
+	}
+	return s.send(ctx, clientEvent{Type: "response.create"})
+}
+
+// SendToolResults implements the model.RealtimeSession interface.
+func (s *session) SendToolResults(ctx context.Context, results []model.Message) error {
+	for _, result := range results {
+		if err := s.send(ctx, clientEvent{
+			Type: "conversation.item.create",
+			Item: &conversationItem{
+				Type:   "function_call_output",
+				CallID: result.ToolID,
+				Output: result.Content,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return s.send(ctx, clientEvent{Type: "response.create"})
+}
+
+// Cancel implements the model.RealtimeSession interface.
+func (s *session) Cancel(ctx context.Context) error {
+	s.mu.Lock()
+	active := s.responseID != ""
+	s.mu.Unlock()
+	if !active {
+		return nil
+	}
+	return s.send(ctx, clientEvent{Type: "response.cancel"})
+}
+
+// Events implements the model.RealtimeSession interface.
+func (s *session) Events() <-chan *model.RealtimeEvent {
+	return s.events
+}
+
+// Close implements the model.RealtimeSession interface.
+func (s *session) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		// WriteControl may run alongside a send, so a send stalled on the
+		// connection does not hold up Close; closing the connection then
+		// unblocks it.
+		s.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(closeTimeout),
+		)
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// readLoop turns server events into session events until the connection
+// ends.
+func (s *session) readLoop() {
+	defer close(s.events)
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					s.emit(errorEvent("", fmt.Sprintf("realtime: read: %v", err)))
+				}
+				s.Close()
+			}
+			return
+		}
+		var ev serverEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			s.emit(errorEvent("", fmt.Sprintf("realtime: decode event: %v", err)))
+			continue
+		}
+		if out := s.convertEvent(&ev); out != nil {
+			s.emit(out)
+		}
+	}
+}
+
+func (s *session) emit(ev *model.RealtimeEvent) {
+	select {
+	case s.events <- ev:
+	case <-s.done:
+	}
+}
+
+// convertEvent converts a server event. Events without a counterpart
+// return nil.
+func (s *session) convertEvent(ev *serverEvent) *model.RealtimeEvent {
+	switch ev.Type {
+	case "session.created":
+		return &model.RealtimeEvent{Type: model.RealtimeEventSessionCreated}
+	case "input_audio_buffer.speech_started":
+		return &model.RealtimeEvent{Type: model.RealtimeEventSpeechStarted}
+	case "input_audio_buffer.speech_stopped":
+		return &model.RealtimeEvent{Type: model.RealtimeEventSpeechStopped}
+	case "conversation.item.input_audio_transcription.completed":
+		return &model.RealtimeEvent{
+			Type: model.RealtimeEventInputTranscript,
+			Text: ev.Transcript,
+		}
+	case "response.created":
+		if ev.Response != nil {
+			s.mu.Lock()
+			s.responseID = ev.Response.ID
+			s.mu.Unlock()
+		}
+		return nil
+	case "response.output_audio.delta":
+		audio, err := base64.StdEncoding.DecodeString(ev.Delta)
+		if err != nil {
+			return errorEvent(ev.ResponseID, fmt.Sprintf("realtime: decode audio: %v", err))
+		}
+		return &model.RealtimeEvent{
+			Type:       model.RealtimeEventAudioDelta,
+			ResponseID: ev.ResponseID,
+			Audio:      audio,
+		}
+	case "response.output_audio_transcript.delta":
+		return &model.RealtimeEvent{
+			Type:       model.RealtimeEventTranscriptDelta,
+			ResponseID: ev.ResponseID,
+			Text:       ev.Delta,
+		}
+	case "response.output_text.delta":
+		return &model.RealtimeEvent{
+			Type:       model.RealtimeEventTextDelta,
+			ResponseID: ev.ResponseID,
+			Text:       ev.Delta,
+		}
+	case "response.done":
+		s.mu.Lock()
+		s.responseID = ""
+		s.mu.Unlock()
+		if ev.Response == nil {
+			return nil
+		}
+		return responseDone(ev.Response)
+	case "error":
+		if ev.Error == nil {
+			return errorEvent("", "realtime: unknown error")
+		}
+		return &model.RealtimeEvent{
+			Type: model.RealtimeEventError,
+			Error: &model.ResponseError{
+				Type:    ev.Error.Type,
+				Message: ev.Error.Message,
+				Code:    ev.Error.Code,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// responseDone converts a finished response.
+func responseDone(rsp *responseObject) *model.RealtimeEvent {
+	out := &model.RealtimeEvent{
+		Type:        model.RealtimeEventResponseDone,
+		ResponseID:  rsp.ID,
+		Interrupted: rsp.Status == responseStatusCancelled,
+	}
+	var text strings.Builder
+	for _, item := range rsp.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				text.WriteString(part.Text)
+				text.WriteString(part.Transcript)
+			}
+		case "function_call":
+			out.ToolCalls = append(out.ToolCalls, model.ToolCall{
+				Type: functionToolType,
+				ID:   item.CallID,
+				Function: model.FunctionDefinitionParam{
+					Name:      item.Name,
+					Arguments: []byte(item.Arguments),
+				},
+			})
+		}
+	}
+	out.Text = text.String()
+	if u := rsp.Usage; u != nil {
+		out.Usage = &model.Usage{
+			PromptTokens:     u.InputTokens,
+			CompletionTokens: u.OutputTokens,
+			TotalTokens:      u.TotalTokens,
+			PromptTokensDetails: model.PromptTokensDetails{
+				CachedTokens:    u.InputTokenDetails.CachedTokens,
+				CacheReadTokens: u.InputTokenDetails.CachedTokens,
+			},
+		}
+	}
+	return out
+}
+
+func errorEvent(responseID, msg string) *model.RealtimeEvent {
+	return &model.RealtimeEvent{
+		Type:       model.RealtimeEventError,
+		ResponseID: responseID,
+		Error: &model.ResponseError{
+			Type:    model.ErrorTypeStreamError,
+			Message: msg,
+		},
+	}
+}
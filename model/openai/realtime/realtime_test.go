@@ -0,0 +1,291 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+// fakeServer is a Realtime API server that hands each connection to a
+// script.
+func fakeServer(t *testing.T, script func(conn *websocket.Conn, r *http.Request)) string {
+	t.Helper()
+	var upgrader websocket.Upgrader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		script(conn, r)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	var ev map[string]any
+	require.NoError(t, conn.ReadJSON(&ev))
+	return ev
+}
+
+func writeEvent(t *testing.T, conn *websocket.Conn, ev string) {
+	t.Helper()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(ev)))
+}
+
+func nextEvent(t *testing.T, s model.RealtimeSession) *model.RealtimeEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-s.Events():
+		require.True(t, ok, "events closed")
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+func weatherTool() tool.Tool {
+	return function.NewFunctionTool(
+		func(_ context.Context, args weatherArgs) (string, error) { return "sunny", nil },
+		function.WithName("weather"),
+		function.WithDescription("Get the weather"),
+	)
+}
+
+func TestConnect_Conversation(t *testing.T) {
+	audio := []byte{1, 2, 3, 4}
+	url := fakeServer(t, func(conn *websocket.Conn, r *http.Request) {
+		assert.Equal(t, "gpt-realtime", r.URL.Query().Get("model"))
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+
+		update := readEvent(t, conn)
+		assert.Equal(t, "session.update", update["type"])
+		cfg := update["session"].(map[string]any)
+		assert.Equal(t, "Be brief.", cfg["instructions"])
+		assert.Equal(t, []any{"audio"}, cfg["output_modalities"])
+		input := cfg["audio"].(map[string]any)["input"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "server_vad", "silence_duration_ms": float64(400)},
+			input["turn_detection"])
+		assert.Equal(t, defaultTranscriptionModel, input["transcription"].(map[string]any)["model"])
+		tools := cfg["tools"].([]any)
+		require.Len(t, tools, 1)
+		assert.Equal(t, "weather", tools[0].(map[string]any)["name"])
+
+		history := readEvent(t, conn)
+		assert.Equal(t, "conversation.item.create", history["type"])
+		assert.Equal(t, "assistant", history["item"].(map[string]any)["role"])
+		writeEvent(t, conn, `{"type":"session.created"}`)
+
+		appended := readEvent(t, conn)
+		assert.Equal(t, "input_audio_buffer.append", appended["type"])
+		assert.Equal(t, base64.StdEncoding.EncodeToString(audio), appended["audio"])
+		writeEvent(t, conn, `{"type":"input_audio_buffer.speech_started"}`)
+		writeEvent(t, conn, `{"type":"input_audio_buffer.speech_stopped"}`)
+		writeEvent(t, conn, `{"type":"conversation.item.input_audio_transcription.completed","transcript":"Weather in Paris?"}`)
+		writeEvent(t, conn, `{"type":"response.created","response":{"id":"r1"}}`)
+		writeEvent(t, conn, `{"type":"response.output_audio.delta","response_id":"r1","delta":"`+
+			base64.StdEncoding.EncodeToString(audio)+`"}`)
+		writeEvent(t, conn, `{"type":"response.output_audio_transcript.delta","response_id":"r1","delta":"Let me check."}`)
+		writeEvent(t, conn, `{"type":"response.done","response":{"id":"r1","status":"completed","output":[`+
+			`{"type":"message","content":[{"type":"output_audio","transcript":"Let me check."}]},`+
+			`{"type":"function_call","call_id":"c1","name":"weather","arguments":"{\"city\":\"Paris\"}"}],`+
+			`"usage":{"total_tokens":30,"input_tokens":20,"output_tokens":10,"input_token_details":{"cached_tokens":5}}}}`)
+
+		output := readEvent(t, conn)
+		assert.Equal(t, "conversation.item.create", output["type"])
+		assert.Equal(t, map[string]any{"type": "function_call_output", "call_id": "c1", "output": "sunny"},
+			output["item"])
+		assert.Equal(t, "response.create", readEvent(t, conn)["type"])
+		writeEvent(t, conn, `{"type":"response.created","response":{"id":"r2"}}`)
+
+		// The user barges in.
+		assert.Equal(t, "response.cancel", readEvent(t, conn)["type"])
+		writeEvent(t, conn, `{"type":"response.done","response":{"id":"r2","status":"cancelled","output":[]}}`)
+		writeEvent(t, conn, `{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`)
+		conn.ReadMessage()
+	})
+
+	m := New("gpt-realtime", WithBaseURL(url), WithAPIKey("key"))
+	ctx := context.Background()
+	s, err := m.Connect(ctx, &model.RealtimeRequest{
+		Instructions:  "Be brief.",
+		Messages:      []model.Message{model.NewSystemMessage("ignored"), model.NewAssistantMessage("Hi!")},
+		Tools:         map[string]tool.Tool{"weather": weatherTool()},
+		TurnDetection: &model.TurnDetection{SilenceDuration: 400 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, model.RealtimeEventSessionCreated, nextEvent(t, s).Type)
+	require.NoError(t, s.SendAudio(ctx, audio))
+	assert.Equal(t, model.RealtimeEventSpeechStarted, nextEvent(t, s).Type)
+	assert.Equal(t, model.RealtimeEventSpeechStopped, nextEvent(t, s).Type)
+	assert.Equal(t, &model.RealtimeEvent{Type: model.RealtimeEventInputTranscript, Text: "Weather in Paris?"},
+		nextEvent(t, s))
+	assert.Equal(t, &model.RealtimeEvent{Type: model.RealtimeEventAudioDelta, ResponseID: "r1", Audio: audio},
+		nextEvent(t, s))
+	assert.Equal(t, &model.RealtimeEvent{
+		Type: model.RealtimeEventTranscriptDelta, ResponseID: "r1", Text: "Let me check.",
+	}, nextEvent(t, s))
+
+	done := nextEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.Equal(t, "Let me check.", done.Text)
+	assert.False(t, done.Interrupted)
+	require.Len(t, done.ToolCalls, 1)
+	assert.Equal(t, "c1", done.ToolCalls[0].ID)
+	assert.Equal(t, "weather", done.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(done.ToolCalls[0].Function.Arguments))
+	assert.Equal(t, 20, done.Usage.PromptTokens)
+	assert.Equal(t, 5, done.Usage.PromptTokensDetails.CacheReadTokens)
+
+	// Cancel is a no-op without a response in progress.
+	require.NoError(t, s.Cancel(ctx))
+	require.NoError(t, s.SendToolResults(ctx, []model.Message{model.NewToolMessage("c1", "weather", "sunny")}))
+	require.Eventually(t, func() bool {
+		s.(*session).mu.Lock()
+		defer s.(*session).mu.Unlock()
+		return s.(*session).responseID == "r2"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Cancel(ctx))
+	done = nextEvent(t, s)
+	assert.Equal(t, model.RealtimeEventResponseDone, done.Type)
+	assert.True(t, done.Interrupted)
+
+	errEvent := nextEvent(t, s)
+	assert.Equal(t, model.RealtimeEventError, errEvent.Type)
+	assert.Equal(t, "bad", errEvent.Error.Message)
+}
+
+func TestConnect_TextAndManualTurns(t *testing.T) {
+	url := fakeServer(t, func(conn *websocket.Conn, _ *http.Request) {
+		update := readEvent(t, conn)
+		cfg := update["session"].(map[string]any)
+		assert.Equal(t, []any{"text"}, cfg["output_modalities"])
+		input := cfg["audio"].(map[string]any)["input"].(map[string]any)
+		v, ok := input["turn_detection"]
+		assert.True(t, ok)
+		assert.Nil(t, v)
+		assert.NotContains(t, input, "transcription")
+
+		item := readEvent(t, conn)
+		assert.Equal(t, "input_text",
+			item["item"].(map[string]any)["content"].([]any)[0].(map[string]any)["type"])
+		assert.Equal(t, "response.create", readEvent(t, conn)["type"])
+		assert.Equal(t, "input_audio_buffer.commit", readEvent(t, conn)["type"])
+		assert.Equal(t, "response.create", readEvent(t, conn)["type"])
+		writeEvent(t, conn, `{"type":"response.output_text.delta","response_id":"r1","delta":"Hi"}`)
+		writeEvent(t, conn, `{"type":"response.done","response":{"id":"r1","status":"completed",`+
+			`"output":[{"type":"message","content":[{"type":"output_text","text":"Hi"}]}]}}`)
+		conn.ReadMessage()
+	})
+
+	m := New("gpt-realtime", WithBaseURL(url))
+	ctx := context.Background()
+	s, err := m.Connect(ctx, &model.RealtimeRequest{
+		Modalities:                []string{model.RealtimeModalityText},
+		TurnDetection:             &model.TurnDetection{Disabled: true},
+		DisableInputTranscription: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.SendText(ctx, "Hello"))
+	require.NoError(t, s.CommitAudio(ctx))
+	assert.Equal(t, &model.RealtimeEvent{Type: model.RealtimeEventTextDelta, ResponseID: "r1", Text: "Hi"},
+		nextEvent(t, s))
+	assert.Equal(t, "Hi", nextEvent(t, s).Text)
+
+	require.NoError(t, s.Close())
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+	assert.Error(t, s.SendText(ctx, "Again"))
+}
+
+func TestConnect_ServerClosed(t *testing.T) {
+	url := fakeServer(t, func(conn *websocket.Conn, _ *http.Request) {
+		readEvent(t, conn)
+	})
+	s, err := New("gpt-realtime", WithBaseURL(url)).Connect(context.Background(), &model.RealtimeRequest{})
+	require.NoError(t, err)
+	ev := nextEvent(t, s)
+	assert.Equal(t, model.RealtimeEventError, ev.Type)
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+}
+
+func TestSession_CloseDuringBlockedSend(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	url := fakeServer(t, func(conn *websocket.Conn, _ *http.Request) {
+		readEvent(t, conn)
+		// Stop reading so that the client's writes fill the connection.
+		<-release
+	})
+	s, err := New("gpt-realtime", WithBaseURL(url)).Connect(context.Background(), &model.RealtimeRequest{})
+	require.NoError(t, err)
+
+	sendErr := make(chan error, 1)
+	go func() {
+		chunk := make([]byte, 1<<20)
+		for {
+			if err := s.SendAudio(context.Background(), chunk); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close is blocked by the send")
+	}
+	select {
+	case err := <-sendErr:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("send is still blocked after Close")
+	}
+}
+
+func TestSessionConfigJSON(t *testing.T) {
+	cfg := New("gpt-realtime").sessionConfig(&model.RealtimeRequest{Voice: "marin"})
+	b, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"voice":"marin"`)
+	assert.Contains(t, string(b), `"turn_detection":{"type":"server_vad"}`)
+	assert.Contains(t, string(b), `"format":{"type":"audio/pcm","rate":24000}`)
+}
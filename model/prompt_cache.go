@@ -0,0 +1,90 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package model
+
+import "time"
+
+// MaxHistoryCacheBreakpoints is the number of history breakpoints returned
+// by HistoryCacheBreakpoints. Together with one breakpoint for the system
+// prompt and one for the tools it stays within the four breakpoints that
+// Anthropic and Bedrock allow per request.
+const MaxHistoryCacheBreakpoints = 2
+
+// PromptCache asks the model to cache the stable prefix of a request so
+// later requests that share it are cheaper and faster. Each provider maps
+// the hint to its native mechanism:
+//   - Anthropic: cache_control breakpoints on the marked parts.
+//   - Bedrock: cache point blocks after the marked parts.
+//   - OpenAI: prompt_cache_key set to Key. Prefix caching itself is automatic.
+//   - Gemini: an explicit cached content holding the system prompt and tools.
+//
+// Providers ignore the parts they cannot cache. Cache hits are reported in
+// Usage.PromptTokensDetails.
+type PromptCache struct {
+	// System marks the system prompt as a stable prefix.
+	System bool `json:"system,omitempty"`
+	// Tools marks the tool definitions as a stable prefix.
+	Tools bool `json:"tools,omitempty"`
+	// History marks the conversation history up to the last assistant
+	// message as a stable prefix. See HistoryCacheBreakpoints.
+	History bool `json:"history,omitempty"`
+	// Key groups requests that share a prefix, e.g. the agent name. It is
+	// used by providers that route requests to caches by key.
+	Key string `json:"key,omitempty"`
+	// TTL is how long cached content should be kept. Zero uses the
+	// provider default. Providers round it to the nearest supported value.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// Enabled reports whether any part of the request is marked for caching.
+func (c *PromptCache) Enabled() bool {
+	return c != nil && (c.System || c.Tools || c.History)
+}
+
+// HistoryCacheBreakpoints returns the indexes of the messages after which
+// the conversation history should be cached, in ascending order. n is the
+// number of messages sent to the provider and isAssistant reports whether
+// the message at an index was written by the model.
+//
+// The last breakpoint is on the last assistant message before the final
+// message, so everything but the newest input is cached. The one before it
+// is on the previous assistant message, which is where the last breakpoint
+// of the previous request was. Each request thus reads the prefix the
+// previous one wrote, however many messages a tool loop adds in between.
+func HistoryCacheBreakpoints(n int, isAssistant func(i int) bool) []int {
+	var idx []int
+	for i := n - 2; i >= 0 && len(idx) < MaxHistoryCacheBreakpoints; i-- {
+		if isAssistant(i) {
+			idx = append(idx, i)
+		}
+	}
+	for i, j := 0, len(idx)-1; i < j; i, j = i+1, j-1 {
+		idx[i], idx[j] = idx[j], idx[i]
+	}
+	return idx
+}
+
+// CacheHitRate returns the share of prompt tokens that were read from the
+// prompt cache, between 0 and 1. When the provider reports the cache tokens
+// apart from PromptTokens they are added to the total.
+func (u *Usage) CacheHitRate() float64 {
+	if u == nil {
+		return 0
+	}
+	d := u.PromptTokensDetails
+	total := u.PromptTokens
+	if d.CacheTokensExcluded {
+		total += d.CacheReadTokens + d.CacheCreationTokens
+	}
+	if total <= 0 {
+		return 0
+	}
+	return float64(d.CacheReadTokens) / float64(total)
+}
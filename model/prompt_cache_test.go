@@ -0,0 +1,80 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryCacheBreakpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		roles    []Role
+		expected []int
+	}{
+		{name: "empty", roles: nil, expected: nil},
+		{name: "first turn", roles: []Role{RoleUser}, expected: nil},
+		{name: "final assistant is not cached", roles: []Role{RoleUser, RoleAssistant}, expected: nil},
+		{name: "second turn", roles: []Role{RoleUser, RoleAssistant, RoleUser}, expected: []int{1}},
+		{
+			name:     "tool loop",
+			roles:    []Role{RoleUser, RoleAssistant, RoleTool, RoleAssistant, RoleTool, RoleAssistant, RoleUser},
+			expected: []int{3, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HistoryCacheBreakpoints(len(tt.roles), func(i int) bool {
+				return tt.roles[i] == RoleAssistant
+			})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	// The newest breakpoint of a request is the older one of the next.
+	roles := []Role{RoleUser, RoleAssistant, RoleUser, RoleAssistant, RoleUser}
+	isAssistant := func(i int) bool { return roles[i] == RoleAssistant }
+	first := HistoryCacheBreakpoints(len(roles), isAssistant)
+	roles = append(roles, RoleAssistant, RoleUser)
+	second := HistoryCacheBreakpoints(len(roles), isAssistant)
+	assert.Equal(t, first[len(first)-1], second[0])
+}
+
+func TestPromptCacheEnabled(t *testing.T) {
+	var cache *PromptCache
+	assert.False(t, cache.Enabled())
+	assert.False(t, (&PromptCache{Key: "k"}).Enabled())
+	assert.True(t, (&PromptCache{History: true}).Enabled())
+}
+
+func TestUsageCacheHitRate(t *testing.T) {
+	var usage *Usage
+	assert.Zero(t, usage.CacheHitRate())
+	assert.Zero(t, (&Usage{}).CacheHitRate())
+	usage = &Usage{
+		PromptTokens:        200,
+		PromptTokensDetails: PromptTokensDetails{CachedTokens: 150, CacheReadTokens: 150},
+	}
+	assert.InDelta(t, 0.75, usage.CacheHitRate(), 1e-9)
+
+	// Cache tokens reported apart from the prompt tokens count toward the total.
+	usage = &Usage{
+		PromptTokens: 20,
+		PromptTokensDetails: PromptTokensDetails{
+			CachedTokens:        150,
+			CacheReadTokens:     150,
+			CacheCreationTokens: 30,
+			CacheTokensExcluded: true,
+		},
+	}
+	assert.InDelta(t, 0.75, usage.CacheHitRate(), 1e-9)
+}
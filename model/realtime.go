@@ -0,0 +1,151 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// RealtimeModel is a model that holds full-duplex sessions, in which audio
+// and text stream in both directions while the model is speaking.
+type RealtimeModel interface {
+	// Connect opens a realtime session configured by request. The session
+	// lives until it is closed, ctx is done or the connection drops.
+	Connect(ctx context.Context, request *RealtimeRequest) (RealtimeSession, error)
+
+	// Info returns basic information about the model.
+	Info() Info
+}
+
+// Realtime output modalities.
+const (
+	RealtimeModalityText  = "text"
+	RealtimeModalityAudio = "audio"
+)
+
+// RealtimeRequest configures a realtime session.
+type RealtimeRequest struct {
+	// Instructions is the system prompt of the session.
+	Instructions string `json:"instructions,omitempty"`
+	// Messages is the conversation so far. It is replayed into the session
+	// before any input is sent. Only the text of the messages is replayed.
+	Messages []Message `json:"messages,omitempty"`
+	// Tools are the tools the model can call during the session.
+	Tools map[string]tool.Tool `json:"-"`
+	// Modalities are the output modalities, RealtimeModalityAudio when empty.
+	Modalities []string `json:"modalities,omitempty"`
+	// Voice is the provider specific name of the output voice.
+	Voice string `json:"voice,omitempty"`
+	// Temperature is the sampling temperature.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// MaxTokens limits the output tokens of each response.
+	MaxTokens *int `json:"max_tokens,omitempty"`
+	// TurnDetection configures server side voice activity detection.
+	// Nil uses the provider defaults.
+	TurnDetection *TurnDetection `json:"turn_detection,omitempty"`
+	// DisableInputTranscription turns off the transcription of the user's
+	// audio. Transcripts are what the conversation history keeps of it.
+	DisableInputTranscription bool `json:"disable_input_transcription,omitempty"`
+}
+
+// TurnDetection configures server side voice activity detection (VAD).
+// With VAD the server ends the user's turn when they stop speaking and
+// interrupts the model when they start speaking over it.
+type TurnDetection struct {
+	// Disabled turns VAD off. The user's turn then ends with
+	// RealtimeSession.CommitAudio.
+	Disabled bool `json:"disabled,omitempty"`
+	// PrefixPadding is the audio kept before detected speech.
+	PrefixPadding time.Duration `json:"prefix_padding,omitempty"`
+	// SilenceDuration is the silence that ends the user's turn.
+	SilenceDuration time.Duration `json:"silence_duration,omitempty"`
+}
+
+// RealtimeSession is an open realtime session.
+//
+// Audio is raw 16-bit little-endian mono PCM. The sample rate is the one of
+// the provider: OpenAI Realtime takes and produces 24kHz, Gemini Live takes
+// 16kHz and produces 24kHz.
+//
+// The send methods are safe for concurrent use.
+type RealtimeSession interface {
+	// SendAudio appends a chunk of the user's audio.
+	SendAudio(ctx context.Context, chunk []byte) error
+	// CommitAudio ends the user's audio turn and asks for a response. It is
+	// only needed when turn detection is disabled.
+	CommitAudio(ctx context.Context) error
+	// SendText sends a user text message and asks for a response.
+	SendText(ctx context.Context, text string) error
+	// SendToolResults returns the results of the tool calls that ended a
+	// response, as tool messages, and lets the model continue.
+	SendToolResults(ctx context.Context, results []Message) error
+	// Cancel interrupts the response in progress, e.g. when the user barges
+	// in. The response ends with an interrupted RealtimeEventResponseDone.
+	Cancel(ctx context.Context) error
+	// Events returns the events of the session. The channel is closed when
+	// the session ends.
+	Events() <-chan *RealtimeEvent
+	// Close ends the session.
+	Close() error
+}
+
+// RealtimeEventType is the type of a RealtimeEvent.
+type RealtimeEventType string
+
+// Realtime event types.
+const (
+	// RealtimeEventSessionCreated is sent once the session is ready.
+	RealtimeEventSessionCreated RealtimeEventType = "session.created"
+	// RealtimeEventSpeechStarted is sent when VAD detects the user speaking.
+	// Clients should stop playing the model's audio.
+	RealtimeEventSpeechStarted RealtimeEventType = "input.speech_started"
+	// RealtimeEventSpeechStopped is sent when VAD detects the user stopped
+	// speaking.
+	RealtimeEventSpeechStopped RealtimeEventType = "input.speech_stopped"
+	// RealtimeEventInputTranscript carries the transcript of a user turn.
+	RealtimeEventInputTranscript RealtimeEventType = "input.transcript"
+	// RealtimeEventAudioDelta carries a chunk of the model's audio.
+	RealtimeEventAudioDelta RealtimeEventType = "response.audio.delta"
+	// RealtimeEventTranscriptDelta carries a chunk of the transcript of the
+	// model's audio.
+	RealtimeEventTranscriptDelta RealtimeEventType = "response.transcript.delta"
+	// RealtimeEventTextDelta carries a chunk of the model's text.
+	RealtimeEventTextDelta RealtimeEventType = "response.text.delta"
+	// RealtimeEventResponseDone ends a response. Text holds its full text
+	// or transcript. A response that calls tools carries the calls, and the
+	// model waits for RealtimeSession.SendToolResults.
+	RealtimeEventResponseDone RealtimeEventType = "response.done"
+	// RealtimeEventError reports an error. The session may still be usable.
+	RealtimeEventError RealtimeEventType = "error"
+)
+
+// RealtimeEvent is an event of a realtime session.
+type RealtimeEvent struct {
+	// Type is the type of the event.
+	Type RealtimeEventType `json:"type"`
+	// ResponseID identifies the response the event belongs to.
+	ResponseID string `json:"response_id,omitempty"`
+	// Audio is the audio of RealtimeEventAudioDelta.
+	Audio []byte `json:"audio,omitempty"`
+	// Text is the text of delta, transcript and done events.
+	Text string `json:"text,omitempty"`
+	// ToolCalls are the tool calls of RealtimeEventResponseDone.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Usage is the usage of RealtimeEventResponseDone, when reported.
+	Usage *Usage `json:"usage,omitempty"`
+	// Interrupted reports a response that was cut short by the user or by
+	// RealtimeSession.Cancel.
+	Interrupted bool `json:"interrupted,omitempty"`
+	// Error is the error of RealtimeEventError.
+	Error *ResponseError `json:"error,omitempty"`
+}
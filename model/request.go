@@ -608,6 +608,10 @@ type Request struct {
 	// JSON formatting. This field is optional and provider-agnostic.
 	StructuredOutput *StructuredOutput `json:"structured_output,omitempty"`
 
+	// PromptCache asks the model to cache the stable prefix of the request.
+	// This field is optional and provider-agnostic.
+	PromptCache *PromptCache `json:"prompt_cache,omitempty"`
+
 	// ExtraFields stores provider-specific top-level request body fields.
 	// Model adapters merge these with model-level extra fields when supported;
 	// request-level values take precedence.
@@ -48,6 +48,16 @@ const (
 	ObjectTypeRunnerCompletion = "runner.completion"
 	// ObjectTypeStateUpdate is the object type for state update events.
 	ObjectTypeStateUpdate = "state.update"
+	// ObjectTypePlanProgress is the object type for plan progress events.
+	ObjectTypePlanProgress = "plan.progress"
+	// ObjectTypeTaskBoard is the object type for team task board events.
+	ObjectTypeTaskBoard = "team.task_board"
+	// ObjectTypeRealtimeSpeechStarted is the object type for events of a
+	// realtime session that report the user started speaking.
+	ObjectTypeRealtimeSpeechStarted = "realtime.speech_started"
+	// ObjectTypeRealtimeSpeechStopped is the object type for events of a
+	// realtime session that report the user stopped speaking.
+	ObjectTypeRealtimeSpeechStopped = "realtime.speech_stopped"
 
 	// ObjectTypeChatCompletionChunk is the object type for chat completion chunk events.
 	ObjectTypeChatCompletionChunk = "chat.completion.chunk"
@@ -115,7 +125,9 @@ type TimingInfo struct {
 
 // Usage represents token usage information.
 type Usage struct {
-	// PromptTokens is the number of tokens in the prompt.
+	// PromptTokens is the number of tokens in the prompt. Providers that set
+	// PromptTokensDetails.CacheTokensExcluded do not count the tokens read
+	// from and written to the prompt cache here.
 	PromptTokens int `json:"prompt_tokens"`
 
 	// CompletionTokens is the number of tokens in the completion.
@@ -136,12 +148,17 @@ type Usage struct {
 
 // PromptTokensDetails is the details of the prompt tokens.
 type PromptTokensDetails struct {
-	// CachedTokens is the number of cached tokens in the prompt.
+	// CachedTokens is the number of cached tokens in the prompt. It equals
+	// CacheReadTokens.
 	CachedTokens int `json:"cached_tokens"`
-	// CacheCreationTokens is the number of tokens used to create the cache (Anthropic).
+	// CacheCreationTokens is the number of prompt tokens written to the
+	// cache. Only providers that bill cache writes report it.
 	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
-	// CacheReadTokens is the number of tokens read from cache (Anthropic).
+	// CacheReadTokens is the number of prompt tokens read from the cache.
 	CacheReadTokens int `json:"cache_read_tokens,omitempty"`
+	// CacheTokensExcluded reports that PromptTokens does not include
+	// CacheReadTokens and CacheCreationTokens, as with Anthropic and Bedrock.
+	CacheTokensExcluded bool `json:"cache_tokens_excluded,omitempty"`
 }
 
 // CompletionTokensDetails is the details of the completion tokens.
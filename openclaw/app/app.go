@@ -569,6 +569,8 @@ func MainWithOptions(args []string, options ...RuntimeOption) int {
 			return runBootstrap(args[1:])
 		case subcmdEvolution:
 			return runEvolution(args[1:])
+		case subcmdSkills:
+			return runSkills(args[1:])
 		}
 	}
 
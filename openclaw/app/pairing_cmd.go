@@ -21,6 +21,8 @@ import (
 	"text/tabwriter"
 	"time"
 
+	discordch "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/discord"
+	slackch "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/slack"
 	tgch "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/telegram"
 	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
 	tgapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/telegram"
@@ -36,6 +38,9 @@ const (
 
 	pairingCmdList    = "list"
 	pairingCmdApprove = "approve"
+
+	slackChannelType   = "slack"
+	discordChannelType = "discord"
 )
 
 var probeBotInfo = func(
@@ -58,7 +63,7 @@ func runPairing(args []string) int {
 	channelName := fs.String(
 		flagChannel,
 		"",
-		"Telegram channel name (optional)",
+		"Channel name or type (optional)",
 	)
 	stateDir := fs.String(
 		flagStateDir,
@@ -212,11 +217,24 @@ func openPairingStore(
 	opts runOptions,
 	wantChannel string,
 ) (*pairing.FileStore, error) {
-	spec, err := resolveTelegramPairingChannel(opts, wantChannel)
+	spec, err := resolvePairingChannel(opts, wantChannel)
 	if err != nil {
 		return nil, err
 	}
 
+	switch strings.ToLower(strings.TrimSpace(spec.Type)) {
+	case slackChannelType, discordChannelType:
+		return openChatPairingStore(opts, spec)
+	default:
+		return openTelegramPairingStore(ctx, opts, spec)
+	}
+}
+
+func openTelegramPairingStore(
+	ctx context.Context,
+	opts runOptions,
+	spec pluginSpec,
+) (*pairing.FileStore, error) {
 	var cfg telegramChannelConfig
 	if err := registry.DecodeStrict(spec.Config, &cfg); err != nil {
 		return nil, err
@@ -258,14 +276,57 @@ func openPairingStore(
 	return pairing.NewFileStore(path, storeOpts...)
 }
 
-func resolveTelegramPairingChannel(
+// openChatPairingStore opens the pairing store of a Slack or Discord
+// channel. Their stores are keyed by the configured channel name, so no
+// API call is needed.
+func openChatPairingStore(
+	opts runOptions,
+	spec pluginSpec,
+) (*pairing.FileStore, error) {
+	var cfg struct {
+		PairingTTL string `yaml:"pairing_ttl"`
+	}
+	if spec.Config != nil {
+		if err := spec.Config.Decode(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	resolvedStateDir, err := resolveStateDir(opts.StateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var path string
+	if strings.EqualFold(strings.TrimSpace(spec.Type), slackChannelType) {
+		path, err = slackch.PairingStorePath(resolvedStateDir, spec.Name)
+	} else {
+		path, err = discordch.PairingStorePath(resolvedStateDir, spec.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	storeOpts, err := pairingStoreOptions(cfg.PairingTTL)
+	if err != nil {
+		return nil, err
+	}
+	return pairing.NewFileStore(path, storeOpts...)
+}
+
+// resolvePairingChannel picks the channel whose pairing store the command
+// operates on. wantChannel matches a channel name first, then a channel
+// type configured exactly once.
+func resolvePairingChannel(
 	opts runOptions,
 	wantChannel string,
 ) (pluginSpec, error) {
-	specs := resolveTelegramChannelSpecs(opts.Channels)
+	specs := resolvePairingChannelSpecs(opts.Channels)
 
 	if len(specs) == 0 {
-		return pluginSpec{}, errors.New("pairing: telegram not configured")
+		return pluginSpec{}, errors.New(
+			"pairing: no telegram, slack or discord channel configured",
+		)
 	}
 
 	name := strings.TrimSpace(wantChannel)
@@ -274,7 +335,7 @@ func resolveTelegramPairingChannel(
 			return specs[0], nil
 		}
 		return pluginSpec{}, errors.New(
-			"pairing: multiple telegram channels configured; " +
+			"pairing: multiple channels configured; " +
 				"use -channel to select one",
 		)
 	}
@@ -285,12 +346,40 @@ func resolveTelegramPairingChannel(
 			return spec, nil
 		}
 	}
+
+	var byType []pluginSpec
+	for _, spec := range specs {
+		if strings.EqualFold(strings.TrimSpace(spec.Type), name) {
+			byType = append(byType, spec)
+		}
+	}
+	if len(byType) == 1 {
+		return byType[0], nil
+	}
+	if len(byType) > 1 {
+		return pluginSpec{}, fmt.Errorf(
+			"pairing: multiple %s channels configured; "+
+				"use -channel <NAME> to select one",
+			name,
+		)
+	}
 	return pluginSpec{}, fmt.Errorf(
-		"pairing: telegram channel not found: %s",
+		"pairing: channel not found: %s",
 		name,
 	)
 }
 
+func resolvePairingChannelSpecs(specs []pluginSpec) []pluginSpec {
+	out := make([]pluginSpec, 0, len(specs))
+	for _, spec := range specs {
+		switch strings.ToLower(strings.TrimSpace(spec.Type)) {
+		case telegramChannelType, slackChannelType, discordChannelType:
+			out = append(out, spec)
+		}
+	}
+	return out
+}
+
 func normalizePairingArgs(args []string) ([]string, error) {
 	var (
 		flagArgs []string
@@ -292,7 +292,7 @@ func TestResolveTelegramPairingChannel_MultipleChannels(t *testing.T) {
 	var node yaml.Node
 	require.NoError(t, yaml.Unmarshal([]byte("token: x"), &node))
 
-	_, err := resolveTelegramPairingChannel(runOptions{
+	_, err := resolvePairingChannel(runOptions{
 		Channels: []pluginSpec{
 			{
 				Type:   telegramChannelType,
@@ -314,7 +314,7 @@ func TestResolveTelegramPairingChannel_SelectByName(t *testing.T) {
 	var node yaml.Node
 	require.NoError(t, yaml.Unmarshal([]byte("token: x"), &node))
 
-	spec, err := resolveTelegramPairingChannel(runOptions{
+	spec, err := resolvePairingChannel(runOptions{
 		Channels: []pluginSpec{
 			{
 				Type:   telegramChannelType,
@@ -336,7 +336,7 @@ func TestResolveTelegramPairingChannel_NotFound(t *testing.T) {
 	var node yaml.Node
 	require.NoError(t, yaml.Unmarshal([]byte("token: x"), &node))
 
-	_, err := resolveTelegramPairingChannel(runOptions{
+	_, err := resolvePairingChannel(runOptions{
 		Channels: []pluginSpec{
 			{
 				Type:   telegramChannelType,
@@ -0,0 +1,170 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package app
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/skill"
+)
+
+const subcmdSkills = "skills"
+
+const (
+	skillsCmdUpdate = "update"
+	skillsCmdVerify = "verify"
+)
+
+const skillsUsageText = `Usage: openclaw skills <command> [options] [skill...]
+
+Commands:
+  update [skill...]    Download skills and re-lock their checksums
+                       (all skills when none are named)
+  verify               Fetch every locked skill and verify it
+
+Options:
+  --lockfile <path>     Lockfile path (default skills.lock)
+  --cache-dir <path>    Skills cache directory (or SKILLS_CACHE_DIR)
+  --sign-key <path>     update: ed25519 private key file used to sign
+  --trusted-key <path>  verify: trusted ed25519 public key file,
+                        repeatable
+  --offline             verify: only use the cache (or SKILLS_OFFLINE)
+
+Key files hold a base64 encoded raw ed25519 key; private keys may be
+the 32 byte seed or the 64 byte key.`
+
+type skillsEnv struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string     { return strings.Join(*f, ",") }
+func (f *repeatedFlag) Set(v string) error { *f = append(*f, v); return nil }
+
+func runSkills(args []string) int {
+	env := skillsEnv{stdout: os.Stdout, stderr: os.Stderr}
+	return env.dispatch(args)
+}
+
+func (e *skillsEnv) dispatch(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(e.stderr, skillsUsageText)
+		return 2
+	}
+	switch cmd := strings.ToLower(strings.TrimSpace(args[0])); cmd {
+	case skillsCmdUpdate, skillsCmdVerify:
+		return e.run(cmd, args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprintln(e.stdout, skillsUsageText)
+		return 0
+	default:
+		fmt.Fprintf(e.stderr, "unknown skills command: %s\n", cmd)
+		fmt.Fprintln(e.stderr, skillsUsageText)
+		return 2
+	}
+}
+
+func (e *skillsEnv) run(cmd string, args []string) int {
+	fs := flag.NewFlagSet("skills "+cmd, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	lockPath := fs.String("lockfile", skill.DefaultLockfileName, "lockfile path")
+	cacheDir := fs.String("cache-dir", "", "skills cache directory")
+	signKey := fs.String("sign-key", "", "ed25519 private key file")
+	offline := fs.Bool("offline", false, "only use the cache")
+	var trusted repeatedFlag
+	fs.Var(&trusted, "trusted-key", "trusted ed25519 public key file")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	opts := []skill.LockOption{skill.WithLockCacheDir(*cacheDir)}
+	if *offline {
+		opts = append(opts, skill.WithOffline(true))
+	}
+	for _, path := range trusted {
+		key, err := readSkillKey(path, ed25519.PublicKeySize)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "error: %v\n", err)
+			return 1
+		}
+		opts = append(opts, skill.WithTrustedKeys(ed25519.PublicKey(key)))
+	}
+	if *signKey != "" {
+		key, err := readSkillKey(*signKey, ed25519.SeedSize, ed25519.PrivateKeySize)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "error: %v\n", err)
+			return 1
+		}
+		if len(key) == ed25519.SeedSize {
+			key = ed25519.NewKeyFromSeed(key)
+		}
+		opts = append(opts, skill.WithSigningKey(ed25519.PrivateKey(key)))
+	}
+
+	lf, err := skill.LoadLockfile(*lockPath)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "error: %v\n", err)
+		return 1
+	}
+	if cmd == skillsCmdVerify {
+		if _, err := lf.Fetch(opts...); err != nil {
+			fmt.Fprintf(e.stderr, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(e.stdout, "%d skills verified\n", len(lf.Skills))
+		return 0
+	}
+	changed, err := lf.Update(fs.Args(), opts...)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := lf.Save(*lockPath); err != nil {
+		fmt.Fprintf(e.stderr, "error: %v\n", err)
+		return 1
+	}
+	if len(changed) == 0 {
+		fmt.Fprintln(e.stdout, "all skills up to date")
+		return 0
+	}
+	for _, name := range changed {
+		fmt.Fprintf(e.stdout, "relocked %s\n", name)
+	}
+	return 0
+}
+
+func readSkillKey(path string, sizes ...int) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode key %s: %w", path, err)
+	}
+	for _, size := range sizes {
+		if len(key) == size {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("key %s has unexpected length %d", path, len(key))
+}
@@ -17,6 +17,8 @@ import (
 
 	"trpc.group/trpc-go/trpc-agent-go/openclaw/app"
 
+	_ "trpc.group/trpc-go/trpc-agent-go/openclaw/plugins/discord"
+	_ "trpc.group/trpc-go/trpc-agent-go/openclaw/plugins/slack"
 	_ "trpc.group/trpc-go/trpc-agent-go/openclaw/plugins/stdin"
 	_ "trpc.group/trpc-go/trpc-agent-go/openclaw/plugins/telegram"
 )
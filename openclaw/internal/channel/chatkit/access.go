@@ -0,0 +1,183 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package chatkit holds the access policy, pairing and attachment helpers
+// shared by the Slack and Discord channels.
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DM policies.
+const (
+	DMPolicyDisabled  = "disabled"
+	DMPolicyOpen      = "open"
+	DMPolicyAllowlist = "allowlist"
+	DMPolicyPairing   = "pairing"
+)
+
+// Group policies.
+const (
+	GroupPolicyDisabled  = "disabled"
+	GroupPolicyOpen      = "open"
+	GroupPolicyAllowlist = "allowlist"
+)
+
+// ThreadSep separates the segments of a thread key, for example
+// "<channel>:<thread_ts>". Allowlists match any prefix of whole segments.
+const ThreadSep = ":"
+
+// NotAllowedMessage is sent to users rejected by the allowlist.
+const NotAllowedMessage = "You are not allowed to use this bot."
+
+const pairingMessageTemplate = `Pairing required.
+
+Code: %s
+
+Ask the operator to approve:
+openclaw pairing approve %s -config <CONFIG> -channel %s`
+
+// PairingStore is the subset of pairing.FileStore used by channels.
+type PairingStore interface {
+	IsApproved(ctx context.Context, userID string) (bool, error)
+	Request(ctx context.Context, userID string) (string, bool, error)
+}
+
+// ParseDMPolicy validates a DM policy, defaulting to pairing.
+func ParseDMPolicy(raw string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	switch v {
+	case "":
+		return DMPolicyPairing, nil
+	case DMPolicyDisabled, DMPolicyOpen, DMPolicyAllowlist, DMPolicyPairing:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unsupported dm policy: %s", raw)
+	}
+}
+
+// ParseGroupPolicy validates a group policy, defaulting to disabled.
+func ParseGroupPolicy(raw string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	switch v {
+	case "":
+		return GroupPolicyDisabled, nil
+	case GroupPolicyDisabled, GroupPolicyOpen, GroupPolicyAllowlist:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unsupported group policy: %s", raw)
+	}
+}
+
+// StringSet builds a lookup set from trimmed non-empty values. It returns
+// nil when no value is left, which means "no allowlist configured".
+func StringSet(values ...string) map[string]struct{} {
+	var set map[string]struct{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if set == nil {
+			set = make(map[string]struct{})
+		}
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Access applies the DM/group policies, user allowlist and pairing flow of
+// one channel instance.
+type Access struct {
+	// Channel is the configured channel name shown in pairing hints.
+	Channel      string
+	DMPolicy     string
+	GroupPolicy  string
+	AllowUsers   map[string]struct{}
+	AllowThreads map[string]struct{}
+	Pairing      PairingStore
+}
+
+// UserAllowed reports whether the user passes the global allowlist.
+func (a *Access) UserAllowed(userID string) bool {
+	if a.AllowUsers == nil {
+		return true
+	}
+	_, ok := a.AllowUsers[userID]
+	return ok
+}
+
+// ThreadAllowed reports whether a group thread passes the group policy.
+// Under the allowlist policy a thread is allowed when the allowlist holds
+// the thread key or any of its leading segments.
+func (a *Access) ThreadAllowed(thread string) bool {
+	switch a.GroupPolicy {
+	case GroupPolicyOpen:
+		return true
+	case GroupPolicyAllowlist:
+		key := thread
+		for key != "" {
+			if _, ok := a.AllowThreads[key]; ok {
+				return true
+			}
+			idx := strings.LastIndex(key, ThreadSep)
+			if idx <= 0 {
+				break
+			}
+			key = key[:idx]
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// CheckDM applies the DM policy. When the user is rejected, notice holds
+// the text to send back to them, which may be empty.
+func (a *Access) CheckDM(
+	ctx context.Context,
+	userID string,
+) (ok bool, notice string, err error) {
+	switch a.DMPolicy {
+	case DMPolicyDisabled:
+		return false, "", nil
+	case DMPolicyOpen:
+		return true, "", nil
+	case DMPolicyAllowlist:
+		if a.AllowUsers == nil || !a.UserAllowed(userID) {
+			return false, NotAllowedMessage, nil
+		}
+		return true, "", nil
+	case DMPolicyPairing:
+		if a.Pairing == nil {
+			return false, "", errors.New("pairing store unavailable")
+		}
+		approved, err := a.Pairing.IsApproved(ctx, userID)
+		if err != nil || approved {
+			return approved, "", err
+		}
+		code, _, err := a.Pairing.Request(ctx, userID)
+		if err != nil {
+			return false, "", err
+		}
+		return false, fmt.Sprintf(
+			pairingMessageTemplate,
+			code,
+			code,
+			a.Channel,
+		), nil
+	default:
+		return false, "", fmt.Errorf("unsupported dm policy: %s", a.DMPolicy)
+	}
+}
@@ -0,0 +1,192 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package chatkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
+)
+
+func TestParsePolicies(t *testing.T) {
+	dm, err := ParseDMPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, DMPolicyPairing, dm)
+	dm, err = ParseDMPolicy(" Open ")
+	require.NoError(t, err)
+	require.Equal(t, DMPolicyOpen, dm)
+	_, err = ParseDMPolicy("maybe")
+	require.Error(t, err)
+
+	group, err := ParseGroupPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, GroupPolicyDisabled, group)
+	_, err = ParseGroupPolicy("pairing")
+	require.Error(t, err)
+}
+
+func TestAccess_ThreadAllowed(t *testing.T) {
+	a := &Access{
+		GroupPolicy:  GroupPolicyAllowlist,
+		AllowThreads: StringSet("G1", "G2:C2", " "),
+	}
+	require.True(t, a.ThreadAllowed("G1:C9"))
+	require.True(t, a.ThreadAllowed("G2:C2"))
+	require.True(t, a.ThreadAllowed("G2:C2:1.0"))
+	require.False(t, a.ThreadAllowed("G2:C3"))
+	require.False(t, a.ThreadAllowed("G3"))
+
+	a.GroupPolicy = GroupPolicyOpen
+	require.True(t, a.ThreadAllowed("G3"))
+	a.GroupPolicy = GroupPolicyDisabled
+	require.False(t, a.ThreadAllowed("G1"))
+}
+
+func TestAccess_CheckDM(t *testing.T) {
+	ctx := context.Background()
+	store, err := pairing.NewFileStore(
+		filepath.Join(t.TempDir(), "pairing.json"),
+	)
+	require.NoError(t, err)
+	a := &Access{
+		Channel:    "work",
+		DMPolicy:   DMPolicyPairing,
+		AllowUsers: StringSet("U1", "U2"),
+		Pairing:    store,
+	}
+
+	require.True(t, a.UserAllowed("U1"))
+	require.False(t, a.UserAllowed("U3"))
+
+	ok, notice, err := a.CheckDM(ctx, "U1")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, notice, "Pairing required.")
+	require.Contains(t, notice, "-channel work")
+
+	pending, err := store.ListPending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	_, approved, err := store.Approve(ctx, pending[0].Code)
+	require.NoError(t, err)
+	require.True(t, approved)
+
+	ok, notice, err = a.CheckDM(ctx, "U1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, notice)
+
+	a.DMPolicy = DMPolicyAllowlist
+	ok, notice, err = a.CheckDM(ctx, "U3")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, NotAllowedMessage, notice)
+
+	a.DMPolicy = DMPolicyDisabled
+	ok, _, err = a.CheckDM(ctx, "U1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	a.DMPolicy = DMPolicyPairing
+	a.Pairing = nil
+	_, _, err = a.CheckDM(ctx, "U1")
+	require.Error(t, err)
+}
+
+func TestAttachmentParts(t *testing.T) {
+	parts := AttachmentParts("cat.PNG", "", []byte("png"))
+	require.Len(t, parts, 2)
+	require.Equal(t, gwproto.PartTypeImage, parts[0].Type)
+	require.Equal(t, "png", parts[0].Image.Format)
+	require.Equal(t, "image/png", parts[1].File.Format)
+
+	parts = AttachmentParts("notes", "text/plain; charset=utf-8", []byte("x"))
+	require.Len(t, parts, 1)
+	require.Equal(t, gwproto.PartTypeFile, parts[0].Type)
+	require.Equal(t, "text/plain", parts[0].File.Format)
+	require.Equal(t, "notes", parts[0].File.Filename)
+}
+
+func TestSplitRunes(t *testing.T) {
+	require.Equal(t, []string{"héllo"}, SplitRunes("héllo", 10))
+	require.Equal(t, []string{"hé", "ll", "o"}, SplitRunes("héllo", 2))
+}
+
+func TestLaneLocker(t *testing.T) {
+	l := NewLaneLocker()
+	var (
+		mu      sync.Mutex
+		running int
+		maxSeen int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.Do("lane", func() error {
+				mu.Lock()
+				running++
+				maxSeen = max(maxSeen, running)
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, maxSeen)
+	require.Empty(t, l.lanes)
+}
+
+func TestPairingStorePath(t *testing.T) {
+	path, err := PairingStorePath("/state", "slack", "team a/b")
+	require.NoError(t, err)
+	require.Equal(t, "/state/slack/pairing-team_a_b.json", path)
+	path, err = PairingStorePath("/state", "discord", "")
+	require.NoError(t, err)
+	require.Equal(t, "/state/discord/pairing-default.json", path)
+	_, err = PairingStorePath(" ", "slack", "")
+	require.Error(t, err)
+}
+
+func TestLoadOutboundFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+
+	f, err := LoadOutboundFile(context.Background(), channel.OutboundFile{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Equal(t, OutboundFile{Name: "report.txt", Data: []byte("data")}, f)
+
+	f, err = LoadOutboundFile(context.Background(), channel.OutboundFile{
+		Path: path,
+		Name: "renamed.txt",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "renamed.txt", f.Name)
+
+	_, err = LoadOutboundFile(context.Background(), channel.OutboundFile{})
+	require.Error(t, err)
+}
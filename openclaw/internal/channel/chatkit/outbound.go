@@ -0,0 +1,125 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/artifact"
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor"
+	"trpc.group/trpc-go/trpc-agent-go/internal/fileref"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+)
+
+const defaultOutboundName = "attachment"
+
+// OutboundFile is an outbound file loaded into memory.
+type OutboundFile struct {
+	Name string
+	Data []byte
+}
+
+// LoadOutboundFile reads an outbound file from an artifact:// or
+// workspace:// reference, or from a host path where "~" expands to the
+// user home.
+func LoadOutboundFile(
+	ctx context.Context,
+	file channel.OutboundFile,
+) (OutboundFile, error) {
+	raw := strings.TrimSpace(file.Path)
+	if raw == "" {
+		return OutboundFile{}, errors.New("empty file path")
+	}
+
+	var (
+		data []byte
+		name string
+	)
+	switch {
+	case strings.HasPrefix(raw, fileref.ArtifactPrefix):
+		ref, err := fileref.Parse(raw)
+		if err != nil {
+			return OutboundFile{}, fmt.Errorf("parse artifact ref: %w", err)
+		}
+		data, _, _, err = codeexecutor.LoadArtifactHelper(
+			withArtifactContext(ctx),
+			ref.ArtifactName,
+			ref.ArtifactVersion,
+		)
+		if err != nil {
+			return OutboundFile{}, fmt.Errorf("load artifact: %w", err)
+		}
+		name = path.Base(ref.ArtifactName)
+	case strings.HasPrefix(raw, fileref.WorkspacePrefix):
+		content, _, handled, err := fileref.TryRead(ctx, raw)
+		if err != nil {
+			return OutboundFile{}, fmt.Errorf("load workspace ref: %w", err)
+		}
+		if !handled {
+			return OutboundFile{}, fmt.Errorf("unsupported workspace ref: %s", raw)
+		}
+		ref, err := fileref.Parse(raw)
+		if err != nil {
+			return OutboundFile{}, fmt.Errorf("parse workspace ref: %w", err)
+		}
+		data = []byte(content)
+		name = path.Base(ref.Path)
+	default:
+		resolved := raw
+		if resolved == "~" || strings.HasPrefix(resolved, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return OutboundFile{}, fmt.Errorf("resolve home dir: %w", err)
+			}
+			resolved = filepath.Join(home, strings.TrimPrefix(resolved[1:], "/"))
+		}
+		var err error
+		data, err = os.ReadFile(resolved)
+		if err != nil {
+			return OutboundFile{}, fmt.Errorf("read file: %w", err)
+		}
+		name = filepath.Base(resolved)
+	}
+
+	if hint := strings.TrimSpace(file.Name); hint != "" {
+		name = hint
+	}
+	if name == "" || name == "." || name == "/" {
+		name = defaultOutboundName
+	}
+	return OutboundFile{Name: name, Data: data}, nil
+}
+
+func withArtifactContext(ctx context.Context) context.Context {
+	if svc, ok := codeexecutor.ArtifactServiceFromContext(ctx); ok &&
+		svc != nil {
+		return ctx
+	}
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil || inv.ArtifactService == nil ||
+		inv.Session == nil {
+		return ctx
+	}
+	ctx = codeexecutor.WithArtifactService(ctx, inv.ArtifactService)
+	return codeexecutor.WithArtifactSession(ctx, artifact.SessionInfo{
+		AppName:   inv.Session.AppName,
+		UserID:    inv.Session.UserID,
+		SessionID: inv.Session.ID,
+	})
+}
@@ -0,0 +1,139 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package chatkit
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+)
+
+// DefaultMaxDownloadBytes is the default per-attachment download limit.
+const DefaultMaxDownloadBytes int64 = 20 << 20
+
+// AttachmentParts converts a downloaded attachment into gateway content
+// parts. Images become an image part plus a file part so the bytes can
+// also be stored as an upload; other files become a file part.
+func AttachmentParts(
+	filename string,
+	mimeType string,
+	data []byte,
+) []gwproto.ContentPart {
+	mimeType = normalizeMIME(filename, mimeType)
+	file := gwproto.ContentPart{
+		Type: gwproto.PartTypeFile,
+		File: &gwproto.FilePart{
+			Filename: filename,
+			Data:     data,
+			Format:   mimeType,
+		},
+	}
+	if format := imageFormat(mimeType); format != "" {
+		return []gwproto.ContentPart{{
+			Type: gwproto.PartTypeImage,
+			Image: &gwproto.ImagePart{
+				Data:   data,
+				Format: format,
+			},
+		}, file}
+	}
+	return []gwproto.ContentPart{file}
+}
+
+func normalizeMIME(filename, mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if idx := strings.IndexByte(mimeType, ';'); idx >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:idx])
+	}
+	if mimeType != "" {
+		return mimeType
+	}
+	if byExt := mime.TypeByExtension(path.Ext(filename)); byExt != "" {
+		return normalizeMIME("", byExt)
+	}
+	return "application/octet-stream"
+}
+
+func imageFormat(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg", "image/jpg":
+		return "jpeg"
+	case "image/png":
+		return "png"
+	case "image/gif":
+		return "gif"
+	case "image/webp":
+		return "webp"
+	default:
+		return ""
+	}
+}
+
+// SplitRunes splits text into chunks of at most limit runes.
+func SplitRunes(text string, limit int) []string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return []string{text}
+	}
+	parts := make([]string, 0, len(runes)/limit+1)
+	for len(runes) > limit {
+		parts = append(parts, string(runes[:limit]))
+		runes = runes[limit:]
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+	return parts
+}
+
+// LaneLocker serializes work per conversation lane so replies in one
+// conversation keep their order while other conversations run in parallel.
+type LaneLocker struct {
+	mu    sync.Mutex
+	lanes map[string]*lane
+}
+
+type lane struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewLaneLocker creates an empty LaneLocker.
+func NewLaneLocker() *LaneLocker {
+	return &LaneLocker{lanes: make(map[string]*lane)}
+}
+
+// Do runs fn while holding the lock of key.
+func (l *LaneLocker) Do(key string, fn func() error) error {
+	l.mu.Lock()
+	ln := l.lanes[key]
+	if ln == nil {
+		ln = &lane{}
+		l.lanes[key] = ln
+	}
+	ln.refs++
+	l.mu.Unlock()
+
+	ln.mu.Lock()
+	defer func() {
+		ln.mu.Unlock()
+		l.mu.Lock()
+		ln.refs--
+		if ln.refs == 0 {
+			delete(l.lanes, key)
+		}
+		l.mu.Unlock()
+	}()
+	return fn()
+}
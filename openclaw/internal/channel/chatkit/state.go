@@ -0,0 +1,75 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package chatkit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultStateRootDir = ".trpc-agent-go-github"
+	defaultStateAppName = "openclaw"
+
+	defaultInstanceName = "default"
+
+	pairingStoreFilePrefix = "pairing-"
+	pairingStoreFileSuffix = ".json"
+)
+
+// ResolveStateDir returns stateDir, or the default OpenClaw state dir
+// under the user home when it is empty.
+func ResolveStateDir(stateDir string) (string, error) {
+	if trimmed := strings.TrimSpace(stateDir); trimmed != "" {
+		return trimmed, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, defaultStateRootDir, defaultStateAppName), nil
+}
+
+// PairingStorePath returns the pairing store file of one configured
+// channel instance, for example "<state>/slack/pairing-default.json".
+func PairingStorePath(
+	stateDir string,
+	channelType string,
+	name string,
+) (string, error) {
+	if strings.TrimSpace(stateDir) == "" {
+		return "", errors.New("empty state dir")
+	}
+	return filepath.Join(
+		stateDir,
+		channelType,
+		pairingStoreFilePrefix+InstanceName(name)+pairingStoreFileSuffix,
+	), nil
+}
+
+// InstanceName returns a file-safe name for a configured channel instance.
+func InstanceName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultInstanceName
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z',
+			r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
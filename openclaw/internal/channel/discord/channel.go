@@ -0,0 +1,418 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package discord implements the OpenClaw Discord channel on top of the
+// gateway websocket. Direct messages map to one conversation per user and
+// every guild channel or thread maps to its own conversation; in guilds
+// the bot only answers messages that mention it.
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/chatkit"
+	dcapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/discord"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
+)
+
+const (
+	channelID = "discord"
+
+	requestIDPrefix = "discord:"
+
+	laneDMPrefix     = channelID + ":dm:"
+	laneThreadPrefix = channelID + ":thread:"
+
+	// maxReplyRunes is the Discord message content limit.
+	maxReplyRunes = 2000
+
+	defaultPairingTTL = time.Hour
+)
+
+// ChannelName is the stable channel identifier used across OpenClaw.
+const ChannelName = channelID
+
+const (
+	failedMessage = "Failed to process message."
+
+	fileTooLargeTemplate = "Attachment %s is larger than %d bytes " +
+		"and was skipped."
+)
+
+type gatewayClient interface {
+	SendMessage(
+		ctx context.Context,
+		req gwclient.MessageRequest,
+	) (gwclient.MessageResponse, error)
+}
+
+type restAPI interface {
+	CreateMessage(
+		ctx context.Context,
+		channelID string,
+		params dcapi.CreateMessageParams,
+	) (dcapi.Message, error)
+
+	CreateDM(ctx context.Context, userID string) (dcapi.Channel, error)
+
+	DownloadAttachment(
+		ctx context.Context,
+		fileURL string,
+		maxBytes int64,
+	) ([]byte, error)
+}
+
+type eventSource interface {
+	Run(ctx context.Context, handler dcapi.MessageHandler) error
+}
+
+type config struct {
+	name             string
+	stateDir         string
+	dmPolicy         string
+	groupPolicy      string
+	allowUsers       []string
+	allowThreads     []string
+	pairingTTL       time.Duration
+	maxDownloadBytes int64
+}
+
+// Option configures the Discord channel.
+type Option func(*config)
+
+// WithName sets the configured plugin instance name, which keys the
+// pairing store.
+func WithName(name string) Option {
+	return func(c *config) { c.name = name }
+}
+
+// WithStateDir sets the state directory for the pairing store.
+func WithStateDir(dir string) Option {
+	return func(c *config) { c.stateDir = dir }
+}
+
+// WithDMPolicy sets the policy for direct messages.
+func WithDMPolicy(policy string) Option {
+	return func(c *config) { c.dmPolicy = policy }
+}
+
+// WithGroupPolicy sets the policy for guild messages.
+func WithGroupPolicy(policy string) Option {
+	return func(c *config) { c.groupPolicy = policy }
+}
+
+// WithAllowUsers sets a per-channel user allowlist of Discord user IDs.
+func WithAllowUsers(users ...string) Option {
+	return func(c *config) { c.allowUsers = append(c.allowUsers, users...) }
+}
+
+// WithAllowThreads sets an allowlist for guilds and guild channels.
+//
+// Values should match the `thread` field derived by this channel:
+//   - Guild: "<guild_id>"
+//   - Channel or thread: "<guild_id>:<channel_id>"
+func WithAllowThreads(threads ...string) Option {
+	return func(c *config) {
+		c.allowThreads = append(c.allowThreads, threads...)
+	}
+}
+
+// WithPairingTTL sets how long pairing codes stay valid.
+func WithPairingTTL(ttl time.Duration) Option {
+	return func(c *config) { c.pairingTTL = ttl }
+}
+
+// WithMaxDownloadBytes sets the per-file download limit for attachments.
+func WithMaxDownloadBytes(maxBytes int64) Option {
+	return func(c *config) { c.maxDownloadBytes = maxBytes }
+}
+
+// Channel implements a Discord gateway chat surface.
+type Channel struct {
+	api    restAPI
+	events eventSource
+	gw     gatewayClient
+	botID  string
+
+	access chatkit.Access
+
+	maxDownloadBytes int64
+
+	lanes *chatkit.LaneLocker
+}
+
+// New creates a Discord channel for the bot user me.
+func New(
+	api *dcapi.Client,
+	gateway *dcapi.Gateway,
+	me dcapi.User,
+	gw gatewayClient,
+	opts ...Option,
+) (*Channel, error) {
+	if api == nil || gateway == nil {
+		return nil, errors.New("discord: nil api client")
+	}
+	if gw == nil {
+		return nil, errors.New("discord: nil gateway client")
+	}
+
+	cfg := config{
+		pairingTTL:       defaultPairingTTL,
+		maxDownloadBytes: chatkit.DefaultMaxDownloadBytes,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	dmPolicy, err := chatkit.ParseDMPolicy(cfg.dmPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("discord: %w", err)
+	}
+	groupPolicy, err := chatkit.ParseGroupPolicy(cfg.groupPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("discord: %w", err)
+	}
+	if cfg.pairingTTL <= 0 {
+		return nil, errors.New("discord: non-positive pairing ttl")
+	}
+	if cfg.maxDownloadBytes <= 0 {
+		return nil, errors.New("discord: non-positive max download bytes")
+	}
+
+	hintName := strings.TrimSpace(cfg.name)
+	if hintName == "" {
+		hintName = channelID
+	}
+	access := chatkit.Access{
+		Channel:      hintName,
+		DMPolicy:     dmPolicy,
+		GroupPolicy:  groupPolicy,
+		AllowUsers:   chatkit.StringSet(cfg.allowUsers...),
+		AllowThreads: chatkit.StringSet(cfg.allowThreads...),
+	}
+	if dmPolicy == chatkit.DMPolicyPairing {
+		stateDir, err := chatkit.ResolveStateDir(cfg.stateDir)
+		if err != nil {
+			return nil, err
+		}
+		path, err := PairingStorePath(stateDir, cfg.name)
+		if err != nil {
+			return nil, err
+		}
+		store, err := pairing.NewFileStore(
+			path,
+			pairing.WithTTL(cfg.pairingTTL),
+		)
+		if err != nil {
+			return nil, err
+		}
+		access.Pairing = store
+	}
+
+	return &Channel{
+		api:              api,
+		events:           gateway,
+		gw:               gw,
+		botID:            me.ID,
+		access:           access,
+		maxDownloadBytes: cfg.maxDownloadBytes,
+		lanes:            chatkit.NewLaneLocker(),
+	}, nil
+}
+
+// PairingStorePath returns the pairing store of a configured Discord
+// channel instance.
+func PairingStorePath(stateDir string, name string) (string, error) {
+	path, err := chatkit.PairingStorePath(stateDir, channelID, name)
+	if err != nil {
+		return "", fmt.Errorf("discord: %w", err)
+	}
+	return path, nil
+}
+
+// ID returns the channel identifier used by the gateway.
+func (c *Channel) ID() string { return channelID }
+
+// Run receives gateway events and blocks until ctx is done.
+func (c *Channel) Run(ctx context.Context) error {
+	if c == nil {
+		return errors.New("discord: nil channel")
+	}
+	return c.events.Run(ctx, func(ctx context.Context, msg dcapi.Message) {
+		go func() {
+			if err := c.handleMessage(ctx, msg); err != nil {
+				log.WarnfContext(ctx, "discord: handle message: %v", err)
+			}
+		}()
+	})
+}
+
+func (c *Channel) handleMessage(ctx context.Context, msg dcapi.Message) error {
+	if msg.Author.Bot || msg.Author.ID == "" || msg.Author.ID == c.botID {
+		return nil
+	}
+
+	fromID := msg.Author.ID
+	isDM := msg.GuildID == ""
+	thread := ""
+	if !isDM {
+		if !c.mentionsBot(msg) {
+			return nil
+		}
+		thread = msg.GuildID + chatkit.ThreadSep + msg.ChannelID
+	}
+
+	if !c.access.UserAllowed(fromID) {
+		if isDM {
+			c.send(ctx, msg.ChannelID, "", chatkit.NotAllowedMessage)
+		}
+		return nil
+	}
+	if isDM {
+		ok, notice, err := c.access.CheckDM(ctx, fromID)
+		if err != nil {
+			return fmt.Errorf("discord: %w", err)
+		}
+		if !ok {
+			if notice != "" {
+				c.send(ctx, msg.ChannelID, "", notice)
+			}
+			return nil
+		}
+	} else if !c.access.ThreadAllowed(thread) {
+		return nil
+	}
+
+	laneKey := laneDMPrefix + fromID
+	if thread != "" {
+		laneKey = laneThreadPrefix + thread
+	}
+	requestID := requestIDPrefix + msg.ChannelID + ":" + msg.ID
+
+	return c.lanes.Do(laneKey, func() error {
+		req, err := c.buildGatewayRequest(ctx, msg, thread, laneKey, requestID)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Text) == "" && len(req.ContentParts) == 0 {
+			return nil
+		}
+		rsp, err := c.gw.SendMessage(ctx, req)
+		if err != nil {
+			if rsp.StatusCode >= http.StatusBadRequest &&
+				rsp.StatusCode < http.StatusInternalServerError {
+				log.WarnfContext(ctx, "discord: gateway rejected: %v", err)
+				return nil
+			}
+			c.send(ctx, msg.ChannelID, msg.ID, failedMessage)
+			return err
+		}
+		if rsp.Ignored || strings.TrimSpace(rsp.Reply) == "" {
+			return nil
+		}
+		replyTo := msg.ID
+		for _, part := range chatkit.SplitRunes(rsp.Reply, maxReplyRunes) {
+			if _, err := c.api.CreateMessage(
+				ctx,
+				msg.ChannelID,
+				dcapi.CreateMessageParams{Content: part, ReplyTo: replyTo},
+			); err != nil {
+				return err
+			}
+			replyTo = ""
+		}
+		return nil
+	})
+}
+
+func (c *Channel) buildGatewayRequest(
+	ctx context.Context,
+	msg dcapi.Message,
+	thread string,
+	sessionID string,
+	requestID string,
+) (gwclient.MessageRequest, error) {
+	req := gwclient.MessageRequest{
+		Channel:   channelID,
+		From:      msg.Author.ID,
+		Thread:    thread,
+		MessageID: msg.ID,
+		Text:      c.stripBotMention(msg.Content),
+		UserID:    msg.Author.ID,
+		SessionID: sessionID,
+		RequestID: requestID,
+	}
+	var parts []gwproto.ContentPart
+	for _, a := range msg.Attachments {
+		if a.Size > c.maxDownloadBytes {
+			c.send(ctx, msg.ChannelID, msg.ID, fmt.Sprintf(
+				fileTooLargeTemplate, a.Filename, c.maxDownloadBytes,
+			))
+			continue
+		}
+		data, err := c.api.DownloadAttachment(ctx, a.URL, c.maxDownloadBytes)
+		if errors.Is(err, dcapi.ErrFileTooLarge) {
+			c.send(ctx, msg.ChannelID, msg.ID, fmt.Sprintf(
+				fileTooLargeTemplate, a.Filename, c.maxDownloadBytes,
+			))
+			continue
+		}
+		if err != nil {
+			return gwclient.MessageRequest{}, err
+		}
+		parts = append(
+			parts,
+			chatkit.AttachmentParts(a.Filename, a.ContentType, data)...,
+		)
+	}
+	req.ContentParts = parts
+	return req, nil
+}
+
+func (c *Channel) send(
+	ctx context.Context,
+	channelID string,
+	replyTo string,
+	text string,
+) {
+	_, err := c.api.CreateMessage(ctx, channelID, dcapi.CreateMessageParams{
+		Content: text,
+		ReplyTo: replyTo,
+	})
+	if err != nil {
+		log.WarnfContext(ctx, "discord: send message: %v", err)
+	}
+}
+
+func (c *Channel) mentionsBot(msg dcapi.Message) bool {
+	for _, u := range msg.Mentions {
+		if u.ID == c.botID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Channel) stripBotMention(text string) string {
+	if c.botID != "" {
+		text = strings.ReplaceAll(text, "<@"+c.botID+">", "")
+		text = strings.ReplaceAll(text, "<@!"+c.botID+">", "")
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,262 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+	dcapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/discord"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
+)
+
+const testBotID = "B1"
+
+type stubGateway struct {
+	mu    sync.Mutex
+	reqs  []gwclient.MessageRequest
+	reply string
+}
+
+func (g *stubGateway) SendMessage(
+	_ context.Context,
+	req gwclient.MessageRequest,
+) (gwclient.MessageResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reqs = append(g.reqs, req)
+	return gwclient.MessageResponse{Reply: g.reply}, nil
+}
+
+type sentMessage struct {
+	channelID string
+	params    dcapi.CreateMessageParams
+}
+
+type stubAPI struct {
+	sent  []sentMessage
+	dms   []string
+	files map[string][]byte
+}
+
+func (a *stubAPI) CreateMessage(
+	_ context.Context,
+	channelID string,
+	params dcapi.CreateMessageParams,
+) (dcapi.Message, error) {
+	a.sent = append(a.sent, sentMessage{channelID: channelID, params: params})
+	return dcapi.Message{ID: "M9", ChannelID: channelID}, nil
+}
+
+func (a *stubAPI) CreateDM(
+	_ context.Context,
+	userID string,
+) (dcapi.Channel, error) {
+	a.dms = append(a.dms, userID)
+	return dcapi.Channel{ID: "DM-" + userID}, nil
+}
+
+func (a *stubAPI) DownloadAttachment(
+	_ context.Context,
+	fileURL string,
+	maxBytes int64,
+) ([]byte, error) {
+	data := a.files[fileURL]
+	if int64(len(data)) > maxBytes {
+		return nil, dcapi.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func newTestChannel(
+	t *testing.T,
+	opts ...Option,
+) (*Channel, *stubAPI, *stubGateway) {
+	t.Helper()
+	client, err := dcapi.New("tok")
+	require.NoError(t, err)
+	gw := &stubGateway{reply: "pong"}
+	opts = append([]Option{WithStateDir(t.TempDir())}, opts...)
+	c, err := New(
+		client,
+		dcapi.NewGateway(client),
+		dcapi.User{ID: testBotID},
+		gw,
+		opts...,
+	)
+	require.NoError(t, err)
+	api := &stubAPI{files: map[string][]byte{}}
+	c.api = api
+	return c, api, gw
+}
+
+func TestNew_Validation(t *testing.T) {
+	client, err := dcapi.New("tok")
+	require.NoError(t, err)
+	gateway := dcapi.NewGateway(client)
+	me := dcapi.User{ID: testBotID}
+
+	_, err = New(client, nil, me, &stubGateway{})
+	require.Error(t, err)
+	_, err = New(client, gateway, me, nil)
+	require.Error(t, err)
+	_, err = New(client, gateway, me, &stubGateway{},
+		WithGroupPolicy("pairing"))
+	require.Error(t, err)
+	_, err = New(client, gateway, me, &stubGateway{},
+		WithPairingTTL(-1))
+	require.Error(t, err)
+}
+
+func TestChannel_DMPairing(t *testing.T) {
+	ctx := context.Background()
+	stateDir := t.TempDir()
+	c, api, gw := newTestChannel(t, WithStateDir(stateDir))
+
+	msg := dcapi.Message{
+		ID:        "M1",
+		ChannelID: "D1",
+		Author:    dcapi.User{ID: "U1"},
+		Content:   "hi",
+	}
+	require.NoError(t, c.handleMessage(ctx, msg))
+	require.Empty(t, gw.reqs)
+	require.Len(t, api.sent, 1)
+	require.Contains(t, api.sent[0].params.Content, "-channel discord")
+
+	path, err := PairingStorePath(stateDir, "")
+	require.NoError(t, err)
+	store, err := pairing.NewFileStore(path)
+	require.NoError(t, err)
+	pending, err := store.ListPending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	_, _, err = store.Approve(ctx, pending[0].Code)
+	require.NoError(t, err)
+
+	msg.ID = "M2"
+	require.NoError(t, c.handleMessage(ctx, msg))
+	require.Len(t, gw.reqs, 1)
+	require.Equal(t, "discord:dm:U1", gw.reqs[0].SessionID)
+	require.Equal(t, "discord:D1:M2", gw.reqs[0].RequestID)
+	require.Equal(t, sentMessage{
+		channelID: "D1",
+		params:    dcapi.CreateMessageParams{Content: "pong", ReplyTo: "M2"},
+	}, api.sent[len(api.sent)-1])
+}
+
+func TestChannel_GuildMentions(t *testing.T) {
+	ctx := context.Background()
+	c, api, gw := newTestChannel(t,
+		WithGroupPolicy("allowlist"), WithAllowThreads("G1"))
+	gw.reply = strings.Repeat("x", maxReplyRunes+1)
+
+	// Guild messages without a mention are ignored.
+	require.NoError(t, c.handleMessage(ctx, dcapi.Message{
+		ID: "M1", ChannelID: "C1", GuildID: "G1",
+		Author: dcapi.User{ID: "U1"}, Content: "hello",
+	}))
+	// Guilds outside the allowlist are ignored.
+	require.NoError(t, c.handleMessage(ctx, dcapi.Message{
+		ID: "M2", ChannelID: "C1", GuildID: "G2",
+		Author: dcapi.User{ID: "U1"}, Content: "<@B1> hello",
+		Mentions: []dcapi.User{{ID: testBotID}},
+	}))
+	// Bots are ignored.
+	require.NoError(t, c.handleMessage(ctx, dcapi.Message{
+		ID: "M3", ChannelID: "C1", GuildID: "G1",
+		Author: dcapi.User{ID: "U2", Bot: true}, Content: "<@B1> hi",
+		Mentions: []dcapi.User{{ID: testBotID}},
+	}))
+	require.Empty(t, gw.reqs)
+
+	require.NoError(t, c.handleMessage(ctx, dcapi.Message{
+		ID: "M4", ChannelID: "C1", GuildID: "G1",
+		Author: dcapi.User{ID: "U1"}, Content: "<@!B1> hello",
+		Mentions: []dcapi.User{{ID: testBotID}},
+	}))
+	require.Len(t, gw.reqs, 1)
+	require.Equal(t, "G1:C1", gw.reqs[0].Thread)
+	require.Equal(t, "discord:thread:G1:C1", gw.reqs[0].SessionID)
+	require.Equal(t, "hello", gw.reqs[0].Text)
+
+	require.Len(t, api.sent, 2)
+	require.Equal(t, "M4", api.sent[0].params.ReplyTo)
+	require.Empty(t, api.sent[1].params.ReplyTo)
+}
+
+func TestChannel_Attachments(t *testing.T) {
+	ctx := context.Background()
+	c, api, gw := newTestChannel(t,
+		WithDMPolicy("open"), WithMaxDownloadBytes(8))
+	api.files["https://cdn/a.png"] = []byte("png")
+
+	require.NoError(t, c.handleMessage(ctx, dcapi.Message{
+		ID: "M1", ChannelID: "D1", Author: dcapi.User{ID: "U1"},
+		Attachments: []dcapi.Attachment{
+			{Filename: "a.png", ContentType: "image/png", Size: 3,
+				URL: "https://cdn/a.png"},
+			{Filename: "big.bin", Size: 100, URL: "https://cdn/big.bin"},
+		},
+	}))
+	require.Len(t, gw.reqs, 1)
+	parts := gw.reqs[0].ContentParts
+	require.Len(t, parts, 2)
+	require.Equal(t, gwproto.PartTypeImage, parts[0].Type)
+	require.Contains(t, api.sent[0].params.Content, "big.bin")
+}
+
+func TestResolveTextTargetFromSessionID(t *testing.T) {
+	target, ok := ResolveTextTargetFromSessionID("discord:dm:U1")
+	require.True(t, ok)
+	require.Equal(t, "user:U1", target)
+	target, ok = ResolveTextTargetFromSessionID("discord:thread:G1:C1")
+	require.True(t, ok)
+	require.Equal(t, "C1", target)
+	_, ok = ResolveTextTargetFromSessionID("discord:thread:G1")
+	require.False(t, ok)
+	_, ok = ResolveTextTargetFromSessionID("slack:dm:U1")
+	require.False(t, ok)
+}
+
+func TestChannel_SendMessage(t *testing.T) {
+	ctx := context.Background()
+	c, api, _ := newTestChannel(t, WithDMPolicy("open"))
+	path := filepath.Join(t.TempDir(), "a.txt")
+	require.NoError(t, os.WriteFile(path, []byte("A"), 0o600))
+
+	require.NoError(t, c.SendText(ctx, "user:U1", "hi"))
+	require.Equal(t, []string{"U1"}, api.dms)
+	require.Equal(t, "DM-U1", api.sent[0].channelID)
+
+	require.NoError(t, c.SendMessage(ctx, "C1", channel.OutboundMessage{
+		Files: []channel.OutboundFile{{Path: path}},
+	}))
+	require.Equal(t, sentMessage{
+		channelID: "C1",
+		params: dcapi.CreateMessageParams{
+			Files: []dcapi.UploadFile{{Name: "a.txt", Data: []byte("A")}},
+		},
+	}, api.sent[1])
+
+	require.NoError(t, c.SendText(ctx, "C1", " "))
+	require.Len(t, api.sent, 2)
+	require.Error(t, c.SendText(ctx, "user:", "x"))
+}
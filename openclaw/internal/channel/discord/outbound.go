@@ -0,0 +1,135 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/chatkit"
+	dcapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/discord"
+)
+
+// targetUserPrefix marks a target that is a user to DM instead of a
+// channel ID.
+const targetUserPrefix = "user:"
+
+// ResolveTextTargetFromSessionID converts a Discord session id into the
+// outbound target used by SendText.
+//
+// DM sessions ("discord:dm:<user>") resolve to "user:<user>" and guild
+// sessions ("discord:thread:<guild>:<channel>") resolve to the channel ID.
+func ResolveTextTargetFromSessionID(sessionID string) (string, bool) {
+	raw := strings.TrimSpace(sessionID)
+	switch {
+	case strings.HasPrefix(raw, laneDMPrefix):
+		user := strings.TrimPrefix(raw, laneDMPrefix)
+		if user == "" {
+			return "", false
+		}
+		return targetUserPrefix + user, true
+	case strings.HasPrefix(raw, laneThreadPrefix):
+		thread := strings.TrimPrefix(raw, laneThreadPrefix)
+		_, channelID, ok := strings.Cut(thread, chatkit.ThreadSep)
+		if !ok || channelID == "" {
+			return "", false
+		}
+		return channelID, true
+	default:
+		return "", false
+	}
+}
+
+// SendText implements channel.TextSender for Discord.
+//
+// Target is a channel ID, or "user:<user_id>" to send a DM.
+func (c *Channel) SendText(
+	ctx context.Context,
+	target string,
+	text string,
+) error {
+	return c.SendMessage(ctx, target, channel.OutboundMessage{Text: text})
+}
+
+// SendMessage implements channel.MessageSender for Discord. Files are
+// attached to the last text chunk.
+func (c *Channel) SendMessage(
+	ctx context.Context,
+	target string,
+	msg channel.OutboundMessage,
+) error {
+	if c == nil || c.api == nil {
+		return errors.New("discord: sender unavailable")
+	}
+	channelID, err := c.resolveTarget(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	files := make([]dcapi.UploadFile, 0, len(msg.Files))
+	for _, f := range msg.Files {
+		loaded, err := chatkit.LoadOutboundFile(ctx, f)
+		if err != nil {
+			return fmt.Errorf("discord: %w", err)
+		}
+		files = append(files, dcapi.UploadFile{
+			Name: loaded.Name,
+			Data: loaded.Data,
+		})
+	}
+
+	var chunks []string
+	for _, part := range chatkit.SplitRunes(msg.Text, maxReplyRunes) {
+		if strings.TrimSpace(part) != "" {
+			chunks = append(chunks, part)
+		}
+	}
+	if len(chunks) == 0 {
+		if len(files) == 0 {
+			return nil
+		}
+		chunks = []string{""}
+	}
+	for i, chunk := range chunks {
+		params := dcapi.CreateMessageParams{Content: chunk}
+		if i == len(chunks)-1 {
+			params.Files = files
+		}
+		if _, err := c.api.CreateMessage(ctx, channelID, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Channel) resolveTarget(
+	ctx context.Context,
+	target string,
+) (string, error) {
+	raw := strings.TrimSpace(target)
+	if user, ok := strings.CutPrefix(raw, targetUserPrefix); ok {
+		if user == "" {
+			return "", fmt.Errorf("discord: invalid target: %q", target)
+		}
+		dm, err := c.api.CreateDM(ctx, user)
+		if err != nil {
+			return "", err
+		}
+		return dm.ID, nil
+	}
+	if raw == "" {
+		return "", fmt.Errorf("discord: invalid target: %q", target)
+	}
+	return raw, nil
+}
@@ -0,0 +1,555 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package slack implements the OpenClaw Slack channel on top of Socket
+// Mode. Direct messages map to one conversation per user, and every
+// channel thread the bot is mentioned in maps to its own conversation.
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/chatkit"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
+	slackapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/slack"
+)
+
+const (
+	channelID = "slack"
+
+	requestIDPrefix = "slack:"
+
+	laneDMPrefix     = channelID + ":dm:"
+	laneThreadPrefix = channelID + ":thread:"
+
+	maxReplyRunes = 4000
+
+	maxActiveThreads = 4096
+
+	defaultPairingTTL = time.Hour
+
+	dmChannelPrefix = "D"
+)
+
+// ChannelName is the stable channel identifier used across OpenClaw.
+const ChannelName = channelID
+
+const (
+	helpMessage = "Send me a message, mention me in a channel, " +
+		"or use a slash command followed by your request."
+
+	failedMessage = "Failed to process message."
+
+	fileTooLargeTemplate = "Attachment %s is larger than %d bytes " +
+		"and was skipped."
+)
+
+var userMentionPattern = regexp.MustCompile(`<@([A-Z0-9]+)(\|[^>]*)?>`)
+
+type gatewayClient interface {
+	SendMessage(
+		ctx context.Context,
+		req gwclient.MessageRequest,
+	) (gwclient.MessageResponse, error)
+}
+
+type webAPI interface {
+	PostMessage(
+		ctx context.Context,
+		params slackapi.PostMessageParams,
+	) (string, error)
+
+	UploadFile(ctx context.Context, params slackapi.UploadFileParams) error
+
+	DownloadFile(
+		ctx context.Context,
+		fileURL string,
+		maxBytes int64,
+	) ([]byte, error)
+}
+
+type eventSource interface {
+	Run(ctx context.Context, handler slackapi.EnvelopeHandler) error
+}
+
+type config struct {
+	name             string
+	stateDir         string
+	dmPolicy         string
+	groupPolicy      string
+	allowUsers       []string
+	allowThreads     []string
+	pairingTTL       time.Duration
+	maxDownloadBytes int64
+}
+
+// Option configures the Slack channel.
+type Option func(*config)
+
+// WithName sets the configured plugin instance name, which keys the
+// pairing store.
+func WithName(name string) Option {
+	return func(c *config) { c.name = name }
+}
+
+// WithStateDir sets the state directory for the pairing store.
+func WithStateDir(dir string) Option {
+	return func(c *config) { c.stateDir = dir }
+}
+
+// WithDMPolicy sets the policy for direct messages.
+func WithDMPolicy(policy string) Option {
+	return func(c *config) { c.dmPolicy = policy }
+}
+
+// WithGroupPolicy sets the policy for channel messages.
+func WithGroupPolicy(policy string) Option {
+	return func(c *config) { c.groupPolicy = policy }
+}
+
+// WithAllowUsers sets a per-channel user allowlist of Slack user IDs.
+func WithAllowUsers(users ...string) Option {
+	return func(c *config) { c.allowUsers = append(c.allowUsers, users...) }
+}
+
+// WithAllowThreads sets an allowlist for channels and threads.
+//
+// Values should match the `thread` field derived by this channel:
+//   - Channel: "<channel_id>"
+//   - Thread: "<channel_id>:<thread_ts>"
+func WithAllowThreads(threads ...string) Option {
+	return func(c *config) {
+		c.allowThreads = append(c.allowThreads, threads...)
+	}
+}
+
+// WithPairingTTL sets how long pairing codes stay valid.
+func WithPairingTTL(ttl time.Duration) Option {
+	return func(c *config) { c.pairingTTL = ttl }
+}
+
+// WithMaxDownloadBytes sets the per-file download limit for attachments.
+func WithMaxDownloadBytes(maxBytes int64) Option {
+	return func(c *config) { c.maxDownloadBytes = maxBytes }
+}
+
+// Channel implements a Slack Socket Mode chat surface.
+type Channel struct {
+	api    webAPI
+	events eventSource
+	gw     gatewayClient
+	botID  string
+
+	access chatkit.Access
+
+	maxDownloadBytes int64
+
+	lanes *chatkit.LaneLocker
+
+	mu            sync.Mutex
+	activeThreads map[string]struct{}
+}
+
+// New creates a Slack channel for the bot identified by auth.
+func New(
+	api *slackapi.Client,
+	socket *slackapi.SocketClient,
+	auth slackapi.AuthInfo,
+	gw gatewayClient,
+	opts ...Option,
+) (*Channel, error) {
+	if api == nil || socket == nil {
+		return nil, errors.New("slack: nil api client")
+	}
+	if gw == nil {
+		return nil, errors.New("slack: nil gateway client")
+	}
+
+	cfg := config{
+		pairingTTL:       defaultPairingTTL,
+		maxDownloadBytes: chatkit.DefaultMaxDownloadBytes,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	dmPolicy, err := chatkit.ParseDMPolicy(cfg.dmPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("slack: %w", err)
+	}
+	groupPolicy, err := chatkit.ParseGroupPolicy(cfg.groupPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("slack: %w", err)
+	}
+	if cfg.pairingTTL <= 0 {
+		return nil, errors.New("slack: non-positive pairing ttl")
+	}
+	if cfg.maxDownloadBytes <= 0 {
+		return nil, errors.New("slack: non-positive max download bytes")
+	}
+
+	hintName := strings.TrimSpace(cfg.name)
+	if hintName == "" {
+		hintName = channelID
+	}
+	access := chatkit.Access{
+		Channel:      hintName,
+		DMPolicy:     dmPolicy,
+		GroupPolicy:  groupPolicy,
+		AllowUsers:   chatkit.StringSet(cfg.allowUsers...),
+		AllowThreads: chatkit.StringSet(cfg.allowThreads...),
+	}
+	if dmPolicy == chatkit.DMPolicyPairing {
+		stateDir, err := chatkit.ResolveStateDir(cfg.stateDir)
+		if err != nil {
+			return nil, err
+		}
+		path, err := PairingStorePath(stateDir, cfg.name)
+		if err != nil {
+			return nil, err
+		}
+		store, err := pairing.NewFileStore(
+			path,
+			pairing.WithTTL(cfg.pairingTTL),
+		)
+		if err != nil {
+			return nil, err
+		}
+		access.Pairing = store
+	}
+
+	return &Channel{
+		api:              api,
+		events:           socket,
+		gw:               gw,
+		botID:            auth.UserID,
+		access:           access,
+		maxDownloadBytes: cfg.maxDownloadBytes,
+		lanes:            chatkit.NewLaneLocker(),
+		activeThreads:    make(map[string]struct{}),
+	}, nil
+}
+
+// PairingStorePath returns the pairing store of a configured Slack
+// channel instance.
+func PairingStorePath(stateDir string, name string) (string, error) {
+	path, err := chatkit.PairingStorePath(stateDir, channelID, name)
+	if err != nil {
+		return "", fmt.Errorf("slack: %w", err)
+	}
+	return path, nil
+}
+
+// ID returns the channel identifier used by the gateway.
+func (c *Channel) ID() string { return channelID }
+
+// Run receives Socket Mode events and blocks until ctx is done.
+func (c *Channel) Run(ctx context.Context) error {
+	if c == nil {
+		return errors.New("slack: nil channel")
+	}
+	return c.events.Run(ctx, c.handleEnvelope)
+}
+
+func (c *Channel) handleEnvelope(ctx context.Context, env slackapi.Envelope) {
+	switch env.Type {
+	case slackapi.EnvelopeTypeEventsAPI:
+		var cb slackapi.EventCallback
+		if err := json.Unmarshal(env.Payload, &cb); err != nil {
+			log.WarnfContext(ctx, "slack: decode event: %v", err)
+			return
+		}
+		go func() {
+			c.logErr(ctx, "handle event", c.handleEvent(ctx, cb.Event))
+		}()
+	case slackapi.EnvelopeTypeSlashCommands:
+		var cmd slackapi.SlashCommand
+		if err := json.Unmarshal(env.Payload, &cmd); err != nil {
+			log.WarnfContext(ctx, "slack: decode command: %v", err)
+			return
+		}
+		go func() {
+			c.logErr(ctx, "handle command", c.handleSlashCommand(ctx, cmd))
+		}()
+	}
+}
+
+func (c *Channel) logErr(ctx context.Context, what string, err error) {
+	if err != nil {
+		log.WarnfContext(ctx, "slack: %s: %v", what, err)
+	}
+}
+
+// inbound is one user message normalized from an event or a command.
+type inbound struct {
+	user      string
+	channel   string
+	isDM      bool
+	thread    string
+	replyTS   string
+	messageID string
+	text      string
+	files     []slackapi.File
+}
+
+func (c *Channel) handleEvent(
+	ctx context.Context,
+	ev slackapi.MessageEvent,
+) error {
+	if ev.BotID != "" || ev.User == "" || ev.User == c.botID {
+		return nil
+	}
+	if ev.Subtype != "" && ev.Subtype != slackapi.MessageSubtypeFileShare {
+		return nil
+	}
+
+	isDM := ev.ChannelType == slackapi.ChannelTypeIM ||
+		(ev.ChannelType == "" && strings.HasPrefix(ev.Channel, dmChannelPrefix))
+	msg := inbound{
+		user:      ev.User,
+		channel:   ev.Channel,
+		isDM:      isDM,
+		messageID: ev.TS,
+		text:      c.stripBotMention(ev.Text),
+		files:     ev.Files,
+	}
+	if isDM {
+		if ev.Type != slackapi.EventTypeMessage {
+			return nil
+		}
+		if ev.ThreadTS != "" {
+			msg.thread = ev.Channel + chatkit.ThreadSep + ev.ThreadTS
+			msg.replyTS = ev.ThreadTS
+		}
+		return c.handleInbound(ctx, msg)
+	}
+
+	msg.replyTS = ev.ThreadTS
+	if msg.replyTS == "" {
+		msg.replyTS = ev.TS
+	}
+	msg.thread = ev.Channel + chatkit.ThreadSep + msg.replyTS
+	switch ev.Type {
+	case slackapi.EventTypeAppMention:
+	case slackapi.EventTypeMessage:
+		// Follow-ups in threads the bot already answers do not need a
+		// mention. Mentions are handled by the app_mention event.
+		if ev.ThreadTS == "" || c.mentionsBot(ev.Text) ||
+			!c.isActiveThread(msg.thread) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return c.handleInbound(ctx, msg)
+}
+
+func (c *Channel) handleSlashCommand(
+	ctx context.Context,
+	cmd slackapi.SlashCommand,
+) error {
+	if cmd.UserID == "" || cmd.ChannelID == "" {
+		return nil
+	}
+	msg := inbound{
+		user:      cmd.UserID,
+		channel:   cmd.ChannelID,
+		isDM:      strings.HasPrefix(cmd.ChannelID, dmChannelPrefix),
+		messageID: cmd.TriggerID,
+		text:      strings.TrimSpace(cmd.Text),
+	}
+	if !msg.isDM {
+		msg.thread = cmd.ChannelID
+	}
+	if msg.text == "" {
+		if ok, _ := c.allowed(ctx, msg); ok {
+			c.post(ctx, msg.channel, "", helpMessage)
+		}
+		return nil
+	}
+	return c.handleInbound(ctx, msg)
+}
+
+// allowed applies the access policies and tells rejected DM users why.
+func (c *Channel) allowed(ctx context.Context, msg inbound) (bool, error) {
+	if !c.access.UserAllowed(msg.user) {
+		if msg.isDM {
+			c.post(ctx, msg.channel, "", chatkit.NotAllowedMessage)
+		}
+		return false, nil
+	}
+	if !msg.isDM {
+		return c.access.ThreadAllowed(msg.thread), nil
+	}
+	ok, notice, err := c.access.CheckDM(ctx, msg.user)
+	if err != nil {
+		return false, fmt.Errorf("slack: %w", err)
+	}
+	if !ok && notice != "" {
+		c.post(ctx, msg.channel, "", notice)
+	}
+	return ok, nil
+}
+
+func (c *Channel) handleInbound(ctx context.Context, msg inbound) error {
+	ok, err := c.allowed(ctx, msg)
+	if err != nil || !ok {
+		return err
+	}
+
+	laneKey := laneDMPrefix + msg.user
+	if msg.thread != "" {
+		laneKey = laneThreadPrefix + msg.thread
+	}
+	requestID := requestIDPrefix + msg.channel + ":" + msg.messageID
+
+	return c.lanes.Do(laneKey, func() error {
+		req, err := c.buildGatewayRequest(ctx, msg, laneKey, requestID)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Text) == "" && len(req.ContentParts) == 0 {
+			return nil
+		}
+		rsp, err := c.gw.SendMessage(ctx, req)
+		if err != nil {
+			if rsp.StatusCode >= http.StatusBadRequest &&
+				rsp.StatusCode < http.StatusInternalServerError {
+				log.WarnfContext(ctx, "slack: gateway rejected: %v", err)
+				return nil
+			}
+			c.post(ctx, msg.channel, msg.replyTS, failedMessage)
+			return err
+		}
+		if rsp.Ignored || strings.TrimSpace(rsp.Reply) == "" {
+			return nil
+		}
+		if !msg.isDM && msg.replyTS != "" {
+			c.markActiveThread(msg.thread)
+		}
+		for _, part := range chatkit.SplitRunes(rsp.Reply, maxReplyRunes) {
+			if _, err := c.api.PostMessage(ctx, slackapi.PostMessageParams{
+				Channel:  msg.channel,
+				Text:     part,
+				ThreadTS: msg.replyTS,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *Channel) buildGatewayRequest(
+	ctx context.Context,
+	msg inbound,
+	sessionID string,
+	requestID string,
+) (gwclient.MessageRequest, error) {
+	req := gwclient.MessageRequest{
+		Channel:   channelID,
+		From:      msg.user,
+		Thread:    msg.thread,
+		MessageID: msg.messageID,
+		Text:      msg.text,
+		UserID:    msg.user,
+		SessionID: sessionID,
+		RequestID: requestID,
+	}
+	var parts []gwproto.ContentPart
+	for _, f := range msg.files {
+		if f.Size > c.maxDownloadBytes {
+			c.post(ctx, msg.channel, msg.replyTS, fmt.Sprintf(
+				fileTooLargeTemplate, f.Name, c.maxDownloadBytes,
+			))
+			continue
+		}
+		data, err := c.api.DownloadFile(ctx, f.DownloadURL(), c.maxDownloadBytes)
+		if errors.Is(err, slackapi.ErrFileTooLarge) {
+			c.post(ctx, msg.channel, msg.replyTS, fmt.Sprintf(
+				fileTooLargeTemplate, f.Name, c.maxDownloadBytes,
+			))
+			continue
+		}
+		if err != nil {
+			return gwclient.MessageRequest{}, err
+		}
+		parts = append(parts, chatkit.AttachmentParts(f.Name, f.Mimetype, data)...)
+	}
+	req.ContentParts = parts
+	return req, nil
+}
+
+func (c *Channel) post(
+	ctx context.Context,
+	channel string,
+	threadTS string,
+	text string,
+) {
+	_, err := c.api.PostMessage(ctx, slackapi.PostMessageParams{
+		Channel:  channel,
+		Text:     text,
+		ThreadTS: threadTS,
+	})
+	if err != nil {
+		log.WarnfContext(ctx, "slack: post message: %v", err)
+	}
+}
+
+func (c *Channel) stripBotMention(text string) string {
+	if c.botID == "" {
+		return strings.TrimSpace(text)
+	}
+	text = userMentionPattern.ReplaceAllStringFunc(text, func(m string) string {
+		sub := userMentionPattern.FindStringSubmatch(m)
+		if len(sub) > 1 && sub[1] == c.botID {
+			return ""
+		}
+		return m
+	})
+	return strings.TrimSpace(text)
+}
+
+func (c *Channel) mentionsBot(text string) bool {
+	for _, sub := range userMentionPattern.FindAllStringSubmatch(text, -1) {
+		if sub[1] == c.botID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Channel) markActiveThread(thread string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.activeThreads) >= maxActiveThreads {
+		c.activeThreads = make(map[string]struct{})
+	}
+	c.activeThreads[thread] = struct{}{}
+}
+
+func (c *Channel) isActiveThread(thread string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.activeThreads[thread]
+	return ok
+}
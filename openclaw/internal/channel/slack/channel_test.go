@@ -0,0 +1,320 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwproto"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/pairing"
+	slackapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/slack"
+)
+
+const testBotID = "UBOT"
+
+type stubGateway struct {
+	mu    sync.Mutex
+	reqs  []gwclient.MessageRequest
+	reply string
+}
+
+func (g *stubGateway) SendMessage(
+	_ context.Context,
+	req gwclient.MessageRequest,
+) (gwclient.MessageResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reqs = append(g.reqs, req)
+	return gwclient.MessageResponse{Reply: g.reply}, nil
+}
+
+type stubAPI struct {
+	posts   []slackapi.PostMessageParams
+	uploads []slackapi.UploadFileParams
+	files   map[string][]byte
+}
+
+func (a *stubAPI) PostMessage(
+	_ context.Context,
+	params slackapi.PostMessageParams,
+) (string, error) {
+	a.posts = append(a.posts, params)
+	return "9.9", nil
+}
+
+func (a *stubAPI) UploadFile(
+	_ context.Context,
+	params slackapi.UploadFileParams,
+) error {
+	a.uploads = append(a.uploads, params)
+	return nil
+}
+
+func (a *stubAPI) DownloadFile(
+	_ context.Context,
+	fileURL string,
+	maxBytes int64,
+) ([]byte, error) {
+	data := a.files[fileURL]
+	if int64(len(data)) > maxBytes {
+		return nil, slackapi.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func newTestChannel(
+	t *testing.T,
+	opts ...Option,
+) (*Channel, *stubAPI, *stubGateway) {
+	t.Helper()
+	client, err := slackapi.New("xoxb-test")
+	require.NoError(t, err)
+	socket := slackapi.NewSocketClient(client)
+	gw := &stubGateway{reply: "pong"}
+	opts = append([]Option{WithStateDir(t.TempDir())}, opts...)
+	c, err := New(
+		client,
+		socket,
+		slackapi.AuthInfo{UserID: testBotID},
+		gw,
+		opts...,
+	)
+	require.NoError(t, err)
+	api := &stubAPI{files: map[string][]byte{}}
+	c.api = api
+	return c, api, gw
+}
+
+func TestNew_Validation(t *testing.T) {
+	client, err := slackapi.New("xoxb-test")
+	require.NoError(t, err)
+	socket := slackapi.NewSocketClient(client)
+	auth := slackapi.AuthInfo{UserID: testBotID}
+
+	_, err = New(nil, socket, auth, &stubGateway{})
+	require.Error(t, err)
+	_, err = New(client, socket, auth, nil)
+	require.Error(t, err)
+	_, err = New(client, socket, auth, &stubGateway{},
+		WithDMPolicy("nope"))
+	require.Error(t, err)
+	_, err = New(client, socket, auth, &stubGateway{},
+		WithDMPolicy("open"), WithMaxDownloadBytes(0))
+	require.Error(t, err)
+}
+
+func TestChannel_DMPairing(t *testing.T) {
+	ctx := context.Background()
+	stateDir := t.TempDir()
+	c, api, gw := newTestChannel(t,
+		WithStateDir(stateDir), WithName("work"))
+
+	ev := slackapi.MessageEvent{
+		Type:        slackapi.EventTypeMessage,
+		User:        "U1",
+		Channel:     "D1",
+		ChannelType: slackapi.ChannelTypeIM,
+		Text:        "hi",
+		TS:          "1.0",
+	}
+	require.NoError(t, c.handleEvent(ctx, ev))
+	require.Empty(t, gw.reqs)
+	require.Len(t, api.posts, 1)
+	require.Contains(t, api.posts[0].Text, "-channel work")
+
+	path, err := PairingStorePath(stateDir, "work")
+	require.NoError(t, err)
+	store, err := pairing.NewFileStore(path)
+	require.NoError(t, err)
+	pending, err := store.ListPending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	_, _, err = store.Approve(ctx, pending[0].Code)
+	require.NoError(t, err)
+
+	ev.TS = "2.0"
+	require.NoError(t, c.handleEvent(ctx, ev))
+	require.Len(t, gw.reqs, 1)
+	req := gw.reqs[0]
+	require.Equal(t, "slack:dm:U1", req.SessionID)
+	require.Equal(t, "slack:D1:2.0", req.RequestID)
+	require.Empty(t, req.Thread)
+	require.Equal(t, "hi", req.Text)
+	require.Equal(t, slackapi.PostMessageParams{
+		Channel: "D1",
+		Text:    "pong",
+	}, api.posts[len(api.posts)-1])
+}
+
+func TestChannel_ThreadFollowUps(t *testing.T) {
+	ctx := context.Background()
+	c, api, gw := newTestChannel(t, WithGroupPolicy("open"))
+
+	// Plain channel messages are ignored until the bot is mentioned.
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, User: "U1", Channel: "C1",
+		ChannelType: "channel", Text: "hello", TS: "1.0",
+	}))
+	require.Empty(t, gw.reqs)
+
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeAppMention, User: "U1", Channel: "C1",
+		Text: "<@UBOT> hello <@U2>", TS: "1.0",
+	}))
+	require.Len(t, gw.reqs, 1)
+	require.Equal(t, "C1:1.0", gw.reqs[0].Thread)
+	require.Equal(t, "slack:thread:C1:1.0", gw.reqs[0].SessionID)
+	require.Equal(t, "hello <@U2>", gw.reqs[0].Text)
+	require.Equal(t, "1.0", api.posts[0].ThreadTS)
+
+	// Follow-ups in the active thread need no mention.
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, User: "U2", Channel: "C1",
+		ChannelType: "channel", Text: "more", TS: "1.5", ThreadTS: "1.0",
+	}))
+	require.Len(t, gw.reqs, 2)
+	require.Equal(t, "slack:thread:C1:1.0", gw.reqs[1].SessionID)
+
+	// A mention in the thread arrives twice; only app_mention handles it.
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, User: "U2", Channel: "C1",
+		ChannelType: "channel", Text: "<@UBOT> again", TS: "1.6",
+		ThreadTS: "1.0",
+	}))
+	// Bot messages and edits are ignored.
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, BotID: "B1", Channel: "C1",
+		Text: "echo", TS: "1.7", ThreadTS: "1.0",
+	}))
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, Subtype: "message_changed",
+		User: "U2", Channel: "C1", Text: "x", TS: "1.8", ThreadTS: "1.0",
+	}))
+	require.Len(t, gw.reqs, 2)
+}
+
+func TestChannel_GroupAllowlist(t *testing.T) {
+	ctx := context.Background()
+	c, _, gw := newTestChannel(t,
+		WithGroupPolicy("allowlist"), WithAllowThreads("C2"))
+
+	for _, ch := range []string{"C1", "C2"} {
+		require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+			Type: slackapi.EventTypeAppMention, User: "U1", Channel: ch,
+			Text: "<@UBOT> hi", TS: "1.0",
+		}))
+	}
+	require.Len(t, gw.reqs, 1)
+	require.Equal(t, "C2:1.0", gw.reqs[0].Thread)
+}
+
+func TestChannel_FileShare(t *testing.T) {
+	ctx := context.Background()
+	c, api, gw := newTestChannel(t,
+		WithDMPolicy("open"), WithMaxDownloadBytes(8))
+	api.files["https://files/a.png"] = []byte("png")
+	api.files["https://files/big.txt"] = []byte("0123456789")
+
+	require.NoError(t, c.handleEvent(ctx, slackapi.MessageEvent{
+		Type: slackapi.EventTypeMessage, Subtype: slackapi.MessageSubtypeFileShare,
+		User: "U1", Channel: "D1", ChannelType: slackapi.ChannelTypeIM,
+		TS: "1.0",
+		Files: []slackapi.File{
+			{Name: "a.png", Mimetype: "image/png", Size: 3,
+				URLPrivateDownload: "https://files/a.png"},
+			{Name: "big.txt", Size: 2,
+				URLPrivateDownload: "https://files/big.txt"},
+		},
+	}))
+	require.Len(t, gw.reqs, 1)
+	parts := gw.reqs[0].ContentParts
+	require.Len(t, parts, 2)
+	require.Equal(t, gwproto.PartTypeImage, parts[0].Type)
+	require.Equal(t, "a.png", parts[1].File.Filename)
+	require.Contains(t, api.posts[0].Text, "big.txt")
+}
+
+func TestChannel_SlashCommand(t *testing.T) {
+	ctx := context.Background()
+	c, api, gw := newTestChannel(t,
+		WithDMPolicy("open"), WithGroupPolicy("open"))
+
+	require.NoError(t, c.handleSlashCommand(ctx, slackapi.SlashCommand{
+		UserID: "U1", ChannelID: "C1", TriggerID: "T1",
+	}))
+	require.Empty(t, gw.reqs)
+	require.Equal(t, helpMessage, api.posts[0].Text)
+
+	require.NoError(t, c.handleSlashCommand(ctx, slackapi.SlashCommand{
+		UserID: "U1", ChannelID: "C1", TriggerID: "T2", Text: " status ",
+	}))
+	require.Len(t, gw.reqs, 1)
+	require.Equal(t, "C1", gw.reqs[0].Thread)
+	require.Equal(t, "status", gw.reqs[0].Text)
+	require.Equal(t, slackapi.PostMessageParams{
+		Channel: "C1",
+		Text:    "pong",
+	}, api.posts[1])
+}
+
+func TestResolveTextTargetFromSessionID(t *testing.T) {
+	target, ok := ResolveTextTargetFromSessionID("slack:dm:U1")
+	require.True(t, ok)
+	require.Equal(t, "U1", target)
+	target, ok = ResolveTextTargetFromSessionID("slack:thread:C1:1.0")
+	require.True(t, ok)
+	require.Equal(t, "C1:1.0", target)
+	_, ok = ResolveTextTargetFromSessionID("telegram:dm:1")
+	require.False(t, ok)
+	_, ok = ResolveTextTargetFromSessionID("slack:dm:")
+	require.False(t, ok)
+}
+
+func TestChannel_SendMessage(t *testing.T) {
+	ctx := context.Background()
+	c, api, _ := newTestChannel(t, WithDMPolicy("open"))
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	require.NoError(t, os.WriteFile(a, []byte("A"), 0o600))
+	require.NoError(t, os.WriteFile(b, []byte("B"), 0o600))
+
+	require.NoError(t, c.SendMessage(ctx, "C1:1.0", channel.OutboundMessage{
+		Text:  "report",
+		Files: []channel.OutboundFile{{Path: a}},
+	}))
+	require.Empty(t, api.posts)
+	require.Equal(t, slackapi.UploadFileParams{
+		Channel:        "C1",
+		ThreadTS:       "1.0",
+		Filename:       "a.txt",
+		InitialComment: "report",
+		Data:           []byte("A"),
+	}, api.uploads[0])
+
+	require.NoError(t, c.SendMessage(ctx, "C1", channel.OutboundMessage{
+		Text:  "both",
+		Files: []channel.OutboundFile{{Path: a}, {Path: b}},
+	}))
+	require.Equal(t, "both", api.posts[0].Text)
+	require.Len(t, api.uploads, 3)
+	require.Empty(t, api.uploads[2].InitialComment)
+
+	require.Error(t, c.SendText(ctx, " ", "x"))
+}
@@ -0,0 +1,130 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/chatkit"
+	slackapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/slack"
+)
+
+// ResolveTextTargetFromSessionID converts a Slack session id into the
+// outbound target used by SendText.
+//
+// DM sessions ("slack:dm:<user>") resolve to the user ID, which Slack
+// accepts as a channel for the bot DM. Thread sessions resolve to their
+// "<channel>" or "<channel>:<thread_ts>" thread key.
+func ResolveTextTargetFromSessionID(sessionID string) (string, bool) {
+	raw := strings.TrimSpace(sessionID)
+	var target string
+	switch {
+	case strings.HasPrefix(raw, laneDMPrefix):
+		target = strings.TrimPrefix(raw, laneDMPrefix)
+	case strings.HasPrefix(raw, laneThreadPrefix):
+		target = strings.TrimPrefix(raw, laneThreadPrefix)
+	default:
+		return "", false
+	}
+	if _, _, err := parseTarget(target); err != nil {
+		return "", false
+	}
+	return target, true
+}
+
+// SendText implements channel.TextSender for Slack.
+//
+// Target is "<channel>" or "<channel>:<thread_ts>"; a user ID sends a DM.
+func (c *Channel) SendText(
+	ctx context.Context,
+	target string,
+	text string,
+) error {
+	if c == nil || c.api == nil {
+		return errors.New("slack: sender unavailable")
+	}
+	channelID, threadTS, err := parseTarget(target)
+	if err != nil {
+		return err
+	}
+	for _, part := range chatkit.SplitRunes(text, maxReplyRunes) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		if _, err := c.api.PostMessage(ctx, slackapi.PostMessageParams{
+			Channel:  channelID,
+			Text:     part,
+			ThreadTS: threadTS,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendMessage implements channel.MessageSender for Slack. A single file
+// is uploaded with the text as its comment; otherwise the text is posted
+// first and each file is uploaded after it.
+func (c *Channel) SendMessage(
+	ctx context.Context,
+	target string,
+	msg channel.OutboundMessage,
+) error {
+	if c == nil || c.api == nil {
+		return errors.New("slack: sender unavailable")
+	}
+	channelID, threadTS, err := parseTarget(target)
+	if err != nil {
+		return err
+	}
+	files := make([]chatkit.OutboundFile, 0, len(msg.Files))
+	for _, f := range msg.Files {
+		loaded, err := chatkit.LoadOutboundFile(ctx, f)
+		if err != nil {
+			return fmt.Errorf("slack: %w", err)
+		}
+		files = append(files, loaded)
+	}
+
+	comment := ""
+	if len(files) == 1 && len([]rune(msg.Text)) <= maxReplyRunes {
+		comment = msg.Text
+	} else if strings.TrimSpace(msg.Text) != "" {
+		if err := c.SendText(ctx, target, msg.Text); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		if err := c.api.UploadFile(ctx, slackapi.UploadFileParams{
+			Channel:        channelID,
+			ThreadTS:       threadTS,
+			Filename:       f.Name,
+			InitialComment: comment,
+			Data:           f.Data,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseTarget(target string) (string, string, error) {
+	raw := strings.TrimSpace(target)
+	channelID, threadTS, _ := strings.Cut(raw, chatkit.ThreadSep)
+	if channelID == "" {
+		return "", "", fmt.Errorf("slack: invalid target: %q", target)
+	}
+	return channelID, threadTS, nil
+}
@@ -0,0 +1,352 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package discord implements the small subset of the Discord REST API and
+// gateway protocol used by the OpenClaw Discord channel.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://discord.com/api/v10"
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	maxRetryAfter      = 10 * time.Second
+)
+
+const (
+	headerAuthorization = "Authorization"
+	headerContentType   = "Content-Type"
+	contentTypeJSON     = "application/json"
+
+	authPrefixBot = "Bot "
+)
+
+const maxErrorBodyBytes int64 = 4 << 10
+
+// ErrFileTooLarge is returned when a downloaded file exceeds the configured
+// maximum size.
+var ErrFileTooLarge = errors.New("discord: file too large")
+
+// Client talks to the Discord REST API.
+type Client struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the REST API base URL, mainly for tests.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API calls and downloads.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) { c.httpClient = client }
+}
+
+// New creates a REST client authenticated with a bot token.
+func New(token string, opts ...Option) (*Client, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New("discord: empty bot token")
+	}
+	c := &Client{
+		token:      token,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return c, nil
+}
+
+// Token returns the bot token, used to identify on the gateway.
+func (c *Client) Token() string { return c.token }
+
+// Me returns the bot user.
+func (c *Client) Me(ctx context.Context) (User, error) {
+	var out User
+	err := c.doJSON(ctx, http.MethodGet, "/users/@me", nil, &out)
+	return out, err
+}
+
+// GatewayURL returns the websocket URL to connect the gateway to.
+func (c *Client) GatewayURL(ctx context.Context) (string, error) {
+	var out struct {
+		URL string `json:"url"`
+	}
+	if err := c.doJSON(
+		ctx,
+		http.MethodGet,
+		"/gateway/bot",
+		nil,
+		&out,
+	); err != nil {
+		return "", err
+	}
+	if out.URL == "" {
+		return "", errors.New("discord: empty gateway url")
+	}
+	return out.URL, nil
+}
+
+// CreateDM opens (or returns the existing) DM channel with a user.
+func (c *Client) CreateDM(
+	ctx context.Context,
+	userID string,
+) (Channel, error) {
+	var out Channel
+	err := c.doJSON(
+		ctx,
+		http.MethodPost,
+		"/users/@me/channels",
+		map[string]string{"recipient_id": userID},
+		&out,
+	)
+	return out, err
+}
+
+type messageReference struct {
+	MessageID       string `json:"message_id"`
+	FailIfNotExists bool   `json:"fail_if_not_exists"`
+}
+
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+type attachmentRef struct {
+	ID       int    `json:"id"`
+	Filename string `json:"filename"`
+}
+
+type createMessageBody struct {
+	Content          string            `json:"content,omitempty"`
+	MessageReference *messageReference `json:"message_reference,omitempty"`
+	AllowedMentions  allowedMentions   `json:"allowed_mentions"`
+	Attachments      []attachmentRef   `json:"attachments,omitempty"`
+}
+
+// CreateMessage posts a message, uploading files as multipart attachments
+// when present. Replies never ping anyone.
+func (c *Client) CreateMessage(
+	ctx context.Context,
+	channelID string,
+	params CreateMessageParams,
+) (Message, error) {
+	if strings.TrimSpace(channelID) == "" {
+		return Message{}, errors.New("discord: empty channel id")
+	}
+	body := createMessageBody{
+		Content:         params.Content,
+		AllowedMentions: allowedMentions{Parse: []string{}},
+	}
+	if params.ReplyTo != "" {
+		body.MessageReference = &messageReference{
+			MessageID: params.ReplyTo,
+		}
+	}
+	path := "/channels/" + channelID + "/messages"
+
+	var out Message
+	if len(params.Files) == 0 {
+		err := c.doJSON(ctx, http.MethodPost, path, body, &out)
+		return out, err
+	}
+
+	for i, f := range params.Files {
+		body.Attachments = append(
+			body.Attachments,
+			attachmentRef{ID: i, Filename: f.Name},
+		)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return Message{}, fmt.Errorf("discord: marshal message: %w", err)
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("payload_json", string(payload)); err != nil {
+		return Message{}, err
+	}
+	for i, f := range params.Files {
+		part, err := w.CreateFormFile(fmt.Sprintf("files[%d]", i), f.Name)
+		if err != nil {
+			return Message{}, err
+		}
+		if _, err := part.Write(f.Data); err != nil {
+			return Message{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return Message{}, err
+	}
+	err = c.do(
+		ctx,
+		http.MethodPost,
+		path,
+		w.FormDataContentType(),
+		buf.Bytes(),
+		&out,
+	)
+	return out, err
+}
+
+// DownloadAttachment fetches an attachment URL, failing with
+// ErrFileTooLarge once more than maxBytes are read.
+func (c *Client) DownloadAttachment(
+	ctx context.Context,
+	fileURL string,
+	maxBytes int64,
+) ([]byte, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("discord: non-positive max bytes")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fileURL,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("discord: new download request: %w", err)
+	}
+	rsp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discord: download attachment: %w", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, statusError(rsp)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("discord: read attachment: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func (c *Client) doJSON(
+	ctx context.Context,
+	method string,
+	path string,
+	in any,
+	out any,
+) error {
+	var body []byte
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("discord: marshal request: %w", err)
+		}
+		body = raw
+	}
+	return c.do(ctx, method, path, contentTypeJSON, body, out)
+}
+
+// do sends one request, retrying once when Discord rate limits it.
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	contentType string,
+	body []byte,
+	out any,
+) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(
+			ctx,
+			method,
+			c.baseURL+path,
+			bytes.NewReader(body),
+		)
+		if err != nil {
+			return fmt.Errorf("discord: new request: %w", err)
+		}
+		req.Header.Set(headerAuthorization, authPrefixBot+c.token)
+		if body != nil {
+			req.Header.Set(headerContentType, contentType)
+		}
+
+		rsp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("discord: %s %s: %w", method, path, err)
+		}
+		if rsp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			wait := retryAfter(rsp)
+			rsp.Body.Close()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		defer rsp.Body.Close()
+		if rsp.StatusCode < http.StatusOK ||
+			rsp.StatusCode >= http.StatusMultipleChoices {
+			return statusError(rsp)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(rsp.Body).Decode(out); err != nil {
+			return fmt.Errorf("discord: decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+func retryAfter(rsp *http.Response) time.Duration {
+	var payload struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	_ = json.NewDecoder(
+		io.LimitReader(rsp.Body, maxErrorBodyBytes),
+	).Decode(&payload)
+	wait := time.Duration(payload.RetryAfter * float64(time.Second))
+	return min(max(wait, 0), maxRetryAfter)
+}
+
+func statusError(rsp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(rsp.Body, maxErrorBodyBytes))
+	return fmt.Errorf(
+		"discord: status %d: %s",
+		rsp.StatusCode,
+		strings.TrimSpace(string(body)),
+	)
+}
@@ -0,0 +1,247 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MeAndCreateMessage(t *testing.T) {
+	var (
+		gotAuth string
+		gotBody map[string]any
+		calls   int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		gotAuth = r.Header.Get(headerAuthorization)
+		switch r.URL.Path {
+		case "/users/@me":
+			_, _ = io.WriteString(w, `{"id":"B1","username":"bot","bot":true}`)
+		case "/channels/C1/messages":
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = io.WriteString(w, `{"retry_after":0.001}`)
+				return
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+			_, _ = io.WriteString(w, `{"id":"M2","channel_id":"C1"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New("tok", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	me, err := c.Me(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, User{ID: "B1", Username: "bot", Bot: true}, me)
+	require.Equal(t, "Bot tok", gotAuth)
+
+	msg, err := c.CreateMessage(context.Background(), "C1", CreateMessageParams{
+		Content: "hi",
+		ReplyTo: "M1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "M2", msg.ID)
+	require.Equal(t, 2, calls, "rate limited request is retried")
+	require.Equal(t, "hi", gotBody["content"])
+	require.Equal(t, "M1", gotBody["message_reference"].(map[string]any)["message_id"])
+	require.Empty(t, gotBody["allowed_mentions"].(map[string]any)["parse"])
+}
+
+func TestClient_CreateMessageWithFiles(t *testing.T) {
+	var (
+		payload string
+		file    string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		payload = r.FormValue("payload_json")
+		f, hdr, err := r.FormFile("files[0]")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(f)
+		file = hdr.Filename + "=" + string(data)
+		_, _ = io.WriteString(w, `{"id":"M3"}`)
+	}))
+	defer srv.Close()
+
+	c, err := New("tok", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	_, err = c.CreateMessage(context.Background(), "C1", CreateMessageParams{
+		Content: "see",
+		Files:   []UploadFile{{Name: "a.txt", Data: []byte("abc")}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "a.txt=abc", file)
+	require.JSONEq(t, `{"content":"see","allowed_mentions":{"parse":[]},`+
+		`"attachments":[{"id":0,"filename":"a.txt"}]}`, payload)
+}
+
+func TestClient_DownloadAttachment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		_ *http.Request,
+	) {
+		_, _ = io.WriteString(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	c, err := New("tok")
+	require.NoError(t, err)
+	data, err := c.DownloadAttachment(context.Background(), srv.URL, 10)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789", string(data))
+	_, err = c.DownloadAttachment(context.Background(), srv.URL, 3)
+	require.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+// fakeGateway serves a scripted gateway session per connection and
+// records the identify/resume payloads it receives.
+type fakeGateway struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	received []gatewayPayload
+	conns    int
+}
+
+func newFakeGateway(t *testing.T) *fakeGateway {
+	g := &fakeGateway{t: t}
+	upgrader := websocket.Upgrader{}
+	g.srv = httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		if r.URL.Path == "/gateway/bot" {
+			_, _ = io.WriteString(w, `{"url":"`+g.wsURL()+`"}`)
+			return
+		}
+		require.Equal(t, "10", r.URL.Query().Get("v"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		g.serve(conn)
+	}))
+	return g
+}
+
+func (g *fakeGateway) wsURL() string {
+	return "ws" + strings.TrimPrefix(g.srv.URL, "http") + "/ws"
+}
+
+func (g *fakeGateway) serve(conn *websocket.Conn) {
+	g.mu.Lock()
+	g.conns++
+	n := g.conns
+	g.mu.Unlock()
+
+	send := func(op int, t string, seq int64, d string) {
+		p := gatewayPayload{Op: op, T: t, D: json.RawMessage(d)}
+		if seq != 0 {
+			p.S = &seq
+		}
+		_ = conn.WriteJSON(p)
+	}
+	send(OpHello, "", 0, `{"heartbeat_interval":20}`)
+
+	var first gatewayPayload
+	if err := conn.ReadJSON(&first); err != nil {
+		return
+	}
+	g.mu.Lock()
+	g.received = append(g.received, first)
+	g.mu.Unlock()
+
+	if n == 1 {
+		send(OpDispatch, EventReady, 1, `{"session_id":"S1",`+
+			`"resume_gateway_url":"`+g.wsURL()+`","user":{"id":"B1"}}`)
+		send(OpDispatch, EventMessageCreate, 2,
+			`{"id":"M1","channel_id":"C1","author":{"id":"U1"},"content":"one"}`)
+		send(OpReconnect, "", 0, `null`)
+	} else {
+		send(OpDispatch, EventResumed, 3, `{}`)
+		send(OpDispatch, EventMessageCreate, 4,
+			`{"id":"M2","channel_id":"C1","author":{"id":"U1"},"content":"two"}`)
+	}
+	for {
+		var p gatewayPayload
+		if err := conn.ReadJSON(&p); err != nil {
+			return
+		}
+		if p.Op == OpHeartbeat {
+			send(OpHeartbeatAck, "", 0, `null`)
+		}
+	}
+}
+
+func TestGateway_IdentifyDispatchAndResume(t *testing.T) {
+	fake := newFakeGateway(t)
+	defer fake.srv.Close()
+
+	api, err := New("tok", WithBaseURL(fake.srv.URL))
+	require.NoError(t, err)
+	gw := NewGateway(api, WithGatewayReconnectBackoff(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	msgs := make(chan Message, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- gw.Run(ctx, func(_ context.Context, msg Message) {
+			msgs <- msg
+		})
+	}()
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case m := <-msgs:
+			got = append(got, m.Content)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no message received")
+		}
+	}
+	require.Equal(t, []string{"one", "two"}, got)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	require.Len(t, fake.received, 2)
+	require.Equal(t, OpIdentify, fake.received[0].Op)
+	var ident identifyData
+	require.NoError(t, json.Unmarshal(fake.received[0].D, &ident))
+	require.Equal(t, "tok", ident.Token)
+	require.Equal(t, DefaultIntents, ident.Intents)
+
+	require.Equal(t, OpResume, fake.received[1].Op)
+	var resume resumeData
+	require.NoError(t, json.Unmarshal(fake.received[1].D, &resume))
+	require.Equal(t, resumeData{Token: "tok", SessionID: "S1", Seq: 2}, resume)
+}
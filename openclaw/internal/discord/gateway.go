@@ -0,0 +1,340 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	gatewayVersion  = "10"
+	gatewayEncoding = "json"
+
+	identifyName = "openclaw"
+
+	defaultReconnectBackoff = time.Second
+)
+
+var errZombieConnection = errors.New("discord: heartbeat not acknowledged")
+
+// MessageHandler receives MESSAGE_CREATE events.
+type MessageHandler func(ctx context.Context, msg Message)
+
+type gatewayLocator interface {
+	GatewayURL(ctx context.Context) (string, error)
+}
+
+// Gateway keeps a websocket session with the Discord gateway, resuming it
+// after disconnects when Discord allows.
+type Gateway struct {
+	api     gatewayLocator
+	token   string
+	intents int
+	dialer  *websocket.Dialer
+	backoff time.Duration
+	onError func(error)
+
+	sessionID string
+	resumeURL string
+
+	mu  sync.Mutex
+	seq int64
+}
+
+// GatewayOption configures a Gateway.
+type GatewayOption func(*Gateway)
+
+// WithIntents overrides the gateway intents.
+func WithIntents(intents int) GatewayOption {
+	return func(g *Gateway) { g.intents = intents }
+}
+
+// WithGatewayReconnectBackoff sets the delay before reconnecting after an
+// error.
+func WithGatewayReconnectBackoff(backoff time.Duration) GatewayOption {
+	return func(g *Gateway) { g.backoff = backoff }
+}
+
+// WithGatewayErrorHandler receives connection errors that trigger a
+// reconnect.
+func WithGatewayErrorHandler(fn func(error)) GatewayOption {
+	return func(g *Gateway) { g.onError = fn }
+}
+
+// NewGateway creates a gateway client for the bot behind api.
+func NewGateway(api *Client, opts ...GatewayOption) *Gateway {
+	g := &Gateway{
+		api:     api,
+		token:   api.Token(),
+		intents: DefaultIntents,
+		dialer:  websocket.DefaultDialer,
+		backoff: defaultReconnectBackoff,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Run connects and dispatches messages to handler until ctx is done.
+func (g *Gateway) Run(ctx context.Context, handler MessageHandler) error {
+	if g == nil || g.api == nil {
+		return errors.New("discord: nil gateway")
+	}
+	if handler == nil {
+		return errors.New("discord: nil message handler")
+	}
+	for {
+		err := g.runOnce(ctx, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			continue
+		}
+		if g.onError != nil {
+			g.onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(g.backoff):
+		}
+	}
+}
+
+func (g *Gateway) connectURL(ctx context.Context) (string, error) {
+	base := g.resumeURL
+	if g.sessionID == "" || base == "" {
+		u, err := g.api.GatewayURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		base = u
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("discord: parse gateway url: %w", err)
+	}
+	q := u.Query()
+	q.Set("v", gatewayVersion)
+	q.Set("encoding", gatewayEncoding)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// runOnce serves one websocket connection. A nil error asks Run to
+// reconnect immediately.
+func (g *Gateway) runOnce(ctx context.Context, handler MessageHandler) error {
+	wsURL, err := g.connectURL(ctx)
+	if err != nil {
+		return err
+	}
+	conn, _, err := g.dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("discord: dial gateway: %w", err)
+	}
+	defer conn.Close()
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-connCtx.Done()
+		_ = conn.Close()
+	}()
+
+	var hello gatewayPayload
+	if err := conn.ReadJSON(&hello); err != nil {
+		return fmt.Errorf("discord: read hello: %w", err)
+	}
+	if hello.Op != OpHello {
+		return fmt.Errorf("discord: expected hello, got op %d", hello.Op)
+	}
+	var hd helloData
+	if err := json.Unmarshal(hello.D, &hd); err != nil {
+		return fmt.Errorf("discord: decode hello: %w", err)
+	}
+
+	w := &gatewayWriter{conn: conn}
+	if err := g.identifyOrResume(w); err != nil {
+		return err
+	}
+
+	acked := make(chan struct{}, 1)
+	heartbeatErr := make(chan error, 1)
+	go func() {
+		heartbeatErr <- g.heartbeat(connCtx, w, hd.HeartbeatInterval, acked)
+		cancel()
+	}()
+
+	for {
+		var p gatewayPayload
+		if err := conn.ReadJSON(&p); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			select {
+			case hbErr := <-heartbeatErr:
+				if hbErr != nil {
+					return hbErr
+				}
+			default:
+			}
+			return fmt.Errorf("discord: read gateway: %w", err)
+		}
+		if p.S != nil {
+			g.setSeq(*p.S)
+		}
+		switch p.Op {
+		case OpDispatch:
+			g.dispatch(ctx, p, handler)
+		case OpHeartbeat:
+			if err := w.write(OpHeartbeat, g.heartbeatData()); err != nil {
+				return err
+			}
+		case OpHeartbeatAck:
+			select {
+			case acked <- struct{}{}:
+			default:
+			}
+		case OpReconnect:
+			return nil
+		case OpInvalidSession:
+			var resumable bool
+			_ = json.Unmarshal(p.D, &resumable)
+			if !resumable {
+				g.resetSession()
+			}
+			return errors.New("discord: invalid session")
+		}
+	}
+}
+
+func (g *Gateway) identifyOrResume(w *gatewayWriter) error {
+	if g.sessionID != "" {
+		return w.write(OpResume, resumeData{
+			Token:     g.token,
+			SessionID: g.sessionID,
+			Seq:       g.currentSeq(),
+		})
+	}
+	return w.write(OpIdentify, identifyData{
+		Token:   g.token,
+		Intents: g.intents,
+		Properties: identifyProperties{
+			OS:      "linux",
+			Browser: identifyName,
+			Device:  identifyName,
+		},
+	})
+}
+
+func (g *Gateway) heartbeat(
+	ctx context.Context,
+	w *gatewayWriter,
+	intervalMs int64,
+	acked <-chan struct{},
+) error {
+	if intervalMs <= 0 {
+		return errors.New("discord: invalid heartbeat interval")
+	}
+	ticker := time.NewTicker(time.Duration(intervalMs) * time.Millisecond)
+	defer ticker.Stop()
+	waiting := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-acked:
+			waiting = false
+		case <-ticker.C:
+			if waiting {
+				return errZombieConnection
+			}
+			if err := w.write(OpHeartbeat, g.heartbeatData()); err != nil {
+				return err
+			}
+			waiting = true
+		}
+	}
+}
+
+func (g *Gateway) dispatch(
+	ctx context.Context,
+	p gatewayPayload,
+	handler MessageHandler,
+) {
+	switch p.T {
+	case EventReady:
+		var ready readyData
+		if err := json.Unmarshal(p.D, &ready); err == nil {
+			g.sessionID = ready.SessionID
+			g.resumeURL = ready.ResumeGatewayURL
+		}
+	case EventMessageCreate:
+		var msg Message
+		if err := json.Unmarshal(p.D, &msg); err == nil {
+			handler(ctx, msg)
+		}
+	}
+}
+
+func (g *Gateway) setSeq(seq int64) {
+	g.mu.Lock()
+	g.seq = seq
+	g.mu.Unlock()
+}
+
+func (g *Gateway) currentSeq() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.seq
+}
+
+// heartbeatData is the last sequence number, or null before any dispatch.
+func (g *Gateway) heartbeatData() any {
+	if seq := g.currentSeq(); seq != 0 {
+		return seq
+	}
+	return nil
+}
+
+func (g *Gateway) resetSession() {
+	g.sessionID = ""
+	g.resumeURL = ""
+	g.setSeq(0)
+}
+
+type gatewayWriter struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (w *gatewayWriter) write(op int, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.conn.WriteJSON(gatewayPayload{Op: op, D: raw}); err != nil {
+		return fmt.Errorf("discord: write gateway: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import "encoding/json"
+
+// Gateway opcodes.
+const (
+	OpDispatch       = 0
+	OpHeartbeat      = 1
+	OpIdentify       = 2
+	OpResume         = 6
+	OpReconnect      = 7
+	OpInvalidSession = 9
+	OpHello          = 10
+	OpHeartbeatAck   = 11
+)
+
+// Gateway dispatch event names.
+const (
+	EventReady         = "READY"
+	EventResumed       = "RESUMED"
+	EventMessageCreate = "MESSAGE_CREATE"
+)
+
+// Gateway intents.
+const (
+	IntentGuilds         = 1 << 0
+	IntentGuildMessages  = 1 << 9
+	IntentDirectMessages = 1 << 12
+	IntentMessageContent = 1 << 15
+
+	// DefaultIntents subscribes to guild and direct messages including
+	// their content.
+	DefaultIntents = IntentGuilds |
+		IntentGuildMessages |
+		IntentDirectMessages |
+		IntentMessageContent
+)
+
+// User is a Discord user.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Bot      bool   `json:"bot,omitempty"`
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+}
+
+// MessageReference points at the message being replied to.
+type MessageReference struct {
+	MessageID string `json:"message_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+}
+
+// Message is a Discord message as delivered by MESSAGE_CREATE.
+type Message struct {
+	ID               string            `json:"id"`
+	ChannelID        string            `json:"channel_id"`
+	GuildID          string            `json:"guild_id,omitempty"`
+	Author           User              `json:"author"`
+	Content          string            `json:"content"`
+	Mentions         []User            `json:"mentions,omitempty"`
+	Attachments      []Attachment      `json:"attachments,omitempty"`
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+}
+
+// Channel is the subset of a Discord channel object the client needs.
+type Channel struct {
+	ID   string `json:"id"`
+	Type int    `json:"type"`
+}
+
+// UploadFile is one file attached to an outgoing message.
+type UploadFile struct {
+	Name string
+	Data []byte
+}
+
+// CreateMessageParams describes an outgoing message.
+type CreateMessageParams struct {
+	Content string
+	// ReplyTo references a message in the same channel.
+	ReplyTo string
+	Files   []UploadFile
+}
+
+type gatewayPayload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d,omitempty"`
+	S  *int64          `json:"s,omitempty"`
+	T  string          `json:"t,omitempty"`
+}
+
+type helloData struct {
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+}
+
+type readyData struct {
+	SessionID        string `json:"session_id"`
+	ResumeGatewayURL string `json:"resume_gateway_url"`
+	User             User   `json:"user"`
+}
+
+type identifyData struct {
+	Token      string             `json:"token"`
+	Intents    int                `json:"intents"`
+	Properties identifyProperties `json:"properties"`
+}
+
+type identifyProperties struct {
+	OS      string `json:"os"`
+	Browser string `json:"browser"`
+	Device  string `json:"device"`
+}
+
+type resumeData struct {
+	Token     string `json:"token"`
+	SessionID string `json:"session_id"`
+	Seq       int64  `json:"seq"`
+}
@@ -16,6 +16,8 @@ import (
 	"strings"
 
 	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/discord"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/slack"
 	"trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/telegram"
 )
 
@@ -142,6 +144,12 @@ func fillTargetFromSession(
 // ResolveTargetFromSessionID infers an outbound target from a chat
 // session id.
 func ResolveTargetFromSessionID(sessionID string) (DeliveryTarget, bool) {
+	if target, ok := resolveSessionScopedTargetValue(
+		"",
+		sessionID,
+	); ok {
+		return target, true
+	}
 	if target, ok := telegram.ResolveTextTargetFromSessionID(sessionID); ok {
 		return DeliveryTarget{
 			Channel: telegram.ChannelName,
@@ -165,6 +173,12 @@ func resolveTargetValue(
 	channelID string,
 	value string,
 ) (DeliveryTarget, bool) {
+	if target, ok := resolveSessionScopedTargetValue(
+		channelID,
+		value,
+	); ok {
+		return target, true
+	}
 	if target, ok := resolveTelegramTargetValue(
 		channelID,
 		value,
@@ -180,6 +194,33 @@ func resolveTargetValue(
 	return DeliveryTarget{}, false
 }
 
+// sessionTargetResolvers map channels whose session ids carry a
+// "<channel>:" prefix to their session id parsers.
+var sessionTargetResolvers = []struct {
+	channel string
+	resolve func(string) (string, bool)
+}{
+	{channel: slack.ChannelName, resolve: slack.ResolveTextTargetFromSessionID},
+	{channel: discord.ChannelName, resolve: discord.ResolveTextTargetFromSessionID},
+}
+
+func resolveSessionScopedTargetValue(
+	channelID string,
+	value string,
+) (DeliveryTarget, bool) {
+	for _, r := range sessionTargetResolvers {
+		if channelID != "" && channelID != r.channel {
+			continue
+		}
+		target, ok := r.resolve(value)
+		if !ok {
+			continue
+		}
+		return DeliveryTarget{Channel: r.channel, Target: target}, true
+	}
+	return DeliveryTarget{}, false
+}
+
 func resolveTelegramTargetValue(
 	channelID string,
 	value string,
@@ -77,6 +77,40 @@ func TestResolveTarget_WeComScopedGroupSession(t *testing.T) {
 	}, target)
 }
 
+func TestResolveTarget_SlackAndDiscordSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sessionID string
+		want      DeliveryTarget
+	}{
+		{
+			sessionID: "slack:dm:U1",
+			want:      DeliveryTarget{Channel: "slack", Target: "U1"},
+		},
+		{
+			sessionID: "slack:thread:C1:1.0",
+			want:      DeliveryTarget{Channel: "slack", Target: "C1:1.0"},
+		},
+		{
+			sessionID: "discord:dm:U1",
+			want:      DeliveryTarget{Channel: "discord", Target: "user:U1"},
+		},
+		{
+			sessionID: "discord:thread:G1:C1",
+			want:      DeliveryTarget{Channel: "discord", Target: "C1"},
+		},
+	}
+	for _, tt := range tests {
+		target, err := ResolveTarget(
+			context.Background(),
+			DeliveryTarget{Target: tt.sessionID},
+		)
+		require.NoError(t, err, tt.sessionID)
+		require.Equal(t, tt.want, target, tt.sessionID)
+	}
+}
+
 func TestResolveTarget_ExplicitWeComTarget(t *testing.T) {
 	t.Parallel()
 
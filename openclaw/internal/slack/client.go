@@ -0,0 +1,345 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package slack implements the small subset of the Slack Web API and
+// Socket Mode protocol used by the OpenClaw Slack channel.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://slack.com/api"
+
+const defaultHTTPTimeout = 30 * time.Second
+
+const (
+	methodAuthTest         = "auth.test"
+	methodConnectionsOpen  = "apps.connections.open"
+	methodPostMessage      = "chat.postMessage"
+	methodGetUploadURL     = "files.getUploadURLExternal"
+	methodCompleteUpload   = "files.completeUploadExternal"
+	headerAuthorization    = "Authorization"
+	headerContentType      = "Content-Type"
+	contentTypeJSON        = "application/json; charset=utf-8"
+	contentTypeForm        = "application/x-www-form-urlencoded"
+	contentTypeOctetStream = "application/octet-stream"
+)
+
+const maxErrorBodyBytes int64 = 4 << 10
+
+// ErrFileTooLarge is returned when a downloaded file exceeds the configured
+// maximum size.
+var ErrFileTooLarge = errors.New("slack: file too large")
+
+// APIError is a Web API response with ok=false.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slack: %s: %s", e.Method, e.Code)
+}
+
+// Client talks to the Slack Web API.
+type Client struct {
+	botToken   string
+	appToken   string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the Web API base URL, mainly for tests.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API calls and downloads.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) { c.httpClient = client }
+}
+
+// WithAppToken sets the app-level token (xapp-...) required to open
+// Socket Mode connections.
+func WithAppToken(token string) Option {
+	return func(c *Client) { c.appToken = strings.TrimSpace(token) }
+}
+
+// New creates a Web API client authenticated with a bot token.
+func New(botToken string, opts ...Option) (*Client, error) {
+	botToken = strings.TrimSpace(botToken)
+	if botToken == "" {
+		return nil, errors.New("slack: empty bot token")
+	}
+	c := &Client{
+		botToken:   botToken,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return c, nil
+}
+
+// AuthTest returns the identity of the bot token.
+func (c *Client) AuthTest(ctx context.Context) (AuthInfo, error) {
+	var out AuthInfo
+	err := c.callForm(ctx, c.botToken, methodAuthTest, nil, &out)
+	return out, err
+}
+
+// OpenConnection returns a fresh Socket Mode websocket URL.
+func (c *Client) OpenConnection(ctx context.Context) (string, error) {
+	if c.appToken == "" {
+		return "", errors.New("slack: socket mode requires an app token")
+	}
+	var out struct {
+		URL string `json:"url"`
+	}
+	if err := c.callForm(
+		ctx,
+		c.appToken,
+		methodConnectionsOpen,
+		nil,
+		&out,
+	); err != nil {
+		return "", err
+	}
+	if out.URL == "" {
+		return "", errors.New("slack: empty socket mode url")
+	}
+	return out.URL, nil
+}
+
+// PostMessage posts a message and returns its timestamp.
+func (c *Client) PostMessage(
+	ctx context.Context,
+	params PostMessageParams,
+) (string, error) {
+	var out struct {
+		TS string `json:"ts"`
+	}
+	err := c.callJSON(ctx, methodPostMessage, params, &out)
+	return out.TS, err
+}
+
+// UploadFile uploads a file into a conversation using the external
+// upload flow (files.getUploadURLExternal + files.completeUploadExternal).
+func (c *Client) UploadFile(
+	ctx context.Context,
+	params UploadFileParams,
+) error {
+	if strings.TrimSpace(params.Filename) == "" {
+		return errors.New("slack: empty upload filename")
+	}
+	var ticket struct {
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	if err := c.callForm(ctx, c.botToken, methodGetUploadURL, url.Values{
+		"filename": {params.Filename},
+		"length":   {strconv.Itoa(len(params.Data))},
+	}, &ticket); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		ticket.UploadURL,
+		bytes.NewReader(params.Data),
+	)
+	if err != nil {
+		return fmt.Errorf("slack: new upload request: %w", err)
+	}
+	req.Header.Set(headerContentType, contentTypeOctetStream)
+	rsp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: upload file: %w", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return statusError(rsp)
+	}
+
+	title := params.Title
+	if title == "" {
+		title = params.Filename
+	}
+	files, err := json.Marshal([]map[string]string{
+		{"id": ticket.FileID, "title": title},
+	})
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"files":      {string(files)},
+		"channel_id": {params.Channel},
+	}
+	if params.ThreadTS != "" {
+		form.Set("thread_ts", params.ThreadTS)
+	}
+	if params.InitialComment != "" {
+		form.Set("initial_comment", params.InitialComment)
+	}
+	return c.callForm(ctx, c.botToken, methodCompleteUpload, form, nil)
+}
+
+// DownloadFile fetches a private file URL with the bot token, failing with
+// ErrFileTooLarge once more than maxBytes are read.
+func (c *Client) DownloadFile(
+	ctx context.Context,
+	fileURL string,
+	maxBytes int64,
+) ([]byte, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("slack: non-positive max bytes")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fileURL,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("slack: new download request: %w", err)
+	}
+	req.Header.Set(headerAuthorization, "Bearer "+c.botToken)
+	rsp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("slack: download file: %w", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, statusError(rsp)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("slack: read file: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func (c *Client) callJSON(
+	ctx context.Context,
+	method string,
+	params any,
+	out any,
+) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("slack: marshal %s: %w", method, err)
+	}
+	return c.do(ctx, c.botToken, method, contentTypeJSON, body, out)
+}
+
+func (c *Client) callForm(
+	ctx context.Context,
+	token string,
+	method string,
+	form url.Values,
+	out any,
+) error {
+	return c.do(
+		ctx,
+		token,
+		method,
+		contentTypeForm,
+		[]byte(form.Encode()),
+		out,
+	)
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	token string,
+	method string,
+	contentType string,
+	body []byte,
+	out any,
+) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.baseURL+"/"+method,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return fmt.Errorf("slack: new request: %w", err)
+	}
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+	req.Header.Set(headerContentType, contentType)
+
+	rsp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: %s: %w", method, err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return statusError(rsp)
+	}
+
+	raw, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return fmt.Errorf("slack: read %s response: %w", method, err)
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return fmt.Errorf("slack: decode %s response: %w", method, err)
+	}
+	if !status.OK {
+		return &APIError{Method: method, Code: status.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("slack: decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+func statusError(rsp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(rsp.Body, maxErrorBodyBytes))
+	return fmt.Errorf(
+		"slack: status %d: %s",
+		rsp.StatusCode,
+		strings.TrimSpace(string(body)),
+	)
+}
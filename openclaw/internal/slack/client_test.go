@@ -0,0 +1,230 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AuthTestAndPostMessage(t *testing.T) {
+	var gotAuth, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		gotAuth = r.Header.Get(headerAuthorization)
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = io.WriteString(w, `{"ok":true,"user_id":"UBOT","team":"T"}`)
+		case "/chat.postMessage":
+			body, _ := io.ReadAll(r.Body)
+			gotBody = string(body)
+			_, _ = io.WriteString(w, `{"ok":true,"ts":"1.2"}`)
+		default:
+			_, _ = io.WriteString(w, `{"ok":false,"error":"unknown_method"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New("xoxb-1", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	auth, err := c.AuthTest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "UBOT", auth.UserID)
+	require.Equal(t, "Bearer xoxb-1", gotAuth)
+
+	ts, err := c.PostMessage(context.Background(), PostMessageParams{
+		Channel:  "C1",
+		Text:     "hi",
+		ThreadTS: "1.1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1.2", ts)
+	require.JSONEq(t, `{"channel":"C1","text":"hi","thread_ts":"1.1"}`, gotBody)
+
+	_, err = c.OpenConnection(context.Background())
+	require.ErrorContains(t, err, "app token")
+
+	_, err = New(" ")
+	require.Error(t, err)
+}
+
+func TestClient_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		_ *http.Request,
+	) {
+		_, _ = io.WriteString(w, `{"ok":false,"error":"invalid_auth"}`)
+	}))
+	defer srv.Close()
+
+	c, err := New("xoxb-1", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	_, err = c.AuthTest(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "invalid_auth", apiErr.Code)
+}
+
+func TestClient_UploadFile(t *testing.T) {
+	var (
+		uploaded []byte
+		complete map[string][]string
+	)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		switch r.URL.Path {
+		case "/files.getUploadURLExternal":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "a.txt", r.Form.Get("filename"))
+			require.Equal(t, "5", r.Form.Get("length"))
+			_, _ = io.WriteString(w, `{"ok":true,"file_id":"F1","upload_url":"`+
+				srv.URL+`/upload"}`)
+		case "/upload":
+			uploaded, _ = io.ReadAll(r.Body)
+		case "/files.completeUploadExternal":
+			require.NoError(t, r.ParseForm())
+			complete = r.Form
+			_, _ = io.WriteString(w, `{"ok":true}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New("xoxb-1", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	require.NoError(t, c.UploadFile(context.Background(), UploadFileParams{
+		Channel:        "C1",
+		ThreadTS:       "1.1",
+		Filename:       "a.txt",
+		InitialComment: "here",
+		Data:           []byte("hello"),
+	}))
+	require.Equal(t, "hello", string(uploaded))
+	require.Equal(t, "C1", complete["channel_id"][0])
+	require.Equal(t, "1.1", complete["thread_ts"][0])
+	require.Equal(t, "here", complete["initial_comment"][0])
+	require.JSONEq(t, `[{"id":"F1","title":"a.txt"}]`, complete["files"][0])
+}
+
+func TestClient_DownloadFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		if r.Header.Get(headerAuthorization) != "Bearer xoxb-1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = io.WriteString(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	c, err := New("xoxb-1")
+	require.NoError(t, err)
+	data, err := c.DownloadFile(context.Background(), srv.URL, 10)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789", string(data))
+
+	_, err = c.DownloadFile(context.Background(), srv.URL, 5)
+	require.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestSocketClient_AcksAndReconnects(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var (
+		mu    sync.Mutex
+		acks  []string
+		opens int
+	)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		switch r.URL.Path {
+		case "/apps.connections.open":
+			require.Equal(t, "Bearer xapp-1", r.Header.Get(headerAuthorization))
+			mu.Lock()
+			opens++
+			mu.Unlock()
+			wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+			_, _ = io.WriteString(w, `{"ok":true,"url":"`+wsURL+`"}`)
+		case "/ws":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+			defer conn.Close()
+			_ = conn.WriteJSON(Envelope{Type: EnvelopeTypeHello})
+			_ = conn.WriteJSON(Envelope{
+				Type:       EnvelopeTypeEventsAPI,
+				EnvelopeID: "e1",
+				Payload: json.RawMessage(
+					`{"event":{"type":"message","text":"hi"}}`,
+				),
+			})
+			var ack map[string]string
+			if err := conn.ReadJSON(&ack); err == nil {
+				mu.Lock()
+				acks = append(acks, ack["envelope_id"])
+				mu.Unlock()
+			}
+			_ = conn.WriteJSON(Envelope{Type: EnvelopeTypeDisconnect})
+			_, _, _ = conn.ReadMessage()
+		}
+	}))
+	defer srv.Close()
+
+	api, err := New("xoxb-1", WithBaseURL(srv.URL), WithAppToken("xapp-1"))
+	require.NoError(t, err)
+	socket := NewSocketClient(api, WithReconnectBackoff(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Envelope, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- socket.Run(ctx, func(_ context.Context, env Envelope) {
+			events <- env
+		})
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case env := <-events:
+			var cb EventCallback
+			require.NoError(t, json.Unmarshal(env.Payload, &cb))
+			require.Equal(t, "hi", cb.Event.Text)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+		}
+	}
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, opens, 2, "disconnect reopens the socket")
+	require.Equal(t, "e1", acks[0])
+}
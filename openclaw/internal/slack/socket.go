@@ -0,0 +1,149 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const defaultReconnectBackoff = time.Second
+
+// EnvelopeHandler receives acknowledged Socket Mode envelopes.
+//
+// Envelopes are acknowledged before the handler runs, so handlers that
+// do slow work should not block the read loop for long.
+type EnvelopeHandler func(ctx context.Context, env Envelope)
+
+type connectionOpener interface {
+	OpenConnection(ctx context.Context) (string, error)
+}
+
+// SocketClient receives events over Slack Socket Mode and reconnects when
+// Slack rotates or drops the connection.
+type SocketClient struct {
+	api     connectionOpener
+	dialer  *websocket.Dialer
+	backoff time.Duration
+	onError func(error)
+}
+
+// SocketOption configures a SocketClient.
+type SocketOption func(*SocketClient)
+
+// WithReconnectBackoff sets the delay before reconnecting after an error.
+func WithReconnectBackoff(backoff time.Duration) SocketOption {
+	return func(s *SocketClient) { s.backoff = backoff }
+}
+
+// WithSocketErrorHandler receives connection errors that trigger a
+// reconnect.
+func WithSocketErrorHandler(fn func(error)) SocketOption {
+	return func(s *SocketClient) { s.onError = fn }
+}
+
+// NewSocketClient creates a Socket Mode client. The Client must have been
+// configured with an app-level token.
+func NewSocketClient(api *Client, opts ...SocketOption) *SocketClient {
+	s := &SocketClient{
+		api:     api,
+		dialer:  websocket.DefaultDialer,
+		backoff: defaultReconnectBackoff,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run connects and dispatches envelopes to handler until ctx is done.
+func (s *SocketClient) Run(ctx context.Context, handler EnvelopeHandler) error {
+	if s == nil || s.api == nil {
+		return errors.New("slack: nil socket client")
+	}
+	if handler == nil {
+		return errors.New("slack: nil envelope handler")
+	}
+	for {
+		err := s.runOnce(ctx, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if s.onError != nil {
+				s.onError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.backoff):
+			}
+		}
+	}
+}
+
+func (s *SocketClient) runOnce(
+	ctx context.Context,
+	handler EnvelopeHandler,
+) error {
+	wsURL, err := s.api.OpenConnection(ctx)
+	if err != nil {
+		return err
+	}
+	conn, _, err := s.dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("slack: dial socket: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	var writeMu sync.Mutex
+	for {
+		var env Envelope
+		if err := conn.ReadJSON(&env); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("slack: read socket: %w", err)
+		}
+		if env.EnvelopeID != "" {
+			writeMu.Lock()
+			err := conn.WriteJSON(map[string]string{
+				"envelope_id": env.EnvelopeID,
+			})
+			writeMu.Unlock()
+			if err != nil {
+				return fmt.Errorf("slack: ack envelope: %w", err)
+			}
+		}
+		switch env.Type {
+		case EnvelopeTypeHello:
+		case EnvelopeTypeDisconnect:
+			return nil
+		default:
+			handler(ctx, env)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import "encoding/json"
+
+const (
+	// EnvelopeTypeHello is sent once after the socket connects.
+	EnvelopeTypeHello = "hello"
+	// EnvelopeTypeDisconnect asks the client to reconnect.
+	EnvelopeTypeDisconnect = "disconnect"
+	// EnvelopeTypeEventsAPI carries an Events API callback.
+	EnvelopeTypeEventsAPI = "events_api"
+	// EnvelopeTypeSlashCommands carries a slash command invocation.
+	EnvelopeTypeSlashCommands = "slash_commands"
+)
+
+const (
+	// EventTypeMessage is a message posted in a conversation.
+	EventTypeMessage = "message"
+	// EventTypeAppMention is a message that mentions the app.
+	EventTypeAppMention = "app_mention"
+
+	// MessageSubtypeFileShare is a message carrying uploaded files.
+	MessageSubtypeFileShare = "file_share"
+
+	// ChannelTypeIM is a direct message conversation.
+	ChannelTypeIM = "im"
+)
+
+// AuthInfo is the identity returned by auth.test.
+type AuthInfo struct {
+	UserID string `json:"user_id"`
+	User   string `json:"user"`
+	TeamID string `json:"team_id"`
+	Team   string `json:"team"`
+	BotID  string `json:"bot_id"`
+}
+
+// Envelope is one Socket Mode frame.
+type Envelope struct {
+	Type       string          `json:"type"`
+	EnvelopeID string          `json:"envelope_id,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// EventCallback is the payload of an events_api envelope.
+type EventCallback struct {
+	TeamID  string       `json:"team_id"`
+	EventID string       `json:"event_id"`
+	Event   MessageEvent `json:"event"`
+}
+
+// MessageEvent is a message or app_mention event.
+type MessageEvent struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype,omitempty"`
+	User        string `json:"user,omitempty"`
+	BotID       string `json:"bot_id,omitempty"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type,omitempty"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts,omitempty"`
+	Files       []File `json:"files,omitempty"`
+}
+
+// File is a Slack file attached to a message.
+type File struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Mimetype           string `json:"mimetype"`
+	Size               int64  `json:"size"`
+	URLPrivate         string `json:"url_private"`
+	URLPrivateDownload string `json:"url_private_download"`
+}
+
+// DownloadURL returns the best URL to fetch the file content from.
+func (f File) DownloadURL() string {
+	if f.URLPrivateDownload != "" {
+		return f.URLPrivateDownload
+	}
+	return f.URLPrivate
+}
+
+// SlashCommand is the payload of a slash_commands envelope.
+type SlashCommand struct {
+	Command     string `json:"command"`
+	Text        string `json:"text"`
+	UserID      string `json:"user_id"`
+	UserName    string `json:"user_name"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	TeamID      string `json:"team_id"`
+	TriggerID   string `json:"trigger_id"`
+	ResponseURL string `json:"response_url"`
+}
+
+// PostMessageParams are the chat.postMessage arguments the channel uses.
+type PostMessageParams struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+}
+
+// UploadFileParams describes one file upload into a conversation.
+type UploadFileParams struct {
+	Channel        string
+	ThreadTS       string
+	Filename       string
+	Title          string
+	InitialComment string
+	Data           []byte
+}
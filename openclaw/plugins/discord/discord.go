@@ -0,0 +1,136 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package discord registers the Discord channel plugin.
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+
+	occhannel "trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	dcch "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/discord"
+	dcapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/discord"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/registry"
+)
+
+const (
+	pluginType = "discord"
+
+	errMissingToken = "discord channel: missing config.token"
+)
+
+func init() {
+	if err := registry.RegisterChannel(pluginType, newChannel); err != nil {
+		panic(err)
+	}
+}
+
+type channelCfg struct {
+	Token string `yaml:"token"`
+
+	APIBaseURL  string `yaml:"api_base_url"`
+	HTTPTimeout string `yaml:"http_timeout"`
+	Intents     *int   `yaml:"intents"`
+
+	DMPolicy     string   `yaml:"dm_policy"`
+	GroupPolicy  string   `yaml:"group_policy"`
+	AllowThreads []string `yaml:"allow_threads"`
+	PairingTTL   string   `yaml:"pairing_ttl"`
+
+	MaxDownloadBytes *int64 `yaml:"max_download_bytes"`
+}
+
+func newChannel(
+	deps registry.ChannelDeps,
+	spec registry.PluginSpec,
+) (occhannel.Channel, error) {
+	if deps.Gateway == nil {
+		return nil, errors.New("discord channel: nil gateway client")
+	}
+
+	var cfg channelCfg
+	if err := registry.DecodeStrict(spec.Config, &cfg); err != nil {
+		return nil, err
+	}
+	token := strings.TrimSpace(cfg.Token)
+	if token == "" {
+		return nil, errors.New(errMissingToken)
+	}
+
+	ctx := deps.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var apiOpts []dcapi.Option
+	if strings.TrimSpace(cfg.APIBaseURL) != "" {
+		apiOpts = append(apiOpts, dcapi.WithBaseURL(cfg.APIBaseURL))
+	}
+	if strings.TrimSpace(cfg.HTTPTimeout) != "" {
+		timeout, err := time.ParseDuration(strings.TrimSpace(cfg.HTTPTimeout))
+		if err != nil {
+			return nil, err
+		}
+		apiOpts = append(
+			apiOpts,
+			dcapi.WithHTTPClient(&http.Client{Timeout: timeout}),
+		)
+	}
+	api, err := dcapi.New(token, apiOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := api.Me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Discord enabled as %s (%s)", me.Username, me.ID)
+
+	gwOpts := []dcapi.GatewayOption{
+		dcapi.WithGatewayErrorHandler(func(err error) {
+			log.Warnf("discord: gateway: %v", err)
+		}),
+	}
+	if cfg.Intents != nil {
+		gwOpts = append(gwOpts, dcapi.WithIntents(*cfg.Intents))
+	}
+	gateway := dcapi.NewGateway(api, gwOpts...)
+
+	chOpts := []dcch.Option{
+		dcch.WithName(spec.Name),
+		dcch.WithStateDir(deps.StateDir),
+		dcch.WithDMPolicy(cfg.DMPolicy),
+		dcch.WithGroupPolicy(cfg.GroupPolicy),
+		dcch.WithAllowUsers(deps.AllowUsers...),
+		dcch.WithAllowThreads(cfg.AllowThreads...),
+	}
+	if strings.TrimSpace(cfg.PairingTTL) != "" {
+		ttl, err := time.ParseDuration(strings.TrimSpace(cfg.PairingTTL))
+		if err != nil {
+			return nil, err
+		}
+		chOpts = append(chOpts, dcch.WithPairingTTL(ttl))
+	}
+	if cfg.MaxDownloadBytes != nil {
+		chOpts = append(
+			chOpts,
+			dcch.WithMaxDownloadBytes(*cfg.MaxDownloadBytes),
+		)
+	}
+
+	return dcch.New(api, gateway, me, deps.Gateway, chOpts...)
+}
@@ -0,0 +1,123 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package discord
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/registry"
+)
+
+type stubGateway struct{}
+
+func (stubGateway) SendMessage(
+	_ context.Context,
+	_ gwclient.MessageRequest,
+) (gwclient.MessageResponse, error) {
+	return gwclient.MessageResponse{}, nil
+}
+
+func (stubGateway) Cancel(
+	_ context.Context,
+	_ string,
+) (bool, error) {
+	return false, nil
+}
+
+func TestNewChannel_Success(t *testing.T) {
+	srv := newDiscordServer(t, http.StatusOK, `{"id":"B1","username":"bot"}`)
+
+	spec := registry.PluginSpec{
+		Type: pluginType,
+		Config: mustYAMLNode(t, `
+token: tok
+api_base_url: `+srv.URL+`
+http_timeout: 1s
+intents: 4609
+dm_policy: allowlist
+group_policy: open
+pairing_ttl: 30m
+max_download_bytes: 123
+`),
+	}
+
+	ch, err := newChannel(registry.ChannelDeps{
+		Gateway:    stubGateway{},
+		StateDir:   t.TempDir(),
+		AllowUsers: []string{"U1"},
+	}, spec)
+	require.NoError(t, err)
+	require.Equal(t, pluginType, ch.ID())
+}
+
+func TestNewChannel_Errors(t *testing.T) {
+	srv := newDiscordServer(t, http.StatusUnauthorized,
+		`{"message":"401: Unauthorized","code":0}`)
+	deps := registry.ChannelDeps{
+		Gateway:  stubGateway{},
+		StateDir: t.TempDir(),
+	}
+
+	_, err := newChannel(deps, registry.PluginSpec{
+		Type:   pluginType,
+		Config: mustYAMLNode(t, "dm_policy: open\n"),
+	})
+	require.ErrorContains(t, err, errMissingToken)
+
+	_, err = newChannel(deps, registry.PluginSpec{
+		Type: pluginType,
+		Config: mustYAMLNode(t, "token: tok\napi_base_url: "+
+			srv.URL+"\n"),
+	})
+	require.ErrorContains(t, err, "401")
+
+	_, err = newChannel(deps, registry.PluginSpec{
+		Type:   pluginType,
+		Config: mustYAMLNode(t, "token: tok\nhttp_timeout: soon\n"),
+	})
+	require.Error(t, err)
+
+	_, err = newChannel(registry.ChannelDeps{}, registry.PluginSpec{})
+	require.Error(t, err)
+}
+
+func newDiscordServer(
+	t *testing.T,
+	status int,
+	body string,
+) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		_ *http.Request,
+	) {
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func mustYAMLNode(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	return &node
+}
@@ -0,0 +1,138 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package slack registers the Slack channel plugin.
+package slack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+
+	occhannel "trpc.group/trpc-go/trpc-agent-go/openclaw/channel"
+	slackch "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/channel/slack"
+	slackapi "trpc.group/trpc-go/trpc-agent-go/openclaw/internal/slack"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/registry"
+)
+
+const (
+	pluginType = "slack"
+
+	errMissingBotToken = "slack channel: missing config.bot_token"
+	errMissingAppToken = "slack channel: missing config.app_token"
+)
+
+func init() {
+	if err := registry.RegisterChannel(pluginType, newChannel); err != nil {
+		panic(err)
+	}
+}
+
+type channelCfg struct {
+	BotToken string `yaml:"bot_token"`
+	AppToken string `yaml:"app_token"`
+
+	APIBaseURL  string `yaml:"api_base_url"`
+	HTTPTimeout string `yaml:"http_timeout"`
+
+	DMPolicy     string   `yaml:"dm_policy"`
+	GroupPolicy  string   `yaml:"group_policy"`
+	AllowThreads []string `yaml:"allow_threads"`
+	PairingTTL   string   `yaml:"pairing_ttl"`
+
+	MaxDownloadBytes *int64 `yaml:"max_download_bytes"`
+}
+
+func newChannel(
+	deps registry.ChannelDeps,
+	spec registry.PluginSpec,
+) (occhannel.Channel, error) {
+	if deps.Gateway == nil {
+		return nil, errors.New("slack channel: nil gateway client")
+	}
+
+	var cfg channelCfg
+	if err := registry.DecodeStrict(spec.Config, &cfg); err != nil {
+		return nil, err
+	}
+	botToken := strings.TrimSpace(cfg.BotToken)
+	if botToken == "" {
+		return nil, errors.New(errMissingBotToken)
+	}
+	appToken := strings.TrimSpace(cfg.AppToken)
+	if appToken == "" {
+		return nil, errors.New(errMissingAppToken)
+	}
+
+	ctx := deps.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	apiOpts := []slackapi.Option{slackapi.WithAppToken(appToken)}
+	if strings.TrimSpace(cfg.APIBaseURL) != "" {
+		apiOpts = append(apiOpts, slackapi.WithBaseURL(cfg.APIBaseURL))
+	}
+	if strings.TrimSpace(cfg.HTTPTimeout) != "" {
+		timeout, err := time.ParseDuration(strings.TrimSpace(cfg.HTTPTimeout))
+		if err != nil {
+			return nil, err
+		}
+		apiOpts = append(
+			apiOpts,
+			slackapi.WithHTTPClient(&http.Client{Timeout: timeout}),
+		)
+	}
+	api, err := slackapi.New(botToken, apiOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := api.AuthTest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Slack enabled as %s (%s) in %s", auth.User, auth.UserID, auth.Team)
+
+	socket := slackapi.NewSocketClient(
+		api,
+		slackapi.WithSocketErrorHandler(func(err error) {
+			log.Warnf("slack: socket mode: %v", err)
+		}),
+	)
+
+	chOpts := []slackch.Option{
+		slackch.WithName(spec.Name),
+		slackch.WithStateDir(deps.StateDir),
+		slackch.WithDMPolicy(cfg.DMPolicy),
+		slackch.WithGroupPolicy(cfg.GroupPolicy),
+		slackch.WithAllowUsers(deps.AllowUsers...),
+		slackch.WithAllowThreads(cfg.AllowThreads...),
+	}
+	if strings.TrimSpace(cfg.PairingTTL) != "" {
+		ttl, err := time.ParseDuration(strings.TrimSpace(cfg.PairingTTL))
+		if err != nil {
+			return nil, err
+		}
+		chOpts = append(chOpts, slackch.WithPairingTTL(ttl))
+	}
+	if cfg.MaxDownloadBytes != nil {
+		chOpts = append(
+			chOpts,
+			slackch.WithMaxDownloadBytes(*cfg.MaxDownloadBytes),
+		)
+	}
+
+	return slackch.New(api, socket, auth, deps.Gateway, chOpts...)
+}
@@ -0,0 +1,137 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/gwclient"
+	"trpc.group/trpc-go/trpc-agent-go/openclaw/registry"
+)
+
+type stubGateway struct{}
+
+func (stubGateway) SendMessage(
+	_ context.Context,
+	_ gwclient.MessageRequest,
+) (gwclient.MessageResponse, error) {
+	return gwclient.MessageResponse{}, nil
+}
+
+func (stubGateway) Cancel(
+	_ context.Context,
+	_ string,
+) (bool, error) {
+	return false, nil
+}
+
+func TestNewChannel_Success(t *testing.T) {
+	srv := newSlackServer(t, `{"ok":true,"user_id":"UBOT","user":"bot"}`)
+
+	spec := registry.PluginSpec{
+		Type: pluginType,
+		Name: "work",
+		Config: mustYAMLNode(t, `
+bot_token: xoxb-1
+app_token: xapp-1
+api_base_url: `+srv.URL+`
+http_timeout: 1s
+dm_policy: open
+group_policy: allowlist
+allow_threads:
+  - "C1"
+pairing_ttl: 30m
+max_download_bytes: 123
+`),
+	}
+
+	ch, err := newChannel(registry.ChannelDeps{
+		Gateway:  stubGateway{},
+		StateDir: t.TempDir(),
+	}, spec)
+	require.NoError(t, err)
+	require.Equal(t, pluginType, ch.ID())
+}
+
+func TestNewChannel_Errors(t *testing.T) {
+	srv := newSlackServer(t, `{"ok":false,"error":"invalid_auth"}`)
+	deps := registry.ChannelDeps{
+		Gateway:  stubGateway{},
+		StateDir: t.TempDir(),
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "missing bot token",
+			config:  "app_token: xapp-1\n",
+			wantErr: errMissingBotToken,
+		},
+		{
+			name:    "missing app token",
+			config:  "bot_token: xoxb-1\n",
+			wantErr: errMissingAppToken,
+		},
+		{
+			name:    "unknown field",
+			config:  "bot_token: xoxb-1\napp_token: xapp-1\ntoken: x\n",
+			wantErr: "token",
+		},
+		{
+			name: "auth failure",
+			config: "bot_token: xoxb-1\napp_token: xapp-1\n" +
+				"api_base_url: " + srv.URL + "\n",
+			wantErr: "invalid_auth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newChannel(deps, registry.PluginSpec{
+				Type:   pluginType,
+				Config: mustYAMLNode(t, tt.config),
+			})
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := newChannel(registry.ChannelDeps{}, registry.PluginSpec{})
+	require.Error(t, err)
+}
+
+func newSlackServer(t *testing.T, authBody string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(
+		w http.ResponseWriter,
+		_ *http.Request,
+	) {
+		_, _ = io.WriteString(w, authBody)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func mustYAMLNode(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	return &node
+}
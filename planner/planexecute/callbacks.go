@@ -0,0 +1,269 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package planexecute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// modelCallbacks returns the callbacks around model calls: the plugin
+// callbacks of the runner first, then those of the agent.
+func (r *run) modelCallbacks() []*model.Callbacks {
+	var sets []*model.Callbacks
+	if r.inv.Plugins != nil {
+		if cb := r.inv.Plugins.ModelCallbacks(); cb != nil {
+			sets = append(sets, cb)
+		}
+	}
+	if r.opts.modelCallbacks != nil {
+		sets = append(sets, r.opts.modelCallbacks)
+	}
+	return sets
+}
+
+// toolCallbacks returns the callbacks around tool calls in the same order
+// as modelCallbacks.
+func (r *run) toolCallbacks() []*tool.Callbacks {
+	var sets []*tool.Callbacks
+	if r.inv.Plugins != nil {
+		if cb := r.inv.Plugins.ToolCallbacks(); cb != nil {
+			sets = append(sets, cb)
+		}
+	}
+	if r.opts.toolCallbacks != nil {
+		sets = append(sets, r.opts.toolCallbacks)
+	}
+	return sets
+}
+
+// generate sends a non-streaming request to the model through the model
+// callbacks and returns its complete response. A before-model callback
+// that returns a response replaces the model call, and an after-model
+// callback that returns one replaces the model response; either skips the
+// callbacks that follow it.
+func (r *run) generate(ctx context.Context, system, user string) (*model.Response, error) {
+	req := &model.Request{
+		Messages: []model.Message{
+			model.NewSystemMessage(system),
+			model.NewUserMessage(user),
+		},
+	}
+	callbacks := r.modelCallbacks()
+	var rsp *model.Response
+	for _, cb := range callbacks {
+		result, err := cb.RunBeforeModel(ctx, &model.BeforeModelArgs{Request: req})
+		if err != nil {
+			return nil, fmt.Errorf("callback before model: %w", err)
+		}
+		if result != nil && result.Context != nil {
+			ctx = result.Context
+		}
+		if result != nil && result.CustomResponse != nil {
+			rsp = result.CustomResponse
+			break
+		}
+	}
+	var modelErr error
+	if rsp == nil {
+		rsp, modelErr = r.callModel(ctx, req)
+	}
+	for _, cb := range callbacks {
+		result, err := cb.RunAfterModel(ctx, &model.AfterModelArgs{
+			Request:  req,
+			Response: rsp,
+			Error:    modelErr,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("callback after model: %w", err)
+		}
+		if result != nil && result.CustomResponse != nil {
+			rsp, modelErr = result.CustomResponse, nil
+			break
+		}
+	}
+	if modelErr != nil {
+		return nil, modelErr
+	}
+	if rsp != nil && rsp.Error != nil {
+		return nil, fmt.Errorf("model error: %s", rsp.Error.Message)
+	}
+	if rsp == nil || len(rsp.Choices) == 0 {
+		return nil, errors.New("empty model response")
+	}
+	return rsp, nil
+}
+
+// callModel returns the last complete response of the model. On an error
+// response it stops the model and drains the rest of its responses, so the
+// model goroutine does not block on a send nobody receives.
+func (r *run) callModel(ctx context.Context, req *model.Request) (*model.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rsps, err := r.opts.model.GenerateContent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var last *model.Response
+	for rsp := range rsps {
+		if rsp == nil {
+			continue
+		}
+		if rsp.Error != nil {
+			cancel()
+			for range rsps {
+			}
+			return rsp, fmt.Errorf("model error: %s", rsp.Error.Message)
+		}
+		if !rsp.IsPartial {
+			last = rsp
+		}
+	}
+	return last, nil
+}
+
+// callTool runs step i on t through the tool callbacks and emits the call
+// and its result as tool events, as a tool called by the model would be.
+func (r *run) callTool(ctx context.Context, t tool.CallableTool, i int) (string, error) {
+	step := r.plan.Steps[i]
+	args := step.Input
+	if args == "" {
+		args = "{}"
+	}
+	decl := t.Declaration()
+	call := model.ToolCall{
+		Type: "function",
+		ID:   fmt.Sprintf("%s-step-%d", r.inv.InvocationID, step.ID),
+		Function: model.FunctionDefinitionParam{
+			Name:      decl.Name,
+			Arguments: []byte(args),
+		},
+	}
+	if err := agent.EmitEvent(ctx, r.inv, r.ch, event.NewResponseEvent(r.inv.InvocationID, r.name,
+		&model.Response{
+			Object:    model.ObjectTypeChatCompletion,
+			Created:   time.Now().Unix(),
+			Timestamp: time.Now(),
+			Done:      true,
+			Choices: []model.Choice{{Message: model.Message{
+				Role:      model.RoleAssistant,
+				ToolCalls: []model.ToolCall{call},
+			}}},
+		})); err != nil {
+		return "", err
+	}
+
+	out, callErr := r.runTool(ctx, t, decl, call)
+	content, err := toolContent(out, callErr)
+	rsp := &model.Response{
+		Object:    model.ObjectTypeToolResponse,
+		Created:   time.Now().Unix(),
+		Timestamp: time.Now(),
+		Choices: []model.Choice{{
+			Message: model.NewToolMessage(call.ID, decl.Name, content),
+		}},
+	}
+	if err != nil {
+		// Like the flow, a failed call is reported as the tool result.
+		rsp.Choices[0].Message.Content = err.Error()
+	}
+	if emitErr := agent.EmitEvent(ctx, r.inv, r.ch,
+		event.NewResponseEvent(r.inv.InvocationID, r.name, rsp)); emitErr != nil {
+		return "", emitErr
+	}
+	return content, err
+}
+
+// runTool calls t between the before-tool and after-tool callbacks. A
+// before-tool result replaces the tool call and skips the callbacks that
+// follow it; an after-tool result replaces the tool result seen by the
+// callbacks that follow it.
+func (r *run) runTool(
+	ctx context.Context,
+	t tool.CallableTool,
+	decl *tool.Declaration,
+	call model.ToolCall,
+) (any, error) {
+	callbacks := r.toolCallbacks()
+	var (
+		out    any
+		custom bool
+	)
+	for _, cb := range callbacks {
+		result, err := cb.RunBeforeTool(ctx, &tool.BeforeToolArgs{
+			ToolCallID:  call.ID,
+			ToolName:    decl.Name,
+			Declaration: decl,
+			Arguments:   call.Function.Arguments,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("callback before tool: %w", err)
+		}
+		if result == nil {
+			continue
+		}
+		if result.Context != nil {
+			ctx = result.Context
+		}
+		if result.ModifiedArguments != nil {
+			call.Function.Arguments = result.ModifiedArguments
+		}
+		if result.CustomResult != nil {
+			out, custom = result.CustomResult, true
+			break
+		}
+	}
+	var callErr error
+	if !custom {
+		out, callErr = t.Call(ctx, call.Function.Arguments)
+	}
+	for _, cb := range callbacks {
+		result, err := cb.RunAfterTool(ctx, &tool.AfterToolArgs{
+			ToolCallID:  call.ID,
+			ToolName:    decl.Name,
+			Declaration: decl,
+			Arguments:   call.Function.Arguments,
+			Result:      out,
+			Error:       callErr,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("callback after tool: %w", err)
+		}
+		// A set echoes the result when its callbacks return none, so each
+		// set passes its result on to the next instead of ending the loop.
+		if result != nil && result.CustomResult != nil {
+			out, callErr = result.CustomResult, nil
+		}
+	}
+	return out, callErr
+}
+
+// toolContent renders a tool result as step result text.
+func toolContent(out any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if s, ok := out.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("encode tool result: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,133 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package planexecute
+
+import (
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	defaultChannelBufferSize = 256
+	defaultMaxSteps          = 10
+	defaultMaxReplans        = 3
+)
+
+// Option configures the plan-and-execute agent.
+type Option func(*options)
+
+type options struct {
+	model             model.Model
+	description       string
+	instruction       string
+	subAgents         []agent.Agent
+	tools             []tool.Tool
+	maxSteps          int
+	maxReplans        int
+	checkSteps        bool
+	channelBufferSize int
+	modelCallbacks    *model.Callbacks
+	toolCallbacks     *tool.Callbacks
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		maxSteps:          defaultMaxSteps,
+		maxReplans:        defaultMaxReplans,
+		checkSteps:        true,
+		channelBufferSize: defaultChannelBufferSize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithModel sets the model that plans, checks step results, replans and
+// writes the final answer. It also executes steps without an executor.
+// The option is required.
+func WithModel(m model.Model) Option {
+	return func(o *options) { o.model = m }
+}
+
+// WithDescription sets the agent description.
+func WithDescription(description string) Option {
+	return func(o *options) { o.description = description }
+}
+
+// WithInstruction adds domain guidance to every planner prompt.
+func WithInstruction(instruction string) Option {
+	return func(o *options) { o.instruction = instruction }
+}
+
+// WithSubAgents makes agents available as step executors. The planner
+// sees their names and descriptions.
+func WithSubAgents(subAgents []agent.Agent) Option {
+	return func(o *options) { o.subAgents = subAgents }
+}
+
+// WithTools makes callable tools available as step executors. Steps call
+// them once with JSON arguments chosen by the planner.
+func WithTools(tools []tool.Tool) Option {
+	return func(o *options) { o.tools = tools }
+}
+
+// WithMaxSteps caps the number of steps executed in one run, including
+// steps added by replanning. Default is 10.
+func WithMaxSteps(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxSteps = n
+		}
+	}
+}
+
+// WithMaxReplans caps the number of replans in one run. Zero disables
+// replanning, so the first failed step fails the plan. Default is 3.
+func WithMaxReplans(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.maxReplans = n
+		}
+	}
+}
+
+// WithCheckSteps controls whether the model reviews every step result
+// before the plan continues. When disabled, only execution errors trigger
+// replanning. Default is true.
+func WithCheckSteps(enabled bool) Option {
+	return func(o *options) { o.checkSteps = enabled }
+}
+
+// WithChannelBufferSize sets the buffer size of the event channel.
+// Default is 256.
+func WithChannelBufferSize(size int) Option {
+	return func(o *options) {
+		if size >= 0 {
+			o.channelBufferSize = size
+		}
+	}
+}
+
+// WithModelCallbacks sets callbacks around every model call: planning,
+// checking, replanning, steps answered by the model and the final answer.
+// They run after the model callbacks of runner plugins.
+func WithModelCallbacks(callbacks *model.Callbacks) Option {
+	return func(o *options) { o.modelCallbacks = callbacks }
+}
+
+// WithToolCallbacks sets callbacks around every tool step. They run after
+// the tool callbacks of runner plugins.
+func WithToolCallbacks(callbacks *tool.Callbacks) Option {
+	return func(o *options) { o.toolCallbacks = callbacks }
+}
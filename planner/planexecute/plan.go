@@ -0,0 +1,151 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package planexecute
+
+import (
+	"encoding/json"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/session"
+)
+
+// StateKeyPrefix is the session state key prefix under which plans are
+// stored. The final key is StateKeyPrefix + agent name, so several
+// plan-and-execute agents in one session keep separate plans.
+const StateKeyPrefix = "temp:plan:"
+
+// StateKey returns the session state key of the plan of agentName.
+func StateKey(agentName string) string {
+	return StateKeyPrefix + agentName
+}
+
+// IsStateKey reports whether key holds a plan.
+func IsStateKey(key string) bool {
+	return strings.HasPrefix(key, StateKeyPrefix)
+}
+
+// PlanStatus is the lifecycle state of a plan.
+type PlanStatus string
+
+// Plan statuses.
+const (
+	PlanStatusPlanning  PlanStatus = "planning"
+	PlanStatusRunning   PlanStatus = "running"
+	PlanStatusCompleted PlanStatus = "completed"
+	PlanStatusFailed    PlanStatus = "failed"
+)
+
+// StepStatus is the lifecycle state of a plan step.
+type StepStatus string
+
+// Step statuses.
+const (
+	StepStatusPending    StepStatus = "pending"
+	StepStatusInProgress StepStatus = "in_progress"
+	StepStatusCompleted  StepStatus = "completed"
+	StepStatusFailed     StepStatus = "failed"
+	// StepStatusSkipped marks steps dropped by a replan.
+	StepStatusSkipped StepStatus = "skipped"
+)
+
+// Plan is the structured plan of one run. It is stored as JSON in session
+// state and attached to every plan progress event.
+type Plan struct {
+	// Goal is the user request the plan works towards.
+	Goal string `json:"goal"`
+	// Status is the overall plan status.
+	Status PlanStatus `json:"status"`
+	// Revision starts at 1 and increases with every replan.
+	Revision int `json:"revision"`
+	// Steps lists the steps in execution order, including finished ones.
+	Steps []Step `json:"steps"`
+	// Current is the index of the running step, or -1.
+	Current int `json:"current"`
+	// Reason explains the last replan or the failure of the plan.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Step is a single plan step.
+type Step struct {
+	// ID is stable across replans, so clients can track steps.
+	ID int `json:"id"`
+	// Description says what the step achieves.
+	Description string `json:"description"`
+	// Executor names the sub-agent or tool running the step. Empty means
+	// the planner model answers the step itself.
+	Executor string `json:"executor,omitempty"`
+	// Input is the task given to a sub-agent, or the JSON arguments of a
+	// tool.
+	Input string `json:"input,omitempty"`
+	// Status is the step status.
+	Status StepStatus `json:"status"`
+	// Result is the output of the step once it has run.
+	Result string `json:"result,omitempty"`
+	// Error explains why the step failed.
+	Error string `json:"error,omitempty"`
+}
+
+// GetPlan returns the plan agentName stored in sess, if any.
+func GetPlan(sess *session.Session, agentName string) (*Plan, bool) {
+	if sess == nil {
+		return nil, false
+	}
+	raw, ok := sess.GetState(StateKey(agentName))
+	if !ok || len(raw) == 0 {
+		return nil, false
+	}
+	return DecodePlan(raw)
+}
+
+// DecodePlan decodes a plan from a state value or a plan progress event
+// state delta.
+func DecodePlan(raw []byte) (*Plan, bool) {
+	var p Plan
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, false
+	}
+	return &p, true
+}
+
+// next returns the index of the first pending step, or -1.
+func (p *Plan) next() int {
+	for i, s := range p.Steps {
+		if s.Status == StepStatusPending {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *Plan) nextID() int {
+	id := 0
+	for _, s := range p.Steps {
+		if s.ID > id {
+			id = s.ID
+		}
+	}
+	return id + 1
+}
+
+// replace drops the pending steps of p and appends steps.
+func (p *Plan) replace(steps []Step) {
+	id := p.nextID()
+	for i := range p.Steps {
+		if p.Steps[i].Status == StepStatusPending {
+			p.Steps[i].Status = StepStatusSkipped
+		}
+	}
+	for _, s := range steps {
+		s.ID = id
+		s.Status = StepStatusPending
+		id++
+		p.Steps = append(p.Steps, s)
+	}
+}
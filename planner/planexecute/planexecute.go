@@ -0,0 +1,401 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package planexecute implements a plan-and-execute agent.
+//
+// Unlike the builtin and React planners, which shape a single model
+// response, the plan-and-execute agent drives the whole loop:
+//
+//  1. The model turns the user request into a structured list of steps.
+//  2. Steps run one at a time, either answered by the model or delegated
+//     to a sub-agent or a tool chosen by the planner.
+//  3. The model checks every step result.
+//  4. A failed step, or a result that makes the remaining steps wrong,
+//     triggers a replan that replaces the pending steps.
+//  5. The model writes the final answer from the step results.
+//
+// The plan is stored as JSON in session state under StateKey(name), and
+// every change is emitted as a plan progress event (object type
+// model.ObjectTypePlanProgress) whose state delta carries the full plan.
+// A2A clients receive it as message metadata and the AG-UI server
+// translates it into a "plan.progress" custom event.
+//
+// Typical use:
+//
+//	planner := planexecute.New("planner",
+//	    planexecute.WithModel(m),
+//	    planexecute.WithSubAgents([]agent.Agent{researcher, writer}),
+//	    planexecute.WithTools([]tool.Tool{calculator}),
+//	)
+package planexecute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+var _ agent.Agent = (*Agent)(nil)
+
+// Agent is a plan-and-execute agent.
+type Agent struct {
+	name      string
+	opts      options
+	executors []executor
+}
+
+// New creates a plan-and-execute agent. WithModel is required.
+func New(name string, opts ...Option) *Agent {
+	a := &Agent{name: name, opts: newOptions(opts...)}
+	for _, sub := range a.opts.subAgents {
+		info := sub.Info()
+		a.executors = append(a.executors, executor{
+			name:        info.Name,
+			kind:        "agent",
+			description: info.Description,
+		})
+	}
+	for _, t := range a.opts.tools {
+		decl := t.Declaration()
+		if _, ok := t.(tool.CallableTool); !ok {
+			log.Warnf("planexecute %s: tool %s is not callable, skipped", name, decl.Name)
+			continue
+		}
+		e := executor{name: decl.Name, kind: "tool", description: decl.Description}
+		if decl.InputSchema != nil {
+			if schema, err := json.Marshal(decl.InputSchema); err == nil {
+				e.parameters = string(schema)
+			}
+		}
+		a.executors = append(a.executors, e)
+	}
+	return a
+}
+
+// Run implements agent.Agent.
+func (a *Agent) Run(ctx context.Context, inv *agent.Invocation) (<-chan *event.Event, error) {
+	if a.opts.model == nil {
+		return nil, errors.New("planexecute: model is required")
+	}
+	size := a.opts.channelBufferSize
+	if s := agent.GetEventChannelBufferSize(inv); s > 0 {
+		size = s
+	}
+	ch := make(chan *event.Event, size)
+	runCtx := agent.CloneContext(ctx)
+	go func() {
+		defer close(ch)
+		inv.Agent = a
+		inv.AgentName = a.name
+		r := &run{Agent: a, inv: inv, ch: ch}
+		if err := r.execute(agent.NewInvocationContext(runCtx, inv)); err != nil {
+			log.WarnfContext(runCtx, "planexecute %s: %v", a.name, err)
+			agent.EmitEvent(runCtx, inv, ch, event.NewErrorEvent(
+				inv.InvocationID, a.name, model.ErrorTypeFlowError, err.Error()))
+		}
+	}()
+	return ch, nil
+}
+
+// Tools implements agent.Agent.
+func (a *Agent) Tools() []tool.Tool {
+	return a.opts.tools
+}
+
+// Info implements agent.Agent.
+func (a *Agent) Info() agent.Info {
+	return agent.Info{Name: a.name, Description: a.opts.description}
+}
+
+// SubAgents implements agent.Agent.
+func (a *Agent) SubAgents() []agent.Agent {
+	return a.opts.subAgents
+}
+
+// FindSubAgent implements agent.Agent.
+func (a *Agent) FindSubAgent(name string) agent.Agent {
+	for _, sub := range a.opts.subAgents {
+		if sub.Info().Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (a *Agent) findExecutor(name string) *executor {
+	for i := range a.executors {
+		if a.executors[i].name == name {
+			return &a.executors[i]
+		}
+	}
+	return nil
+}
+
+func (a *Agent) findTool(name string) tool.CallableTool {
+	for _, t := range a.opts.tools {
+		if ct, ok := t.(tool.CallableTool); ok && t.Declaration().Name == name {
+			return ct
+		}
+	}
+	return nil
+}
+
+// run holds the state of one invocation.
+type run struct {
+	*Agent
+	inv      *agent.Invocation
+	ch       chan<- *event.Event
+	plan     *Plan
+	executed int
+}
+
+func (r *run) execute(ctx context.Context) error {
+	r.plan = &Plan{Goal: r.inv.Message.Content, Status: PlanStatusPlanning, Revision: 1, Current: -1}
+	reply, err := r.generateText(ctx, r.planPrompt(r.opts.maxSteps), "Goal: "+r.plan.Goal)
+	if err != nil {
+		return fmt.Errorf("plan: %w", err)
+	}
+	steps, err := r.parseSteps(reply)
+	if err != nil {
+		return fmt.Errorf("plan: %w", err)
+	}
+	r.plan.replace(steps)
+	r.plan.Status = PlanStatusRunning
+	if err := r.emitProgress(ctx); err != nil {
+		return err
+	}
+
+	replans := 0
+	for r.plan.Status == PlanStatusRunning {
+		i := r.plan.next()
+		if i < 0 {
+			r.plan.Status = PlanStatusCompleted
+			break
+		}
+		if r.executed >= r.opts.maxSteps {
+			r.fail(fmt.Sprintf("step budget of %d exhausted", r.opts.maxSteps))
+			break
+		}
+		reason, err := r.runStep(ctx, i)
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			continue
+		}
+		failed := r.plan.Steps[i].Status == StepStatusFailed
+		if replans >= r.opts.maxReplans {
+			if failed {
+				r.fail(reason)
+			}
+			continue
+		}
+		replans++
+		if err := r.replan(ctx, reason); err != nil {
+			return err
+		}
+	}
+	if err := r.emitProgress(ctx); err != nil {
+		return err
+	}
+	return r.answer(ctx)
+}
+
+// runStep executes, checks and records step i. It returns the reason to
+// replan, if any.
+func (r *run) runStep(ctx context.Context, i int) (string, error) {
+	r.executed++
+	r.plan.Current = i
+	r.plan.Steps[i].Status = StepStatusInProgress
+	if err := r.emitProgress(ctx); err != nil {
+		return "", err
+	}
+
+	result, execErr := r.executeStep(ctx, i)
+	if err := agent.CheckContextCancelled(ctx); err != nil {
+		return "", err
+	}
+	step := &r.plan.Steps[i]
+	r.plan.Current = -1
+	var reason string
+	switch {
+	case execErr != nil:
+		step.Status = StepStatusFailed
+		step.Error = execErr.Error()
+		reason = fmt.Sprintf("step %d failed: %s", step.ID, step.Error)
+	case r.opts.checkSteps:
+		step.Result = result
+		verdict, err := r.check(ctx, i)
+		if err != nil {
+			log.WarnfContext(ctx, "planexecute %s: check step %d: %v", r.name, step.ID, err)
+			step.Status = StepStatusCompleted
+			break
+		}
+		if !verdict.OK {
+			step.Status = StepStatusFailed
+			step.Error = verdict.Reason
+			reason = fmt.Sprintf("step %d failed: %s", step.ID, verdict.Reason)
+			break
+		}
+		step.Status = StepStatusCompleted
+		if verdict.Replan {
+			reason = fmt.Sprintf("step %d: %s", step.ID, verdict.Reason)
+		}
+	default:
+		step.Result = result
+		step.Status = StepStatusCompleted
+	}
+	return reason, r.emitProgress(ctx)
+}
+
+func (r *run) executeStep(ctx context.Context, i int) (string, error) {
+	step := r.plan.Steps[i]
+	if step.Executor == "" {
+		return r.generateText(ctx, r.withInstruction(stepPrompt), progressPrompt(r.plan, i))
+	}
+	if sub := r.FindSubAgent(step.Executor); sub != nil {
+		return r.delegate(ctx, sub, i)
+	}
+	if t := r.findTool(step.Executor); t != nil {
+		return r.callTool(ctx, t, i)
+	}
+	return "", fmt.Errorf("unknown executor %q", step.Executor)
+}
+
+// delegate runs step i on sub, forwarding its events, and returns the
+// content of its last complete response.
+func (r *run) delegate(ctx context.Context, sub agent.Agent, i int) (string, error) {
+	task := progressPrompt(r.plan, i) + "\nComplete the current step and report its result."
+	subInv := r.inv.Clone(
+		agent.WithInvocationAgent(sub),
+		agent.WithInvocationMessage(model.NewUserMessage(task)),
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := agent.RunWithPlugins(agent.NewInvocationContext(ctx, subInv), subInv, sub)
+	if err != nil {
+		return "", err
+	}
+	var result string
+	var runErr error
+	for evt := range events {
+		if err := event.EmitEvent(ctx, r.ch, evt); err != nil {
+			// Stop the sub-agent and drain its events so that it does not
+			// block forever on a send nobody receives.
+			cancel()
+			for range events {
+			}
+			return "", err
+		}
+		if evt == nil || evt.Response == nil || evt.IsPartial {
+			continue
+		}
+		if evt.Error != nil && runErr == nil {
+			runErr = fmt.Errorf("%s: %s", evt.Error.Type, evt.Error.Message)
+			continue
+		}
+		if evt.Author == subInv.AgentName && len(evt.Choices) > 0 &&
+			evt.Choices[0].Message.Content != "" {
+			result = evt.Choices[0].Message.Content
+		}
+	}
+	return result, runErr
+}
+
+func (r *run) check(ctx context.Context, i int) (checkReply, error) {
+	var verdict checkReply
+	reply, err := r.generateText(ctx, r.withInstruction(checkPrompt), progressPrompt(r.plan, i))
+	if err != nil {
+		return verdict, err
+	}
+	err = decodeJSON(reply, &verdict)
+	return verdict, err
+}
+
+func (r *run) replan(ctx context.Context, reason string) error {
+	budget := r.opts.maxSteps - r.executed
+	if budget <= 0 {
+		r.fail(reason)
+		return nil
+	}
+	reply, err := r.generateText(ctx, r.replanPrompt(budget),
+		progressPrompt(r.plan, -1)+"\nReason for replanning: "+reason)
+	if err == nil {
+		var steps []Step
+		if steps, err = r.parseSteps(reply); err == nil {
+			r.plan.replace(steps)
+			r.plan.Revision++
+			r.plan.Reason = reason
+			return r.emitProgress(ctx)
+		}
+	}
+	if cerr := agent.CheckContextCancelled(ctx); cerr != nil {
+		return cerr
+	}
+	r.fail(fmt.Sprintf("%s; replan failed: %v", reason, err))
+	return nil
+}
+
+func (r *run) fail(reason string) {
+	r.plan.Status = PlanStatusFailed
+	r.plan.Reason = reason
+	r.plan.Current = -1
+}
+
+// answer emits the final answer, written by the model from the step
+// results.
+func (r *run) answer(ctx context.Context) error {
+	user := progressPrompt(r.plan, -1)
+	if r.plan.Status == PlanStatusFailed {
+		user += "\nThe plan failed: " + r.plan.Reason
+	}
+	rsp, err := r.generate(ctx, r.withInstruction(finalPrompt), user)
+	if err != nil {
+		return fmt.Errorf("final answer: %w", err)
+	}
+	rsp.Done = true
+	return agent.EmitEvent(ctx, r.inv, r.ch, event.NewResponseEvent(r.inv.InvocationID, r.name, rsp))
+}
+
+// emitProgress stores the plan in session state and emits it as a plan
+// progress event.
+func (r *run) emitProgress(ctx context.Context) error {
+	raw, err := json.Marshal(r.plan)
+	if err != nil {
+		return fmt.Errorf("encode plan: %w", err)
+	}
+	key := StateKey(r.name)
+	evt := event.New(r.inv.InvocationID, r.name,
+		event.WithObject(model.ObjectTypePlanProgress),
+		event.WithStateDelta(map[string][]byte{key: raw}),
+		event.WithProgress(model.ObjectTypePlanProgress, map[string]any{
+			"agent": r.name,
+			"plan":  json.RawMessage(raw),
+		}),
+	)
+	if r.inv.Session != nil {
+		r.inv.Session.SetState(key, raw)
+	}
+	return agent.EmitEvent(ctx, r.inv, r.ch, evt)
+}
+
+func (r *run) generateText(ctx context.Context, system, user string) (string, error) {
+	rsp, err := r.generate(ctx, system, user)
+	if err != nil {
+		return "", err
+	}
+	return rsp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,414 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package planexecute
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+// scriptedModel answers requests with replies in order and records the
+// user prompts it received.
+type scriptedModel struct {
+	mu      sync.Mutex
+	replies []string
+	prompts []string
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, req *model.Request) (<-chan *model.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompts = append(m.prompts, req.Messages[len(req.Messages)-1].Content)
+	ch := make(chan *model.Response, 1)
+	defer close(ch)
+	if len(m.replies) == 0 {
+		ch <- &model.Response{Error: &model.ResponseError{Message: "script exhausted"}}
+		return ch, nil
+	}
+	reply := m.replies[0]
+	m.replies = m.replies[1:]
+	ch <- &model.Response{Choices: []model.Choice{{Message: model.NewAssistantMessage(reply)}}}
+	return ch, nil
+}
+
+func (m *scriptedModel) Info() model.Info {
+	return model.Info{Name: "scripted"}
+}
+
+// echoAgent answers every task with a fixed reply.
+type echoAgent struct {
+	name  string
+	reply string
+	tasks []string
+}
+
+func (a *echoAgent) Run(_ context.Context, inv *agent.Invocation) (<-chan *event.Event, error) {
+	a.tasks = append(a.tasks, inv.Message.Content)
+	ch := make(chan *event.Event, 1)
+	ch <- event.NewResponseEvent(inv.InvocationID, a.name, &model.Response{
+		Done:    true,
+		Choices: []model.Choice{{Message: model.NewAssistantMessage(a.reply)}},
+	})
+	close(ch)
+	return ch, nil
+}
+
+func (a *echoAgent) Tools() []tool.Tool              { return nil }
+func (a *echoAgent) Info() agent.Info                { return agent.Info{Name: a.name, Description: "researches"} }
+func (a *echoAgent) SubAgents() []agent.Agent        { return nil }
+func (a *echoAgent) FindSubAgent(string) agent.Agent { return nil }
+
+type addInput struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+func newAddTool() tool.Tool {
+	return function.NewFunctionTool(func(_ context.Context, in addInput) (int, error) {
+		if in.A < 0 {
+			return 0, errors.New("negative input")
+		}
+		return in.A + in.B, nil
+	}, function.WithName("add"), function.WithDescription("adds two numbers"))
+}
+
+func runAgent(t *testing.T, a *Agent, goal string) (*session.Session, []*event.Event) {
+	t.Helper()
+	sess := session.NewSession("app", "user", "session")
+	inv := agent.NewInvocation(
+		agent.WithInvocationSession(sess),
+		agent.WithInvocationMessage(model.NewUserMessage(goal)),
+	)
+	events, err := a.Run(context.Background(), inv)
+	require.NoError(t, err)
+	var got []*event.Event
+	for evt := range events {
+		got = append(got, evt)
+	}
+	return sess, got
+}
+
+func progress(t *testing.T, events []*event.Event, name string) []*Plan {
+	t.Helper()
+	var plans []*Plan
+	for _, evt := range events {
+		if evt.Object != model.ObjectTypePlanProgress {
+			continue
+		}
+		p, ok := DecodePlan(evt.StateDelta[StateKey(name)])
+		require.True(t, ok)
+		pg, ok := event.GetProgress(evt)
+		require.True(t, ok)
+		require.Equal(t, model.ObjectTypePlanProgress, pg.Name)
+		require.JSONEq(t, string(evt.StateDelta[StateKey(name)]), string(pg.Data["plan"]))
+		plans = append(plans, p)
+	}
+	return plans
+}
+
+func TestAgent_ExecutesPlan(t *testing.T) {
+	researcher := &echoAgent{name: "researcher", reply: "Paris has 2.1M people."}
+	m := &scriptedModel{replies: []string{
+		"```json\n" + `{"steps": [
+			{"description": "Look up the population", "executor": "researcher", "input": "population of Paris"},
+			{"description": "Add the suburbs", "executor": "add", "input": {"a": 2, "b": 3}},
+			{"description": "Summarize"}
+		]}` + "\n```",
+		`{"ok": true}`,
+		`{"ok": true}`,
+		"Paris is large.",
+		`{"ok": true}`,
+		"Paris has 2.1M people, 5M with suburbs.",
+	}}
+	a := New("planner", WithModel(m),
+		WithSubAgents([]agent.Agent{researcher}), WithTools([]tool.Tool{newAddTool()}))
+
+	sess, events := runAgent(t, a, "How big is Paris?")
+
+	plans := progress(t, events, "planner")
+	require.NotEmpty(t, plans)
+	final := plans[len(plans)-1]
+	assert.Equal(t, PlanStatusCompleted, final.Status)
+	require.Len(t, final.Steps, 3)
+	assert.Equal(t, "Paris has 2.1M people.", final.Steps[0].Result)
+	assert.Equal(t, "5", final.Steps[1].Result)
+	assert.Equal(t, "Paris is large.", final.Steps[2].Result)
+	for _, s := range final.Steps {
+		assert.Equal(t, StepStatusCompleted, s.Status)
+	}
+	assert.Equal(t, 0, plans[1].Current, "the first step runs first")
+	assert.Equal(t, StepStatusInProgress, plans[1].Steps[0].Status)
+
+	stored, ok := GetPlan(sess, "planner")
+	require.True(t, ok)
+	assert.Equal(t, final, stored)
+
+	require.Len(t, researcher.tasks, 1)
+	assert.Contains(t, researcher.tasks[0], "Current step 1: Look up the population")
+	assert.Contains(t, m.prompts[len(m.prompts)-1], "Result: 5")
+
+	last := events[len(events)-1]
+	assert.Equal(t, "planner", last.Author)
+	assert.True(t, last.Done)
+	assert.Equal(t, "Paris has 2.1M people, 5M with suburbs.", last.Choices[0].Message.Content)
+	assert.Contains(t, m.prompts[0], "How big is Paris?")
+}
+
+func TestAgent_Replans(t *testing.T) {
+	m := &scriptedModel{replies: []string{
+		`{"steps": [{"description": "Add", "executor": "add", "input": "{\"a\": -1, \"b\": 1}"},
+			{"description": "Report"}]}`,
+		`{"steps": [{"description": "Add again", "executor": "add", "input": "{\"a\": 1, \"b\": 1}"}]}`,
+		`{"ok": true, "replan": true, "reason": "the sum answers the goal"}`,
+		`{"steps": []}`,
+		"The sum is 2.",
+	}}
+	a := New("planner", WithModel(m), WithTools([]tool.Tool{newAddTool()}))
+
+	_, events := runAgent(t, a, "Add numbers")
+	final := progress(t, events, "planner")
+	p := final[len(final)-1]
+	assert.Equal(t, PlanStatusCompleted, p.Status)
+	assert.Equal(t, 3, p.Revision)
+	assert.Equal(t, "step 3: the sum answers the goal", p.Reason)
+	require.Len(t, p.Steps, 3)
+	assert.Equal(t, StepStatusFailed, p.Steps[0].Status)
+	assert.Contains(t, p.Steps[0].Error, "negative input")
+	assert.Equal(t, StepStatusSkipped, p.Steps[1].Status)
+	assert.Equal(t, 3, p.Steps[2].ID)
+	assert.Equal(t, "2", p.Steps[2].Result)
+	assert.Contains(t, m.prompts[1], "Reason for replanning: step 1 failed")
+	assert.Equal(t, "The sum is 2.", events[len(events)-1].Choices[0].Message.Content)
+}
+
+func TestAgent_FailsWithoutReplans(t *testing.T) {
+	m := &scriptedModel{replies: []string{
+		`{"steps": [{"description": "Guess"}, {"description": "Never runs"}]}`,
+		"42",
+		`{"ok": false, "reason": "not verified"}`,
+		"I could not verify the answer.",
+	}}
+	a := New("planner", WithModel(m), WithMaxReplans(0))
+
+	_, events := runAgent(t, a, "Answer")
+	plans := progress(t, events, "planner")
+	p := plans[len(plans)-1]
+	assert.Equal(t, PlanStatusFailed, p.Status)
+	assert.Equal(t, "step 1 failed: not verified", p.Reason)
+	assert.Equal(t, StepStatusPending, p.Steps[1].Status)
+	assert.True(t, strings.HasSuffix(m.prompts[len(m.prompts)-1],
+		"The plan failed: step 1 failed: not verified"))
+}
+
+func TestAgent_Errors(t *testing.T) {
+	_, err := New("planner").Run(context.Background(), agent.NewInvocation())
+	require.Error(t, err)
+
+	m := &scriptedModel{replies: []string{`{"steps": [{"description": "x", "executor": "missing"}]}`}}
+	_, events := runAgent(t, New("planner", WithModel(m)), "goal")
+	require.Len(t, events, 1)
+	require.NotNil(t, events[0].Error)
+	assert.Contains(t, events[0].Error.Message, `unknown executor "missing"`)
+
+	m = &scriptedModel{replies: []string{
+		`{"steps": [{"description": "a"}, {"description": "b"}, {"description": "c"}]}`,
+		"a done", "b done", "done",
+	}}
+	_, events = runAgent(t, New("planner", WithModel(m), WithMaxSteps(2), WithCheckSteps(false)), "goal")
+	plans := progress(t, events, "planner")
+	p := plans[len(plans)-1]
+	assert.Equal(t, PlanStatusFailed, p.Status)
+	assert.Equal(t, "step budget of 2 exhausted", p.Reason)
+	assert.Equal(t, "done", events[len(events)-1].Choices[0].Message.Content)
+}
+
+// recordingPlugin records the model and tool calls it sees.
+type recordingPlugin struct {
+	mu     sync.Mutex
+	models int
+	tools  []string
+}
+
+func (p *recordingPlugin) Name() string { return "recording" }
+
+func (p *recordingPlugin) Register(r *plugin.Registry) {
+	r.BeforeModel(func(context.Context, *model.BeforeModelArgs) (*model.BeforeModelResult, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.models++
+		return nil, nil
+	})
+	r.BeforeTool(func(_ context.Context, args *tool.BeforeToolArgs) (*tool.BeforeToolResult, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.tools = append(p.tools, args.ToolName)
+		return nil, nil
+	})
+}
+
+func TestAgent_Callbacks(t *testing.T) {
+	m := &scriptedModel{replies: []string{
+		`{"steps": [{"description": "Add", "executor": "add", "input": {"a": 1, "b": 1}}]}`,
+		"The sum is 4.",
+	}}
+	// The model never sees the check: a callback answers it.
+	modelCallbacks := model.NewCallbacks().RegisterBeforeModel(
+		func(_ context.Context, args *model.BeforeModelArgs) (*model.BeforeModelResult, error) {
+			if strings.HasPrefix(args.Request.Messages[0].Content, "You review") {
+				return &model.BeforeModelResult{CustomResponse: &model.Response{
+					Choices: []model.Choice{{Message: model.NewAssistantMessage(`{"ok": true}`)}},
+				}}, nil
+			}
+			return nil, nil
+		})
+	var afterTool []any
+	toolCallbacks := tool.NewCallbacks().
+		RegisterBeforeTool(func(context.Context, *tool.BeforeToolArgs) (*tool.BeforeToolResult, error) {
+			return &tool.BeforeToolResult{ModifiedArguments: []byte(`{"a": 2, "b": 2}`)}, nil
+		}).
+		RegisterAfterTool(func(_ context.Context, args *tool.AfterToolArgs) (*tool.AfterToolResult, error) {
+			afterTool = append(afterTool, args.Result)
+			return nil, nil
+		})
+	a := New("planner", WithModel(m), WithTools([]tool.Tool{newAddTool()}),
+		WithModelCallbacks(modelCallbacks), WithToolCallbacks(toolCallbacks))
+	rec := &recordingPlugin{}
+	plugins, err := plugin.NewManager(rec)
+	require.NoError(t, err)
+
+	inv := agent.NewInvocation(
+		agent.WithInvocationSession(session.NewSession("app", "user", "session")),
+		agent.WithInvocationMessage(model.NewUserMessage("Add numbers")),
+	)
+	inv.Plugins = plugins
+	ch, err := a.Run(context.Background(), inv)
+	require.NoError(t, err)
+	var events []*event.Event
+	for evt := range ch {
+		events = append(events, evt)
+	}
+
+	plans := progress(t, events, "planner")
+	p := plans[len(plans)-1]
+	assert.Equal(t, PlanStatusCompleted, p.Status)
+	assert.Equal(t, "4", p.Steps[0].Result)
+	assert.Equal(t, []any{4}, afterTool)
+	assert.Len(t, m.prompts, 2, "the check is answered by the callback")
+	assert.Equal(t, 3, rec.models)
+	assert.Equal(t, []string{"add"}, rec.tools)
+
+	var call, result *event.Event
+	for _, evt := range events {
+		switch {
+		case evt.Response != nil && evt.IsToolCallResponse():
+			call = evt
+		case evt.Response != nil && evt.IsToolResultResponse():
+			result = evt
+		}
+	}
+	require.NotNil(t, call)
+	require.NotNil(t, result)
+	toolCall := call.Choices[0].Message.ToolCalls[0]
+	assert.Equal(t, "add", toolCall.Function.Name)
+	assert.Equal(t, toolCall.ID, result.Choices[0].Message.ToolID)
+	assert.Equal(t, "4", result.Choices[0].Message.Content)
+	assert.Equal(t, "The sum is 4.", events[len(events)-1].Choices[0].Message.Content)
+}
+
+// blockingAgent sends count events without watching its context and
+// closes done when it returns.
+type blockingAgent struct {
+	name  string
+	count int
+	done  chan struct{}
+}
+
+func (b *blockingAgent) Run(_ context.Context, inv *agent.Invocation) (<-chan *event.Event, error) {
+	ch := make(chan *event.Event)
+	go func() {
+		defer close(b.done)
+		defer close(ch)
+		for i := 0; i < b.count; i++ {
+			ch <- event.New(inv.InvocationID, b.name)
+		}
+	}()
+	return ch, nil
+}
+
+func (b *blockingAgent) Tools() []tool.Tool              { return nil }
+func (b *blockingAgent) Info() agent.Info                { return agent.Info{Name: b.name} }
+func (b *blockingAgent) SubAgents() []agent.Agent        { return nil }
+func (b *blockingAgent) FindSubAgent(string) agent.Agent { return nil }
+
+// blockingModel sends an error and then count more responses without
+// watching its context, and closes done when it returns.
+type blockingModel struct {
+	count int
+	done  chan struct{}
+}
+
+func (m *blockingModel) GenerateContent(context.Context, *model.Request) (<-chan *model.Response, error) {
+	ch := make(chan *model.Response)
+	go func() {
+		defer close(m.done)
+		defer close(ch)
+		ch <- &model.Response{Error: &model.ResponseError{Message: "overloaded"}}
+		for i := 0; i < m.count; i++ {
+			ch <- &model.Response{Choices: []model.Choice{{Message: model.NewAssistantMessage("late")}}}
+		}
+	}()
+	return ch, nil
+}
+
+func (m *blockingModel) Info() model.Info { return model.Info{Name: "blocking"} }
+
+func TestRun_DrainsOnEarlyReturn(t *testing.T) {
+	waitDone := func(done <-chan struct{}, what string) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s blocked after the run returned", what)
+		}
+	}
+
+	sub := &blockingAgent{name: "sub", count: 3, done: make(chan struct{})}
+	r := &run{
+		Agent: New("planner", WithSubAgents([]agent.Agent{sub})),
+		inv:   agent.NewInvocation(),
+		ch:    make(chan *event.Event),
+		plan:  &Plan{Goal: "goal", Steps: []Step{{ID: 1, Description: "step", Executor: "sub"}}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := r.delegate(ctx, sub, 0)
+	require.ErrorIs(t, err, context.Canceled)
+	waitDone(sub.done, "sub-agent")
+
+	m := &blockingModel{count: 3, done: make(chan struct{})}
+	r = &run{Agent: New("planner", WithModel(m)), inv: agent.NewInvocation()}
+	_, err = r.generate(context.Background(), "system", "user")
+	require.ErrorContains(t, err, "overloaded")
+	waitDone(m.done, "model")
+}
@@ -0,0 +1,187 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package planexecute
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const stepsFormat = `Reply with JSON only, in this format:
+{"steps": [{"description": "what the step achieves", "executor": "executor name or empty", "input": "task or JSON arguments for the executor"}]}`
+
+// executor describes a sub-agent or tool the planner may assign steps to.
+type executor struct {
+	name        string
+	kind        string
+	description string
+	parameters  string
+}
+
+func (a *Agent) executorsPrompt() string {
+	var b strings.Builder
+	b.WriteString("Executors:\n")
+	b.WriteString("- \"\" (empty): answer the step yourself with reasoning.\n")
+	for _, e := range a.executors {
+		fmt.Fprintf(&b, "- %q (%s): %s", e.name, e.kind, e.description)
+		if e.parameters != "" {
+			fmt.Fprintf(&b, " JSON arguments schema: %s", e.parameters)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("For agents the input is the task for the agent. " +
+		"For tools the input is a JSON object of arguments.\n")
+	return b.String()
+}
+
+func (a *Agent) withInstruction(prompt string) string {
+	if a.opts.instruction == "" {
+		return prompt
+	}
+	return prompt + "\n" + a.opts.instruction
+}
+
+func (a *Agent) planPrompt(budget int) string {
+	return a.withInstruction(fmt.Sprintf(
+		"You are a planner. Break the user's goal into at most %d concrete, "+
+			"ordered steps. Each step is executed on its own, in order, and "+
+			"sees the results of the previous steps. Do not add a step for "+
+			"writing the final answer.\n%s%s",
+		budget, a.executorsPrompt(), stepsFormat,
+	))
+}
+
+func (a *Agent) replanPrompt(budget int) string {
+	return a.withInstruction(fmt.Sprintf(
+		"You are a planner revising a plan during execution. Given the goal, "+
+			"the steps run so far with their results and the reason for "+
+			"replanning, list at most %d new steps that complete the goal. "+
+			"Return an empty list when the goal is already achieved.\n%s%s",
+		budget, a.executorsPrompt(), stepsFormat,
+	))
+}
+
+const checkPrompt = `You review the result of one step of a plan.
+Decide whether the step achieved its purpose and whether the remaining steps still make sense given the result.
+Reply with JSON only, in this format:
+{"ok": true, "replan": false, "reason": "short explanation"}
+Set "ok" to false when the step failed. Set "replan" to true when the result reveals information that makes the remaining steps wrong or unnecessary.`
+
+const stepPrompt = `You execute one step of a plan. Do only this step and report its result concisely.`
+
+const finalPrompt = `You have executed a plan for the user's goal. Using the step results, write the final answer for the user. If some steps failed, say what could not be done.`
+
+// progressPrompt renders the goal, the steps run so far and, when current
+// is a valid index, the current step.
+func progressPrompt(p *Plan, current int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Goal: %s\n", p.Goal)
+	var done bool
+	for i, s := range p.Steps {
+		if i == current || (s.Status != StepStatusCompleted && s.Status != StepStatusFailed) {
+			continue
+		}
+		if !done {
+			b.WriteString("\nSteps run so far:\n")
+			done = true
+		}
+		fmt.Fprintf(&b, "%d. %s [%s]\n", s.ID, s.Description, s.Status)
+		if s.Result != "" {
+			fmt.Fprintf(&b, "   Result: %s\n", s.Result)
+		}
+		if s.Error != "" {
+			fmt.Fprintf(&b, "   Error: %s\n", s.Error)
+		}
+	}
+	if current >= 0 && current < len(p.Steps) {
+		s := p.Steps[current]
+		fmt.Fprintf(&b, "\nCurrent step %d: %s\n", s.ID, s.Description)
+		if s.Input != "" {
+			fmt.Fprintf(&b, "Input: %s\n", s.Input)
+		}
+		if s.Result != "" {
+			fmt.Fprintf(&b, "Result: %s\n", s.Result)
+		}
+	}
+	return b.String()
+}
+
+type stepsReply struct {
+	Steps []struct {
+		Description string          `json:"description"`
+		Executor    string          `json:"executor"`
+		Input       json.RawMessage `json:"input"`
+	} `json:"steps"`
+}
+
+// parseSteps parses a planner reply and validates its executors.
+func (a *Agent) parseSteps(reply string) ([]Step, error) {
+	var r stepsReply
+	if err := decodeJSON(reply, &r); err != nil {
+		return nil, err
+	}
+	steps := make([]Step, 0, len(r.Steps))
+	for _, s := range r.Steps {
+		if strings.TrimSpace(s.Description) == "" {
+			return nil, errors.New("step without description")
+		}
+		if s.Executor != "" && a.findExecutor(s.Executor) == nil {
+			return nil, fmt.Errorf("unknown executor %q", s.Executor)
+		}
+		steps = append(steps, Step{
+			Description: s.Description,
+			Executor:    s.Executor,
+			Input:       rawInput(s.Input),
+		})
+	}
+	return steps, nil
+}
+
+// rawInput accepts the input either as a string or as a JSON value, since
+// models often inline tool arguments as objects.
+func rawInput(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+type checkReply struct {
+	OK     bool   `json:"ok"`
+	Replan bool   `json:"replan"`
+	Reason string `json:"reason"`
+}
+
+// decodeJSON decodes the JSON object in reply, tolerating code fences and
+// surrounding prose.
+func decodeJSON(reply string, v any) error {
+	start := strings.IndexByte(reply, '{')
+	end := strings.LastIndexByte(reply, '}')
+	if start < 0 || end < start {
+		return fmt.Errorf("no JSON object in reply %q", truncate(reply, 200))
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), v); err != nil {
+		return fmt.Errorf("decode reply: %w", err)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -15,6 +15,7 @@ import (
 
 	"trpc.group/trpc-go/trpc-agent-go/plugin"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/approval"
+	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/pii"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/promptinjection"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/unsafeintent"
 )
@@ -23,6 +24,7 @@ import (
 type Plugin struct {
 	name            string
 	approval        *approval.Plugin
+	pii             *pii.Plugin
 	promptInjection *promptinjection.Plugin
 	unsafeIntent    *unsafeintent.Plugin
 }
@@ -33,6 +35,7 @@ func New(options ...Option) (*Plugin, error) {
 	return &Plugin{
 		name:            opts.name,
 		approval:        opts.approval,
+		pii:             opts.pii,
 		promptInjection: opts.promptInjection,
 		unsafeIntent:    opts.unsafeIntent,
 	}, nil
@@ -48,6 +51,11 @@ func (p *Plugin) Register(r *plugin.Registry) {
 	if p == nil || r == nil {
 		return
 	}
+	// PII runs first so that reviewers and later hooks only see redacted
+	// data.
+	if p.pii != nil {
+		p.pii.Register(r)
+	}
 	if p.unsafeIntent != nil {
 		p.unsafeIntent.Register(r)
 	}
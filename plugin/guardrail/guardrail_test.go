@@ -17,6 +17,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/plugin"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/approval"
 	approvalreview "trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/approval/review"
+	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/pii"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/promptinjection"
 	promptreview "trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/promptinjection/review"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/unsafeintent"
@@ -122,3 +123,23 @@ func TestRegister_ForwardsUnsafeIntentRegistration(t *testing.T) {
 	require.NoError(t, runErr)
 	require.Nil(t, result)
 }
+
+func TestRegister_ForwardsPIIRegistration(t *testing.T) {
+	piiPlugin, err := pii.New()
+	require.NoError(t, err)
+	p, err := New(WithPII(piiPlugin))
+	require.NoError(t, err)
+	manager, err := plugin.NewManager(p)
+	require.NoError(t, err)
+	req := &model.Request{
+		Messages: []model.Message{{
+			Role:    model.RoleUser,
+			Content: "Mail me at jane@example.com.",
+		}},
+	}
+	_, runErr := manager.ModelCallbacks().RunBeforeModel(context.Background(), &model.BeforeModelArgs{
+		Request: req,
+	})
+	require.NoError(t, runErr)
+	require.Equal(t, "Mail me at [REDACTED:email].", req.Messages[0].Content)
+}
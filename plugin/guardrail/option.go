@@ -10,6 +10,7 @@ package guardrail
 
 import (
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/approval"
+	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/pii"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/promptinjection"
 	"trpc.group/trpc-go/trpc-agent-go/plugin/guardrail/unsafeintent"
 )
@@ -22,6 +23,7 @@ type Option func(*options)
 type options struct {
 	name            string
 	approval        *approval.Plugin
+	pii             *pii.Plugin
 	promptInjection *promptinjection.Plugin
 	unsafeIntent    *unsafeintent.Plugin
 }
@@ -52,6 +54,13 @@ func WithApproval(approvalPlugin *approval.Plugin) Option {
 	}
 }
 
+// WithPII attaches the PII detection and redaction capability.
+func WithPII(piiPlugin *pii.Plugin) Option {
+	return func(opts *options) {
+		opts.pii = piiPlugin
+	}
+}
+
 // WithPromptInjection attaches the prompt injection capability.
 func WithPromptInjection(promptInjectionPlugin *promptinjection.Plugin) Option {
 	return func(opts *options) {
@@ -0,0 +1,333 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Entity identifies a kind of personal data.
+type Entity string
+
+const (
+	// EntityEmail is an email address.
+	EntityEmail Entity = "email"
+	// EntityPhone is a phone number.
+	EntityPhone Entity = "phone"
+	// EntityPaymentCard is a Luhn-valid payment card number.
+	EntityPaymentCard Entity = "payment_card"
+	// EntityIBAN is a checksum-valid international bank account number.
+	EntityIBAN Entity = "iban"
+	// EntityNationalID is a national identity number. US social security
+	// numbers and checksum-valid Chinese resident identity numbers are
+	// recognized.
+	EntityNationalID Entity = "national_id"
+)
+
+// builtinEntities lists the built-in entities in detection priority order.
+// When matches overlap, the earlier entity wins at equal length.
+var builtinEntities = []Entity{
+	EntityEmail,
+	EntityIBAN,
+	EntityPaymentCard,
+	EntityNationalID,
+	EntityPhone,
+}
+
+// Match is one detected occurrence of personal data.
+type Match struct {
+	// Entity is the detected entity.
+	Entity Entity
+	// Start and End are the byte offsets of Value in the scanned text.
+	Start int
+	End   int
+	// Value is the matched text.
+	Value string
+}
+
+type recognizer struct {
+	entity  Entity
+	pattern *regexp.Regexp
+	// valid filters candidate matches. A nil valid accepts every match.
+	valid func(string) bool
+}
+
+var builtinRecognizers = map[Entity][]recognizer{
+	EntityEmail: {{
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	}},
+	EntityIBAN: {
+		{
+			pattern: regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`),
+			valid:   validIBAN,
+		},
+		{
+			// Printed form in groups of four. The last group is digits only
+			// so that a following word is not taken for it.
+			pattern: regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: [A-Z0-9]{4}){2,7}(?: [0-9]{1,3})?\b`),
+			valid:   validIBAN,
+		},
+	},
+	EntityPaymentCard: {{
+		pattern: regexp.MustCompile(`\b(?:[0-9][ -]?){12,18}[0-9]\b`),
+		valid:   validPaymentCard,
+	}},
+	EntityNationalID: {
+		{
+			pattern: regexp.MustCompile(`\b[0-9]{3}-[0-9]{2}-[0-9]{4}\b`),
+			valid:   validSSN,
+		},
+		{
+			pattern: regexp.MustCompile(`\b[0-9]{17}[0-9Xx]\b`),
+			valid:   validResidentID,
+		},
+	},
+	EntityPhone: {{
+		pattern: regexp.MustCompile(
+			`(?:\+[0-9]{1,3}[ .-]?(?:\([0-9]{1,4}\)[ .-]?)?|\([0-9]{1,4}\)[ .-]?|\b)` +
+				`[0-9]{2,4}(?:[ .-]?[0-9]{2,4}){1,4}\b`,
+		),
+		valid: validPhone,
+	}},
+}
+
+// detector finds personal data with built-in and custom recognizers.
+type detector struct {
+	recognizers []recognizer
+}
+
+func newDetector(opts *options) (*detector, error) {
+	d := &detector{}
+	enabled := opts.entities
+	if enabled == nil {
+		enabled = builtinEntities
+	}
+	for _, entity := range builtinEntities {
+		if !containsEntity(enabled, entity) {
+			continue
+		}
+		for _, r := range builtinRecognizers[entity] {
+			r.entity = entity
+			d.recognizers = append(d.recognizers, r)
+		}
+	}
+	for _, entity := range enabled {
+		if _, ok := builtinRecognizers[entity]; !ok {
+			return nil, fmt.Errorf("unknown built-in entity %q", entity)
+		}
+	}
+	for _, p := range opts.patterns {
+		if p.entity == "" {
+			return nil, fmt.Errorf("custom pattern %q has an empty entity", p.pattern)
+		}
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom entity %q: %w", p.entity, err)
+		}
+		d.recognizers = append(d.recognizers, recognizer{entity: p.entity, pattern: re})
+	}
+	for _, dict := range opts.dictionaries {
+		if dict.entity == "" {
+			return nil, fmt.Errorf("dictionary has an empty entity")
+		}
+		re := dictionaryPattern(dict.terms)
+		if re == nil {
+			continue
+		}
+		d.recognizers = append(d.recognizers, recognizer{entity: dict.entity, pattern: re})
+	}
+	return d, nil
+}
+
+// detect returns the non-overlapping matches in text ordered by position.
+// Overlaps keep the longest match, then the earliest recognizer.
+func (d *detector) detect(text string) []Match {
+	if d == nil || strings.TrimSpace(text) == "" {
+		return nil
+	}
+	type candidate struct {
+		Match
+		priority int
+	}
+	var candidates []candidate
+	for i, r := range d.recognizers {
+		for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+			value := text[loc[0]:loc[1]]
+			if loc[0] == loc[1] || (r.valid != nil && !r.valid(value)) {
+				continue
+			}
+			candidates = append(candidates, candidate{
+				Match:    Match{Entity: r.entity, Start: loc[0], End: loc[1], Value: value},
+				priority: i,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		li := candidates[i].End - candidates[i].Start
+		lj := candidates[j].End - candidates[j].Start
+		if li != lj {
+			return li > lj
+		}
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].Start < candidates[j].Start
+	})
+	var matches []Match
+	for _, c := range candidates {
+		overlaps := false
+		for _, m := range matches {
+			if c.Start < m.End && m.Start < c.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			matches = append(matches, c.Match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// dictionaryPattern matches any of terms case-insensitively. Word boundaries
+// are required on the sides of a term that start or end with a word
+// character.
+func dictionaryPattern(terms []string) *regexp.Regexp {
+	alternatives := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		alt := regexp.QuoteMeta(term)
+		if isWordRune(rune(term[0])) {
+			alt = `\b` + alt
+		}
+		if isWordRune(rune(term[len(term)-1])) {
+			alt += `\b`
+		}
+		alternatives = append(alternatives, alt)
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	// Longer terms first so that a term is not shadowed by its prefix.
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		return len(alternatives[i]) > len(alternatives[j])
+	})
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(alternatives, "|") + `)`)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func containsEntity(entities []Entity, entity Entity) bool {
+	for _, e := range entities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
+
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func validPaymentCard(s string) bool {
+	digits := digitsOf(s)
+	return len(digits) >= 13 && len(digits) <= 19 && luhn(digits)
+}
+
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&numeric, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func validSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' &&
+		group != "00" && serial != "0000"
+}
+
+var residentIDWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+func validResidentID(s string) bool {
+	sum := 0
+	for i, w := range residentIDWeights {
+		sum += int(s[i]-'0') * w
+	}
+	return "10X98765432"[sum%11] == s[17] || (s[17] == 'x' && sum%11 == 2)
+}
+
+var (
+	datePattern = regexp.MustCompile(`^[0-9]{4}[-./][0-9]{1,2}[-./][0-9]{1,2}$`)
+	ssnPattern  = regexp.MustCompile(`^[0-9]{3}-[0-9]{2}-[0-9]{4}$`)
+)
+
+// validPhone accepts 9 to 15 digits, which excludes dates and most short
+// numbers, or an explicit international prefix. SSN-shaped numbers are left
+// to the national ID recognizer.
+func validPhone(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 7 || len(digits) > 15 ||
+		datePattern.MatchString(s) || ssnPattern.MatchString(s) {
+		return false
+	}
+	return len(digits) >= 9 || strings.HasPrefix(s, "+")
+}
@@ -0,0 +1,87 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect_BuiltinEntities(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	cases := []struct {
+		text   string
+		entity Entity
+		value  string
+	}{
+		{"write to jane.doe+work@mail.example.co.uk today", EntityEmail, "jane.doe+work@mail.example.co.uk"},
+		{"call +1 (415) 555-0132 now", EntityPhone, "+1 (415) 555-0132"},
+		{"mobile 13800138000.", EntityPhone, "13800138000"},
+		{"card 4111 1111 1111 1111 exp 12/29", EntityPaymentCard, "4111 1111 1111 1111"},
+		{"card 4111-1111-1111-1111", EntityPaymentCard, "4111-1111-1111-1111"},
+		{"iban GB82 WEST 1234 5698 7654 32 please", EntityIBAN, "GB82 WEST 1234 5698 7654 32"},
+		{"iban DE89370400440532013000", EntityIBAN, "DE89370400440532013000"},
+		{"ssn 123-45-6789", EntityNationalID, "123-45-6789"},
+		{"id 11010519491231002X", EntityNationalID, "11010519491231002X"},
+	}
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			matches := p.Detect(c.text)
+			require.Len(t, matches, 1)
+			require.Equal(t, c.entity, matches[0].Entity)
+			require.Equal(t, c.value, matches[0].Value)
+			require.Equal(t, c.value, c.text[matches[0].Start:matches[0].End])
+		})
+	}
+}
+
+func TestDetect_RejectsInvalidCandidates(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	for _, text := range []string{
+		"order 4111 1111 1111 1112 shipped", // fails Luhn, too long for a phone
+		"released on 2024-10-18",
+		"version 1.2.3",
+		"GB82WEST12345698765433", // bad IBAN checksum
+		"ssn 000-12-3456",
+		"id 110105194912310021", // bad resident ID checksum
+		"code 12345",
+	} {
+		require.Empty(t, p.Detect(text), text)
+	}
+}
+
+func TestDetect_CustomEntities(t *testing.T) {
+	p, err := New(
+		WithEntities(EntityEmail),
+		WithPattern("employee_id", `\bEMP-[0-9]{6}\b`),
+		WithDictionary("customer", "Acme Corp", "Acme"),
+	)
+	require.NoError(t, err)
+	matches := p.Detect("EMP-004211 from ACME CORP, not Acmeville, phone 13800138000")
+	require.Equal(t, []Match{
+		{Entity: "employee_id", Start: 0, End: 10, Value: "EMP-004211"},
+		{Entity: "customer", Start: 16, End: 25, Value: "ACME CORP"},
+	}, matches)
+}
+
+func TestNew_ValidatesOptions(t *testing.T) {
+	_, err := New(WithAction("drop"))
+	require.ErrorContains(t, err, "default action")
+	_, err = New(WithEntityAction(EntityEmail, ""))
+	require.ErrorContains(t, err, "email")
+	_, err = New(WithEntities("passport"))
+	require.ErrorContains(t, err, "passport")
+	_, err = New(WithPattern("bad", "("))
+	require.ErrorContains(t, err, "bad")
+	_, err = New(WithScopes("logs"))
+	require.ErrorContains(t, err, "logs")
+}
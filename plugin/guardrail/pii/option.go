@@ -0,0 +1,136 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+const defaultPluginName = "pii"
+
+// Option configures the PII plugin.
+type Option func(*options)
+
+type options struct {
+	name           string
+	entities       []Entity
+	patterns       []customPattern
+	dictionaries   []dictionary
+	defaultAction  Action
+	entityActions  map[Entity]Action
+	scopes         []Scope
+	restoreTools   map[string]bool
+	placeholderKey []byte
+}
+
+type customPattern struct {
+	entity  Entity
+	pattern string
+}
+
+type dictionary struct {
+	entity Entity
+	terms  []string
+}
+
+func newOptions(opts ...Option) *options {
+	options := &options{
+		name:          defaultPluginName,
+		defaultAction: ActionMask,
+		entityActions: make(map[Entity]Action),
+		scopes:        allScopes,
+		restoreTools:  make(map[string]bool),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	return options
+}
+
+// WithName sets the plugin name.
+func WithName(name string) Option {
+	return func(opts *options) {
+		opts.name = name
+	}
+}
+
+// WithEntities limits detection to the given built-in entities. All
+// built-in entities are detected by default. Custom entities are always
+// detected.
+func WithEntities(entities ...Entity) Option {
+	return func(opts *options) {
+		opts.entities = append([]Entity{}, entities...)
+	}
+}
+
+// WithPattern adds a custom entity detected by a regular expression.
+func WithPattern(entity Entity, pattern string) Option {
+	return func(opts *options) {
+		opts.patterns = append(opts.patterns, customPattern{entity: entity, pattern: pattern})
+	}
+}
+
+// WithDictionary adds a custom entity detected by case-insensitive whole
+// word matches of terms, such as customer or project names.
+func WithDictionary(entity Entity, terms ...string) Option {
+	return func(opts *options) {
+		opts.dictionaries = append(opts.dictionaries, dictionary{
+			entity: entity,
+			terms:  append([]string{}, terms...),
+		})
+	}
+}
+
+// WithAction sets the action applied to entities without an explicit
+// action. The default is ActionMask.
+func WithAction(action Action) Option {
+	return func(opts *options) {
+		opts.defaultAction = action
+	}
+}
+
+// WithEntityAction sets the action applied to one entity.
+func WithEntityAction(entity Entity, action Action) Option {
+	return func(opts *options) {
+		if opts.entityActions == nil {
+			opts.entityActions = make(map[Entity]Action)
+		}
+		opts.entityActions[entity] = action
+	}
+}
+
+// WithScopes limits the places the plugin inspects. All scopes are
+// inspected by default.
+func WithScopes(scopes ...Scope) Option {
+	return func(opts *options) {
+		opts.scopes = append([]Scope{}, scopes...)
+	}
+}
+
+// WithRestoreTools allowlists tools whose arguments get placeholders
+// replaced by the original values before execution. Other tools only ever
+// see placeholders.
+func WithRestoreTools(names ...string) Option {
+	return func(opts *options) {
+		if opts.restoreTools == nil {
+			opts.restoreTools = make(map[string]bool)
+		}
+		for _, name := range names {
+			opts.restoreTools[name] = true
+		}
+	}
+}
+
+// WithPlaceholderKey sets the key used to derive placeholders. Equal values
+// map to equal placeholders under the same key, so a fixed key keeps the
+// placeholders in session history consistent across restarts and replicas.
+// A random key is generated by default.
+func WithPlaceholderKey(key []byte) Option {
+	return func(opts *options) {
+		opts.placeholderKey = append([]byte{}, key...)
+	}
+}
@@ -0,0 +1,421 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+// Package pii provides a runner-scoped guardrail plugin that detects
+// personal data and blocks, masks or replaces it with reversible
+// placeholders.
+//
+// Detection is deterministic: emails, phone numbers, Luhn-checked payment
+// cards, IBANs, national ID numbers and custom regular expression or
+// dictionary entities. Placeholders are restored only in the arguments of
+// allowlisted tools, so the model and every other tool see placeholders
+// while the allowlisted tools receive the original values.
+package pii
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// Scope identifies a place where the plugin inspects data.
+type Scope string
+
+const (
+	// ScopeUserInput inspects user messages before they reach the model.
+	ScopeUserInput Scope = "user_input"
+	// ScopeModelOutput inspects model responses.
+	ScopeModelOutput Scope = "model_output"
+	// ScopeToolArguments inspects tool call arguments before execution.
+	ScopeToolArguments Scope = "tool_arguments"
+	// ScopeToolResults inspects tool results before they reach the model.
+	ScopeToolResults Scope = "tool_results"
+	// ScopeTelemetry inspects span attributes exported through
+	// Plugin.SpanExporter.
+	ScopeTelemetry Scope = "telemetry"
+)
+
+var allScopes = []Scope{
+	ScopeUserInput,
+	ScopeModelOutput,
+	ScopeToolArguments,
+	ScopeToolResults,
+	ScopeTelemetry,
+}
+
+// Plugin is the PII guardrail implementation.
+type Plugin struct {
+	name           string
+	detector       *detector
+	defaultAction  Action
+	entityActions  map[Entity]Action
+	scopes         map[Scope]bool
+	restoreTools   map[string]bool
+	placeholderKey []byte
+	vaultKey       string
+	streamKey      string
+	stateMu        sync.Mutex
+}
+
+// New creates a new PII plugin.
+func New(options ...Option) (*Plugin, error) {
+	opts := newOptions(options...)
+	if err := validateAction(opts.defaultAction); err != nil {
+		return nil, fmt.Errorf("newing pii plugin: default action: %w", err)
+	}
+	for entity, action := range opts.entityActions {
+		if err := validateAction(action); err != nil {
+			return nil, fmt.Errorf("newing pii plugin: entity %q action: %w", entity, err)
+		}
+	}
+	scopes := make(map[Scope]bool, len(opts.scopes))
+	for _, scope := range opts.scopes {
+		if !containsScope(allScopes, scope) {
+			return nil, fmt.Errorf("newing pii plugin: invalid scope %q", scope)
+		}
+		scopes[scope] = true
+	}
+	d, err := newDetector(opts)
+	if err != nil {
+		return nil, fmt.Errorf("newing pii plugin: %w", err)
+	}
+	key := opts.placeholderKey
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("newing pii plugin: generate placeholder key: %w", err)
+		}
+	}
+	return &Plugin{
+		name:           opts.name,
+		detector:       d,
+		defaultAction:  opts.defaultAction,
+		entityActions:  opts.entityActions,
+		scopes:         scopes,
+		restoreTools:   opts.restoreTools,
+		placeholderKey: key,
+		vaultKey:       "pii:" + opts.name + ":placeholders",
+		streamKey:      "pii:" + opts.name + ":stream",
+	}, nil
+}
+
+// Name implements plugin.Plugin.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Register implements plugin.Plugin.
+func (p *Plugin) Register(r *plugin.Registry) {
+	if p == nil || r == nil {
+		return
+	}
+	if p.scopes[ScopeUserInput] || p.scopes[ScopeToolResults] {
+		r.BeforeModel(p.beforeModel())
+	}
+	if p.scopes[ScopeModelOutput] {
+		r.AfterModel(p.afterModel())
+	}
+	if p.scopes[ScopeToolArguments] || len(p.restoreTools) > 0 {
+		r.BeforeTool(p.beforeTool())
+	}
+	if p.scopes[ScopeToolResults] {
+		r.AfterTool(p.afterTool())
+	}
+}
+
+// Detect returns the personal data found in text ordered by position.
+func (p *Plugin) Detect(text string) []Match {
+	return p.detector.detect(text)
+}
+
+// Redact returns text with its personal data masked or replaced by
+// placeholders. Entities configured with ActionBlock are masked.
+func (p *Plugin) Redact(text string) string {
+	redacted, _ := p.redactText(text, nil)
+	return redacted
+}
+
+func (p *Plugin) beforeModel() model.BeforeModelCallbackStructured {
+	return func(ctx context.Context, args *model.BeforeModelArgs) (*model.BeforeModelResult, error) {
+		if p == nil || args == nil || args.Request == nil || len(args.Request.Messages) == 0 {
+			return nil, nil
+		}
+		v := p.vault(ctx)
+		messages := args.Request.Messages
+		lastUser := -1
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role == model.RoleUser {
+				lastUser = i
+				break
+			}
+		}
+		var redacted []model.Message
+		for i, msg := range messages {
+			switch {
+			case msg.Role == model.RoleUser && p.scopes[ScopeUserInput]:
+			case msg.Role == model.RoleTool && p.scopes[ScopeToolResults]:
+			default:
+				continue
+			}
+			next, blocked, changed := p.redactMessage(msg, v)
+			if len(blocked) > 0 && i == lastUser && msg.Role == model.RoleUser {
+				log.WarnfContext(ctx, "PII guardrail blocked user input containing %s", joinEntities(blocked))
+				return &model.BeforeModelResult{
+					CustomResponse: blockedResponse("The input was blocked by the PII guardrail."),
+				}, nil
+			}
+			if !changed {
+				continue
+			}
+			if redacted == nil {
+				redacted = append([]model.Message(nil), messages...)
+			}
+			redacted[i] = next
+		}
+		if redacted != nil {
+			args.Request.Messages = redacted
+		}
+		return nil, nil
+	}
+}
+
+func (p *Plugin) afterModel() model.AfterModelCallbackStructured {
+	return func(ctx context.Context, args *model.AfterModelArgs) (*model.AfterModelResult, error) {
+		if p == nil || args == nil || args.Response == nil || len(args.Response.Choices) == 0 {
+			return nil, nil
+		}
+		v := p.vault(ctx)
+		s := p.stream(ctx, args.Response.ID)
+		if !args.Response.IsPartial {
+			defer s.reset()
+		}
+		var choices []model.Choice
+		var blocked []Entity
+		for i, choice := range args.Response.Choices {
+			msg, msgBlocked, msgChanged := p.redactMessage(choice.Message, v)
+			delta, deltaBlocked, deltaChanged := p.redactDelta(
+				choice.Index, choice.Delta, s, args.Response.IsPartial, v)
+			blocked = appendEntities(blocked, msgBlocked...)
+			blocked = appendEntities(blocked, deltaBlocked...)
+			if !msgChanged && !deltaChanged {
+				continue
+			}
+			if choices == nil {
+				choices = append([]model.Choice(nil), args.Response.Choices...)
+			}
+			choices[i].Message = msg
+			choices[i].Delta = delta
+		}
+		if len(blocked) > 0 {
+			log.WarnfContext(ctx, "PII guardrail blocked model output containing %s", joinEntities(blocked))
+			resp := blockedResponse("The response was blocked by the PII guardrail.")
+			resp.ID = args.Response.ID
+			resp.Model = args.Response.Model
+			resp.Created = args.Response.Created
+			return &model.AfterModelResult{CustomResponse: resp}, nil
+		}
+		if choices == nil {
+			return nil, nil
+		}
+		resp := *args.Response
+		resp.Choices = choices
+		return &model.AfterModelResult{CustomResponse: &resp}, nil
+	}
+}
+
+func (p *Plugin) beforeTool() tool.BeforeToolCallbackStructured {
+	return func(ctx context.Context, args *tool.BeforeToolArgs) (*tool.BeforeToolResult, error) {
+		if p == nil || args == nil || len(args.Arguments) == 0 {
+			return nil, nil
+		}
+		v := p.vault(ctx)
+		if p.restoreTools[args.ToolName] {
+			restored, changed := rewriteJSON(args.Arguments, v.restore)
+			if !changed {
+				return nil, nil
+			}
+			return &tool.BeforeToolResult{ModifiedArguments: restored}, nil
+		}
+		if !p.scopes[ScopeToolArguments] {
+			return nil, nil
+		}
+		redacted, blocked, changed := p.redactJSON(args.Arguments, v)
+		if len(blocked) > 0 {
+			log.WarnfContext(ctx, "PII guardrail blocked tool %q arguments containing %s",
+				args.ToolName, joinEntities(blocked))
+			return &tool.BeforeToolResult{
+				CustomResult: fmt.Sprintf("tool %q call was blocked by the PII guardrail: arguments contain %s",
+					args.ToolName, joinEntities(blocked)),
+			}, nil
+		}
+		if !changed {
+			return nil, nil
+		}
+		return &tool.BeforeToolResult{ModifiedArguments: redacted}, nil
+	}
+}
+
+func (p *Plugin) afterTool() tool.AfterToolCallbackStructured {
+	return func(ctx context.Context, args *tool.AfterToolArgs) (*tool.AfterToolResult, error) {
+		if p == nil || args == nil || args.Result == nil {
+			return nil, nil
+		}
+		v := p.vault(ctx)
+		var result any
+		var blocked []Entity
+		switch r := args.Result.(type) {
+		case string:
+			redacted, b := p.redactText(r, v)
+			if redacted == r {
+				return nil, nil
+			}
+			result, blocked = redacted, b
+		default:
+			raw, err := json.Marshal(r)
+			if err != nil {
+				return nil, nil
+			}
+			redacted, b, changed := p.redactJSON(raw, v)
+			if !changed {
+				return nil, nil
+			}
+			var decoded any
+			if err := json.Unmarshal(redacted, &decoded); err != nil {
+				return nil, nil
+			}
+			result, blocked = decoded, b
+		}
+		if len(blocked) > 0 {
+			log.WarnfContext(ctx, "PII guardrail blocked tool %q result containing %s",
+				args.ToolName, joinEntities(blocked))
+			result = fmt.Sprintf("tool %q result was blocked by the PII guardrail: result contains %s",
+				args.ToolName, joinEntities(blocked))
+		}
+		return &tool.AfterToolResult{CustomResult: result, SkipResultFormatter: true}, nil
+	}
+}
+
+// redactMessage redacts the text content of msg. Tool call arguments are
+// left to the tool hooks.
+func (p *Plugin) redactMessage(msg model.Message, v *vault) (model.Message, []Entity, bool) {
+	var blocked []Entity
+	changed := false
+	if msg.Content != "" {
+		content, b := p.redactText(msg.Content, v)
+		blocked = appendEntities(blocked, b...)
+		if content != msg.Content {
+			msg.Content = content
+			changed = true
+		}
+	}
+	if msg.ReasoningContent != "" {
+		content, b := p.redactText(msg.ReasoningContent, v)
+		blocked = appendEntities(blocked, b...)
+		if content != msg.ReasoningContent {
+			msg.ReasoningContent = content
+			changed = true
+		}
+	}
+	parts, b, partsChanged := p.redactParts(msg.ContentParts, v)
+	blocked = appendEntities(blocked, b...)
+	if partsChanged {
+		msg.ContentParts = parts
+		changed = true
+	}
+	return msg, blocked, changed
+}
+
+// redactParts redacts the text parts of a message, copying parts before
+// changing them.
+func (p *Plugin) redactParts(parts []model.ContentPart, v *vault) ([]model.ContentPart, []Entity, bool) {
+	var blocked []Entity
+	var out []model.ContentPart
+	for i, part := range parts {
+		if part.Text == nil {
+			continue
+		}
+		text, b := p.redactText(*part.Text, v)
+		blocked = appendEntities(blocked, b...)
+		if text == *part.Text {
+			continue
+		}
+		if out == nil {
+			out = append([]model.ContentPart(nil), parts...)
+		}
+		out[i].Text = &text
+	}
+	if out == nil {
+		return parts, blocked, false
+	}
+	return out, blocked, true
+}
+
+// vault returns the placeholder vault of the current invocation. It returns
+// nil outside an invocation, in which case placeholders cannot be restored.
+func (p *Plugin) vault(ctx context.Context) *vault {
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil {
+		return nil
+	}
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	if val, ok := inv.GetState(p.vaultKey); ok {
+		if v, ok := val.(*vault); ok {
+			return v
+		}
+	}
+	v := newVault()
+	inv.SetState(p.vaultKey, v)
+	return v
+}
+
+func blockedResponse(content string) *model.Response {
+	return &model.Response{
+		Object: model.ObjectTypeChatCompletion,
+		Done:   true,
+		Choices: []model.Choice{{
+			Index:   0,
+			Message: model.NewAssistantMessage(content),
+		}},
+	}
+}
+
+func appendEntities(entities []Entity, more ...Entity) []Entity {
+	for _, e := range more {
+		if !containsEntity(entities, e) {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+func joinEntities(entities []Entity) string {
+	names := make([]string, 0, len(entities))
+	for _, e := range entities {
+		names = append(names, string(e))
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsScope(scopes []Scope, scope Scope) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
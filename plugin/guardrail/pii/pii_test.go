@@ -0,0 +1,270 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+func invocationContext() context.Context {
+	return agent.NewInvocationContext(context.Background(), agent.NewInvocation())
+}
+
+func TestPlugin_PlaceholdersRestoredOnlyForAllowlistedTools(t *testing.T) {
+	p, err := New(
+		WithAction(ActionPlaceholder),
+		WithRestoreTools("send_email"),
+		WithPlaceholderKey([]byte("k")),
+	)
+	require.NoError(t, err)
+	manager := plugin.MustNewManager(p)
+	ctx := invocationContext()
+
+	req := &model.Request{Messages: []model.Message{
+		{Role: model.RoleSystem, Content: "Reach ops at ops@example.com."},
+		{Role: model.RoleUser, Content: "Email jane@example.com about it."},
+	}}
+	original := req.Messages
+	_, err = manager.ModelCallbacks().RunBeforeModel(ctx, &model.BeforeModelArgs{Request: req})
+	require.NoError(t, err)
+	ph := p.placeholder(EntityEmail, "jane@example.com")
+	require.Equal(t, "Email "+ph+" about it.", req.Messages[1].Content)
+	require.Equal(t, "Reach ops at ops@example.com.", req.Messages[0].Content)
+	require.Equal(t, "Email jane@example.com about it.", original[1].Content)
+
+	// The same value maps to the same placeholder under the same key.
+	other, err := New(WithAction(ActionPlaceholder), WithPlaceholderKey([]byte("k")))
+	require.NoError(t, err)
+	require.Equal(t, ph, other.Redact("jane@example.com"))
+
+	args := []byte(`{"to":"` + ph + `","count":2}`)
+	res, err := manager.ToolCallbacks().RunBeforeTool(ctx, &tool.BeforeToolArgs{
+		ToolName: "send_email", Arguments: args,
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"to":"jane@example.com","count":2}`, string(res.ModifiedArguments))
+
+	res, err = manager.ToolCallbacks().RunBeforeTool(ctx, &tool.BeforeToolArgs{
+		ToolName: "search", Arguments: args,
+	})
+	require.NoError(t, err)
+	if res != nil {
+		require.Nil(t, res.ModifiedArguments)
+	}
+
+	// Outside the invocation nothing can be restored.
+	res, err = manager.ToolCallbacks().RunBeforeTool(context.Background(), &tool.BeforeToolArgs{
+		ToolName: "send_email", Arguments: args,
+	})
+	require.NoError(t, err)
+	if res != nil {
+		require.Nil(t, res.ModifiedArguments)
+	}
+}
+
+func TestPlugin_BlockLatestUserInput(t *testing.T) {
+	p, err := New(WithEntityAction(EntityPaymentCard, ActionBlock))
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+
+	req := &model.Request{Messages: []model.Message{
+		{Role: model.RoleUser, Content: "My card is 4111 1111 1111 1111."},
+	}}
+	res, err := callbacks.RunBeforeModel(context.Background(), &model.BeforeModelArgs{Request: req})
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.NotNil(t, res.CustomResponse)
+	require.Contains(t, res.CustomResponse.Choices[0].Message.Content, "PII guardrail")
+
+	// Earlier turns are masked rather than blocking every later turn.
+	req = &model.Request{Messages: []model.Message{
+		{Role: model.RoleUser, Content: "My card is 4111 1111 1111 1111."},
+		{Role: model.RoleAssistant, Content: "I cannot help with that."},
+		{Role: model.RoleUser, Content: "ok, call me at 13800138000"},
+	}}
+	res, err = callbacks.RunBeforeModel(context.Background(), &model.BeforeModelArgs{Request: req})
+	require.NoError(t, err)
+	require.Nil(t, res)
+	require.Equal(t, "My card is [REDACTED:payment_card].", req.Messages[0].Content)
+	require.Equal(t, "ok, call me at [REDACTED:phone]", req.Messages[2].Content)
+}
+
+func TestPlugin_ModelOutput(t *testing.T) {
+	p, err := New(WithEntityAction(EntityNationalID, ActionBlock))
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+
+	text := "Contact jane@example.com"
+	resp := &model.Response{ID: "r", Choices: []model.Choice{{
+		Message: model.NewAssistantMessage(text),
+		Delta:   model.Message{ContentParts: []model.ContentPart{{Type: model.ContentTypeText, Text: &text}}},
+	}}}
+	res, err := callbacks.RunAfterModel(context.Background(), &model.AfterModelArgs{Response: resp})
+	require.NoError(t, err)
+	require.Equal(t, "Contact [REDACTED:email]", res.CustomResponse.Choices[0].Message.Content)
+	require.Equal(t, "Contact [REDACTED:email]", *res.CustomResponse.Choices[0].Delta.ContentParts[0].Text)
+	require.Equal(t, "Contact jane@example.com", text)
+	require.Equal(t, "r", res.CustomResponse.ID)
+
+	resp = &model.Response{ID: "r", Choices: []model.Choice{{
+		Message: model.NewAssistantMessage("Your SSN is 123-45-6789"),
+	}}}
+	res, err = callbacks.RunAfterModel(context.Background(), &model.AfterModelArgs{Response: resp})
+	require.NoError(t, err)
+	require.Contains(t, res.CustomResponse.Choices[0].Message.Content, "blocked by the PII guardrail")
+}
+
+func TestPlugin_StreamedModelOutput(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+	ctx := invocationContext()
+
+	deltas := []string{
+		"Thanks for your order. The card on file is 4111 11",
+		"11 1111 1111; the receipt goes to ja",
+		"ne@example.com once the payment has been confirmed by the bank.",
+	}
+	var streamed strings.Builder
+	for _, d := range deltas {
+		resp := &model.Response{ID: "r", IsPartial: true, Choices: []model.Choice{{
+			Delta: model.Message{Role: model.RoleAssistant, Content: d},
+		}}}
+		res, err := callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Response: resp})
+		require.NoError(t, err)
+		if res != nil && res.CustomResponse != nil {
+			resp = res.CustomResponse
+		}
+		require.True(t, resp.IsPartial)
+		streamed.WriteString(resp.Choices[0].Delta.Content)
+	}
+	require.NotContains(t, streamed.String(), "4111")
+	require.NotContains(t, streamed.String(), "ja")
+
+	full := strings.Join(deltas, "")
+	want := "Thanks for your order. The card on file is [REDACTED:payment_card]; " +
+		"the receipt goes to [REDACTED:email] once the payment has been confirmed by the bank."
+	resp := &model.Response{ID: "r", Done: true, Choices: []model.Choice{{
+		Message: model.NewAssistantMessage(full),
+	}}}
+	res, err := callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Response: resp})
+	require.NoError(t, err)
+	require.Equal(t, want, res.CustomResponse.Choices[0].Message.Content)
+	// The final response releases the held tail.
+	streamed.WriteString(res.CustomResponse.Choices[0].Delta.Content)
+	require.Equal(t, want, streamed.String())
+
+	// A new response does not inherit the tail of an unfinished one.
+	for _, d := range []string{"call 138", "00138000"} {
+		_, err = callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Response: &model.Response{
+			ID: d, IsPartial: true, Choices: []model.Choice{{Delta: model.Message{Content: d}}},
+		}})
+		require.NoError(t, err)
+	}
+	res, err = callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Response: &model.Response{
+		ID: "00138000", Choices: []model.Choice{{}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "00138000", res.CustomResponse.Choices[0].Delta.Content)
+}
+
+func TestPlugin_ToolArgumentsAndResults(t *testing.T) {
+	p, err := New(
+		WithAction(ActionPlaceholder),
+		WithEntityAction(EntityIBAN, ActionBlock),
+		WithEntityAction(EntityPhone, ActionMask),
+	)
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ToolCallbacks()
+	ctx := invocationContext()
+
+	res, err := callbacks.RunBeforeTool(ctx, &tool.BeforeToolArgs{
+		ToolName:  "lookup",
+		Arguments: []byte(`{"phone":13800138000,"note":"x"}`),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"phone":"[REDACTED:phone]","note":"x"}`, string(res.ModifiedArguments))
+
+	res, err = callbacks.RunBeforeTool(ctx, &tool.BeforeToolArgs{
+		ToolName:  "transfer",
+		Arguments: []byte(`{"iban":"DE89370400440532013000"}`),
+	})
+	require.NoError(t, err)
+	require.Contains(t, res.CustomResult, "blocked by the PII guardrail")
+
+	after, err := callbacks.RunAfterTool(ctx, &tool.AfterToolArgs{
+		ToolName: "lookup",
+		Result:   map[string]any{"owner": "jane@example.com", "id": 7},
+	})
+	require.NoError(t, err)
+	result := after.CustomResult.(map[string]any)
+	require.Equal(t, p.placeholder(EntityEmail, "jane@example.com"), result["owner"])
+	require.EqualValues(t, 7, result["id"])
+	require.True(t, after.SkipResultFormatter)
+
+	after, err = p.afterTool()(ctx, &tool.AfterToolArgs{ToolName: "lookup", Result: "nothing here"})
+	require.NoError(t, err)
+	require.Nil(t, after)
+}
+
+func TestPlugin_ScopesLimitRegistration(t *testing.T) {
+	p, err := New(WithScopes(ScopeModelOutput))
+	require.NoError(t, err)
+	manager := plugin.MustNewManager(p)
+	require.Empty(t, manager.ModelCallbacks().BeforeModel)
+	require.Len(t, manager.ModelCallbacks().AfterModel, 1)
+	require.Nil(t, manager.ToolCallbacks())
+
+	next := tracetest.NewInMemoryExporter()
+	require.Same(t, next, p.SpanExporter(next))
+}
+
+func TestPlugin_SpanExporterRedactsAttributes(t *testing.T) {
+	p, err := New(WithAction(ActionPlaceholder))
+	require.NoError(t, err)
+	recorder := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(p.SpanExporter(recorder)),
+	)
+	defer provider.Shutdown(context.Background())
+	_, span := provider.Tracer("test").Start(context.Background(), "chat")
+	span.SetAttributes(
+		attribute.String("gen_ai.input.messages", `[{"content":"I am jane@example.com"}]`),
+		attribute.StringSlice("tags", []string{"ok", "4111111111111111"}),
+		attribute.Int("n", 13800138000),
+	)
+	span.AddEvent("tool", oteltrace.WithAttributes(attribute.String("args", "call 13800138000")))
+	span.End()
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 1)
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	input := attrs["gen_ai.input.messages"].AsString()
+	require.NotContains(t, input, "jane@example.com")
+	require.True(t, strings.Contains(input, "PII_EMAIL_"))
+	require.Equal(t, []string{"ok", p.placeholder(EntityPaymentCard, "4111111111111111")},
+		attrs["tags"].AsStringSlice())
+	require.EqualValues(t, 13800138000, attrs["n"].AsInt64())
+	require.NotContains(t, spans[0].Events[0].Attributes[0].Value.AsString(), "13800138000")
+}
@@ -0,0 +1,211 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Action determines what happens to detected personal data.
+type Action string
+
+const (
+	// ActionBlock refuses the model call or tool call that carries the
+	// entity. Where nothing can be refused, such as history messages and
+	// telemetry, the entity is masked instead.
+	ActionBlock Action = "block"
+	// ActionMask replaces the entity with an irreversible marker such as
+	// "[REDACTED:email]".
+	ActionMask Action = "mask"
+	// ActionPlaceholder replaces the entity with a stable placeholder such as
+	// "PII_EMAIL_1A2B3C4D" that is restored for allowlisted tools.
+	ActionPlaceholder Action = "placeholder"
+)
+
+func validateAction(action Action) error {
+	switch action {
+	case ActionBlock, ActionMask, ActionPlaceholder:
+		return nil
+	default:
+		return fmt.Errorf("invalid action %q", action)
+	}
+}
+
+func maskValue(entity Entity) string {
+	return "[REDACTED:" + string(entity) + "]"
+}
+
+// placeholder derives the placeholder of value from the plugin key.
+func (p *Plugin) placeholder(entity Entity, value string) string {
+	mac := hmac.New(sha256.New, p.placeholderKey)
+	mac.Write([]byte(entity))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	sum := strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:4]))
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, string(entity))
+	return "PII_" + name + "_" + sum
+}
+
+func (p *Plugin) action(entity Entity) Action {
+	if action, ok := p.entityActions[entity]; ok {
+		return action
+	}
+	return p.defaultAction
+}
+
+// redactText replaces the personal data in text. Entities whose action is
+// ActionBlock are masked and reported so that callers able to refuse the
+// surrounding operation can do so. Placeholders are recorded in v when it
+// is not nil.
+func (p *Plugin) redactText(text string, v *vault) (string, []Entity) {
+	return p.redactMatches(text, p.detector.detect(text), v)
+}
+
+// redactMatches is redactText with the matches in text already detected.
+func (p *Plugin) redactMatches(text string, matches []Match, v *vault) (string, []Entity) {
+	if len(matches) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	var blocked []Entity
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		last = m.End
+		switch p.action(m.Entity) {
+		case ActionPlaceholder:
+			ph := p.placeholder(m.Entity, m.Value)
+			v.put(ph, m.Value)
+			b.WriteString(ph)
+		case ActionBlock:
+			blocked = appendEntities(blocked, m.Entity)
+			b.WriteString(maskValue(m.Entity))
+		default:
+			b.WriteString(maskValue(m.Entity))
+		}
+	}
+	b.WriteString(text[last:])
+	return b.String(), blocked
+}
+
+// redactJSON applies redactText to every string and number of a JSON
+// document. Documents that are not valid JSON are redacted as text.
+func (p *Plugin) redactJSON(raw []byte, v *vault) ([]byte, []Entity, bool) {
+	var blocked []Entity
+	out, changed := rewriteJSON(raw, func(s string) string {
+		redacted, b := p.redactText(s, v)
+		blocked = appendEntities(blocked, b...)
+		return redacted
+	})
+	return out, blocked, changed
+}
+
+// rewriteJSON applies fn to the string and number values of raw. Object
+// keys are left untouched.
+func rewriteJSON(raw []byte, fn func(string) string) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		text := string(raw)
+		out := fn(text)
+		return []byte(out), out != text
+	}
+	doc, changed := rewriteValue(doc, fn)
+	if !changed {
+		return raw, false
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return raw, false
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), true
+}
+
+func rewriteValue(v any, fn func(string) string) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		out := fn(val)
+		return out, out != val
+	case json.Number:
+		if out := fn(val.String()); out != val.String() {
+			return out, true
+		}
+		return val, false
+	case []any:
+		changed := false
+		for i, item := range val {
+			next, c := rewriteValue(item, fn)
+			val[i] = next
+			changed = changed || c
+		}
+		return val, changed
+	case map[string]any:
+		changed := false
+		for k, item := range val {
+			next, c := rewriteValue(item, fn)
+			val[k] = next
+			changed = changed || c
+		}
+		return val, changed
+	default:
+		return v, false
+	}
+}
+
+// vault remembers the original values behind placeholders for one
+// invocation.
+type vault struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newVault() *vault {
+	return &vault{values: make(map[string]string)}
+}
+
+func (v *vault) put(placeholder, value string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[placeholder] = value
+}
+
+// restore replaces known placeholders in text with their original values.
+func (v *vault) restore(text string) string {
+	if v == nil || !strings.Contains(text, "PII_") {
+		return text
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for ph, value := range v.values {
+		text = strings.ReplaceAll(text, ph, value)
+	}
+	return text
+}
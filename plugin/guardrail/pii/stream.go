@@ -0,0 +1,175 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"context"
+	"sync"
+	"unicode/utf8"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// streamHoldBytes is how much streamed text is held back at least, so that
+// personal data split across deltas is redacted as a whole. It covers the
+// longest built-in entity.
+const streamHoldBytes = 64
+
+// streamField identifies the text of a delta that is held back.
+type streamField struct {
+	choice    int
+	reasoning bool
+}
+
+// stream holds the tails of the streamed deltas of one model response that
+// were not released yet.
+type stream struct {
+	mu      sync.Mutex
+	id      string
+	pending map[streamField]string
+}
+
+// take removes and returns the tail held for f.
+func (s *stream) take(f streamField) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	text := s.pending[f]
+	delete(s.pending, f)
+	return text
+}
+
+// hold stores the tail of f until the next delta.
+func (s *stream) hold(f streamField, text string) {
+	if text == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[f] = text
+}
+
+// reset drops every held tail.
+func (s *stream) reset() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.pending)
+}
+
+// stream returns the stream state of the current invocation for the
+// response id, dropping the tails of an earlier response. It returns nil
+// outside an invocation, in which case each delta is redacted on its own.
+func (p *Plugin) stream(ctx context.Context, id string) *stream {
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil {
+		return nil
+	}
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	if val, ok := inv.GetState(p.streamKey); ok {
+		if s, ok := val.(*stream); ok {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.id != id {
+				s.id = id
+				clear(s.pending)
+			}
+			return s
+		}
+	}
+	s := &stream{id: id, pending: make(map[streamField]string)}
+	inv.SetState(p.streamKey, s)
+	return s
+}
+
+// redactDelta redacts the delta of choice. In a partial response the tail
+// that may continue in the next delta is held back in s and prepended to
+// that delta; the final response releases whatever is still held.
+func (p *Plugin) redactDelta(
+	choice int,
+	delta model.Message,
+	s *stream,
+	partial bool,
+	v *vault,
+) (model.Message, []Entity, bool) {
+	if s == nil {
+		return p.redactMessage(delta, v)
+	}
+	var blocked []Entity
+	changed := false
+	for _, f := range []struct {
+		text      *string
+		reasoning bool
+	}{
+		{&delta.Content, false},
+		{&delta.ReasoningContent, true},
+	} {
+		key := streamField{choice: choice, reasoning: f.reasoning}
+		text := s.take(key) + *f.text
+		matches := p.detector.detect(text)
+		if partial {
+			var held string
+			text, held, matches = holdBack(text, matches)
+			s.hold(key, held)
+		}
+		redacted, b := p.redactMatches(text, matches, v)
+		blocked = appendEntities(blocked, b...)
+		if redacted != *f.text {
+			*f.text = redacted
+			changed = true
+		}
+	}
+	parts, b, partsChanged := p.redactParts(delta.ContentParts, v)
+	blocked = appendEntities(blocked, b...)
+	if partsChanged {
+		delta.ContentParts = parts
+		changed = true
+	}
+	return delta, blocked, changed
+}
+
+// holdBack splits text into the part that can be released and the tail
+// that may be the start of personal data continued by the next delta,
+// and returns the matches within the released part. The tail is at least
+// streamHoldBytes long, extends to the start of the word it cuts and
+// includes every match that reaches into it.
+func holdBack(text string, matches []Match) (string, string, []Match) {
+	cut := len(text) - streamHoldBytes
+	if cut <= 0 {
+		return "", text, nil
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	for limit := cut - streamHoldBytes; cut > 0 && cut > limit && isEntityByte(text[cut-1]); {
+		cut--
+	}
+	for _, m := range matches {
+		if m.Start < cut && cut < m.End {
+			cut = m.Start
+		}
+	}
+	released := matches[:0:0]
+	for _, m := range matches {
+		if m.End <= cut {
+			released = append(released, m)
+		}
+	}
+	return text[:cut], text[cut:], released
+}
+
+// isEntityByte reports whether b can be part of a built-in entity other
+// than at a separator between groups.
+func isEntityByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' ||
+		b == '.' || b == '@' || b == '_' || b == '%' || b == '+' || b == '-'
+}
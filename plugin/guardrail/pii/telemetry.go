@@ -0,0 +1,130 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package pii
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanExporter wraps next so that string span attributes and span event
+// attributes are redacted before export. Placeholders are never restored in
+// telemetry. It can be installed with trace.WithSpanExporterWrapper.
+//
+// next is returned unchanged when ScopeTelemetry is not enabled.
+func (p *Plugin) SpanExporter(next sdktrace.SpanExporter) sdktrace.SpanExporter {
+	if p == nil || next == nil || !p.scopes[ScopeTelemetry] {
+		return next
+	}
+	return &spanExporter{plugin: p, next: next}
+}
+
+type spanExporter struct {
+	plugin *Plugin
+	next   sdktrace.SpanExporter
+}
+
+var _ sdktrace.SpanExporter = (*spanExporter)(nil)
+
+// ExportSpans implements sdktrace.SpanExporter.
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	out := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		out[i] = e.redactSpan(span)
+	}
+	return e.next.ExportSpans(ctx, out)
+}
+
+// Shutdown implements sdktrace.SpanExporter.
+func (e *spanExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
+
+func (e *spanExporter) redactSpan(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, changed := e.redactAttributes(span.Attributes())
+	events := span.Events()
+	var redactedEvents []sdktrace.Event
+	for i, evt := range events {
+		evtAttrs, evtChanged := e.redactAttributes(evt.Attributes)
+		if !evtChanged {
+			continue
+		}
+		if redactedEvents == nil {
+			redactedEvents = append([]sdktrace.Event(nil), events...)
+		}
+		redactedEvents[i].Attributes = evtAttrs
+	}
+	if !changed && redactedEvents == nil {
+		return span
+	}
+	if redactedEvents == nil {
+		redactedEvents = events
+	}
+	return &redactedSpan{ReadOnlySpan: span, attrs: attrs, events: redactedEvents}
+}
+
+func (e *spanExporter) redactAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		next, ok := e.redactValue(kv)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]attribute.KeyValue(nil), attrs...)
+		}
+		out[i] = next
+	}
+	if out == nil {
+		return attrs, false
+	}
+	return out, true
+}
+
+func (e *spanExporter) redactValue(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		s := kv.Value.AsString()
+		if redacted := e.plugin.Redact(s); redacted != s {
+			return kv.Key.String(redacted), true
+		}
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		changed := false
+		for i, s := range values {
+			if redacted := e.plugin.Redact(s); redacted != s {
+				values[i] = redacted
+				changed = true
+			}
+		}
+		if changed {
+			return kv.Key.StringSlice(values), true
+		}
+	}
+	return kv, false
+}
+
+// redactedSpan overrides the attributes of a finished span.
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+// Attributes implements sdktrace.ReadOnlySpan.
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+// Events implements sdktrace.ReadOnlySpan.
+func (s *redactedSpan) Events() []sdktrace.Event {
+	return s.events
+}
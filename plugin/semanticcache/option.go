@@ -0,0 +1,154 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package semanticcache
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/knowledge/embedder"
+)
+
+const (
+	defaultPluginName          = "semanticcache"
+	defaultMemoryCapacity      = 10000
+	defaultSimilarityThreshold = 0.95
+	defaultTTL                 = 24 * time.Hour
+)
+
+// Option configures the semantic cache plugin.
+type Option func(*options)
+
+type options struct {
+	name      string
+	store     Store
+	embedder  embedder.Embedder
+	threshold float64
+	ttl       time.Duration
+	scope     func(ctx context.Context) string
+	normalize func(string) string
+}
+
+func newOptions(opts ...Option) *options {
+	options := &options{
+		name:      defaultPluginName,
+		threshold: defaultSimilarityThreshold,
+		ttl:       defaultTTL,
+		scope:     AppScope,
+		normalize: NormalizePrompt,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	if options.store == nil {
+		options.store = NewMemoryStore(defaultMemoryCapacity)
+	}
+	if options.scope == nil {
+		options.scope = AppScope
+	}
+	if options.normalize == nil {
+		options.normalize = NormalizePrompt
+	}
+	return options
+}
+
+// WithName sets the plugin name.
+func WithName(name string) Option {
+	return func(opts *options) {
+		opts.name = name
+	}
+}
+
+// WithStore sets the store that holds cached responses. The default is an
+// in-memory LRU store with 10000 entries.
+func WithStore(store Store) Option {
+	return func(opts *options) {
+		opts.store = store
+	}
+}
+
+// WithEmbedder enables similarity matching of the latest user turn. Without
+// an embedder only prompts that normalize to the same text hit the cache.
+func WithEmbedder(e embedder.Embedder) Option {
+	return func(opts *options) {
+		opts.embedder = e
+	}
+}
+
+// WithSimilarityThreshold sets the minimum cosine similarity for a cached
+// response to be served. The default is 0.95.
+func WithSimilarityThreshold(threshold float64) Option {
+	return func(opts *options) {
+		opts.threshold = threshold
+	}
+}
+
+// WithTTL sets how long cached responses are served. Zero or a negative
+// value keeps them until the store evicts them. The default is 24 hours.
+func WithTTL(ttl time.Duration) Option {
+	return func(opts *options) {
+		opts.ttl = ttl
+	}
+}
+
+// WithScope sets the function that partitions the cache, such as per
+// tenant. Requests in different scopes never share responses. The default
+// is AppScope.
+func WithScope(scope func(ctx context.Context) string) Option {
+	return func(opts *options) {
+		opts.scope = scope
+	}
+}
+
+// WithNormalizer replaces the function applied to message text before it
+// is fingerprinted and embedded. The default is NormalizePrompt.
+func WithNormalizer(normalize func(string) string) Option {
+	return func(opts *options) {
+		opts.normalize = normalize
+	}
+}
+
+// AppScope scopes the cache by the application name of the session.
+func AppScope(ctx context.Context) string {
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil || inv.Session == nil {
+		return ""
+	}
+	return inv.Session.AppName
+}
+
+// NormalizePrompt is the default normalizer. It applies Unicode NFC and
+// lower case, collapses white space runs into a single space and trims
+// surrounding white space and trailing sentence punctuation, so prompts
+// that differ only in layout or case share a key.
+func NormalizePrompt(text string) string {
+	text = strings.ToLower(norm.NFC.String(text))
+	var b strings.Builder
+	b.Grow(len(text))
+	space := false
+	for _, r := range strings.TrimSpace(text) {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimRight(b.String(), "?!.。？！ ")
+}
@@ -0,0 +1,218 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package redis provides a Redis-backed store for the semantic response
+// cache, so several processes can share cached responses.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-agent-go/plugin/semanticcache"
+	storage "trpc.group/trpc-go/trpc-agent-go/storage/redis"
+)
+
+const defaultKeyPrefix = "semcache:"
+
+var _ semanticcache.Store = (*Store)(nil)
+
+// Options is the options for the redis semantic cache store.
+type Options struct {
+	url          string
+	instanceName string
+	extraOptions []any
+	keyPrefix    string
+}
+
+// Option is the option for the redis semantic cache store.
+type Option func(*Options)
+
+// WithRedisClientURL creates a redis client from URL and sets it to the store.
+func WithRedisClientURL(url string) Option {
+	return func(opts *Options) {
+		opts.url = url
+	}
+}
+
+// WithRedisInstance uses a redis instance from storage.
+// Note: WithRedisClientURL has higher priority than WithRedisInstance.
+// If both are specified, WithRedisClientURL will be used.
+func WithRedisInstance(instanceName string) Option {
+	return func(opts *Options) {
+		opts.instanceName = instanceName
+	}
+}
+
+// WithExtraOptions sets the extra options passed to the redis client builder.
+func WithExtraOptions(extraOptions ...any) Option {
+	return func(opts *Options) {
+		opts.extraOptions = append(opts.extraOptions, extraOptions...)
+	}
+}
+
+// WithKeyPrefix sets the prefix of every redis key. The default is "semcache:".
+func WithKeyPrefix(prefix string) Option {
+	return func(opts *Options) {
+		opts.keyPrefix = prefix
+	}
+}
+
+// Store is a semanticcache.Store persisting entries in redis.
+//
+// Each entry is a string key expiring with the entry; each partition is a
+// set of entry keys used for similarity search. Members whose entry has
+// expired are dropped from the set when the partition is read.
+type Store struct {
+	opts   Options
+	client redis.UniversalClient
+}
+
+// NewStore creates a redis-backed semantic cache store.
+func NewStore(options ...Option) (*Store, error) {
+	opts := Options{keyPrefix: defaultKeyPrefix}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	builderOpts := []storage.ClientBuilderOpt{
+		storage.WithClientBuilderURL(opts.url),
+		storage.WithExtraOptions(opts.extraOptions...),
+	}
+	// if instance name set, and url not set, use instance name to create redis client
+	if opts.url == "" && opts.instanceName != "" {
+		var ok bool
+		if builderOpts, ok = storage.GetRedisInstance(opts.instanceName); !ok {
+			return nil, fmt.Errorf("redis instance %s not found", opts.instanceName)
+		}
+	}
+	client, err := storage.GetClientBuilder()(builderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create redis client from url failed: %w", err)
+	}
+	return &Store{opts: opts, client: client}, nil
+}
+
+// Get implements semanticcache.Store.
+func (s *Store) Get(ctx context.Context, key string) (*semanticcache.Entry, error) {
+	data, err := s.client.Get(ctx, s.entryKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+	return unmarshalEntry(data)
+}
+
+// Candidates implements semanticcache.Store.
+func (s *Store) Candidates(ctx context.Context, partition string) ([]*semanticcache.Entry, error) {
+	setKey := s.partitionKey(partition)
+	keys, err := s.client.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis smembers: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	entryKeys := make([]string, len(keys))
+	for i, key := range keys {
+		entryKeys[i] = s.entryKey(key)
+	}
+	values, err := s.client.MGet(ctx, entryKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget: %w", err)
+	}
+	var (
+		entries []*semanticcache.Entry
+		stale   []any
+	)
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			stale = append(stale, keys[i])
+			continue
+		}
+		entry, err := unmarshalEntry([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if len(stale) > 0 {
+		if err := s.client.SRem(ctx, setKey, stale...).Err(); err != nil {
+			return nil, fmt.Errorf("redis srem: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+// Put implements semanticcache.Store.
+func (s *Store) Put(ctx context.Context, entry *semanticcache.Entry) error {
+	if entry == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+	var ttl time.Duration
+	if !entry.ExpiresAt.IsZero() {
+		if ttl = time.Until(entry.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+	setKey := s.partitionKey(entry.Partition)
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.entryKey(entry.Key), data, ttl)
+	indexExists := pipe.Exists(ctx, setKey)
+	pipe.SAdd(ctx, setKey, entry.Key)
+	if ttl == 0 {
+		pipe.Persist(ctx, setKey)
+	}
+	indexTTL := pipe.TTL(ctx, setKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis put: %w", err)
+	}
+	// Keep the index at least as long as its newest entry. An existing
+	// index without TTL holds entries that never expire.
+	current := indexTTL.Val()
+	if ttl > 0 && (indexExists.Val() == 0 || (current >= 0 && current < ttl)) {
+		if err := s.client.Expire(ctx, setKey, ttl).Err(); err != nil {
+			return fmt.Errorf("redis expire: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying redis client.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+func (s *Store) entryKey(key string) string {
+	return s.opts.keyPrefix + "entry:" + key
+}
+
+func (s *Store) partitionKey(partition string) string {
+	return s.opts.keyPrefix + "partition:" + partition
+}
+
+func unmarshalEntry(data []byte) (*semanticcache.Entry, error) {
+	var entry semanticcache.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal entry: %w", err)
+	}
+	return &entry, nil
+}
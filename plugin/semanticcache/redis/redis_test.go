@@ -0,0 +1,88 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin/semanticcache"
+	storage "trpc.group/trpc-go/trpc-agent-go/storage/redis"
+)
+
+func entry(key, partition string, ttl time.Duration) *semanticcache.Entry {
+	e := &semanticcache.Entry{
+		Key:       key,
+		Partition: partition,
+		Prompt:    "how do i reset my password",
+		Embedding: []float64{0.5, -1},
+		Response: &model.Response{Done: true, Choices: []model.Choice{{
+			Message: model.NewAssistantMessage("Use the reset link."),
+		}}},
+		CreatedAt: time.Now(),
+	}
+	if ttl > 0 {
+		e.ExpiresAt = e.CreatedAt.Add(ttl)
+	}
+	return e
+}
+
+func TestStore_PutGetCandidates(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s, err := NewStore(WithRedisClientURL("redis://" + mr.Addr()))
+	require.NoError(t, err)
+	defer s.Close()
+	ctx := context.Background()
+
+	got, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, s.Put(ctx, entry("a", "p1", time.Minute)))
+	require.NoError(t, s.Put(ctx, entry("b", "p1", time.Hour)))
+	require.NoError(t, s.Put(ctx, entry("c", "p2", 0)))
+
+	got, err = s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, []float64{0.5, -1}, got.Embedding)
+	assert.Equal(t, "Use the reset link.", got.Response.Choices[0].Message.Content)
+	assert.InDelta(t, time.Minute, mr.TTL(defaultKeyPrefix+"entry:a"), float64(time.Second))
+	assert.InDelta(t, time.Hour, mr.TTL(defaultKeyPrefix+"partition:p1"), float64(time.Second))
+	assert.Zero(t, mr.TTL(defaultKeyPrefix+"partition:p2"))
+
+	mr.FastForward(2 * time.Minute)
+	candidates, err := s.Candidates(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "b", candidates[0].Key)
+	members, err := mr.Members(defaultKeyPrefix + "partition:p1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, members)
+}
+
+func TestNewStore_Instance(t *testing.T) {
+	mr := miniredis.RunT(t)
+	storage.RegisterRedisInstance("semcache-test", storage.WithClientBuilderURL("redis://"+mr.Addr()))
+	s, err := NewStore(WithRedisInstance("semcache-test"), WithKeyPrefix("p:"))
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Put(context.Background(), entry("k", "part", 0)))
+	assert.True(t, mr.Exists("p:entry:k"))
+
+	_, err = NewStore(WithRedisInstance("missing"))
+	assert.Error(t, err)
+}
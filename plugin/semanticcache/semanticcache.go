@@ -0,0 +1,382 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+// Package semanticcache provides a runner-scoped plugin that serves final
+// model responses from a cache when a new question is the same as, or
+// semantically close to, one answered before.
+//
+// Requests are partitioned by scope (the application by default), model,
+// system prompt, tool set, structured output schema and the conversation
+// before the latest user turn. Within a partition a request hits when its
+// normalized latest user turn matches a cached one exactly, or when the
+// embedding of that turn is at least as similar as the configured
+// threshold. Only final text answers are cached; responses with tool calls,
+// errors or partial content are not.
+package semanticcache
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin"
+)
+
+const (
+	// HitTag is added to the tag of events served from the cache.
+	HitTag = "semantic_cache_hit"
+	// HitExtensionKey is the event extension describing a cache hit.
+	HitExtensionKey = "trpc_agent.semantic_cache"
+
+	// trackedTTL bounds how long lookups and hits are remembered while the
+	// model call and event emission complete.
+	trackedTTL = 10 * time.Minute
+)
+
+// Hit describes a response served from the cache.
+type Hit struct {
+	// Key is the cache key of the served entry.
+	Key string `json:"key"`
+	// Similarity is the cosine similarity of the latest user turns, or 1 for
+	// exact matches.
+	Similarity float64 `json:"similarity"`
+	// Exact reports whether the normalized prompts were identical.
+	Exact bool `json:"exact"`
+	// CachedAt is when the served response was stored.
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// Plugin is the semantic response cache implementation.
+type Plugin struct {
+	opts *options
+
+	mu      sync.Mutex
+	pending map[*model.Request]tracked[*Entry]
+	hits    map[string]tracked[Hit]
+}
+
+type tracked[T any] struct {
+	value T
+	at    time.Time
+}
+
+// New creates a new semantic cache plugin.
+func New(options ...Option) (*Plugin, error) {
+	opts := newOptions(options...)
+	if opts.threshold <= 0 || opts.threshold > 1 {
+		return nil, fmt.Errorf("newing semantic cache plugin: similarity threshold %v is not in (0, 1]", opts.threshold)
+	}
+	return &Plugin{
+		opts:    opts,
+		pending: make(map[*model.Request]tracked[*Entry]),
+		hits:    make(map[string]tracked[Hit]),
+	}, nil
+}
+
+// Name implements plugin.Plugin.
+func (p *Plugin) Name() string {
+	return p.opts.name
+}
+
+// Register implements plugin.Plugin.
+func (p *Plugin) Register(r *plugin.Registry) {
+	if p == nil || r == nil {
+		return
+	}
+	r.BeforeModel(p.beforeModel)
+	r.AfterModel(p.afterModel)
+	r.OnEvent(p.onEvent)
+}
+
+func (p *Plugin) beforeModel(
+	ctx context.Context,
+	args *model.BeforeModelArgs,
+) (*model.BeforeModelResult, error) {
+	if args == nil || args.Request == nil {
+		return nil, nil
+	}
+	entry := p.newEntry(ctx, args.Request)
+	if entry == nil {
+		return nil, nil
+	}
+	cached, hit := p.lookup(ctx, entry)
+	if cached != nil {
+		return &model.BeforeModelResult{CustomResponse: p.serve(cached, hit)}, nil
+	}
+	p.mu.Lock()
+	p.pruneLocked(time.Now())
+	p.pending[args.Request] = tracked[*Entry]{value: entry, at: time.Now()}
+	p.mu.Unlock()
+	return nil, nil
+}
+
+// lookup finds an exact match first and falls back to the most similar
+// entry of the partition.
+func (p *Plugin) lookup(ctx context.Context, entry *Entry) (*Entry, Hit) {
+	now := time.Now()
+	cached, err := p.opts.store.Get(ctx, entry.Key)
+	if err != nil {
+		log.WarnfContext(ctx, "semantic cache: lookup failed: %v", err)
+		return nil, Hit{}
+	}
+	if cached != nil && !cached.Expired(now) && cached.Response != nil {
+		return cached, Hit{Key: cached.Key, Similarity: 1, Exact: true, CachedAt: cached.CreatedAt}
+	}
+	if p.opts.embedder == nil {
+		return nil, Hit{}
+	}
+	embedding, err := p.opts.embedder.GetEmbedding(ctx, entry.Prompt)
+	if err != nil || len(embedding) == 0 {
+		log.WarnfContext(ctx, "semantic cache: embedding failed: %v", err)
+		return nil, Hit{}
+	}
+	entry.Embedding = embedding
+	candidates, err := p.opts.store.Candidates(ctx, entry.Partition)
+	if err != nil {
+		log.WarnfContext(ctx, "semantic cache: candidate lookup failed: %v", err)
+		return nil, Hit{}
+	}
+	var best *Entry
+	bestScore := p.opts.threshold
+	for _, c := range candidates {
+		if c.Expired(now) || c.Response == nil {
+			continue
+		}
+		if score := cosineSimilarity(embedding, c.Embedding); score >= bestScore {
+			best, bestScore = c, score
+		}
+	}
+	if best == nil {
+		return nil, Hit{}
+	}
+	return best, Hit{Key: best.Key, Similarity: bestScore, CachedAt: best.CreatedAt}
+}
+
+// serve returns a fresh copy of a cached response and remembers it so that
+// the resulting events can be marked.
+func (p *Plugin) serve(cached *Entry, hit Hit) *model.Response {
+	resp := cached.Response.Clone()
+	resp.ID = "semcache-" + randomID()
+	resp.Created = time.Now().Unix()
+	resp.Timestamp = time.Now()
+	resp.Usage = nil
+	resp.Done = true
+	resp.IsPartial = false
+	p.mu.Lock()
+	p.pruneLocked(time.Now())
+	p.hits[resp.ID] = tracked[Hit]{value: hit, at: time.Now()}
+	p.mu.Unlock()
+	return resp
+}
+
+func (p *Plugin) afterModel(
+	ctx context.Context,
+	args *model.AfterModelArgs,
+) (*model.AfterModelResult, error) {
+	if args == nil || args.Request == nil {
+		return nil, nil
+	}
+	resp := args.Response
+	if resp != nil && resp.IsPartial && args.Error == nil && resp.Error == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	pending, ok := p.pending[args.Request]
+	delete(p.pending, args.Request)
+	p.mu.Unlock()
+	if !ok || args.Error != nil || !cacheable(resp) {
+		return nil, nil
+	}
+	entry := pending.value
+	if p.opts.embedder != nil && len(entry.Embedding) == 0 {
+		// The exact lookup missed without embedding; embed now so that
+		// similar questions can hit later.
+		embedding, err := p.opts.embedder.GetEmbedding(ctx, entry.Prompt)
+		if err != nil {
+			log.WarnfContext(ctx, "semantic cache: embedding failed: %v", err)
+		} else {
+			entry.Embedding = embedding
+		}
+	}
+	entry.Response = resp.Clone()
+	entry.CreatedAt = time.Now()
+	if p.opts.ttl > 0 {
+		entry.ExpiresAt = entry.CreatedAt.Add(p.opts.ttl)
+	}
+	if err := p.opts.store.Put(ctx, entry); err != nil {
+		log.WarnfContext(ctx, "semantic cache: store failed: %v", err)
+	}
+	return nil, nil
+}
+
+func (p *Plugin) onEvent(
+	_ context.Context,
+	_ *agent.Invocation,
+	e *event.Event,
+) (*event.Event, error) {
+	if e == nil || e.Response == nil || !strings.HasPrefix(e.Response.ID, "semcache-") {
+		return nil, nil
+	}
+	p.mu.Lock()
+	hit, ok := p.hits[e.Response.ID]
+	p.mu.Unlock()
+	if !ok || e.ContainsTag(HitTag) {
+		return nil, nil
+	}
+	if e.Tag == "" {
+		e.Tag = HitTag
+	} else {
+		e.Tag += event.TagDelimiter + HitTag
+	}
+	if err := event.SetExtension(e, HitExtensionKey, hit.value); err != nil {
+		return nil, fmt.Errorf("set cache hit extension: %w", err)
+	}
+	return e, nil
+}
+
+// newEntry fingerprints a request. It returns nil for requests that must
+// not be cached: those not ending with a plain text user turn.
+func (p *Plugin) newEntry(ctx context.Context, req *model.Request) *Entry {
+	if len(req.Messages) == 0 {
+		return nil
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != model.RoleUser {
+		return nil
+	}
+	prompt, ok := plainText(last)
+	if !ok {
+		return nil
+	}
+	prompt = p.opts.normalize(prompt)
+	if prompt == "" {
+		return nil
+	}
+	h := sha256.New()
+	write := func(parts ...string) {
+		for _, part := range parts {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+	}
+	write("scope", p.opts.scope(ctx))
+	if inv, ok := agent.InvocationFromContext(ctx); ok && inv != nil && inv.Model != nil {
+		write("model", inv.Model.Info().Name)
+	}
+	for _, msg := range req.Messages[:len(req.Messages)-1] {
+		text, ok := plainText(msg)
+		if !ok || len(msg.ToolCalls) > 0 {
+			// Multimodal or tool-calling history is never shared.
+			return nil
+		}
+		if msg.Role != model.RoleSystem {
+			text = p.opts.normalize(text)
+		}
+		write("message", string(msg.Role), text)
+	}
+	write("tools")
+	names := make([]string, 0, len(req.Tools))
+	for name := range req.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		decl, err := json.Marshal(req.Tools[name].Declaration())
+		if err != nil {
+			return nil
+		}
+		write(name, string(decl))
+	}
+	if req.StructuredOutput != nil {
+		schema, err := json.Marshal(req.StructuredOutput)
+		if err != nil {
+			return nil
+		}
+		write("structured_output", string(schema))
+	}
+	partition := hex.EncodeToString(h.Sum(nil))
+	key := sha256.Sum256([]byte(partition + "\x00" + prompt))
+	return &Entry{
+		Key:       hex.EncodeToString(key[:]),
+		Partition: partition,
+		Prompt:    prompt,
+	}
+}
+
+// plainText returns the text of msg, or false when it carries non-text
+// content.
+func plainText(msg model.Message) (string, bool) {
+	parts := []string{msg.Content}
+	for _, part := range msg.ContentParts {
+		if part.Type != model.ContentTypeText || part.Text == nil {
+			return "", false
+		}
+		parts = append(parts, *part.Text)
+	}
+	return strings.Join(parts, "\n"), true
+}
+
+// cacheable reports whether resp is a complete text answer.
+func cacheable(resp *model.Response) bool {
+	if resp == nil || resp.Error != nil || resp.IsPartial || len(resp.Choices) == 0 {
+		return false
+	}
+	for _, choice := range resp.Choices {
+		if len(choice.Message.ToolCalls) > 0 || strings.TrimSpace(choice.Message.Content) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Plugin) pruneLocked(now time.Time) {
+	for req, t := range p.pending {
+		if now.Sub(t.at) > trackedTTL {
+			delete(p.pending, req)
+		}
+	}
+	for id, t := range p.hits {
+		if now.Sub(t.at) > trackedTTL {
+			delete(p.hits, id)
+		}
+	}
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
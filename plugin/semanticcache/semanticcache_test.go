@@ -0,0 +1,217 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package semanticcache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+type stubEmbedder struct {
+	vectors map[string][]float64
+	calls   int
+}
+
+func (e *stubEmbedder) GetEmbedding(_ context.Context, text string) ([]float64, error) {
+	e.calls++
+	return e.vectors[text], nil
+}
+
+func (e *stubEmbedder) GetEmbeddingWithUsage(ctx context.Context, text string) ([]float64, map[string]any, error) {
+	v, err := e.GetEmbedding(ctx, text)
+	return v, nil, err
+}
+
+func (e *stubEmbedder) GetDimensions() int { return 2 }
+
+type stubTool struct{ decl tool.Declaration }
+
+func (t stubTool) Declaration() *tool.Declaration { return &t.decl }
+
+func appContext(app string) context.Context {
+	inv := agent.NewInvocation(agent.WithInvocationSession(session.NewSession(app, "u", "s")))
+	return agent.NewInvocationContext(context.Background(), inv)
+}
+
+func request(system, question string) *model.Request {
+	return &model.Request{Messages: []model.Message{
+		model.NewSystemMessage(system),
+		model.NewUserMessage(question),
+	}}
+}
+
+func answer(text string) *model.Response {
+	return &model.Response{ID: "r1", Done: true, Usage: &model.Usage{TotalTokens: 10}, Choices: []model.Choice{{
+		Message: model.NewAssistantMessage(text),
+	}}}
+}
+
+// call runs one model call through the callbacks and returns the cached
+// response, or nil after storing resp on a miss.
+func call(
+	t *testing.T,
+	callbacks *model.Callbacks,
+	ctx context.Context,
+	req *model.Request,
+	resp *model.Response,
+) *model.Response {
+	t.Helper()
+	res, err := callbacks.RunBeforeModel(ctx, &model.BeforeModelArgs{Request: req})
+	require.NoError(t, err)
+	if res != nil && res.CustomResponse != nil {
+		return res.CustomResponse
+	}
+	_, err = callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Request: req, Response: resp})
+	require.NoError(t, err)
+	return nil
+}
+
+func TestPlugin_ExactAndSemanticHits(t *testing.T) {
+	emb := &stubEmbedder{vectors: map[string][]float64{
+		"how do i reset my password":  {1, 0},
+		"how can i reset my password": {0.99, 0.1},
+		"what are your opening hours": {0, 1},
+	}}
+	p, err := New(WithEmbedder(emb), WithSimilarityThreshold(0.9))
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+	ctx := appContext("support")
+
+	require.Nil(t, call(t, callbacks, ctx, request("sys", "How do I reset my password?"), answer("Use the reset link.")))
+
+	// Layout and case differences hit exactly without embedding.
+	calls := emb.calls
+	hit := call(t, callbacks, ctx, request("sys", "  how do I   reset my PASSWORD "), nil)
+	require.NotNil(t, hit)
+	require.Equal(t, calls, emb.calls)
+	require.Equal(t, "Use the reset link.", hit.Choices[0].Message.Content)
+	require.Nil(t, hit.Usage)
+	require.NotEqual(t, "r1", hit.ID)
+
+	hit = call(t, callbacks, ctx, request("sys", "How can I reset my password"), nil)
+	require.NotNil(t, hit)
+	require.Equal(t, "Use the reset link.", hit.Choices[0].Message.Content)
+
+	require.Nil(t, call(t, callbacks, ctx, request("sys", "What are your opening hours?"), answer("9 to 5.")))
+	require.Equal(t, 2, p.opts.store.(*MemoryStore).Len())
+}
+
+func TestPlugin_FingerprintSeparatesRequests(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+	ctx := appContext("support")
+	search := stubTool{decl: tool.Declaration{Name: "search", Description: "Search docs."}}
+
+	req := request("sys", "hello")
+	req.Tools = map[string]tool.Tool{"search": search}
+	require.Nil(t, call(t, callbacks, ctx, req, answer("hi")))
+
+	// Other system prompt, other tools, other app: all miss.
+	require.Nil(t, call(t, callbacks, ctx, request("other sys", "hello"), answer("hi")))
+	require.Nil(t, call(t, callbacks, ctx, request("sys", "hello"), answer("hi")))
+	req = request("sys", "hello")
+	req.Tools = map[string]tool.Tool{"search": search}
+	require.Nil(t, call(t, callbacks, appContext("billing"), req, answer("hi")))
+
+	req = request("sys", "hello")
+	req.Tools = map[string]tool.Tool{"search": search}
+	require.NotNil(t, call(t, callbacks, ctx, req, nil))
+}
+
+func TestPlugin_OnlyFinalAnswersAreCached(t *testing.T) {
+	p, err := New(WithTTL(time.Hour))
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+	ctx := appContext("support")
+
+	partial := answer("Use")
+	partial.IsPartial = true
+	req := request("sys", "reset password")
+	_, err = callbacks.RunBeforeModel(ctx, &model.BeforeModelArgs{Request: req})
+	require.NoError(t, err)
+	_, err = callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Request: req, Response: partial})
+	require.NoError(t, err)
+	toolCall := answer("")
+	toolCall.Choices[0].Message.ToolCalls = []model.ToolCall{{ID: "c1"}}
+	_, err = callbacks.RunAfterModel(ctx, &model.AfterModelArgs{Request: req, Response: toolCall})
+	require.NoError(t, err)
+	require.Zero(t, p.opts.store.(*MemoryStore).Len())
+
+	// Conversations continuing after tool calls are never looked up.
+	req = request("sys", "reset password")
+	req.Messages = append(req.Messages, model.Message{Role: model.RoleAssistant, ToolCalls: toolCall.Choices[0].Message.ToolCalls})
+	req.Messages = append(req.Messages, model.NewUserMessage("thanks"))
+	require.Nil(t, p.newEntry(ctx, req))
+	require.Nil(t, p.newEntry(ctx, &model.Request{Messages: []model.Message{model.NewAssistantMessage("hi")}}))
+}
+
+func TestPlugin_TTL(t *testing.T) {
+	store := NewMemoryStore(0)
+	p, err := New(WithStore(store), WithTTL(time.Hour))
+	require.NoError(t, err)
+	callbacks := plugin.MustNewManager(p).ModelCallbacks()
+	ctx := appContext("support")
+
+	require.Nil(t, call(t, callbacks, ctx, request("sys", "hello"), answer("hi")))
+	entry := p.newEntry(ctx, request("sys", "hello"))
+	cached, err := store.Get(ctx, entry.Key)
+	require.NoError(t, err)
+	cached.ExpiresAt = time.Now().Add(-time.Second)
+	require.Nil(t, call(t, callbacks, ctx, request("sys", "hello"), answer("hello again")))
+	hit := call(t, callbacks, ctx, request("sys", "hello"), nil)
+	require.Equal(t, "hello again", hit.Choices[0].Message.Content)
+}
+
+func TestPlugin_MarksHitEvents(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	manager := plugin.MustNewManager(p)
+	ctx := appContext("support")
+	require.Nil(t, call(t, manager.ModelCallbacks(), ctx, request("sys", "hello"), answer("hi")))
+	hit := call(t, manager.ModelCallbacks(), ctx, request("sys", "hello"), nil)
+
+	e := event.NewResponseEvent("inv", "assistant", hit)
+	e.Tag = "existing"
+	out, err := manager.OnEvent(ctx, agent.NewInvocation(), e)
+	require.NoError(t, err)
+	require.True(t, out.ContainsTag(HitTag))
+	require.True(t, out.ContainsTag("existing"))
+	var info Hit
+	require.NoError(t, json.Unmarshal(out.Extensions[HitExtensionKey], &info))
+	require.True(t, info.Exact)
+	require.Equal(t, 1.0, info.Similarity)
+
+	miss := event.NewResponseEvent("inv", "assistant", answer("hi"))
+	out, err = manager.OnEvent(ctx, agent.NewInvocation(), miss)
+	require.NoError(t, err)
+	require.False(t, out.ContainsTag(HitTag))
+}
+
+func TestNew_ValidatesThreshold(t *testing.T) {
+	_, err := New(WithSimilarityThreshold(0))
+	require.Error(t, err)
+	_, err = New(WithSimilarityThreshold(1.5))
+	require.Error(t, err)
+}
+
+func TestNormalizePrompt(t *testing.T) {
+	require.Equal(t, "what is go", NormalizePrompt("  What\tis\n Go?? "))
+	require.Equal(t, "你好", NormalizePrompt("你好。"))
+}
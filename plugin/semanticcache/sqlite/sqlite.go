@@ -0,0 +1,163 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package sqlite provides a SQLite-backed store for the semantic response
+// cache, so cached responses survive process restarts in a single local file.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/plugin/semanticcache"
+)
+
+const defaultTableName = "semantic_cache"
+
+var (
+	_ semanticcache.Store = (*Store)(nil)
+
+	tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Option configures the SQLite store.
+type Option func(*Store)
+
+// WithTableName sets the table that holds cached responses.
+// The default is "semantic_cache".
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.table = name
+	}
+}
+
+// Store is a semanticcache.Store persisting entries in a SQLite table.
+// It expects an initialized *sql.DB and creates the table if needed.
+type Store struct {
+	db    *sql.DB
+	table string
+}
+
+// NewStore creates a store using the provided DB.
+// The DB must use a SQLite driver.
+func NewStore(db *sql.DB, opts ...Option) (*Store, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	s := &Store{db: db, table: defaultTableName}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !tableNamePattern.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	stmts := []string{
+		"CREATE TABLE IF NOT EXISTS " + s.table + " (" +
+			"cache_key TEXT PRIMARY KEY, " +
+			"cache_partition TEXT NOT NULL, " +
+			"entry BLOB NOT NULL, " +
+			"expires_at INTEGER NOT NULL" +
+			")",
+		"CREATE INDEX IF NOT EXISTS " + s.table + "_partition ON " + s.table + " (cache_partition)",
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("create %s table: %w", s.table, err)
+		}
+	}
+	return s, nil
+}
+
+// Get implements semanticcache.Store.
+func (s *Store) Get(ctx context.Context, key string) (*semanticcache.Entry, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT entry FROM "+s.table+" WHERE cache_key = ? AND (expires_at = 0 OR expires_at > ?)",
+		key, time.Now().UnixNano()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select entry: %w", err)
+	}
+	return unmarshalEntry(data)
+}
+
+// Candidates implements semanticcache.Store.
+func (s *Store) Candidates(ctx context.Context, partition string) ([]*semanticcache.Entry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT entry FROM "+s.table+" WHERE cache_partition = ? AND (expires_at = 0 OR expires_at > ?)",
+		partition, time.Now().UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("select entries: %w", err)
+	}
+	defer rows.Close()
+	var entries []*semanticcache.Entry
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan entry: %w", err)
+		}
+		entry, err := unmarshalEntry(data)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Put implements semanticcache.Store.
+func (s *Store) Put(ctx context.Context, entry *semanticcache.Entry) error {
+	if entry == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+	var expiresAt int64
+	if !entry.ExpiresAt.IsZero() {
+		expiresAt = entry.ExpiresAt.UnixNano()
+	}
+	_, err = s.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO "+s.table+" (cache_key, cache_partition, entry, expires_at) VALUES (?, ?, ?, ?)",
+		entry.Key, entry.Partition, data, expiresAt)
+	if err != nil {
+		return fmt.Errorf("insert entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired entries and returns how many were removed.
+// Expired entries are never served, so calling it only reclaims space.
+func (s *Store) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		"DELETE FROM "+s.table+" WHERE expires_at != 0 AND expires_at <= ?", time.Now().UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired entries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func unmarshalEntry(data []byte) (*semanticcache.Entry, error) {
+	var entry semanticcache.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal entry: %w", err)
+	}
+	return &entry, nil
+}
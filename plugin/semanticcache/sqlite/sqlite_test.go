@@ -0,0 +1,93 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Import SQLite driver.
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/plugin/semanticcache"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func entry(key, partition string, expiresAt time.Time) *semanticcache.Entry {
+	return &semanticcache.Entry{
+		Key:       key,
+		Partition: partition,
+		Prompt:    "how do i reset my password",
+		Embedding: []float64{0.5, -1},
+		Response: &model.Response{Done: true, Choices: []model.Choice{{
+			Message: model.NewAssistantMessage("Use the reset link."),
+		}}},
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestStore_PutGetCandidates(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewStore(openDB(t))
+	require.NoError(t, err)
+
+	got, err := s.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, s.Put(ctx, entry("a", "p1", time.Time{})))
+	require.NoError(t, s.Put(ctx, entry("b", "p1", time.Now().Add(time.Hour))))
+	require.NoError(t, s.Put(ctx, entry("c", "p2", time.Time{})))
+	require.NoError(t, s.Put(ctx, entry("d", "p1", time.Now().Add(-time.Second))))
+
+	got, err = s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, []float64{0.5, -1}, got.Embedding)
+	assert.Equal(t, "Use the reset link.", got.Response.Choices[0].Message.Content)
+
+	got, err = s.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	candidates, err := s.Candidates(ctx, "p1")
+	require.NoError(t, err)
+	keys := []string{}
+	for _, c := range candidates {
+		keys = append(keys, c.Key)
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, keys)
+
+	n, err := s.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+}
+
+func TestNewStore_Validation(t *testing.T) {
+	_, err := NewStore(nil)
+	require.Error(t, err)
+	_, err = NewStore(openDB(t), WithTableName("bad name"))
+	require.Error(t, err)
+	_, err = NewStore(openDB(t), WithTableName("custom_cache"))
+	require.NoError(t, err)
+}
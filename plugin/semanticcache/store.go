@@ -0,0 +1,157 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package semanticcache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// Entry is one cached model response.
+type Entry struct {
+	// Key identifies the exact prompt: the partition plus the normalized
+	// latest user turn.
+	Key string `json:"key"`
+	// Partition groups the entries that may answer each other: same scope,
+	// model, system prompt, tool set and earlier conversation.
+	Partition string `json:"partition"`
+	// Prompt is the normalized latest user turn.
+	Prompt string `json:"prompt"`
+	// Embedding is the embedding of the latest user turn. It is empty when
+	// no embedder is configured.
+	Embedding []float64 `json:"embedding,omitempty"`
+	// Response is the cached final response.
+	Response *model.Response `json:"response"`
+	// CreatedAt is when the entry was stored.
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is when the entry stops being served. Zero never expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the entry is expired at now.
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Store persists cached responses.
+//
+// Implementations must be safe for concurrent use and should not return
+// expired entries. The plugin never modifies returned entries.
+type Store interface {
+	// Get returns the entry stored under key. A miss returns nil and no
+	// error.
+	Get(ctx context.Context, key string) (*Entry, error)
+	// Candidates returns the entries of a partition for similarity search.
+	Candidates(ctx context.Context, partition string) ([]*Entry, error)
+	// Put stores entry, replacing any entry with the same key.
+	Put(ctx context.Context, entry *Entry) error
+}
+
+// MemoryStore is an in-process Store that evicts the least recently used
+// entry once it holds more than its capacity.
+type MemoryStore struct {
+	mu         sync.Mutex
+	capacity   int
+	ll         *list.List
+	items      map[string]*list.Element
+	partitions map[string]map[string]struct{}
+}
+
+// NewMemoryStore creates an LRU store holding at most capacity entries. A
+// capacity of zero or less uses the default of 10000.
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+	return &MemoryStore{
+		capacity:   capacity,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		partitions: make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := elem.Value.(*Entry)
+	if entry.Expired(time.Now()) {
+		s.remove(elem)
+		return nil, nil
+	}
+	s.ll.MoveToFront(elem)
+	return entry, nil
+}
+
+// Candidates implements Store.
+func (s *MemoryStore) Candidates(_ context.Context, partition string) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var out []*Entry
+	for key := range s.partitions[partition] {
+		elem := s.items[key]
+		entry := elem.Value.(*Entry)
+		if entry.Expired(now) {
+			s.remove(elem)
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, entry *Entry) error {
+	if entry == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[entry.Key]; ok {
+		s.remove(elem)
+	}
+	s.items[entry.Key] = s.ll.PushFront(entry)
+	if s.partitions[entry.Partition] == nil {
+		s.partitions[entry.Partition] = make(map[string]struct{})
+	}
+	s.partitions[entry.Partition][entry.Key] = struct{}{}
+	for s.ll.Len() > s.capacity {
+		s.remove(s.ll.Back())
+	}
+	return nil
+}
+
+// Len returns the number of cached entries, including expired entries not
+// yet evicted.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	entry := s.ll.Remove(elem).(*Entry)
+	delete(s.items, entry.Key)
+	if keys := s.partitions[entry.Partition]; keys != nil {
+		delete(keys, entry.Key)
+		if len(keys) == 0 {
+			delete(s.partitions, entry.Partition)
+		}
+	}
+}
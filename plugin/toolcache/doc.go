@@ -0,0 +1,12 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+// Package toolcache provides a runner-scoped plugin that caches the results
+// of tools declared cacheable, keyed by tool name and canonicalized JSON
+// arguments, and drops them when a declared mutating tool runs.
+package toolcache
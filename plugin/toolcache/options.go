@@ -0,0 +1,130 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package toolcache
+
+import "time"
+
+const (
+	defaultPluginName     = "tool_cache"
+	defaultTTL            = 5 * time.Minute
+	defaultMaxEntries     = 1000
+	defaultMaxResultBytes = 1 << 20
+)
+
+// Scope controls which calls share cached results.
+type Scope string
+
+const (
+	// ScopeSession shares results within one session.
+	ScopeSession Scope = "session"
+	// ScopeUser shares results across the sessions of one user of an app.
+	ScopeUser Scope = "user"
+	// ScopeApp shares results across all users of an app.
+	ScopeApp Scope = "app"
+)
+
+// Policy declares how results of a cacheable tool are cached. Zero fields
+// use the plugin defaults set by WithTTL and WithScope.
+type Policy struct {
+	// TTL is how long a result is served.
+	TTL time.Duration
+	// Scope controls which calls share a result.
+	Scope Scope
+}
+
+// Option configures the tool cache plugin.
+type Option func(*options)
+
+type options struct {
+	name           string
+	ttl            time.Duration
+	scope          Scope
+	maxEntries     int
+	maxResultBytes int
+	cacheable      map[string]Policy
+	mutating       map[string][]string
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		name:           defaultPluginName,
+		ttl:            defaultTTL,
+		scope:          ScopeSession,
+		maxEntries:     defaultMaxEntries,
+		maxResultBytes: defaultMaxResultBytes,
+		cacheable:      make(map[string]Policy),
+		mutating:       make(map[string][]string),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithName sets the plugin name. The name must be unique within a Runner. An
+// empty name is ignored and keeps the default name.
+func WithName(name string) Option {
+	return func(o *options) {
+		if name != "" {
+			o.name = name
+		}
+	}
+}
+
+// WithCacheableTool marks a tool as cacheable. Only read-only tools whose
+// result depends on nothing but their arguments within the scope should be
+// marked.
+func WithCacheableTool(name string, policy Policy) Option {
+	return func(o *options) {
+		o.cacheable[name] = policy
+	}
+}
+
+// WithMutatingTool declares that running the named tool changes what the
+// listed cacheable tools return. Each run drops every cached result of
+// those tools in all scopes, or of all cacheable tools when none are
+// listed.
+func WithMutatingTool(name string, invalidates ...string) Option {
+	return func(o *options) {
+		o.mutating[name] = append([]string(nil), invalidates...)
+	}
+}
+
+// WithTTL sets the default time a result is served. The default is five
+// minutes.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithScope sets the default scope. The default is ScopeSession.
+func WithScope(scope Scope) Option {
+	return func(o *options) {
+		o.scope = scope
+	}
+}
+
+// WithMaxEntries sets how many results are kept before the least recently
+// used one is evicted. The default is 1000.
+func WithMaxEntries(n int) Option {
+	return func(o *options) {
+		o.maxEntries = n
+	}
+}
+
+// WithMaxResultBytes sets the largest JSON-encoded result that is cached.
+// Larger results are returned normally but not cached. The default is 1 MiB.
+func WithMaxResultBytes(n int) Option {
+	return func(o *options) {
+		o.maxResultBytes = n
+	}
+}
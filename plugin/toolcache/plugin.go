@@ -0,0 +1,415 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package toolcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	pluginbase "trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	// HitTag is added to the tag of tool response events that contain at
+	// least one cached result.
+	HitTag = "tool_cache_hit"
+	// HitExtensionKey is the event extension listing the cached results of a
+	// tool response event as []Hit.
+	HitExtensionKey = "trpc_agent.tool_cache"
+
+	// hitTTL bounds how long served hits are remembered until their tool
+	// response event is emitted, and how long calls are remembered until
+	// their result arrives.
+	hitTTL = 10 * time.Minute
+)
+
+// Hit describes a tool result served from the cache.
+type Hit struct {
+	// ToolCallID is the ID of the tool call that was answered.
+	ToolCallID string `json:"tool_call_id"`
+	// ToolName is the name of the tool.
+	ToolName string `json:"tool_name"`
+	// CachedAt is when the served result was stored.
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// Plugin caches results of cacheable tools.
+//
+// Cached results are shared by reference between calls, so tools and
+// callbacks must not mutate the result values they return or receive.
+type Plugin struct {
+	opts *options
+	now  func() time.Time
+
+	mu     sync.Mutex
+	ll     *list.List
+	items  map[string]*list.Element
+	byTool map[string]map[string]*list.Element
+	hits   map[string]servedHit
+	// gens counts the invalidations of each tool and allGen those of all
+	// tools, so a result computed across an invalidation is not stored.
+	gens   map[string]uint64
+	allGen uint64
+	calls  map[string]startedCall
+}
+
+type entry struct {
+	key       string
+	tool      string
+	result    any
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+type servedHit struct {
+	hit Hit
+	at  time.Time
+}
+
+// startedCall is a call of a cacheable tool that missed the cache.
+type startedCall struct {
+	gen uint64
+	at  time.Time
+}
+
+// New creates a tool cache plugin.
+func New(opts ...Option) (*Plugin, error) {
+	o := newOptions(opts...)
+	if o.ttl <= 0 {
+		return nil, fmt.Errorf("newing tool cache plugin: ttl must be positive, got %v", o.ttl)
+	}
+	if o.maxEntries <= 0 {
+		return nil, fmt.Errorf("newing tool cache plugin: max entries must be positive, got %d", o.maxEntries)
+	}
+	if err := validScope(o.scope); err != nil {
+		return nil, fmt.Errorf("newing tool cache plugin: %w", err)
+	}
+	for name, policy := range o.cacheable {
+		if policy.TTL < 0 {
+			return nil, fmt.Errorf("newing tool cache plugin: tool %q: negative ttl %v", name, policy.TTL)
+		}
+		if policy.Scope != "" {
+			if err := validScope(policy.Scope); err != nil {
+				return nil, fmt.Errorf("newing tool cache plugin: tool %q: %w", name, err)
+			}
+		}
+		if _, ok := o.mutating[name]; ok {
+			return nil, fmt.Errorf("newing tool cache plugin: tool %q is both cacheable and mutating", name)
+		}
+	}
+	return &Plugin{
+		opts:   o,
+		now:    time.Now,
+		ll:     list.New(),
+		items:  make(map[string]*list.Element),
+		byTool: make(map[string]map[string]*list.Element),
+		hits:   make(map[string]servedHit),
+		gens:   make(map[string]uint64),
+		calls:  make(map[string]startedCall),
+	}, nil
+}
+
+func validScope(scope Scope) error {
+	switch scope {
+	case ScopeSession, ScopeUser, ScopeApp:
+		return nil
+	default:
+		return fmt.Errorf("unknown scope %q", scope)
+	}
+}
+
+// Name implements plugin.Plugin.
+func (p *Plugin) Name() string {
+	if p == nil {
+		return ""
+	}
+	return p.opts.name
+}
+
+// Register implements plugin.Plugin.
+func (p *Plugin) Register(r *pluginbase.Registry) {
+	if p == nil || r == nil {
+		return
+	}
+	r.BeforeTool(p.beforeTool)
+	r.AfterTool(p.afterTool)
+	r.OnEvent(p.onEvent)
+}
+
+// Invalidate drops every cached result of the named tools, or of all tools
+// when none are named.
+func (p *Plugin) Invalidate(toolNames ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invalidateLocked(toolNames)
+}
+
+// Len returns the number of cached results, including expired results not
+// yet evicted.
+func (p *Plugin) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ll.Len()
+}
+
+func (p *Plugin) beforeTool(
+	ctx context.Context,
+	args *tool.BeforeToolArgs,
+) (*tool.BeforeToolResult, error) {
+	if args == nil {
+		return nil, nil
+	}
+	if invalidates, ok := p.opts.mutating[args.ToolName]; ok {
+		// Drop results before the mutation runs too, so calls running in
+		// parallel with it are not answered from the old state.
+		p.Invalidate(invalidates...)
+		return nil, nil
+	}
+	key, _, ok := p.key(ctx, args.ToolName, args.Arguments)
+	if !ok {
+		return nil, nil
+	}
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pruneHitsLocked(now)
+	elem, ok := p.items[key]
+	if ok && !now.Before(elem.Value.(*entry).expiresAt) {
+		p.removeLocked(elem)
+		ok = false
+	}
+	if !ok {
+		// Remember the generation the call started in, so afterTool can
+		// tell whether the result may predate an invalidation.
+		p.calls[args.ToolCallID] = startedCall{gen: p.genLocked(args.ToolName), at: now}
+		return nil, nil
+	}
+	e := elem.Value.(*entry)
+	p.ll.MoveToFront(elem)
+	p.hits[args.ToolCallID] = servedHit{
+		hit: Hit{ToolCallID: args.ToolCallID, ToolName: args.ToolName, CachedAt: e.createdAt},
+		at:  now,
+	}
+	return &tool.BeforeToolResult{CustomResult: e.result, SkipStateDelta: true}, nil
+}
+
+func (p *Plugin) afterTool(
+	ctx context.Context,
+	args *tool.AfterToolArgs,
+) (*tool.AfterToolResult, error) {
+	if args == nil {
+		return nil, nil
+	}
+	if invalidates, ok := p.opts.mutating[args.ToolName]; ok {
+		// Runs whether or not the tool failed: a failed mutation may still
+		// have changed something.
+		p.Invalidate(invalidates...)
+		return nil, nil
+	}
+	key, policy, ok := p.key(ctx, args.ToolName, args.Arguments)
+	if !ok {
+		return nil, nil
+	}
+	p.mu.Lock()
+	call, started := p.calls[args.ToolCallID]
+	delete(p.calls, args.ToolCallID)
+	p.mu.Unlock()
+	if !started || args.Error != nil || args.Result == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(args.Result)
+	if err != nil || len(encoded) > p.opts.maxResultBytes {
+		return nil, nil
+	}
+	ttl := policy.TTL
+	if ttl == 0 {
+		ttl = p.opts.ttl
+	}
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if call.gen != p.genLocked(args.ToolName) {
+		// The tool was invalidated while the call ran, so the result may
+		// reflect the state before the mutation.
+		return nil, nil
+	}
+	if elem, ok := p.items[key]; ok {
+		p.removeLocked(elem)
+	}
+	elem := p.ll.PushFront(&entry{
+		key:       key,
+		tool:      args.ToolName,
+		result:    args.Result,
+		createdAt: now,
+		expiresAt: now.Add(ttl),
+	})
+	p.items[key] = elem
+	if p.byTool[args.ToolName] == nil {
+		p.byTool[args.ToolName] = make(map[string]*list.Element)
+	}
+	p.byTool[args.ToolName][key] = elem
+	for p.ll.Len() > p.opts.maxEntries {
+		p.removeLocked(p.ll.Back())
+	}
+	return nil, nil
+}
+
+func (p *Plugin) onEvent(
+	_ context.Context,
+	_ *agent.Invocation,
+	e *event.Event,
+) (*event.Event, error) {
+	if e == nil || e.Response == nil || e.Response.IsPartial {
+		return nil, nil
+	}
+	var hits []Hit
+	p.mu.Lock()
+	for _, choice := range e.Response.Choices {
+		if choice.Message.Role != model.RoleTool || choice.Message.ToolID == "" {
+			continue
+		}
+		if served, ok := p.hits[choice.Message.ToolID]; ok {
+			hits = append(hits, served.hit)
+			delete(p.hits, choice.Message.ToolID)
+		}
+	}
+	p.mu.Unlock()
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	if !e.ContainsTag(HitTag) {
+		if e.Tag == "" {
+			e.Tag = HitTag
+		} else {
+			e.Tag += event.TagDelimiter + HitTag
+		}
+	}
+	if err := event.SetExtension(e, HitExtensionKey, hits); err != nil {
+		return nil, fmt.Errorf("set tool cache hit extension: %w", err)
+	}
+	return e, nil
+}
+
+// key returns the cache key of a call of a cacheable tool. It reports false
+// for tools that are not cacheable, calls outside a session scope and
+// arguments that are not valid JSON.
+func (p *Plugin) key(ctx context.Context, toolName string, arguments []byte) (string, Policy, bool) {
+	policy, ok := p.opts.cacheable[toolName]
+	if !ok {
+		return "", Policy{}, false
+	}
+	scope := policy.Scope
+	if scope == "" {
+		scope = p.opts.scope
+	}
+	scopeKey, ok := scopeKey(ctx, scope)
+	if !ok {
+		return "", Policy{}, false
+	}
+	args, ok := canonicalArguments(arguments)
+	if !ok {
+		return "", Policy{}, false
+	}
+	sum := sha256.Sum256([]byte(toolName + "\x00" + scopeKey + "\x00" + args))
+	return hex.EncodeToString(sum[:]), policy, true
+}
+
+func scopeKey(ctx context.Context, scope Scope) (string, bool) {
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil || inv.Session == nil {
+		return "", false
+	}
+	sess := inv.Session
+	switch scope {
+	case ScopeApp:
+		return "app\x00" + sess.AppName, true
+	case ScopeUser:
+		return "user\x00" + sess.AppName + "\x00" + sess.UserID, true
+	default:
+		return "session\x00" + sess.AppName + "\x00" + sess.UserID + "\x00" + sess.ID, true
+	}
+}
+
+// canonicalArguments re-encodes JSON arguments with sorted object keys and
+// no insignificant white space, so equivalent calls share a key. Empty
+// arguments are the empty object.
+func canonicalArguments(arguments []byte) (string, bool) {
+	if len(bytes.TrimSpace(arguments)) == 0 {
+		return "{}", true
+	}
+	dec := json.NewDecoder(bytes.NewReader(arguments))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return "", false
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", false
+	}
+	return string(bytes.TrimSpace(buf.Bytes())), true
+}
+
+// genLocked returns the invalidation generation of a tool.
+func (p *Plugin) genLocked(toolName string) uint64 {
+	return p.allGen + p.gens[toolName]
+}
+
+func (p *Plugin) invalidateLocked(toolNames []string) {
+	if len(toolNames) == 0 {
+		p.allGen++
+		p.ll.Init()
+		p.items = make(map[string]*list.Element)
+		p.byTool = make(map[string]map[string]*list.Element)
+		return
+	}
+	for _, name := range toolNames {
+		p.gens[name]++
+		for _, elem := range p.byTool[name] {
+			p.removeLocked(elem)
+		}
+	}
+}
+
+func (p *Plugin) removeLocked(elem *list.Element) {
+	e := p.ll.Remove(elem).(*entry)
+	delete(p.items, e.key)
+	if keys := p.byTool[e.tool]; keys != nil {
+		delete(keys, e.key)
+		if len(keys) == 0 {
+			delete(p.byTool, e.tool)
+		}
+	}
+}
+
+func (p *Plugin) pruneHitsLocked(now time.Time) {
+	for id, served := range p.hits {
+		if now.Sub(served.at) > hitTTL {
+			delete(p.hits, id)
+		}
+	}
+	for id, call := range p.calls {
+		if now.Sub(call.at) > hitTTL {
+			delete(p.calls, id)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package toolcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	pluginbase "trpc.group/trpc-go/trpc-agent-go/plugin"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+func sessionContext(app, user, id string) context.Context {
+	inv := agent.NewInvocation(agent.WithInvocationSession(session.NewSession(app, user, id)))
+	return agent.NewInvocationContext(context.Background(), inv)
+}
+
+// call runs one tool call through the plugin and returns the result and
+// whether it came from the cache.
+func call(
+	t *testing.T,
+	p *Plugin,
+	ctx context.Context,
+	name, args string,
+	result any,
+) (any, bool) {
+	t.Helper()
+	before, err := p.beforeTool(ctx, &tool.BeforeToolArgs{
+		ToolCallID: "call-" + name, ToolName: name, Arguments: []byte(args),
+	})
+	require.NoError(t, err)
+	if before != nil && before.CustomResult != nil {
+		require.True(t, before.SkipStateDelta)
+		return before.CustomResult, true
+	}
+	_, err = p.afterTool(ctx, &tool.AfterToolArgs{
+		ToolCallID: "call-" + name, ToolName: name, Arguments: []byte(args), Result: result,
+	})
+	require.NoError(t, err)
+	return result, false
+}
+
+func TestPlugin_CachesByCanonicalArguments(t *testing.T) {
+	p, err := New(WithCacheableTool("search", Policy{}))
+	require.NoError(t, err)
+	ctx := sessionContext("app", "u1", "s1")
+
+	_, hit := call(t, p, ctx, "search", `{"q":"go","limit":5}`, "first")
+	require.False(t, hit)
+	got, hit := call(t, p, ctx, "search", "{ \"limit\": 5,\n \"q\": \"go\" }", "second")
+	require.True(t, hit)
+	require.Equal(t, "first", got)
+
+	_, hit = call(t, p, ctx, "search", `{"q":"go","limit":6}`, "third")
+	require.False(t, hit)
+	_, hit = call(t, p, ctx, "fetch", `{"q":"go","limit":5}`, "other tool")
+	require.False(t, hit)
+	require.Equal(t, 2, p.Len())
+}
+
+func TestPlugin_Scopes(t *testing.T) {
+	p, err := New(
+		WithCacheableTool("search", Policy{}),
+		WithCacheableTool("catalog", Policy{Scope: ScopeApp}),
+		WithCacheableTool("profile", Policy{Scope: ScopeUser}),
+	)
+	require.NoError(t, err)
+	s1 := sessionContext("app", "u1", "s1")
+	s2 := sessionContext("app", "u1", "s2")
+	other := sessionContext("app", "u2", "s3")
+
+	for _, name := range []string{"search", "catalog", "profile"} {
+		_, hit := call(t, p, s1, name, `{}`, name)
+		require.False(t, hit)
+	}
+	_, hit := call(t, p, s2, "search", `{}`, "x")
+	require.False(t, hit)
+	_, hit = call(t, p, s2, "profile", `{}`, "x")
+	require.True(t, hit)
+	_, hit = call(t, p, other, "profile", `{}`, "x")
+	require.False(t, hit)
+	_, hit = call(t, p, other, "catalog", ``, "x")
+	require.True(t, hit)
+
+	// Without a session there is no scope to cache in.
+	_, hit = call(t, p, context.Background(), "catalog", `{}`, "x")
+	require.False(t, hit)
+	_, hit = call(t, p, context.Background(), "catalog", `{}`, "x")
+	require.False(t, hit)
+}
+
+func TestPlugin_TTLAndLimits(t *testing.T) {
+	p, err := New(
+		WithCacheableTool("search", Policy{}),
+		WithCacheableTool("quote", Policy{TTL: time.Second}),
+		WithMaxEntries(2),
+		WithMaxResultBytes(16),
+	)
+	require.NoError(t, err)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	ctx := sessionContext("app", "u1", "s1")
+
+	call(t, p, ctx, "quote", `{"s":"A"}`, 1)
+	now = now.Add(2 * time.Second)
+	_, hit := call(t, p, ctx, "quote", `{"s":"A"}`, 2)
+	require.False(t, hit)
+	_, hit = call(t, p, ctx, "quote", `{"s":"A"}`, 3)
+	require.True(t, hit)
+
+	call(t, p, ctx, "search", `{"q":"big"}`, "a result longer than sixteen bytes")
+	require.Equal(t, 1, p.Len())
+
+	call(t, p, ctx, "search", `{"q":"1"}`, 1)
+	call(t, p, ctx, "search", `{"q":"2"}`, 2)
+	require.Equal(t, 2, p.Len())
+	_, hit = call(t, p, ctx, "quote", `{"s":"A"}`, 4)
+	require.False(t, hit)
+
+	// Failed calls are not cached.
+	_, err = p.beforeTool(ctx, &tool.BeforeToolArgs{
+		ToolCallID: "failed", ToolName: "search", Arguments: []byte(`{"q":"3"}`),
+	})
+	require.NoError(t, err)
+	_, err = p.afterTool(ctx, &tool.AfterToolArgs{
+		ToolCallID: "failed", ToolName: "search", Arguments: []byte(`{"q":"3"}`),
+		Result: "partial", Error: errors.New("boom"),
+	})
+	require.NoError(t, err)
+	_, hit = call(t, p, ctx, "search", `{"q":"3"}`, "ok")
+	require.False(t, hit)
+}
+
+func TestPlugin_MutatingToolsInvalidate(t *testing.T) {
+	p, err := New(
+		WithCacheableTool("get_order", Policy{Scope: ScopeApp}),
+		WithCacheableTool("search", Policy{}),
+		WithMutatingTool("update_order", "get_order"),
+		WithMutatingTool("reset"),
+	)
+	require.NoError(t, err)
+	ctx := sessionContext("app", "u1", "s1")
+
+	call(t, p, ctx, "get_order", `{"id":1}`, "pending")
+	call(t, p, ctx, "search", `{}`, "x")
+	call(t, p, sessionContext("app", "u2", "s2"), "update_order", `{"id":1}`, "ok")
+	_, hit := call(t, p, ctx, "get_order", `{"id":1}`, "shipped")
+	require.False(t, hit)
+	_, hit = call(t, p, ctx, "search", `{}`, "x")
+	require.True(t, hit)
+
+	call(t, p, ctx, "reset", `{}`, "ok")
+	require.Zero(t, p.Len())
+
+	call(t, p, ctx, "search", `{}`, "x")
+	p.Invalidate("search")
+	require.Zero(t, p.Len())
+}
+
+func TestPlugin_InvalidationDuringCall(t *testing.T) {
+	p, err := New(
+		WithCacheableTool("get_order", Policy{}),
+		WithMutatingTool("update_order", "get_order"),
+	)
+	require.NoError(t, err)
+	ctx := sessionContext("app", "u1", "s1")
+	get := func(id string) {
+		_, err := p.beforeTool(ctx, &tool.BeforeToolArgs{
+			ToolCallID: id, ToolName: "get_order", Arguments: []byte(`{"id":1}`),
+		})
+		require.NoError(t, err)
+	}
+	got := func(id string, result any) {
+		_, err := p.afterTool(ctx, &tool.AfterToolArgs{
+			ToolCallID: id, ToolName: "get_order", Arguments: []byte(`{"id":1}`), Result: result,
+		})
+		require.NoError(t, err)
+	}
+
+	// A read that started before the update finished after it: its result
+	// may be stale and is not stored.
+	get("read")
+	call(t, p, ctx, "update_order", `{"id":1}`, "ok")
+	got("read", "pending")
+	require.Zero(t, p.Len())
+
+	// The same holds for Invalidate.
+	get("read")
+	p.Invalidate()
+	got("read", "pending")
+	require.Zero(t, p.Len())
+
+	// A read that started after the update is stored.
+	get("read")
+	got("read", "shipped")
+	result, hit := call(t, p, ctx, "get_order", `{"id":1}`, "unused")
+	require.True(t, hit)
+	require.Equal(t, "shipped", result)
+}
+
+func TestPlugin_MarksHitEvents(t *testing.T) {
+	p, err := New(WithCacheableTool("search", Policy{}))
+	require.NoError(t, err)
+	manager := pluginbase.MustNewManager(p)
+	ctx := sessionContext("app", "u1", "s1")
+	call(t, p, ctx, "search", `{}`, "x")
+	_, hit := call(t, p, ctx, "search", `{}`, "x")
+	require.True(t, hit)
+
+	e := event.NewResponseEvent("inv", "assistant", &model.Response{Choices: []model.Choice{
+		{Message: model.Message{Role: model.RoleTool, ToolID: "call-other", Content: "y"}},
+		{Message: model.Message{Role: model.RoleTool, ToolID: "call-search", Content: "x"}},
+	}})
+	out, err := manager.OnEvent(ctx, agent.NewInvocation(), e)
+	require.NoError(t, err)
+	require.True(t, out.ContainsTag(HitTag))
+	var hits []Hit
+	require.NoError(t, json.Unmarshal(out.Extensions[HitExtensionKey], &hits))
+	require.Len(t, hits, 1)
+	require.Equal(t, "call-search", hits[0].ToolCallID)
+	require.Equal(t, "search", hits[0].ToolName)
+
+	e = event.NewResponseEvent("inv", "assistant", &model.Response{Choices: []model.Choice{
+		{Message: model.Message{Role: model.RoleTool, ToolID: "call-search", Content: "x"}},
+	}})
+	out, err = manager.OnEvent(ctx, agent.NewInvocation(), e)
+	require.NoError(t, err)
+	require.False(t, out.ContainsTag(HitTag))
+}
+
+func TestNew_Validation(t *testing.T) {
+	_, err := New(WithTTL(0))
+	require.Error(t, err)
+	_, err = New(WithMaxEntries(0))
+	require.Error(t, err)
+	_, err = New(WithScope("tenant"))
+	require.Error(t, err)
+	_, err = New(WithCacheableTool("search", Policy{Scope: "tenant"}))
+	require.ErrorContains(t, err, "search")
+	_, err = New(WithCacheableTool("search", Policy{}), WithMutatingTool("search"))
+	require.ErrorContains(t, err, "both")
+}
@@ -17,9 +17,11 @@
 // placeholders in the same template. SyntaxSingleBrace and SyntaxDoubleBrace
 // restrict recognition to one delimiter style.
 //
-// Double-curly placeholders are treated as variable substitution only; this
-// package does not implement full Mustache control syntax such as sections or
-// partials.
+// Text.Render treats placeholders as variable substitution only. Text.Compile
+// turns a text into a Template that adds a small sandboxed logic dialect on
+// top of the same syntax: {{#if}}/{{else}}, {{#each}} over lists, and
+// {{> name}} partials resolved from a Source, with static validation of the
+// referenced variables.
 //
 // More advanced behaviors such as few-shot assembly, chat prompt composition,
 // and remote prompt registries remain in their existing packages for now.
@@ -0,0 +1,124 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGitReloadInterval = time.Minute
+	gitTerminalPromptEnv     = "GIT_TERMINAL_PROMPT=0"
+)
+
+// WithGitRef sets the branch or tag served by NewGit. The default is the
+// remote HEAD.
+func WithGitRef(ref string) Option {
+	return func(o *options) {
+		o.gitRef = ref
+	}
+}
+
+// WithSubdir serves the prompts under a subdirectory of the repository.
+func WithSubdir(dir string) Option {
+	return func(o *options) {
+		o.subdir = dir
+	}
+}
+
+// WithCheckoutDir sets where NewGit checks out the repository. An existing
+// checkout is reused and kept on Close. By default a temporary directory is
+// used and removed on Close.
+func WithCheckoutDir(dir string) Option {
+	return func(o *options) {
+		o.checkoutDir = dir
+	}
+}
+
+// NewGit creates a registry serving the prompts of a git repository. The
+// repository is shallow-cloned, and every reload fetches the ref and
+// resets the checkout to it, so merged prompt changes go live without a
+// redeploy.
+func NewGit(ctx context.Context, url string, opts ...Option) (*Registry, error) {
+	if url == "" {
+		return nil, errors.New("registry: empty git url")
+	}
+	o := newOptions(defaultGitReloadInterval, opts)
+
+	dir := o.checkoutDir
+	var cleanup func() error
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "prompt-registry-")
+		if err != nil {
+			return nil, fmt.Errorf("registry: create checkout dir: %w", err)
+		}
+		dir = tmp
+		cleanup = func() error { return os.RemoveAll(tmp) }
+	}
+
+	syncFn := func(ctx context.Context) error {
+		return syncGit(ctx, dir, url, o.gitRef)
+	}
+	if err := syncFn(ctx); err != nil {
+		if cleanup != nil {
+			_ = cleanup()
+		}
+		return nil, err
+	}
+	root := dir
+	if o.subdir != "" {
+		root = filepath.Join(dir, filepath.FromSlash(o.subdir))
+	}
+	return newRegistry(root, o, syncFn, cleanup)
+}
+
+// syncGit clones url into dir, or updates an existing checkout to the
+// latest commit of ref.
+func syncGit(ctx context.Context, dir, url, ref string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		args := []string{"clone", "--depth", "1"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		args = append(args, "--", url, dir)
+		if err := runGit(ctx, "", args...); err != nil {
+			return fmt.Errorf("registry: git clone %s: %w", url, err)
+		}
+		return nil
+	}
+	target := ref
+	if target == "" {
+		target = "HEAD"
+	}
+	if err := runGit(ctx, dir, "fetch", "--depth", "1", "origin", target); err != nil {
+		return fmt.Errorf("registry: git fetch %s: %w", url, err)
+	}
+	if err := runGit(ctx, dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("registry: git reset: %w", err)
+	}
+	return nil
+}
+
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), gitTerminalPromptEnv)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
+)
+
+const manifestFileName = "prompt.yaml"
+
+// versionExtensions are the file extensions recognized as prompt versions.
+var versionExtensions = map[string]bool{
+	".txt":  true,
+	".md":   true,
+	".tmpl": true,
+}
+
+// manifest is the optional prompt.yaml of a prompt directory.
+type manifest struct {
+	Syntax string                `yaml:"syntax"`
+	Labels map[string]labelValue `yaml:"labels"`
+}
+
+// labelValue is either a single version or a map of version to rollout
+// weight.
+type labelValue struct {
+	weights map[string]int
+}
+
+func (l *labelValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		l.weights = map[string]int{node.Value: 1}
+		return nil
+	}
+	var weights map[string]int
+	if err := node.Decode(&weights); err != nil {
+		return fmt.Errorf("label must be a version or a map of version to weight: %w", err)
+	}
+	l.weights = weights
+	return nil
+}
+
+// entry is one loaded prompt.
+type entry struct {
+	name     string
+	syntax   prompt.Syntax
+	versions map[string]string
+	// order lists versions from oldest to newest.
+	order  []string
+	labels map[string]rollout
+}
+
+// rollout is a weighted choice between versions.
+type rollout struct {
+	versions []string
+	weights  []int
+	total    int
+}
+
+// pick selects a version for key. Equal keys get equal versions, and a
+// key keeps its version when weights of other versions change only if
+// its bucket does not move, which makes ramps mostly sticky.
+func (r rollout) pick(name, label, key string, randInt func(int) int) string {
+	if len(r.versions) == 1 {
+		return r.versions[0]
+	}
+	var n int
+	if key == "" {
+		n = randInt(r.total)
+	} else {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name + "\x00" + label + "\x00" + key))
+		n = int(h.Sum32() % uint32(r.total))
+	}
+	for i, w := range r.weights {
+		if n < w {
+			return r.versions[i]
+		}
+		n -= w
+	}
+	return r.versions[len(r.versions)-1]
+}
+
+// snapshot is an immutable view of all prompts under a root.
+type snapshot struct {
+	entries map[string]*entry
+}
+
+// loadSnapshot reads every prompt directory under root. A prompt directory
+// is any directory holding at least one version file; its path relative to
+// root, with forward slashes, is the prompt name.
+func loadSnapshot(root string) (*snapshot, error) {
+	snap := &snapshot{entries: make(map[string]*entry)}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		e, err := loadEntry(root, p)
+		if err != nil {
+			return err
+		}
+		if e != nil {
+			snap.entries[e.name] = e
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+func loadEntry(root, dir string) (*entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{
+		name:     path.Clean(filepath.ToSlash(rel)),
+		versions: make(map[string]string),
+		labels:   make(map[string]rollout),
+	}
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || !versionExtensions[ext] {
+			continue
+		}
+		version := strings.TrimSuffix(f.Name(), ext)
+		if _, dup := e.versions[version]; dup {
+			return nil, fmt.Errorf("prompt %q: duplicate version %q", e.name, version)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		e.versions[version] = string(data)
+		e.order = append(e.order, version)
+	}
+	if len(e.versions) == 0 {
+		return nil, nil
+	}
+	if e.name == "." {
+		return nil, errors.New("prompt files must be placed in a prompt directory, not the root")
+	}
+	sort.Slice(e.order, func(i, j int) bool {
+		return versionLess(e.order[i], e.order[j])
+	})
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return e, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("prompt %q: parse %s: %w", e.name, manifestFileName, err)
+	}
+	if e.syntax, err = parseSyntax(m.Syntax); err != nil {
+		return nil, fmt.Errorf("prompt %q: %w", e.name, err)
+	}
+	for label, v := range m.Labels {
+		r, err := e.newRollout(v.weights)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %q: label %q: %w", e.name, label, err)
+		}
+		e.labels[label] = r
+	}
+	return e, nil
+}
+
+func (e *entry) newRollout(weights map[string]int) (rollout, error) {
+	var r rollout
+	for _, version := range e.order {
+		w, ok := weights[version]
+		if !ok {
+			continue
+		}
+		if w < 0 {
+			return rollout{}, fmt.Errorf("negative weight for version %q", version)
+		}
+		if w == 0 {
+			continue
+		}
+		r.versions = append(r.versions, version)
+		r.weights = append(r.weights, w)
+		r.total += w
+	}
+	for version := range weights {
+		if _, ok := e.versions[version]; !ok {
+			return rollout{}, fmt.Errorf("unknown version %q", version)
+		}
+	}
+	if r.total == 0 {
+		return rollout{}, errors.New("no version with positive weight")
+	}
+	return r, nil
+}
+
+func (e *entry) latest() string {
+	return e.order[len(e.order)-1]
+}
+
+func parseSyntax(s string) (prompt.Syntax, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "mixed":
+		return prompt.SyntaxMixedBrace, nil
+	case "single":
+		return prompt.SyntaxSingleBrace, nil
+	case "double":
+		return prompt.SyntaxDoubleBrace, nil
+	default:
+		return 0, fmt.Errorf("unknown syntax %q", s)
+	}
+}
+
+// versionLess orders versions numerically when both are numbers, with an
+// optional "v" prefix, and lexically otherwise.
+func versionLess(a, b string) bool {
+	na, errA := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	switch {
+	case errA == nil && errB == nil && na != nb:
+		return na < nb
+	case errA == nil && errB != nil:
+		return true
+	case errA != nil && errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+// fingerprint summarizes the files under root so reloads can be skipped
+// when nothing changed.
+func fingerprint(root string) (string, error) {
+	h := fnv.New64a()
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(h.Sum64(), 16), nil
+}
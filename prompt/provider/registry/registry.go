@@ -0,0 +1,373 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+// Package registry serves versioned text prompts from a directory or a git
+// repository, with labels, percentage rollouts and hot reload.
+//
+// Every directory holding version files is a prompt; its path relative to
+// the root is the prompt name:
+//
+//	prompts/
+//	  support/triage/
+//	    1.txt
+//	    2.txt
+//	    prompt.yaml
+//
+// Version files end in .txt, .md or .tmpl and the file name without the
+// extension is the version. Numeric versions are ordered numerically, so
+// the latest version of the prompt above is "2". The optional prompt.yaml
+// selects the placeholder syntax and maps labels to versions, either
+// directly or as a weighted rollout:
+//
+//	syntax: double
+//	labels:
+//	  production:
+//	    "1": 90
+//	    "2": 10
+//	  staging: "2"
+//
+// Rollouts are sticky: the same rollout key always gets the same version.
+// The key defaults to the user ID of the invocation in the context.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
+)
+
+// DefaultLabel is the label resolved when neither a label nor a version is
+// requested. Prompts without this label resolve to their latest version.
+const DefaultLabel = "production"
+
+const defaultReloadInterval = 5 * time.Second
+
+var (
+	// ErrPromptNotFound is returned for unknown prompt names.
+	ErrPromptNotFound = errors.New("registry: prompt not found")
+	// ErrVersionNotFound is returned for unknown versions or labels.
+	ErrVersionNotFound = errors.New("registry: version not found")
+)
+
+// RolloutBy selects the invocation field used as the rollout key.
+type RolloutBy int
+
+const (
+	// RolloutByUser keeps a user on the same version across sessions.
+	RolloutByUser RolloutBy = iota
+	// RolloutBySession picks a version per session.
+	RolloutBySession
+)
+
+// Option configures a Registry.
+type Option func(*options)
+
+type options struct {
+	reloadInterval    time.Duration
+	reloadIntervalSet bool
+	rolloutBy         RolloutBy
+	gitRef            string
+	subdir            string
+	checkoutDir       string
+}
+
+// WithReloadInterval sets how often the registry checks for changes.
+// A non-positive interval disables hot reload; Reload can still be called.
+// The default is 5s for directories and 1m for git repositories.
+func WithReloadInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.reloadInterval = d
+		o.reloadIntervalSet = true
+	}
+}
+
+// WithRolloutBy sets the invocation field used as the default rollout key.
+func WithRolloutBy(by RolloutBy) Option {
+	return func(o *options) {
+		o.rolloutBy = by
+	}
+}
+
+// GetOption configures a single prompt lookup.
+type GetOption func(*getOptions)
+
+type getOptions struct {
+	label   string
+	version string
+}
+
+// WithLabel resolves the version carrying label. It clears any version
+// selected before.
+func WithLabel(label string) GetOption {
+	return func(o *getOptions) {
+		o.label = label
+		o.version = ""
+	}
+}
+
+// WithVersion resolves an exact version. It clears any label selected
+// before.
+func WithVersion(version string) GetOption {
+	return func(o *getOptions) {
+		o.version = version
+		o.label = ""
+	}
+}
+
+type rolloutKeyCtxKey struct{}
+
+// WithRolloutKey returns a context whose lookups use key to pick a version
+// of weighted labels, overriding the invocation user or session.
+func WithRolloutKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, rolloutKeyCtxKey{}, key)
+}
+
+// Registry serves versioned prompts. It is safe for concurrent use.
+type Registry struct {
+	opts options
+	root string
+	// sync refreshes root before a reload, e.g. by fetching a git remote.
+	sync    func(ctx context.Context) error
+	cleanup func() error
+
+	mu          sync.RWMutex
+	snap        *snapshot
+	fingerprint string
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a registry serving the prompts under dir.
+func New(dir string, opts ...Option) (*Registry, error) {
+	o := newOptions(defaultReloadInterval, opts)
+	return newRegistry(dir, o, nil, nil)
+}
+
+func newOptions(reloadInterval time.Duration, opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.reloadIntervalSet {
+		o.reloadInterval = reloadInterval
+	}
+	return o
+}
+
+func newRegistry(
+	root string,
+	o options,
+	syncFn func(context.Context) error,
+	cleanup func() error,
+) (*Registry, error) {
+	r := &Registry{
+		opts:    o,
+		root:    root,
+		sync:    syncFn,
+		cleanup: cleanup,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		if cleanup != nil {
+			_ = cleanup()
+		}
+		return nil, err
+	}
+	if o.reloadInterval > 0 {
+		go r.watch(o.reloadInterval)
+	} else {
+		close(r.done)
+	}
+	return r, nil
+}
+
+// Get resolves a prompt version. Without options it resolves DefaultLabel,
+// falling back to the latest version when the prompt has no such label.
+func (r *Registry) Get(
+	ctx context.Context,
+	name string,
+	opts ...GetOption,
+) (prompt.Text, error) {
+	var o getOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r.mu.RLock()
+	e, ok := r.snap.entries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return prompt.Text{}, fmt.Errorf("%w: %q", ErrPromptNotFound, name)
+	}
+
+	version := o.version
+	switch {
+	case version != "":
+	case o.label != "":
+		ro, ok := e.labels[o.label]
+		if !ok {
+			return prompt.Text{}, fmt.Errorf("%w: prompt %q has no label %q",
+				ErrVersionNotFound, name, o.label)
+		}
+		version = ro.pick(name, o.label, r.rolloutKey(ctx), r.randInt)
+	default:
+		if ro, ok := e.labels[DefaultLabel]; ok {
+			version = ro.pick(name, DefaultLabel, r.rolloutKey(ctx), r.randInt)
+		} else {
+			version = e.latest()
+		}
+	}
+	tmpl, ok := e.versions[version]
+	if !ok {
+		return prompt.Text{}, fmt.Errorf("%w: prompt %q has no version %q",
+			ErrVersionNotFound, name, version)
+	}
+	return prompt.Text{
+		Template: tmpl,
+		Syntax:   e.syntax,
+		Meta:     prompt.Meta{Name: name, Version: version},
+	}, nil
+}
+
+// Source returns a prompt.Source resolving name on every fetch, so agents
+// pick up reloads and rollouts per invocation.
+func (r *Registry) Source(name string, opts ...GetOption) prompt.Source {
+	return &source{registry: r, name: name, opts: opts}
+}
+
+// Names returns the sorted names of all prompts.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.snap.entries))
+	for name := range r.snap.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of a prompt from oldest to newest.
+func (r *Registry) Versions(name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.snap.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPromptNotFound, name)
+	}
+	return append([]string(nil), e.order...), nil
+}
+
+// Reload refreshes the prompts immediately. On error the previously
+// loaded prompts stay in use.
+func (r *Registry) Reload(ctx context.Context) error {
+	if r.sync != nil {
+		if err := r.sync(ctx); err != nil {
+			return err
+		}
+	}
+	return r.load()
+}
+
+// Close stops hot reload and removes any checkout created by the registry.
+func (r *Registry) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		if r.cleanup != nil {
+			err = r.cleanup()
+		}
+	})
+	return err
+}
+
+func (r *Registry) load() error {
+	fp, err := fingerprint(r.root)
+	if err != nil {
+		return fmt.Errorf("registry: scan %s: %w", r.root, err)
+	}
+	r.mu.RLock()
+	unchanged := r.snap != nil && fp == r.fingerprint
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+	snap, err := loadSnapshot(r.root)
+	if err != nil {
+		return fmt.Errorf("registry: load %s: %w", r.root, err)
+	}
+	r.mu.Lock()
+	r.snap = snap
+	r.fingerprint = fp
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Registry) watch(interval time.Duration) {
+	defer close(r.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := r.Reload(ctx); err != nil {
+				log.Warnf("prompt registry: reload %s failed, keeping previous prompts: %v",
+					r.root, err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (r *Registry) rolloutKey(ctx context.Context) string {
+	if key, ok := ctx.Value(rolloutKeyCtxKey{}).(string); ok {
+		return key
+	}
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil || inv.Session == nil {
+		return ""
+	}
+	if r.opts.rolloutBy == RolloutBySession {
+		return inv.Session.ID
+	}
+	return inv.Session.UserID
+}
+
+func (r *Registry) randInt(n int) int {
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	return r.rand.Intn(n)
+}
+
+type source struct {
+	registry *Registry
+	name     string
+	opts     []GetOption
+}
+
+func (s *source) FetchPrompt(ctx context.Context) (prompt.Text, error) {
+	return s.registry.Get(ctx, s.name, s.opts...)
+}
@@ -0,0 +1,245 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/prompt"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func newTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "support/triage/1.txt"), "v1 {{name}}")
+	writeFile(t, filepath.Join(dir, "support/triage/2.txt"), "v2 {{name}}")
+	writeFile(t, filepath.Join(dir, "support/triage/10.md"), "v10 {{name}}")
+	writeFile(t, filepath.Join(dir, "support/triage/prompt.yaml"), `
+syntax: double
+labels:
+  production:
+    "1": 50
+    "2": 50
+    "10": 0
+  staging: "10"
+`)
+	writeFile(t, filepath.Join(dir, "greeting/v1.txt"), "hello {name}")
+	writeFile(t, filepath.Join(dir, "greeting/notes.json"), "{}")
+	writeFile(t, filepath.Join(dir, ".hidden/1.txt"), "ignored")
+	return dir
+}
+
+func TestRegistry_Get(t *testing.T) {
+	ctx := context.Background()
+	r, err := New(newTestDir(t), WithReloadInterval(0))
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, []string{"greeting", "support/triage"}, r.Names())
+	versions, err := r.Versions("support/triage")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "10"}, versions)
+
+	text, err := r.Get(ctx, "support/triage", WithLabel("staging"))
+	require.NoError(t, err)
+	assert.Equal(t, "v10 {{name}}", text.Template)
+	assert.Equal(t, prompt.SyntaxDoubleBrace, text.Syntax)
+	assert.Equal(t, prompt.Meta{Name: "support/triage", Version: "10"}, text.Meta)
+
+	text, err = r.Get(ctx, "support/triage", WithLabel("staging"), WithVersion("2"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", text.Meta.Version)
+
+	text, err = r.Get(ctx, "greeting")
+	require.NoError(t, err, "prompts without the default label use the latest version")
+	assert.Equal(t, "v1", text.Meta.Version)
+	assert.Equal(t, prompt.SyntaxMixedBrace, text.Syntax)
+
+	_, err = r.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+	_, err = r.Get(ctx, "greeting", WithVersion("v9"))
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	_, err = r.Get(ctx, "greeting", WithLabel("canary"))
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	_, err = r.Versions("missing")
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+}
+
+func TestRegistry_Rollout(t *testing.T) {
+	r, err := New(newTestDir(t), WithReloadInterval(0))
+	require.NoError(t, err)
+	defer r.Close()
+	src := r.Source("support/triage")
+
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		ctx := WithRolloutKey(context.Background(), fmt.Sprintf("user-%d", i))
+		first, err := src.FetchPrompt(ctx)
+		require.NoError(t, err)
+		again, err := src.FetchPrompt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, first.Meta, again.Meta, "rollouts are sticky per key")
+		counts[first.Meta.Version]++
+	}
+	assert.Zero(t, counts["10"], "zero weight versions are never served")
+	assert.Greater(t, counts["1"], 50)
+	assert.Greater(t, counts["2"], 50)
+
+	inv := agent.NewInvocation(agent.WithInvocationSession(
+		session.NewSession("app", "alice", "s1")))
+	ctx := agent.NewInvocationContext(context.Background(), inv)
+	byUser, err := r.Get(ctx, "support/triage")
+	require.NoError(t, err)
+	keyed, err := r.Get(WithRolloutKey(context.Background(), "alice"), "support/triage")
+	require.NoError(t, err)
+	assert.Equal(t, keyed.Meta, byUser.Meta, "the invocation user is the default key")
+
+	bySession, err := New(newTestDir(t), WithReloadInterval(0), WithRolloutBy(RolloutBySession))
+	require.NoError(t, err)
+	defer bySession.Close()
+	got, err := bySession.Get(ctx, "support/triage")
+	require.NoError(t, err)
+	keyed, err = r.Get(WithRolloutKey(context.Background(), "s1"), "support/triage")
+	require.NoError(t, err)
+	assert.Equal(t, keyed.Meta, got.Meta)
+}
+
+func TestNew_InvalidManifest(t *testing.T) {
+	for name, manifest := range map[string]string{
+		"unknown version": "labels:\n  production: \"3\"\n",
+		"negative weight": "labels:\n  production:\n    \"1\": -1\n",
+		"zero weights":    "labels:\n  production:\n    \"1\": 0\n",
+		"bad syntax":      "syntax: angle\n",
+		"bad yaml":        "labels: [\n",
+		"bad label":       "labels:\n  production: [1]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "p/1.txt"), "one")
+			writeFile(t, filepath.Join(dir, "p/prompt.yaml"), manifest)
+			_, err := New(dir, WithReloadInterval(0))
+			assert.Error(t, err)
+		})
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "1.txt"), "root level")
+	_, err := New(dir, WithReloadInterval(0))
+	assert.Error(t, err)
+
+	dir = t.TempDir()
+	writeFile(t, filepath.Join(dir, "p/1.txt"), "one")
+	writeFile(t, filepath.Join(dir, "p/1.md"), "one")
+	_, err = New(dir, WithReloadInterval(0))
+	assert.Error(t, err, "duplicate versions")
+
+	_, err = New(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRegistry_HotReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "p/1.txt"), "one")
+	r, err := New(dir, WithReloadInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	defer r.Close()
+
+	writeFile(t, filepath.Join(dir, "p/2.txt"), "two")
+	assert.Eventually(t, func() bool {
+		text, err := r.Get(ctx, "p")
+		return err == nil && text.Template == "two"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// A broken change keeps the last good prompts.
+	writeFile(t, filepath.Join(dir, "p/prompt.yaml"), "labels:\n  production: \"9\"\n")
+	assert.Error(t, r.Reload(ctx))
+	text, err := r.Get(ctx, "p")
+	require.NoError(t, err)
+	assert.Equal(t, "two", text.Template)
+
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+}
+
+func TestNewGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	work := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		require.NoError(t, runGit(ctx, work, append([]string{
+			"-c", "user.name=test", "-c", "user.email=test@example.com",
+		}, args...)...))
+	}
+	git("init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(work, "prompts/p/1.txt"), "one")
+	git("add", "-A")
+	git("commit", "-q", "-m", "v1")
+
+	checkout := filepath.Join(t.TempDir(), "checkout")
+	r, err := NewGit(ctx, work,
+		WithGitRef("main"),
+		WithSubdir("prompts"),
+		WithCheckoutDir(checkout),
+		WithReloadInterval(0),
+	)
+	require.NoError(t, err)
+	text, err := r.Get(ctx, "p")
+	require.NoError(t, err)
+	assert.Equal(t, "one", text.Template)
+
+	writeFile(t, filepath.Join(work, "prompts/p/2.txt"), "two")
+	git("add", "-A")
+	git("commit", "-q", "-m", "v2")
+	require.NoError(t, r.Reload(ctx))
+	text, err = r.Get(ctx, "p")
+	require.NoError(t, err)
+	assert.Equal(t, "two", text.Template)
+	require.NoError(t, r.Close())
+	assert.DirExists(t, checkout, "caller provided checkouts are kept")
+
+	r, err = NewGit(ctx, work, WithReloadInterval(0), WithSubdir("prompts"))
+	require.NoError(t, err)
+	root := r.root
+	require.NoError(t, r.Close())
+	assert.NoDirExists(t, root, "temporary checkouts are removed")
+
+	_, err = NewGit(ctx, "")
+	assert.Error(t, err)
+	_, err = NewGit(ctx, filepath.Join(work, "missing"), WithReloadInterval(0))
+	assert.Error(t, err)
+}
+
+func TestVersionLess(t *testing.T) {
+	assert.True(t, versionLess("2", "10"))
+	assert.True(t, versionLess("v2", "v10"))
+	assert.True(t, versionLess("10", "beta"))
+	assert.False(t, versionLess("beta", "10"))
+	assert.True(t, versionLess("alpha", "beta"))
+}
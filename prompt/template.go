@@ -0,0 +1,219 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	promptcore "trpc.group/trpc-go/trpc-agent-go/internal/prompt/core"
+)
+
+// Template is a compiled Text with conditionals, loops and partials.
+//
+// Tags use the placeholder delimiters of the text syntax, so with the
+// default mixed syntax both forms below work:
+//
+//	{{#if premium}}Offer priority support.{{else}}Offer community help.{{/if}}
+//	{{#unless tools}}You have no tools.{{/unless}}
+//	{{#if tier == "gold"}}...{{/if}}
+//	{{#each orders as order}}- {{order.id}}: {{order.status}}
+//	{{/each}}
+//	{{> safety_rules}}
+//
+// Conditions accept a value path, "not path", or a comparison of a path
+// with a quoted string, a number, true, false, null or another path using
+// == or !=. Missing values, false, 0, empty strings, empty lists and the
+// strings "false", "0" and "null" are false.
+//
+// #each iterates lists and maps, including JSON arrays and objects stored
+// as strings such as session state values. Inside the loop the item is
+// available as this, or under the name given with "as", together with
+// @index, @number (1-based), @first, @last and, for maps, @key. An {{else}}
+// branch renders for empty lists.
+//
+// Partials are resolved from sources when the template is compiled and see
+// the loop variables of the place they are included. Tags alone on their
+// line are removed together with the line.
+//
+// Templates are sandboxed: they only read values from the render
+// environment and cannot call functions.
+type Template struct {
+	text Text
+	core *promptcore.LogicTemplate
+}
+
+// CompileOption configures Text.Compile.
+type CompileOption func(*compileConfig)
+
+type compileConfig struct {
+	partials map[string]Source
+}
+
+// WithPartial makes source available as the partial name, included with
+// {{> name}}. A Text is itself a Source for static partials.
+func WithPartial(name string, source Source) CompileOption {
+	return func(cfg *compileConfig) {
+		if cfg.partials == nil {
+			cfg.partials = make(map[string]Source)
+		}
+		cfg.partials[name] = source
+	}
+}
+
+// FetchPrompt implements Source, returning t itself.
+func (t Text) FetchPrompt(context.Context) (Text, error) {
+	return t, nil
+}
+
+// Compile parses t with conditionals, loops and partials and resolves its
+// partials, recursively. Syntax errors, unknown partials and partial cycles
+// are reported here rather than at render time.
+func (t Text) Compile(ctx context.Context, opts ...CompileOption) (*Template, error) {
+	cfg := compileConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	c := compiler{ctx: ctx, partials: cfg.partials, compiled: make(map[string]*promptcore.LogicTemplate)}
+	root, err := c.compile(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{text: t, core: root}, nil
+}
+
+type compiler struct {
+	ctx      context.Context
+	partials map[string]Source
+	compiled map[string]*promptcore.LogicTemplate
+}
+
+func (c *compiler) compile(t Text, stack []string) (*promptcore.LogicTemplate, error) {
+	lt, err := promptcore.ParseLogic(t.Template, toCoreSyntax(t.Syntax))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range lt.Partials() {
+		partial, err := c.partial(name, stack)
+		if err != nil {
+			return nil, err
+		}
+		lt.Link(name, partial)
+	}
+	return lt, nil
+}
+
+func (c *compiler) partial(name string, stack []string) (*promptcore.LogicTemplate, error) {
+	for i, s := range stack {
+		if s == name {
+			cycle := append(append([]string(nil), stack[i:]...), name)
+			return nil, fmt.Errorf("prompt: partial cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	if lt, ok := c.compiled[name]; ok {
+		return lt, nil
+	}
+	source, ok := c.partials[name]
+	if !ok || source == nil {
+		return nil, fmt.Errorf("prompt: partial %q is not defined", name)
+	}
+	text, err := source.FetchPrompt(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("prompt: fetch partial %q: %w", name, err)
+	}
+	lt, err := c.compile(text, append(stack, name))
+	if err != nil {
+		return nil, fmt.Errorf("prompt: partial %q: %w", name, err)
+	}
+	c.compiled[name] = lt
+	return lt, nil
+}
+
+// Text returns the source text of the template.
+func (t *Template) Text() Text {
+	return t.text
+}
+
+// Render renders the template. Values are looked up in env.Data, then
+// env.Vars, then env.Resolver; dotted paths such as order.id navigate
+// into maps and lists. Unknown placeholders follow WithUnknownBehavior,
+// while unknown values in conditions and loops count as empty.
+func (t *Template) Render(env RenderEnv, opts ...RenderOption) (string, error) {
+	cfg := renderConfig{unknownBehavior: PreserveUnknown}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return t.core.RenderLogic(
+		promptcore.Env{
+			Vars: env.Vars,
+			Data: env.Data,
+			Resolve: func(name string) (string, bool, error) {
+				if env.Resolver == nil {
+					return "", false, nil
+				}
+				return env.Resolver.Resolve(Ref{Name: name})
+			},
+		},
+		toCoreUnknownBehavior(cfg.unknownBehavior),
+	)
+}
+
+// Variables returns the sorted names of the values the template and its
+// partials reference, excluding loop variables.
+func (t *Template) Variables() []string {
+	refs := t.core.References()
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Name
+	}
+	return names
+}
+
+// Validate reports the values referenced by the template that are not
+// among names, so typos surface before a run starts. Optional placeholders
+// such as {name?} are not reported. A name also covers the paths below it:
+// "order" covers order.id.
+func (t *Template) Validate(names ...string) error {
+	known := make(map[string]struct{}, len(names))
+	for _, name := range normalizeNames(names) {
+		known[name] = struct{}{}
+	}
+	var undefined []string
+	for _, ref := range t.core.References() {
+		if ref.Optional || isKnownPath(ref.Name, known) {
+			continue
+		}
+		undefined = append(undefined, ref.Name)
+	}
+	if len(undefined) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"prompt: undefined variables: %s",
+		strings.Join(formatPlaceholderNames(undefined), ", "),
+	)
+}
+
+func isKnownPath(path string, known map[string]struct{}) bool {
+	for {
+		if _, ok := known[path]; ok {
+			return true
+		}
+		idx := strings.LastIndexByte(path, '.')
+		if idx < 0 {
+			return false
+		}
+		path = path[:idx]
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingSource struct{}
+
+func (failingSource) FetchPrompt(context.Context) (Text, error) {
+	return Text{}, errors.New("unavailable")
+}
+
+func compile(t *testing.T, text Text, opts ...CompileOption) *Template {
+	t.Helper()
+	tmpl, err := text.Compile(context.Background(), opts...)
+	require.NoError(t, err)
+	return tmpl
+}
+
+func TestTemplate_Conditionals(t *testing.T) {
+	tmpl := compile(t, Text{Template: "" +
+		"You are a support agent.\n" +
+		"{{#if premium}}\n" +
+		"Offer priority support.\n" +
+		"{{else}}\n" +
+		"Offer community help.\n" +
+		"{{/if}}\n" +
+		"{#unless tools}No tools.{/unless}" +
+		"{{#if tier == \"gold\"}} Gold.{{/if}}" +
+		"{{#if not locale}} Default locale.{{/if}}" +
+		"{{#if count != 0}} Has {count}.{{/if}}",
+	})
+
+	rendered, err := tmpl.Render(RenderEnv{
+		Vars: Vars{"premium": "true", "tier": "gold", "count": "2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "You are a support agent.\nOffer priority support.\n"+
+		"No tools. Gold. Default locale. Has 2.", rendered)
+
+	rendered, err = tmpl.Render(RenderEnv{
+		Vars: Vars{"premium": "false", "locale": "fr"},
+		Data: map[string]any{"tools": []string{"search"}, "count": 0},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "You are a support agent.\nOffer community help.\n", rendered)
+}
+
+func TestTemplate_Each(t *testing.T) {
+	tmpl := compile(t, Text{
+		Syntax: SyntaxDoubleBrace,
+		Template: "" +
+			"Orders:\n" +
+			"{{#each orders as order}}\n" +
+			"{{@number}}. {{order.id}} {{order.status}}{{#if @last}} (latest){{/if}}\n" +
+			"{{else}}\n" +
+			"none\n" +
+			"{{/each}}\n" +
+			"Tags: {{#each tags}}{{this}}{{#unless @last}}, {{/unless}}{{/each}}\n" +
+			"Limits: {{#each limits}}{{@key}}={{this}} {{/each}}\n" +
+			"First: {{orders.0.id}} {single}",
+	})
+
+	rendered, err := tmpl.Render(RenderEnv{
+		Data: map[string]any{
+			"orders": []map[string]any{
+				{"id": "A1", "status": "shipped"},
+				{"id": "B2", "status": "pending"},
+			},
+			"limits": map[string]int{"b": 2, "a": 1},
+		},
+		Resolver: mapResolver{"tags": `["vip","beta"]`},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Orders:\n1. A1 shipped\n2. B2 pending (latest)\n"+
+		"Tags: vip, beta\nLimits: a=1 b=2 \nFirst: A1 {single}", rendered)
+
+	rendered, err = tmpl.Render(RenderEnv{})
+	require.NoError(t, err)
+	require.Equal(t, "Orders:\nnone\nTags: \nLimits: \nFirst: {{orders.0.id}} {single}",
+		rendered)
+
+	_, err = tmpl.Render(RenderEnv{}, WithUnknownBehavior(ErrorOnUnknown))
+	require.ErrorContains(t, err, "{{orders.0.id}}")
+}
+
+func TestTemplate_Partials(t *testing.T) {
+	ctx := context.Background()
+	rules := Text{Template: "Rules for {name}:\n{{> footer}}"}
+	footer := Text{Template: "Be kind to {{item}}.", Syntax: SyntaxDoubleBrace}
+	tmpl := compile(t,
+		Text{Template: "{{#each users as item}}{{> rules}}\n{{/each}}"},
+		WithPartial("rules", rules),
+		WithPartial("footer", footer),
+	)
+	rendered, err := tmpl.Render(RenderEnv{
+		Vars: Vars{"name": "support"},
+		Data: map[string]any{"users": []string{"ann", "bob"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Rules for support:\nBe kind to ann.\nRules for support:\nBe kind to bob.\n",
+		rendered)
+	require.Equal(t, []string{"name", "users"}, tmpl.Variables())
+
+	_, err = Text{Template: "{{> missing}}"}.Compile(ctx)
+	require.ErrorContains(t, err, `partial "missing" is not defined`)
+
+	_, err = Text{Template: "{{> a}}"}.Compile(ctx,
+		WithPartial("a", Text{Template: "{{> b}}"}),
+		WithPartial("b", Text{Template: "{{> a}}"}),
+	)
+	require.ErrorContains(t, err, "partial cycle: a -> b -> a")
+
+	_, err = Text{Template: "{{> a}}"}.Compile(ctx, WithPartial("a", failingSource{}))
+	require.ErrorContains(t, err, "unavailable")
+
+	_, err = Text{Template: "{{> a}}"}.Compile(ctx,
+		WithPartial("a", Text{Template: "{{#if x}}"}))
+	require.ErrorContains(t, err, `partial "a"`)
+}
+
+func TestTemplate_SyntaxErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		template string
+		err      string
+	}{
+		"unclosed":       {"a\n{{#if x}}", "line 2: #if is not closed"},
+		"mismatched":     {"{{#if x}}{{/each}}", "/each closes #if"},
+		"stray close":    {"{{/if}}", "unexpected /if"},
+		"stray else":     {"{{else}}", "unexpected else"},
+		"double else":    {"{{#if x}}{{else}}{{else}}{{/if}}", "unexpected else"},
+		"bad each":       {"{{#each a b}}{{/each}}", "#each expects"},
+		"bad condition":  {"{{#if 1}}{{/if}}", "invalid value path"},
+		"bad operand":    {"{{#if a == \"x}}{{/if}}", "invalid operand"},
+		"bad partial":    {"{{> a b}}", "invalid partial name"},
+		"missing arg":    {"{#each }{/each}", "needs an argument"},
+		"bad alias path": {"{{#each a as b.c}}{{/each}}", "#each expects"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Text{Template: tc.template}.Compile(context.Background())
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	// Single brace mode keeps double-brace tags literal.
+	tmpl := compile(t, Text{Template: "{{#if x}}{#if x}y{/if}", Syntax: SyntaxSingleBrace})
+	rendered, err := tmpl.Render(RenderEnv{Vars: Vars{"x": "1"}})
+	require.NoError(t, err)
+	require.Equal(t, "{{#if x}}y", rendered)
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tmpl := compile(t, Text{Template: "{user:name} {nickname?} " +
+		"{{#if tier == level}}{{/if}}{{#each orders as o}}{{o.id}}{{this}}{{@index}}{{/each}}" +
+		"{order.total}"})
+	require.Equal(t,
+		[]string{"level", "nickname", "order.total", "orders", "tier", "user:name"},
+		tmpl.Variables())
+	require.NoError(t, tmpl.Validate("user:name", "tier", "level", "orders", "order"))
+	err := tmpl.Validate("user:name", "orders")
+	require.EqualError(t, err, "prompt: undefined variables: {level}, {order.total}, {tier}")
+	require.Equal(t, "{user:name} {nickname?} ", tmpl.Text().Template[:24])
+}
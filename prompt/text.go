@@ -24,12 +24,17 @@ type Source interface {
 	FetchPrompt(ctx context.Context) (Text, error)
 }
 
-// Meta identifies a prompt template for observability or future registry use.
+// Meta identifies a prompt template for observability or registry use.
 type Meta struct {
-	Name    string
-	Version string
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
 }
 
+// EventExtensionKey is the event extension under which agents record the
+// []Meta of the prompts that produced an event, so evaluation results can
+// be attributed to prompt versions. Read it with event.GetExtension.
+const EventExtensionKey = "trpc.prompt.meta"
+
 // Vars stores runtime values used to render a prompt template.
 type Vars map[string]string
 
@@ -72,6 +77,9 @@ type Text struct {
 type RenderEnv struct {
 	Vars     Vars
 	Resolver Resolver
+	// Data holds structured values, such as lists and maps, for the
+	// conditions and loops of a compiled Template. Text.Render ignores it.
+	Data map[string]any
 }
 
 // Ref identifies a resolver-backed placeholder using the raw extracted name.
@@ -0,0 +1,431 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// ErrRealtimeUnsupported is returned by RunRealtime when the runner or the
+// selected agent cannot hold realtime sessions.
+var ErrRealtimeUnsupported = errors.New("runner: realtime sessions are not supported")
+
+// ErrRealtimeClosed is returned by the methods of a RealtimeRun that has
+// ended.
+var ErrRealtimeClosed = errors.New("runner: realtime run is closed")
+
+// realtimeAudioFormat is the format of the audio in realtime events.
+const realtimeAudioFormat = "pcm16"
+
+// RealtimeRunner is implemented by runners that can hold realtime sessions.
+// The runner created by NewRunner implements it.
+type RealtimeRunner interface {
+	// RunRealtime opens a realtime session of the selected agent, which
+	// must implement agent.RealtimeAgent. The session lives until it is
+	// closed, ctx is done or the run is cancelled by request ID.
+	RunRealtime(
+		ctx context.Context,
+		userID string,
+		sessionID string,
+		runOpts ...agent.RunOption,
+	) (*RealtimeRun, error)
+}
+
+// RunRealtime opens a realtime session on runners that support it.
+func RunRealtime(
+	ctx context.Context,
+	r Runner,
+	userID string,
+	sessionID string,
+	runOpts ...agent.RunOption,
+) (*RealtimeRun, error) {
+	rt, ok := r.(RealtimeRunner)
+	if !ok {
+		return nil, ErrRealtimeUnsupported
+	}
+	return rt.RunRealtime(ctx, userID, sessionID, runOpts...)
+}
+
+// RealtimeRun is a realtime session of an agent opened by RunRealtime.
+//
+// It keeps the session consistent with normal runs: the transcripts of the
+// user's audio and the user's texts, the assistant responses with their
+// usage, the tool calls and the tool results are appended to the session
+// as the same events a run appends. Audio and transcript deltas are emitted
+// as partial events and are not persisted. The agent runs the tool calls
+// of the model as in normal runs, while the audio keeps flowing.
+type RealtimeRun struct {
+	r          *runner
+	ctx        context.Context
+	cancel     context.CancelFunc
+	sess       *session.Session
+	invocation *agent.Invocation
+	agent      agent.RealtimeAgent
+	author     string
+	modelName  string
+	tools      map[string]tool.Tool
+	session    model.RealtimeSession
+	events     chan *event.Event
+	done       chan struct{}
+	closeOnce  sync.Once
+	// mu guards closed and serializes emitted events.
+	mu     sync.Mutex
+	closed bool
+	// toolMu runs the tool calls of one response at a time.
+	toolMu sync.Mutex
+}
+
+// RunRealtime implements the RealtimeRunner interface.
+func (r *runner) RunRealtime(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	runOpts ...agent.RunOption,
+) (*RealtimeRun, error) {
+	ro := agent.RunOptions{RequestID: uuid.NewString()}
+	for _, opt := range runOpts {
+		opt(&ro)
+	}
+	if ro.RequestID == "" {
+		ro.RequestID = uuid.NewString()
+	}
+	r.applyRunnerRunDefaults(&ro)
+	effectiveAppName := r.appName
+	if ro.AppName != "" {
+		effectiveAppName = ro.AppName
+	}
+
+	execCtx, execCancel := r.newExecutionContext(ctx, ro)
+	sessionKey := session.Key{
+		AppName:   effectiveAppName,
+		UserID:    userID,
+		SessionID: sessionID,
+	}
+	sess, err := r.getOrCreateSession(execCtx, ro, sessionKey)
+	if err != nil {
+		execCancel()
+		return nil, err
+	}
+	ag, err := r.selectRealtimeAgent(execCtx, ro)
+	if err != nil {
+		execCancel()
+		return nil, err
+	}
+	rtAgent, ok := ag.(agent.RealtimeAgent)
+	if !ok {
+		execCancel()
+		return nil, fmt.Errorf("%w: agent %q", ErrRealtimeUnsupported, ag.Info().Name)
+	}
+
+	invocation := r.newRunInvocation(sess, model.Message{}, ag, ro, effectiveAppName, "", "")
+	if _, err := r.registerRun(
+		ro.RequestID,
+		RunStatus{
+			RequestID:    ro.RequestID,
+			InvocationID: invocation.InvocationID,
+			AgentName:    ag.Info().Name,
+			SessionKey:   sessionKey,
+			StartedAt:    time.Now(),
+		},
+		execCancel,
+		nil,
+	); err != nil {
+		execCancel()
+		return nil, err
+	}
+	execCtx = agent.NewInvocationContext(execCtx, invocation)
+	rm, req, err := rtAgent.RealtimeRequest(execCtx, invocation)
+	if err == nil {
+		var rs model.RealtimeSession
+		if rs, err = rm.Connect(execCtx, req); err == nil {
+			rr := &RealtimeRun{
+				r:          r,
+				ctx:        execCtx,
+				cancel:     execCancel,
+				sess:       sess,
+				invocation: invocation,
+				agent:      rtAgent,
+				author:     ag.Info().Name,
+				modelName:  rm.Info().Name,
+				tools:      req.Tools,
+				session:    rs,
+				events:     make(chan *event.Event, defaultRealtimeEventBufferSize),
+				done:       make(chan struct{}),
+			}
+			go rr.loop()
+			return rr, nil
+		}
+	}
+	r.unregisterRun(ro.RequestID)
+	execCancel()
+	return nil, err
+}
+
+// defaultRealtimeEventBufferSize is the buffer size of the event channel
+// of a realtime run.
+const defaultRealtimeEventBufferSize = 256
+
+// selectRealtimeAgent resolves the agent of a realtime session. The agent
+// is not wrapped by the run-level decorators, which apply to model runs.
+func (r *runner) selectRealtimeAgent(
+	ctx context.Context,
+	ro agent.RunOptions,
+) (agent.Agent, error) {
+	if ro.Agent != nil {
+		return ro.Agent, nil
+	}
+	agentName := r.defaultAgentName
+	if ro.AgentByName != "" {
+		agentName = ro.AgentByName
+	}
+	ag, ok, err := r.loadRegisteredAgent(ctx, agentName, ro)
+	if err != nil {
+		return nil, fmt.Errorf("select agent: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("select agent: runner: agent %q not found", agentName)
+	}
+	return ag, nil
+}
+
+// RequestID returns the request ID of the run, which Cancel and RunStatus
+// of the runner accept.
+func (rr *RealtimeRun) RequestID() string {
+	return rr.invocation.RunOptions.RequestID
+}
+
+// SendAudio appends a chunk of the user's audio, see
+// model.RealtimeSession.
+func (rr *RealtimeRun) SendAudio(ctx context.Context, chunk []byte) error {
+	return rr.session.SendAudio(ctx, chunk)
+}
+
+// CommitAudio ends the user's audio turn when turn detection is disabled.
+func (rr *RealtimeRun) CommitAudio(ctx context.Context) error {
+	return rr.session.CommitAudio(ctx)
+}
+
+// SendText appends a user message to the session and sends it to the
+// model. It returns ErrRealtimeClosed once the run has ended.
+func (rr *RealtimeRun) SendText(ctx context.Context, text string) error {
+	select {
+	case <-rr.done:
+		return ErrRealtimeClosed
+	default:
+	}
+	rr.appendUserMessage(text)
+	return rr.session.SendText(ctx, text)
+}
+
+// Interrupt interrupts the response in progress, e.g. when the user barges
+// in without server side turn detection.
+func (rr *RealtimeRun) Interrupt(ctx context.Context) error {
+	return rr.session.Cancel(ctx)
+}
+
+// Events returns the events of the run. The channel is closed when the run
+// ends.
+func (rr *RealtimeRun) Events() <-chan *event.Event {
+	return rr.events
+}
+
+// Close ends the run and its realtime session.
+func (rr *RealtimeRun) Close() error {
+	var err error
+	rr.closeOnce.Do(func() {
+		close(rr.done)
+		err = rr.session.Close()
+		rr.cancel()
+	})
+	return err
+}
+
+// loop relays the events of the realtime session until it ends.
+func (rr *RealtimeRun) loop() {
+	defer func() {
+		rr.Close()
+		rr.r.unregisterRun(rr.RequestID())
+		rr.mu.Lock()
+		rr.closed = true
+		close(rr.events)
+		rr.mu.Unlock()
+	}()
+	for ev := range rr.session.Events() {
+		if ev != nil {
+			rr.handle(ev)
+		}
+	}
+}
+
+func (rr *RealtimeRun) handle(ev *model.RealtimeEvent) {
+	switch ev.Type {
+	case model.RealtimeEventSpeechStarted:
+		rr.emit(event.New(rr.invocation.InvocationID, rr.author,
+			event.WithObject(model.ObjectTypeRealtimeSpeechStarted)), false)
+	case model.RealtimeEventSpeechStopped:
+		rr.emit(event.New(rr.invocation.InvocationID, rr.author,
+			event.WithObject(model.ObjectTypeRealtimeSpeechStopped)), false)
+	case model.RealtimeEventInputTranscript:
+		rr.appendUserMessage(ev.Text)
+	case model.RealtimeEventAudioDelta:
+		rr.emitDelta(ev.ResponseID, model.Message{
+			Role: model.RoleAssistant,
+			ContentParts: []model.ContentPart{{
+				Type:  model.ContentTypeAudio,
+				Audio: &model.Audio{Data: ev.Audio, Format: realtimeAudioFormat},
+			}},
+		})
+	case model.RealtimeEventTextDelta, model.RealtimeEventTranscriptDelta:
+		rr.emitDelta(ev.ResponseID, model.Message{Role: model.RoleAssistant, Content: ev.Text})
+	case model.RealtimeEventResponseDone:
+		rr.handleResponseDone(ev)
+	case model.RealtimeEventError:
+		errType, msg := model.ErrorTypeStreamError, "realtime session error"
+		if ev.Error != nil {
+			if ev.Error.Type != "" {
+				errType = ev.Error.Type
+			}
+			msg = ev.Error.Message
+		}
+		rr.emit(event.NewErrorEvent(rr.invocation.InvocationID, rr.author, errType, msg), true)
+	}
+}
+
+// handleResponseDone persists a finished response and runs its tool calls.
+// An interrupted response is persisted only when the run persists
+// interrupted assistant messages.
+func (rr *RealtimeRun) handleResponseDone(ev *model.RealtimeEvent) {
+	if ev.Text == "" && len(ev.ToolCalls) == 0 {
+		return
+	}
+	persist := !ev.Interrupted || len(ev.ToolCalls) > 0
+	if p := rr.invocation.RunOptions.PersistInterruptedAssistant; ev.Interrupted && p != nil && *p {
+		persist = true
+	}
+	rsp := &model.Response{
+		ID:        ev.ResponseID,
+		Object:    model.ObjectTypeChatCompletion,
+		Created:   time.Now().Unix(),
+		Model:     rr.modelName,
+		Timestamp: time.Now(),
+		Done:      true,
+		Usage:     ev.Usage,
+		Choices: []model.Choice{{
+			Message: model.Message{
+				Role:      model.RoleAssistant,
+				Content:   ev.Text,
+				ToolCalls: ev.ToolCalls,
+			},
+		}},
+	}
+	rr.emit(event.NewResponseEvent(rr.invocation.InvocationID, rr.author, rsp), persist)
+	if len(ev.ToolCalls) > 0 {
+		go rr.runTools(rsp.Clone())
+	}
+}
+
+// runTools runs the tool calls of rsp with the agent and sends their
+// results to the model. It runs off the event loop, so the audio of the
+// session keeps flowing while tools run.
+func (rr *RealtimeRun) runTools(rsp *model.Response) {
+	rr.toolMu.Lock()
+	defer rr.toolMu.Unlock()
+	ch := make(chan *event.Event)
+	go func() {
+		defer close(ch)
+		rr.agent.RunRealtimeTools(rr.ctx, rr.invocation, rr.tools, rsp, ch)
+	}()
+	var results []model.Message
+	for evt := range ch {
+		if evt == nil {
+			continue
+		}
+		if evt.Response != nil && !evt.IsPartial {
+			for _, choice := range evt.Choices {
+				if choice.Message.Role == model.RoleTool {
+					results = append(results, choice.Message)
+				}
+			}
+		}
+		rr.emit(evt, rr.r.shouldPersistEvent(evt))
+	}
+	if len(results) == 0 {
+		return
+	}
+	if err := rr.session.SendToolResults(rr.ctx, results); err != nil {
+		log.WarnfContext(rr.ctx, "runner: send realtime tool results: %v", err)
+	}
+}
+
+func (rr *RealtimeRun) appendUserMessage(text string) {
+	if text == "" {
+		return
+	}
+	rr.emit(event.NewResponseEvent(rr.invocation.InvocationID, authorUser, &model.Response{
+		Choices: []model.Choice{{Message: model.NewUserMessage(text)}},
+	}), true)
+}
+
+func (rr *RealtimeRun) emitDelta(responseID string, delta model.Message) {
+	rr.emit(event.NewResponseEvent(rr.invocation.InvocationID, rr.author, &model.Response{
+		ID:        responseID,
+		Object:    model.ObjectTypeChatCompletionChunk,
+		Created:   time.Now().Unix(),
+		Model:     rr.modelName,
+		Timestamp: time.Now(),
+		IsPartial: true,
+		Choices:   []model.Choice{{Delta: delta}},
+	}), false)
+}
+
+// emit persists evt when asked to and sends it to the events channel.
+// Events emitted after the run has ended are dropped.
+func (rr *RealtimeRun) emit(evt *event.Event, persist bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.closed {
+		return
+	}
+	agent.InjectIntoEvent(rr.invocation, evt)
+	evt = rr.r.applyEventPlugins(rr.ctx, rr.invocation, evt)
+	if evt == nil {
+		return
+	}
+	if persist {
+		persistCtx, cancel := sessionPersistenceContext(rr.ctx)
+		err := rr.r.sessionService.AppendEvent(persistCtx, rr.sess, evt)
+		cancel()
+		if err != nil {
+			log.ErrorfContext(rr.ctx, "runner: append realtime event: %v", err)
+		}
+	}
+	if handle := rr.r.lookupRun(rr.RequestID()); handle != nil {
+		handle.mu.Lock()
+		handle.status.LastEventAt = time.Now()
+		handle.status.EventCount++
+		handle.mu.Unlock()
+	}
+	select {
+	case rr.events <- evt:
+	case <-rr.done:
+	}
+}
@@ -0,0 +1,401 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/agent/llmagent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	sessioninmemory "trpc.group/trpc-go/trpc-agent-go/session/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+// fakeRealtimeModel hands out a single fakeRealtimeSession.
+type fakeRealtimeModel struct {
+	req     *model.RealtimeRequest
+	session *fakeRealtimeSession
+	err     error
+}
+
+func (m *fakeRealtimeModel) Connect(
+	_ context.Context,
+	req *model.RealtimeRequest,
+) (model.RealtimeSession, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.req = req
+	return m.session, nil
+}
+
+func (m *fakeRealtimeModel) Info() model.Info {
+	return model.Info{Name: "fake-realtime"}
+}
+
+// fakeRealtimeSession records what it is sent and emits the events pushed
+// by the test.
+type fakeRealtimeSession struct {
+	mu          sync.Mutex
+	texts       []string
+	audio       [][]byte
+	toolResults chan []model.Message
+	cancelled   bool
+	closed      bool
+	events      chan *model.RealtimeEvent
+}
+
+func newFakeRealtimeSession() *fakeRealtimeSession {
+	return &fakeRealtimeSession{
+		toolResults: make(chan []model.Message, 1),
+		events:      make(chan *model.RealtimeEvent, 16),
+	}
+}
+
+func (s *fakeRealtimeSession) SendAudio(_ context.Context, chunk []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.audio = append(s.audio, chunk)
+	return nil
+}
+
+func (s *fakeRealtimeSession) CommitAudio(context.Context) error { return nil }
+
+func (s *fakeRealtimeSession) SendText(_ context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = append(s.texts, text)
+	return nil
+}
+
+func (s *fakeRealtimeSession) SendToolResults(_ context.Context, results []model.Message) error {
+	s.toolResults <- results
+	return nil
+}
+
+func (s *fakeRealtimeSession) Cancel(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelled = true
+	return nil
+}
+
+func (s *fakeRealtimeSession) Events() <-chan *model.RealtimeEvent { return s.events }
+
+func (s *fakeRealtimeSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+type realtimeWeatherArgs struct {
+	City string `json:"city"`
+}
+
+func nextRealtimeEvent(t *testing.T, rr *RealtimeRun) *event.Event {
+	t.Helper()
+	select {
+	case evt, ok := <-rr.Events():
+		require.True(t, ok, "events closed")
+		return evt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func TestRunRealtime_Conversation(t *testing.T) {
+	rs := newFakeRealtimeSession()
+	rm := &fakeRealtimeModel{session: rs}
+	weather := function.NewFunctionTool(
+		func(_ context.Context, args realtimeWeatherArgs) (map[string]string, error) {
+			return map[string]string{"city": args.City, "sky": "<sunny>"}, nil
+		},
+		function.WithName("weather"),
+		function.WithDescription("Get the weather"),
+	)
+	ag := llmagent.New(
+		"assistant",
+		llmagent.WithInstruction("Be brief."),
+		llmagent.WithTools([]tool.Tool{weather}),
+		llmagent.WithRealtimeModel(rm),
+		llmagent.WithRealtimeConfig(model.RealtimeRequest{Voice: "marin"}),
+	)
+	sessionService := sessioninmemory.NewSessionService()
+	r := NewRunner("app", ag, WithSessionService(sessionService))
+	ctx := context.Background()
+
+	rr, err := RunRealtime(ctx, r, "user", "session", agent.WithRequestID("req-1"))
+	require.NoError(t, err)
+	require.NotNil(t, rm.req)
+	assert.Contains(t, rm.req.Instructions, "Be brief.")
+	assert.Equal(t, "marin", rm.req.Voice)
+	assert.Contains(t, rm.req.Tools, "weather")
+	status, ok := r.(*runner).RunStatus("req-1")
+	require.True(t, ok)
+	assert.Equal(t, "assistant", status.AgentName)
+
+	require.NoError(t, rr.SendAudio(ctx, []byte{1, 2}))
+	rs.events <- &model.RealtimeEvent{Type: model.RealtimeEventSessionCreated}
+	rs.events <- &model.RealtimeEvent{Type: model.RealtimeEventSpeechStarted}
+	rs.events <- &model.RealtimeEvent{Type: model.RealtimeEventInputTranscript, Text: "Weather in Paris?"}
+	rs.events <- &model.RealtimeEvent{
+		Type: model.RealtimeEventAudioDelta, ResponseID: "r1", Audio: []byte{3, 4},
+	}
+	rs.events <- &model.RealtimeEvent{
+		Type:       model.RealtimeEventResponseDone,
+		ResponseID: "r1",
+		Text:       "Let me check.",
+		ToolCalls: []model.ToolCall{{
+			Type: "function",
+			ID:   "c1",
+			Function: model.FunctionDefinitionParam{
+				Name: "weather", Arguments: []byte(`{"city":"Paris"}`),
+			},
+		}, {
+			Type:     "function",
+			ID:       "c2",
+			Function: model.FunctionDefinitionParam{Name: "missing"},
+		}},
+		Usage: &model.Usage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30},
+	}
+
+	speech := nextRealtimeEvent(t, rr)
+	assert.Equal(t, model.ObjectTypeRealtimeSpeechStarted, speech.Object)
+	assert.Equal(t, "assistant", speech.Author)
+	transcript := nextRealtimeEvent(t, rr)
+	assert.Equal(t, authorUser, transcript.Author)
+	assert.Equal(t, "Weather in Paris?", transcript.Choices[0].Message.Content)
+	delta := nextRealtimeEvent(t, rr)
+	assert.True(t, delta.IsPartial)
+	assert.Equal(t, model.ContentTypeAudio, delta.Choices[0].Delta.ContentParts[0].Type)
+	assert.Equal(t, []byte{3, 4}, delta.Choices[0].Delta.ContentParts[0].Audio.Data)
+	response := nextRealtimeEvent(t, rr)
+	assert.True(t, response.Done)
+	assert.Equal(t, "fake-realtime", response.Model)
+	assert.Equal(t, 30, response.Usage.TotalTokens)
+	assert.Len(t, response.Choices[0].Message.ToolCalls, 2)
+	toolResponse := nextRealtimeEvent(t, rr)
+	assert.Equal(t, model.ObjectTypeToolResponse, toolResponse.Object)
+	require.Len(t, toolResponse.Choices, 2)
+
+	var results []model.Message
+	select {
+	case results = <-rs.toolResults:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for tool results")
+	}
+	require.Len(t, results, 2)
+	assert.Equal(t, "c1", results[0].ToolID)
+	assert.Equal(t, `{"city":"Paris","sky":"<sunny>"}`, results[0].Content)
+	assert.Equal(t, "c2", results[1].ToolID)
+	assert.Contains(t, results[1].Content, "missing")
+
+	rs.events <- &model.RealtimeEvent{
+		Type: model.RealtimeEventResponseDone, ResponseID: "r2", Text: "It is sunny.",
+	}
+	assert.Equal(t, "It is sunny.", nextRealtimeEvent(t, rr).Choices[0].Message.Content)
+
+	require.NoError(t, rr.SendText(ctx, "Thanks"))
+	assert.Equal(t, "Thanks", nextRealtimeEvent(t, rr).Choices[0].Message.Content)
+	require.NoError(t, rr.Interrupt(ctx))
+	rs.events <- &model.RealtimeEvent{
+		Type: model.RealtimeEventResponseDone, ResponseID: "r3", Text: "You are", Interrupted: true,
+	}
+	assert.Equal(t, "You are", nextRealtimeEvent(t, rr).Choices[0].Message.Content)
+	rs.events <- &model.RealtimeEvent{
+		Type:  model.RealtimeEventError,
+		Error: &model.ResponseError{Type: "server_error", Message: "boom"},
+	}
+	errEvent := nextRealtimeEvent(t, rr)
+	require.NotNil(t, errEvent.Error)
+	assert.Equal(t, "boom", errEvent.Error.Message)
+
+	close(rs.events)
+	_, ok = <-rr.Events()
+	assert.False(t, ok)
+	rs.mu.Lock()
+	assert.Equal(t, [][]byte{{1, 2}}, rs.audio)
+	assert.Equal(t, []string{"Thanks"}, rs.texts)
+	assert.True(t, rs.cancelled)
+	assert.True(t, rs.closed)
+	rs.mu.Unlock()
+	_, ok = r.(*runner).RunStatus("req-1")
+	assert.False(t, ok)
+
+	sess, err := sessionService.GetSession(ctx, session.Key{AppName: "app", UserID: "user", SessionID: "session"})
+	require.NoError(t, err)
+	var persisted []string
+	for _, evt := range sess.Events {
+		require.NotEmpty(t, evt.Choices)
+		msg := evt.Choices[0].Message
+		b, err := json.Marshal([]any{evt.Author, msg.Role, msg.Content, len(msg.ToolCalls)})
+		require.NoError(t, err)
+		persisted = append(persisted, string(b))
+	}
+	assert.Equal(t, []string{
+		`["user","user","Weather in Paris?",0]`,
+		`["assistant","assistant","Let me check.",2]`,
+		`["assistant","tool","{\"city\":\"Paris\",\"sky\":\"\u003csunny\u003e\"}",0]`,
+		`["assistant","assistant","It is sunny.",0]`,
+		`["user","user","Thanks",0]`,
+	}, persisted)
+}
+
+func TestRunRealtime_HistoryFromSession(t *testing.T) {
+	rm := &fakeRealtimeModel{session: newFakeRealtimeSession()}
+	ag := llmagent.New("assistant", llmagent.WithRealtimeModel(rm))
+	sessionService := sessioninmemory.NewSessionService()
+	r := NewRunner("app", ag, WithSessionService(sessionService))
+	ctx := context.Background()
+	key := session.Key{AppName: "app", UserID: "user", SessionID: "session"}
+	sess, err := sessionService.CreateSession(ctx, key, nil)
+	require.NoError(t, err)
+	for _, evt := range []*event.Event{
+		event.NewResponseEvent("inv", authorUser, &model.Response{
+			Choices: []model.Choice{{Message: model.NewUserMessage("Hi")}},
+		}),
+		event.NewResponseEvent("inv", "assistant", &model.Response{
+			Done:    true,
+			Choices: []model.Choice{{Message: model.NewAssistantMessage("Hello!")}},
+		}),
+	} {
+		require.NoError(t, sessionService.AppendEvent(ctx, sess, evt))
+	}
+
+	rr, err := RunRealtime(ctx, r, "user", "session")
+	require.NoError(t, err)
+	defer rr.Close()
+	require.Len(t, rm.req.Messages, 2)
+	assert.Equal(t, "Hi", rm.req.Messages[0].Content)
+	assert.Equal(t, "Hello!", rm.req.Messages[1].Content)
+}
+
+func TestRunRealtime_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	r := NewRunner("app", &mockAgent{name: "plain"}, WithSessionService(sessioninmemory.NewSessionService()))
+	_, err := RunRealtime(ctx, r, "user", "session")
+	assert.ErrorIs(t, err, ErrRealtimeUnsupported)
+
+	_, err = RunRealtime(ctx, unsupportedSteerRunner{}, "user", "session")
+	assert.ErrorIs(t, err, ErrRealtimeUnsupported)
+
+	_, err = RunRealtime(ctx, NewRunner("app", llmagent.New("assistant"),
+		WithSessionService(sessioninmemory.NewSessionService())), "user", "session")
+	assert.Error(t, err)
+
+	connectErr := errors.New("dial failed")
+	r = NewRunner("app", llmagent.New("assistant",
+		llmagent.WithRealtimeModel(&fakeRealtimeModel{err: connectErr})),
+		WithSessionService(sessioninmemory.NewSessionService()))
+	_, err = RunRealtime(ctx, r, "user", "session", agent.WithRequestID("req-1"))
+	assert.ErrorIs(t, err, connectErr)
+	_, ok := r.(*runner).RunStatus("req-1")
+	assert.False(t, ok)
+}
+
+func TestRunRealtime_ToolsRunWithCallbacksOffLoop(t *testing.T) {
+	rs := newFakeRealtimeSession()
+	release := make(chan struct{})
+	slow := function.NewFunctionTool(
+		func(_ context.Context, _ realtimeWeatherArgs) (string, error) {
+			<-release
+			return "done", nil
+		},
+		function.WithName("slow"),
+	)
+	callbacks := tool.NewCallbacks().RegisterBeforeTool(tool.BeforeToolCallbackStructured(
+		func(_ context.Context, args *tool.BeforeToolArgs) (*tool.BeforeToolResult, error) {
+			if args.ToolName == "guarded" {
+				return &tool.BeforeToolResult{CustomResult: "denied"}, nil
+			}
+			return nil, nil
+		},
+	))
+	guarded := function.NewFunctionTool(
+		func(_ context.Context, _ realtimeWeatherArgs) (string, error) {
+			t.Error("guarded tool must not run")
+			return "", nil
+		},
+		function.WithName("guarded"),
+	)
+	ag := llmagent.New(
+		"assistant",
+		llmagent.WithTools([]tool.Tool{slow, guarded}),
+		llmagent.WithToolCallbacks(callbacks),
+		llmagent.WithRealtimeModel(&fakeRealtimeModel{session: rs}),
+	)
+	r := NewRunner("app", ag, WithSessionService(sessioninmemory.NewSessionService()))
+	rr, err := RunRealtime(context.Background(), r, "user", "session")
+	require.NoError(t, err)
+	defer rr.Close()
+
+	rs.events <- &model.RealtimeEvent{
+		Type:       model.RealtimeEventResponseDone,
+		ResponseID: "r1",
+		ToolCalls: []model.ToolCall{{
+			Type: "function", ID: "c1",
+			Function: model.FunctionDefinitionParam{Name: "guarded", Arguments: []byte(`{}`)},
+		}, {
+			Type: "function", ID: "c2",
+			Function: model.FunctionDefinitionParam{Name: "slow", Arguments: []byte(`{}`)},
+		}},
+	}
+	rs.events <- &model.RealtimeEvent{
+		Type: model.RealtimeEventAudioDelta, ResponseID: "r2", Audio: []byte{1},
+	}
+	assert.Len(t, nextRealtimeEvent(t, rr).Choices[0].Message.ToolCalls, 2)
+	delta := nextRealtimeEvent(t, rr)
+	assert.True(t, delta.IsPartial, "audio must flow while tools run")
+	close(release)
+
+	var results []model.Message
+	select {
+	case results = <-rs.toolResults:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for tool results")
+	}
+	require.Len(t, results, 2)
+	assert.Equal(t, `"denied"`, results[0].Content)
+	assert.Equal(t, `"done"`, results[1].Content)
+}
+
+func TestRealtimeRun_SendTextAfterClose(t *testing.T) {
+	rs := newFakeRealtimeSession()
+	ag := llmagent.New("assistant", llmagent.WithRealtimeModel(&fakeRealtimeModel{session: rs}))
+	r := NewRunner("app", ag, WithSessionService(sessioninmemory.NewSessionService()))
+	ctx := context.Background()
+	rr, err := RunRealtime(ctx, r, "user", "session")
+	require.NoError(t, err)
+
+	require.NoError(t, rr.Close())
+	close(rs.events)
+	for range rr.Events() {
+	}
+	assert.ErrorIs(t, rr.SendText(ctx, "late"), ErrRealtimeClosed)
+	rr.appendUserMessage("late")
+	rs.mu.Lock()
+	assert.Empty(t, rs.texts)
+	rs.mu.Unlock()
+}
@@ -0,0 +1,296 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	srvauth "trpc.group/trpc-go/trpc-agent-go/server/auth"
+)
+
+const (
+	// PushNotificationTokenHeader carries the token of the push notification
+	// configuration so receivers can validate the notification.
+	PushNotificationTokenHeader = "X-A2A-Notification-Token"
+
+	defaultPushMaxAttempts = 5
+	defaultPushBackoff     = time.Second
+	maxPushBackoff         = 5 * time.Minute
+	pushRequestTimeout     = 30 * time.Second
+)
+
+// errPushURLNotAllowed is returned for push notification URLs the server
+// does not deliver to.
+var errPushURLNotAllowed = errors.New("push notification URL is not allowed")
+
+// pushDispatcher delivers the push notification outbox of a TaskStore. It
+// runs while the outbox has pending deliveries and stops when it is empty.
+// Deliveries of one task are sent in event order: a failed delivery holds
+// back the later events of its task until it succeeds or is dropped.
+type pushDispatcher struct {
+	store         TaskStore
+	client        *http.Client
+	signingKeyID  string
+	signingSecret []byte
+	maxAttempts   int
+	backoff       time.Duration
+	allowURL      func(u *url.URL) bool
+	resolver      *net.Resolver
+	now           func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	wake    chan struct{}
+}
+
+func newPushDispatcher(store TaskStore, opts *options) *pushDispatcher {
+	p := &pushDispatcher{
+		store:         store,
+		client:        opts.pushHTTPClient,
+		signingKeyID:  opts.pushSigningKeyID,
+		signingSecret: opts.pushSigningSecret,
+		maxAttempts:   opts.pushMaxAttempts,
+		backoff:       opts.pushBackoff,
+		allowURL:      opts.pushURLAllowlist,
+		resolver:      net.DefaultResolver,
+		now:           time.Now,
+		wake:          make(chan struct{}, 1),
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: pushRequestTimeout}
+		if p.allowURL == nil {
+			p.client.Transport = publicOnlyTransport()
+		}
+	}
+	if p.maxAttempts <= 0 {
+		p.maxAttempts = defaultPushMaxAttempts
+	}
+	if p.backoff <= 0 {
+		p.backoff = defaultPushBackoff
+	}
+	return p
+}
+
+// publicOnlyTransport returns a transport that only connects to public
+// addresses, so a push URL whose host later resolves to an internal
+// address is still refused.
+func publicOnlyTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	// Dial receivers directly so the address check sees them.
+	t.Proxy = nil
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !isPublicAddr(addr) {
+				return fmt.Errorf("%w: %s", errPushURLNotAllowed, host)
+			}
+			return nil
+		},
+	}
+	t.DialContext = dialer.DialContext
+	return t
+}
+
+// isPublicAddr reports whether addr is a public unicast address, i.e. not
+// a loopback, link-local, private, multicast or unspecified one.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// validateURL checks a push notification URL before it is stored. Only
+// http and https URLs are accepted. With an allowlist, the allowlist
+// decides; otherwise hosts resolving to non-public addresses are rejected.
+func (p *pushDispatcher) validateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPushURLNotAllowed, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q", errPushURLNotAllowed, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: host is empty", errPushURLNotAllowed)
+	}
+	if p.allowURL != nil {
+		if !p.allowURL(u) {
+			return fmt.Errorf("%w: %s", errPushURLNotAllowed, u.Host)
+		}
+		return nil
+	}
+	var addrs []netip.Addr
+	if addr, perr := netip.ParseAddr(host); perr == nil {
+		addrs = []netip.Addr{addr}
+	} else if addrs, err = p.resolver.LookupNetIP(ctx, "ip", host); err != nil {
+		return fmt.Errorf("%w: resolve %s: %v", errPushURLNotAllowed, host, err)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", errPushURLNotAllowed, host, addr)
+		}
+	}
+	return nil
+}
+
+// notify makes sure the dispatcher runs and looks at the outbox again.
+func (p *pushDispatcher) notify() {
+	p.mu.Lock()
+	if !p.running {
+		p.running = true
+		go p.run()
+	}
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *pushDispatcher) run() {
+	ctx := context.Background()
+	for {
+		pending, err := p.store.PendingPushes(ctx)
+		if err != nil {
+			log.Warnf("a2a push: failed to list outbox: %v", err)
+			pending = nil
+		}
+		if len(pending) == 0 {
+			p.mu.Lock()
+			select {
+			case <-p.wake:
+				p.mu.Unlock()
+				continue
+			default:
+			}
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		next := p.deliverDue(ctx, pending)
+		if next.IsZero() {
+			continue
+		}
+		timer := time.NewTimer(next.Sub(p.now()))
+		select {
+		case <-timer.C:
+		case <-p.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// deliverDue sends the due deliveries and returns the earliest time a
+// remaining delivery becomes due, or zero when none remain.
+func (p *pushDispatcher) deliverDue(ctx context.Context, pending []PushDelivery) time.Time {
+	var next time.Time
+	held := make(map[string]bool)
+	hold := func(d PushDelivery) {
+		held[d.TaskID] = true
+		if next.IsZero() || d.NextAttempt.Before(next) {
+			next = d.NextAttempt
+		}
+	}
+	for _, d := range pending {
+		if held[d.TaskID] {
+			continue
+		}
+		if d.NextAttempt.After(p.now()) {
+			hold(d)
+			continue
+		}
+		if !d.Done {
+			if err := p.deliver(ctx, d); err != nil {
+				d.Attempts++
+				if d.Attempts < p.maxAttempts {
+					d.NextAttempt = p.now().Add(p.retryDelay(d.Attempts))
+					p.update(ctx, d)
+					hold(d)
+					continue
+				}
+				log.Warnf("a2a push: dropping notification %s for task %s after %d attempts: %v",
+					d.ID, d.TaskID, d.Attempts, err)
+			}
+		}
+		if err := p.store.DeletePush(ctx, d.ID); err != nil {
+			// Keep the delivery from being sent again and retry the
+			// removal later instead of spinning on it.
+			log.Warnf("a2a push: failed to remove notification %s: %v", d.ID, err)
+			d.Done = true
+			d.NextAttempt = p.now().Add(p.backoff)
+			p.update(ctx, d)
+			hold(d)
+		}
+	}
+	if !next.IsZero() && !next.After(p.now()) {
+		next = p.now().Add(time.Millisecond)
+	}
+	return next
+}
+
+func (p *pushDispatcher) retryDelay(attempts int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < attempts && delay < maxPushBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxPushBackoff)
+}
+
+func (p *pushDispatcher) update(ctx context.Context, d PushDelivery) {
+	if err := p.store.UpdatePush(ctx, d); err != nil {
+		log.Warnf("a2a push: failed to reschedule notification %s: %v", d.ID, err)
+	}
+}
+
+func (p *pushDispatcher) deliver(ctx context.Context, d PushDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, pushRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Config.URL,
+		bytes.NewReader(d.Payload))
+	if err != nil {
+		return fmt.Errorf("build push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.Config.Token != "" {
+		req.Header.Set(PushNotificationTokenHeader, d.Config.Token)
+	}
+	if len(p.signingSecret) > 0 {
+		if err := srvauth.SignRequest(req, p.signingKeyID, p.signingSecret); err != nil {
+			return fmt.Errorf("sign push request: %w", err)
+		}
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
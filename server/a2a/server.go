@@ -33,6 +33,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/log"
 	"trpc.group/trpc-go/trpc-agent-go/model"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	srvauth "trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 	"trpc.group/trpc-go/trpc-agent-go/session/inmemory"
 )
@@ -199,6 +200,14 @@ func buildA2AServer(options *options) (*a2a.A2AServer, error) {
 		}
 	}
 
+	if options.taskStore != nil {
+		taskManager = newDurableTaskManager(taskManager, options.taskStore, options)
+		if agentCard.Capabilities.PushNotifications == nil {
+			pushNotifications := true
+			agentCard.Capabilities.PushNotifications = &pushNotifications
+		}
+	}
+
 	// Set default UserID header if not configured
 	userIDHeader := options.userIDHeader
 	if userIDHeader == "" {
@@ -215,6 +224,27 @@ func buildA2AServer(options *options) (*a2a.A2AServer, error) {
 	// Extract trace context before caller middleware runs, then apply the
 	// provisional identity and explicitly configured pre-auth middleware before
 	// anonymous-cookie creation and authentication.
+	if options.authenticator != nil {
+		// The authenticator replaces the user header and anonymous cookie
+		// identities: every request must carry credentials.
+		opts := []a2a.Option{
+			a2a.WithBasePath(basePath),
+			a2a.WithMiddleWare(&traceContextMiddleware{}),
+		}
+		if len(options.preAuthMiddlewares) > 0 {
+			opts = append(opts, a2a.WithMiddleWare(options.preAuthMiddlewares...))
+		}
+		opts = append(opts, a2a.WithMiddleWare(authenticatorMiddleware{
+			authenticator: options.authenticator,
+			resource:      srvauth.Resource{Agent: agentCard.Name},
+		}))
+		opts = append(opts, options.extraOptions...)
+		a2aServer, err := a2a.NewA2AServer(agentCard, taskManager, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a2a server: %w", err)
+		}
+		return a2aServer, nil
+	}
 	opts := []a2a.Option{
 		a2a.WithBasePath(basePath),
 		a2a.WithMiddleWare(&traceContextMiddleware{}),
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
@@ -28,6 +29,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/log"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	srvauth "trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -122,6 +124,24 @@ func (f ResponseRewriterFuncs) RewriteStreaming(
 // default converter continues with its normal behavior.
 type EventToA2APartMapper func(ctx context.Context, event *event.Event) ([]protocol.Part, error)
 
+// authenticatorMiddleware authenticates requests with a server
+// authenticator and exposes the principal as the A2A user.
+type authenticatorMiddleware struct {
+	authenticator srvauth.Authenticator
+	resource      srvauth.Resource
+}
+
+func (m authenticatorMiddleware) Wrap(next http.Handler) http.Handler {
+	return srvauth.Middleware(m.authenticator, srvauth.WithResource(m.resource))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p, ok := srvauth.FromContext(r.Context()); ok {
+				r = r.WithContext(context.WithValue(r.Context(), auth.AuthUserKey,
+					&auth.User{ID: p.ID, Claims: p.Claims}))
+			}
+			next.ServeHTTP(w, r)
+		}))
+}
+
 type defaultAuthProvider struct {
 	userIDHeader string
 	cookieScope  string
@@ -407,6 +427,14 @@ type options struct {
 	preAuthMiddlewares        []a2a.Middleware
 	adkCompatibility          bool
 	structuredTaskErrors      bool
+	authenticator             srvauth.Authenticator
+	taskStore                 TaskStore
+	pushSigningKeyID          string
+	pushSigningSecret         []byte
+	pushMaxAttempts           int
+	pushBackoff               time.Duration
+	pushHTTPClient            *http.Client
+	pushURLAllowlist          func(u *url.URL) bool
 }
 
 // Option is a function that configures a Server.
@@ -545,6 +573,17 @@ func WithExtraA2AOptions(opts ...a2a.Option) Option {
 	}
 }
 
+// WithAuthenticator requires every JSON-RPC request to authenticate with a,
+// replacing the user ID header and anonymous cookie identities. The
+// principal ID becomes the session user ID, and the principal must be
+// allowed to use the agent named in the agent card. The agent card stays
+// public.
+func WithAuthenticator(a srvauth.Authenticator) Option {
+	return func(options *options) {
+		options.authenticator = a
+	}
+}
+
 // WithPreAuthA2AMiddleware adds middleware that runs before the built-in
 // anonymous-cookie and authentication middleware. Use it only for request
 // normalization that must happen before the authenticated identity is created.
@@ -561,6 +600,63 @@ func WithTaskManagerBuilder(builder TaskManagerBuilder) Option {
 	}
 }
 
+// WithTaskStore records the events of every task in store and enables
+// tasks/resubscribe and push notifications. Streaming runs keep going when
+// the client disconnects; clients resume them with tasks/resubscribe,
+// passing the last task_event_seq they received as the last_event_seq
+// metadata value, and stop them with tasks/cancel. Only the user that
+// created a task can resubscribe to it, cancel it and read or set its push
+// notification configuration.
+//
+// The agent card advertises push notification support unless it sets the
+// capability explicitly.
+func WithTaskStore(store TaskStore) Option {
+	return func(opts *options) {
+		opts.taskStore = store
+	}
+}
+
+// WithPushNotificationSigningKey signs push notification requests with the
+// HMAC scheme of server/auth, so receivers can verify them with an
+// auth.HMACAuthenticator holding the same key.
+func WithPushNotificationSigningKey(keyID string, secret []byte) Option {
+	return func(opts *options) {
+		opts.pushSigningKeyID = keyID
+		opts.pushSigningSecret = secret
+	}
+}
+
+// WithPushNotificationRetry sets how often a push notification is attempted
+// and the delay before the first retry, which doubles on every further
+// retry. The defaults are 5 attempts and one second.
+func WithPushNotificationRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(opts *options) {
+		opts.pushMaxAttempts = maxAttempts
+		opts.pushBackoff = backoff
+	}
+}
+
+// WithPushNotificationHTTPClient sets the HTTP client used to deliver push
+// notifications. The client is used as is: unlike the default client, it
+// does not refuse to connect to non-public addresses.
+func WithPushNotificationHTTPClient(c *http.Client) Option {
+	return func(opts *options) {
+		opts.pushHTTPClient = c
+	}
+}
+
+// WithPushNotificationURLAllowlist sets which push notification URLs
+// clients may register. By default only http and https URLs whose host
+// resolves to public addresses are accepted, which keeps clients from
+// making the server call loopback, link-local or private addresses. When
+// allow is set, it alone decides which http and https URLs are accepted,
+// e.g. to deliver to receivers inside a private network.
+func WithPushNotificationURLAllowlist(allow func(u *url.URL) bool) Option {
+	return func(opts *options) {
+		opts.pushURLAllowlist = allow
+	}
+}
+
 // WithRunOptions appends additional run options for every agent invocation.
 // These options are applied before the A2A message metadata is merged into RuntimeState.
 // If both WithRunOptions and A2A message metadata set the same RuntimeState key,
@@ -25,6 +25,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	"trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	srvauth "trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -1213,3 +1214,39 @@ func TestWithGraphEventObjectAllowlist_Empty(t *testing.T) {
 	assert.NotNil(t, opts.graphEventObjectAllowlist)
 	assert.Empty(t, opts.graphEventObjectAllowlist)
 }
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	keys, err := srvauth.NewAPIKeyAuthenticator([]srvauth.APIKey{
+		{Key: "k1", Principal: srvauth.Principal{ID: "alice"}},
+		{Key: "k2", Principal: srvauth.Principal{ID: "bob", Agents: []string{"other"}}},
+	})
+	require.NoError(t, err)
+	m := authenticatorMiddleware{
+		authenticator: keys,
+		resource:      srvauth.Resource{Agent: "assistant"},
+	}
+	var userID string
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = UserIDFromContext(r.Context())
+	}))
+
+	call := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(serverUserIDHeader, "spoofed")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusForbidden, call("k2"))
+	assert.Empty(t, userID)
+	assert.Equal(t, http.StatusOK, call("k1"))
+	assert.Equal(t, "alice", userID)
+
+	opts := &options{}
+	WithAuthenticator(keys)(opts)
+	assert.Equal(t, keys, opts.authenticator)
+}
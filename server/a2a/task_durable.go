@@ -0,0 +1,485 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+	ia2a "trpc.group/trpc-go/trpc-agent-go/internal/a2a"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+// liveFeedBufSize bounds the events buffered for one resubscribed client. A
+// client that falls further behind is disconnected and resubscribes from
+// its last event.
+const liveFeedBufSize = 256
+
+// durableTaskManager records every streamed task event in a TaskStore,
+// delivers push notifications for them and serves tasks/resubscribe from
+// the recorded log.
+//
+// Streaming runs are detached from the request context so that a task keeps
+// running when the client connection drops; tasks/cancel stops them.
+//
+// The user that creates a task owns it: the other task methods, and
+// messages that continue the task, answer other users as if the task did
+// not exist.
+type durableTaskManager struct {
+	taskmanager.TaskManager
+	store  TaskStore
+	pusher *pushDispatcher
+
+	// logMu orders appending and sequence stamping against log reads, so a
+	// replay never sees an event whose sequence is still being set.
+	logMu sync.Mutex
+
+	mu      sync.Mutex
+	feeds   map[string]map[chan protocol.StreamingMessageEvent]struct{}
+	cancels map[string]context.CancelFunc
+}
+
+func newDurableTaskManager(
+	inner taskmanager.TaskManager,
+	store TaskStore,
+	opts *options,
+) *durableTaskManager {
+	m := &durableTaskManager{
+		TaskManager: inner,
+		store:       store,
+		pusher:      newPushDispatcher(store, opts),
+		feeds:       make(map[string]map[chan protocol.StreamingMessageEvent]struct{}),
+		cancels:     make(map[string]context.CancelFunc),
+	}
+	// Resume deliveries left in a persisted outbox.
+	m.pusher.notify()
+	return m
+}
+
+// OnSendMessage records a task result and notifies its push endpoint.
+func (m *durableTaskManager) OnSendMessage(
+	ctx context.Context,
+	request protocol.SendMessageParams,
+) (*protocol.MessageResult, error) {
+	if err := m.authorizeMessage(ctx, request.Message); err != nil {
+		return nil, err
+	}
+	if err := m.validateRequestPushConfig(ctx, request.Configuration); err != nil {
+		return nil, err
+	}
+	result, err := m.TaskManager.OnSendMessage(ctx, request)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if task, ok := result.Result.(*protocol.Task); ok && task.ID != "" {
+		m.claim(ctx, task.ID)
+		m.setRequestPushConfig(ctx, task.ID, request.Configuration)
+		m.record(ctx, task.ID, protocol.StreamingMessageEvent{Result: task})
+	}
+	return result, nil
+}
+
+// OnSendMessageStream runs the task detached from the client connection and
+// records its events while forwarding them to the client.
+func (m *durableTaskManager) OnSendMessageStream(
+	ctx context.Context,
+	request protocol.SendMessageParams,
+) (<-chan protocol.StreamingMessageEvent, error) {
+	if err := m.authorizeMessage(ctx, request.Message); err != nil {
+		return nil, err
+	}
+	if err := m.validateRequestPushConfig(ctx, request.Configuration); err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	events, err := m.TaskManager.OnSendMessageStream(runCtx, request)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	out := make(chan protocol.StreamingMessageEvent, liveFeedBufSize)
+	go m.forward(ctx, runCtx, cancel, request.Configuration, events, out)
+	return out, nil
+}
+
+func (m *durableTaskManager) forward(
+	clientCtx context.Context,
+	runCtx context.Context,
+	cancel context.CancelFunc,
+	cfg *protocol.SendMessageConfiguration,
+	events <-chan protocol.StreamingMessageEvent,
+	out chan<- protocol.StreamingMessageEvent,
+) {
+	// Recording must outlive both the client and a cancelled run.
+	storeCtx := context.WithoutCancel(runCtx)
+	var taskID string
+	clientGone := false
+	defer func() {
+		close(out)
+		cancel()
+		if taskID != "" {
+			m.finish(taskID)
+		}
+	}()
+	for evt := range events {
+		if id := ia2a.StreamingEventTaskID(evt); id != "" && taskID == "" {
+			taskID = id
+			m.claim(storeCtx, taskID)
+			m.start(taskID, cancel)
+			m.setRequestPushConfig(storeCtx, taskID, cfg)
+		}
+		if taskID != "" {
+			m.record(storeCtx, taskID, evt)
+		}
+		if clientGone {
+			continue
+		}
+		select {
+		case out <- evt:
+		case <-clientCtx.Done():
+			clientGone = true
+			log.Debugf("a2a: client left task %s, recording in background", taskID)
+		}
+	}
+}
+
+// record appends evt to the task log, then publishes it to resubscribed
+// clients and the push outbox.
+func (m *durableTaskManager) record(
+	ctx context.Context,
+	taskID string,
+	evt protocol.StreamingMessageEvent,
+) {
+	m.logMu.Lock()
+	seq, err := m.store.AppendTaskEvent(ctx, taskID, evt)
+	if err == nil {
+		ia2a.SetTaskEventSeq(evt, seq)
+	}
+	m.logMu.Unlock()
+	if err != nil {
+		log.Warnf("a2a: failed to record event for task %s: %v", taskID, err)
+		return
+	}
+	m.publish(taskID, evt)
+	m.enqueuePush(ctx, taskID, seq, evt)
+}
+
+// claim records the user of ctx as the owner of a task that has none yet.
+func (m *durableTaskManager) claim(ctx context.Context, taskID string) {
+	if _, ok, err := m.store.TaskOwner(ctx, taskID); err != nil || ok {
+		if err != nil {
+			log.Warnf("a2a: failed to load owner of task %s: %v", taskID, err)
+		}
+		return
+	}
+	userID, _ := UserIDFromContext(ctx)
+	if err := m.store.SetTaskOwner(ctx, taskID, userID); err != nil {
+		log.Warnf("a2a: failed to record owner of task %s: %v", taskID, err)
+	}
+}
+
+// authorize checks that the user of ctx owns taskID. Tasks of other users
+// are reported as not found, so their IDs cannot be probed.
+func (m *durableTaskManager) authorize(ctx context.Context, taskID string) error {
+	owner, ok, err := m.store.TaskOwner(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("load task owner: %w", err)
+	}
+	if userID, _ := UserIDFromContext(ctx); !ok || owner != userID {
+		return taskmanager.ErrTaskNotFound(taskID)
+	}
+	return nil
+}
+
+// authorizeMessage checks that a message continuing a task is sent by the
+// owner of that task. A task ID without an owner is a new task the client
+// named itself.
+func (m *durableTaskManager) authorizeMessage(ctx context.Context, msg protocol.Message) error {
+	if msg.TaskID == nil || *msg.TaskID == "" {
+		return nil
+	}
+	taskID := *msg.TaskID
+	owner, ok, err := m.store.TaskOwner(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("load task owner: %w", err)
+	}
+	if userID, _ := UserIDFromContext(ctx); ok && owner != userID {
+		return taskmanager.ErrTaskNotFound(taskID)
+	}
+	return nil
+}
+
+func (m *durableTaskManager) start(taskID string, cancel context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancels[taskID] = cancel
+}
+
+// finish closes the live feeds of a task whose stream ended.
+func (m *durableTaskManager) finish(taskID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cancels, taskID)
+	for feed := range m.feeds[taskID] {
+		close(feed)
+	}
+	delete(m.feeds, taskID)
+}
+
+func (m *durableTaskManager) publish(taskID string, evt protocol.StreamingMessageEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for feed := range m.feeds[taskID] {
+		select {
+		case feed <- evt:
+		default:
+			// The client fell behind; dropping it lets it resubscribe from
+			// the log instead of stalling the task.
+			close(feed)
+			delete(m.feeds[taskID], feed)
+		}
+	}
+}
+
+// subscribe registers a live feed for a running task. It returns nil when
+// the task is not running in this process.
+func (m *durableTaskManager) subscribe(taskID string) chan protocol.StreamingMessageEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, running := m.cancels[taskID]; !running {
+		return nil
+	}
+	feed := make(chan protocol.StreamingMessageEvent, liveFeedBufSize)
+	if m.feeds[taskID] == nil {
+		m.feeds[taskID] = make(map[chan protocol.StreamingMessageEvent]struct{})
+	}
+	m.feeds[taskID][feed] = struct{}{}
+	return feed
+}
+
+func (m *durableTaskManager) unsubscribe(taskID string, feed chan protocol.StreamingMessageEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.feeds[taskID][feed]; ok {
+		delete(m.feeds[taskID], feed)
+		close(feed)
+	}
+}
+
+// OnResubscribe replays the recorded events after the last_event_seq
+// metadata value and then follows the task until it ends.
+func (m *durableTaskManager) OnResubscribe(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (<-chan protocol.StreamingMessageEvent, error) {
+	if err := m.authorize(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	afterSeq := ia2a.LastEventSeq(params.Metadata)
+	// Subscribe before reading the log so that no event falls in between;
+	// duplicates are skipped by sequence number.
+	feed := m.subscribe(params.ID)
+	m.logMu.Lock()
+	replay, err := m.store.TaskEvents(ctx, params.ID, afterSeq)
+	for _, e := range replay {
+		if ia2a.TaskEventSeq(e.Event) != e.Seq {
+			ia2a.SetTaskEventSeq(e.Event, e.Seq)
+		}
+	}
+	m.logMu.Unlock()
+	if err != nil {
+		if feed != nil {
+			m.unsubscribe(params.ID, feed)
+		}
+		return nil, fmt.Errorf("read task log: %w", err)
+	}
+	if replay == nil && feed == nil {
+		return nil, taskmanager.ErrTaskNotFound(params.ID)
+	}
+	out := make(chan protocol.StreamingMessageEvent, liveFeedBufSize)
+	go func() {
+		defer close(out)
+		if feed != nil {
+			defer m.unsubscribe(params.ID, feed)
+		}
+		send := func(evt protocol.StreamingMessageEvent) bool {
+			select {
+			case out <- evt:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		last := afterSeq
+		for _, e := range replay {
+			if !send(e.Event) || ia2a.IsFinalStreamingEvent(e.Event) {
+				return
+			}
+			last = e.Seq
+		}
+		if feed == nil {
+			return
+		}
+		for {
+			select {
+			case evt, ok := <-feed:
+				if !ok {
+					return
+				}
+				if ia2a.TaskEventSeq(evt) <= last {
+					continue
+				}
+				if !send(evt) || ia2a.IsFinalStreamingEvent(evt) {
+					return
+				}
+				last = ia2a.TaskEventSeq(evt)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// OnGetTask returns a task to its owner.
+func (m *durableTaskManager) OnGetTask(
+	ctx context.Context,
+	params protocol.TaskQueryParams,
+) (*protocol.Task, error) {
+	if err := m.authorize(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	return m.TaskManager.OnGetTask(ctx, params)
+}
+
+// OnCancelTask stops a detached streaming run before cancelling the task.
+func (m *durableTaskManager) OnCancelTask(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.Task, error) {
+	if err := m.authorize(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	cancel := m.cancels[params.ID]
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return m.TaskManager.OnCancelTask(ctx, params)
+}
+
+// OnPushNotificationSet validates and stores the push notification
+// configuration of a task.
+func (m *durableTaskManager) OnPushNotificationSet(
+	ctx context.Context,
+	params protocol.TaskPushNotificationConfig,
+) (*protocol.TaskPushNotificationConfig, error) {
+	if err := m.authorize(ctx, params.TaskID); err != nil {
+		return nil, err
+	}
+	if err := m.pusher.validateURL(ctx, params.PushNotificationConfig.URL); err != nil {
+		return nil, err
+	}
+	if err := m.store.SetPushConfig(ctx, params); err != nil {
+		return nil, fmt.Errorf("store push notification config: %w", err)
+	}
+	return &params, nil
+}
+
+// OnPushNotificationGet returns the stored push notification configuration
+// of a task.
+func (m *durableTaskManager) OnPushNotificationGet(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskPushNotificationConfig, error) {
+	if err := m.authorize(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	cfg, err := m.store.PushConfig(ctx, params.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load push notification config: %w", err)
+	}
+	if cfg == nil {
+		return nil, taskmanager.ErrTaskNotFound(params.ID)
+	}
+	return cfg, nil
+}
+
+// validateRequestPushConfig checks the push notification URL sent with
+// message/send or message/stream before the task starts.
+func (m *durableTaskManager) validateRequestPushConfig(
+	ctx context.Context,
+	cfg *protocol.SendMessageConfiguration,
+) error {
+	if cfg == nil || cfg.PushNotificationConfig == nil ||
+		cfg.PushNotificationConfig.URL == "" {
+		return nil
+	}
+	return m.pusher.validateURL(ctx, cfg.PushNotificationConfig.URL)
+}
+
+// setRequestPushConfig stores the push notification configuration sent with
+// message/send or message/stream once the task ID is known.
+func (m *durableTaskManager) setRequestPushConfig(
+	ctx context.Context,
+	taskID string,
+	cfg *protocol.SendMessageConfiguration,
+) {
+	if cfg == nil || cfg.PushNotificationConfig == nil ||
+		cfg.PushNotificationConfig.URL == "" {
+		return
+	}
+	err := m.store.SetPushConfig(ctx, protocol.TaskPushNotificationConfig{
+		TaskID:                 taskID,
+		PushNotificationConfig: *cfg.PushNotificationConfig,
+	})
+	if err != nil {
+		log.Warnf("a2a: failed to store push config for task %s: %v", taskID, err)
+	}
+}
+
+func (m *durableTaskManager) enqueuePush(
+	ctx context.Context,
+	taskID string,
+	seq int64,
+	evt protocol.StreamingMessageEvent,
+) {
+	cfg, err := m.store.PushConfig(ctx, taskID)
+	if err != nil {
+		log.Warnf("a2a: failed to load push config for task %s: %v", taskID, err)
+		return
+	}
+	if cfg == nil || cfg.PushNotificationConfig.URL == "" {
+		return
+	}
+	payload, err := json.Marshal(&evt)
+	if err != nil {
+		log.Warnf("a2a: failed to encode push notification for task %s: %v", taskID, err)
+		return
+	}
+	err = m.store.EnqueuePush(ctx, PushDelivery{
+		ID:      taskID + "/" + strconv.FormatInt(seq, 10),
+		TaskID:  taskID,
+		Seq:     seq,
+		Config:  cfg.PushNotificationConfig,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Warnf("a2a: failed to queue push notification for task %s: %v", taskID, err)
+		return
+	}
+	m.pusher.notify()
+}
@@ -0,0 +1,451 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	a2a "trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	ia2a "trpc.group/trpc-go/trpc-agent-go/internal/a2a"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	srvauth "trpc.group/trpc-go/trpc-agent-go/server/auth"
+)
+
+func textResponseEvent(id, text string) *event.Event {
+	return event.NewResponseEvent("inv", "agent", &model.Response{
+		ID:      id,
+		Object:  model.ObjectTypeChatCompletion,
+		Created: time.Now().Unix(),
+		Choices: []model.Choice{{
+			Message: model.Message{Role: model.RoleAssistant, Content: text},
+		}},
+	})
+}
+
+func TestTaskStore_ResubscribeAfterDisconnect(t *testing.T) {
+	release := make(chan struct{})
+	runner := &mockRunner{runFunc: func(
+		ctx context.Context, _ string, _ string, _ model.Message, _ ...agent.RunOption,
+	) (<-chan *event.Event, error) {
+		ch := make(chan *event.Event, 2)
+		go func() {
+			defer close(ch)
+			ch <- textResponseEvent("r1", "first")
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return
+			}
+			ch <- textResponseEvent("r2", "second")
+		}()
+		return ch, nil
+	}}
+
+	secret := []byte("push-secret")
+	verifier, err := srvauth.NewHMACAuthenticator([]srvauth.HMACKey{
+		{ID: "srv", Secret: secret, Principal: srvauth.Principal{ID: "agent"}},
+	})
+	require.NoError(t, err)
+	var pushMu sync.Mutex
+	var pushed []int64
+	var failOnce atomic.Bool
+	failOnce.Store(true)
+	pushSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Authenticate(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(PushNotificationTokenHeader) != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if failOnce.CompareAndSwap(true, false) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		raw, _ := io.ReadAll(r.Body)
+		var evt protocol.StreamingMessageEvent
+		if json.Unmarshal(raw, &evt) == nil {
+			pushMu.Lock()
+			pushed = append(pushed, ia2a.TaskEventSeq(evt))
+			pushMu.Unlock()
+		}
+	}))
+	defer pushSrv.Close()
+
+	srv, err := New(
+		WithRunner(runner),
+		WithAgentCard(a2a.AgentCard{Name: "durable", URL: "http://placeholder.local"}),
+		WithTaskStore(NewInMemoryTaskStore()),
+		WithPushNotificationSigningKey("srv", secret),
+		WithPushNotificationRetry(3, time.Millisecond),
+		WithPushNotificationURLAllowlist(func(u *url.URL) bool {
+			return "http://"+u.Host == pushSrv.URL
+		}),
+	)
+	require.NoError(t, err)
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+	// The jar keeps the anonymous user of the client across requests.
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	cli, err := a2aclient.NewA2AClient(httpSrv.URL,
+		a2aclient.WithHTTPClient(&http.Client{Jar: jar}))
+	require.NoError(t, err)
+
+	resp, err := http.Get(httpSrv.URL + protocol.AgentCardPath)
+	require.NoError(t, err)
+	var card a2a.AgentCard
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+	resp.Body.Close()
+	require.NotNil(t, card.Capabilities.PushNotifications)
+	require.True(t, *card.Capabilities.PushNotifications)
+
+	streamCtx, disconnect := context.WithCancel(context.Background())
+	msg := protocol.NewMessage(protocol.MessageRoleUser,
+		[]protocol.Part{protocol.NewTextPart("hi")})
+	stream, err := cli.StreamMessage(streamCtx, protocol.SendMessageParams{
+		Message: msg,
+		Configuration: &protocol.SendMessageConfiguration{
+			PushNotificationConfig: &protocol.PushNotificationConfig{
+				URL: pushSrv.URL, Token: "tok",
+			},
+		},
+	})
+	require.NoError(t, err)
+	first := <-stream
+	taskID := ia2a.StreamingEventTaskID(first)
+	require.NotEmpty(t, taskID)
+	require.EqualValues(t, 1, ia2a.TaskEventSeq(first))
+	disconnect()
+	for range stream {
+	}
+
+	// While the task runs, only its owner can read it or send to it.
+	task, err := cli.GetTasks(context.Background(), protocol.TaskQueryParams{ID: taskID})
+	require.NoError(t, err)
+	require.Equal(t, taskID, task.ID)
+	other, err := a2aclient.NewA2AClient(httpSrv.URL)
+	require.NoError(t, err)
+	_, err = other.GetTasks(context.Background(), protocol.TaskQueryParams{ID: taskID})
+	require.Error(t, err)
+	cont := protocol.NewMessageWithContext(protocol.MessageRoleUser,
+		[]protocol.Part{protocol.NewTextPart("again")}, &taskID, nil)
+	_, err = other.SendMessage(context.Background(), protocol.SendMessageParams{Message: cont})
+	require.Error(t, err)
+	_, err = other.StreamMessage(context.Background(), protocol.SendMessageParams{Message: cont})
+	require.Error(t, err)
+
+	// The run outlives the dropped connection.
+	close(release)
+	events, err := cli.ResubscribeTask(context.Background(), protocol.TaskIDParams{
+		ID:       taskID,
+		Metadata: map[string]any{ia2a.ResubscribeMetadataLastEventSeqKey: 1},
+	})
+	require.NoError(t, err)
+	var seqs []int64
+	var last protocol.StreamingMessageEvent
+	for evt := range events {
+		seqs = append(seqs, ia2a.TaskEventSeq(evt))
+		last = evt
+	}
+	require.NotEmpty(t, seqs)
+	require.EqualValues(t, 2, seqs[0])
+	for i := 1; i < len(seqs); i++ {
+		require.Equal(t, seqs[i-1]+1, seqs[i])
+	}
+	require.True(t, ia2a.IsFinalStreamingEvent(last))
+
+	cfg, err := cli.GetPushNotification(context.Background(), protocol.TaskIDParams{ID: taskID})
+	require.NoError(t, err)
+	require.Equal(t, pushSrv.URL, cfg.PushNotificationConfig.URL)
+
+	// Every event is pushed once, in order, despite the failed first attempt.
+	require.Eventually(t, func() bool {
+		pushMu.Lock()
+		defer pushMu.Unlock()
+		return len(pushed) == len(seqs)+1
+	}, 5*time.Second, 10*time.Millisecond)
+	pushMu.Lock()
+	require.Equal(t, append([]int64{1}, seqs...), pushed)
+	pushMu.Unlock()
+
+	_, err = cli.ResubscribeTask(context.Background(), protocol.TaskIDParams{ID: "missing"})
+	require.Error(t, err)
+	_, err = cli.SetPushNotification(context.Background(), protocol.TaskPushNotificationConfig{
+		TaskID:                 taskID,
+		PushNotificationConfig: protocol.PushNotificationConfig{URL: "http://169.254.169.254/"},
+	})
+	require.Error(t, err)
+
+	// Other users cannot reach the task.
+	_, err = other.ResubscribeTask(context.Background(), protocol.TaskIDParams{ID: taskID})
+	require.Error(t, err)
+	_, err = other.GetPushNotification(context.Background(), protocol.TaskIDParams{ID: taskID})
+	require.Error(t, err)
+	_, err = other.SetPushNotification(context.Background(), protocol.TaskPushNotificationConfig{
+		TaskID:                 taskID,
+		PushNotificationConfig: protocol.PushNotificationConfig{URL: pushSrv.URL},
+	})
+	require.Error(t, err)
+	_, err = other.CancelTasks(context.Background(), protocol.TaskIDParams{ID: taskID})
+	require.Error(t, err)
+}
+
+func TestTaskStore_Implementations(t *testing.T) {
+	dir := t.TempDir()
+	fileStore, err := NewFileTaskStore(dir)
+	require.NoError(t, err)
+	stores := map[string]TaskStore{
+		"memory": NewInMemoryTaskStore(),
+		"file":   fileStore,
+	}
+	ctx := context.Background()
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			events, err := store.TaskEvents(ctx, "t", 0)
+			require.NoError(t, err)
+			require.Nil(t, events)
+
+			for i, state := range []protocol.TaskState{
+				protocol.TaskStateSubmitted, protocol.TaskStateWorking,
+				protocol.TaskStateCompleted,
+			} {
+				evt := protocol.NewTaskStatusUpdateEvent("t", "c",
+					protocol.TaskStatus{State: state}, i == 2)
+				seq, err := store.AppendTaskEvent(ctx, "t",
+					protocol.StreamingMessageEvent{Result: &evt})
+				require.NoError(t, err)
+				require.EqualValues(t, i+1, seq)
+			}
+			events, err = store.TaskEvents(ctx, "t", 1)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			require.EqualValues(t, 2, events[0].Seq)
+			require.True(t, ia2a.IsFinalStreamingEvent(events[1].Event))
+			events, err = store.TaskEvents(ctx, "t", 3)
+			require.NoError(t, err)
+			require.NotNil(t, events)
+			require.Empty(t, events)
+
+			cfg, err := store.PushConfig(ctx, "t")
+			require.NoError(t, err)
+			require.Nil(t, cfg)
+			require.NoError(t, store.SetPushConfig(ctx, protocol.TaskPushNotificationConfig{
+				TaskID:                 "t",
+				PushNotificationConfig: protocol.PushNotificationConfig{URL: "http://hook"},
+			}))
+			cfg, err = store.PushConfig(ctx, "t")
+			require.NoError(t, err)
+			require.Equal(t, "http://hook", cfg.PushNotificationConfig.URL)
+
+			for _, seq := range []int64{2, 1} {
+				require.NoError(t, store.EnqueuePush(ctx, PushDelivery{
+					ID: "t/" + string(rune('0'+seq)), TaskID: "t", Seq: seq,
+					Payload: json.RawMessage(`{}`),
+				}))
+			}
+			pending, err := store.PendingPushes(ctx)
+			require.NoError(t, err)
+			require.Len(t, pending, 2)
+			require.EqualValues(t, 1, pending[0].Seq)
+			pending[0].Attempts = 2
+			require.NoError(t, store.UpdatePush(ctx, pending[0]))
+			require.NoError(t, store.DeletePush(ctx, pending[1].ID))
+			pending, err = store.PendingPushes(ctx)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			require.Equal(t, 2, pending[0].Attempts)
+			require.Error(t, store.UpdatePush(ctx, PushDelivery{ID: "gone"}))
+
+			_, ok, err := store.TaskOwner(ctx, "t")
+			require.NoError(t, err)
+			require.False(t, ok)
+			require.NoError(t, store.SetTaskOwner(ctx, "t", "alice"))
+			owner, ok, err := store.TaskOwner(ctx, "t")
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "alice", owner)
+		})
+	}
+
+	// A reopened file store continues the task log.
+	reopened, err := NewFileTaskStore(dir)
+	require.NoError(t, err)
+	evt := protocol.NewTaskStatusUpdateEvent("t", "c", protocol.TaskStatus{}, false)
+	seq, err := reopened.AppendTaskEvent(ctx, "t", protocol.StreamingMessageEvent{Result: &evt})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, seq)
+}
+
+func TestPushDispatcher_DropsAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hook.Close()
+	store := NewInMemoryTaskStore()
+	p := newPushDispatcher(store, &options{
+		pushMaxAttempts:  2,
+		pushBackoff:      time.Millisecond,
+		pushURLAllowlist: func(*url.URL) bool { return true },
+	})
+	ctx := context.Background()
+	for seq := int64(1); seq <= 2; seq++ {
+		require.NoError(t, store.EnqueuePush(ctx, PushDelivery{
+			ID: "t/" + string(rune('0'+seq)), TaskID: "t", Seq: seq,
+			Config:  protocol.PushNotificationConfig{URL: hook.URL},
+			Payload: json.RawMessage(`{}`),
+		}))
+	}
+	p.notify()
+	require.Eventually(t, func() bool {
+		pending, _ := store.PendingPushes(ctx)
+		return len(pending) == 0
+	}, 5*time.Second, 5*time.Millisecond)
+	require.EqualValues(t, 4, calls.Load())
+	require.Equal(t, 4*time.Millisecond, p.retryDelay(3))
+	require.Equal(t, maxPushBackoff, p.retryDelay(40))
+}
+
+// failingDeleteStore fails the first deletes of the outbox.
+type failingDeleteStore struct {
+	TaskStore
+	failures atomic.Int32
+}
+
+func (s *failingDeleteStore) DeletePush(ctx context.Context, id string) error {
+	if s.failures.Add(-1) >= 0 {
+		return errors.New("delete failed")
+	}
+	return s.TaskStore.DeletePush(ctx, id)
+}
+
+func TestPushDispatcher_RetriesFailedRemoval(t *testing.T) {
+	var calls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		calls.Add(1)
+	}))
+	defer hook.Close()
+	store := &failingDeleteStore{TaskStore: NewInMemoryTaskStore()}
+	store.failures.Store(2)
+	p := newPushDispatcher(store, &options{
+		pushBackoff:      10 * time.Millisecond,
+		pushURLAllowlist: func(*url.URL) bool { return true },
+	})
+	ctx := context.Background()
+	require.NoError(t, store.EnqueuePush(ctx, PushDelivery{
+		ID: "t/1", TaskID: "t", Seq: 1,
+		Config:  protocol.PushNotificationConfig{URL: hook.URL},
+		Payload: json.RawMessage(`{}`),
+	}))
+	p.notify()
+	require.Eventually(t, func() bool {
+		pending, _ := store.PendingPushes(ctx)
+		return len(pending) == 0
+	}, 5*time.Second, 5*time.Millisecond)
+	require.EqualValues(t, 1, calls.Load(), "a sent notification must not be sent again")
+	require.Less(t, store.failures.Load(), int32(0))
+}
+
+func TestPushDispatcher_ValidateURL(t *testing.T) {
+	p := newPushDispatcher(NewInMemoryTaskStore(), &options{})
+	ctx := context.Background()
+	for _, raw := range []string{
+		"ftp://example.com/hook",
+		"http:///hook",
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		require.ErrorIs(t, p.validateURL(ctx, raw), errPushURLNotAllowed, raw)
+	}
+	require.NoError(t, p.validateURL(ctx, "https://8.8.8.8/hook"))
+
+	// The default client refuses to connect to internal addresses even
+	// when a host resolves to one after validation.
+	hook := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("internal receiver must not be called")
+	}))
+	defer hook.Close()
+	err := p.deliver(ctx, PushDelivery{Config: protocol.PushNotificationConfig{URL: hook.URL}})
+	require.ErrorIs(t, err, errPushURLNotAllowed)
+
+	p = newPushDispatcher(NewInMemoryTaskStore(), &options{
+		pushURLAllowlist: func(u *url.URL) bool { return u.Hostname() == "hooks.internal" },
+	})
+	require.NoError(t, p.validateURL(ctx, "http://hooks.internal/hook"))
+	require.ErrorIs(t, p.validateURL(ctx, "http://example.com/hook"), errPushURLNotAllowed)
+	require.ErrorIs(t, p.validateURL(ctx, "file://hooks.internal/hook"), errPushURLNotAllowed)
+}
+
+func TestMemoryTaskStore_Limits(t *testing.T) {
+	store := NewInMemoryTaskStore(
+		WithMemoryTaskStoreMaxEvents(2),
+		WithMemoryTaskStoreTTL(time.Minute),
+	).(*memoryTaskStore)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+	appendEvent := func(taskID string, final bool) int64 {
+		evt := protocol.NewTaskStatusUpdateEvent(taskID, "c", protocol.TaskStatus{}, final)
+		seq, err := store.AppendTaskEvent(ctx, taskID, protocol.StreamingMessageEvent{Result: &evt})
+		require.NoError(t, err)
+		return seq
+	}
+
+	for i := 1; i <= 3; i++ {
+		require.EqualValues(t, i, appendEvent("done", i == 3))
+	}
+	require.NoError(t, store.SetTaskOwner(ctx, "done", "alice"))
+	events, err := store.TaskEvents(ctx, "done", 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.EqualValues(t, 2, events[0].Seq)
+	events, err = store.TaskEvents(ctx, "done", 2)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 3, events[0].Seq)
+	appendEvent("running", false)
+
+	now = now.Add(2 * time.Minute)
+	appendEvent("running", false)
+	events, err = store.TaskEvents(ctx, "done", 0)
+	require.NoError(t, err)
+	require.Nil(t, events, "finished tasks are evicted after the TTL")
+	_, ok, err := store.TaskOwner(ctx, "done")
+	require.NoError(t, err)
+	require.False(t, ok)
+	events, err = store.TaskEvents(ctx, "running", 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
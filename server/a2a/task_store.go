@@ -0,0 +1,567 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package a2a
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	ia2a "trpc.group/trpc-go/trpc-agent-go/internal/a2a"
+)
+
+// TaskEvent is one entry of a persisted task event log.
+type TaskEvent struct {
+	// Seq is the position of the event in the task log, starting at 1.
+	Seq int64
+	// Event is the streaming event as sent to the client.
+	Event protocol.StreamingMessageEvent
+}
+
+// PushDelivery is a push notification waiting in the outbox.
+type PushDelivery struct {
+	// ID identifies the delivery in the outbox.
+	ID string `json:"id"`
+	// TaskID is the task the notification belongs to.
+	TaskID string `json:"taskId"`
+	// Seq is the task event sequence the notification carries.
+	Seq int64 `json:"seq"`
+	// Config is the push notification configuration of the task when the
+	// event was recorded.
+	Config protocol.PushNotificationConfig `json:"config"`
+	// Payload is the JSON encoded event.
+	Payload json.RawMessage `json:"payload"`
+	// Attempts is the number of failed delivery attempts so far.
+	Attempts int `json:"attempts"`
+	// Done reports that the delivery was sent or dropped and only its
+	// removal from the outbox is still pending.
+	Done bool `json:"done,omitempty"`
+	// NextAttempt is the earliest time of the next delivery attempt.
+	NextAttempt time.Time `json:"nextAttempt"`
+}
+
+// TaskStore persists task event logs, push notification configurations and
+// the push notification outbox, so that clients can resubscribe to a task
+// and notifications survive restarts.
+type TaskStore interface {
+	// AppendTaskEvent appends evt to the log of taskID and returns its
+	// sequence number.
+	AppendTaskEvent(
+		ctx context.Context,
+		taskID string,
+		evt protocol.StreamingMessageEvent,
+	) (int64, error)
+	// TaskEvents returns the events of taskID with a sequence number greater
+	// than afterSeq, in order. It returns nil when the task has no log.
+	TaskEvents(ctx context.Context, taskID string, afterSeq int64) ([]TaskEvent, error)
+
+	// SetTaskOwner records the user that created taskID.
+	SetTaskOwner(ctx context.Context, taskID string, owner string) error
+	// TaskOwner returns the user that created taskID and whether one is
+	// recorded.
+	TaskOwner(ctx context.Context, taskID string) (string, bool, error)
+
+	// SetPushConfig stores the push notification configuration of a task.
+	SetPushConfig(ctx context.Context, cfg protocol.TaskPushNotificationConfig) error
+	// PushConfig returns the push notification configuration of taskID, or
+	// nil when none is set.
+	PushConfig(ctx context.Context, taskID string) (*protocol.TaskPushNotificationConfig, error)
+
+	// EnqueuePush adds d to the outbox.
+	EnqueuePush(ctx context.Context, d PushDelivery) error
+	// PendingPushes returns every delivery in the outbox ordered by task
+	// event sequence.
+	PendingPushes(ctx context.Context) ([]PushDelivery, error)
+	// UpdatePush replaces a delivery in the outbox.
+	UpdatePush(ctx context.Context, d PushDelivery) error
+	// DeletePush removes a delivery from the outbox.
+	DeletePush(ctx context.Context, id string) error
+}
+
+const (
+	defaultMemoryTaskStoreMaxEvents = 1000
+	defaultMemoryTaskStoreTTL       = time.Hour
+)
+
+// MemoryTaskStoreOption configures the store created by
+// NewInMemoryTaskStore.
+type MemoryTaskStoreOption func(*memoryTaskStore)
+
+// WithMemoryTaskStoreMaxEvents sets how many events the log of a task
+// keeps. Older events are dropped, so a client resubscribing from before
+// them misses them. Defaults to 1000.
+func WithMemoryTaskStoreMaxEvents(n int) MemoryTaskStoreOption {
+	return func(s *memoryTaskStore) {
+		if n > 0 {
+			s.maxEvents = n
+		}
+	}
+}
+
+// WithMemoryTaskStoreTTL sets how long a finished task is kept after its
+// final event. Defaults to one hour.
+func WithMemoryTaskStoreTTL(ttl time.Duration) MemoryTaskStoreOption {
+	return func(s *memoryTaskStore) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// NewInMemoryTaskStore returns a TaskStore that keeps everything in memory.
+// Task logs are lost on restart; use NewFileTaskStore to keep them. The log
+// of each task is capped and finished tasks are evicted after a TTL.
+func NewInMemoryTaskStore(opts ...MemoryTaskStoreOption) TaskStore {
+	s := &memoryTaskStore{
+		maxEvents: defaultMemoryTaskStoreMaxEvents,
+		ttl:       defaultMemoryTaskStoreTTL,
+		now:       time.Now,
+		events:    make(map[string][]TaskEvent),
+		seqs:      make(map[string]int64),
+		finished:  make(map[string]time.Time),
+		owners:    make(map[string]string),
+		push:      make(map[string]protocol.TaskPushNotificationConfig),
+		outbox:    make(map[string]PushDelivery),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type memoryTaskStore struct {
+	maxEvents int
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu        sync.RWMutex
+	events    map[string][]TaskEvent
+	seqs      map[string]int64
+	finished  map[string]time.Time
+	lastSweep time.Time
+	owners    map[string]string
+	push      map[string]protocol.TaskPushNotificationConfig
+	outbox    map[string]PushDelivery
+}
+
+func (s *memoryTaskStore) AppendTaskEvent(
+	_ context.Context,
+	taskID string,
+	evt protocol.StreamingMessageEvent,
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictFinished()
+	s.seqs[taskID]++
+	seq := s.seqs[taskID]
+	events := append(s.events[taskID], TaskEvent{Seq: seq, Event: evt})
+	if n := len(events) - s.maxEvents; n > 0 {
+		events = slices.Delete(events, 0, n)
+	}
+	s.events[taskID] = events
+	if ia2a.IsFinalStreamingEvent(evt) {
+		s.finished[taskID] = s.now()
+	} else {
+		delete(s.finished, taskID)
+	}
+	return seq, nil
+}
+
+// evictFinished drops the tasks that finished more than the TTL ago. It
+// scans the tasks at most once per TTL.
+func (s *memoryTaskStore) evictFinished() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+	for taskID, at := range s.finished {
+		if now.Sub(at) < s.ttl {
+			continue
+		}
+		delete(s.finished, taskID)
+		delete(s.events, taskID)
+		delete(s.seqs, taskID)
+		delete(s.owners, taskID)
+		delete(s.push, taskID)
+	}
+}
+
+func (s *memoryTaskStore) TaskEvents(
+	_ context.Context,
+	taskID string,
+	afterSeq int64,
+) ([]TaskEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events, ok := s.events[taskID]
+	if !ok {
+		return nil, nil
+	}
+	i, _ := slices.BinarySearchFunc(events, afterSeq+1, func(e TaskEvent, seq int64) int {
+		return cmp.Compare(e.Seq, seq)
+	})
+	return slices.Clone(events[i:]), nil
+}
+
+func (s *memoryTaskStore) SetTaskOwner(_ context.Context, taskID string, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners[taskID] = owner
+	return nil
+}
+
+func (s *memoryTaskStore) TaskOwner(_ context.Context, taskID string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	owner, ok := s.owners[taskID]
+	return owner, ok, nil
+}
+
+func (s *memoryTaskStore) SetPushConfig(
+	_ context.Context,
+	cfg protocol.TaskPushNotificationConfig,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.push[cfg.TaskID] = cfg
+	return nil
+}
+
+func (s *memoryTaskStore) PushConfig(
+	_ context.Context,
+	taskID string,
+) (*protocol.TaskPushNotificationConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cfg, ok := s.push[taskID]
+	if !ok {
+		return nil, nil
+	}
+	return &cfg, nil
+}
+
+func (s *memoryTaskStore) EnqueuePush(_ context.Context, d PushDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outbox[d.ID] = d
+	return nil
+}
+
+func (s *memoryTaskStore) PendingPushes(_ context.Context) ([]PushDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pending := make([]PushDelivery, 0, len(s.outbox))
+	for _, d := range s.outbox {
+		pending = append(pending, d)
+	}
+	sortPushDeliveries(pending)
+	return pending, nil
+}
+
+func (s *memoryTaskStore) UpdatePush(_ context.Context, d PushDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.outbox[d.ID]; !ok {
+		return fmt.Errorf("push delivery %q not found", d.ID)
+	}
+	s.outbox[d.ID] = d
+	return nil
+}
+
+func (s *memoryTaskStore) DeletePush(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.outbox, id)
+	return nil
+}
+
+func sortPushDeliveries(pending []PushDelivery) {
+	slices.SortFunc(pending, func(a, b PushDelivery) int {
+		if c := strings.Compare(a.TaskID, b.TaskID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+}
+
+const (
+	fileTaskStoreEventsDir = "events"
+	fileTaskStorePushDir   = "push"
+	fileTaskStoreOwnerDir  = "owner"
+	fileTaskStoreOutboxDir = "outbox"
+)
+
+// NewFileTaskStore returns a TaskStore that persists task logs, push
+// configurations and the outbox as files under dir. Each task log is an
+// append-only JSON lines file.
+func NewFileTaskStore(dir string) (TaskStore, error) {
+	if dir == "" {
+		return nil, errors.New("task store directory is required")
+	}
+	for _, sub := range []string{
+		fileTaskStoreEventsDir, fileTaskStorePushDir, fileTaskStoreOwnerDir,
+		fileTaskStoreOutboxDir,
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("create task store directory: %w", err)
+		}
+	}
+	return &fileTaskStore{dir: dir, seqs: make(map[string]int64)}, nil
+}
+
+type fileTaskStore struct {
+	dir  string
+	mu   sync.Mutex
+	seqs map[string]int64
+}
+
+// fileName maps an arbitrary ID to a safe file name.
+func fileName(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *fileTaskStore) eventsPath(taskID string) string {
+	return filepath.Join(s.dir, fileTaskStoreEventsDir, fileName(taskID)+".jsonl")
+}
+
+func (s *fileTaskStore) pushPath(taskID string) string {
+	return filepath.Join(s.dir, fileTaskStorePushDir, fileName(taskID)+".json")
+}
+
+func (s *fileTaskStore) ownerPath(taskID string) string {
+	return filepath.Join(s.dir, fileTaskStoreOwnerDir, fileName(taskID)+".json")
+}
+
+func (s *fileTaskStore) outboxPath(id string) string {
+	return filepath.Join(s.dir, fileTaskStoreOutboxDir, fileName(id)+".json")
+}
+
+type fileTaskEvent struct {
+	Seq   int64           `json:"seq"`
+	Event json.RawMessage `json:"event"`
+}
+
+func (s *fileTaskStore) AppendTaskEvent(
+	_ context.Context,
+	taskID string,
+	evt protocol.StreamingMessageEvent,
+) (int64, error) {
+	raw, err := json.Marshal(&evt)
+	if err != nil {
+		return 0, fmt.Errorf("marshal task event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seq, ok := s.seqs[taskID]
+	if !ok {
+		events, err := s.readEvents(taskID, 0)
+		if err != nil {
+			return 0, err
+		}
+		if n := len(events); n > 0 {
+			seq = events[n-1].Seq
+		}
+	}
+	seq++
+	line, err := json.Marshal(fileTaskEvent{Seq: seq, Event: raw})
+	if err != nil {
+		return 0, fmt.Errorf("marshal task event: %w", err)
+	}
+	f, err := os.OpenFile(s.eventsPath(taskID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("open task log: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("write task log: %w", err)
+	}
+	s.seqs[taskID] = seq
+	return seq, nil
+}
+
+func (s *fileTaskStore) TaskEvents(
+	_ context.Context,
+	taskID string,
+	afterSeq int64,
+) ([]TaskEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readEvents(taskID, afterSeq)
+}
+
+func (s *fileTaskStore) readEvents(taskID string, afterSeq int64) ([]TaskEvent, error) {
+	f, err := os.Open(s.eventsPath(taskID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open task log: %w", err)
+	}
+	defer f.Close()
+	events := []TaskEvent{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry fileTaskEvent
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("decode task log: %w", err)
+		}
+		if entry.Seq <= afterSeq {
+			continue
+		}
+		var evt protocol.StreamingMessageEvent
+		if err := json.Unmarshal(entry.Event, &evt); err != nil {
+			return nil, fmt.Errorf("decode task event %d: %w", entry.Seq, err)
+		}
+		events = append(events, TaskEvent{Seq: entry.Seq, Event: evt})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read task log: %w", err)
+	}
+	return events, nil
+}
+
+// fileTaskOwner is the content of an owner file.
+type fileTaskOwner struct {
+	Owner string `json:"owner"`
+}
+
+func (s *fileTaskStore) SetTaskOwner(_ context.Context, taskID string, owner string) error {
+	return writeJSONFile(s.ownerPath(taskID), fileTaskOwner{Owner: owner})
+}
+
+func (s *fileTaskStore) TaskOwner(_ context.Context, taskID string) (string, bool, error) {
+	var o fileTaskOwner
+	ok, err := readJSONFile(s.ownerPath(taskID), &o)
+	return o.Owner, ok, err
+}
+
+func (s *fileTaskStore) SetPushConfig(
+	_ context.Context,
+	cfg protocol.TaskPushNotificationConfig,
+) error {
+	return writeJSONFile(s.pushPath(cfg.TaskID), cfg)
+}
+
+func (s *fileTaskStore) PushConfig(
+	_ context.Context,
+	taskID string,
+) (*protocol.TaskPushNotificationConfig, error) {
+	var cfg protocol.TaskPushNotificationConfig
+	ok, err := readJSONFile(s.pushPath(taskID), &cfg)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (s *fileTaskStore) EnqueuePush(_ context.Context, d PushDelivery) error {
+	return writeJSONFile(s.outboxPath(d.ID), d)
+}
+
+func (s *fileTaskStore) PendingPushes(_ context.Context) ([]PushDelivery, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, fileTaskStoreOutboxDir))
+	if err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+	pending := make([]PushDelivery, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		var d PushDelivery
+		path := filepath.Join(s.dir, fileTaskStoreOutboxDir, entry.Name())
+		ok, err := readJSONFile(path, &d)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			pending = append(pending, d)
+		}
+	}
+	sortPushDeliveries(pending)
+	return pending, nil
+}
+
+func (s *fileTaskStore) UpdatePush(_ context.Context, d PushDelivery) error {
+	path := s.outboxPath(d.ID)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("push delivery %q not found: %w", d.ID, err)
+	}
+	return writeJSONFile(path, d)
+}
+
+func (s *fileTaskStore) DeletePush(_ context.Context, id string) error {
+	err := os.Remove(s.outboxPath(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete push delivery: %w", err)
+	}
+	return nil
+}
+
+// writeJSONFile writes v to path atomically.
+func writeJSONFile(path string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", filepath.Base(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	_, err = tmp.Write(raw)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func readJSONFile(path string, v any) (bool, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
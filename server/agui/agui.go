@@ -11,14 +11,17 @@
 package agui
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/agui/adapter"
 	aguirunner "trpc.group/trpc-go/trpc-agent-go/server/agui/runner"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/service"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -37,6 +40,11 @@ func New(runner runner.Runner, opt ...Option) (*Server, error) {
 		return nil, errors.New("agui: runner must not be nil")
 	}
 	opts := newOptions(opt...)
+	if opts.authenticator != nil {
+		// Appended last so the principal wins over other resolvers.
+		opts.aguiRunnerOptions = append(opts.aguiRunnerOptions,
+			aguirunner.WithUserIDResolver(principalUserID))
+	}
 	aguiService, err := newService(runner, opts)
 	if err != nil {
 		return nil, fmt.Errorf("new service: %w", err)
@@ -45,15 +53,29 @@ func New(runner runner.Runner, opt ...Option) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("agui: url join chat path: %w", err)
 	}
+	handler := aguiService.Handler()
+	if opts.authenticator != nil {
+		handler = auth.Middleware(opts.authenticator,
+			auth.WithResource(auth.Resource{App: opts.appName}))(handler)
+	}
 	return &Server{
 		basePath:       opts.basePath,
 		appName:        opts.appName,
 		path:           chatPath,
 		sessionService: opts.sessionService,
-		handler:        aguiService.Handler(),
+		handler:        handler,
 	}, nil
 }
 
+// principalUserID resolves the user ID from the authenticated principal.
+func principalUserID(ctx context.Context, _ *adapter.RunAgentInput) (string, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return "", errors.New("agui: request is not authenticated")
+	}
+	return p.ID, nil
+}
+
 // newService creates a new service instance.
 func newService(runner runner.Runner, opts *options) (service.Service, error) {
 	if opts.serviceFactory == nil {
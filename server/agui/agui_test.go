@@ -29,6 +29,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/adapter"
 	aguirunner "trpc.group/trpc-go/trpc-agent-go/server/agui/runner"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/service"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 	"trpc.group/trpc-go/trpc-agent-go/session/inmemory"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
@@ -817,3 +818,33 @@ func (fakeSessionService) GetSessionSummaryText(context.Context, *session.Sessio
 }
 
 func (fakeSessionService) Close() error { return nil }
+
+func TestAuthenticatorSetsRunUserID(t *testing.T) {
+	agent := &mockAgent{info: agent.Info{Name: "demo"}}
+	r := runner.NewRunner(agent.Info().Name, agent)
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Key: "k1", Principal: auth.Principal{ID: "alice"}},
+		{Key: "k2", Principal: auth.Principal{ID: "bob", Apps: []string{"other"}}},
+	})
+	assert.NoError(t, err)
+	srv, err := New(r, WithPath("/agui"), WithAppName("demo"), WithAuthenticator(keys))
+	assert.NoError(t, err)
+
+	call := func(key string) int {
+		payload := `{"threadId":"thread-1","runId":"run-1","messages":[{"role":"user","content":"hi"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/agui", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusForbidden, call("k2"))
+	assert.Equal(t, 0, agent.runCalls)
+	assert.Equal(t, http.StatusOK, call("k1"))
+	assert.Equal(t, 1, agent.runCalls)
+	assert.Equal(t, "alice", agent.lastInvocation.Session.UserID)
+}
@@ -16,6 +16,7 @@ import (
 	aguirunner "trpc.group/trpc-go/trpc-agent-go/server/agui/runner"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/service"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/service/sse"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -44,6 +45,7 @@ type options struct {
 	heartbeatInterval        time.Duration
 	appName                  string
 	sessionService           session.Service
+	authenticator            auth.Authenticator
 }
 
 // newOptions creates a new options instance.
@@ -326,3 +328,13 @@ func WithSessionService(service session.Service) Option {
 		o.aguiRunnerOptions = append(o.aguiRunnerOptions, aguirunner.WithSessionService(service))
 	}
 }
+
+// WithAuthenticator requires every request to authenticate with a. The
+// principal ID becomes the user ID of runs, cancels and snapshots,
+// replacing any user ID resolver, and the principal must be allowed to use
+// the app set by WithAppName.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(o *options) {
+		o.authenticator = a
+	}
+}
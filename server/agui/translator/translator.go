@@ -145,6 +145,7 @@ func (t *translator) Translate(ctx context.Context, event *agentevent.Event) ([]
 	// Handle node custom events (progress, text, custom).
 	events = append(events, t.graphNodeCustomEvents(event)...)
 	events = append(events, t.toolArtifactsEvents(event)...)
+	events = append(events, t.progressEvents(event)...)
 	queuedUserEvents, handled, err := t.queuedUserMessageEvents(event)
 	if err != nil {
 		return nil, err
@@ -377,6 +378,28 @@ func (t *translator) toolArtifactsEvents(evt *agentevent.Event) []aguievents.Eve
 	}
 }
 
+// progressEvents converts the progress snapshot published by an agent
+// into an AG-UI custom event named after it.
+func (t *translator) progressEvents(evt *agentevent.Event) []aguievents.Event {
+	if t == nil {
+		return nil
+	}
+	progress, ok := agentevent.GetProgress(evt)
+	if !ok {
+		return nil
+	}
+	payload := map[string]any{
+		"threadId": t.threadID,
+		"runId":    t.runID,
+	}
+	for key, value := range progress.Data {
+		payload[key] = value
+	}
+	return []aguievents.Event{
+		aguievents.NewCustomEvent(progress.Name, aguievents.WithValue(payload)),
+	}
+}
+
 const (
 	graphNodeLifecycleActivityType = "graph.node.lifecycle"
 	graphNodePatchPath             = "/node"
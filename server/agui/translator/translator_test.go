@@ -25,11 +25,13 @@ import (
 	agentevent "trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/graph"
 	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/planner/planexecute"
 	trunner "trpc.group/trpc-go/trpc-agent-go/runner"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/internal/multimodal"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/internal/source"
 	"trpc.group/trpc-go/trpc-agent-go/server/agui/internal/steerext"
 	aguitool "trpc.group/trpc-go/trpc-agent-go/server/agui/internal/tool"
+	"trpc.group/trpc-go/trpc-agent-go/team"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
 )
 
@@ -4964,6 +4966,86 @@ func TestTranslate_ToolArtifactsFromStateDelta_NoResponse(t *testing.T) {
 	assert.Equal(t, "tool.artifacts", ce.Name)
 }
 
+func TestTranslate_PlanProgress(t *testing.T) {
+	tr := newTranslatorImplForTest(t)
+	if tr == nil {
+		return
+	}
+
+	plan := []byte(`{"goal":"g","status":"running","revision":1,"steps":[],"current":-1}`)
+	evt := agentevent.New("inv-1", "planner",
+		agentevent.WithObject(model.ObjectTypePlanProgress),
+		agentevent.WithStateDelta(map[string][]byte{
+			planexecute.StateKey("planner"): plan,
+			"other":                         []byte(`"x"`),
+		}),
+		agentevent.WithProgress(model.ObjectTypePlanProgress, map[string]any{
+			"agent": "planner",
+			"plan":  json.RawMessage(plan),
+		}),
+	)
+
+	events, err := tr.Translate(context.Background(), evt)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	ce, ok := events[0].(*aguievents.CustomEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "plan.progress", ce.Name)
+	value, ok := ce.Value.(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, json.RawMessage(`"planner"`), value["agent"])
+	assert.Equal(t, json.RawMessage(plan), value["plan"])
+	assert.Contains(t, value, "threadId")
+}
+
+func TestTranslate_TaskBoard(t *testing.T) {
+	tr := newTranslatorImplForTest(t)
+	if tr == nil {
+		return
+	}
+
+	board := []byte(`{"round":1,"tasks":[{"id":"t1","title":"a","status":"pending"}],"done":false}`)
+	evt := agentevent.New("inv-1", "lead",
+		agentevent.WithObject(model.ObjectTypeTaskBoard),
+		agentevent.WithStateDelta(map[string][]byte{
+			team.TaskBoardKey("lead"): board,
+		}),
+		agentevent.WithProgress(model.ObjectTypeTaskBoard, map[string]any{
+			"team":  "lead",
+			"board": json.RawMessage(board),
+		}),
+	)
+
+	events, err := tr.Translate(context.Background(), evt)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	ce, ok := events[0].(*aguievents.CustomEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "team.task_board", ce.Name)
+	value, ok := ce.Value.(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, json.RawMessage(`"lead"`), value["team"])
+	assert.Equal(t, json.RawMessage(board), value["board"])
+}
+
+func TestTranslate_StateDeltaWithoutProgress(t *testing.T) {
+	tr := newTranslatorImplForTest(t)
+	if tr == nil {
+		return
+	}
+
+	evt := agentevent.New("inv-1", "lead",
+		agentevent.WithStateDelta(map[string][]byte{
+			team.TaskBoardKey("lead"): []byte(`{"round":1}`),
+		}),
+	)
+	events, err := tr.Translate(context.Background(), evt)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
 func TestStreamToolResultEvent(t *testing.T) {
 	tr := newTranslatorImplForTest(t)
 	if tr == nil {
@@ -0,0 +1,108 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer "
+
+	defaultAPIKeyHeader = "X-API-Key"
+)
+
+// APIKey binds a static key to the principal it authenticates.
+type APIKey struct {
+	Key       string
+	Principal Principal
+}
+
+// APIKeyOption configures NewAPIKeyAuthenticator.
+type APIKeyOption func(*APIKeyAuthenticator)
+
+// WithAPIKeyHeader sets the header carrying the key. Default is
+// "X-API-Key". A bearer token in the Authorization header is accepted
+// as well.
+func WithAPIKeyHeader(name string) APIKeyOption {
+	return func(a *APIKeyAuthenticator) {
+		a.header = name
+	}
+}
+
+// APIKeyAuthenticator authenticates requests with static API keys.
+type APIKeyAuthenticator struct {
+	header string
+	// keys is indexed by the SHA-256 of the key, so lookups do not leak
+	// key prefixes through timing.
+	keys map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeyAuthenticator creates an authenticator for keys.
+func NewAPIKeyAuthenticator(
+	keys []APIKey,
+	opts ...APIKeyOption,
+) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{
+		header: defaultAPIKeyHeader,
+		keys:   make(map[[sha256.Size]byte]Principal, len(keys)),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for _, k := range keys {
+		if k.Key == "" {
+			return nil, errors.New("auth: api key is empty")
+		}
+		if k.Principal.ID == "" {
+			return nil, errors.New("auth: api key principal has no ID")
+		}
+		sum := sha256.Sum256([]byte(k.Key))
+		if _, ok := a.keys[sum]; ok {
+			return nil, fmt.Errorf("auth: duplicate api key for principal %q",
+				k.Principal.ID)
+		}
+		p := k.Principal
+		p.Method = MethodAPIKey
+		a.keys[sum] = p
+	}
+	return a, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := strings.TrimSpace(r.Header.Get(a.header)); key != "" {
+		p, ok := a.keys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+		}
+		return &p, nil
+	}
+	// Bearer tokens may belong to another authenticator in a Chain, such
+	// as a JWT, so unknown ones are not an error here.
+	if p, ok := a.keys[sha256.Sum256([]byte(bearerToken(r)))]; ok {
+		return &p, nil
+	}
+	return nil, ErrNoCredentials
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get(headerAuthorization)
+	if len(h) < len(bearerPrefix) ||
+		!strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(bearerPrefix):])
+}
@@ -0,0 +1,74 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "k1", Principal: Principal{ID: "alice", Apps: []string{"app"}}},
+		{Key: "k2", Principal: Principal{ID: "bob"}},
+	})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	_, err = a.Authenticate(r)
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	r.Header.Set(defaultAPIKeyHeader, "k1")
+	p, err := a.Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, "alice", p.ID)
+	require.Equal(t, MethodAPIKey, p.Method)
+	require.Equal(t, []string{"app"}, p.Apps)
+
+	r.Header.Set(defaultAPIKeyHeader, "nope")
+	_, err = a.Authenticate(r)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(headerAuthorization, "bearer k2")
+	p, err = a.Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, "bob", p.ID)
+
+	// Unknown bearer tokens are left to other authenticators.
+	r.Header.Set(headerAuthorization, "Bearer a.b.c")
+	_, err = a.Authenticate(r)
+	require.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestNewAPIKeyAuthenticator_Validation(t *testing.T) {
+	_, err := NewAPIKeyAuthenticator([]APIKey{{Principal: Principal{ID: "u"}}})
+	require.ErrorContains(t, err, "api key is empty")
+	_, err = NewAPIKeyAuthenticator([]APIKey{{Key: "k"}})
+	require.ErrorContains(t, err, "no ID")
+	_, err = NewAPIKeyAuthenticator([]APIKey{
+		{Key: "k", Principal: Principal{ID: "a"}},
+		{Key: "k", Principal: Principal{ID: "b"}},
+	})
+	require.ErrorContains(t, err, "duplicate api key")
+
+	a, err := NewAPIKeyAuthenticator(
+		[]APIKey{{Key: "k", Principal: Principal{ID: "u"}}},
+		WithAPIKeyHeader("X-Key"),
+	)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("X-Key", "k")
+	_, err = a.Authenticate(r)
+	require.NoError(t, err)
+}
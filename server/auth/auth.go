@@ -0,0 +1,149 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package auth provides pluggable request authentication for the HTTP
+// servers in this repository.
+//
+// An Authenticator turns a request into a Principal using a static API key,
+// an HMAC signature or a JWT verified against a JWKS. Servers accept an
+// Authenticator through their WithAuthenticator option; they then reject
+// unauthenticated requests, use Principal.ID as the session UserID and
+// enforce the app and agent allowlists of the principal.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Authentication methods reported in Principal.Method.
+const (
+	MethodAPIKey = "api_key"
+	MethodHMAC   = "hmac"
+	MethodJWT    = "jwt"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request
+	// carries no credentials it understands. Chain tries the next
+	// authenticator on this error.
+	ErrNoCredentials = errors.New("auth: no credentials")
+
+	// ErrInvalidCredentials is returned when credentials are present but
+	// cannot be verified.
+	ErrInvalidCredentials = errors.New("auth: invalid credentials")
+
+	// ErrForbidden is returned when an authenticated principal may not use
+	// the requested app or agent.
+	ErrForbidden = errors.New("auth: forbidden")
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// ID identifies the caller. Servers use it as the session UserID.
+	ID string
+
+	// Method is the authentication method that produced the principal.
+	Method string
+
+	// Apps lists the apps the principal may use. Nil or "*" allows every
+	// app; an empty non-nil list allows none.
+	Apps []string
+
+	// Agents lists the agents the principal may use, like Apps.
+	Agents []string
+
+	// Claims holds the verified claims of JWT principals.
+	Claims map[string]any
+}
+
+// Resource is the app and agent a request targets. Empty fields are not
+// checked.
+type Resource struct {
+	App   string
+	Agent string
+}
+
+// Authorize checks that p may use res.
+func (p *Principal) Authorize(res Resource) error {
+	if res.App != "" && !allowed(p.Apps, res.App) {
+		return fmt.Errorf("%w: principal %q may not use app %q",
+			ErrForbidden, p.ID, res.App)
+	}
+	if res.Agent != "" && !allowed(p.Agents, res.Agent) {
+		return fmt.Errorf("%w: principal %q may not use agent %q",
+			ErrForbidden, p.ID, res.Agent)
+	}
+	return nil
+}
+
+func allowed(list []string, name string) bool {
+	return list == nil || slices.Contains(list, "*") ||
+		slices.Contains(list, name)
+}
+
+// Authenticator authenticates HTTP requests.
+//
+// Authenticate returns ErrNoCredentials when the request carries no
+// credentials for this authenticator, and an error wrapping
+// ErrInvalidCredentials when they cannot be verified.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// Chain returns an Authenticator that tries authenticators in order. The
+// first one that finds credentials decides the result.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		for _, a := range authenticators {
+			p, err := a.Authenticate(r)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return p, err
+		}
+		return nil, ErrNoCredentials
+	})
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// UserID returns the ID of the principal in ctx, or fallback when the
+// request was not authenticated.
+func UserID(ctx context.Context, fallback string) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.ID
+	}
+	return fallback
+}
@@ -0,0 +1,138 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipal_Authorize(t *testing.T) {
+	open := &Principal{ID: "u"}
+	require.NoError(t, open.Authorize(Resource{App: "app", Agent: "agent"}))
+
+	p := &Principal{ID: "u", Apps: []string{"app"}, Agents: []string{"*"}}
+	require.NoError(t, p.Authorize(Resource{App: "app", Agent: "any"}))
+	require.NoError(t, p.Authorize(Resource{}))
+	err := p.Authorize(Resource{App: "other"})
+	require.ErrorIs(t, err, ErrForbidden)
+	require.ErrorContains(t, err, `may not use app "other"`)
+
+	none := &Principal{ID: "u", Agents: []string{}}
+	require.ErrorIs(t, none.Authorize(Resource{Agent: "agent"}), ErrForbidden)
+}
+
+func TestChain(t *testing.T) {
+	none := AuthenticatorFunc(func(*http.Request) (*Principal, error) {
+		return nil, ErrNoCredentials
+	})
+	bad := AuthenticatorFunc(func(*http.Request) (*Principal, error) {
+		return nil, ErrInvalidCredentials
+	})
+	good := AuthenticatorFunc(func(*http.Request) (*Principal, error) {
+		return &Principal{ID: "u"}, nil
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	p, err := Chain(none, good, bad).Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, "u", p.ID)
+	_, err = Chain(none, bad, good).Authenticate(r)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = Chain(none).Authenticate(r)
+	require.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := FromContext(ctx)
+	require.False(t, ok)
+	require.Equal(t, "anon", UserID(ctx, "anon"))
+
+	ctx = NewContext(ctx, &Principal{ID: "u"})
+	p, ok := FromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "u", p.ID)
+	require.Equal(t, "u", UserID(ctx, "anon"))
+}
+
+func TestMiddleware(t *testing.T) {
+	a := AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		switch r.Header.Get("X-Test") {
+		case "":
+			return nil, ErrNoCredentials
+		case "nobody":
+			return &Principal{}, nil
+		case "bad":
+			return nil, errors.Join(ErrInvalidCredentials, errors.New("nope"))
+		}
+		return &Principal{ID: r.Header.Get("X-Test"), Apps: []string{"app"}}, nil
+	})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(UserID(r.Context(), "anon")))
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		header    string
+		preflight bool
+		res       Resource
+		code      int
+		body      string
+	}{
+		{name: "ok", header: "u", code: http.StatusOK, body: "u"},
+		{name: "missing", code: http.StatusUnauthorized},
+		{name: "invalid", header: "bad", code: http.StatusUnauthorized},
+		{name: "no id", header: "nobody", code: http.StatusUnauthorized},
+		{name: "forbidden", header: "u", res: Resource{App: "other"},
+			code: http.StatusForbidden},
+		{name: "preflight", method: http.MethodOptions, preflight: true,
+			code: http.StatusOK, body: "anon"},
+		{name: "options", method: http.MethodOptions,
+			code: http.StatusUnauthorized},
+		{name: "public", path: "/health", code: http.StatusOK, body: "anon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, path := tt.method, tt.path
+			if method == "" {
+				method = http.MethodPost
+			}
+			if path == "" {
+				path = "/run"
+			}
+			r := httptest.NewRequest(method, path, nil)
+			if tt.header != "" {
+				r.Header.Set("X-Test", tt.header)
+			}
+			if tt.preflight {
+				r.Header.Set("Origin", "https://example.com")
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			Middleware(a, WithResource(tt.res), WithPublicPaths("/health"))(next).
+				ServeHTTP(w, r)
+			require.Equal(t, tt.code, w.Code)
+			if tt.body != "" {
+				require.Equal(t, tt.body, w.Body.String())
+			}
+			if tt.code == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", w.Header().Get(headerWWWAuthenticate))
+			}
+		})
+	}
+}
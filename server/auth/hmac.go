@@ -0,0 +1,187 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of HMAC-signed requests.
+const (
+	HeaderHMACKeyID     = "X-Auth-Key-Id"
+	HeaderHMACTimestamp = "X-Auth-Timestamp"
+	HeaderHMACSignature = "X-Auth-Signature"
+)
+
+const (
+	defaultHMACMaxSkew = 5 * time.Minute
+	// maxHMACBodyBytes bounds the body read to verify a signature.
+	maxHMACBodyBytes = 32 << 20
+)
+
+// HMACKey binds a shared secret to the principal it authenticates.
+type HMACKey struct {
+	ID        string
+	Secret    []byte
+	Principal Principal
+}
+
+// HMACOption configures NewHMACAuthenticator.
+type HMACOption func(*HMACAuthenticator)
+
+// WithHMACMaxSkew sets how far the request timestamp may be from the
+// server clock. Default is 5 minutes.
+func WithHMACMaxSkew(d time.Duration) HMACOption {
+	return func(a *HMACAuthenticator) {
+		a.maxSkew = d
+	}
+}
+
+// HMACAuthenticator authenticates requests signed with a shared secret.
+//
+// Clients send the key ID in X-Auth-Key-Id, the Unix time in seconds in
+// X-Auth-Timestamp and the hex HMAC-SHA256 of the string to sign in
+// X-Auth-Signature. The string to sign is
+//
+//	METHOD "\n" PATH ["?" QUERY] "\n" TIMESTAMP "\n" hex(SHA256(body))
+//
+// SignRequest produces these headers. Timestamps outside the allowed skew
+// are rejected, which bounds how long a captured request can be replayed.
+type HMACAuthenticator struct {
+	maxSkew time.Duration
+	keys    map[string]HMACKey
+	now     func() time.Time
+}
+
+// NewHMACAuthenticator creates an authenticator for keys.
+func NewHMACAuthenticator(
+	keys []HMACKey,
+	opts ...HMACOption,
+) (*HMACAuthenticator, error) {
+	a := &HMACAuthenticator{
+		maxSkew: defaultHMACMaxSkew,
+		keys:    make(map[string]HMACKey, len(keys)),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for _, k := range keys {
+		if k.ID == "" || len(k.Secret) == 0 {
+			return nil, errors.New("auth: hmac key needs an ID and a secret")
+		}
+		if k.Principal.ID == "" {
+			return nil, fmt.Errorf("auth: hmac key %q principal has no ID", k.ID)
+		}
+		if _, ok := a.keys[k.ID]; ok {
+			return nil, fmt.Errorf("auth: duplicate hmac key %q", k.ID)
+		}
+		k.Principal.Method = MethodHMAC
+		a.keys[k.ID] = k
+	}
+	return a, nil
+}
+
+// Authenticate implements Authenticator. It reads the request body and
+// restores it for the next handler.
+func (a *HMACAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	keyID := r.Header.Get(HeaderHMACKeyID)
+	sig := r.Header.Get(HeaderHMACSignature)
+	if keyID == "" && sig == "" {
+		return nil, ErrNoCredentials
+	}
+	k, ok := a.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hmac key %q", ErrInvalidCredentials, keyID)
+	}
+	ts := r.Header.Get(HeaderHMACTimestamp)
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad hmac timestamp", ErrInvalidCredentials)
+	}
+	if skew := a.now().Sub(time.Unix(unix, 0)).Abs(); skew > a.maxSkew {
+		return nil, fmt.Errorf("%w: hmac timestamp outside allowed skew",
+			ErrInvalidCredentials)
+	}
+	want, err := hex.DecodeString(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad hmac signature", ErrInvalidCredentials)
+	}
+	got, err := signature(r, ts, k.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if !hmac.Equal(got, want) {
+		return nil, fmt.Errorf("%w: hmac signature mismatch", ErrInvalidCredentials)
+	}
+	p := k.Principal
+	return &p, nil
+}
+
+// SignRequest signs r for an HMACAuthenticator with the current time.
+func SignRequest(r *http.Request, keyID string, secret []byte) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	sig, err := signature(r, ts, secret)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(HeaderHMACKeyID, keyID)
+	r.Header.Set(HeaderHMACTimestamp, ts)
+	r.Header.Set(HeaderHMACSignature, hex.EncodeToString(sig))
+	return nil
+}
+
+func signature(r *http.Request, ts string, secret []byte) ([]byte, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	bodySum := sha256.Sum256(body)
+	target := r.URL.EscapedPath()
+	if target == "" {
+		// Clients send "/" for an empty path.
+		target = "/"
+	}
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join([]string{
+		r.Method, target, ts, hex.EncodeToString(bodySum[:]),
+	}, "\n")))
+	return mac.Sum(nil), nil
+}
+
+// readBody reads the body of r and replaces it with a fresh reader.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxHMACBodyBytes+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if len(body) > maxHMACBodyBytes {
+		return nil, errors.New("body too large to verify")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,82 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACAuthenticator(t *testing.T) {
+	secret := []byte("s3cret")
+	a, err := NewHMACAuthenticator([]HMACKey{
+		{ID: "svc", Secret: secret, Principal: Principal{ID: "service"}},
+	})
+	require.NoError(t, err)
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/run?x=1",
+			strings.NewReader(`{"q":"hi"}`))
+		require.NoError(t, SignRequest(r, "svc", secret))
+		return r
+	}
+
+	r := newRequest()
+	p, err := a.Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, "service", p.ID)
+	require.Equal(t, MethodHMAC, p.Method)
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"q":"hi"}`, string(body), "body is restored")
+
+	_, err = a.Authenticate(httptest.NewRequest(http.MethodPost, "/", nil))
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	tampered := newRequest()
+	tampered.URL.RawQuery = "x=2"
+	_, err = a.Authenticate(tampered)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	require.ErrorContains(t, err, "signature mismatch")
+
+	unknown := newRequest()
+	unknown.Header.Set(HeaderHMACKeyID, "other")
+	_, err = a.Authenticate(unknown)
+	require.ErrorContains(t, err, `unknown hmac key "other"`)
+
+	// A client signing a URL without a path matches the "/" the server sees.
+	client := httptest.NewRequest(http.MethodGet, "/", nil)
+	client.URL.Path = ""
+	require.NoError(t, SignRequest(client, "svc", secret))
+	server := httptest.NewRequest(http.MethodGet, "/", nil)
+	server.Header = client.Header
+	_, err = a.Authenticate(server)
+	require.NoError(t, err)
+
+	a.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	_, err = a.Authenticate(newRequest())
+	require.ErrorContains(t, err, "outside allowed skew")
+}
+
+func TestNewHMACAuthenticator_Validation(t *testing.T) {
+	_, err := NewHMACAuthenticator([]HMACKey{{ID: "k"}})
+	require.ErrorContains(t, err, "needs an ID and a secret")
+	_, err = NewHMACAuthenticator([]HMACKey{{ID: "k", Secret: []byte("s")}})
+	require.ErrorContains(t, err, "no ID")
+	key := HMACKey{ID: "k", Secret: []byte("s"), Principal: Principal{ID: "u"}}
+	_, err = NewHMACAuthenticator([]HMACKey{key, key})
+	require.ErrorContains(t, err, "duplicate hmac key")
+}
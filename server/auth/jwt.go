@@ -0,0 +1,364 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"golang.org/x/sync/singleflight"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+const (
+	defaultJWKSCacheTTL   = 15 * time.Minute
+	defaultJWTClockSkew   = time.Minute
+	defaultJWTUserIDClaim = "sub"
+	// minJWKSRefresh bounds how often an unknown signing key or a failed
+	// fetch triggers a JWKS refetch, so bad tokens or a key server that is
+	// down cannot make every request wait on the key server.
+	minJWKSRefresh = 30 * time.Second
+	// jwksFetchTimeout bounds each request to the key server.
+	jwksFetchTimeout = 10 * time.Second
+	maxJWKSBytes     = 1 << 20
+
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+)
+
+// JWTOption configures NewJWTAuthenticator.
+type JWTOption func(*JWTAuthenticator)
+
+// WithJWKS sets a static JWKS document to verify tokens with.
+func WithJWKS(raw []byte) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.staticJWKS = raw
+	}
+}
+
+// WithJWKSURL fetches the verification keys from url. Keys are cached for
+// the cache TTL and refetched early when a token names an unknown key.
+func WithJWKSURL(url string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.jwksURL = url
+	}
+}
+
+// WithJWKSCacheTTL sets how long fetched keys are used before they are
+// refetched. Default is 15 minutes. When a refetch fails, the cached keys
+// stay in use.
+func WithJWKSCacheTTL(d time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.cacheTTL = d
+	}
+}
+
+// WithIssuer requires the "iss" claim to equal issuer. Without a JWKS or
+// JWKS URL, the keys are discovered from the OIDC configuration of the
+// issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.issuer = issuer
+	}
+}
+
+// WithAudience requires the "aud" claim to contain audience.
+func WithAudience(audience string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.audience = audience
+	}
+}
+
+// WithUserIDClaim sets the claim used as Principal.ID. Default is "sub".
+func WithUserIDClaim(name string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.userIDClaim = name
+	}
+}
+
+// WithAllowlistClaims sets the claims holding the app and agent allowlists
+// of the principal, as a space separated string or a list of strings.
+// Tokens without a configured claim may use no app or agent. Empty names
+// leave the corresponding allowlist open.
+func WithAllowlistClaims(apps, agents string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.appsClaim = apps
+		a.agentsClaim = agents
+	}
+}
+
+// WithClockSkew sets the tolerance for the "exp", "nbf" and "iat" claims.
+// Default is one minute.
+func WithClockSkew(d time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.clockSkew = d
+	}
+}
+
+// WithHTTPClient sets the client used to fetch keys. Default is a client
+// with a 10 second timeout, which also bounds requests made by c.
+func WithHTTPClient(c *http.Client) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.client = c
+	}
+}
+
+// JWTAuthenticator authenticates requests with bearer JWTs verified
+// against a JWKS.
+type JWTAuthenticator struct {
+	staticJWKS  []byte
+	jwksURL     string
+	cacheTTL    time.Duration
+	issuer      string
+	audience    string
+	userIDClaim string
+	appsClaim   string
+	agentsClaim string
+	clockSkew   time.Duration
+	client      *http.Client
+
+	fetches   singleflight.Group
+	mu        sync.Mutex
+	keys      jwk.Set
+	fetchedAt time.Time // last successful fetch
+	attempted time.Time // last fetch, successful or not
+	fetchErr  error     // error of the last fetch
+}
+
+// NewJWTAuthenticator creates a JWT authenticator. One of WithJWKS,
+// WithJWKSURL or WithIssuer must provide the keys.
+func NewJWTAuthenticator(opts ...JWTOption) (*JWTAuthenticator, error) {
+	a := &JWTAuthenticator{
+		cacheTTL:    defaultJWKSCacheTTL,
+		userIDClaim: defaultJWTUserIDClaim,
+		clockSkew:   defaultJWTClockSkew,
+		client:      &http.Client{Timeout: jwksFetchTimeout},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	switch {
+	case a.staticJWKS != nil:
+		keys, err := jwk.Parse(a.staticJWKS)
+		if err != nil {
+			return nil, fmt.Errorf("auth: parse jwks: %w", err)
+		}
+		a.keys = keys
+	case a.jwksURL == "" && a.issuer == "":
+		return nil, errors.New("auth: jwt authenticator needs a jwks, jwks url or issuer")
+	}
+	return a, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	raw := bearerToken(r)
+	// Only compact JWS tokens are ours; other bearer tokens may belong to
+	// another authenticator in a Chain.
+	if strings.Count(raw, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+	ctx := r.Context()
+	keys, err := a.keySet(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	tok, err := a.parse(raw, keys)
+	if err != nil && a.staticJWKS == nil && errors.Is(err, errJWTKeyNotFound) {
+		if keys, ferr := a.keySet(ctx, true); ferr == nil {
+			tok, err = a.parse(raw, keys)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	claims, err := tok.AsMap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	id, _ := claims[a.userIDClaim].(string)
+	if id == "" {
+		return nil, fmt.Errorf("%w: claim %q is missing",
+			ErrInvalidCredentials, a.userIDClaim)
+	}
+	return &Principal{
+		ID:     id,
+		Method: MethodJWT,
+		Apps:   stringsClaim(claims, a.appsClaim),
+		Agents: stringsClaim(claims, a.agentsClaim),
+		Claims: claims,
+	}, nil
+}
+
+var errJWTKeyNotFound = errors.New("signing key not found")
+
+func (a *JWTAuthenticator) parse(raw string, keys jwk.Set) (jwt.Token, error) {
+	opts := []jwt.ParseOption{
+		jwt.WithKeySet(keys,
+			jws.WithInferAlgorithmFromKey(true),
+			jws.WithRequireKid(false)),
+		jwt.WithValidate(true),
+		jwt.WithAcceptableSkew(a.clockSkew),
+	}
+	if a.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		opts = append(opts, jwt.WithAudience(a.audience))
+	}
+	tok, err := jwt.ParseString(raw, opts...)
+	if err != nil && !jwt.IsValidationError(err) && !hasKey(raw, keys) {
+		return nil, fmt.Errorf("%w: %v", errJWTKeyNotFound, err)
+	}
+	return tok, err
+}
+
+// hasKey reports whether keys holds the key named by the "kid" header of
+// the token. Tokens without "kid" are checked against every key.
+func hasKey(raw string, keys jwk.Set) bool {
+	msg, err := jws.ParseString(raw)
+	if err != nil || len(msg.Signatures()) == 0 {
+		return true
+	}
+	kid := msg.Signatures()[0].ProtectedHeaders().KeyID()
+	if kid == "" {
+		return true
+	}
+	_, ok := keys.LookupKeyID(kid)
+	return ok
+}
+
+// keySet returns the cached keys, fetching them when they are missing or
+// expired, or when force is set. Fetches are shared by concurrent callers
+// and happen at most once per minJWKSRefresh; a failed fetch keeps the
+// cached keys in use.
+func (a *JWTAuthenticator) keySet(ctx context.Context, force bool) (jwk.Set, error) {
+	if a.staticJWKS != nil {
+		return a.keys, nil
+	}
+	a.mu.Lock()
+	keys, fetchedAt, attempted, fetchErr := a.keys, a.fetchedAt, a.attempted, a.fetchErr
+	a.mu.Unlock()
+	if keys != nil && time.Since(fetchedAt) < a.cacheTTL && !force {
+		return keys, nil
+	}
+	if time.Since(attempted) < minJWKSRefresh {
+		if keys != nil {
+			return keys, nil
+		}
+		return nil, fetchErr
+	}
+	v, err, _ := a.fetches.Do("jwks", func() (any, error) {
+		a.mu.Lock()
+		if !a.attempted.Equal(attempted) {
+			// Another fetch finished since the cache was checked.
+			keys, err := a.keys, a.fetchErr
+			a.mu.Unlock()
+			if err != nil {
+				return nil, err
+			}
+			return keys, nil
+		}
+		a.mu.Unlock()
+		keys, err := a.fetch(ctx)
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.attempted, a.fetchErr = time.Now(), err
+		if err != nil {
+			return nil, err
+		}
+		a.keys, a.fetchedAt = keys, a.attempted
+		return keys, nil
+	})
+	if err != nil {
+		if keys != nil {
+			log.WarnfContext(ctx, "auth: refresh jwks, using cached keys: %v", err)
+			return keys, nil
+		}
+		return nil, err
+	}
+	return v.(jwk.Set), nil
+}
+
+func (a *JWTAuthenticator) fetch(ctx context.Context) (jwk.Set, error) {
+	url := a.jwksURL
+	if url == "" {
+		var cfg struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		raw, err := a.get(ctx, strings.TrimRight(a.issuer, "/")+oidcDiscoveryPath)
+		if err != nil {
+			return nil, fmt.Errorf("discover oidc configuration: %w", err)
+		}
+		if err := json.Unmarshal(raw, &cfg); err != nil || cfg.JWKSURI == "" {
+			return nil, errors.New("oidc configuration has no jwks_uri")
+		}
+		url = cfg.JWKSURI
+	}
+	raw, err := a.get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys, err := jwk.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	return keys, nil
+}
+
+func (a *JWTAuthenticator) get(ctx context.Context, url string) ([]byte, error) {
+	// Fetches outlive the request that triggered them, since their result
+	// is shared by later requests.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+}
+
+func stringsClaim(claims map[string]any, name string) []string {
+	if name == "" {
+		return nil
+	}
+	out := []string{}
+	switch v := claims[name].(type) {
+	case string:
+		out = append(out, strings.Fields(v)...)
+	case []string:
+		out = append(out, v...)
+	case []any:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigner struct {
+	key jwk.Key
+	alg jwa.SignatureAlgorithm
+}
+
+func newRSASigner(t *testing.T, kid string) testSigner {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, kid))
+	return testSigner{key: key, alg: jwa.RS256}
+}
+
+func newECSigner(t *testing.T, kid string) testSigner {
+	raw, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, kid))
+	return testSigner{key: key, alg: jwa.ES256}
+}
+
+func (s testSigner) token(t *testing.T, claims map[string]any) string {
+	tok := jwt.New()
+	require.NoError(t, tok.Set(jwt.ExpirationKey, time.Now().Add(time.Hour)))
+	for k, v := range claims {
+		require.NoError(t, tok.Set(k, v))
+	}
+	raw, err := jwt.Sign(tok, jwt.WithKey(s.alg, s.key))
+	require.NoError(t, err)
+	return string(raw)
+}
+
+func jwksOf(t *testing.T, signers ...testSigner) []byte {
+	set := jwk.NewSet()
+	for _, s := range signers {
+		pub, err := s.key.PublicKey()
+		require.NoError(t, err)
+		require.NoError(t, set.AddKey(pub))
+	}
+	raw, err := json.Marshal(set)
+	require.NoError(t, err)
+	return raw
+}
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(headerAuthorization, bearerPrefix+token)
+	return r
+}
+
+func TestJWTAuthenticator_StaticJWKS(t *testing.T) {
+	rs, es := newRSASigner(t, "rs"), newECSigner(t, "es")
+	a, err := NewJWTAuthenticator(
+		WithJWKS(jwksOf(t, rs, es)),
+		WithIssuer("https://issuer"),
+		WithAudience("agents"),
+		WithAllowlistClaims("apps", "agents"),
+	)
+	require.NoError(t, err)
+	valid := map[string]any{
+		jwt.SubjectKey:  "alice",
+		jwt.IssuerKey:   "https://issuer",
+		jwt.AudienceKey: []string{"agents"},
+		"apps":          []string{"app"},
+		"agents":        "a b",
+	}
+
+	p, err := a.Authenticate(bearerRequest(rs.token(t, valid)))
+	require.NoError(t, err)
+	require.Equal(t, "alice", p.ID)
+	require.Equal(t, MethodJWT, p.Method)
+	require.Equal(t, []string{"app"}, p.Apps)
+	require.Equal(t, []string{"a", "b"}, p.Agents)
+	require.Equal(t, "https://issuer", p.Claims[jwt.IssuerKey])
+
+	p, err = a.Authenticate(bearerRequest(es.token(t, map[string]any{
+		jwt.SubjectKey:  "bob",
+		jwt.IssuerKey:   "https://issuer",
+		jwt.AudienceKey: []string{"agents"},
+	})))
+	require.NoError(t, err)
+	require.Equal(t, "bob", p.ID)
+	require.NotNil(t, p.Apps)
+	require.Empty(t, p.Apps, "missing allowlist claims allow nothing")
+
+	for name, claims := range map[string]map[string]any{
+		"wrong audience": {jwt.SubjectKey: "a", jwt.IssuerKey: "https://issuer",
+			jwt.AudienceKey: []string{"other"}},
+		"wrong issuer": {jwt.SubjectKey: "a", jwt.IssuerKey: "https://evil",
+			jwt.AudienceKey: []string{"agents"}},
+		"expired": {jwt.SubjectKey: "a", jwt.IssuerKey: "https://issuer",
+			jwt.AudienceKey:   []string{"agents"},
+			jwt.ExpirationKey: time.Now().Add(-time.Hour)},
+		"no subject": {jwt.IssuerKey: "https://issuer",
+			jwt.AudienceKey: []string{"agents"}},
+	} {
+		_, err := a.Authenticate(bearerRequest(rs.token(t, claims)))
+		require.ErrorIs(t, err, ErrInvalidCredentials, name)
+	}
+
+	other := newRSASigner(t, "rs")
+	_, err = a.Authenticate(bearerRequest(other.token(t, valid)))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = a.Authenticate(bearerRequest("opaque-api-key"))
+	require.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestJWTAuthenticator_DiscoversAndCachesJWKS(t *testing.T) {
+	first, second := newRSASigner(t, "k1"), newRSASigner(t, "k2")
+	var jwks atomic.Value
+	jwks.Store(jwksOf(t, first))
+	var fetches, fail atomic.Int32
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		if fail.Load() != 0 {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(jwks.Load().([]byte))
+	})
+
+	a, err := NewJWTAuthenticator(WithIssuer(srv.URL), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	claims := map[string]any{jwt.SubjectKey: "u", jwt.IssuerKey: srv.URL}
+
+	for i := 0; i < 3; i++ {
+		_, err = a.Authenticate(bearerRequest(first.token(t, claims)))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, fetches.Load())
+
+	// A rotated key is picked up by refetching, at most once per
+	// minJWKSRefresh.
+	jwks.Store(jwksOf(t, first, second))
+	_, err = a.Authenticate(bearerRequest(second.token(t, claims)))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	age(a, minJWKSRefresh)
+	_, err = a.Authenticate(bearerRequest(second.token(t, claims)))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, fetches.Load())
+
+	// Cached keys stay in use while the key server is down, and a failed
+	// fetch is not retried before minJWKSRefresh.
+	fail.Store(1)
+	age(a, defaultJWKSCacheTTL)
+	for i := 0; i < 3; i++ {
+		_, err = a.Authenticate(bearerRequest(first.token(t, claims)))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 3, fetches.Load())
+	age(a, minJWKSRefresh)
+	fail.Store(0)
+	_, err = a.Authenticate(bearerRequest(first.token(t, claims)))
+	require.NoError(t, err)
+	require.EqualValues(t, 4, fetches.Load())
+}
+
+// age moves the fetches of a back by d.
+func age(a *JWTAuthenticator, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.fetchedAt = a.fetchedAt.Add(-d)
+	a.attempted = a.attempted.Add(-d)
+}
+
+func TestJWTAuthenticator_SharesAndBacksOffFetches(t *testing.T) {
+	signer := newRSASigner(t, "k1")
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) > 1 {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+		w.Write(jwksOf(t, signer))
+	}))
+	defer srv.Close()
+	a, err := NewJWTAuthenticator(WithJWKSURL(srv.URL), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	tok := signer.token(t, map[string]any{jwt.SubjectKey: "u"})
+
+	// Concurrent requests wait on one fetch.
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = a.Authenticate(bearerRequest(tok))
+		}(i)
+	}
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, fetches.Load())
+
+	// Without cached keys, a failed fetch fails the requests that follow
+	// it until minJWKSRefresh has passed.
+	a.keys = nil
+	age(a, defaultJWKSCacheTTL)
+	for i := 0; i < 3; i++ {
+		_, err = a.Authenticate(bearerRequest(tok))
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+		require.ErrorContains(t, err, "503")
+	}
+	require.EqualValues(t, 2, fetches.Load())
+}
+
+func TestNewJWTAuthenticator_Validation(t *testing.T) {
+	_, err := NewJWTAuthenticator()
+	require.ErrorContains(t, err, "needs a jwks")
+	_, err = NewJWTAuthenticator(WithJWKS([]byte("{")))
+	require.ErrorContains(t, err, "parse jwks")
+}
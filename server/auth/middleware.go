@@ -0,0 +1,120 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+const headerWWWAuthenticate = "WWW-Authenticate"
+
+// MiddlewareOption configures Middleware.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	resource    Resource
+	publicPaths []string
+}
+
+// WithResource sets the app and agent checked against the allowlists of
+// every principal.
+func WithResource(res Resource) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.resource = res
+	}
+}
+
+// WithPublicPaths lets requests to the given exact paths through without
+// authentication, e.g. health checks or discovery documents.
+func WithPublicPaths(paths ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.publicPaths = append(o.publicPaths, paths...)
+	}
+}
+
+// Middleware returns HTTP middleware that authenticates every request with
+// a, authorizes the principal for the configured resource and stores it in
+// the request context, where FromContext finds it.
+//
+// Unauthenticated requests get 401 and unauthorized ones 403. CORS
+// preflight requests pass through, since browsers send them without
+// credentials. Other OPTIONS requests are authenticated like any request.
+func Middleware(
+	a Authenticator,
+	opts ...MiddlewareOption,
+) func(http.Handler) http.Handler {
+	var o middlewareOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPreflight(r) || slices.Contains(o.publicPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			p, err := Authenticate(a, r, o.resource)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+		})
+	}
+}
+
+// isPreflight reports whether r is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// Authenticate authenticates r with a and authorizes the principal for
+// res. It is the building block of Middleware for servers that plug into
+// other authentication hooks.
+func Authenticate(a Authenticator, r *http.Request, res Resource) (*Principal, error) {
+	p, err := a.Authenticate(r)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.ID == "" {
+		return nil, errors.Join(ErrInvalidCredentials,
+			errors.New("principal has no ID"))
+	}
+	if err := p.Authorize(res); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// StatusCode maps an error returned by Authenticate to an HTTP status.
+func StatusCode(err error) int {
+	if errors.Is(err, ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := StatusCode(err)
+	log.DebugfContext(r.Context(), "auth: %s %s rejected: %v",
+		r.Method, r.URL.Path, err)
+	if status == http.StatusUnauthorized {
+		w.Header().Set(headerWWWAuthenticate, "Bearer")
+		http.Error(w, "unauthorized", status)
+		return
+	}
+	http.Error(w, "forbidden", status)
+}
@@ -16,6 +16,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/evalresult"
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/evalset"
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/metric"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 )
 
 const (
@@ -42,6 +43,7 @@ type options struct {
 	metricManager     metric.Manager
 	evalResultManager evalresult.Manager
 	routeRegistrars   []RouteRegistrar
+	authenticator     auth.Authenticator
 }
 
 func newOptions(opt ...Option) *options {
@@ -144,3 +146,11 @@ func WithRouteRegistrar(registrar RouteRegistrar) Option {
 		opts.routeRegistrars = append(opts.routeRegistrars, registrar)
 	}
 }
+
+// WithAuthenticator requires every request, including extra routes, to
+// authenticate with a. The principal must be allowed to use the app.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(opts *options) {
+		opts.authenticator = a
+	}
+}
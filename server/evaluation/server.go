@@ -26,6 +26,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/evalset"
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/metric"
 	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 )
 
 const (
@@ -52,6 +53,7 @@ type Server struct {
 	metricManager     metric.Manager
 	evalResultManager evalresult.Manager
 	routeRegistrars   []RouteRegistrar
+	authenticator     auth.Authenticator
 	handler           http.Handler
 }
 
@@ -94,6 +96,7 @@ func New(opts ...Option) (*Server, error) {
 		metricManager:     options.metricManager,
 		evalResultManager: options.evalResultManager,
 		routeRegistrars:   append([]RouteRegistrar(nil), options.routeRegistrars...),
+		authenticator:     options.authenticator,
 	}
 	if err := server.setupHandler(); err != nil {
 		return nil, err
@@ -168,6 +171,10 @@ func (s *Server) setupHandler() error {
 		}
 	}
 	s.handler = mux
+	if s.authenticator != nil {
+		s.handler = auth.Middleware(s.authenticator,
+			auth.WithResource(auth.Resource{App: s.appName}))(mux)
+	}
 	return nil
 }
 
@@ -28,6 +28,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/metric"
 	"trpc.group/trpc-go/trpc-agent-go/evaluation/status"
 	agentlog "trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 )
 
 type fakeAgentEvaluator struct {
@@ -1547,3 +1548,25 @@ func TestApplyMetricNameFromPath(t *testing.T) {
 	require.NoError(t, applyMetricNameFromPath(&metric.EvalMetric{MetricName: "accuracy"}, "accuracy"))
 	require.EqualError(t, applyMetricNameFromPath(&metric.EvalMetric{MetricName: "groundedness"}, "accuracy"), "metric.metricName must match path metricName when provided")
 }
+
+func TestAuthenticatorProtectsRoutes(t *testing.T) {
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Key: "k1", Principal: auth.Principal{ID: "alice", Apps: []string{"demo-app"}}},
+		{Key: "k2", Principal: auth.Principal{ID: "bob", Apps: []string{"other"}}},
+	})
+	require.NoError(t, err)
+	srv := newTestServer(t, WithAuthenticator(keys))
+
+	call := func(key string) int {
+		request := httptest.NewRequest(http.MethodGet, srv.SetsPath(), nil)
+		if key != "" {
+			request.Header.Set("X-API-Key", key)
+		}
+		recorder := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusForbidden, call("k2"))
+	assert.Equal(t, http.StatusOK, call("k1"))
+}
@@ -12,6 +12,7 @@ package openai
 import (
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 )
 
@@ -27,6 +28,7 @@ type options struct {
 	runner         runner.Runner
 	modelName      string
 	appName        string
+	authenticator  auth.Authenticator
 }
 
 // WithBasePath sets the base path for the server.
@@ -84,3 +86,12 @@ func WithAppName(name string) Option {
 		opts.appName = name
 	}
 }
+
+// WithAuthenticator requires every request to authenticate with a. The
+// principal ID replaces the "user" field of the request as the session
+// user ID, and the principal must be allowed to use the app and agent.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(opts *options) {
+		opts.authenticator = a
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/log"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session"
 	"trpc.group/trpc-go/trpc-agent-go/session/inmemory"
 )
@@ -66,6 +67,8 @@ type Server struct {
 	agent          agent.Agent
 	modelName      string
 	converter      *converter
+	appName        string
+	authenticator  auth.Authenticator
 	ownedRunner    bool // Indicates if runner was created by this server.
 	closeOnce      sync.Once
 }
@@ -110,6 +113,8 @@ func New(opts ...Option) (*Server, error) {
 		agent:          options.agent,
 		modelName:      options.modelName,
 		converter:      conv,
+		appName:        options.appName,
+		authenticator:  options.authenticator,
 		ownedRunner:    ownedRunner,
 	}
 	s.setupHandler()
@@ -156,6 +161,13 @@ func (s *Server) setupHandler() {
 	mux.HandleFunc(s.path, s.handleChatCompletions)
 	mux.HandleFunc(s.path+"/", s.handleChatCompletions)
 	s.handler = mux
+	if s.authenticator != nil {
+		res := auth.Resource{App: s.appName}
+		if s.agent != nil {
+			res.Agent = s.agent.Info().Name
+		}
+		s.handler = auth.Middleware(s.authenticator, auth.WithResource(res))(mux)
+	}
 }
 
 // joinURLPath joins the base path and the path into a URL path.
@@ -198,6 +210,16 @@ func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// requestUserID returns the authenticated principal, or the user field of
+// the request when the server does not authenticate.
+func requestUserID(ctx context.Context, req *openAIRequest) string {
+	userID := req.User
+	if userID == "" {
+		userID = defaultUserID
+	}
+	return auth.UserID(ctx, userID)
+}
+
 // handleCORS handles CORS preflight requests.
 func (s *Server) handleCORS(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set(headerAccessControlOrigin, "*")
@@ -239,11 +261,7 @@ func (s *Server) handleNonStreaming(w http.ResponseWriter, r *http.Request, req
 	if sessionID == "" {
 		sessionID = uuid.New().String()
 	}
-	// Get user ID from header or use default.
-	userID := req.User
-	if userID == "" {
-		userID = defaultUserID
-	}
+	userID := requestUserID(ctx, req)
 	// Build run options with history and caller-declared external tools.
 	// Generation config (temperature, max_tokens, etc.) should be set when
 	// creating the agent, not at runtime. OpenAI API parameters are ignored
@@ -339,11 +357,7 @@ func (s *Server) handleStreaming(w http.ResponseWriter, r *http.Request, req *op
 	if sessionID == "" {
 		sessionID = uuid.New().String()
 	}
-	// Get user ID from header or use default.
-	userID := req.User
-	if userID == "" {
-		userID = defaultUserID
-	}
+	userID := requestUserID(ctx, req)
 	// Build run options with history and caller-declared external tools.
 	// Generation config (temperature, max_tokens, etc.) should be set when
 	// creating the agent, not at runtime. OpenAI API parameters are ignored
@@ -25,6 +25,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	"trpc.group/trpc-go/trpc-agent-go/event"
 	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/session/inmemory"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
 )
@@ -2213,3 +2214,49 @@ func parseSSEChunks(t *testing.T, body string) ([]openAIChunk, bool) {
 	}
 	return chunks, sawDone
 }
+
+// userRunner records the user ID of the last run.
+type userRunner struct {
+	mockRunner
+	userID string
+}
+
+func (r *userRunner) Run(ctx context.Context, userID, sessionID string, message model.Message, opts ...agent.RunOption) (<-chan *event.Event, error) {
+	r.userID = userID
+	ch := make(chan *event.Event, 1)
+	ch <- &event.Event{Response: &model.Response{
+		Done:    true,
+		Choices: []model.Choice{{Message: model.NewAssistantMessage("ok")}},
+	}}
+	close(ch)
+	return ch, nil
+}
+
+func TestServer_Authenticator(t *testing.T) {
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Key: "k-alice", Principal: auth.Principal{ID: "alice"}},
+		{Key: "k-bob", Principal: auth.Principal{ID: "bob", Apps: []string{"other"}}},
+	})
+	require.NoError(t, err)
+	r := &userRunner{}
+	s, err := New(WithRunner(r), WithAuthenticator(keys))
+	require.NoError(t, err)
+
+	body := `{"model":"m","user":"mallory","messages":[{"role":"user","content":"hi"}]}`
+	call := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		s.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusUnauthorized, call("wrong"))
+	assert.Equal(t, http.StatusForbidden, call("k-bob"))
+	assert.Empty(t, r.userID)
+	assert.Equal(t, http.StatusOK, call("k-alice"))
+	assert.Equal(t, "alice", r.userID, "principal replaces the request user")
+}
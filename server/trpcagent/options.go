@@ -13,17 +13,19 @@ import (
 
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 )
 
 // Option configures a tRPC-Agent API server.
 type Option func(*options)
 
 type options struct {
-	basePath string
-	timeout  time.Duration
-	appName  string
-	agent    agent.Agent
-	runner   runner.Runner
+	basePath      string
+	timeout       time.Duration
+	appName       string
+	agent         agent.Agent
+	runner        runner.Runner
+	authenticator auth.Authenticator
 }
 
 // WithBasePath sets the tRPC-Agent API base path.
@@ -61,6 +63,15 @@ func WithRunner(r runner.Runner) Option {
 	}
 }
 
+// WithAuthenticator requires every request to authenticate with a. The
+// principal ID replaces session.userId of run requests, and the principal
+// must be allowed to use the app and agent.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(opts *options) {
+		opts.authenticator = a
+	}
+}
+
 func newOptions(opts ...Option) options {
 	options := options{
 		basePath: defaultBasePath,
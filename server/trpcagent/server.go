@@ -29,6 +29,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/log"
 	"trpc.group/trpc-go/trpc-agent-go/model"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 )
 
 const (
@@ -43,12 +44,13 @@ const (
 
 // Server exposes one registered app through the tRPC-Agent API.
 type Server struct {
-	basePath string
-	timeout  time.Duration
-	appName  string
-	agent    agent.Agent
-	runner   runner.Runner
-	handler  http.Handler
+	basePath      string
+	timeout       time.Duration
+	appName       string
+	agent         agent.Agent
+	runner        runner.Runner
+	authenticator auth.Authenticator
+	handler       http.Handler
 }
 
 // New creates a tRPC-Agent API server.
@@ -59,11 +61,12 @@ func New(opts ...Option) (*Server, error) {
 		return nil, errors.New("trpcagent: app name must not be empty")
 	}
 	server := &Server{
-		basePath: options.basePath,
-		timeout:  options.timeout,
-		appName:  appName,
-		agent:    options.agent,
-		runner:   options.runner,
+		basePath:      options.basePath,
+		timeout:       options.timeout,
+		appName:       appName,
+		agent:         options.agent,
+		runner:        options.runner,
+		authenticator: options.authenticator,
 	}
 	if err := server.setupHandler(); err != nil {
 		return nil, err
@@ -104,6 +107,13 @@ func (s *Server) setupHandler() error {
 		mux.HandleFunc(path, s.handleRuns)
 	}
 	s.handler = mux
+	if s.authenticator != nil {
+		res := auth.Resource{App: s.appName}
+		if s.agent != nil {
+			res.Agent = s.agent.Info().Name
+		}
+		s.handler = auth.Middleware(s.authenticator, auth.WithResource(res))(mux)
+	}
 	return nil
 }
 
@@ -148,6 +158,7 @@ func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
 	if !s.decodeJSONRequestBody(w, r, &req) {
 		return
 	}
+	req.Session.UserID = auth.UserID(ctx, req.Session.UserID)
 	if err := validateRunRequest(&req); err != nil {
 		s.respondError(w, r, http.StatusBadRequest, err.Error())
 		return
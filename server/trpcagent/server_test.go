@@ -29,6 +29,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/internal/tracecapture"
 	"trpc.group/trpc-go/trpc-agent-go/model"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/server/auth"
 	"trpc.group/trpc-go/trpc-agent-go/tool"
 )
 
@@ -939,3 +940,50 @@ func encodeJSON(t *testing.T, payload any) *bytes.Buffer {
 func stringPtr(value string) *string {
 	return &value
 }
+
+type userRecordingRunner struct {
+	scriptedRunner
+	userID string
+}
+
+func (r *userRecordingRunner) Run(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	msg model.Message,
+	opts ...agent.RunOption,
+) (<-chan *event.Event, error) {
+	r.userID = userID
+	return r.scriptedRunner.Run(ctx, userID, sessionID, msg, opts...)
+}
+
+func TestServerAuthenticatorSetsUserAndEnforcesAllowlists(t *testing.T) {
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Key: "k-engine", Principal: auth.Principal{ID: "engine", Agents: []string{"writer"}}},
+		{Key: "k-other", Principal: auth.Principal{ID: "other", Apps: []string{"finance"}}},
+	})
+	require.NoError(t, err)
+	r := &userRecordingRunner{}
+	srv, err := New(WithAppName("sports-agent"), WithAgent(newFakeAgent()),
+		WithRunner(r), WithAuthenticator(keys))
+	require.NoError(t, err)
+
+	call := func(key string) int {
+		body := encodeJSON(t, runRequest{
+			Session: session{UserID: "spoofed", SessionID: "session-1"},
+			Input:   model.NewUserMessage("match_001"),
+		})
+		req := httptest.NewRequest(http.MethodPost, "/trpc-agent/v1/apps/sports-agent/runs", body)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusForbidden, call("k-other"))
+	assert.Empty(t, r.userID)
+	assert.Equal(t, http.StatusOK, call("k-engine"))
+	assert.Equal(t, "engine", r.userID)
+}
@@ -0,0 +1,206 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// capabilitiesKey is the front matter key declaring skill capabilities.
+const capabilitiesKey = "capabilities"
+
+// ErrCapabilityDenied is returned when a skill declares a capability the
+// operator's CapabilityPolicy does not allow.
+var ErrCapabilityDenied = errors.New("skill capability denied")
+
+// Capabilities are the runtime requirements a skill declares in the
+// `capabilities` block of its SKILL.md front matter:
+//
+//	---
+//	name: release-notes
+//	capabilities:
+//	  network: [api.github.com]
+//	  write_paths: [out, /var/cache/release-notes]
+//	  env: [GITHUB_TOKEN]
+//	  binaries: [git, jq]
+//	  timeout: 10m
+//	---
+//
+// A skill without the block declares no requirements.
+type Capabilities struct {
+	// Network lists the hosts the skill connects to. Entries may use
+	// path.Match wildcards such as "*.github.com".
+	Network []string `yaml:"network,omitempty"`
+	// WritePaths lists the paths the skill writes. Relative paths are
+	// workspace-relative; absolute paths are host paths.
+	WritePaths []string `yaml:"write_paths,omitempty"`
+	// Env lists the host environment variables the skill reads.
+	Env []string `yaml:"env,omitempty"`
+	// Binaries lists the programs the skill expects on PATH.
+	Binaries []string `yaml:"binaries,omitempty"`
+	// Timeout is the run timeout the skill needs, e.g. "10m".
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// IsZero reports whether c declares no requirements.
+func (c Capabilities) IsZero() bool {
+	return len(c.Network) == 0 && len(c.WritePaths) == 0 &&
+		len(c.Env) == 0 && len(c.Binaries) == 0 && c.Timeout == 0
+}
+
+// CapabilityPolicy is the operator's ceiling on skill capabilities.
+// Anything a skill declares beyond it is denied; the zero policy only
+// admits skills that need nothing but their workspace.
+type CapabilityPolicy struct {
+	// AllowedHosts lists path.Match patterns of hosts skills may reach.
+	// "*" allows any host.
+	AllowedHosts []string
+	// AllowedWritePaths lists absolute host directories skills may write
+	// under. Workspace-relative paths are always allowed.
+	AllowedWritePaths []string
+	// AllowedEnv lists path.Match patterns of environment variables
+	// skills may read, e.g. "GITHUB_*".
+	AllowedEnv []string
+	// AllowedBinaries lists the programs skills may require. "*" allows
+	// any program.
+	AllowedBinaries []string
+	// MaxTimeout caps declared and requested run timeouts. Zero means no
+	// cap.
+	MaxTimeout time.Duration
+}
+
+// Check returns an error wrapping ErrCapabilityDenied that lists every
+// requirement of skill name exceeding p, or nil when p allows them all.
+func (p CapabilityPolicy) Check(name string, c Capabilities) error {
+	var denied []string
+	for _, host := range c.Network {
+		if !matchAny(p.AllowedHosts, strings.ToLower(host)) {
+			denied = append(denied, fmt.Sprintf("network host %q", host))
+		}
+	}
+	for _, wp := range c.WritePaths {
+		if !p.allowsWritePath(wp) {
+			denied = append(denied, fmt.Sprintf("write path %q", wp))
+		}
+	}
+	for _, env := range c.Env {
+		if !matchAny(p.AllowedEnv, env) {
+			denied = append(denied, fmt.Sprintf("env var %q", env))
+		}
+	}
+	for _, bin := range c.Binaries {
+		if !matchAny(p.AllowedBinaries, bin) {
+			denied = append(denied, fmt.Sprintf("binary %q", bin))
+		}
+	}
+	if p.MaxTimeout > 0 && c.Timeout > p.MaxTimeout {
+		denied = append(denied, fmt.Sprintf(
+			"timeout %s (max %s)", c.Timeout, p.MaxTimeout))
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: skill %q requires %s",
+		ErrCapabilityDenied, name, strings.Join(denied, ", "))
+}
+
+func (p CapabilityPolicy) allowsWritePath(wp string) bool {
+	if !filepath.IsAbs(wp) {
+		clean := filepath.ToSlash(filepath.Clean(wp))
+		return clean != ".." && !strings.HasPrefix(clean, "../")
+	}
+	clean := filepath.Clean(wp)
+	for _, dir := range p.AllowedWritePaths {
+		dir = filepath.Clean(dir)
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		rel, err := filepath.Rel(dir, clean)
+		if err == nil && rel != ".." &&
+			!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pat := range patterns {
+		if pat == "*" || pat == s {
+			return true
+		}
+		if ok, err := path.Match(pat, s); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCapabilities decodes the capabilities block of a front matter
+// block. The rest of the front matter is deliberately not decoded as
+// YAML; see parseFrontMatterYAML.
+func parseCapabilities(fm string) (Capabilities, error) {
+	block, ok := capabilitiesBlock(fm)
+	if !ok {
+		return Capabilities{}, nil
+	}
+	var doc struct {
+		Capabilities Capabilities `yaml:"capabilities"`
+	}
+	dec := yaml.NewDecoder(strings.NewReader(block))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return Capabilities{}, fmt.Errorf("invalid %s: %w",
+			capabilitiesKey, err)
+	}
+	c := doc.Capabilities
+	if c.Timeout < 0 {
+		return Capabilities{}, fmt.Errorf("invalid %s: negative timeout",
+			capabilitiesKey)
+	}
+	for _, env := range c.Env {
+		if env == "" || strings.ContainsAny(env, "= ") {
+			return Capabilities{}, fmt.Errorf(
+				"invalid %s: bad env var name %q", capabilitiesKey, env)
+		}
+	}
+	return c, nil
+}
+
+// capabilitiesBlock extracts the capabilities key and its indented
+// continuation lines from front matter.
+func capabilitiesBlock(fm string) (string, bool) {
+	lines := strings.Split(fm, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, capabilitiesKey+":") {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(line)
+		b.WriteByte('\n')
+		for _, next := range lines[i+1:] {
+			if next != "" && next[0] != ' ' && next[0] != '\t' {
+				break
+			}
+			b.WriteString(next)
+			b.WriteByte('\n')
+		}
+		return b.String(), true
+	}
+	return "", false
+}
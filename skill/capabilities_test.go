@@ -0,0 +1,85 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSRepository_Capabilities(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "notes")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, skillFile), []byte(
+		"---\nname: notes\ndescription: a #1 skill\ncapabilities:\n"+
+			"  network:\n    - api.github.com\n"+
+			"  write_paths: [out, /var/cache/notes]\n"+
+			"  env: [GITHUB_TOKEN]\n  timeout: 90s\n"+
+			"metadata: x\n---\nbody\n"), 0o644))
+
+	repo, err := NewFSRepository(root)
+	require.NoError(t, err)
+	sk, err := repo.Get("notes")
+	require.NoError(t, err)
+	require.Equal(t, "a #1 skill", sk.Summary.Description)
+	require.Equal(t, Capabilities{
+		Network:    []string{"api.github.com"},
+		WritePaths: []string{"out", "/var/cache/notes"},
+		Env:        []string{"GITHUB_TOKEN"},
+		Timeout:    90 * time.Second,
+	}, sk.Capabilities)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, skillFile), []byte(
+		"---\nname: notes\ncapabilities:\n  network_hosts: [x]\n---\n"),
+		0o644))
+	_, err = repo.Get("notes")
+	require.ErrorContains(t, err, "invalid capabilities")
+}
+
+func TestCapabilityPolicy_Check(t *testing.T) {
+	caps := Capabilities{
+		Network:    []string{"api.github.com"},
+		WritePaths: []string{"out", "/var/cache/notes/tmp"},
+		Env:        []string{"GITHUB_TOKEN"},
+		Binaries:   []string{"git"},
+		Timeout:    time.Minute,
+	}
+	require.NoError(t, CapabilityPolicy{}.Check("none", Capabilities{
+		WritePaths: []string{"out/reports"},
+	}))
+
+	policy := CapabilityPolicy{
+		AllowedHosts:      []string{"*.github.com"},
+		AllowedWritePaths: []string{"/var/cache/notes"},
+		AllowedEnv:        []string{"GITHUB_*"},
+		AllowedBinaries:   []string{"*"},
+		MaxTimeout:        time.Minute,
+	}
+	require.NoError(t, policy.Check("notes", caps))
+
+	err := CapabilityPolicy{MaxTimeout: time.Second}.Check("notes", caps)
+	require.ErrorIs(t, err, ErrCapabilityDenied)
+	require.EqualError(t, err, `skill capability denied: skill "notes" `+
+		`requires network host "api.github.com", write path `+
+		`"/var/cache/notes/tmp", env var "GITHUB_TOKEN", binary "git", `+
+		`timeout 1m0s (max 1s)`)
+
+	err = policy.Check("escape", Capabilities{
+		WritePaths: []string{"../outside", "/var/cache/notes-other"},
+	})
+	require.ErrorContains(t, err, `write path "../outside"`)
+	require.ErrorContains(t, err, `write path "/var/cache/notes-other"`)
+}
@@ -0,0 +1,439 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLockfileName is the conventional lockfile name.
+const DefaultLockfileName = "skills.lock"
+
+// LockfileVersion is the lockfile format version written by Save.
+const LockfileVersion = 1
+
+// versionPlaceholder in a locked skill URL is replaced with its version.
+const versionPlaceholder = "{version}"
+
+const cacheLockedPrefix = "sha256-"
+
+// ErrChecksumMismatch is returned when a downloaded skill archive does not
+// match its locked SHA-256.
+var ErrChecksumMismatch = errors.New("skill checksum mismatch")
+
+// ErrSignatureInvalid is returned when a locked skill has no signature
+// from a trusted key.
+var ErrSignatureInvalid = errors.New("skill signature is missing or invalid")
+
+// Lockfile pins remote skills to exact archives.
+//
+// A lockfile doubles as the manifest: entries without a sha256 declare the
+// skills to fetch and are filled in by Update. Example:
+//
+//	version: 1
+//	skills:
+//	  - name: pdf
+//	    url: https://example.com/skills/pdf-{version}.zip
+//	    version: 1.2.0
+//	    sha256: 3b1f...e9
+//	    signature: 9kqZ...==
+type Lockfile struct {
+	Version int           `yaml:"version"`
+	Skills  []LockedSkill `yaml:"skills"`
+}
+
+// LockedSkill is a single pinned skill.
+type LockedSkill struct {
+	// Name is the skill name the archive must provide.
+	Name string `yaml:"name"`
+	// URL is the http(s) or file URL of the archive, or of a single
+	// SKILL.md. A {version} placeholder is replaced with Version.
+	URL string `yaml:"url"`
+	// Version is informational unless URL contains {version}.
+	Version string `yaml:"version,omitempty"`
+	// SHA256 is the hex digest of the archive.
+	SHA256 string `yaml:"sha256,omitempty"`
+	// Signature is the base64 ed25519 signature of the raw SHA-256
+	// digest, see SignDigest.
+	Signature string `yaml:"signature,omitempty"`
+}
+
+// ResolvedURL returns URL with the version placeholder replaced.
+func (s LockedSkill) ResolvedURL() string {
+	return strings.ReplaceAll(s.URL, versionPlaceholder, s.Version)
+}
+
+// LoadLockfile reads and validates a lockfile.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lf Lockfile
+	if err := yaml.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("parse skills lockfile %s: %w", path, err)
+	}
+	if err := lf.validate(); err != nil {
+		return nil, fmt.Errorf("skills lockfile %s: %w", path, err)
+	}
+	return &lf, nil
+}
+
+// Save writes the lockfile to path.
+func (l *Lockfile) Save(path string) error {
+	if err := l.validate(); err != nil {
+		return err
+	}
+	l.Version = LockfileVersion
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *Lockfile) validate() error {
+	if l.Version > LockfileVersion {
+		return fmt.Errorf("unsupported lockfile version %d", l.Version)
+	}
+	seen := make(map[string]struct{}, len(l.Skills))
+	for _, s := range l.Skills {
+		if strings.TrimSpace(s.Name) == "" {
+			return errors.New("skill without name")
+		}
+		if _, ok := seen[s.Name]; ok {
+			return fmt.Errorf("duplicate skill %q", s.Name)
+		}
+		seen[s.Name] = struct{}{}
+		if strings.TrimSpace(s.URL) == "" {
+			return fmt.Errorf("skill %q: missing url", s.Name)
+		}
+		if s.SHA256 != "" {
+			if b, err := hex.DecodeString(s.SHA256); err != nil || len(b) != sha256.Size {
+				return fmt.Errorf("skill %q: invalid sha256", s.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// LockOption configures fetching and updating locked skills.
+type LockOption func(*lockOptions)
+
+type lockOptions struct {
+	trustedKeys []ed25519.PublicKey
+	signingKey  ed25519.PrivateKey
+	offline     bool
+	cacheDir    string
+	client      *http.Client
+}
+
+func newLockOptions(opts []LockOption) lockOptions {
+	o := lockOptions{
+		offline:  skillsOffline(),
+		cacheDir: skillsCacheDir(),
+		client:   http.DefaultClient,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithTrustedKeys requires every locked skill to carry a signature made
+// by one of keys.
+func WithTrustedKeys(keys ...ed25519.PublicKey) LockOption {
+	return func(o *lockOptions) { o.trustedKeys = append(o.trustedKeys, keys...) }
+}
+
+// WithSigningKey makes Update sign the skills it locks.
+func WithSigningKey(key ed25519.PrivateKey) LockOption {
+	return func(o *lockOptions) { o.signingKey = key }
+}
+
+// WithOffline serves locked skills only from the cache. It defaults to the
+// SKILLS_OFFLINE environment variable.
+func WithOffline(offline bool) LockOption {
+	return func(o *lockOptions) { o.offline = offline }
+}
+
+// WithLockCacheDir overrides the cache directory. It defaults to
+// SKILLS_CACHE_DIR or the user cache directory.
+func WithLockCacheDir(dir string) LockOption {
+	return func(o *lockOptions) {
+		if dir != "" {
+			o.cacheDir = dir
+		}
+	}
+}
+
+// WithLockHTTPClient sets the client used for downloads.
+func WithLockHTTPClient(client *http.Client) LockOption {
+	return func(o *lockOptions) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// NewLockedFSRepository fetches the skills pinned in the lockfile at path,
+// verifies them and returns a repository over them. Additional roots, such
+// as local skill directories, are scanned after the locked skills.
+func NewLockedFSRepository(
+	path string,
+	roots []string,
+	opts ...LockOption,
+) (*FSRepository, error) {
+	lf, err := LoadLockfile(path)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := lf.Fetch(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFSRepository(append(dirs, roots...)...)
+}
+
+// Fetch makes every locked skill available in the cache and returns their
+// root directories in lockfile order.
+//
+// Archives are downloaded to a temporary file and checked against their
+// SHA-256 and, with WithTrustedKeys, their signature before anything is
+// extracted. The cache is keyed by checksum, so a cached skill is never
+// downloaded again and works offline.
+func (l *Lockfile) Fetch(opts ...LockOption) ([]string, error) {
+	o := newLockOptions(opts)
+	dirs := make([]string, 0, len(l.Skills))
+	for _, s := range l.Skills {
+		if s.SHA256 == "" {
+			return nil, fmt.Errorf("skill %q is not locked, run an update first", s.Name)
+		}
+		if err := o.verifySignature(s); err != nil {
+			return nil, err
+		}
+		dir, err := o.fetch(s)
+		if err != nil {
+			return nil, fmt.Errorf("fetch skill %q: %w", s.Name, err)
+		}
+		if err := checkProvidesSkill(dir, s.Name); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// Update downloads the named skills, or all skills when names is empty,
+// and records their current checksums. Skills whose checksum changed lose
+// their signature unless WithSigningKey is given. It returns the names of
+// the skills whose checksum changed. Update ignores offline mode.
+func (l *Lockfile) Update(names []string, opts ...LockOption) ([]string, error) {
+	o := newLockOptions(opts)
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = false
+	}
+	var changed []string
+	for i := range l.Skills {
+		s := &l.Skills[i]
+		if len(names) > 0 {
+			if _, ok := selected[s.Name]; !ok {
+				continue
+			}
+			selected[s.Name] = true
+		}
+		sum, dir, err := o.download(*s)
+		if err != nil {
+			return nil, fmt.Errorf("update skill %q: %w", s.Name, err)
+		}
+		if err := checkProvidesSkill(dir, s.Name); err != nil {
+			return nil, err
+		}
+		if sum != s.SHA256 {
+			changed = append(changed, s.Name)
+			s.SHA256 = sum
+			s.Signature = ""
+		}
+		if o.signingKey != nil {
+			if s.Signature, err = SignDigest(o.signingKey, s.SHA256); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for name, found := range selected {
+		if !found {
+			return nil, fmt.Errorf("skill %q is not in the lockfile", name)
+		}
+	}
+	return changed, nil
+}
+
+// SignDigest signs the hex SHA-256 digest of a skill archive with key and
+// returns the base64 signature stored in the lockfile.
+func SignDigest(key ed25519.PrivateKey, sha256Hex string) (string, error) {
+	digest, err := hex.DecodeString(sha256Hex)
+	if err != nil || len(digest) != sha256.Size {
+		return "", fmt.Errorf("invalid sha256 %q", sha256Hex)
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest)), nil
+}
+
+func (o *lockOptions) verifySignature(s LockedSkill) error {
+	if len(o.trustedKeys) == 0 {
+		return nil
+	}
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil || s.Signature == "" {
+		return fmt.Errorf("skill %q: %w", s.Name, ErrSignatureInvalid)
+	}
+	digest, _ := hex.DecodeString(s.SHA256)
+	for _, key := range o.trustedKeys {
+		if ed25519.Verify(key, digest, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("skill %q: %w", s.Name, ErrSignatureInvalid)
+}
+
+func (o *lockOptions) lockedCacheDir(sum string) string {
+	return filepath.Join(o.cacheDir, cacheLockedPrefix+strings.ToLower(sum))
+}
+
+func (o *lockOptions) fetch(s LockedSkill) (string, error) {
+	destDir := o.lockedCacheDir(s.SHA256)
+	if fileExists(filepath.Join(destDir, cacheReadyFile)) {
+		return destDir, nil
+	}
+	if o.offline {
+		return "", fmt.Errorf("%w: %s", ErrSkillNotCached, s.Name)
+	}
+	u, err := url.Parse(s.ResolvedURL())
+	if err != nil {
+		return "", err
+	}
+	return populateCache(destDir, u, o.fetcher(u), func(srcPath string) error {
+		sum, err := fileSHA256(srcPath)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, s.SHA256) {
+			return fmt.Errorf("%w: got %s, locked %s", ErrChecksumMismatch, sum, s.SHA256)
+		}
+		return nil
+	})
+}
+
+// download fetches the current archive of s, caches it under its checksum
+// and returns the checksum and the cache directory.
+func (o *lockOptions) download(s LockedSkill) (string, string, error) {
+	u, err := url.Parse(s.ResolvedURL())
+	if err != nil {
+		return "", "", err
+	}
+	tmpDir, err := os.MkdirTemp("", cacheTempPrefix)
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	srcPath := filepath.Join(tmpDir, cacheDownloadFile)
+	if err := o.fetcher(u)(srcPath); err != nil {
+		return "", "", err
+	}
+	sum, err := fileSHA256(srcPath)
+	if err != nil {
+		return "", "", err
+	}
+	dir, err := populateCache(o.lockedCacheDir(sum), u, func(dst string) error {
+		return copyFile(srcPath, dst)
+	}, nil)
+	return sum, dir, err
+}
+
+func (o *lockOptions) fetcher(u *url.URL) func(string) error {
+	return func(dst string) error {
+		switch u.Scheme {
+		case "http", "https":
+			return downloadWithClient(o.client, u, dst)
+		case "file":
+			p, err := fileURLPath(u)
+			if err != nil {
+				return err
+			}
+			return copyFile(p, dst)
+		default:
+			return fmt.Errorf("unsupported skill URL: %s", u.Redacted())
+		}
+	}
+}
+
+func checkProvidesSkill(dir, name string) error {
+	index, err := scanRoots([]string{dir})
+	if err != nil {
+		return err
+	}
+	if _, ok := index[name]; !ok {
+		return fmt.Errorf("skill archive for %q does not contain that skill", name)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(in, maxDownloadBytes+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > maxDownloadBytes {
+		err = errors.New("skill archive too large")
+	}
+	return err
+}
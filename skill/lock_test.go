@@ -0,0 +1,157 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// skillServer serves a mutable skill archive at /pdf-<version>.zip.
+type skillServer struct {
+	mu      sync.Mutex
+	archive []byte
+	hits    int
+}
+
+func (s *skillServer) set(archive []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archive = archive
+}
+
+func (s *skillServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits++
+	if r.URL.Path != "/pdf-1.0.0.zip" {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write(s.archive)
+}
+
+func pdfArchive(t *testing.T, body string) []byte {
+	return buildZip(t, map[string]string{
+		"pdf/SKILL.md": "---\nname: pdf\ndescription: PDF tools\n---\n" + body,
+	})
+}
+
+func TestLockfile_UpdateFetchAndVerify(t *testing.T) {
+	srv := &skillServer{archive: pdfArchive(t, "v1")}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, DefaultLockfileName)
+	require.NoError(t, os.WriteFile(lockPath, []byte(
+		"skills:\n  - name: pdf\n    url: "+ts.URL+"/pdf-{version}.zip\n    version: 1.0.0\n"),
+		filePerm))
+
+	lf, err := LoadLockfile(lockPath)
+	require.NoError(t, err)
+	_, err = lf.Fetch(WithLockCacheDir(t.TempDir()))
+	require.ErrorContains(t, err, "not locked")
+
+	cache := filepath.Join(dir, "cache")
+	changed, err := lf.Update(nil, WithLockCacheDir(cache), WithSigningKey(priv))
+	require.NoError(t, err)
+	require.Equal(t, []string{"pdf"}, changed)
+	require.NoError(t, lf.Save(lockPath))
+
+	lf, err = LoadLockfile(lockPath)
+	require.NoError(t, err)
+	require.Equal(t, LockfileVersion, lf.Version)
+	require.Len(t, lf.Skills[0].SHA256, 64)
+	require.NotEmpty(t, lf.Skills[0].Signature)
+
+	// Cached by checksum during the update: fetching needs no download and
+	// works offline.
+	hits := srv.hits
+	repo, err := NewLockedFSRepository(lockPath, nil,
+		WithLockCacheDir(cache), WithTrustedKeys(pub), WithOffline(true))
+	require.NoError(t, err)
+	sk, err := repo.Get("pdf")
+	require.NoError(t, err)
+	require.Equal(t, "v1", sk.Body)
+	require.Equal(t, hits, srv.hits)
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, err = lf.Fetch(WithLockCacheDir(cache), WithTrustedKeys(otherPub))
+	require.ErrorIs(t, err, ErrSignatureInvalid)
+
+	// A changed remote archive is rejected before extraction.
+	srv.set(pdfArchive(t, "v2"))
+	fresh := filepath.Join(dir, "fresh")
+	_, err = lf.Fetch(WithLockCacheDir(fresh))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	entries, err := os.ReadDir(fresh)
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.NotContains(t, e.Name(), cacheLockedPrefix)
+	}
+
+	_, err = lf.Fetch(WithLockCacheDir(filepath.Join(dir, "empty")), WithOffline(true))
+	require.ErrorIs(t, err, ErrSkillNotCached)
+
+	// Re-locking picks up the new archive and drops the stale signature.
+	old := lf.Skills[0].SHA256
+	changed, err = lf.Update([]string{"pdf"}, WithLockCacheDir(cache))
+	require.NoError(t, err)
+	require.Equal(t, []string{"pdf"}, changed)
+	require.NotEqual(t, old, lf.Skills[0].SHA256)
+	require.Empty(t, lf.Skills[0].Signature)
+
+	_, err = lf.Update([]string{"missing"}, WithLockCacheDir(cache))
+	require.ErrorContains(t, err, `"missing" is not in the lockfile`)
+}
+
+func TestLockfile_Validation(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"duplicate": "skills:\n  - {name: a, url: file:///a}\n  - {name: a, url: file:///b}\n",
+		"no url":    "skills:\n  - {name: a}\n",
+		"bad sum":   "skills:\n  - {name: a, url: file:///a, sha256: abc}\n",
+		"version":   "version: 99\nskills: []\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(p, []byte(content), filePerm))
+			_, err := LoadLockfile(p)
+			require.Error(t, err)
+		})
+	}
+
+	// The archive must provide the locked skill.
+	archive := filepath.Join(dir, "other.zip")
+	require.NoError(t, os.WriteFile(archive,
+		buildZip(t, map[string]string{"other/SKILL.md": "# other"}), filePerm))
+	lf := &Lockfile{Skills: []LockedSkill{{Name: "pdf", URL: "file://" + archive}}}
+	_, err := lf.Update(nil, WithLockCacheDir(t.TempDir()))
+	require.ErrorContains(t, err, `does not contain that skill`)
+}
+
+func TestCacheURLRoot_Offline(t *testing.T) {
+	t.Setenv(EnvSkillsCacheDir, t.TempDir())
+	t.Setenv(EnvSkillsOffline, "true")
+	_, err := NewFSRepository("https://example.invalid/skills.zip")
+	require.ErrorIs(t, err, ErrSkillNotCached)
+}
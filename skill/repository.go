@@ -62,6 +62,9 @@ type Skill struct {
 	Summary Summary
 	Body    string
 	Docs    []Doc
+	// Capabilities are the runtime requirements declared in the
+	// front matter.
+	Capabilities Capabilities
 }
 
 // Repository is a source of skills.
@@ -247,7 +250,7 @@ func (r *FSRepository) Get(name string) (*Skill, error) {
 	}
 	dir := entry.dir
 	sf := filepath.Join(dir, skillFile)
-	sum, body, err := parseFull(sf)
+	sum, body, caps, err := parseSkillFile(sf)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +258,12 @@ func (r *FSRepository) Get(name string) (*Skill, error) {
 		sum.Name = name
 	}
 	docs := r.readDocs(dir)
-	return &Skill{Summary: sum, Body: body, Docs: docs}, nil
+	return &Skill{
+		Summary:      sum,
+		Body:         body,
+		Docs:         docs,
+		Capabilities: caps,
+	}, nil
 }
 
 func (r *FSRepository) snapshotIndex() map[string]fsSkillEntry {
@@ -372,17 +380,28 @@ func parseSummary(path string) (Summary, error) {
 
 // parseFull returns front matter and the Markdown body.
 func parseFull(path string) (Summary, string, error) {
+	s, body, _, err := parseSkillFile(path)
+	return s, body, err
+}
+
+// parseSkillFile returns front matter, the Markdown body and the
+// declared capabilities.
+func parseSkillFile(path string) (Summary, string, Capabilities, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
-		return Summary{}, "", err
+		return Summary{}, "", Capabilities{}, err
 	}
-	text := string(b)
-	fm, body := splitFrontMatter(text)
+	raw, body := splitFrontMatterRaw(string(b))
+	fm := parseFrontMatterYAML(raw)
 	s := Summary{
 		Name:        fm["name"],
 		Description: fm["description"],
 	}
-	return s, body, nil
+	caps, err := parseCapabilities(raw)
+	if err != nil {
+		return Summary{}, "", Capabilities{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, body, caps, nil
 }
 
 // readFrontMatter reads YAML front matter block into a simple map.
@@ -416,18 +435,22 @@ func readFrontMatter(r *bufio.Reader) (map[string]string, string, error) {
 // It uses gopkg.in/yaml.v3 to correctly handle multi-line block scalars
 // (e.g. "description: |-\n  text") that the previous hand-rolled parser missed.
 func splitFrontMatter(text string) (map[string]string, string) {
+	fm, body := splitFrontMatterRaw(text)
+	return parseFrontMatterYAML(fm), body
+}
+
+// splitFrontMatterRaw returns the unparsed front matter block and the body.
+func splitFrontMatterRaw(text string) (string, string) {
 	text = strings.ReplaceAll(text, "\r\n", "\n")
 	if !strings.HasPrefix(text, "---\n") {
-		return map[string]string{}, text
+		return "", text
 	}
 	idx := strings.Index(text[4:], "\n---\n")
 	if idx < 0 {
 		// No closing delimiter; treat whole as body.
-		return map[string]string{}, text
+		return "", text
 	}
-	fm := text[4 : 4+idx]
-	body := text[4+idx+5:]
-	return parseFrontMatterYAML(fm), body
+	return text[4 : 4+idx], text[4+idx+5:]
 }
 
 // isBlockScalarIndicator reports whether val (the text after "key: ") is a
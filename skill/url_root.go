@@ -16,6 +16,7 @@ import (
 	"compress/gzip"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,6 +24,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -51,6 +53,15 @@ const (
 // When empty, the user cache directory is used.
 const EnvSkillsCacheDir = "SKILLS_CACHE_DIR"
 
+// EnvSkillsOffline, when set to a true value such as "1" or "true",
+// forbids downloads: URL-based skills roots and locked skills are only
+// served from the cache.
+const EnvSkillsOffline = "SKILLS_OFFLINE"
+
+// ErrSkillNotCached is returned in offline mode for skills roots that are
+// not in the cache yet.
+var ErrSkillNotCached = errors.New("skills root is not cached and offline mode is enabled")
+
 func resolveSkillsRoot(root string) (string, error) {
 	root = strings.TrimSpace(root)
 	if root == "" {
@@ -84,9 +95,28 @@ func fileURLPath(u *url.URL) (string, error) {
 }
 
 func cacheURLRoot(u *url.URL) (string, error) {
-	cacheDir := skillsCacheDir()
-	key := sha256Hex(u.String())
-	destDir := filepath.Join(cacheDir, key)
+	destDir := filepath.Join(skillsCacheDir(), sha256Hex(u.String()))
+	if fileExists(filepath.Join(destDir, cacheReadyFile)) {
+		return destDir, nil
+	}
+	if skillsOffline() {
+		return "", fmt.Errorf("%w: %s", ErrSkillNotCached, u.Redacted())
+	}
+	return populateCache(destDir, u, func(srcPath string) error {
+		return downloadURLToFile(u, srcPath)
+	}, nil)
+}
+
+// populateCache fetches a skills root into destDir unless it is already
+// ready. verify, when set, checks the downloaded payload before it is
+// extracted.
+func populateCache(
+	destDir string,
+	u *url.URL,
+	fetch func(srcPath string) error,
+	verify func(srcPath string) error,
+) (string, error) {
+	cacheDir := filepath.Dir(destDir)
 	ready := filepath.Join(destDir, cacheReadyFile)
 	if fileExists(ready) {
 		return destDir, nil
@@ -104,9 +134,14 @@ func cacheURLRoot(u *url.URL) (string, error) {
 	defer os.RemoveAll(tmpDir)
 
 	srcPath := filepath.Join(tmpDir, cacheDownloadFile)
-	if err := downloadURLToFile(u, srcPath); err != nil {
+	if err := fetch(srcPath); err != nil {
 		return "", err
 	}
+	if verify != nil {
+		if err := verify(srcPath); err != nil {
+			return "", err
+		}
+	}
 	extractDir := filepath.Join(tmpDir, cacheExtractDir)
 	if err := os.MkdirAll(extractDir, dirPerm); err != nil {
 		return "", err
@@ -130,6 +165,11 @@ func cacheURLRoot(u *url.URL) (string, error) {
 	return destDir, nil
 }
 
+func skillsOffline() bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(EnvSkillsOffline)))
+	return err == nil && v
+}
+
 func skillsCacheDir() string {
 	if d := strings.TrimSpace(os.Getenv(EnvSkillsCacheDir)); d != "" {
 		return d
@@ -142,7 +182,11 @@ func skillsCacheDir() string {
 }
 
 func downloadURLToFile(u *url.URL, path string) error {
-	resp, err := http.Get(u.String())
+	return downloadWithClient(http.DefaultClient, u, path)
+}
+
+func downloadWithClient(client *http.Client, u *url.URL, path string) error {
+	resp, err := client.Get(u.String())
 	if err != nil {
 		return err
 	}
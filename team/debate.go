@@ -0,0 +1,327 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// DebateConfig configures a debate team.
+type DebateConfig struct {
+	// Rounds is the number of critique rounds after the initial answers.
+	// In each round every member sees the other answers, critiques them
+	// and revises its own. Zero skips straight to the decision.
+	Rounds int
+
+	// MaxTokens bounds the tokens members may use across all rounds, as
+	// reported in their response usage. Once exceeded, the remaining
+	// rounds are skipped and the decision is made from the latest answers.
+	// Zero means no budget.
+	MaxTokens int
+
+	// Judge, when set, reads the final answers and writes the answer of
+	// the team. It must not be one of the members.
+	Judge agent.Agent
+
+	// Voting decides the winner when no judge is set. Members vote for
+	// the best answer other than their own and the policy picks the
+	// winning member. Nil means MajorityVote.
+	Voting VotingPolicy
+}
+
+// VotingPolicy picks the winning member from votes, which map each voter to
+// the member it voted for. candidates lists the members in team order.
+// Returning "" selects the first candidate.
+type VotingPolicy func(candidates []string, votes map[string]string) string
+
+// MajorityVote picks the member with the most votes. Ties go to the member
+// listed first.
+func MajorityVote(candidates []string, votes map[string]string) string {
+	counts := make(map[string]int, len(candidates))
+	for _, v := range votes {
+		counts[v]++
+	}
+	winner, best := "", 0
+	for _, c := range candidates {
+		if counts[c] > best {
+			winner, best = c, counts[c]
+		}
+	}
+	return winner
+}
+
+// DefaultDebateConfig returns the defaults used by NewDebate.
+func DefaultDebateConfig() DebateConfig {
+	return DebateConfig{Rounds: 2}
+}
+
+const (
+	debateVotePrefix = "VOTE:"
+
+	debateVotePrompt = "Vote for the best answer other than your own. " +
+		"Explain briefly, then end with a line of the form \"" +
+		debateVotePrefix + " <name>\"."
+)
+
+// NewDebate creates a debate team.
+//
+// Every member answers the user request independently. For
+// DebateConfig.Rounds rounds, members then see each other's answers,
+// critique them and revise their own. Finally DebateConfig.Judge writes the
+// answer of the team from the final answers, or, without a judge, members
+// vote and DebateConfig.Voting picks the winning answer.
+//
+// Each member runs on its own branch, so every member's transcript is kept
+// as branch events in the session.
+func NewDebate(
+	name string,
+	members []agent.Agent,
+	opts ...Option,
+) (*Team, error) {
+	if name == "" {
+		return nil, errEmptyTeamName
+	}
+	cfg := defaultOptions(name)
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	memberByName, err := buildMemberIndex(name, members)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) < 2 {
+		return nil, errors.New("debate needs at least two members")
+	}
+	d := cfg.debate
+	if d.Rounds < 0 || d.MaxTokens < 0 {
+		return nil, errors.New("debate rounds and token budget must not be negative")
+	}
+	if d.Judge != nil {
+		judgeName := d.Judge.Info().Name
+		if judgeName == "" {
+			return nil, errors.New("judge name is empty")
+		}
+		if memberByName[judgeName] != nil || judgeName == name {
+			return nil, fmt.Errorf("judge name %q conflicts with the team", judgeName)
+		}
+	} else if d.Voting == nil {
+		d.Voting = MajorityVote
+	}
+	return &Team{
+		name:         name,
+		description:  cfg.description,
+		mode:         ModeDebate,
+		members:      members,
+		memberByName: memberByName,
+		debate:       d,
+	}, nil
+}
+
+// debateRun is the state of one debate.
+type debateRun struct {
+	team *Team
+	inv  *agent.Invocation
+	ch   chan *event.Event
+
+	mu      sync.Mutex
+	answers map[string]string
+	tokens  int
+}
+
+func (t *Team) runDebate(
+	ctx context.Context,
+	invocation *agent.Invocation,
+) (<-chan *event.Event, error) {
+	size := defaultTaskBoardChannelBufferSize
+	if s := agent.GetEventChannelBufferSize(invocation); s > 0 {
+		size = s
+	}
+	r := &debateRun{
+		team:    t,
+		inv:     invocation,
+		ch:      make(chan *event.Event, size),
+		answers: make(map[string]string, len(t.members)),
+	}
+	runCtx := agent.CloneContext(ctx)
+	go func() {
+		defer close(r.ch)
+		ctx := agent.NewInvocationContext(runCtx, invocation)
+		if err := r.execute(ctx); err != nil {
+			log.WarnfContext(ctx, "team %s: debate: %v", t.name, err)
+			agent.EmitEvent(ctx, invocation, r.ch, event.NewErrorEvent(
+				invocation.InvocationID, t.name,
+				model.ErrorTypeFlowError, err.Error()))
+		}
+	}()
+	return r.ch, nil
+}
+
+func (r *debateRun) execute(ctx context.Context) error {
+	cfg := r.team.debate
+	if err := r.round(ctx, func(string) model.Message {
+		return r.inv.Message
+	}); err != nil {
+		return err
+	}
+	for i := 0; i < cfg.Rounds && !r.overBudget(); i++ {
+		if err := r.round(ctx, func(member string) model.Message {
+			return model.NewUserMessage(r.critiquePrompt(member))
+		}); err != nil {
+			return err
+		}
+	}
+	if cfg.Judge != nil {
+		inv := r.inv.Clone(
+			agent.WithInvocationAgent(cfg.Judge),
+			agent.WithInvocationMessage(model.NewUserMessage(r.judgePrompt())),
+		)
+		// The judge's final response is the answer of the team.
+		_, err := runMember(ctx, r.ch, cfg.Judge, inv)
+		return err
+	}
+	return r.vote(ctx)
+}
+
+// round runs all members in parallel and records their answers.
+func (r *debateRun) round(
+	ctx context.Context,
+	input func(member string) model.Message,
+) error {
+	results := make([]memberResult, len(r.team.members))
+	errs := make([]error, len(r.team.members))
+	var wg sync.WaitGroup
+	for i, m := range r.team.members {
+		// Per-member filter keys keep members from reading each other's
+		// transcripts; they only see the answers the prompts show them.
+		key := r.inv.GetEventFilterKey()
+		if key == "" {
+			key = r.team.name
+		}
+		inv := r.inv.Clone(
+			agent.WithInvocationAgent(m),
+			agent.WithInvocationMessage(input(m.Info().Name)),
+			agent.WithInvocationEventFilterKey(
+				key+agent.EventFilterKeyDelimiter+m.Info().Name),
+		)
+		wg.Add(1)
+		go func(i int, m agent.Agent) {
+			defer wg.Done()
+			results[i], errs[i] = runMember(ctx, r.ch, m, inv)
+		}(i, m)
+	}
+	wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, m := range r.team.members {
+		r.tokens += results[i].tokens
+		if errs[i] != nil {
+			return fmt.Errorf("member %s: %w", m.Info().Name, errs[i])
+		}
+		r.answers[m.Info().Name] = results[i].text
+	}
+	return nil
+}
+
+func (r *debateRun) overBudget() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit := r.team.debate.MaxTokens
+	return limit > 0 && r.tokens >= limit
+}
+
+// vote lets members vote and emits the winning answer as the answer of the
+// team.
+func (r *debateRun) vote(ctx context.Context) error {
+	r.mu.Lock()
+	final := maps.Clone(r.answers)
+	r.mu.Unlock()
+	if err := r.round(ctx, func(member string) model.Message {
+		return model.NewUserMessage(r.answersPrompt(member) + "\n\n" + debateVotePrompt)
+	}); err != nil {
+		return err
+	}
+	candidates := make([]string, len(r.team.members))
+	for i, m := range r.team.members {
+		candidates[i] = m.Info().Name
+	}
+	r.mu.Lock()
+	votes := make(map[string]string, len(candidates))
+	for _, voter := range candidates {
+		if c := parseVote(r.answers[voter]); c != voter && r.team.memberByName[c] != nil {
+			votes[voter] = c
+		}
+	}
+	r.mu.Unlock()
+	winner := r.team.debate.Voting(candidates, votes)
+	if r.team.memberByName[winner] == nil {
+		winner = candidates[0]
+	}
+	return agent.EmitEvent(ctx, r.inv, r.ch, event.NewResponseEvent(
+		r.inv.InvocationID, r.team.name, &model.Response{
+			Object: model.ObjectTypeChatCompletion,
+			Done:   true,
+			Choices: []model.Choice{{
+				Message: model.NewAssistantMessage(final[winner]),
+			}},
+		}))
+}
+
+func parseVote(ballot string) string {
+	lines := strings.Split(strings.TrimSpace(ballot), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if rest, ok := strings.CutPrefix(line, debateVotePrefix); ok {
+			return strings.Trim(strings.TrimSpace(rest), "*\"'`.")
+		}
+	}
+	return ""
+}
+
+func (r *debateRun) critiquePrompt(member string) string {
+	return r.answersPrompt(member) + "\n\nCritique the other answers, " +
+		"then reply with your improved answer to the question."
+}
+
+func (r *debateRun) judgePrompt() string {
+	return r.answersPrompt("") + "\n\nDecide the best answer to the " +
+		"question and reply with that answer only."
+}
+
+// answersPrompt renders the question and the latest answers, starting with
+// the answer of member when it is set.
+func (r *debateRun) answersPrompt(member string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question:\n%s\n", r.inv.Message.Content)
+	if member != "" {
+		fmt.Fprintf(&b, "\nYour answer:\n%s\n", r.answers[member])
+	}
+	b.WriteString("\nAnswers of the other participants:\n")
+	for _, m := range r.team.members {
+		name := m.Info().Name
+		if name == member {
+			continue
+		}
+		fmt.Fprintf(&b, "\n[%s]\n%s\n", name, r.answers[name])
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/runner"
+	sessioninmemory "trpc.group/trpc-go/trpc-agent-go/session/inmemory"
+)
+
+const testJudgeName = "judge"
+
+// debateAgent answers with reply and reports tokens as usage.
+type debateAgent struct {
+	testAgent
+	tokens int
+	reply  func(input string) string
+
+	mu     sync.Mutex
+	inputs []string
+	keys   []string
+}
+
+func (a *debateAgent) Run(
+	_ context.Context,
+	inv *agent.Invocation,
+) (<-chan *event.Event, error) {
+	a.mu.Lock()
+	a.inputs = append(a.inputs, inv.Message.Content)
+	a.keys = append(a.keys, inv.GetEventFilterKey())
+	a.mu.Unlock()
+	ch := make(chan *event.Event, 1)
+	ch <- event.NewResponseEvent(inv.InvocationID, a.name, &model.Response{
+		Done:    true,
+		Usage:   &model.Usage{TotalTokens: a.tokens},
+		Choices: []model.Choice{{Message: model.NewAssistantMessage(a.reply(inv.Message.Content))}},
+	})
+	close(ch)
+	return ch, nil
+}
+
+func runDebateTeam(t *testing.T, tm *Team) (string, string) {
+	t.Helper()
+	r := runner.NewRunner(testAppName, tm,
+		runner.WithSessionService(sessioninmemory.NewSessionService()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := r.Run(ctx, testUserID, testSessionID,
+		model.NewUserMessage("what is 6*7?"))
+	require.NoError(t, err)
+	var author, final string
+	for evt := range events {
+		require.Nil(t, evt.Error)
+		if evt.Response != nil && evt.Done && len(evt.Choices) > 0 {
+			author, final = evt.Author, evt.Choices[0].Message.Content
+		}
+	}
+	return author, final
+}
+
+func TestDebate_JudgeDecides(t *testing.T) {
+	one := &debateAgent{testAgent: testAgent{name: testMemberNameOne},
+		reply: func(input string) string {
+			if strings.Contains(input, "Critique") {
+				return "42"
+			}
+			return "41"
+		}}
+	two := &debateAgent{testAgent: testAgent{name: testMemberNameTwo},
+		reply: func(string) string { return "42" }}
+	judge := &debateAgent{testAgent: testAgent{name: testJudgeName},
+		reply: func(string) string { return "the answer is 42" }}
+
+	tm, err := NewDebate(testTeamName, []agent.Agent{one, two},
+		WithDebateConfig(DebateConfig{Rounds: 1, Judge: judge}))
+	require.NoError(t, err)
+
+	author, final := runDebateTeam(t, tm)
+	require.Equal(t, testJudgeName, author)
+	require.Equal(t, "the answer is 42", final)
+
+	require.Len(t, one.inputs, 2)
+	require.Equal(t, "what is 6*7?", one.inputs[0])
+	require.Contains(t, one.inputs[1], "Your answer:\n41")
+	require.Contains(t, one.inputs[1], "["+testMemberNameTwo+"]\n42")
+	require.NotEqual(t, one.keys[0], two.keys[0])
+	require.Len(t, judge.inputs, 1)
+	require.Contains(t, judge.inputs[0], "["+testMemberNameOne+"]\n42")
+}
+
+func TestDebate_MajorityVote(t *testing.T) {
+	member := func(name, answer, vote string) *debateAgent {
+		return &debateAgent{testAgent: testAgent{name: name},
+			reply: func(input string) string {
+				if strings.Contains(input, debateVotePrefix) {
+					return "best one\n" + debateVotePrefix + " " + vote
+				}
+				return answer
+			}}
+	}
+	tm, err := NewDebate(testTeamName, []agent.Agent{
+		member("a", "answer a", "b"),
+		member("b", "answer b", "a"),
+		member("c", "answer c", "b"),
+	}, WithDebateConfig(DebateConfig{}))
+	require.NoError(t, err)
+
+	author, final := runDebateTeam(t, tm)
+	require.Equal(t, testTeamName, author)
+	require.Equal(t, "answer b", final)
+}
+
+func TestDebate_TokenBudgetSkipsRounds(t *testing.T) {
+	newMember := func(name string) *debateAgent {
+		return &debateAgent{testAgent: testAgent{name: name}, tokens: 60,
+			reply: func(string) string { return name }}
+	}
+	one, two := newMember(testMemberNameOne), newMember(testMemberNameTwo)
+	judge := &debateAgent{testAgent: testAgent{name: testJudgeName},
+		reply: func(string) string { return "done" }}
+	tm, err := NewDebate(testTeamName, []agent.Agent{one, two},
+		WithDebateConfig(DebateConfig{Rounds: 3, MaxTokens: 100, Judge: judge}))
+	require.NoError(t, err)
+
+	_, final := runDebateTeam(t, tm)
+	require.Equal(t, "done", final)
+	require.Len(t, one.inputs, 1)
+	require.Len(t, two.inputs, 1)
+}
+
+func TestNewDebate_Validation(t *testing.T) {
+	one, two := testAgent{name: testMemberNameOne}, testAgent{name: testMemberNameTwo}
+
+	_, err := NewDebate("", []agent.Agent{one, two})
+	require.Error(t, err)
+	_, err = NewDebate(testTeamName, []agent.Agent{one})
+	require.ErrorContains(t, err, "at least two members")
+	_, err = NewDebate(testTeamName, []agent.Agent{one, two},
+		WithDebateConfig(DebateConfig{Rounds: -1}))
+	require.ErrorContains(t, err, "must not be negative")
+	_, err = NewDebate(testTeamName, []agent.Agent{one, two},
+		WithDebateConfig(DebateConfig{Judge: one}))
+	require.ErrorContains(t, err, "conflicts with the team")
+
+	tm, err := NewDebate(testTeamName, []agent.Agent{one, two})
+	require.NoError(t, err)
+	require.NotNil(t, tm.debate.Voting)
+	require.Nil(t, tm.Tools())
+}
+
+func TestMajorityVote_TieGoesToFirst(t *testing.T) {
+	candidates := []string{"a", "b", "c"}
+	require.Equal(t, "b", MajorityVote(candidates,
+		map[string]string{"a": "c", "c": "b"}))
+	require.Equal(t, "", MajorityVote(candidates, nil))
+	require.Equal(t, "a", parseVote("a is right\n"+debateVotePrefix+" **a**"))
+}
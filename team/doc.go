@@ -16,6 +16,10 @@
 //   - In "coordinator" mode, a coordinator agent calls member agents as
 //     tools.
 //   - In "swarm" mode, members hand off to each other via transfer_to_agent.
+//   - In "task board" mode, the coordinator writes tasks with dependencies
+//     to a shared board and members claim and run ready tasks in parallel.
+//   - In "debate" mode, members answer and critique each other for a few
+//     rounds before a judge or a vote decides.
 //
 // This package focuses on clear composition and safe defaults rather than a
 // large surface area.
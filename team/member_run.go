@@ -0,0 +1,72 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+)
+
+// memberResult is the outcome of one member run.
+type memberResult struct {
+	// text is the last complete response of the member.
+	text string
+	// tokens is the total token usage reported by the member's responses.
+	tokens int
+}
+
+// runMember runs sub on inv, forwards its events to ch and returns its
+// final answer. It is used by the modes that drive members themselves
+// instead of exposing them as tools.
+func runMember(
+	ctx context.Context,
+	ch chan<- *event.Event,
+	sub agent.Agent,
+	inv *agent.Invocation,
+) (memberResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := agent.RunWithPlugins(
+		agent.NewInvocationContext(ctx, inv), inv, sub)
+	if err != nil {
+		return memberResult{}, err
+	}
+	var res memberResult
+	var runErr error
+	for evt := range events {
+		if err := event.EmitEvent(ctx, ch, evt); err != nil {
+			// Stop the member and drain its events so that it does not
+			// block forever on a send nobody receives.
+			cancel()
+			for range events {
+			}
+			return res, err
+		}
+		if evt == nil || evt.Response == nil || evt.IsPartial {
+			continue
+		}
+		if evt.Usage != nil {
+			res.tokens += evt.Usage.TotalTokens
+		}
+		if evt.Error != nil && runErr == nil {
+			runErr = fmt.Errorf("%s: %s", evt.Error.Type, evt.Error.Message)
+			continue
+		}
+		if evt.Author == inv.AgentName && len(evt.Choices) > 0 &&
+			evt.Choices[0].Message.Content != "" {
+			res.text = evt.Choices[0].Message.Content
+		}
+	}
+	return res, runErr
+}
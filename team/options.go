@@ -20,6 +20,8 @@ type options struct {
 	swarm             SwarmConfig
 	swarmHandoff      swarmHandoffPolicy
 	swarmHandoffInput SwarmHandoffInputBuilder
+	taskBoard         TaskBoardConfig
+	debate            DebateConfig
 }
 
 // HistoryScope controls whether and how member AgentTools inherit parent
@@ -218,6 +220,25 @@ func WithSwarmHandoffInputBuilder(builder SwarmHandoffInputBuilder) Option {
 	}
 }
 
+// WithTaskBoardConfig sets the limits of a task board team.
+//
+// This only applies to task board teams.
+func WithTaskBoardConfig(cfg TaskBoardConfig) Option {
+	return func(o *options) {
+		o.taskBoard = cfg
+	}
+}
+
+// WithDebateConfig sets the rounds, token budget and decision of a debate
+// team.
+//
+// This only applies to debate teams.
+func WithDebateConfig(cfg DebateConfig) Option {
+	return func(o *options) {
+		o.debate = cfg
+	}
+}
+
 const (
 	defaultMemberToolSetNamePrefix = "team-members-"
 
@@ -230,7 +251,9 @@ func defaultOptions(teamName string) options {
 			name:         defaultMemberToolSetNamePrefix + teamName,
 			historyScope: defaultMemberToolHistoryScope,
 		},
-		swarm: DefaultSwarmConfig(),
+		swarm:     DefaultSwarmConfig(),
+		taskBoard: DefaultTaskBoardConfig(),
+		debate:    DefaultDebateConfig(),
 	}
 }
 
@@ -28,6 +28,7 @@ func (t *Team) Export(
 	coordinator := t.coordinator
 	entryName := t.entryName
 	members := append([]agent.Agent(nil), t.members...)
+	judge := t.debate.Judge
 	t.mu.RUnlock()
 
 	rootNodeID := istructure.EscapeLocalName(name)
@@ -42,7 +43,7 @@ func (t *Team) Export(
 		},
 	}
 	switch mode {
-	case ModeCoordinator:
+	case ModeCoordinator, ModeTaskBoard:
 		return exportCoordinatorTeam(
 			ctx,
 			exportChild,
@@ -51,6 +52,15 @@ func (t *Team) Export(
 			coordinator,
 			members,
 		)
+	case ModeDebate:
+		return exportDebateTeam(
+			ctx,
+			exportChild,
+			snapshot,
+			rootNodeID,
+			members,
+			judge,
+		)
 	case ModeSwarm:
 		return exportSwarmTeam(
 			ctx,
@@ -117,6 +127,62 @@ func exportCoordinatorTeam(
 	return snapshot, nil
 }
 
+// exportDebateTeam links the root to every member, and the members to the
+// judge when one is set.
+func exportDebateTeam(
+	ctx context.Context,
+	exportChild structure.ChildExporter,
+	snapshot *structure.Snapshot,
+	rootNodeID string,
+	members []agent.Agent,
+	judge agent.Agent,
+) (*structure.Snapshot, error) {
+	allocator := istructure.NewPathAllocator(rootNodeID)
+	export := func(a agent.Agent) (*structure.Snapshot, error) {
+		childSnapshot, err := exportChild(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		rebased, err := istructure.RebaseSnapshot(
+			childSnapshot,
+			allocator.Next(a.Info().Name),
+		)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Nodes = append(snapshot.Nodes, rebased.Nodes...)
+		snapshot.Edges = append(snapshot.Edges, rebased.Edges...)
+		snapshot.Surfaces = append(snapshot.Surfaces, rebased.Surfaces...)
+		return rebased, nil
+	}
+	memberEntries := make([]string, 0, len(members))
+	for _, member := range members {
+		rebasedMember, err := export(member)
+		if err != nil {
+			return nil, err
+		}
+		memberEntries = append(memberEntries, rebasedMember.EntryNodeID)
+		snapshot.Edges = append(snapshot.Edges, structure.Edge{
+			FromNodeID: rootNodeID,
+			ToNodeID:   rebasedMember.EntryNodeID,
+		})
+	}
+	if judge == nil {
+		return snapshot, nil
+	}
+	rebasedJudge, err := export(judge)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range memberEntries {
+		snapshot.Edges = append(snapshot.Edges, structure.Edge{
+			FromNodeID: entry,
+			ToNodeID:   rebasedJudge.EntryNodeID,
+		})
+	}
+	return snapshot, nil
+}
+
 func exportSwarmTeam(
 	ctx context.Context,
 	exportChild structure.ChildExporter,
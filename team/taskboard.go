@@ -0,0 +1,255 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+)
+
+// TaskBoardKeyPrefix is the session state key prefix under which a task
+// board team stores its board. The full key is TaskBoardKeyPrefix +
+// teamName.
+//
+// The board is written through state delta events, so it is persisted
+// with the session and an interrupted run resumes from it.
+const TaskBoardKeyPrefix = "team_task_board:"
+
+// TaskBoardKey returns the session state key of the board of teamName.
+func TaskBoardKey(teamName string) string {
+	return TaskBoardKeyPrefix + teamName
+}
+
+// TaskStatus is the lifecycle state of a board task.
+type TaskStatus string
+
+// Task statuses.
+const (
+	TaskStatusPending    TaskStatus = "pending"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusCompleted  TaskStatus = "completed"
+	// TaskStatusBlocked marks tasks whose member reported a blocker or
+	// failed. Only the coordinator can retry or cancel them.
+	TaskStatusBlocked   TaskStatus = "blocked"
+	TaskStatusCancelled TaskStatus = "cancelled"
+)
+
+// Task is a unit of work on the board.
+type Task struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+	// Assignee restricts the task to one member. Empty means any idle
+	// member may claim it.
+	Assignee string     `json:"assignee,omitempty"`
+	Status   TaskStatus `json:"status"`
+	// ClaimedBy is the member that ran the task last.
+	ClaimedBy string `json:"claimed_by,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Blocker   string `json:"blocker,omitempty"`
+}
+
+// TaskBoard is the shared board of a task board team.
+type TaskBoard struct {
+	// Round counts coordinator turns of the current job.
+	Round int    `json:"round"`
+	Tasks []Task `json:"tasks"`
+	// Done is set once the coordinator answered with no open tasks left.
+	Done bool `json:"done"`
+}
+
+// GetTaskBoard returns the board teamName stored in sess, if any.
+func GetTaskBoard(sess *session.Session, teamName string) (*TaskBoard, bool) {
+	if sess == nil {
+		return nil, false
+	}
+	raw, ok := sess.GetState(TaskBoardKey(teamName))
+	if !ok || len(raw) == 0 {
+		return nil, false
+	}
+	var b TaskBoard
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, false
+	}
+	return &b, true
+}
+
+// TaskBoardConfig bounds a task board team.
+type TaskBoardConfig struct {
+	// MaxTasks limits how many tasks the board may hold.
+	MaxTasks int
+
+	// MaxRounds limits how many times the coordinator is consulted in a
+	// single run: once to plan, then whenever the board completes or
+	// stalls on blocked tasks.
+	MaxRounds int
+
+	// TaskTimeout limits a single member task. Zero means no limit.
+	TaskTimeout time.Duration
+}
+
+// DefaultTaskBoardConfig returns the defaults used by NewTaskBoard.
+func DefaultTaskBoardConfig() TaskBoardConfig {
+	return TaskBoardConfig{
+		MaxTasks:  50,
+		MaxRounds: 8,
+	}
+}
+
+// NewTaskBoard creates a task board team.
+//
+// The coordinator decomposes the request into tasks with dependencies on a
+// board kept in session state. Idle members then claim ready tasks and
+// work on them concurrently. A member's final answer becomes the task
+// result; members that support dynamic tool sets can also report a blocker
+// instead. Whenever the board completes or stalls, the coordinator is
+// consulted again: it can add, retry or cancel tasks, and the run ends
+// when it answers with no open tasks left.
+//
+// Like New, the coordinator must support dynamic tool sets, and the
+// created Team uses coordinator.Info().Name as its own name.
+func NewTaskBoard(
+	coordinator agent.Agent,
+	members []agent.Agent,
+	opts ...Option,
+) (*Team, error) {
+	if coordinator == nil {
+		return nil, errNilCoordinator
+	}
+	name := coordinator.Info().Name
+	if name == "" {
+		return nil, errEmptyTeamName
+	}
+
+	cfg := defaultOptions(name)
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	memberByName, err := buildMemberIndex(name, members)
+	if err != nil {
+		return nil, err
+	}
+	adder, ok := coordinator.(toolSetAdder)
+	if !ok {
+		return nil, errors.New(
+			"coordinator does not support AddToolSet",
+		)
+	}
+	adder.AddToolSet(newCoordinatorBoardToolSet(name))
+	for _, m := range members {
+		if memberAdder, ok := m.(toolSetAdder); ok {
+			memberAdder.AddToolSet(newMemberBoardToolSet(name))
+		}
+	}
+
+	return &Team{
+		name:         name,
+		description:  cfg.description,
+		mode:         ModeTaskBoard,
+		coordinator:  coordinator,
+		members:      members,
+		memberByName: memberByName,
+		taskBoard:    cfg.taskBoard,
+	}, nil
+}
+
+func (b *TaskBoard) task(id string) *Task {
+	for i := range b.Tasks {
+		if b.Tasks[i].ID == id {
+			return &b.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// ready reports whether t can be claimed by member.
+func (b *TaskBoard) ready(t *Task, member string) bool {
+	if t.Status != TaskStatusPending {
+		return false
+	}
+	if t.Assignee != "" && t.Assignee != member {
+		return false
+	}
+	for _, dep := range t.DependsOn {
+		if d := b.task(dep); d == nil || d.Status != TaskStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// add validates tasks and appends them. Dependencies must name tasks that
+// are already on the board or earlier in tasks, which keeps the board
+// acyclic.
+func (b *TaskBoard) add(
+	tasks []Task,
+	members map[string]agent.Agent,
+	maxTasks int,
+) ([]string, error) {
+	if maxTasks > 0 && len(b.Tasks)+len(tasks) > maxTasks {
+		return nil, fmt.Errorf("board is limited to %d tasks", maxTasks)
+	}
+	known := make(map[string]bool, len(b.Tasks)+len(tasks))
+	for _, t := range b.Tasks {
+		known[t.ID] = true
+	}
+	next := len(b.Tasks) + 1
+	added := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.Title == "" {
+			return nil, errors.New("task title is empty")
+		}
+		if t.ID == "" {
+			for t.ID == "" || known[t.ID] {
+				t.ID = "t" + strconv.Itoa(next)
+				next++
+			}
+		}
+		if known[t.ID] {
+			return nil, fmt.Errorf("duplicate task id %q", t.ID)
+		}
+		for _, dep := range t.DependsOn {
+			if !known[dep] {
+				return nil, fmt.Errorf(
+					"task %q depends on unknown task %q", t.ID, dep)
+			}
+		}
+		if t.Assignee != "" && members[t.Assignee] == nil {
+			return nil, fmt.Errorf(
+				"task %q is assigned to unknown member %q",
+				t.ID, t.Assignee)
+		}
+		known[t.ID] = true
+		added = append(added, Task{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			DependsOn:   t.DependsOn,
+			Assignee:    t.Assignee,
+			Status:      TaskStatusPending,
+		})
+	}
+	b.Tasks = append(b.Tasks, added...)
+	ids := make([]string, len(added))
+	for i, t := range added {
+		ids[i] = t.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,360 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+const defaultTaskBoardChannelBufferSize = 256
+
+// boardRun is the state of one task board run. Coordinator and member
+// tools find it through the invocation runtime state.
+type boardRun struct {
+	team *Team
+	inv  *agent.Invocation
+	ch   chan *event.Event
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	board TaskBoard
+	// running maps members to the task they work on.
+	running map[string]string
+	// blockers holds blockers reported for running tasks.
+	blockers map[string]string
+	restored bool
+
+	emitMu sync.Mutex
+}
+
+func (t *Team) runTaskBoard(
+	ctx context.Context,
+	invocation *agent.Invocation,
+) (<-chan *event.Event, error) {
+	if t.coordinator == nil {
+		return nil, errNilCoordinator
+	}
+	size := defaultTaskBoardChannelBufferSize
+	if s := agent.GetEventChannelBufferSize(invocation); s > 0 {
+		size = s
+	}
+	r := &boardRun{
+		team:     t,
+		inv:      invocation,
+		ch:       make(chan *event.Event, size),
+		running:  make(map[string]string),
+		blockers: make(map[string]string),
+	}
+	r.cond = sync.NewCond(&r.mu)
+	r.restore()
+
+	key := taskBoardRuntimeKeyPrefix + t.name
+	cloneRuntimeStateForSwarm(&invocation.RunOptions)
+	invocation.RunOptions.RuntimeState[key] = r
+
+	runCtx := agent.CloneContext(ctx)
+	go func() {
+		defer close(r.ch)
+		ctx := agent.NewInvocationContext(runCtx, invocation)
+		if err := r.execute(ctx); err != nil {
+			log.WarnfContext(ctx, "team %s: task board: %v", t.name, err)
+			agent.EmitEvent(ctx, invocation, r.ch, event.NewErrorEvent(
+				invocation.InvocationID, t.name,
+				model.ErrorTypeFlowError, err.Error()))
+		}
+	}()
+	return r.ch, nil
+}
+
+// restore resumes an unfinished board from session state. Tasks that were
+// running when the previous run stopped are claimable again.
+func (r *boardRun) restore() {
+	b, ok := GetTaskBoard(r.inv.Session, r.team.name)
+	if !ok || b.Done {
+		return
+	}
+	for i := range b.Tasks {
+		if b.Tasks[i].Status == TaskStatusInProgress {
+			b.Tasks[i].Status = TaskStatusPending
+		}
+	}
+	b.Round = 0
+	r.board = *b
+	r.restored = len(b.Tasks) > 0
+}
+
+// execute alternates coordinator rounds and member work until the
+// coordinator ends a round with no runnable task left. Its response in
+// that round is the answer of the team.
+func (r *boardRun) execute(ctx context.Context) error {
+	limit := r.team.taskBoard.MaxRounds
+	for {
+		r.mu.Lock()
+		r.board.Round++
+		round := r.board.Round
+		r.mu.Unlock()
+		if limit > 0 && round > limit {
+			return fmt.Errorf(
+				"board not finished after %d coordinator rounds", limit)
+		}
+		if err := r.runCoordinator(ctx, r.coordinatorInput(round)); err != nil {
+			return err
+		}
+		if !r.runnable() {
+			r.mu.Lock()
+			r.board.Done = true
+			r.mu.Unlock()
+			return r.emitBoard(ctx)
+		}
+		if err := r.emitBoard(ctx); err != nil {
+			return err
+		}
+		r.work(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *boardRun) coordinatorInput(round int) model.Message {
+	if round == 1 && !r.restored {
+		return r.inv.Message
+	}
+	board, _ := json.MarshalIndent(r.snapshot(), "", "  ")
+	var b strings.Builder
+	switch {
+	case round == 1:
+		b.WriteString(r.inv.Message.Content)
+		b.WriteString("\n\nAn unfinished task board was restored. Continue, " +
+			"retry or cancel its tasks as the request requires.")
+	case r.hasBlocked():
+		b.WriteString("The task board stalled: some tasks are blocked. " +
+			"Retry them with guidance, cancel them, add tasks, or " +
+			"answer the user if the job cannot be completed.")
+	default:
+		b.WriteString("All runnable tasks are finished. Answer the user " +
+			"from the task results, or add tasks if more work is needed.")
+	}
+	b.WriteString("\n\nTask board:\n")
+	b.Write(board)
+	return model.NewUserMessage(b.String())
+}
+
+func (r *boardRun) runCoordinator(ctx context.Context, msg model.Message) error {
+	inv := r.inv.Clone(
+		agent.WithInvocationAgent(r.team.coordinator),
+		agent.WithInvocationMessage(msg),
+		agent.WithInvocationBranch(r.inv.Branch),
+	)
+	_, err := runMember(ctx, r.ch, r.team.coordinator, inv)
+	return err
+}
+
+// work lets every member claim and run ready tasks until none is left.
+func (r *boardRun) work(ctx context.Context) {
+	stop := context.AfterFunc(ctx, r.wake)
+	defer stop()
+	var wg sync.WaitGroup
+	for _, m := range r.team.members {
+		wg.Add(1)
+		go func(m agent.Agent) {
+			defer wg.Done()
+			r.worker(ctx, m)
+		}(m)
+	}
+	wg.Wait()
+}
+
+func (r *boardRun) worker(ctx context.Context, m agent.Agent) {
+	name := m.Info().Name
+	for {
+		id, ok := r.claim(ctx, name)
+		if !ok {
+			return
+		}
+		_ = r.emitBoard(ctx)
+		result, err := r.runTask(ctx, m, id)
+		r.finish(name, id, result, err)
+		_ = r.emitBoard(ctx)
+	}
+}
+
+// claim blocks until a task is ready for member, or returns false once no
+// task can become ready because nothing is running any more.
+func (r *boardRun) claim(ctx context.Context, member string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for ctx.Err() == nil {
+		busy := false
+		for i := range r.board.Tasks {
+			t := &r.board.Tasks[i]
+			if r.board.ready(t, member) {
+				t.Status = TaskStatusInProgress
+				t.ClaimedBy = member
+				t.Attempts++
+				r.running[member] = t.ID
+				return t.ID, true
+			}
+			if t.Status == TaskStatusInProgress {
+				busy = true
+			}
+		}
+		if !busy {
+			return "", false
+		}
+		r.cond.Wait()
+	}
+	return "", false
+}
+
+func (r *boardRun) runTask(
+	ctx context.Context,
+	m agent.Agent,
+	id string,
+) (string, error) {
+	if timeout := r.team.taskBoard.TaskTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	inv := r.inv.Clone(
+		agent.WithInvocationAgent(m),
+		agent.WithInvocationMessage(model.NewUserMessage(r.taskInput(id))),
+	)
+	res, err := runMember(ctx, r.ch, m, inv)
+	return res.text, err
+}
+
+func (r *boardRun) taskInput(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.board.task(id)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task %s: %s\n", t.ID, t.Title)
+	if t.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", t.Description)
+	}
+	if len(t.DependsOn) > 0 {
+		b.WriteString("\nResults of the tasks this one depends on:\n")
+		for _, dep := range t.DependsOn {
+			d := r.board.task(dep)
+			fmt.Fprintf(&b, "\n[%s] %s\n%s\n", d.ID, d.Title, d.Result)
+		}
+	}
+	b.WriteString("\nComplete the task and reply with its result.")
+	return b.String()
+}
+
+func (r *boardRun) finish(member, id, result string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer r.cond.Broadcast()
+	delete(r.running, member)
+	blocker, blocked := r.blockers[member]
+	delete(r.blockers, member)
+	t := r.board.task(id)
+	t.Result = result
+	switch {
+	case blocked:
+		t.Status = TaskStatusBlocked
+		t.Blocker = blocker
+	case err != nil:
+		t.Status = TaskStatusBlocked
+		t.Blocker = err.Error()
+	default:
+		t.Status = TaskStatusCompleted
+	}
+}
+
+func (r *boardRun) reportBlocker(member, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.running[member]; !ok {
+		return fmt.Errorf("member %q has no task in progress", member)
+	}
+	r.blockers[member] = reason
+	return nil
+}
+
+func (r *boardRun) wake() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// runnable reports whether some member can claim a task.
+func (r *boardRun) runnable() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.board.Tasks {
+		t := &r.board.Tasks[i]
+		if r.board.ready(t, t.Assignee) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *boardRun) hasBlocked() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.board.Tasks {
+		if t.Status == TaskStatusBlocked {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *boardRun) snapshot() TaskBoard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cloneBoardLocked()
+}
+
+func (r *boardRun) cloneBoardLocked() TaskBoard {
+	b := r.board
+	b.Tasks = append([]Task(nil), r.board.Tasks...)
+	return b
+}
+
+// emitBoard stores the board in session state and emits it as a task board
+// event.
+func (r *boardRun) emitBoard(ctx context.Context) error {
+	r.emitMu.Lock()
+	defer r.emitMu.Unlock()
+	raw, err := json.Marshal(r.snapshot())
+	if err != nil {
+		return fmt.Errorf("encode task board: %w", err)
+	}
+	key := TaskBoardKey(r.team.name)
+	evt := event.New(r.inv.InvocationID, r.team.name,
+		event.WithObject(model.ObjectTypeTaskBoard),
+		event.WithStateDelta(map[string][]byte{key: raw}),
+		event.WithProgress(model.ObjectTypeTaskBoard, map[string]any{
+			"team":  r.team.name,
+			"board": json.RawMessage(raw),
+		}),
+	)
+	if r.inv.Session != nil {
+		r.inv.Session.SetState(key, raw)
+	}
+	return agent.EmitEvent(ctx, r.inv, r.ch, evt)
+}
@@ -0,0 +1,215 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+	"trpc.group/trpc-go/trpc-agent-go/runner"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	sessioninmemory "trpc.group/trpc-go/trpc-agent-go/session/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// boardAgent is a scripted agent that can call the board tools it was
+// given.
+type boardAgent struct {
+	testAgent
+	mu     sync.Mutex
+	sets   []tool.ToolSet
+	inputs []string
+	script func(ctx context.Context, a *boardAgent, input string) string
+}
+
+func (a *boardAgent) AddToolSet(ts tool.ToolSet) { a.sets = append(a.sets, ts) }
+
+func (a *boardAgent) call(ctx context.Context, name, args string) (any, error) {
+	for _, ts := range a.sets {
+		for _, tl := range ts.Tools(ctx) {
+			if tl.Declaration().Name == name {
+				return tl.(tool.CallableTool).Call(ctx, []byte(args))
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (a *boardAgent) Run(
+	ctx context.Context,
+	inv *agent.Invocation,
+) (<-chan *event.Event, error) {
+	a.mu.Lock()
+	a.inputs = append(a.inputs, inv.Message.Content)
+	a.mu.Unlock()
+	ch := make(chan *event.Event, 1)
+	go func() {
+		defer close(ch)
+		text := a.script(ctx, a, inv.Message.Content)
+		ch <- event.NewResponseEvent(inv.InvocationID, a.name, &model.Response{
+			Done:    true,
+			Choices: []model.Choice{{Message: model.NewAssistantMessage(text)}},
+		})
+	}()
+	return ch, nil
+}
+
+func TestTaskBoard_RunsTasksConcurrently(t *testing.T) {
+	// Tasks t1 and t2 only finish once both are running, so the test
+	// deadlocks unless members work in parallel.
+	var started sync.WaitGroup
+	started.Add(2)
+	blockedOnce := false
+	member := func(name string) *boardAgent {
+		return &boardAgent{
+			testAgent: testAgent{name: name},
+			script: func(ctx context.Context, a *boardAgent, input string) string {
+				task := strings.Fields(input)[1]
+				switch task {
+				case "t1:", "t2:":
+					if !strings.Contains(input, "Coordinator note") {
+						started.Done()
+						started.Wait()
+					}
+					if task == "t2:" && !blockedOnce {
+						blockedOnce = true
+						_, err := a.call(ctx, toolBoardReportBlocker,
+							`{"reason":"need a source"}`)
+						require.NoError(t, err)
+						return "no source"
+					}
+				case "t3:":
+					require.Contains(t, input, "[t1] research\nresult t1:")
+					require.Contains(t, input, "[t2] draft\nresult t2:")
+				}
+				return "result " + task
+			},
+		}
+	}
+	one, two := member(testMemberNameOne), member(testMemberNameTwo)
+
+	coordinator := &boardAgent{
+		testAgent: testAgent{name: testCoordinatorName},
+		script: func(ctx context.Context, a *boardAgent, input string) string {
+			switch len(a.inputs) {
+			case 1:
+				_, err := a.call(ctx, toolBoardAdd, `{"tasks":[
+					{"title":"research"},
+					{"title":"draft"},
+					{"title":"review","depends_on":["t1","t2"]}]}`)
+				require.NoError(t, err)
+				return "planned"
+			case 2:
+				require.Contains(t, input, "some tasks are blocked")
+				_, err := a.call(ctx, toolBoardUpdate,
+					`{"id":"t2","action":"retry","note":"use the wiki"}`)
+				require.NoError(t, err)
+				return "retrying"
+			default:
+				require.Contains(t, input, "All runnable tasks are finished")
+				return "final answer"
+			}
+		},
+	}
+
+	tm, err := NewTaskBoard(coordinator, []agent.Agent{one, two})
+	require.NoError(t, err)
+	require.Len(t, tm.Tools(), 0)
+
+	service := sessioninmemory.NewSessionService()
+	r := runner.NewRunner(testAppName, tm, runner.WithSessionService(service))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := r.Run(ctx, testUserID, testSessionID,
+		model.NewUserMessage("write a report"))
+	require.NoError(t, err)
+
+	var boards int
+	var final string
+	for evt := range events {
+		require.Nil(t, evt.Error)
+		if evt.Object == model.ObjectTypeTaskBoard {
+			boards++
+			pg, ok := event.GetProgress(evt)
+			require.True(t, ok)
+			require.JSONEq(t, `"`+testTeamName+`"`, string(pg.Data["team"]))
+		}
+		if evt.Author == testCoordinatorName && len(evt.Choices) > 0 {
+			final = evt.Choices[0].Message.Content
+		}
+	}
+	require.Equal(t, "final answer", final)
+	require.Greater(t, boards, 6)
+
+	sess, err := service.GetSession(ctx, session.Key{
+		AppName: testAppName, UserID: testUserID, SessionID: testSessionID,
+	})
+	require.NoError(t, err)
+	board, ok := GetTaskBoard(sess, testTeamName)
+	require.True(t, ok)
+	require.True(t, board.Done)
+	require.Equal(t, 3, board.Round)
+	require.Len(t, board.Tasks, 3)
+	for _, task := range board.Tasks {
+		require.Equal(t, TaskStatusCompleted, task.Status, task.ID)
+	}
+	require.Equal(t, 2, board.Tasks[1].Attempts)
+	require.Contains(t, board.Tasks[1].Description, "use the wiki")
+}
+
+func TestTaskBoard_AddValidation(t *testing.T) {
+	members := map[string]agent.Agent{testMemberNameOne: testAgent{}}
+	var b TaskBoard
+	ids, err := b.add([]Task{{Title: "a"}, {ID: "x", Title: "b", DependsOn: []string{"t1"}}}, members, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"t1", "x"}, ids)
+
+	_, err = b.add([]Task{{Title: "c", DependsOn: []string{"later"}}, {ID: "later", Title: "d"}}, members, 0)
+	require.ErrorContains(t, err, `depends on unknown task "later"`)
+	_, err = b.add([]Task{{Title: "c", Assignee: "nobody"}}, members, 0)
+	require.ErrorContains(t, err, `unknown member "nobody"`)
+	_, err = b.add([]Task{{ID: "x", Title: "c"}}, members, 0)
+	require.ErrorContains(t, err, `duplicate task id "x"`)
+	_, err = b.add([]Task{{Title: "c"}, {Title: "d"}}, members, 3)
+	require.ErrorContains(t, err, "limited to 3 tasks")
+	require.Len(t, b.Tasks, 2)
+}
+
+func TestTaskBoard_RestoresUnfinishedBoard(t *testing.T) {
+	sess := session.NewSession(testAppName, testUserID, testSessionID)
+	tm, err := NewTaskBoard(
+		&boardAgent{testAgent: testAgent{name: testCoordinatorName}},
+		[]agent.Agent{testAgent{name: testMemberNameOne}},
+		WithTaskBoardConfig(TaskBoardConfig{MaxRounds: 1}),
+	)
+	require.NoError(t, err)
+	sess.SetState(TaskBoardKey(testTeamName), []byte(
+		`{"round":2,"tasks":[{"id":"t1","title":"a","status":"in_progress"}]}`))
+
+	r := &boardRun{team: tm, inv: &agent.Invocation{
+		Session: sess, Message: model.NewUserMessage("go on"),
+	}}
+	r.restore()
+	require.True(t, r.restored)
+	require.Equal(t, TaskStatusPending, r.board.Tasks[0].Status)
+	require.True(t, r.runnable())
+	msg := r.coordinatorInput(1)
+	require.Contains(t, msg.Content, "go on")
+	require.Contains(t, msg.Content, "unfinished task board was restored")
+}
@@ -0,0 +1,219 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+const (
+	taskBoardRuntimeKeyPrefix = "__team_task_board__:"
+
+	taskBoardToolSetNamePrefix = "team-task-board-"
+
+	toolBoardAdd           = "task_board_add"
+	toolBoardView          = "task_board_view"
+	toolBoardUpdate        = "task_board_update"
+	toolBoardReportBlocker = "task_board_report_blocker"
+
+	boardActionRetry  = "retry"
+	boardActionCancel = "cancel"
+)
+
+var errBoardInactive = errors.New("task board is not active")
+
+type boardTaskInput struct {
+	ID          string   `json:"id,omitempty" jsonschema:"description=Optional task id; generated when empty"`
+	Title       string   `json:"title" jsonschema:"description=Short task title,required"`
+	Description string   `json:"description,omitempty" jsonschema:"description=What the member should do and deliver"`
+	DependsOn   []string `json:"depends_on,omitempty" jsonschema:"description=Ids of tasks that must complete first"`
+	Assignee    string   `json:"assignee,omitempty" jsonschema:"description=Optional member name; any member may claim the task when empty"`
+}
+
+type boardAddInput struct {
+	Tasks []boardTaskInput `json:"tasks" jsonschema:"description=Tasks to add in dependency order,required"`
+}
+
+type boardUpdateInput struct {
+	ID     string `json:"id" jsonschema:"description=Task id,required"`
+	Action string `json:"action" jsonschema:"description=retry or cancel,required"`
+	Note   string `json:"note,omitempty" jsonschema:"description=Guidance appended to the task description on retry"`
+}
+
+type boardBlockerInput struct {
+	Reason string `json:"reason" jsonschema:"description=Why the current task cannot be completed,required"`
+}
+
+type boardViewInput struct{}
+
+type boardOutput struct {
+	Message string    `json:"message,omitempty"`
+	Board   TaskBoard `json:"board"`
+}
+
+func boardRunFromContext(
+	ctx context.Context,
+	teamName string,
+) (*boardRun, error) {
+	r, ok := agent.GetRuntimeStateValueFromContext[*boardRun](
+		ctx,
+		taskBoardRuntimeKeyPrefix+teamName,
+	)
+	if !ok || r == nil {
+		return nil, errBoardInactive
+	}
+	return r, nil
+}
+
+func newCoordinatorBoardToolSet(teamName string) tool.ToolSet {
+	add := func(ctx context.Context, in boardAddInput) (boardOutput, error) {
+		r, err := boardRunFromContext(ctx, teamName)
+		if err != nil {
+			return boardOutput{}, err
+		}
+		tasks := make([]Task, len(in.Tasks))
+		for i, t := range in.Tasks {
+			tasks[i] = Task{
+				ID:          t.ID,
+				Title:       t.Title,
+				Description: t.Description,
+				DependsOn:   t.DependsOn,
+				Assignee:    t.Assignee,
+			}
+		}
+		return r.addTasks(tasks)
+	}
+	view := func(ctx context.Context, _ boardViewInput) (boardOutput, error) {
+		r, err := boardRunFromContext(ctx, teamName)
+		if err != nil {
+			return boardOutput{}, err
+		}
+		return boardOutput{Board: r.snapshot()}, nil
+	}
+	update := func(ctx context.Context, in boardUpdateInput) (boardOutput, error) {
+		r, err := boardRunFromContext(ctx, teamName)
+		if err != nil {
+			return boardOutput{}, err
+		}
+		return r.updateTask(in.ID, in.Action, in.Note)
+	}
+	return &staticToolSet{
+		name: taskBoardToolSetNamePrefix + teamName,
+		tools: []tool.Tool{
+			function.NewFunctionTool(add,
+				function.WithName(toolBoardAdd),
+				function.WithDescription(
+					"Add tasks to the team task board. Members claim "+
+						"ready tasks and work on them in parallel once "+
+						"your turn ends."),
+			),
+			function.NewFunctionTool(view,
+				function.WithName(toolBoardView),
+				function.WithDescription(
+					"Show the team task board with task status, "+
+						"results and blockers."),
+			),
+			function.NewFunctionTool(update,
+				function.WithName(toolBoardUpdate),
+				function.WithDescription(
+					"Retry a blocked task, optionally with guidance, "+
+						"or cancel a task that is no longer needed."),
+			),
+		},
+	}
+}
+
+func newMemberBoardToolSet(teamName string) tool.ToolSet {
+	report := func(ctx context.Context, in boardBlockerInput) (boardOutput, error) {
+		r, err := boardRunFromContext(ctx, teamName)
+		if err != nil {
+			return boardOutput{}, err
+		}
+		inv, ok := agent.InvocationFromContext(ctx)
+		if !ok || inv == nil {
+			return boardOutput{}, errBoardInactive
+		}
+		if err := r.reportBlocker(inv.AgentName, in.Reason); err != nil {
+			return boardOutput{}, err
+		}
+		return boardOutput{
+			Message: "Blocker recorded. Stop working on the task and " +
+				"summarize what you found.",
+		}, nil
+	}
+	return &staticToolSet{
+		name: taskBoardToolSetNamePrefix + teamName,
+		tools: []tool.Tool{
+			function.NewFunctionTool(report,
+				function.WithName(toolBoardReportBlocker),
+				function.WithDescription(
+					"Report that your current task cannot be "+
+						"completed and why. The coordinator decides "+
+						"how to continue."),
+			),
+		},
+	}
+}
+
+func (r *boardRun) addTasks(tasks []Task) (boardOutput, error) {
+	r.mu.Lock()
+	ids, err := r.board.add(tasks, r.team.memberByName, r.team.taskBoard.MaxTasks)
+	board := r.cloneBoardLocked()
+	r.mu.Unlock()
+	if err != nil {
+		return boardOutput{}, err
+	}
+	return boardOutput{
+		Message: fmt.Sprintf("added %d tasks: %v", len(ids), ids),
+		Board:   board,
+	}, nil
+}
+
+func (r *boardRun) updateTask(id, action, note string) (boardOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.board.task(id)
+	if t == nil {
+		return boardOutput{}, fmt.Errorf("unknown task %q", id)
+	}
+	switch action {
+	case boardActionRetry:
+		if t.Status != TaskStatusBlocked && t.Status != TaskStatusCancelled {
+			return boardOutput{}, fmt.Errorf(
+				"task %q is %s, only blocked or cancelled tasks can be retried",
+				id, t.Status)
+		}
+		t.Status = TaskStatusPending
+		t.Blocker = ""
+		if note != "" {
+			t.Description += "\n\nCoordinator note: " + note
+		}
+	case boardActionCancel:
+		if t.Status == TaskStatusCompleted {
+			return boardOutput{}, fmt.Errorf("task %q is already completed", id)
+		}
+		t.Status = TaskStatusCancelled
+	default:
+		return boardOutput{}, fmt.Errorf(
+			"unknown action %q, want %s or %s",
+			action, boardActionRetry, boardActionCancel)
+	}
+	return boardOutput{
+		Message: fmt.Sprintf("task %s is %s", id, t.Status),
+		Board:   r.cloneBoardLocked(),
+	}, nil
+}
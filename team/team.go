@@ -46,6 +46,8 @@ type Team struct {
 	swarm             SwarmConfig
 	swarmHandoff      swarmHandoffPolicy
 	swarmHandoffInput SwarmHandoffInputBuilder
+	taskBoard         TaskBoardConfig
+	debate            DebateConfig
 }
 
 // Mode controls how a Team runs.
@@ -59,6 +61,14 @@ const (
 	// ModeSwarm starts from an entry member and lets members transfer control
 	// to each other via transfer_to_agent.
 	ModeSwarm
+
+	// ModeTaskBoard lets the coordinator write tasks with dependencies to a
+	// shared board that members work through concurrently.
+	ModeTaskBoard
+
+	// ModeDebate lets members answer, critique each other for a number of
+	// rounds, and then decides by a judge agent or a vote.
+	ModeDebate
 )
 
 const (
@@ -189,6 +199,10 @@ func (t *Team) Run(
 		return t.runCoordinator(ctx, invocation)
 	case ModeSwarm:
 		return t.runSwarm(ctx, invocation)
+	case ModeTaskBoard:
+		return t.runTaskBoard(ctx, invocation)
+	case ModeDebate:
+		return t.runDebate(ctx, invocation)
 	default:
 		return nil, fmt.Errorf("unknown team mode: %d", t.mode)
 	}
@@ -391,7 +405,7 @@ func (t *Team) getActiveAgent(invocation *agent.Invocation) agent.Agent {
 // Tools implements agent.Agent.
 func (t *Team) Tools() []tool.Tool {
 	switch t.mode {
-	case ModeCoordinator:
+	case ModeCoordinator, ModeTaskBoard:
 		if t.coordinator == nil {
 			return nil
 		}
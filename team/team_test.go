@@ -17,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -2446,3 +2447,49 @@ func collectTeamRunnerCompletionEvent(
 	require.NotNil(t, completion)
 	return completion
 }
+
+// blockingMember sends count events without watching its context and
+// closes done when it returns.
+type blockingMember struct {
+	name  string
+	count int
+	done  chan struct{}
+}
+
+func (b *blockingMember) Info() agent.Info { return agent.Info{Name: b.name} }
+
+func (b *blockingMember) SubAgents() []agent.Agent { return nil }
+
+func (b *blockingMember) FindSubAgent(string) agent.Agent { return nil }
+
+func (b *blockingMember) Tools() []tool.Tool { return nil }
+
+func (b *blockingMember) Run(
+	_ context.Context,
+	inv *agent.Invocation,
+) (<-chan *event.Event, error) {
+	ch := make(chan *event.Event)
+	go func() {
+		defer close(b.done)
+		defer close(ch)
+		for i := 0; i < b.count; i++ {
+			ch <- event.New(inv.InvocationID, b.name)
+		}
+	}()
+	return ch, nil
+}
+
+func TestRunMember_DrainsMemberOnEmitFailure(t *testing.T) {
+	m := &blockingMember{name: "m", count: 3, done: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inv := agent.NewInvocation(agent.WithInvocationAgent(m))
+
+	_, err := runMember(ctx, make(chan *event.Event), m, inv)
+	require.ErrorIs(t, err, context.Canceled)
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("member blocked after runMember returned")
+	}
+}
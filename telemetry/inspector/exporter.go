@@ -0,0 +1,175 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	itelemetry "trpc.group/trpc-go/trpc-agent-go/internal/telemetry"
+	semconvtrace "trpc.group/trpc-go/trpc-agent-go/telemetry/semconv/trace"
+	atrace "trpc.group/trpc-go/trpc-agent-go/telemetry/trace"
+)
+
+var _ sdktrace.SpanExporter = (*Exporter)(nil)
+
+// Exporter is an OpenTelemetry span exporter writing spans to a Store.
+type Exporter struct {
+	store *Store
+}
+
+// NewExporter creates an exporter writing to store.
+func NewExporter(store *Store) (*Exporter, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	return &Exporter{store: store}, nil
+}
+
+// ExportSpans implements sdktrace.SpanExporter.
+func (e *Exporter) ExportSpans(
+	ctx context.Context,
+	spans []sdktrace.ReadOnlySpan,
+) error {
+	converted := make([]Span, 0, len(spans))
+	for _, s := range spans {
+		converted = append(converted, convertSpan(s))
+	}
+	return e.store.Save(ctx, converted)
+}
+
+// Shutdown implements sdktrace.SpanExporter. The DB is owned by the
+// caller and is left open.
+func (e *Exporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// Start registers an Exporter writing to store on the tracer provider of
+// telemetry/trace, creating an SDK provider when none is installed, so the
+// framework spans are recorded locally. The returned function flushes and
+// detaches the exporter and restores the previous tracer and provider.
+func Start(
+	ctx context.Context,
+	store *Store,
+) (clean func(context.Context) error, err error) {
+	exp, err := NewExporter(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider *sdktrace.TracerProvider
+	if _, ok := atrace.TracerProvider.(noop.TracerProvider); !ok {
+		provider, ok = atrace.TracerProvider.(*sdktrace.TracerProvider)
+		if !ok {
+			return nil, fmt.Errorf("inspector: unsupported tracer provider %T",
+				atrace.TracerProvider)
+		}
+	}
+
+	prevProvider, prevTracer := atrace.TracerProvider, atrace.Tracer
+	restore := func() {
+		atrace.TracerProvider = prevProvider
+		atrace.Tracer = prevTracer
+	}
+
+	processor := sdktrace.NewBatchSpanProcessor(exp)
+	if provider != nil {
+		provider.RegisterSpanProcessor(processor)
+		atrace.Tracer = provider.Tracer(itelemetry.InstrumentName)
+		return func(ctx context.Context) error {
+			restore()
+			provider.UnregisterSpanProcessor(processor)
+			return processor.Shutdown(ctx)
+		}, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNamespace(semconvtrace.ResourceServiceNamespace),
+			semconv.ServiceName(semconvtrace.ResourceServiceName),
+			semconv.ServiceVersion(semconvtrace.ResourceServiceVersion),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(processor),
+	)
+	atrace.TracerProvider = provider
+	atrace.Tracer = provider.Tracer(itelemetry.InstrumentName)
+	return func(ctx context.Context) error {
+		restore()
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+func convertSpan(s sdktrace.ReadOnlySpan) Span {
+	sc := s.SpanContext()
+	sp := Span{
+		TraceID:       sc.TraceID().String(),
+		SpanID:        sc.SpanID().String(),
+		Name:          s.Name(),
+		Kind:          s.SpanKind().String(),
+		StartTime:     s.StartTime(),
+		EndTime:       s.EndTime(),
+		StatusCode:    s.Status().Code.String(),
+		StatusMessage: s.Status().Description,
+		Attributes:    attributeMap(s.Attributes()),
+	}
+	if parent := s.Parent(); parent.HasSpanID() {
+		sp.ParentSpanID = parent.SpanID().String()
+	}
+	for _, kv := range s.Attributes() {
+		switch string(kv.Key) {
+		case semconvtrace.KeyGenAIOperationName:
+			sp.Operation = kv.Value.AsString()
+		case semconvtrace.KeyGenAIUsageInputTokens:
+			sp.InputTokens = kv.Value.AsInt64()
+		case semconvtrace.KeyGenAIUsageOutputTokens:
+			sp.OutputTokens = kv.Value.AsInt64()
+		}
+	}
+	for _, ev := range s.Events() {
+		sp.Events = append(sp.Events, Event{
+			Name:       ev.Name,
+			Time:       ev.Time,
+			Attributes: attributeMap(ev.Attributes),
+		})
+	}
+	for _, l := range s.Links() {
+		sp.Links = append(sp.Links, Link{
+			TraceID:    l.SpanContext.TraceID().String(),
+			SpanID:     l.SpanContext.SpanID().String(),
+			Attributes: attributeMap(l.Attributes),
+		})
+	}
+	return sp
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
@@ -0,0 +1,422 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	semconvtrace "trpc.group/trpc-go/trpc-agent-go/telemetry/semconv/trace"
+)
+
+const (
+	defaultPageSize = 50
+
+	routeRuns    = "runs/"
+	routeAPIRuns = "api/runs"
+)
+
+// highlightKeys are the attributes shown expanded above the attribute
+// table, in display order.
+var highlightKeys = []struct {
+	key   string
+	label string
+}{
+	{semconvtrace.KeyGenAISystemInstructions, "System instructions"},
+	{semconvtrace.KeyGenAIInputMessages, "Input messages"},
+	{semconvtrace.KeyGenAIOutputMessages, "Output messages"},
+	{semconvtrace.KeyGenAIToolCallArguments, "Tool arguments"},
+	{semconvtrace.KeyGenAIToolCallResult, "Tool result"},
+	{semconvtrace.KeyRunnerInput, "Runner input"},
+	{semconvtrace.KeyRunnerOutput, "Runner output"},
+	{semconvtrace.KeyGenAIWorkflowRequest, "Workflow request"},
+	{semconvtrace.KeyGenAIWorkflowResponse, "Workflow response"},
+	{semconvtrace.KeyErrorMessage, "Error"},
+}
+
+// subjectKeys name what a span operated on, first match wins.
+var subjectKeys = []string{
+	semconvtrace.KeyGenAIAgentName,
+	semconvtrace.KeyGenAIToolName,
+	semconvtrace.KeyGenAIRequestModel,
+	semconvtrace.KeyGenAIWorkflowName,
+}
+
+// HandlerOption configures the handler returned by NewHandler.
+type HandlerOption func(*handler)
+
+// WithPageSize sets how many runs the run list shows. The default is 50.
+func WithPageSize(n int) HandlerOption {
+	return func(h *handler) {
+		if n > 0 {
+			h.pageSize = n
+		}
+	}
+}
+
+// NewHandler returns an http.Handler serving the inspector UI and its
+// JSON API from store:
+//
+//	GET /                 run list
+//	GET /runs/{trace_id}  span tree of one run
+//	GET /api/runs         run list as JSON, ?limit=n
+//	GET /api/runs/{id}    run detail as JSON
+//
+// Links are relative, so the handler can be mounted under a prefix with
+// http.StripPrefix.
+func NewHandler(store *Store, opts ...HandlerOption) http.Handler {
+	h := &handler{store: store, pageSize: defaultPageSize}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type handler struct {
+	store    *Store
+	pageSize int
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == "" {
+		// Mounted under a prefix without the trailing slash; relative
+		// links need it.
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			http.Redirect(w, r, u.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case path == "":
+		h.serveRunList(w, r)
+	case strings.HasPrefix(path, routeRuns):
+		h.serveRun(w, r, strings.TrimPrefix(path, routeRuns))
+	case path == routeAPIRuns:
+		h.serveRunListJSON(w, r)
+	case strings.HasPrefix(path, routeAPIRuns+"/"):
+		h.serveRunJSON(w, r, strings.TrimPrefix(path, routeAPIRuns+"/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *handler) serveRunList(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.store.ListRuns(r.Context(), h.pageSize)
+	if err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	h.render(w, r, "list", runs)
+}
+
+func (h *handler) serveRun(w http.ResponseWriter, r *http.Request, traceID string) {
+	detail, ok := h.getRun(w, r, traceID)
+	if !ok {
+		return
+	}
+	h.render(w, r, "run", newRunView(detail))
+}
+
+func (h *handler) serveRunListJSON(w http.ResponseWriter, r *http.Request) {
+	limit := h.pageSize
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	runs, err := h.store.ListRuns(r.Context(), limit)
+	if err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	if runs == nil {
+		runs = []Run{}
+	}
+	writeJSON(w, runs)
+}
+
+func (h *handler) serveRunJSON(w http.ResponseWriter, r *http.Request, traceID string) {
+	detail, ok := h.getRun(w, r, traceID)
+	if !ok {
+		return
+	}
+	writeJSON(w, detail)
+}
+
+func (h *handler) getRun(
+	w http.ResponseWriter,
+	r *http.Request,
+	traceID string,
+) (*RunDetail, bool) {
+	if traceID == "" || strings.Contains(traceID, "/") {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	detail, err := h.store.GetRun(r.Context(), traceID)
+	if errors.Is(err, ErrRunNotFound) {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if err != nil {
+		h.serverError(w, r, err)
+		return nil, false
+	}
+	return detail, true
+}
+
+func (h *handler) render(w http.ResponseWriter, r *http.Request, name string, data any) {
+	var buf bytes.Buffer
+	if err := pageTemplate.ExecuteTemplate(&buf, name, data); err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+func (h *handler) serverError(w http.ResponseWriter, r *http.Request, err error) {
+	log.ErrorfContext(r.Context(), "inspector: %s: %v", r.URL.Path, err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// runView is the template data of the run page.
+type runView struct {
+	*RunDetail
+	Rows []spanRow
+}
+
+// spanRow is one span of the flattened tree with its timeline bar.
+type spanRow struct {
+	*Span
+	Depth      int
+	Offset     float64
+	Width      float64
+	Subject    string
+	Highlights []namedValue
+	Attributes []namedValue
+}
+
+type namedValue struct {
+	Name  string
+	Value string
+}
+
+func newRunView(detail *RunDetail) runView {
+	view := runView{RunDetail: detail}
+	total := detail.Duration()
+	var walk func(sp *Span, depth int)
+	walk = func(sp *Span, depth int) {
+		view.Rows = append(view.Rows, newSpanRow(sp, depth, detail.StartTime, total))
+		for _, child := range sp.Children {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range detail.Roots {
+		walk(root, 0)
+	}
+	return view
+}
+
+func newSpanRow(sp *Span, depth int, runStart time.Time, total time.Duration) spanRow {
+	row := spanRow{Span: sp, Depth: depth, Width: 100}
+	if total > 0 {
+		row.Offset = 100 * float64(sp.StartTime.Sub(runStart)) / float64(total)
+		row.Width = 100 * float64(sp.Duration()) / float64(total)
+	}
+	for _, key := range subjectKeys {
+		if v, ok := sp.Attributes[key].(string); ok && v != "" {
+			row.Subject = v
+			break
+		}
+	}
+	highlighted := make(map[string]bool, len(highlightKeys))
+	for _, hk := range highlightKeys {
+		v, ok := sp.Attributes[hk.key]
+		if !ok {
+			continue
+		}
+		highlighted[hk.key] = true
+		row.Highlights = append(row.Highlights, namedValue{
+			Name:  hk.label,
+			Value: formatValue(v),
+		})
+	}
+	for k, v := range sp.Attributes {
+		if !highlighted[k] {
+			row.Attributes = append(row.Attributes, namedValue{Name: k, Value: formatValue(v)})
+		}
+	}
+	sort.Slice(row.Attributes, func(i, j int) bool {
+		return row.Attributes[i].Name < row.Attributes[j].Name
+	})
+	return row
+}
+
+// formatValue renders an attribute value, indenting JSON documents such
+// as serialized messages.
+func formatValue(v any) string {
+	s, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var buf bytes.Buffer
+		if json.Indent(&buf, []byte(trimmed), "", "  ") == nil {
+			return buf.String()
+		}
+	}
+	return s
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	default:
+		return d.String()
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05.000")
+}
+
+func percent(f float64) template.CSS {
+	return template.CSS(strconv.FormatFloat(f, 'f', 3, 64) + "%")
+}
+
+var pageTemplate = template.Must(template.New("inspector").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"time":     formatTime,
+	"percent":  percent,
+	"indent": func(depth int) template.CSS {
+		return template.CSS(strconv.Itoa(depth*16) + "px")
+	},
+}).Parse(pageTemplateHTML))
+
+const pageTemplateHTML = `
+{{define "head"}}<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.}}</title>
+  <style>
+    body { font: 14px/1.4 system-ui, sans-serif; margin: 24px; color: #222; }
+    a { color: #1f5fbf; text-decoration: none; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #e4e4e4; vertical-align: top; }
+    th { font-weight: 600; background: #f6f6f6; }
+    .num { text-align: right; font-variant-numeric: tabular-nums; }
+    .error { color: #b3261e; }
+    .muted { color: #777; }
+    .span { border-bottom: 1px solid #eee; }
+    .span > summary { display: grid; grid-template-columns: 40% 10% 50%; cursor: pointer; padding: 4px 0; }
+    .bar-track { position: relative; height: 12px; margin-top: 4px; background: #f2f2f2; }
+    .bar { position: absolute; height: 12px; min-width: 2px; background: #6a9be0; }
+    .bar.error { background: #e07a6a; }
+    .body { padding: 4px 16px 12px; }
+    pre { white-space: pre-wrap; word-break: break-word; background: #f8f8f8; padding: 8px; margin: 4px 0; max-height: 480px; overflow: auto; }
+  </style>
+</head>
+<body>{{end}}
+
+{{define "list"}}{{template "head" "Agent runs"}}
+<h1>Agent runs</h1>
+{{if .}}
+<table>
+  <tr><th>Started</th><th>Run</th><th class="num">Duration</th><th class="num">Spans</th>
+  <th class="num">Errors</th><th class="num">Input tokens</th><th class="num">Output tokens</th></tr>
+  {{range .}}
+  <tr>
+    <td>{{time .StartTime}}</td>
+    <td><a href="runs/{{.TraceID}}">{{.Name}}</a><div class="muted">{{.TraceID}}</div></td>
+    <td class="num">{{duration .Duration}}</td>
+    <td class="num">{{.SpanCount}}</td>
+    <td class="num{{if .ErrorCount}} error{{end}}">{{.ErrorCount}}</td>
+    <td class="num">{{.InputTokens}}</td>
+    <td class="num">{{.OutputTokens}}</td>
+  </tr>
+  {{end}}
+</table>
+{{else}}
+<p class="muted">No runs recorded yet.</p>
+{{end}}
+</body>
+</html>{{end}}
+
+{{define "run"}}{{template "head" .Name}}
+<p><a href="../">&larr; All runs</a></p>
+<h1>{{.Name}}</h1>
+<p class="muted">{{.TraceID}} &middot; {{time .StartTime}} &middot; {{duration .Duration}} &middot;
+{{.SpanCount}} spans &middot; {{.InputTokens}} input / {{.OutputTokens}} output tokens
+{{if .ErrorCount}}&middot; <span class="error">{{.ErrorCount}} errors</span>{{end}}</p>
+{{range .Rows}}
+<details class="span">
+  <summary>
+    <span style="padding-left: {{indent .Depth}}">
+      <strong{{if eq .StatusCode "Error"}} class="error"{{end}}>{{.Name}}</strong>
+      {{if .Subject}}<span class="muted">{{.Subject}}</span>{{end}}
+    </span>
+    <span class="num">{{duration .Duration}}{{if or .InputTokens .OutputTokens}}<br><span class="muted">{{.InputTokens}}/{{.OutputTokens}} tok</span>{{end}}</span>
+    <span class="bar-track"><span class="bar{{if eq .StatusCode "Error"}} error{{end}}" style="left: {{percent .Offset}}; width: {{percent .Width}}"></span></span>
+  </summary>
+  <div class="body">
+    <p class="muted">{{.Kind}}{{if .Operation}} &middot; {{.Operation}}{{end}} &middot; {{time .StartTime}}
+    {{if .StatusMessage}}&middot; <span class="error">{{.StatusMessage}}</span>{{end}}</p>
+    {{range .Highlights}}<h4>{{.Name}}</h4><pre>{{.Value}}</pre>{{end}}
+    {{if .Events}}<h4>Events</h4>
+    <table>{{range .Events}}<tr><td>{{time .Time}}</td><td>{{.Name}}</td><td>{{range $k, $v := .Attributes}}{{$k}}={{$v}} {{end}}</td></tr>{{end}}</table>
+    {{end}}
+    {{if .Links}}<h4>Links</h4>
+    <ul>{{range .Links}}<li><a href="../runs/{{.TraceID}}">{{.TraceID}}</a> / {{.SpanID}}</li>{{end}}</ul>
+    {{end}}
+    {{if .Attributes}}<h4>Attributes</h4>
+    <table>{{range .Attributes}}<tr><td>{{.Name}}</td><td><pre>{{.Value}}</pre></td></tr>{{end}}</table>
+    {{end}}
+  </div>
+</details>
+{{end}}
+</body>
+</html>{{end}}
+`
@@ -0,0 +1,267 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package inspector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Import SQLite driver.
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	semconvtrace "trpc.group/trpc-go/trpc-agent-go/telemetry/semconv/trace"
+	atrace "trpc.group/trpc-go/trpc-agent-go/telemetry/trace"
+)
+
+func openStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "runs.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db, opts...)
+	require.NoError(t, err)
+	return s
+}
+
+// recordRun records an agent run with a chat and a failing tool call
+// through a real SDK tracer and returns its trace ID.
+func recordRun(t *testing.T, store *Store) string {
+	t.Helper()
+	exp, err := NewExporter(store)
+	require.NoError(t, err)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	tracer := tp.Tracer("test")
+
+	ctx, agentSpan := tracer.Start(context.Background(), "invoke_agent assistant")
+	agentSpan.SetAttributes(
+		attribute.String(semconvtrace.KeyGenAIOperationName, "invoke_agent"),
+		attribute.String(semconvtrace.KeyGenAIAgentName, "assistant"),
+		attribute.Int(semconvtrace.KeyGenAIUsageInputTokens, 10),
+		attribute.Int(semconvtrace.KeyGenAIUsageOutputTokens, 5),
+	)
+	_, chat := tracer.Start(ctx, "chat gpt-test")
+	chat.SetAttributes(
+		attribute.String(semconvtrace.KeyGenAIOperationName, "chat"),
+		attribute.String(semconvtrace.KeyGenAIRequestModel, "gpt-test"),
+		attribute.String(semconvtrace.KeyGenAIInputMessages, `[{"role":"user","content":"hi"}]`),
+		attribute.Int(semconvtrace.KeyGenAIUsageInputTokens, 10),
+		attribute.Int(semconvtrace.KeyGenAIUsageOutputTokens, 5),
+	)
+	chat.AddEvent("first_token", trace.WithAttributes(attribute.Int("index", 0)))
+	chat.End()
+	_, tool := tracer.Start(ctx, "execute_tool search")
+	tool.SetAttributes(
+		attribute.String(semconvtrace.KeyGenAIOperationName, "execute_tool"),
+		attribute.String(semconvtrace.KeyGenAIToolName, "search"),
+		attribute.String(semconvtrace.KeyGenAIToolCallArguments, `{"q":"go"}`),
+	)
+	tool.SetStatus(codes.Error, "timeout")
+	tool.End()
+	agentSpan.End()
+	return agentSpan.SpanContext().TraceID().String()
+}
+
+func TestNewStore_Validation(t *testing.T) {
+	_, err := NewStore(nil)
+	assert.Error(t, err)
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "runs.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = NewStore(db, WithTableName("bad name;"))
+	assert.Error(t, err)
+	_, err = NewExporter(nil)
+	assert.Error(t, err)
+}
+
+func TestExporter_RecordsRunTree(t *testing.T) {
+	ctx := context.Background()
+	store := openStore(t)
+	traceID := recordRun(t, store)
+
+	runs, err := store.ListRuns(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	run := runs[0]
+	assert.Equal(t, traceID, run.TraceID)
+	assert.Equal(t, "invoke_agent assistant", run.Name)
+	assert.Equal(t, 3, run.SpanCount)
+	assert.Equal(t, 1, run.ErrorCount)
+	assert.Equal(t, int64(10), run.InputTokens, "agent usage is not double counted")
+	assert.Equal(t, int64(5), run.OutputTokens)
+
+	detail, err := store.GetRun(ctx, traceID)
+	require.NoError(t, err)
+	require.Len(t, detail.Roots, 1)
+	root := detail.Roots[0]
+	assert.Equal(t, "invoke_agent", root.Operation)
+	require.Len(t, root.Children, 2)
+
+	chat := root.Children[0]
+	assert.Equal(t, "chat gpt-test", chat.Name)
+	assert.Equal(t, root.SpanID, chat.ParentSpanID)
+	assert.Equal(t, int64(10), chat.InputTokens)
+	assert.Equal(t, "gpt-test", chat.Attributes[semconvtrace.KeyGenAIRequestModel])
+	require.Len(t, chat.Events, 1)
+	assert.Equal(t, "first_token", chat.Events[0].Name)
+	assert.EqualValues(t, 0, chat.Events[0].Attributes["index"])
+
+	tool := root.Children[1]
+	assert.Equal(t, StatusError, tool.StatusCode)
+	assert.Equal(t, "timeout", tool.StatusMessage)
+
+	_, err = store.GetRun(ctx, "missing")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
+
+func TestStore_OrphanSpansAndRetention(t *testing.T) {
+	ctx := context.Background()
+	store := openStore(t, WithMaxAttributeBytes(2))
+	old := time.Unix(100, 0)
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, []Span{
+		{TraceID: "t1", SpanID: "a", Name: "old", StartTime: old, EndTime: old.Add(time.Second)},
+		{TraceID: "t2", SpanID: "c", ParentSpanID: "missing", Name: "late",
+			StartTime: now.Add(time.Millisecond), EndTime: now.Add(2 * time.Millisecond),
+			Attributes: map[string]any{"text": "héllo world"}},
+		{TraceID: "t2", SpanID: "b", ParentSpanID: "remote", Name: "first",
+			StartTime: now, EndTime: now.Add(time.Second)},
+	}))
+
+	runs, err := store.ListRuns(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "t2", runs[0].TraceID, "most recent run first")
+	assert.Equal(t, "first", runs[0].Name)
+
+	detail, err := store.GetRun(ctx, "t2")
+	require.NoError(t, err)
+	require.Len(t, detail.Roots, 2, "spans with unknown parents are roots")
+	assert.Equal(t, "h"+truncatedSuffix, detail.Roots[1].Attributes["text"])
+
+	n, err := store.DeleteBefore(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	_, err = store.GetRun(ctx, "t1")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
+
+func TestHandler(t *testing.T) {
+	store := openStore(t)
+	traceID := recordRun(t, store)
+	srv := httptest.NewServer(http.StripPrefix("/inspector", NewHandler(store)))
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		rsp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer rsp.Body.Close()
+		body, err := io.ReadAll(rsp.Body)
+		require.NoError(t, err)
+		return rsp.StatusCode, string(body)
+	}
+
+	code, body := get("/inspector")
+	assert.Equal(t, http.StatusOK, code, "redirected to the trailing slash")
+	assert.Contains(t, body, `href="runs/`+traceID+`"`)
+	assert.Contains(t, body, "invoke_agent assistant")
+
+	code, body = get("/inspector/runs/" + traceID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "chat gpt-test")
+	assert.Contains(t, body, "Tool arguments")
+	assert.Contains(t, body, "&#34;q&#34;: &#34;go&#34;")
+	assert.Contains(t, body, "timeout")
+
+	code, body = get("/inspector/api/runs?limit=5")
+	assert.Equal(t, http.StatusOK, code)
+	var runs []Run
+	require.NoError(t, json.Unmarshal([]byte(body), &runs))
+	require.Len(t, runs, 1)
+
+	code, body = get("/inspector/api/runs/" + traceID)
+	assert.Equal(t, http.StatusOK, code)
+	var detail RunDetail
+	require.NoError(t, json.Unmarshal([]byte(body), &detail))
+	assert.Len(t, detail.Roots[0].Children, 2)
+
+	code, _ = get("/inspector/runs/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/inspector/api/runs?limit=x")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/inspector/other")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	rsp, err := http.Post(srv.URL+"/inspector/", "text/plain", strings.NewReader(""))
+	require.NoError(t, err)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, rsp.StatusCode)
+}
+
+func TestStart(t *testing.T) {
+	prevProvider, prevTracer := atrace.TracerProvider, atrace.Tracer
+	t.Cleanup(func() {
+		atrace.TracerProvider, atrace.Tracer = prevProvider, prevTracer
+	})
+	ctx := context.Background()
+
+	noopProvider := noop.NewTracerProvider()
+	noopTracer := noopProvider.Tracer("test")
+	atrace.TracerProvider, atrace.Tracer = noopProvider, noopTracer
+	store := openStore(t)
+	clean, err := Start(ctx, store)
+	require.NoError(t, err)
+	_, span := atrace.Tracer.Start(ctx, "run")
+	span.End()
+	require.NoError(t, clean(ctx))
+	runs, err := store.ListRuns(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	// The globals no longer point at the shut down provider.
+	assert.Equal(t, noopProvider, atrace.TracerProvider)
+	assert.Equal(t, noopTracer, atrace.Tracer)
+
+	// An existing SDK provider is reused and keeps working after clean.
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(ctx)
+	atrace.TracerProvider = provider
+	store = openStore(t)
+	clean, err = Start(ctx, store)
+	require.NoError(t, err)
+	_, span = atrace.Tracer.Start(ctx, "run")
+	span.End()
+	require.NoError(t, clean(ctx))
+	runs, err = store.ListRuns(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Same(t, provider, atrace.TracerProvider)
+
+	atrace.TracerProvider = unsupportedProvider{}
+	_, err = Start(ctx, store)
+	assert.Error(t, err)
+}
+
+type unsupportedProvider struct {
+	noop.TracerProvider
+}
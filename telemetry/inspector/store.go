@@ -0,0 +1,441 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package inspector is a local run inspector for agent traces.
+//
+// An Exporter writes the spans produced by telemetry/trace (agent invoke,
+// chat, tool execute, workflow and graph node spans) together with their
+// attributes, events and links into a SQLite table, and NewHandler serves
+// a small web UI listing runs and rendering each run as a span tree with
+// prompts, tool arguments and results, token usage and latency per step.
+// Each trace is one run.
+//
+// It needs no collector, which makes it suitable for offline debugging:
+//
+//	db, _ := sql.Open("sqlite3", "runs.db")
+//	store, _ := inspector.NewStore(db)
+//	clean, _ := inspector.Start(ctx, store)
+//	defer clean(ctx)
+//	go http.ListenAndServe("localhost:7070", inspector.NewHandler(store))
+package inspector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	itelemetry "trpc.group/trpc-go/trpc-agent-go/internal/telemetry"
+)
+
+const (
+	defaultTableName = "inspector_spans"
+
+	truncatedSuffix = "...[truncated]"
+)
+
+// StatusError is the status code stored for failed spans.
+const StatusError = "Error"
+
+// ErrRunNotFound is returned when a trace has no stored spans.
+var ErrRunNotFound = errors.New("inspector: run not found")
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Span is one stored span.
+type Span struct {
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind,omitempty"`
+	Operation     string         `json:"operation,omitempty"`
+	StartTime     time.Time      `json:"start_time"`
+	EndTime       time.Time      `json:"end_time"`
+	StatusCode    string         `json:"status_code,omitempty"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	InputTokens   int64          `json:"input_tokens,omitempty"`
+	OutputTokens  int64          `json:"output_tokens,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Events        []Event        `json:"events,omitempty"`
+	Links         []Link         `json:"links,omitempty"`
+	// Children is filled by Store.GetRun.
+	Children []*Span `json:"children,omitempty"`
+}
+
+// Duration returns the span latency.
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Event is a timestamped event recorded on a span.
+type Event struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Link points from a span to a span of another trace or run.
+type Link struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Run summarizes one trace.
+type Run struct {
+	TraceID    string    `json:"trace_id"`
+	Name       string    `json:"name"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	SpanCount  int       `json:"span_count"`
+	ErrorCount int       `json:"error_count"`
+	// InputTokens and OutputTokens sum the usage of chat spans, so usage
+	// repeated on the enclosing agent spans is not counted twice.
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+// Duration returns the wall time of the run.
+func (r Run) Duration() time.Duration {
+	return r.EndTime.Sub(r.StartTime)
+}
+
+// RunDetail is a run with its spans.
+type RunDetail struct {
+	Run
+	// Roots are the spans whose parent is not part of the run, with their
+	// descendants linked through Span.Children in start order.
+	Roots []*Span `json:"roots"`
+}
+
+// Option configures the Store.
+type Option func(*Store)
+
+// WithTableName sets the table that holds spans.
+// The default is "inspector_spans".
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.table = name
+	}
+}
+
+// WithMaxAttributeBytes truncates string attribute values longer than n
+// bytes before they are stored. Zero, the default, keeps them whole.
+func WithMaxAttributeBytes(n int) Option {
+	return func(s *Store) {
+		s.maxAttributeBytes = n
+	}
+}
+
+// Store persists spans in a SQLite table.
+// It expects an initialized *sql.DB and creates the table if needed.
+type Store struct {
+	db                *sql.DB
+	table             string
+	maxAttributeBytes int
+}
+
+// NewStore creates a store using the provided DB.
+// The DB must use a SQLite driver.
+func NewStore(db *sql.DB, opts ...Option) (*Store, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	s := &Store{db: db, table: defaultTableName}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !tableNamePattern.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	stmts := []string{
+		"CREATE TABLE IF NOT EXISTS " + s.table + " (" +
+			"trace_id TEXT NOT NULL, " +
+			"span_id TEXT NOT NULL, " +
+			"parent_span_id TEXT NOT NULL, " +
+			"name TEXT NOT NULL, " +
+			"kind TEXT NOT NULL, " +
+			"operation TEXT NOT NULL, " +
+			"start_ns INTEGER NOT NULL, " +
+			"end_ns INTEGER NOT NULL, " +
+			"status_code TEXT NOT NULL, " +
+			"status_message TEXT NOT NULL, " +
+			"input_tokens INTEGER NOT NULL, " +
+			"output_tokens INTEGER NOT NULL, " +
+			"attributes TEXT NOT NULL, " +
+			"events TEXT NOT NULL, " +
+			"links TEXT NOT NULL, " +
+			"PRIMARY KEY (trace_id, span_id)" +
+			")",
+		"CREATE INDEX IF NOT EXISTS " + s.table + "_start_idx ON " +
+			s.table + " (start_ns)",
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("create %s table: %w", s.table, err)
+		}
+	}
+	return s, nil
+}
+
+// Save stores spans, replacing spans with the same trace and span ID.
+func (s *Store) Save(ctx context.Context, spans []Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT OR REPLACE INTO "+s.table+
+		" (trace_id, span_id, parent_span_id, name, kind, operation, "+
+		"start_ns, end_ns, status_code, status_message, input_tokens, "+
+		"output_tokens, attributes, events, links) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i := range spans {
+		sp := &spans[i]
+		attrs, err := json.Marshal(s.truncate(sp.Attributes))
+		if err != nil {
+			return fmt.Errorf("marshal attributes: %w", err)
+		}
+		events := make([]Event, len(sp.Events))
+		for j, ev := range sp.Events {
+			ev.Attributes = s.truncate(ev.Attributes)
+			events[j] = ev
+		}
+		eventsJSON, err := json.Marshal(events)
+		if err != nil {
+			return fmt.Errorf("marshal events: %w", err)
+		}
+		linksJSON, err := json.Marshal(sp.Links)
+		if err != nil {
+			return fmt.Errorf("marshal links: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx,
+			sp.TraceID, sp.SpanID, sp.ParentSpanID, sp.Name, sp.Kind,
+			sp.Operation, sp.StartTime.UnixNano(), sp.EndTime.UnixNano(),
+			sp.StatusCode, sp.StatusMessage, sp.InputTokens, sp.OutputTokens,
+			string(attrs), string(eventsJSON), string(linksJSON),
+		); err != nil {
+			return fmt.Errorf("insert span: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the most recent runs first. A non-positive limit
+// returns all runs.
+func (s *Store) ListRuns(ctx context.Context, limit int) ([]Run, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, s.runSummaryQuery()+
+		" GROUP BY trace_id ORDER BY MIN(start_ns) DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("select runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate runs: %w", err)
+	}
+	for i := range runs {
+		if runs[i].Name, err = s.rootName(ctx, runs[i].TraceID); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+// GetRun returns a run with its span tree.
+// It returns ErrRunNotFound when no span of the trace is stored.
+func (s *Store) GetRun(ctx context.Context, traceID string) (*RunDetail, error) {
+	row := s.db.QueryRowContext(ctx, s.runSummaryQuery()+
+		" WHERE trace_id = ? GROUP BY trace_id", traceID)
+	run, err := scanRun(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	spans, err := s.spans(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &RunDetail{Run: run, Roots: buildTree(spans)}
+	if len(detail.Roots) > 0 {
+		detail.Name = detail.Roots[0].Name
+	}
+	return detail, nil
+}
+
+// DeleteBefore removes runs that ended before t and returns the number of
+// spans deleted.
+func (s *Store) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		"DELETE FROM "+s.table+" WHERE trace_id IN ("+
+			"SELECT trace_id FROM "+s.table+
+			" GROUP BY trace_id HAVING MAX(end_ns) < ?)", t.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("delete runs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) runSummaryQuery() string {
+	chat := "'" + itelemetry.OperationChat + "'"
+	return "SELECT trace_id, MIN(start_ns), MAX(end_ns), COUNT(*), " +
+		"SUM(CASE WHEN status_code = '" + StatusError + "' THEN 1 ELSE 0 END), " +
+		"SUM(CASE WHEN operation = " + chat + " THEN input_tokens ELSE 0 END), " +
+		"SUM(CASE WHEN operation = " + chat + " THEN output_tokens ELSE 0 END) " +
+		"FROM " + s.table
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRun(row rowScanner) (Run, error) {
+	var (
+		run          Run
+		start, end   int64
+		errs, in, ou int64
+	)
+	if err := row.Scan(&run.TraceID, &start, &end, &run.SpanCount,
+		&errs, &in, &ou); err != nil {
+		return Run{}, fmt.Errorf("scan run: %w", err)
+	}
+	run.StartTime = time.Unix(0, start)
+	run.EndTime = time.Unix(0, end)
+	run.ErrorCount = int(errs)
+	run.InputTokens = in
+	run.OutputTokens = ou
+	return run, nil
+}
+
+// rootName returns the name of the first span of a trace, which is the
+// root span for runs recorded in a single process.
+func (s *Store) rootName(ctx context.Context, traceID string) (string, error) {
+	var name string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT name FROM "+s.table+" WHERE trace_id = ? "+
+			"ORDER BY start_ns, end_ns DESC LIMIT 1", traceID).Scan(&name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("select root span: %w", err)
+	}
+	return name, nil
+}
+
+func (s *Store) spans(ctx context.Context, traceID string) ([]*Span, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT trace_id, span_id, parent_span_id, name, kind, operation, "+
+			"start_ns, end_ns, status_code, status_message, input_tokens, "+
+			"output_tokens, attributes, events, links FROM "+s.table+
+			" WHERE trace_id = ? ORDER BY start_ns, span_id", traceID)
+	if err != nil {
+		return nil, fmt.Errorf("select spans: %w", err)
+	}
+	defer rows.Close()
+
+	var spans []*Span
+	for rows.Next() {
+		var (
+			sp                   Span
+			start, end           int64
+			attrs, events, links string
+		)
+		if err := rows.Scan(&sp.TraceID, &sp.SpanID, &sp.ParentSpanID,
+			&sp.Name, &sp.Kind, &sp.Operation, &start, &end, &sp.StatusCode,
+			&sp.StatusMessage, &sp.InputTokens, &sp.OutputTokens, &attrs,
+			&events, &links); err != nil {
+			return nil, fmt.Errorf("scan span: %w", err)
+		}
+		sp.StartTime = time.Unix(0, start)
+		sp.EndTime = time.Unix(0, end)
+		if err := json.Unmarshal([]byte(attrs), &sp.Attributes); err != nil {
+			return nil, fmt.Errorf("unmarshal attributes: %w", err)
+		}
+		if err := json.Unmarshal([]byte(events), &sp.Events); err != nil {
+			return nil, fmt.Errorf("unmarshal events: %w", err)
+		}
+		if err := json.Unmarshal([]byte(links), &sp.Links); err != nil {
+			return nil, fmt.Errorf("unmarshal links: %w", err)
+		}
+		spans = append(spans, &sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate spans: %w", err)
+	}
+	return spans, nil
+}
+
+// buildTree links spans to their parents and returns the roots. spans
+// must be sorted by start time; children keep that order.
+func buildTree(spans []*Span) []*Span {
+	byID := make(map[string]*Span, len(spans))
+	for _, sp := range spans {
+		byID[sp.SpanID] = sp
+	}
+	var roots []*Span
+	for _, sp := range spans {
+		if parent, ok := byID[sp.ParentSpanID]; ok && parent != sp {
+			parent.Children = append(parent.Children, sp)
+			continue
+		}
+		roots = append(roots, sp)
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].StartTime.Before(roots[j].StartTime)
+	})
+	return roots
+}
+
+func (s *Store) truncate(attrs map[string]any) map[string]any {
+	if s.maxAttributeBytes <= 0 || len(attrs) == 0 {
+		return attrs
+	}
+	out := make(map[string]any, len(attrs))
+	for k, v := range attrs {
+		if str, ok := v.(string); ok && len(str) > s.maxAttributeBytes {
+			cut := s.maxAttributeBytes
+			for cut > 0 && !utf8.RuneStart(str[cut]) {
+				cut--
+			}
+			v = str[:cut] + truncatedSuffix
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -40,6 +40,13 @@ const (
 	// KeyRunnerOutput is the attribute key for runner output.
 	KeyRunnerOutput = "trpc.go.agent.runner.output"
 
+	// KeyPromptNames is the attribute key for the names of the versioned
+	// prompts used by an agent invocation.
+	KeyPromptNames = "trpc.go.agent.prompt.names"
+	// KeyPromptVersions is the attribute key for the versions of the
+	// prompts listed in KeyPromptNames, in the same order.
+	KeyPromptVersions = "trpc.go.agent.prompt.versions"
+
 	// KeyTRPCAgentGoAppName is the attribute key for application name.
 	KeyTRPCAgentGoAppName = "trpc_go_agent.app.name"
 	// KeyTRPCAgentGoUserID is the attribute key for user ID.
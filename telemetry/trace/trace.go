@@ -109,6 +109,7 @@ type options struct {
 	headers             map[string]string // Headers to send with the request
 	resourceAttributes  *[]attribute.KeyValue
 	spanAttributePolicy *SpanAttributePolicy
+	exporterWrappers    []func(sdktrace.SpanExporter) sdktrace.SpanExporter
 }
 
 // WithEndpoint sets the traces endpoint(host and port) the Exporter will connect to.
@@ -196,6 +197,24 @@ func WithHeaders(headers map[string]string) Option {
 	}
 }
 
+// WithSpanExporterWrapper wraps the OTLP span exporter, for example to
+// scrub span attributes before they leave the process. Wrappers are applied
+// in order, so the last one sees spans first.
+func WithSpanExporterWrapper(wrap func(sdktrace.SpanExporter) sdktrace.SpanExporter) Option {
+	return func(opts *options) {
+		if wrap != nil {
+			opts.exporterWrappers = append(opts.exporterWrappers, wrap)
+		}
+	}
+}
+
+func (o *options) wrapExporter(exporter sdktrace.SpanExporter) sdktrace.SpanExporter {
+	for _, wrap := range o.exporterWrappers {
+		exporter = wrap(exporter)
+	}
+	return exporter
+}
+
 func buildResource(ctx context.Context, options *options) (*resource.Resource, error) {
 	// Build resource with options values
 	resourceOpts := []resource.Option{
@@ -289,7 +308,7 @@ func initGRPCTracerProvider(ctx context.Context, res *resource.Resource, opts *o
 		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
 	}
 
-	return setupTracerProvider(res, traceExporter), nil
+	return setupTracerProvider(res, opts.wrapExporter(traceExporter)), nil
 }
 
 // Initializes an OTLP HTTP exporter, and configures the corresponding trace provider.
@@ -317,7 +336,7 @@ func initHTTPTracerProvider(ctx context.Context, res *resource.Resource, opts *o
 		return nil, fmt.Errorf("failed to create HTTP trace exporter: %w", err)
 	}
 
-	return setupTracerProvider(res, traceExporter), nil
+	return setupTracerProvider(res, opts.wrapExporter(traceExporter)), nil
 }
 
 // setupTracerProvider sets up the tracer provider with the given resource and exporter.
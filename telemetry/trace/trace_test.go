@@ -15,6 +15,8 @@ import (
 	"testing"
 
 	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
@@ -288,3 +290,28 @@ func TestStartHTTP_WithURL_NoScheme(t *testing.T) {
 	}
 	_ = clean()
 }
+
+type namedExporter struct {
+	sdktrace.SpanExporter
+	name string
+}
+
+func TestWithSpanExporterWrapper(t *testing.T) {
+	opts := &options{}
+	for _, name := range []string{"inner", "outer"} {
+		name := name
+		WithSpanExporterWrapper(func(next sdktrace.SpanExporter) sdktrace.SpanExporter {
+			return &namedExporter{SpanExporter: next, name: name}
+		})(opts)
+	}
+	WithSpanExporterWrapper(nil)(opts)
+	base := tracetest.NewInMemoryExporter()
+	outer, ok := opts.wrapExporter(base).(*namedExporter)
+	if !ok || outer.name != "outer" {
+		t.Fatalf("expected outer wrapper, got %#v", outer)
+	}
+	inner, ok := outer.SpanExporter.(*namedExporter)
+	if !ok || inner.name != "inner" || inner.SpanExporter != base {
+		t.Fatalf("expected inner wrapper around base exporter, got %#v", inner)
+	}
+}
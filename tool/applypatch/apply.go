@@ -0,0 +1,412 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxReportLines bounds the lines quoted in a hunk failure report.
+	maxReportLines = 20
+)
+
+// document is a text file split into lines.
+type document struct {
+	lines []string
+	// eol reports whether the last line ends with a newline.
+	eol bool
+	// crlf reports whether the file uses CRLF line endings, which are
+	// kept when the file is written back.
+	crlf bool
+}
+
+func newDocument(data []byte) *document {
+	d := &document{}
+	if len(data) == 0 {
+		return d
+	}
+	text := string(data)
+	d.crlf = strings.Contains(text, "\r\n")
+	if d.crlf {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	}
+	d.eol = strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+	d.lines = strings.Split(text, "\n")
+	return d
+}
+
+func (d *document) bytes() []byte {
+	if len(d.lines) == 0 {
+		return []byte{}
+	}
+	nl := "\n"
+	if d.crlf {
+		nl = "\r\n"
+	}
+	text := strings.Join(d.lines, nl)
+	if d.eol {
+		text += nl
+	}
+	return []byte(text)
+}
+
+// isBinary reports whether data looks like a binary file.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// matchLevel is how loosely hunk lines are compared with file lines.
+// Looser levels are tried only when stricter ones find no match.
+type matchLevel int
+
+const (
+	matchExact matchLevel = iota
+	matchTrailingSpace
+	matchSpace
+	matchPunctuation
+)
+
+// punctuation maps typographic characters that models tend to produce
+// to their ASCII equivalents.
+var punctuation = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`,
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "−", "-",
+	" ", " ", " ", " ", " ", " ", " ", " ", " ", " ",
+	"…", "...",
+)
+
+func linesEqual(a, b string, level matchLevel) bool {
+	switch level {
+	case matchExact:
+		return a == b
+	case matchTrailingSpace:
+		return strings.TrimRight(a, " \t") == strings.TrimRight(b, " \t")
+	case matchSpace:
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	default:
+		return strings.TrimSpace(punctuation.Replace(a)) == strings.TrimSpace(punctuation.Replace(b))
+	}
+}
+
+// hunkResult reports how a hunk applied.
+type hunkResult struct {
+	Hunk    int    `json:"hunk"`
+	Header  string `json:"header,omitempty"`
+	Applied bool   `json:"applied"`
+	// Line is the line of the original file where the hunk applied.
+	Line  int    `json:"line,omitempty"`
+	Note  string `json:"note,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// match is where a hunk applies.
+type match struct {
+	pos   int
+	level matchLevel
+	// fuzz is the number of context lines allowed to be dropped from
+	// each end, and lead the number dropped from the start.
+	fuzz  int
+	lead  int
+	lines []hunkLine
+}
+
+// applyHunks applies hunks to doc in order. Hunks that do not apply are
+// reported and leave doc unchanged; the caller discards doc when any
+// hunk failed.
+func applyHunks(doc *document, hunks []*hunk, maxFuzz int) ([]hunkResult, bool) {
+	results := make([]hunkResult, 0, len(hunks))
+	ok := true
+	cursor, delta := 0, 0
+	for i, h := range hunks {
+		res := hunkResult{Hunk: i + 1, Header: h.header}
+		start := cursor
+		if h.anchor != "" {
+			idx := findLine(doc.lines, h.anchor, cursor)
+			if idx < 0 {
+				res.Error = fmt.Sprintf("the anchor line %q was not found after line %d", h.anchor, cursor-delta)
+				results = append(results, res)
+				ok = false
+				continue
+			}
+			start = idx + 1
+		}
+		hint := -1
+		if h.hasRange {
+			hint = max(0, h.oldStart-1+delta)
+			if len(h.oldLines()) == 0 {
+				// "-n,0" inserts after line n.
+				hint = h.oldStart + delta
+			}
+		}
+		m, found := findHunk(doc.lines, h, start, hint, maxFuzz)
+		if !found {
+			res.Error = failureReport(doc.lines, h, start, delta)
+			results = append(results, res)
+			ok = false
+			continue
+		}
+		pos := m.pos
+		fi := pos
+		var repl []string
+		for _, l := range m.lines {
+			switch l.kind {
+			case ' ':
+				// Keep the file's version of context lines, which may
+				// differ in whitespace.
+				repl = append(repl, doc.lines[fi])
+				fi++
+			case '-':
+				fi++
+			case '+':
+				repl = append(repl, l.text)
+			}
+		}
+		atEnd := fi == len(doc.lines)
+		lines := make([]string, 0, len(doc.lines)-(fi-pos)+len(repl))
+		lines = append(lines, doc.lines[:pos]...)
+		lines = append(lines, repl...)
+		lines = append(lines, doc.lines[fi:]...)
+		if len(doc.lines) == 0 {
+			doc.eol = !h.newNoEOL
+		} else if atEnd && h.newNoEOL {
+			doc.eol = false
+		} else if atEnd && h.oldNoEOL {
+			doc.eol = true
+		}
+		doc.lines = lines
+
+		res.Applied = true
+		res.Line = pos - delta + 1
+		res.Note = matchNote(h, m, res.Line)
+		results = append(results, res)
+		cursor = pos + len(repl)
+		delta += len(repl) - (fi - pos)
+	}
+	return results, ok
+}
+
+// findLine returns the first line at or after start that equals text,
+// comparing ever more loosely.
+func findLine(lines []string, text string, start int) int {
+	for level := matchExact; level <= matchPunctuation; level++ {
+		for i := start; i < len(lines); i++ {
+			if linesEqual(lines[i], text, level) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findHunk locates a hunk at or after start. It tries exact matches
+// first, then ignores whitespace and typographic punctuation, and then
+// drops up to maxFuzz context lines from each end of the hunk. When a
+// hint is known, the match nearest to it wins.
+func findHunk(lines []string, h *hunk, start, hint, maxFuzz int) (match, bool) {
+	for fuzz := 0; fuzz <= maxFuzz; fuzz++ {
+		hl, lead, trimmed, atEOF := trimContext(h, fuzz)
+		if fuzz > 0 && !trimmed {
+			break
+		}
+		old := oldOf(hl)
+		if len(old) == 0 {
+			if fuzz > 0 {
+				break
+			}
+			return match{pos: insertPos(lines, start, hint), lines: hl}, true
+		}
+		for level := matchExact; level <= matchPunctuation; level++ {
+			if pos := search(lines, old, start, hint, atEOF, level); pos >= 0 {
+				return match{pos: pos, level: level, fuzz: fuzz, lead: lead, lines: hl}, true
+			}
+		}
+	}
+	return match{}, false
+}
+
+// trimContext drops up to n context lines from each end of the hunk. It
+// returns the number dropped from the start, whether any line was
+// dropped and whether the trimmed hunk must still match the end of the
+// file.
+func trimContext(h *hunk, n int) ([]hunkLine, int, bool, bool) {
+	lines := h.lines
+	lead, trail := 0, 0
+	for lead < n && lead < len(lines) && lines[lead].kind == ' ' {
+		lead++
+	}
+	for trail < n && trail < len(lines)-lead && lines[len(lines)-1-trail].kind == ' ' {
+		trail++
+	}
+	return lines[lead : len(lines)-trail], lead, lead+trail > 0, h.atEOF && trail == 0
+}
+
+func oldOf(lines []hunkLine) []string {
+	var out []string
+	for _, l := range lines {
+		if l.kind != '+' {
+			out = append(out, l.text)
+		}
+	}
+	return out
+}
+
+func newOf(lines []hunkLine) []string {
+	var out []string
+	for _, l := range lines {
+		if l.kind != '-' {
+			out = append(out, l.text)
+		}
+	}
+	return out
+}
+
+// insertPos returns where a hunk without context or removed lines is
+// inserted: at its hint when known and at the end of the file otherwise.
+func insertPos(lines []string, start, hint int) int {
+	if hint < 0 {
+		return len(lines)
+	}
+	return max(start, min(hint, len(lines)))
+}
+
+// search returns the position of old in lines at or after start, or -1.
+func search(lines, old []string, start, hint int, atEOF bool, level matchLevel) int {
+	last := len(lines) - len(old)
+	if last < start {
+		return -1
+	}
+	if atEOF && matchAt(lines, old, last, level) {
+		return last
+	}
+	if hint < 0 {
+		for pos := start; pos <= last; pos++ {
+			if matchAt(lines, old, pos, level) {
+				return pos
+			}
+		}
+		return -1
+	}
+	hint = max(start, min(hint, last))
+	for d := 0; hint-d >= start || hint+d <= last; d++ {
+		if pos := hint + d; pos <= last && matchAt(lines, old, pos, level) {
+			return pos
+		}
+		if pos := hint - d; d > 0 && pos >= start && matchAt(lines, old, pos, level) {
+			return pos
+		}
+	}
+	return -1
+}
+
+func matchAt(lines, old []string, pos int, level matchLevel) bool {
+	for i, l := range old {
+		if !linesEqual(lines[pos+i], l, level) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNote describes a match that needed an offset, loose comparison or
+// fuzz, or returns "" for a clean match.
+func matchNote(h *hunk, m match, line int) string {
+	var notes []string
+	if h.hasRange && len(h.oldLines()) > 0 && line-m.lead != h.oldStart {
+		notes = append(notes, fmt.Sprintf("offset %+d lines", line-m.lead-h.oldStart))
+	}
+	switch m.level {
+	case matchTrailingSpace:
+		notes = append(notes, "trailing whitespace ignored")
+	case matchSpace:
+		notes = append(notes, "whitespace ignored")
+	case matchPunctuation:
+		notes = append(notes, "whitespace and punctuation ignored")
+	}
+	if m.fuzz > 0 {
+		notes = append(notes, fmt.Sprintf("fuzz %d", m.fuzz))
+	}
+	return strings.Join(notes, ", ")
+}
+
+// failureReport explains why a hunk did not apply, quoting the lines it
+// expected and the closest lines found in the file.
+func failureReport(lines []string, h *hunk, start, delta int) string {
+	old := h.oldLines()
+	if produced := newOf(h.lines); len(produced) > 0 &&
+		search(lines, produced, 0, -1, false, matchSpace) >= 0 &&
+		search(lines, old, 0, -1, false, matchSpace) < 0 {
+		return "the lines this hunk produces are already in the file; it may have been applied already."
+	}
+	var b strings.Builder
+	if start > 0 {
+		fmt.Fprintf(&b, "the expected lines were not found after line %d.", start-delta)
+	} else {
+		b.WriteString("the expected lines were not found.")
+	}
+	b.WriteString("\nExpected:\n")
+	writeLines(&b, old, 0, nil)
+	pos, score := closest(lines, old)
+	if score == 0 {
+		b.WriteString("No similar lines were found in the file.")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Closest match at line %d (%d of %d lines match):\n", pos-delta+1, score, len(old))
+	end := min(pos+len(old), len(lines))
+	differs := make([]bool, end-pos)
+	for i := range differs {
+		differs[i] = !linesEqual(lines[pos+i], old[i], matchSpace)
+	}
+	writeLines(&b, lines[pos:end], pos-delta+1, differs)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeLines quotes lines, numbered from first when it is positive and
+// marking the ones that differ.
+func writeLines(b *strings.Builder, lines []string, first int, differs []bool) {
+	for i, l := range lines {
+		if i == maxReportLines {
+			fmt.Fprintf(b, "  ... %d more lines\n", len(lines)-i)
+			return
+		}
+		mark := " "
+		if differs != nil && differs[i] {
+			mark = "!"
+		}
+		if first > 0 {
+			fmt.Fprintf(b, "%s %5d | %s\n", mark, first+i, l)
+		} else {
+			fmt.Fprintf(b, "  | %s\n", l)
+		}
+	}
+}
+
+// closest returns the window of lines that shares the most lines with
+// old, ignoring whitespace.
+func closest(lines, old []string) (int, int) {
+	best, bestScore := 0, 0
+	for pos := 0; pos < len(lines); pos++ {
+		score := 0
+		for i := 0; i < len(old) && pos+i < len(lines); i++ {
+			if strings.TrimSpace(old[i]) != "" && linesEqual(lines[pos+i], old[i], matchSpace) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = pos, score
+		}
+	}
+	return best, bestScore
+}
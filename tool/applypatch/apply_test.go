@@ -0,0 +1,140 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyText(t *testing.T, content, patch string) (string, []hunkResult, bool) {
+	t.Helper()
+	patches, err := parsePatch(patch)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	doc := newDocument([]byte(content))
+	results, ok := applyHunks(doc, patches[0].hunks, defaultMaxFuzz)
+	return string(doc.bytes()), results, ok
+}
+
+const sample = "package main\n\nfunc a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 1\n}\n"
+
+func TestApplyHunks_Offset(t *testing.T) {
+	// The line numbers are off by two and the first hunk grows the file.
+	out, results, ok := applyText(t, sample, `--- a/m.go
++++ b/m.go
+@@ -1,2 +1,3 @@
+ package main
++// Package main.
+
+@@ -9,3 +10,3 @@
+ func b() {
+-	return 1
++	return 2
+ }
+`)
+	require.True(t, ok, results)
+	assert.Equal(t, "package main\n// Package main.\n\nfunc a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n", out)
+	assert.Equal(t, "", results[0].Note)
+	assert.Equal(t, 7, results[1].Line)
+	assert.Equal(t, "offset -2 lines", results[1].Note)
+}
+
+func TestApplyHunks_Anchor(t *testing.T) {
+	// Without the anchor the first "return 1" would be replaced.
+	out, results, ok := applyText(t, sample, "*** Begin Patch\n*** Update File: m.go\n@@ func b() {\n-\treturn 1\n+\treturn 2\n*** End Patch")
+	require.True(t, ok, results)
+	assert.Equal(t, "package main\n\nfunc a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n", out)
+}
+
+func TestApplyHunks_Loose(t *testing.T) {
+	content := "if x {\n\tlog(“hi”)   \n}\n"
+	out, results, ok := applyText(t, content, "*** Begin Patch\n*** Update File: f\n if x {\n-    log(\"hi\")\n+\tlog(\"bye\")\n }\n*** End Patch")
+	require.True(t, ok, results)
+	assert.Equal(t, "if x {\n\tlog(\"bye\")\n}\n", out)
+	assert.Equal(t, "whitespace and punctuation ignored", results[0].Note)
+
+	// Context lines keep the file's whitespace.
+	out, results, ok = applyText(t, "a  \nb\nc\n", "--- f\n+++ f\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n")
+	require.True(t, ok, results)
+	assert.Equal(t, "a  \nB\nc\n", out)
+	assert.Equal(t, "trailing whitespace ignored", results[0].Note)
+}
+
+func TestApplyHunks_Fuzz(t *testing.T) {
+	// The first context line is stale, so it is dropped.
+	out, results, ok := applyText(t, "one\ntwo\nthree\nfour\n",
+		"--- f\n+++ f\n@@ -1,4 +1,4 @@\n uno\n two\n-three\n+THREE\n four\n")
+	require.True(t, ok, results)
+	assert.Equal(t, "one\ntwo\nTHREE\nfour\n", out)
+	assert.Equal(t, "fuzz 1", results[0].Note)
+
+	patches, err := parsePatch("--- f\n+++ f\n@@ -1,4 +1,4 @@\n uno\n two\n-three\n+THREE\n four\n")
+	require.NoError(t, err)
+	_, ok = applyHunks(newDocument([]byte("one\ntwo\nthree\nfour\n")), patches[0].hunks, 0)
+	assert.False(t, ok)
+}
+
+func TestApplyHunks_EndOfFile(t *testing.T) {
+	// "*** End of File" selects the last match.
+	out, results, ok := applyText(t, "x\nend\nx\nend\n", "*** Begin Patch\n*** Update File: f\n x\n-end\n+END\n*** End of File\n*** End Patch")
+	require.True(t, ok, results)
+	assert.Equal(t, "x\nend\nx\nEND\n", out)
+
+	// Trailing newlines are added and removed as the diff says.
+	out, _, ok = applyText(t, "a\nb", "--- f\n+++ f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n")
+	require.True(t, ok)
+	assert.Equal(t, "a\nc\n", out)
+	out, _, ok = applyText(t, "a\nb\n", "--- f\n+++ f\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n")
+	require.True(t, ok)
+	assert.Equal(t, "a\nc", out)
+}
+
+func TestApplyHunks_CRLF(t *testing.T) {
+	out, results, ok := applyText(t, "a\r\nb\r\n", "--- f\n+++ f\n@@ -1,2 +1,3 @@\n a\n b\n+c\n")
+	require.True(t, ok, results)
+	assert.Equal(t, "a\r\nb\r\nc\r\n", out)
+}
+
+func TestApplyHunks_Insert(t *testing.T) {
+	out, _, ok := applyText(t, "a\nb\n", "--- f\n+++ f\n@@ -1,0 +2 @@\n+inserted\n")
+	require.True(t, ok)
+	assert.Equal(t, "a\ninserted\nb\n", out)
+	out, _, ok = applyText(t, "", "--- f\n+++ f\n@@ -0,0 +1 @@\n+first\n")
+	require.True(t, ok)
+	assert.Equal(t, "first\n", out)
+}
+
+func TestApplyHunks_FailureReport(t *testing.T) {
+	_, results, ok := applyText(t, sample, `--- a/m.go
++++ b/m.go
+@@ -7,3 +7,3 @@
+ func b() {
+-	return 3
++	return 4
+ }
+@@ -1 +1 @@
+-package mian
++package main2
+`)
+	require.False(t, ok)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Applied)
+	assert.Contains(t, results[0].Error, "Expected:\n  | func b() {\n  | \treturn 3\n")
+	assert.Contains(t, results[0].Error, "Closest match at line 7 (2 of 3 lines match):\n      7 | func b() {\n!     8 | \treturn 1\n")
+
+	// A hunk that was applied already is recognized.
+	_, results, ok = applyText(t, sample, "--- f\n+++ f\n@@ -7,3 +7,3 @@\n func b() {\n-\treturn 0\n+\treturn 1\n }\n")
+	require.False(t, ok)
+	assert.Contains(t, results[0].Error, "may have been applied already")
+}
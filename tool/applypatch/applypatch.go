@@ -0,0 +1,412 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package applypatch provides the apply_patch tool, which edits several
+// files in one call from a patch.
+//
+// The tool accepts unified diffs, as produced by diff -u and git diff,
+// and a simpler format in which the model lists files to add, update,
+// move or delete:
+//
+//	*** Begin Patch
+//	*** Update File: pkg/server.go
+//	@@ func (s *Server) Start() error {
+//	-	return s.listen(":8080")
+//	+	return s.listen(s.addr)
+//	*** Add File: pkg/addr.go
+//	+package pkg
+//	*** Delete File: pkg/old.go
+//	*** End Patch
+//
+// Hunks are located by their context lines, so line numbers may be
+// wrong. When a hunk does not match exactly, whitespace and typographic
+// punctuation are ignored and then up to a few context lines are
+// dropped. A patch is applied atomically: when any hunk fails, no file
+// is changed and the result explains which hunks failed and quotes the
+// closest lines found in the file.
+//
+// Patches are applied to a base directory of the host filesystem, or,
+// with WithWorkspace, to the workspace of the current invocation
+// through codeexecutor/workspaceio.
+package applypatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+const (
+	// defaultToolName is the default name of the tool.
+	defaultToolName = "apply_patch"
+	// defaultBaseDir is the default base directory on the host.
+	defaultBaseDir = "."
+	// defaultMaxFileSize is the default size of files that can be
+	// patched, which is 1MB.
+	defaultMaxFileSize = 1024 * 1024
+	// defaultMaxFuzz is the default number of context lines that may be
+	// dropped from each end of a hunk.
+	defaultMaxFuzz = 2
+)
+
+// Option is a functional option for configuring the apply_patch tool.
+type Option func(*config)
+
+type config struct {
+	name        string
+	baseDir     string
+	workspace   bool
+	maxFileSize int64
+	maxFuzz     int
+}
+
+// WithName sets the name of the tool, default is "apply_patch".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithBaseDir sets the host directory that patch paths are relative to,
+// default is the current directory. Paths cannot leave it, including
+// through symlinks.
+func WithBaseDir(dir string) Option {
+	return func(c *config) {
+		c.baseDir = dir
+	}
+}
+
+// WithWorkspace applies patches to the workspace of the current
+// invocation instead of the host filesystem. Paths are relative to the
+// workspace root and must be under work/, out/ or runs/. The agent needs
+// a code executor that supports workspaces; deleting and moving files
+// also needs one that can run programs. Workspace backends do not report
+// file modes, so written files get the backend's default mode.
+func WithWorkspace() Option {
+	return func(c *config) {
+		c.workspace = true
+	}
+}
+
+// WithMaxFileSize sets the size of the largest file that can be
+// patched, default is 1MB.
+func WithMaxFileSize(n int64) Option {
+	return func(c *config) {
+		c.maxFileSize = n
+	}
+}
+
+// WithMaxFuzz sets how many context lines may be dropped from each end
+// of a hunk that does not otherwise match, default is 2. Zero requires
+// all context lines to match.
+func WithMaxFuzz(n int) Option {
+	return func(c *config) {
+		c.maxFuzz = n
+	}
+}
+
+// NewTool creates the apply_patch tool.
+func NewTool(opts ...Option) (tool.CallableTool, error) {
+	c := &config{
+		name:        defaultToolName,
+		baseDir:     defaultBaseDir,
+		maxFileSize: defaultMaxFileSize,
+		maxFuzz:     defaultMaxFuzz,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxFuzz < 0 {
+		return nil, errors.New("apply_patch: max fuzz must not be negative")
+	}
+	t := &applyPatchTool{maxFuzz: c.maxFuzz}
+	if c.workspace {
+		t.fs = &workspaceFS{maxFileSize: c.maxFileSize}
+	} else {
+		dir := filepath.Clean(c.baseDir)
+		st, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("apply_patch: base directory '%s' does not exist: %w", dir, err)
+		}
+		if !st.IsDir() {
+			return nil, fmt.Errorf("apply_patch: base directory '%s' is not a directory", dir)
+		}
+		t.fs = &hostFS{root: dir, maxFileSize: c.maxFileSize}
+	}
+	return function.NewFunctionTool(
+		t.apply,
+		function.WithName(c.name),
+		function.WithDescription(description),
+	), nil
+}
+
+const description = "Apply a patch that adds, updates, moves or deletes files. " +
+	"Accepts a unified diff (as from `git diff`) or this format:\n" +
+	"*** Begin Patch\n" +
+	"*** Update File: path/to/file\n" +
+	"@@ optional line before the change, such as a function signature\n" +
+	" context line\n" +
+	"-removed line\n" +
+	"+added line\n" +
+	"*** Add File: path/to/new_file\n" +
+	"+file content\n" +
+	"*** Delete File: path/to/old_file\n" +
+	"*** End Patch\n" +
+	"An update may be followed by \"*** Move to: new/path\". " +
+	"Include about 3 unchanged context lines around each change; line numbers are optional. " +
+	"All changes apply or none do. When hunks fail, fix them using the report and send the whole patch again."
+
+// applyPatchRequest is the input of the tool.
+type applyPatchRequest struct {
+	Patch  string `json:"patch" jsonschema:"description=Unified diff or *** Begin Patch ... *** End Patch text to apply"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"description=Check that the patch applies without changing files"`
+}
+
+// applyPatchResponse is the output of the tool.
+type applyPatchResponse struct {
+	Applied bool         `json:"applied"`
+	DryRun  bool         `json:"dry_run,omitempty"`
+	Files   []fileResult `json:"files"`
+	Message string       `json:"message"`
+}
+
+// fileResult reports the change to one file.
+type fileResult struct {
+	Path      string       `json:"path"`
+	Operation string       `json:"operation"`
+	MoveTo    string       `json:"move_to,omitempty"`
+	Hunks     []hunkResult `json:"hunks,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+type applyPatchTool struct {
+	fs      fileSystem
+	maxFuzz int
+}
+
+// fileState tracks a file while the patch is applied in memory.
+type fileState struct {
+	data    []byte
+	exists  bool
+	old     []byte
+	existed bool
+}
+
+func (s *fileState) changed() bool {
+	if s.exists != s.existed {
+		return true
+	}
+	return s.exists && string(s.data) != string(s.old)
+}
+
+// apply applies every file patch in memory first and only writes files
+// when all of them succeed.
+func (t *applyPatchTool) apply(ctx context.Context, req *applyPatchRequest) (*applyPatchResponse, error) {
+	patches, err := parsePatch(req.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	var (
+		order []string
+		files = make(map[string]*fileState)
+	)
+	load := func(path string) (*fileState, error) {
+		if st, ok := files[path]; ok {
+			return st, nil
+		}
+		data, exists, err := t.fs.read(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		st := &fileState{data: data, exists: exists, old: data, existed: exists}
+		files[path] = st
+		order = append(order, path)
+		return st, nil
+	}
+
+	rsp := &applyPatchResponse{DryRun: req.DryRun, Files: make([]fileResult, 0, len(patches))}
+	ok := true
+	for _, fp := range patches {
+		res := t.applyFile(fp, load)
+		if res.Error != "" {
+			ok = false
+		}
+		rsp.Files = append(rsp.Files, res)
+	}
+	if !ok {
+		rsp.Message = failureMessage(rsp.Files)
+		return rsp, nil
+	}
+
+	var changes []change
+	for _, path := range order {
+		st := files[path]
+		if !st.changed() {
+			continue
+		}
+		changes = append(changes, change{
+			path:    path,
+			data:    st.data,
+			remove:  !st.exists,
+			old:     st.old,
+			existed: st.existed,
+		})
+	}
+	if !req.DryRun {
+		if err := t.fs.commit(ctx, changes); err != nil {
+			return nil, fmt.Errorf("apply patch: %w", err)
+		}
+	}
+	rsp.Applied = !req.DryRun
+	rsp.Message = successMessage(rsp.Files, req.DryRun)
+	return rsp, nil
+}
+
+// applyFile applies one file patch to the in-memory file states.
+func (t *applyPatchTool) applyFile(
+	fp *filePatch,
+	load func(string) (*fileState, error),
+) fileResult {
+	res := fileResult{Path: fp.path, Operation: string(fp.op)}
+	if fp.moveTo != "" {
+		res.Operation, res.MoveTo = "move", fp.moveTo
+	}
+	fail := func(format string, args ...any) fileResult {
+		res.Error = fmt.Sprintf(format, args...)
+		return res
+	}
+	path, err := t.fs.clean(fp.path)
+	if err != nil {
+		return fail("%v", err)
+	}
+	st, err := load(path)
+	if err != nil {
+		return fail("%v", err)
+	}
+	switch fp.op {
+	case opAdd:
+		if st.exists {
+			return fail("file already exists; update it instead of adding it")
+		}
+		doc := &document{lines: fp.lines, eol: !fp.noEOL}
+		st.data, st.exists = doc.bytes(), true
+		return res
+	case opDelete:
+		if !st.exists {
+			return fail("file does not exist")
+		}
+		st.data, st.exists = nil, false
+		return res
+	}
+
+	if !st.exists {
+		return fail("file does not exist; add it instead of updating it")
+	}
+	data := st.data
+	if len(fp.hunks) > 0 {
+		if isBinary(st.data) {
+			return fail("binary files cannot be patched")
+		}
+		doc := newDocument(st.data)
+		hunks, applied := applyHunks(doc, fp.hunks, t.maxFuzz)
+		res.Hunks = hunks
+		if !applied {
+			failed := 0
+			for _, h := range hunks {
+				if !h.Applied {
+					failed++
+				}
+			}
+			return fail("%d of %d hunks failed", failed, len(hunks))
+		}
+		data = doc.bytes()
+	}
+	if fp.moveTo == "" {
+		st.data = data
+		return res
+	}
+	dst, err := t.fs.clean(fp.moveTo)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if dst == path {
+		st.data = data
+		return res
+	}
+	dstState, err := load(dst)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if dstState.exists {
+		return fail("cannot move to %s: file already exists", fp.moveTo)
+	}
+	dstState.data, dstState.exists = data, true
+	st.data, st.exists = nil, false
+	return res
+}
+
+func failureMessage(files []fileResult) string {
+	var b strings.Builder
+	b.WriteString("The patch was not applied and no files were changed. " +
+		"Fix the problems below, re-reading files if needed, and send the whole patch again.")
+	for _, f := range files {
+		if f.Error == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n%s: %s", f.Path, f.Error)
+		for _, h := range f.Hunks {
+			if h.Applied {
+				continue
+			}
+			fmt.Fprintf(&b, "\nHunk %d", h.Hunk)
+			if h.Header != "" && h.Header != "@@" {
+				fmt.Fprintf(&b, " (%s)", h.Header)
+			}
+			fmt.Fprintf(&b, ": %s", h.Error)
+		}
+	}
+	return b.String()
+}
+
+func successMessage(files []fileResult, dryRun bool) string {
+	var done, notes []string
+	for _, f := range files {
+		switch f.Operation {
+		case "move":
+			done = append(done, fmt.Sprintf("moved %s to %s", f.Path, f.MoveTo))
+		case string(opAdd):
+			done = append(done, "added "+f.Path)
+		case string(opDelete):
+			done = append(done, "deleted "+f.Path)
+		default:
+			done = append(done, "updated "+f.Path)
+		}
+		for _, h := range f.Hunks {
+			if h.Note != "" {
+				notes = append(notes, fmt.Sprintf("%s hunk %d applied at line %d (%s)", f.Path, h.Hunk, h.Line, h.Note))
+			}
+		}
+	}
+	msg := "Applied the patch: " + strings.Join(done, ", ") + "."
+	if dryRun {
+		msg = "The patch applies cleanly; no files were changed. It would have " +
+			strings.Join(done, ", ") + "."
+	}
+	if len(notes) > 0 {
+		msg += " Inexact matches: " + strings.Join(notes, "; ") + "."
+	}
+	return msg
+}
@@ -0,0 +1,302 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor"
+	localexec "trpc.group/trpc-go/trpc-agent-go/codeexecutor/local"
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor/workspaceio"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+func callPatch(t *testing.T, ctx context.Context, tl tool.CallableTool, patch string, dryRun bool) (*applyPatchResponse, error) {
+	t.Helper()
+	args, err := json.Marshal(applyPatchRequest{Patch: patch, DryRun: dryRun})
+	require.NoError(t, err)
+	out, err := tl.Call(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return out.(*applyPatchResponse), nil
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestNewTool(t *testing.T) {
+	tl, err := NewTool()
+	require.NoError(t, err)
+	assert.Equal(t, "apply_patch", tl.Declaration().Name)
+	assert.Contains(t, tl.Declaration().InputSchema.Properties, "patch")
+
+	_, err = NewTool(WithBaseDir(filepath.Join(t.TempDir(), "missing")))
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = NewTool(WithMaxFuzz(-1))
+	assert.ErrorContains(t, err, "max fuzz")
+}
+
+func TestApplyPatch_Host(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":   "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n",
+		"old.txt":   "old\n",
+		"rename.md": "# Doc\n",
+	})
+	require.NoError(t, os.Chmod(filepath.Join(dir, "main.go"), 0o755))
+	tl, err := NewTool(WithBaseDir(dir))
+	require.NoError(t, err)
+
+	rsp, err := callPatch(t, context.Background(), tl, `*** Begin Patch
+*** Update File: main.go
+@@ func main() {
+-	println("hi")
++	println("hello")
+*** Add File: pkg/util/util.go
++package util
+*** Delete File: old.txt
+*** Update File: rename.md
+*** Move to: docs/README.md
+@@
+-# Doc
++# Docs
+*** End Patch`, false)
+	require.NoError(t, err)
+	require.True(t, rsp.Applied, rsp.Message)
+	assert.Equal(t, "Applied the patch: updated main.go, added pkg/util/util.go, deleted old.txt, "+
+		"moved rename.md to docs/README.md.", rsp.Message)
+
+	assert.Equal(t, "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n", readFile(t, dir, "main.go"))
+	st, err := os.Stat(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), st.Mode().Perm())
+	assert.Equal(t, "package util\n", readFile(t, dir, "pkg/util/util.go"))
+	assert.Equal(t, "# Docs\n", readFile(t, dir, "docs/README.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "old.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "rename.md"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".patch-", "temporary file left behind")
+	}
+}
+
+func TestApplyPatch_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "one\ntwo\n", "b.txt": "three\n"})
+	tl, err := NewTool(WithBaseDir(dir))
+	require.NoError(t, err)
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++TWO
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-four
++FOUR
+`
+	rsp, err := callPatch(t, context.Background(), tl, patch, false)
+	require.NoError(t, err)
+	assert.False(t, rsp.Applied)
+	require.Len(t, rsp.Files, 2)
+	assert.Empty(t, rsp.Files[0].Error)
+	assert.Equal(t, "1 of 1 hunks failed", rsp.Files[1].Error)
+	assert.Contains(t, rsp.Message, "no files were changed")
+	assert.Contains(t, rsp.Message, "b.txt: 1 of 1 hunks failed\nHunk 1 (@@ -1 +1 @@): the expected lines were not found.")
+	assert.Equal(t, "one\ntwo\n", readFile(t, dir, "a.txt"))
+
+	// A dry run checks the patch without writing.
+	rsp, err = callPatch(t, context.Background(), tl, "--- a/a.txt\n+++ b/a.txt\n@@ -2 +2 @@\n-two\n+TWO\n", true)
+	require.NoError(t, err)
+	assert.False(t, rsp.Applied)
+	assert.True(t, rsp.DryRun)
+	assert.Contains(t, rsp.Message, "applies cleanly")
+	assert.Equal(t, "one\ntwo\n", readFile(t, dir, "a.txt"))
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a\n"})
+	writeFiles(t, outside, map[string]string{"secret.txt": "s\n"})
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+	tl, err := NewTool(WithBaseDir(dir))
+	require.NoError(t, err)
+
+	for patch, want := range map[string]string{
+		"*** Begin Patch\n*** Add File: a.txt\n+x\n*** End Patch":                                                     "already exists",
+		"*** Begin Patch\n*** Delete File: missing.txt\n*** End Patch":                                                "does not exist",
+		"*** Begin Patch\n*** Update File: missing.txt\n-a\n+b\n*** End Patch":                                        "add it instead",
+		"*** Begin Patch\n*** Add File: ../escape.txt\n+x\n*** End Patch":                                             "stay inside it",
+		"*** Begin Patch\n*** Add File: /etc/escape.txt\n+x\n*** End Patch":                                           "stay inside it",
+		"*** Begin Patch\n*** Delete File: link/secret.txt\n*** End Patch":                                            "resolves outside",
+		"*** Begin Patch\n*** Update File: a.txt\n*** Move to: a.txt\n-a\n+b\n*** Add File: a.txt\n+c\n*** End Patch": "already exists",
+	} {
+		rsp, err := callPatch(t, context.Background(), tl, patch, false)
+		require.NoError(t, err, patch)
+		assert.False(t, rsp.Applied, patch)
+		assert.Contains(t, rsp.Message, want, patch)
+	}
+	assert.Equal(t, "a\n", readFile(t, dir, "a.txt"))
+	assert.FileExists(t, filepath.Join(outside, "secret.txt"))
+
+	_, err = callPatch(t, context.Background(), tl, "not a patch", false)
+	assert.ErrorContains(t, err, "invalid patch")
+}
+
+func TestApplyPatch_Workspace(t *testing.T) {
+	inv := agent.NewInvocation(agent.WithInvocationSession(&session.Session{
+		ID: "s1", AppName: "app", UserID: "user",
+	}))
+	ws := workspaceio.New(localexec.New(), codeexecutor.NewWorkspaceRegistry())
+	ctx := workspaceio.WithWorkspace(agent.NewInvocationContext(context.Background(), inv), ws)
+	require.NoError(t, ws.PutFiles(ctx,
+		codeexecutor.PutFile{Path: "work/app.py", Content: []byte("print('hi')\n")},
+		codeexecutor.PutFile{Path: "work/old.py", Content: []byte("pass\n")},
+	))
+	tl, err := NewTool(WithWorkspace())
+	require.NoError(t, err)
+
+	rsp, err := callPatch(t, ctx, tl, `--- a/work/app.py
++++ b/work/app.py
+@@ -1 +1,2 @@
+-print('hi')
++print('hello')
++print('bye')
+--- a/work/old.py
++++ /dev/null
+@@ -1 +0,0 @@
+-pass
+--- /dev/null
++++ b/out/report.txt
+@@ -0,0 +1 @@
++done
+`, false)
+	require.NoError(t, err)
+	require.True(t, rsp.Applied, rsp.Message)
+
+	files, err := ws.Collect(ctx, "work/*.py", "out/report.txt")
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, f := range files {
+		got[f.Path] = string(f.Data)
+	}
+	assert.Equal(t, map[string]string{
+		"work/app.py":    "print('hello')\nprint('bye')\n",
+		"out/report.txt": "done\n",
+	}, got)
+
+	rsp, err = callPatch(t, ctx, tl, "*** Begin Patch\n*** Add File: skills/x.md\n+x\n*** End Patch", false)
+	require.NoError(t, err)
+	assert.Contains(t, rsp.Message, "work/, out/, or runs/")
+
+	rsp, err = callPatch(t, context.Background(), tl, "*** Begin Patch\n*** Add File: work/x\n+x\n*** End Patch", false)
+	require.NoError(t, err)
+	assert.Contains(t, rsp.Message, "no workspace is bound")
+}
+
+// failingFS writes files one by one and fails on the file named fail,
+// leaving the files before it written.
+type failingFS struct {
+	codeexecutor.WorkspaceFS
+	fail string
+}
+
+func (f *failingFS) PutFiles(ctx context.Context, ws codeexecutor.Workspace, files []codeexecutor.PutFile) error {
+	for _, file := range files {
+		if file.Path == f.fail {
+			return errors.New("disk full")
+		}
+		if err := f.WorkspaceFS.PutFiles(ctx, ws, []codeexecutor.PutFile{file}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failingExec is a local executor whose workspace fails on PutFiles.
+type failingExec struct {
+	*localexec.CodeExecutor
+	eng codeexecutor.Engine
+}
+
+func (f *failingExec) Engine() codeexecutor.Engine { return f.eng }
+
+func TestApplyPatch_WorkspaceRollback(t *testing.T) {
+	local := localexec.New()
+	eng := local.Engine()
+	exec := &failingExec{CodeExecutor: local, eng: codeexecutor.NewEngine(
+		eng.Manager(), &failingFS{WorkspaceFS: eng.FS(), fail: "work/broken.txt"}, eng.Runner())}
+	inv := agent.NewInvocation(agent.WithInvocationSession(&session.Session{
+		ID: "s1", AppName: "app", UserID: "user",
+	}))
+	ws := workspaceio.New(exec, codeexecutor.NewWorkspaceRegistry())
+	ctx := workspaceio.WithWorkspace(agent.NewInvocationContext(context.Background(), inv), ws)
+	require.NoError(t, ws.PutFiles(ctx,
+		codeexecutor.PutFile{Path: "work/app.py", Content: []byte("print('hi')\n")},
+		codeexecutor.PutFile{Path: "work/old.py", Content: []byte("pass\n")},
+	))
+	tl, err := NewTool(WithWorkspace())
+	require.NoError(t, err)
+
+	_, err = callPatch(t, ctx, tl, `*** Begin Patch
+*** Update File: work/app.py
+@@
+-print('hi')
++print('hello')
+*** Delete File: work/old.py
+*** Add File: out/new.txt
++new
+*** Add File: work/broken.txt
++broken
+*** End Patch`, false)
+	assert.ErrorContains(t, err, "disk full")
+
+	files, err := ws.Collect(ctx, "work/*", "out/*")
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, f := range files {
+		got[f.Path] = string(f.Data)
+	}
+	assert.Equal(t, map[string]string{
+		"work/app.py": "print('hi')\n",
+		"work/old.py": "pass\n",
+	}, got)
+}
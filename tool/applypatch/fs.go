@@ -0,0 +1,320 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor"
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor/workspaceio"
+	"trpc.group/trpc-go/trpc-agent-go/internal/workspacefacade"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+)
+
+const (
+	// defaultCreateDirMode is the permission mode of created directories.
+	defaultCreateDirMode = os.FileMode(0o755)
+	// defaultCreateFileMode is the permission mode of created files.
+	defaultCreateFileMode = os.FileMode(0o644)
+	// removeTimeout bounds the program that removes workspace files.
+	removeTimeout = 30 * time.Second
+)
+
+// fileSystem is where a patch is applied.
+type fileSystem interface {
+	// clean validates a patch path and returns the canonical form used
+	// to identify the file.
+	clean(path string) (string, error)
+	// read returns the content of a file and whether it exists.
+	read(ctx context.Context, path string) ([]byte, bool, error)
+	// commit writes and removes files, undoing the changes already made
+	// when one of them fails.
+	commit(ctx context.Context, changes []change) error
+}
+
+// change is the new state of a file.
+type change struct {
+	path string
+	// data is the new content, unless remove is set.
+	data   []byte
+	remove bool
+	// old and existed hold the previous state, used to undo the change.
+	old     []byte
+	existed bool
+}
+
+// hostFS applies patches to a directory of the host filesystem.
+type hostFS struct {
+	root        string
+	maxFileSize int64
+}
+
+func (h *hostFS) clean(path string) (string, error) {
+	p := strings.TrimSpace(path)
+	if p == "" {
+		return "", errors.New("path is required")
+	}
+	rel := filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(rel) || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("path %q must be relative to the base directory and stay inside it", path)
+	}
+	// Symlinks must not lead out of the base directory.
+	root, err := filepath.EvalSymlinks(h.root)
+	if err != nil {
+		return "", fmt.Errorf("resolve base directory: %w", err)
+	}
+	resolved := filepath.Join(h.root, rel)
+	for dir, suffix := resolved, ""; ; {
+		if r, err := filepath.EvalSymlinks(dir); err == nil {
+			resolved = filepath.Join(r, suffix)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		suffix = filepath.Join(filepath.Base(dir), suffix)
+		dir = parent
+	}
+	if r, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(r) {
+		return "", fmt.Errorf("path %q resolves outside the base directory", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (h *hostFS) abs(path string) string {
+	return filepath.Join(h.root, filepath.FromSlash(path))
+}
+
+func (h *hostFS) read(_ context.Context, path string) ([]byte, bool, error) {
+	st, err := os.Stat(h.abs(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if st.IsDir() {
+		return nil, false, fmt.Errorf("%s is a directory", path)
+	}
+	if h.maxFileSize > 0 && st.Size() > h.maxFileSize {
+		return nil, false, fmt.Errorf("%s is larger than %d bytes", path, h.maxFileSize)
+	}
+	data, err := os.ReadFile(h.abs(path))
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// commit writes new contents to temporary files next to their targets
+// first, so that a failure to write leaves every file untouched, and
+// then renames them into place.
+func (h *hostFS) commit(_ context.Context, changes []change) error {
+	temps := make([]string, len(changes))
+	modes := make([]os.FileMode, len(changes))
+	cleanup := func() {
+		for _, tmp := range temps {
+			if tmp != "" {
+				os.Remove(tmp)
+			}
+		}
+	}
+	for i, c := range changes {
+		target := h.abs(c.path)
+		modes[i] = defaultCreateFileMode
+		if st, err := os.Stat(target); err == nil {
+			modes[i] = st.Mode().Perm()
+		}
+		if c.remove {
+			continue
+		}
+		tmp, err := writeTemp(target, c.data, modes[i])
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("write %s: %w", c.path, err)
+		}
+		temps[i] = tmp
+	}
+	for i, c := range changes {
+		var err error
+		if c.remove {
+			err = os.Remove(h.abs(c.path))
+		} else {
+			err = os.Rename(temps[i], h.abs(c.path))
+			temps[i] = ""
+		}
+		if err != nil {
+			cleanup()
+			h.undo(changes[:i], modes)
+			return fmt.Errorf("update %s: %w", c.path, err)
+		}
+	}
+	return nil
+}
+
+func writeTemp(target string, data []byte, mode os.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(target), defaultCreateDirMode); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".patch-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), mode)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// undo restores the previous state of files changed by commit.
+func (h *hostFS) undo(done []change, modes []os.FileMode) {
+	for i := len(done) - 1; i >= 0; i-- {
+		c := done[i]
+		var err error
+		if c.existed {
+			err = os.WriteFile(h.abs(c.path), c.old, modes[i])
+		} else {
+			err = os.Remove(h.abs(c.path))
+		}
+		if err != nil {
+			log.Warnf("apply_patch: restore %s: %v", c.path, err)
+		}
+	}
+}
+
+// workspaceFS applies patches to the workspace of the current
+// invocation through workspaceio.
+type workspaceFS struct {
+	maxFileSize int64
+}
+
+func (w *workspaceFS) workspace(ctx context.Context) (*workspaceio.Workspace, error) {
+	ws, ok := workspaceio.WorkspaceFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no workspace is bound to the invocation; " +
+			"configure the agent with a code executor that supports workspaces")
+	}
+	return ws, nil
+}
+
+// clean accepts paths under the writable workspace roots such as work/
+// and out/.
+func (w *workspaceFS) clean(path string) (string, error) {
+	return workspacefacade.NormalizeArtifactPath(path)
+}
+
+func (w *workspaceFS) read(ctx context.Context, path string) ([]byte, bool, error) {
+	ws, err := w.workspace(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	files, err := ws.Collect(ctx, path)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, f := range files {
+		if f.Path != path {
+			continue
+		}
+		if f.Truncated || (w.maxFileSize > 0 && int64(len(f.Data)) > w.maxFileSize) {
+			return nil, false, fmt.Errorf("%s is too large to patch", path)
+		}
+		return f.Data, true, nil
+	}
+	return nil, false, nil
+}
+
+// commit writes files first and removes files last, so that a failed
+// write leaves every file in place. On failure it restores the files
+// from their previous contents and removes the files it created.
+func (w *workspaceFS) commit(ctx context.Context, changes []change) error {
+	ws, err := w.workspace(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		removed []string
+		puts    []codeexecutor.PutFile
+	)
+	for _, c := range changes {
+		if c.remove {
+			removed = append(removed, c.path)
+		} else {
+			puts = append(puts, codeexecutor.PutFile{Path: c.path, Content: c.data})
+		}
+	}
+	if err := ws.PutFiles(ctx, puts...); err != nil {
+		// Part of the batch may have been written.
+		w.undo(ctx, ws, changes)
+		return fmt.Errorf("write workspace files: %w", err)
+	}
+	if err := removeFiles(ctx, ws, removed); err != nil {
+		// rm may have removed some of the files.
+		w.undo(ctx, ws, changes)
+		return fmt.Errorf("remove workspace files: %w", err)
+	}
+	return nil
+}
+
+// undo restores the previous state of files changed by commit.
+func (w *workspaceFS) undo(ctx context.Context, ws *workspaceio.Workspace, changes []change) {
+	var (
+		restore []codeexecutor.PutFile
+		created []string
+	)
+	for _, c := range changes {
+		if c.existed {
+			restore = append(restore, codeexecutor.PutFile{Path: c.path, Content: c.old})
+		} else if !c.remove {
+			created = append(created, c.path)
+		}
+	}
+	if err := ws.PutFiles(ctx, restore...); err != nil {
+		log.Warnf("apply_patch: restore workspace files: %v", err)
+	}
+	if err := removeFiles(ctx, ws, created); err != nil {
+		log.Warnf("apply_patch: remove created workspace files: %v", err)
+	}
+}
+
+// removeFiles removes workspace files with rm, because the workspace
+// has no call to remove files.
+func removeFiles(ctx context.Context, ws *workspaceio.Workspace, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := append([]string{"-f", "--"}, paths...)
+	res, err := ws.RunProgram(ctx, codeexecutor.RunProgramSpec{
+		Cmd:     "rm",
+		Args:    args,
+		Timeout: removeTimeout,
+	})
+	if err == nil && res.ExitCode != 0 {
+		err = fmt.Errorf("rm exited with code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return err
+}
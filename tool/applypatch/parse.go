@@ -0,0 +1,591 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operation is the change a file patch makes.
+type operation string
+
+const (
+	opAdd    operation = "add"
+	opUpdate operation = "update"
+	opDelete operation = "delete"
+)
+
+const (
+	beginPatchMarker = "*** Begin Patch"
+	endPatchMarker   = "*** End Patch"
+	addFileMarker    = "*** Add File: "
+	deleteFileMarker = "*** Delete File: "
+	updateFileMarker = "*** Update File: "
+	moveToMarker     = "*** Move to: "
+	endOfFileMarker  = "*** End of File"
+	noNewlineMarker  = `\ No newline at end of file`
+	devNull          = "/dev/null"
+)
+
+// filePatch is the change to a single file.
+type filePatch struct {
+	op   operation
+	path string
+	// moveTo is the new path of an updated file that is also moved.
+	moveTo string
+	// lines is the content of an added file.
+	lines []string
+	// noEOL reports that an added file has no trailing newline.
+	noEOL bool
+	hunks []*hunk
+}
+
+// hunk is a contiguous change within a file.
+type hunk struct {
+	// header is the "@@" line, used in reports.
+	header string
+	// anchor is a line that precedes the hunk, such as a function
+	// signature after "@@" in the simple format.
+	anchor string
+	// oldStart is the 1-based line the hunk starts at in the original
+	// file when hasRange is set by a unified diff header.
+	oldStart int
+	hasRange bool
+	lines    []hunkLine
+	// atEOF reports that the hunk must match the end of the file.
+	atEOF bool
+	// oldNoEOL and newNoEOL report that the last line of the old or new
+	// side has no trailing newline.
+	oldNoEOL bool
+	newNoEOL bool
+}
+
+// hunkLine is a line of a hunk: ' ' for context, '-' for a removed line
+// and '+' for an added line.
+type hunkLine struct {
+	kind byte
+	text string
+}
+
+// oldLines returns the lines the hunk expects in the file.
+func (h *hunk) oldLines() []string {
+	return oldOf(h.lines)
+}
+
+// parsePatch parses a patch in either the unified diff format or the
+// "*** Begin Patch" format.
+func parsePatch(text string) ([]*filePatch, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(stripFence(text), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) {
+		return nil, fmt.Errorf("patch is empty")
+	}
+	p := &parser{lines: lines, pos: start}
+	var (
+		patches []*filePatch
+		err     error
+	)
+	if strings.TrimSpace(lines[start]) == beginPatchMarker {
+		patches, err = p.parseSimple()
+	} else {
+		patches, err = p.parseUnified()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("patch contains no file changes")
+	}
+	return patches, nil
+}
+
+// stripFence removes a Markdown code fence around the patch, which
+// models sometimes add.
+func stripFence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") {
+		return text
+	}
+	first := strings.IndexByte(trimmed, '\n')
+	last := strings.LastIndex(trimmed, "\n")
+	if first < 0 || last <= first {
+		return text
+	}
+	return trimmed[first+1 : last]
+}
+
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.lines)
+}
+
+func (p *parser) line() string {
+	return p.lines[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("patch line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseSimple parses the "*** Begin Patch" format:
+//
+//	*** Begin Patch
+//	*** Add File: path
+//	+line
+//	*** Update File: path
+//	*** Move to: new/path
+//	@@ optional anchor line
+//	 context
+//	-removed
+//	+added
+//	*** Delete File: path
+//	*** End Patch
+func (p *parser) parseSimple() ([]*filePatch, error) {
+	p.pos++
+	var patches []*filePatch
+	for !p.done() {
+		line := strings.TrimRight(p.line(), " \t")
+		switch {
+		case line == endPatchMarker:
+			p.pos++
+			for ; !p.done(); p.pos++ {
+				if strings.TrimSpace(p.line()) != "" {
+					return nil, p.errorf("unexpected content after %q", endPatchMarker)
+				}
+			}
+			return patches, nil
+		case strings.HasPrefix(line, addFileMarker):
+			fp := &filePatch{op: opAdd, path: strings.TrimSpace(line[len(addFileMarker):])}
+			p.pos++
+			for !p.done() && !strings.HasPrefix(p.line(), "*** ") {
+				l := p.line()
+				if !strings.HasPrefix(l, "+") {
+					return nil, p.errorf("lines of an added file must start with '+', got %q", l)
+				}
+				fp.lines = append(fp.lines, l[1:])
+				p.pos++
+			}
+			patches = append(patches, fp)
+		case strings.HasPrefix(line, deleteFileMarker):
+			patches = append(patches, &filePatch{
+				op:   opDelete,
+				path: strings.TrimSpace(line[len(deleteFileMarker):]),
+			})
+			p.pos++
+		case strings.HasPrefix(line, updateFileMarker):
+			fp := &filePatch{op: opUpdate, path: strings.TrimSpace(line[len(updateFileMarker):])}
+			p.pos++
+			if !p.done() && strings.HasPrefix(p.line(), moveToMarker) {
+				fp.moveTo = strings.TrimSpace(p.line()[len(moveToMarker):])
+				p.pos++
+			}
+			if err := p.parseSimpleHunks(fp); err != nil {
+				return nil, err
+			}
+			patches = append(patches, fp)
+		default:
+			return nil, p.errorf("expected a file operation such as %q, got %q", updateFileMarker+"<path>", line)
+		}
+	}
+	return nil, fmt.Errorf("patch is missing the %q line", endPatchMarker)
+}
+
+func (p *parser) parseSimpleHunks(fp *filePatch) error {
+	var h *hunk
+	for !p.done() {
+		line := p.line()
+		if strings.TrimRight(line, " \t") == endOfFileMarker {
+			if h == nil {
+				return p.errorf("%q must follow a hunk", endOfFileMarker)
+			}
+			h.atEOF = true
+			p.pos++
+			continue
+		}
+		if strings.HasPrefix(line, "*** ") {
+			break
+		}
+		if strings.HasPrefix(line, "@@") {
+			h = &hunk{header: line, anchor: strings.TrimSpace(strings.TrimPrefix(line, "@@"))}
+			fp.hunks = append(fp.hunks, h)
+			p.pos++
+			continue
+		}
+		if h == nil {
+			// The first hunk may omit its "@@" line.
+			h = &hunk{header: "@@"}
+			fp.hunks = append(fp.hunks, h)
+		}
+		if line == "" {
+			// Editors and models often strip the space of an empty
+			// context line.
+			h.lines = append(h.lines, hunkLine{kind: ' '})
+		} else {
+			switch line[0] {
+			case ' ', '-', '+':
+				h.lines = append(h.lines, hunkLine{kind: line[0], text: line[1:]})
+			default:
+				return p.errorf("hunk lines must start with ' ', '-' or '+', got %q", line)
+			}
+		}
+		p.pos++
+	}
+	if len(fp.hunks) == 0 && fp.moveTo == "" {
+		return p.errorf("update of %s has no hunks", fp.path)
+	}
+	for _, h := range fp.hunks {
+		if !h.changes() {
+			return fmt.Errorf("hunk %q of %s changes nothing", h.header, fp.path)
+		}
+	}
+	return nil
+}
+
+// changes reports whether the hunk adds or removes any line.
+func (h *hunk) changes() bool {
+	for _, l := range h.lines {
+		if l.kind != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUnified parses unified diffs as produced by diff -u and git diff,
+// including git's rename and new or deleted file headers.
+func (p *parser) parseUnified() ([]*filePatch, error) {
+	var patches []*filePatch
+	var git gitHeader
+	for !p.done() {
+		line := p.line()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if git.pending() {
+				fp, err := git.patch()
+				if err != nil {
+					return nil, p.errorf("%v", err)
+				}
+				patches = append(patches, fp)
+			}
+			git = gitHeader{seen: true}
+			git.oldPath, git.newPath = splitGitPaths(line[len("diff --git "):])
+			p.pos++
+		case strings.HasPrefix(line, "rename from "):
+			git.renameFrom = strings.TrimSpace(line[len("rename from "):])
+			p.pos++
+		case strings.HasPrefix(line, "rename to "):
+			git.renameTo = strings.TrimSpace(line[len("rename to "):])
+			p.pos++
+		case strings.HasPrefix(line, "new file mode"):
+			git.newFile = true
+			p.pos++
+		case strings.HasPrefix(line, "deleted file mode"):
+			git.deleted = true
+			p.pos++
+		case strings.HasPrefix(line, "--- ") && p.pos+1 < len(p.lines) &&
+			strings.HasPrefix(p.lines[p.pos+1], "+++ "):
+			fp, err := p.parseUnifiedFile(git.seen)
+			if err != nil {
+				return nil, err
+			}
+			git = gitHeader{}
+			patches = append(patches, fp)
+		case strings.HasPrefix(line, "@@"):
+			return nil, p.errorf("hunk without a preceding '---' and '+++' file header")
+		case strings.HasPrefix(line, "Binary files ") || strings.HasPrefix(line, "GIT binary patch"):
+			return nil, p.errorf("binary patches are not supported")
+		default:
+			// Other header lines such as "index ..." are ignored.
+			p.pos++
+		}
+	}
+	if git.pending() {
+		fp, err := git.patch()
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, fp)
+	}
+	return patches, nil
+}
+
+// gitHeader holds the extended header of a git diff entry, which is
+// the whole entry for renames, mode changes and empty files.
+type gitHeader struct {
+	seen       bool
+	oldPath    string
+	newPath    string
+	renameFrom string
+	renameTo   string
+	newFile    bool
+	deleted    bool
+}
+
+// pending reports whether the header describes a change without hunks.
+func (g gitHeader) pending() bool {
+	return g.seen && (g.renameFrom != "" || g.newFile || g.deleted)
+}
+
+func (g gitHeader) patch() (*filePatch, error) {
+	switch {
+	case g.newFile:
+		return &filePatch{op: opAdd, path: g.newPath}, nil
+	case g.deleted:
+		return &filePatch{op: opDelete, path: g.oldPath}, nil
+	case g.renameFrom != "" && g.renameTo != "":
+		return &filePatch{op: opUpdate, path: g.renameFrom, moveTo: g.renameTo}, nil
+	default:
+		return nil, fmt.Errorf("incomplete rename of %s", g.oldPath)
+	}
+}
+
+// splitGitPaths splits the "a/x b/y" part of a "diff --git" line.
+func splitGitPaths(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " b/"); strings.HasPrefix(s, "a/") && i > 0 {
+		return s[2:i], s[i+3:]
+	}
+	if fields := strings.Fields(s); len(fields) == 2 {
+		return fields[0], fields[1]
+	}
+	return s, s
+}
+
+func (p *parser) parseUnifiedFile(git bool) (*filePatch, error) {
+	oldPath := unifiedPath(p.line()[len("--- "):])
+	newPath := unifiedPath(p.lines[p.pos+1][len("+++ "):])
+	if oldPath == devNull && newPath == devNull {
+		return nil, p.errorf("both sides of the file header are %s", devNull)
+	}
+	// git prefixes paths with a/ and b/; for other diffs the prefixes
+	// are stripped only when every side that names a file has them.
+	if git || ((oldPath == devNull || strings.HasPrefix(oldPath, "a/")) &&
+		(newPath == devNull || strings.HasPrefix(newPath, "b/"))) {
+		oldPath, newPath = trimGitPrefix(oldPath, "a/"), trimGitPrefix(newPath, "b/")
+	}
+	p.pos += 2
+
+	fp := &filePatch{op: opUpdate, path: oldPath}
+	switch {
+	case oldPath == devNull:
+		fp.op, fp.path = opAdd, newPath
+	case newPath == devNull:
+		fp.op = opDelete
+	case newPath != oldPath:
+		fp.moveTo = newPath
+	}
+	for !p.done() && strings.HasPrefix(p.line(), "@@") {
+		h, err := p.parseUnifiedHunk()
+		if err != nil {
+			return nil, err
+		}
+		fp.hunks = append(fp.hunks, h)
+	}
+	switch fp.op {
+	case opAdd:
+		for _, h := range fp.hunks {
+			for _, l := range h.lines {
+				if l.kind != '+' {
+					return nil, fmt.Errorf("hunk %q of new file %s has lines that are not additions",
+						h.header, fp.path)
+				}
+				fp.lines = append(fp.lines, l.text)
+			}
+			fp.noEOL = h.newNoEOL
+		}
+		fp.hunks = nil
+	case opDelete:
+		fp.hunks = nil
+	default:
+		if len(fp.hunks) == 0 && fp.moveTo == "" {
+			return nil, p.errorf("update of %s has no hunks", fp.path)
+		}
+	}
+	return fp, nil
+}
+
+// unifiedPath returns the path of a "---" or "+++" line, without the
+// timestamp that diff -u appends after a tab.
+func unifiedPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	return s
+}
+
+func trimGitPrefix(path, prefix string) string {
+	if path == devNull {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// parseUnifiedHunk parses a hunk starting at a "@@ -a,b +c,d @@" line.
+// Line counts in the header are used to end the hunk when they are
+// consistent; models often get them wrong, so the hunk otherwise ends
+// at the next line that cannot belong to it.
+func (p *parser) parseUnifiedHunk() (*hunk, error) {
+	header := p.line()
+	h := &hunk{header: header}
+	oldCount, newCount, ok := parseRange(header, &h.oldStart)
+	h.hasRange = ok
+	p.pos++
+	for !p.done() {
+		line := p.line()
+		if line == noNewlineMarker {
+			if len(h.lines) == 0 {
+				return nil, p.errorf("%q must follow a hunk line", noNewlineMarker)
+			}
+			switch h.lines[len(h.lines)-1].kind {
+			case '-':
+				h.oldNoEOL = true
+			case '+':
+				h.newNoEOL = true
+			default:
+				h.oldNoEOL, h.newNoEOL = true, true
+			}
+			p.pos++
+			continue
+		}
+		if p.hunkBoundary() {
+			break
+		}
+		if line == "" {
+			h.lines = append(h.lines, hunkLine{kind: ' '})
+		} else {
+			switch line[0] {
+			case ' ', '-', '+':
+				h.lines = append(h.lines, hunkLine{kind: line[0], text: line[1:]})
+			default:
+				return nil, p.errorf("hunk lines must start with ' ', '-' or '+', got %q", line)
+			}
+		}
+		p.pos++
+		if ok && h.complete(oldCount, newCount) && !p.done() && p.line() != noNewlineMarker &&
+			!p.continuesHunk() {
+			break
+		}
+	}
+	if ok {
+		h.trimTrailingBlank(oldCount, newCount)
+	}
+	if len(h.lines) == 0 {
+		return nil, fmt.Errorf("hunk %q is empty", header)
+	}
+	return h, nil
+}
+
+// hunkBoundary reports whether the current line starts the next hunk or
+// file.
+func (p *parser) hunkBoundary() bool {
+	line := p.line()
+	switch {
+	case strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "diff --git "):
+		return true
+	case strings.HasPrefix(line, "--- "):
+		return p.pos+1 < len(p.lines) && strings.HasPrefix(p.lines[p.pos+1], "+++ ")
+	}
+	return false
+}
+
+// continuesHunk reports whether a line after a hunk whose counts are
+// satisfied still looks like part of it, in which case the counts are
+// taken to be wrong.
+func (p *parser) continuesHunk() bool {
+	line := p.line()
+	if line == "" || p.hunkBoundary() {
+		return false
+	}
+	return line[0] == ' ' || line[0] == '-' || line[0] == '+'
+}
+
+// complete reports whether the hunk has the old and new line counts
+// from its header.
+func (h *hunk) complete(oldCount, newCount int) bool {
+	o, n := h.counts()
+	return o >= oldCount && n >= newCount
+}
+
+func (h *hunk) counts() (int, int) {
+	o, n := 0, 0
+	for _, l := range h.lines {
+		if l.kind != '+' {
+			o++
+		}
+		if l.kind != '-' {
+			n++
+		}
+	}
+	return o, n
+}
+
+// trimTrailingBlank drops empty context lines past the header counts,
+// which are usually blank lines that separate diffs.
+func (h *hunk) trimTrailingBlank(oldCount, newCount int) {
+	for len(h.lines) > 0 {
+		last := h.lines[len(h.lines)-1]
+		o, n := h.counts()
+		if last.kind != ' ' || last.text != "" || o <= oldCount || n <= newCount {
+			return
+		}
+		h.lines = h.lines[:len(h.lines)-1]
+	}
+}
+
+// parseRange parses the "@@ -a,b +c,d @@" header, storing a in start.
+func parseRange(header string, start *int) (oldCount, newCount int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || fields[0] != "@@" || !strings.HasPrefix(fields[1], "-") ||
+		!strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	oldStart, oldCount, ok1 := parseSpan(fields[1][1:])
+	_, newCount, ok2 := parseSpan(fields[2][1:])
+	if !ok1 || !ok2 {
+		return 0, 0, false
+	}
+	*start = oldStart
+	return oldCount, newCount, true
+}
+
+func parseSpan(s string) (start, count int, ok bool) {
+	startText, countText, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil || count < 0 {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
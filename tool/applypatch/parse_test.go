@@ -0,0 +1,121 @@
+//
+// Tencent is pleased to support the open source community by making
+// trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package applypatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePatch_Simple(t *testing.T) {
+	patches, err := parsePatch("```\n*** Begin Patch\n" +
+		"*** Add File: docs/new.md\n+# Title\n+\n" +
+		"*** Update File: main.go\n*** Move to: cmd/main.go\n" +
+		"@@ func main() {\n-\tprintln(1)\n+\tprintln(2)\n\n" +
+		"@@\n context\n+added\n*** End of File\n" +
+		"*** Delete File: old.go\n" +
+		"*** End Patch\n```")
+	require.NoError(t, err)
+	require.Len(t, patches, 3)
+
+	assert.Equal(t, opAdd, patches[0].op)
+	assert.Equal(t, []string{"# Title", ""}, patches[0].lines)
+
+	update := patches[1]
+	assert.Equal(t, "main.go", update.path)
+	assert.Equal(t, "cmd/main.go", update.moveTo)
+	require.Len(t, update.hunks, 2)
+	assert.Equal(t, "func main() {", update.hunks[0].anchor)
+	assert.Equal(t, []hunkLine{{'-', "\tprintln(1)"}, {'+', "\tprintln(2)"}, {' ', ""}},
+		update.hunks[0].lines)
+	assert.True(t, update.hunks[1].atEOF)
+
+	assert.Equal(t, &filePatch{op: opDelete, path: "old.go"}, patches[2])
+}
+
+func TestParsePatch_Unified(t *testing.T) {
+	patches, err := parsePatch(`diff --git a/a.txt b/a.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@ header
+ one
+-two
++TWO
+\ No newline at end of file
+@@ -10,2 +10,3 @@
+ ten
++ten and a half
+ eleven
+diff --git a/old.txt b/new.txt
+similarity index 100%
+rename from old.txt
+rename to new.txt
+diff --git a/created.txt b/created.txt
+new file mode 100644
+--- /dev/null
++++ b/created.txt
+@@ -0,0 +1,2 @@
++hello
++world
+--- gone.txt	2024-01-01 00:00:00
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`)
+	require.NoError(t, err)
+	require.Len(t, patches, 4)
+
+	a := patches[0]
+	assert.Equal(t, "a.txt", a.path)
+	assert.Equal(t, "", a.moveTo)
+	require.Len(t, a.hunks, 2)
+	assert.Equal(t, 1, a.hunks[0].oldStart)
+	assert.False(t, a.hunks[0].oldNoEOL)
+	assert.True(t, a.hunks[0].newNoEOL)
+	assert.Equal(t, 10, a.hunks[1].oldStart)
+	assert.Len(t, a.hunks[1].lines, 3)
+
+	assert.Equal(t, &filePatch{op: opUpdate, path: "old.txt", moveTo: "new.txt"}, patches[1])
+	assert.Equal(t, &filePatch{op: opAdd, path: "created.txt", lines: []string{"hello", "world"}}, patches[2])
+	assert.Equal(t, &filePatch{op: opDelete, path: "gone.txt"}, patches[3])
+}
+
+func TestParsePatch_WrongCounts(t *testing.T) {
+	// The header undercounts the hunk, and blank separator lines follow
+	// it; neither should confuse the parser.
+	patches, err := parsePatch("--- a/f\n+++ b/f\n@@ -1,1 +1,1 @@\n a\n-b\n+c\n d\n\n\n--- a/g\n+++ b/g\n@@ -1 +1 @@\n-x\n+y\n")
+	require.NoError(t, err)
+	require.Len(t, patches, 2)
+	assert.Equal(t, []hunkLine{{' ', "a"}, {'-', "b"}, {'+', "c"}, {' ', "d"}}, patches[0].hunks[0].lines)
+	assert.Equal(t, []hunkLine{{'-', "x"}, {'+', "y"}}, patches[1].hunks[0].lines)
+}
+
+func TestParsePatch_Errors(t *testing.T) {
+	for text, want := range map[string]string{
+		"":                                      "patch is empty",
+		"just some text":                        "no file changes",
+		"*** Begin Patch\n*** Update File: a\n": "no hunks",
+		"*** Begin Patch\n*** Add File: a\nx\n": "must start with '+'",
+		"*** Begin Patch\n*** Frobnicate: a\n":  "expected a file operation",
+		"*** Begin Patch\n*** Delete File: a\n": "End Patch",
+		"*** Begin Patch\n*** Update File: a\n a\n*** End Patch": "changes nothing",
+		"@@ -1 +1 @@\n-a\n+b\n":                                  "without a preceding",
+		"--- a/f\n+++ b/f\n@@ -1 +1 @@\n-a\n*b\n":                "must start with ' ', '-' or '+'",
+		"--- a/f\n+++ b/f\nBinary files differ\n":                "update of f has no hunks",
+		"diff --git a/f b/f\nGIT binary patch\n":                 "binary patches",
+	} {
+		_, err := parsePatch(text)
+		assert.ErrorContains(t, err, want, text)
+	}
+}
@@ -0,0 +1,97 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/artifact"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	patchMimeType = "text/x-diff"
+	textMimeType  = "text/plain"
+)
+
+type artifactRef struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	MimeType string `json:"mime_type"`
+}
+
+// inline returns text when it fits the inline limit. Otherwise it saves
+// text as an artifact named after prefix and the tool call, and returns a
+// preview that ends at a line break, the artifact and a note for the
+// model.
+func (ts *toolSet) inline(
+	ctx context.Context,
+	prefix, ext, mimeType, text string,
+) (string, bool, *artifactRef, string) {
+	limit := ts.config.maxDiffBytes
+	if len(text) <= limit {
+		return text, false, nil, ""
+	}
+	preview := text[:limit]
+	if i := strings.LastIndexByte(preview, '\n'); i > 0 {
+		preview = preview[:i+1]
+	}
+	for !utf8.ValidString(preview) {
+		preview = preview[:len(preview)-1]
+	}
+	ref, err := saveText(ctx, prefix, ext, mimeType, text)
+	if err != nil {
+		log.Warnf("git tool set: save output as artifact: %v", err)
+		return preview, true, nil, fmt.Sprintf(
+			"Only the first %d of %d bytes are shown; the full output could not be saved: %v. "+
+				"Narrow the request with paths to see the rest.", len(preview), len(text), err)
+	}
+	return preview, true, ref, fmt.Sprintf(
+		"Only the first %d of %d bytes are shown; the full output is saved as the artifact %s.",
+		len(preview), len(text), ref.Name)
+}
+
+// saveText saves text through the artifact service of the invocation.
+func saveText(ctx context.Context, prefix, ext, mimeType, text string) (*artifactRef, error) {
+	cc, err := agent.NewCallbackContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := tool.ToolCallIDFromContext(ctx)
+	if !ok || id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	name := prefix + "_" + id + ext
+	version, err := cc.SaveArtifact(name, &artifact.Artifact{
+		Data:     []byte(text),
+		MimeType: mimeType,
+		Name:     name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &artifactRef{Name: name, Version: version, MimeType: mimeType}, nil
+}
+
+// unixDate formats a Unix time in seconds as RFC 3339 in UTC.
+func unixDate(s string) string {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+}
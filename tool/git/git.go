@@ -0,0 +1,403 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package git provides a toolset over a local Git repository.
+//
+// The toolset offers status, diff, log, show, blame, branch, stage and
+// commit tools that return structured results instead of raw command
+// output. With WithWorktreeRoot it also offers tools that create, list
+// and remove worktrees managed by internal/gitworktree, and every tool
+// can then run in one of them.
+//
+// Git runs without a terminal and with network transports disabled, so
+// only the push tool, which is offered when the policy allows pushes, can
+// reach a remote. The policy also decides whether forced operations such
+// as amending commits or deleting unmerged branches are allowed, and which
+// branches cannot be committed or pushed to. Diffs larger than the inline
+// limit are saved as artifacts with only a preview returned to the model.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/internal/gitworktree"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+const (
+	// defaultToolSetName is the default name for the git tool set.
+	defaultToolSetName = "git"
+	// defaultRepoDir is the default repository directory.
+	defaultRepoDir = "."
+	// defaultMaxDiffBytes is the default size of diffs returned inline.
+	defaultMaxDiffBytes = 32 * 1024
+	// defaultCommandTimeout is the default timeout of a git command.
+	defaultCommandTimeout = 30 * time.Second
+	// defaultPushTimeout is the default timeout of a push.
+	defaultPushTimeout = 2 * time.Minute
+	// worktreeBranchPrefix prefixes the branches of worktrees created by
+	// the tools.
+	worktreeBranchPrefix = "agent-worktree-"
+)
+
+// Policy restricts what the tools may change. The zero value forbids
+// pushes and forced operations and protects no branch.
+type Policy struct {
+	// AllowPush offers the push tool.
+	AllowPush bool
+	// AllowForce allows operations that may discard commits or changes:
+	// amending commits, force-pushing, resetting or deleting unmerged
+	// branches, switching branches over local changes and removing
+	// worktrees that have changes.
+	AllowForce bool
+	// ProtectedBranches lists branches that cannot be committed to,
+	// pushed to, reset or deleted. Entries are path.Match patterns such
+	// as "main" or "release/*".
+	ProtectedBranches []string
+	// Remotes lists the remotes push may use. When empty, push may use
+	// any remote configured in the repository.
+	Remotes []string
+}
+
+// protected reports whether branch matches a protected pattern.
+func (p Policy) protected(branch string) bool {
+	for _, pattern := range p.ProtectedBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Option is a functional option for configuring the git tool set.
+type Option func(*config)
+
+// config holds the configuration for the git tool set.
+type config struct {
+	name           string
+	repoDir        string
+	authorName     string
+	authorEmail    string
+	policy         Policy
+	maxDiffBytes   int
+	worktreeRoot   string
+	commandTimeout time.Duration
+}
+
+// WithName sets the name of the tool set.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithRepoDir sets a directory inside the repository the tools work on,
+// default is the current directory.
+func WithRepoDir(dir string) Option {
+	return func(c *config) {
+		c.repoDir = dir
+	}
+}
+
+// WithAuthor sets the author and committer of commits. Without it the
+// repository's user.name and user.email are used.
+func WithAuthor(name, email string) Option {
+	return func(c *config) {
+		c.authorName = name
+		c.authorEmail = email
+	}
+}
+
+// WithPolicy sets the policy the tools respect.
+func WithPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// WithMaxDiffBytes sets the size of diffs and file contents returned in
+// the tool result, past which they are saved as an artifact when an
+// artifact service is available. The default is 32 KiB.
+func WithMaxDiffBytes(n int) Option {
+	return func(c *config) {
+		c.maxDiffBytes = n
+	}
+}
+
+// WithWorktreeRoot enables the worktree tools, which create worktrees in
+// directories under root on branches named after the worktree id with
+// the prefix "agent-worktree-".
+func WithWorktreeRoot(root string) Option {
+	return func(c *config) {
+		c.worktreeRoot = root
+	}
+}
+
+// WithCommandTimeout sets the timeout of each git command except push,
+// default is 30 seconds.
+func WithCommandTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.commandTimeout = timeout
+	}
+}
+
+// toolSet is a set of git tools.
+type toolSet struct {
+	config   *config
+	repoDir  string
+	worktree *gitworktree.Manager
+	tools    []tool.Tool
+
+	mu     sync.Mutex
+	leases map[string]gitworktree.Lease
+}
+
+// NewToolSet creates a git tool set with the provided options.
+func NewToolSet(opts ...Option) (tool.ToolSet, error) {
+	c := &config{
+		name:           defaultToolSetName,
+		repoDir:        defaultRepoDir,
+		maxDiffBytes:   defaultMaxDiffBytes,
+		commandTimeout: defaultCommandTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git tool set: git is not installed: %w", err)
+	}
+	if c.maxDiffBytes < 1 {
+		return nil, errors.New("git tool set: max diff bytes must be positive")
+	}
+	if (c.authorName == "") != (c.authorEmail == "") {
+		return nil, errors.New("git tool set: author needs both a name and an email")
+	}
+	for _, pattern := range c.policy.ProtectedBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("git tool set: protected branch pattern %q: %w", pattern, err)
+		}
+	}
+	dir, err := filepath.Abs(c.repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("git tool set: %w", err)
+	}
+	ts := &toolSet{config: c, repoDir: dir, leases: make(map[string]gitworktree.Lease)}
+	if _, err := ts.git(context.Background(), dir, "rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("git tool set: %s is not in a git repository: %w", dir, err)
+	}
+	if c.worktreeRoot != "" {
+		ts.worktree = gitworktree.NewManager(c.worktreeRoot,
+			gitworktree.WithBranchPrefix(worktreeBranchPrefix),
+			gitworktree.WithCommandTimeout(c.commandTimeout),
+		)
+	}
+	ts.tools = ts.newTools()
+	return ts, nil
+}
+
+func (ts *toolSet) newTools() []tool.Tool {
+	tools := []tool.Tool{
+		function.NewFunctionTool(ts.status,
+			function.WithName("status"),
+			function.WithDescription("Shows the current branch, its upstream and the staged, unstaged, "+
+				"untracked and conflicted files."),
+		),
+		function.NewFunctionTool(ts.diff,
+			function.WithName("diff"),
+			function.WithDescription("Shows changes as a patch with per-file line counts: unstaged changes by default, "+
+				"staged changes, or changes against a commit or between two commits (\"a..b\"). "+
+				"Paths limit the diff to files or directories."),
+		),
+		function.NewFunctionTool(ts.log,
+			function.WithName("log"),
+			function.WithDescription("Lists commits reachable from a revision, newest first, "+
+				"optionally only those that touch the given paths."),
+		),
+		function.NewFunctionTool(ts.show,
+			function.WithName("show"),
+			function.WithDescription("Shows a commit with its message and patch, "+
+				"or the content of a file at a revision when a path is given."),
+		),
+		function.NewFunctionTool(ts.blame,
+			function.WithName("blame"),
+			function.WithDescription("Shows the commit, author and date that last changed each line of a file."),
+		),
+		function.NewFunctionTool(ts.branch,
+			function.WithName("branch"),
+			function.WithDescription("Lists, creates, deletes or switches branches."),
+		),
+		function.NewFunctionTool(ts.stage,
+			function.WithName("stage"),
+			function.WithDescription("Stages files for the next commit, or unstages them."),
+		),
+		function.NewFunctionTool(ts.commit,
+			function.WithName("commit"),
+			function.WithDescription("Commits the staged changes, or only the given paths."),
+		),
+	}
+	if ts.config.policy.AllowPush {
+		tools = append(tools, function.NewFunctionTool(ts.push,
+			function.WithName("push"),
+			function.WithDescription("Pushes a branch to a remote."),
+		))
+	}
+	if ts.worktree != nil {
+		tools = append(tools,
+			function.NewFunctionTool(ts.worktreeCreate,
+				function.WithName("worktree_create"),
+				function.WithDescription("Creates a worktree on a new branch from HEAD, so changes can be made "+
+					"without touching the main checkout. Pass its id as worktree to the other tools to use it."),
+			),
+			function.NewFunctionTool(ts.worktreeList,
+				function.WithName("worktree_list"),
+				function.WithDescription("Lists the worktrees created by worktree_create."),
+			),
+			function.NewFunctionTool(ts.worktreeRemove,
+				function.WithName("worktree_remove"),
+				function.WithDescription("Removes a worktree and its branch. A worktree with uncommitted changes "+
+					"or new commits is kept unless force is set."),
+			),
+		)
+	}
+	return tools
+}
+
+// Tools implements the ToolSet interface.
+func (ts *toolSet) Tools(ctx context.Context) []tool.Tool {
+	return ts.tools
+}
+
+// Close implements the ToolSet interface. It removes the worktrees that
+// have no changes and keeps the others.
+func (ts *toolSet) Close() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for id, lease := range ts.leases {
+		if _, err := ts.worktree.Finalize(context.Background(), lease); err != nil {
+			log.Warnf("git tool set: finalize worktree %s: %v", id, err)
+		}
+		delete(ts.leases, id)
+	}
+	return nil
+}
+
+// Name implements the ToolSet interface.
+func (ts *toolSet) Name() string {
+	return ts.config.name
+}
+
+// dir returns the directory a tool runs in: the repository, or the
+// worktree with the given id.
+func (ts *toolSet) dir(worktree string) (string, error) {
+	if worktree == "" {
+		return ts.repoDir, nil
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	lease, ok := ts.leases[worktree]
+	if !ok {
+		return "", fmt.Errorf("unknown worktree %q", worktree)
+	}
+	return lease.Path, nil
+}
+
+// git runs a git command offline and returns its standard output.
+func (ts *toolSet) git(ctx context.Context, dir string, args ...string) (string, error) {
+	return ts.run(ctx, dir, ts.config.commandTimeout, true, args...)
+}
+
+// run runs a git command. When offline is set, every network transport
+// is disabled.
+func (ts *toolSet) run(
+	ctx context.Context,
+	dir string,
+	timeout time.Duration,
+	offline bool,
+	args ...string,
+) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	base := []string{"--no-pager", "-c", "core.quotepath=off", "-c", "color.ui=false"}
+	if offline {
+		base = append(base, "-c", "protocol.allow=never")
+	}
+	cmd := exec.CommandContext(ctx, "git", append(base, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_OPTIONAL_LOCKS=0", "LC_ALL=C")
+	if c := ts.config; c.authorName != "" {
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME="+c.authorName, "GIT_AUTHOR_EMAIL="+c.authorEmail,
+			"GIT_COMMITTER_NAME="+c.authorName, "GIT_COMMITTER_EMAIL="+c.authorEmail,
+		)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("git %s: %w", args[0], ctx.Err())
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// currentBranch returns the checked out branch, or "" on a detached HEAD.
+func (ts *toolSet) currentBranch(ctx context.Context, dir string) (string, error) {
+	out, err := ts.git(ctx, dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		if _, herr := ts.git(ctx, dir, "rev-parse", "--verify", "--quiet", "HEAD"); herr == nil {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// checkArg rejects revisions and names that git would read as options.
+func checkArg(kind, value string) error {
+	if strings.HasPrefix(value, "-") {
+		return fmt.Errorf("%s %q must not start with '-'", kind, value)
+	}
+	if strings.ContainsAny(value, "\x00\n") {
+		return fmt.Errorf("%s %q contains invalid characters", kind, value)
+	}
+	return nil
+}
+
+// checkPaths validates path filters, which are passed after "--".
+func checkPaths(paths []string) error {
+	for _, p := range paths {
+		if p == "" || strings.ContainsRune(p, 0) {
+			return fmt.Errorf("invalid path %q", p)
+		}
+	}
+	return nil
+}
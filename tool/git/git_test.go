@@ -0,0 +1,412 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/artifact"
+	"trpc.group/trpc-go/trpc-agent-go/artifact/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// newRepo creates a repository on branch main with one commit.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	gitRun(t, dir, "config", "user.name", "Repo User")
+	gitRun(t, dir, "config", "user.email", "repo@example.com")
+	writeFile(t, dir, "README.md", "# Demo\n\nFirst line.\n")
+	writeFile(t, dir, "src/app.go", "package app\n")
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "commit", "-q", "-m", "Initial commit")
+	return dir
+}
+
+func callTool(t *testing.T, ctx context.Context, ts tool.ToolSet, name, args string) (any, error) {
+	t.Helper()
+	for _, tl := range ts.Tools(ctx) {
+		if tl.Declaration().Name == name {
+			return tl.(tool.CallableTool).Call(ctx, []byte(args))
+		}
+	}
+	require.Failf(t, "tool not found", name)
+	return nil, nil
+}
+
+func toolNames(ts tool.ToolSet) []string {
+	var names []string
+	for _, tl := range ts.Tools(context.Background()) {
+		names = append(names, tl.Declaration().Name)
+	}
+	return names
+}
+
+func TestNewToolSet(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := NewToolSet(WithRepoDir(dir))
+	require.NoError(t, err)
+	assert.Equal(t, "git", ts.Name())
+	assert.Equal(t, []string{"status", "diff", "log", "show", "blame", "branch", "stage", "commit"}, toolNames(ts))
+
+	ts, err = NewToolSet(WithRepoDir(dir), WithPolicy(Policy{AllowPush: true}), WithWorktreeRoot(t.TempDir()))
+	require.NoError(t, err)
+	assert.Contains(t, toolNames(ts), "push")
+	assert.Contains(t, toolNames(ts), "worktree_create")
+
+	_, err = NewToolSet(WithRepoDir(t.TempDir()))
+	assert.ErrorContains(t, err, "not in a git repository")
+	_, err = NewToolSet(WithRepoDir(dir), WithPolicy(Policy{ProtectedBranches: []string{"release/["}}))
+	assert.ErrorContains(t, err, "protected branch pattern")
+	_, err = NewToolSet(WithRepoDir(dir), WithAuthor("Agent", ""))
+	assert.ErrorContains(t, err, "both a name and an email")
+}
+
+func TestToolSet_StatusDiffCommit(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := NewToolSet(WithRepoDir(dir), WithAuthor("Agent", "agent@example.com"),
+		WithPolicy(Policy{ProtectedBranches: []string{"main", "release/*"}}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	writeFile(t, dir, "README.md", "# Demo\n\nSecond line.\n")
+	writeFile(t, dir, "src/app.go", "package app\n\nfunc Run() {}\n")
+	writeFile(t, dir, "notes/todo.txt", "todo\n")
+
+	out, err := callTool(t, ctx, ts, "status", `{}`)
+	require.NoError(t, err)
+	st := out.(statusOutput)
+	assert.Equal(t, "main", st.Branch)
+	assert.False(t, st.Clean)
+	assert.Equal(t, []fileStatus{
+		{Path: "README.md", Unstaged: "M"},
+		{Path: "src/app.go", Unstaged: "M"},
+		{Path: "notes/todo.txt", Untracked: true},
+	}, st.Files)
+
+	out, err = callTool(t, ctx, ts, "diff", `{"paths":["src"]}`)
+	require.NoError(t, err)
+	d := out.(diffOutput)
+	assert.Equal(t, []diffFile{{Path: "src/app.go", Added: 2}}, d.Files)
+	assert.Contains(t, d.Patch, "+func Run() {}")
+	assert.NotContains(t, d.Patch, "README")
+
+	// Commits to a protected branch are refused.
+	_, err = callTool(t, ctx, ts, "commit", `{"message":"Update","all":true}`)
+	assert.ErrorContains(t, err, `branch "main" is protected`)
+	_, err = callTool(t, ctx, ts, "branch", `{"action":"switch","name":"release/1.0"}`)
+	assert.Error(t, err)
+
+	_, err = callTool(t, ctx, ts, "branch", `{"action":"create","name":"feature/run"}`)
+	require.NoError(t, err)
+	out, err = callTool(t, ctx, ts, "branch", `{"action":"switch","name":"feature/run"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "feature/run", out.(branchOutput).Current)
+
+	out, err = callTool(t, ctx, ts, "stage", `{"paths":["src/app.go","notes"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []fileStatus{
+		{Path: "README.md", Unstaged: "M"},
+		{Path: "notes/todo.txt", Staged: "A"},
+		{Path: "src/app.go", Staged: "M"},
+	}, out.(statusOutput).Files)
+
+	out, err = callTool(t, ctx, ts, "diff", `{"staged":true,"stat_only":true}`)
+	require.NoError(t, err)
+	assert.Len(t, out.(diffOutput).Files, 2)
+	assert.Empty(t, out.(diffOutput).Patch)
+
+	out, err = callTool(t, ctx, ts, "commit", `{"message":"Add Run\n\nAnd a todo list."}`)
+	require.NoError(t, err)
+	c := out.(commitOutput)
+	assert.Equal(t, "feature/run", c.Branch)
+	assert.Equal(t, "Add Run", c.Summary)
+	assert.Equal(t, []diffFile{{Path: "notes/todo.txt", Added: 1}, {Path: "src/app.go", Added: 2}}, c.Files)
+	assert.Equal(t, "Agent <agent@example.com>", gitRun(t, dir, "log", "-1", "--format=%an <%ae>"))
+	assert.Equal(t, "agent@example.com", gitRun(t, dir, "log", "-1", "--format=%ce"))
+
+	// Amending needs a policy that allows forced operations.
+	_, err = callTool(t, ctx, ts, "commit", `{"message":"Amend","amend":true}`)
+	assert.ErrorIs(t, err, errForce)
+
+	out, err = callTool(t, ctx, ts, "stage", `{"all":true}`)
+	require.NoError(t, err)
+	assert.Equal(t, "M", out.(statusOutput).Files[0].Staged)
+	out, err = callTool(t, ctx, ts, "stage", `{"all":true,"unstage":true}`)
+	require.NoError(t, err)
+	assert.Equal(t, []fileStatus{{Path: "README.md", Unstaged: "M"}}, out.(statusOutput).Files)
+}
+
+func TestToolSet_LogShowBlame(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "README.md", "# Demo\n\nSecond line.\nThird line.\n")
+	gitRun(t, dir, "commit", "-q", "-am", "Edit README", "-m", "Explain the demo.")
+	gitRun(t, dir, "mv", "src/app.go", "src/main.go")
+	gitRun(t, dir, "commit", "-q", "-m", "Rename app")
+	ts, err := NewToolSet(WithRepoDir(dir))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	out, err := callTool(t, ctx, ts, "log", `{"max_count":2}`)
+	require.NoError(t, err)
+	l := out.(logOutput)
+	assert.True(t, l.More)
+	require.Len(t, l.Commits, 2)
+	assert.Equal(t, "Rename app", l.Commits[0].Subject)
+	assert.Equal(t, "Repo User", l.Commits[0].Author)
+	assert.Equal(t, []string{l.Commits[1].Hash}, l.Commits[0].Parents)
+	assert.Empty(t, l.Commits[1].Body)
+
+	out, err = callTool(t, ctx, ts, "log", `{"paths":["README.md"],"skip":1}`)
+	require.NoError(t, err)
+	l = out.(logOutput)
+	require.Len(t, l.Commits, 1)
+	assert.Equal(t, "Initial commit", l.Commits[0].Subject)
+	assert.False(t, l.More)
+
+	out, err = callTool(t, ctx, ts, "show", `{"rev":"HEAD~1"}`)
+	require.NoError(t, err)
+	s := out.(showOutput)
+	assert.Equal(t, "Edit README", s.Commit.Subject)
+	assert.Equal(t, "Explain the demo.", s.Commit.Body)
+	assert.Equal(t, []diffFile{{Path: "README.md", Added: 2, Deleted: 1}}, s.Files)
+	assert.Contains(t, s.Patch, "+Third line.")
+
+	out, err = callTool(t, ctx, ts, "show", `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, []diffFile{{Path: "src/main.go", OldPath: "src/app.go"}}, out.(showOutput).Files)
+
+	out, err = callTool(t, ctx, ts, "show", `{"rev":"HEAD~2","path":"README.md"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "# Demo\n\nFirst line.\n", out.(showOutput).Content)
+
+	out, err = callTool(t, ctx, ts, "blame", `{"path":"README.md","start_line":3,"end_line":10}`)
+	require.NoError(t, err)
+	b := out.(blameOutput)
+	require.Len(t, b.Lines, 2)
+	assert.Equal(t, 3, b.Lines[0].Line)
+	assert.Equal(t, "Second line.", b.Lines[0].Text)
+	assert.Equal(t, "Edit README", b.Lines[0].Summary)
+	assert.Equal(t, "Repo User", b.Lines[1].Author)
+	assert.NotEmpty(t, b.Lines[1].Date)
+
+	for args, want := range map[string]string{
+		`{"ref":"--output=/tmp/x"}`: "must not start with '-'",
+		`{"max_count":1000}`:        "max_count",
+		`{"ref":"missing"}`:         "bad revision",
+	} {
+		_, err := callTool(t, ctx, ts, "log", args)
+		assert.ErrorContains(t, err, want, args)
+	}
+}
+
+func TestToolSet_Branch(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := NewToolSet(WithRepoDir(dir), WithPolicy(Policy{ProtectedBranches: []string{"main"}}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = callTool(t, ctx, ts, "branch", `{"action":"create","name":"topic"}`)
+	require.NoError(t, err)
+	out, err := callTool(t, ctx, ts, "branch", `{}`)
+	require.NoError(t, err)
+	b := out.(branchOutput)
+	assert.Equal(t, "main", b.Current)
+	require.Len(t, b.Branches, 2)
+	assert.True(t, b.Branches[0].Current)
+	assert.True(t, b.Branches[0].Protected)
+	assert.Equal(t, "topic", b.Branches[1].Name)
+
+	for args, want := range map[string]string{
+		`{"action":"create","name":"bad..name"}`:          "invalid branch name",
+		`{"action":"create","name":"topic","force":true}`: "does not allow forced operations",
+		`{"action":"delete","name":"main"}`:               "protected and cannot be deleted",
+		`{"action":"rename","name":"x"}`:                  "unknown action",
+	} {
+		_, err := callTool(t, ctx, ts, "branch", args)
+		assert.ErrorContains(t, err, want, args)
+	}
+
+	// An unmerged branch can only be deleted with force.
+	gitRun(t, dir, "switch", "-q", "topic")
+	writeFile(t, dir, "topic.txt", "topic\n")
+	gitRun(t, dir, "add", "topic.txt")
+	gitRun(t, dir, "commit", "-q", "-m", "Topic")
+	gitRun(t, dir, "switch", "-q", "main")
+	_, err = callTool(t, ctx, ts, "branch", `{"action":"delete","name":"topic"}`)
+	assert.ErrorContains(t, err, "not fully merged")
+
+	ts, err = NewToolSet(WithRepoDir(dir), WithPolicy(Policy{AllowForce: true}))
+	require.NoError(t, err)
+	_, err = callTool(t, ctx, ts, "branch", `{"action":"delete","name":"topic","force":true}`)
+	require.NoError(t, err)
+	assert.Empty(t, gitRun(t, dir, "branch", "--list", "topic"))
+}
+
+func TestToolSet_LargeDiffArtifact(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "big.txt", strings.Repeat("line of text\n", 100))
+	gitRun(t, dir, "add", "big.txt")
+	ts, err := NewToolSet(WithRepoDir(dir), WithMaxDiffBytes(200))
+	require.NoError(t, err)
+
+	service := inmemory.NewService()
+	inv := agent.NewInvocation(
+		agent.WithInvocationSession(session.NewSession("app", "user", "s1")),
+		agent.WithInvocationArtifactService(service),
+	)
+	ctx := context.WithValue(agent.NewInvocationContext(context.Background(), inv),
+		tool.ContextKeyToolCallID{}, "call-1")
+
+	out, err := callTool(t, ctx, ts, "diff", `{"staged":true}`)
+	require.NoError(t, err)
+	d := out.(diffOutput)
+	assert.True(t, d.Truncated)
+	assert.LessOrEqual(t, len(d.Patch), 200)
+	assert.True(t, strings.HasSuffix(d.Patch, "\n"))
+	require.NotNil(t, d.Artifact)
+	assert.Equal(t, "git_diff_call-1.patch", d.Artifact.Name)
+	assert.Contains(t, d.Note, "saved as the artifact")
+
+	saved, err := service.LoadArtifact(context.Background(),
+		artifact.SessionInfo{AppName: "app", UserID: "user", SessionID: "s1"}, d.Artifact.Name, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "text/x-diff", saved.MimeType)
+	assert.Equal(t, 100, strings.Count(string(saved.Data), "+line of text\n"))
+
+	// Without an artifact service a preview is returned with a note.
+	out, err = callTool(t, context.Background(), ts, "diff", `{"staged":true}`)
+	require.NoError(t, err)
+	d = out.(diffOutput)
+	assert.True(t, d.Truncated)
+	assert.Nil(t, d.Artifact)
+	assert.Contains(t, d.Note, "could not be saved")
+}
+
+func TestToolSet_Push(t *testing.T) {
+	dir := newRepo(t)
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "-q", "--bare")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+	gitRun(t, dir, "switch", "-q", "-c", "feature")
+
+	ts, err := NewToolSet(WithRepoDir(dir), WithPolicy(Policy{
+		AllowPush:         true,
+		ProtectedBranches: []string{"main"},
+	}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	out, err := callTool(t, ctx, ts, "push", `{"set_upstream":true}`)
+	require.NoError(t, err)
+	assert.Equal(t, pushOutput{Remote: "origin", Branch: "feature", Output: out.(pushOutput).Output}, out)
+	assert.Equal(t, gitRun(t, dir, "rev-parse", "HEAD"), gitRun(t, remote, "rev-parse", "feature"))
+	assert.Equal(t, "origin/feature", gitRun(t, dir, "rev-parse", "--abbrev-ref", "@{upstream}"))
+
+	_, err = callTool(t, ctx, ts, "push", `{"branch":"main"}`)
+	assert.ErrorContains(t, err, "protected and cannot be pushed")
+	_, err = callTool(t, ctx, ts, "push", `{"force":true}`)
+	assert.ErrorIs(t, err, errForce)
+	_, err = callTool(t, ctx, ts, "push", `{"remote":"--upload-pack=touch"}`)
+	assert.ErrorContains(t, err, "must not start with '-'")
+	_, err = callTool(t, ctx, ts, "push", `{"remote":"upstream"}`)
+	assert.ErrorContains(t, err, "unknown remote")
+	for _, url := range []string{"file://" + remote, "ext::sh -c touch", remote, "../remote"} {
+		_, err = callTool(t, ctx, ts, "push", fmt.Sprintf(`{"remote":%q}`, url))
+		assert.ErrorContains(t, err, "must be the name of a configured remote", url)
+	}
+	ts.(*toolSet).config.policy.Remotes = []string{"backup"}
+	_, err = callTool(t, ctx, ts, "push", `{}`)
+	assert.ErrorContains(t, err, "does not allow pushes to remote")
+
+	// Other tools cannot reach remotes.
+	_, err = ts.(*toolSet).git(ctx, dir, "fetch", "origin")
+	assert.ErrorContains(t, err, "not allowed")
+}
+
+func TestToolSet_Worktree(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := NewToolSet(WithRepoDir(dir), WithWorktreeRoot(t.TempDir()))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	out, err := callTool(t, ctx, ts, "worktree_create", `{"id":"fix-1"}`)
+	require.NoError(t, err)
+	wt := out.(worktreeInfo)
+	assert.True(t, strings.HasPrefix(wt.Branch, "agent-worktree-fix-1-"), wt.Branch)
+	assert.FileExists(t, filepath.Join(wt.Path, "README.md"))
+	_, err = callTool(t, ctx, ts, "worktree_create", `{"id":"fix-1"}`)
+	assert.ErrorContains(t, err, "already exists")
+
+	out, err = callTool(t, ctx, ts, "worktree_list", `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, []worktreeInfo{wt}, out.(worktreeListOutput).Worktrees)
+
+	// Tools run in the worktree when given its id.
+	writeFile(t, wt.Path, "fix.txt", "fix\n")
+	out, err = callTool(t, ctx, ts, "status", `{"worktree":"fix-1"}`)
+	require.NoError(t, err)
+	assert.Equal(t, wt.Branch, out.(statusOutput).Branch)
+	assert.Equal(t, []fileStatus{{Path: "fix.txt", Untracked: true}}, out.(statusOutput).Files)
+	out, err = callTool(t, ctx, ts, "status", `{}`)
+	require.NoError(t, err)
+	assert.True(t, out.(statusOutput).Clean)
+	_, err = callTool(t, ctx, ts, "status", `{"worktree":"other"}`)
+	assert.ErrorContains(t, err, "unknown worktree")
+
+	// A worktree with changes is kept unless removed with force.
+	out, err = callTool(t, ctx, ts, "worktree_remove", `{"id":"fix-1"}`)
+	require.NoError(t, err)
+	assert.False(t, out.(worktreeRemoveOutput).Removed)
+	assert.True(t, out.(worktreeRemoveOutput).HasChanges)
+	_, err = callTool(t, ctx, ts, "worktree_remove", `{"id":"fix-1","force":true}`)
+	assert.ErrorIs(t, err, errForce)
+
+	ts.(*toolSet).config.policy.AllowForce = true
+	out, err = callTool(t, ctx, ts, "worktree_remove", `{"id":"fix-1","force":true}`)
+	require.NoError(t, err)
+	assert.True(t, out.(worktreeRemoveOutput).Removed)
+	assert.NoDirExists(t, wt.Path)
+	assert.Empty(t, gitRun(t, dir, "branch", "--list", wt.Branch))
+
+	// Close removes clean worktrees.
+	out, err = callTool(t, ctx, ts, "worktree_create", `{"id":"clean"}`)
+	require.NoError(t, err)
+	require.NoError(t, ts.Close())
+	assert.NoDirExists(t, out.(worktreeInfo).Path)
+}
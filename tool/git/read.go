@@ -0,0 +1,482 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultLogCount is the default number of commits log returns.
+	defaultLogCount = 20
+	// maxLogCount bounds the commits log returns.
+	maxLogCount = 200
+	// maxBlameLines bounds the lines blame returns.
+	maxBlameLines = 500
+	// fieldSep and recordSep separate the fields and records of
+	// formatted git output.
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+type statusInput struct {
+	Worktree string `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+}
+
+type statusOutput struct {
+	Branch   string       `json:"branch,omitempty"`
+	Commit   string       `json:"commit,omitempty"`
+	Upstream string       `json:"upstream,omitempty"`
+	Ahead    int          `json:"ahead,omitempty"`
+	Behind   int          `json:"behind,omitempty"`
+	Clean    bool         `json:"clean"`
+	Files    []fileStatus `json:"files,omitempty"`
+}
+
+// fileStatus is the state of a changed file. Staged and Unstaged hold the
+// git status letters of the index and the working tree, such as "M" for
+// modified, "A" for added, "D" for deleted and "R" for renamed.
+type fileStatus struct {
+	Path       string `json:"path"`
+	OrigPath   string `json:"orig_path,omitempty"`
+	Staged     string `json:"staged,omitempty"`
+	Unstaged   string `json:"unstaged,omitempty"`
+	Untracked  bool   `json:"untracked,omitempty"`
+	Conflicted bool   `json:"conflicted,omitempty"`
+}
+
+func (ts *toolSet) status(ctx context.Context, in statusInput) (statusOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return statusOutput{}, err
+	}
+	out, err := ts.git(ctx, dir, "status", "--porcelain=v2", "--branch", "-z", "--untracked-files=all")
+	if err != nil {
+		return statusOutput{}, err
+	}
+	return parseStatus(out), nil
+}
+
+// parseStatus parses the output of git status --porcelain=v2 -z.
+func parseStatus(out string) statusOutput {
+	var st statusOutput
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		if e == "" {
+			continue
+		}
+		switch e[0] {
+		case '#':
+			fields := strings.Fields(e)
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "branch.oid":
+				if fields[2] != "(initial)" {
+					st.Commit = fields[2]
+				}
+			case "branch.head":
+				if fields[2] != "(detached)" {
+					st.Branch = fields[2]
+				}
+			case "branch.upstream":
+				st.Upstream = fields[2]
+			case "branch.ab":
+				if len(fields) == 4 {
+					st.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+					st.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+				}
+			}
+		case '1', '2', 'u':
+			// "1 XY sub mH mI mW hH hI path", "2 XY ... Xscore path" with
+			// the original path in the next entry, and "u XY sub m1 m2 m3
+			// mW h1 h2 h3 path".
+			n := map[byte]int{'1': 9, '2': 10, 'u': 11}[e[0]]
+			fields := strings.SplitN(e, " ", n)
+			if len(fields) < n {
+				continue
+			}
+			f := fileStatus{
+				Path:       fields[n-1],
+				Staged:     statusLetter(fields[1][0]),
+				Unstaged:   statusLetter(fields[1][1]),
+				Conflicted: e[0] == 'u',
+			}
+			if e[0] == '2' && i+1 < len(entries) {
+				i++
+				f.OrigPath = entries[i]
+			}
+			st.Files = append(st.Files, f)
+		case '?':
+			st.Files = append(st.Files, fileStatus{Path: e[2:], Untracked: true})
+		}
+	}
+	st.Clean = len(st.Files) == 0
+	return st
+}
+
+func statusLetter(c byte) string {
+	if c == '.' {
+		return ""
+	}
+	return string(c)
+}
+
+type diffInput struct {
+	Worktree string   `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Staged   bool     `json:"staged,omitempty" jsonschema:"description=Show staged changes instead of unstaged ones"`
+	Ref      string   `json:"ref,omitempty" jsonschema:"description=Compare the working tree (or the index when staged) with this commit; or two commits as a..b"`
+	Paths    []string `json:"paths,omitempty" jsonschema:"description=Limit the diff to these files or directories"`
+	StatOnly bool     `json:"stat_only,omitempty" jsonschema:"description=Return only the changed files and line counts"`
+}
+
+type diffOutput struct {
+	Files     []diffFile   `json:"files"`
+	Patch     string       `json:"patch,omitempty"`
+	Truncated bool         `json:"truncated,omitempty"`
+	Artifact  *artifactRef `json:"artifact,omitempty"`
+	Note      string       `json:"note,omitempty"`
+}
+
+// diffFile is a changed file with its added and deleted line counts.
+type diffFile struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+func (ts *toolSet) diff(ctx context.Context, in diffInput) (diffOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return diffOutput{}, err
+	}
+	args := []string{"diff", "--no-ext-diff", "--no-textconv", "-M"}
+	if in.Staged {
+		args = append(args, "--cached")
+	}
+	if in.Ref != "" {
+		if err := checkArg("ref", in.Ref); err != nil {
+			return diffOutput{}, err
+		}
+		args = append(args, in.Ref)
+	}
+	if err := checkPaths(in.Paths); err != nil {
+		return diffOutput{}, err
+	}
+	tail := append([]string{"--"}, in.Paths...)
+
+	stat, err := ts.git(ctx, dir, concat(args, []string{"--numstat", "-z"}, tail)...)
+	if err != nil {
+		return diffOutput{}, err
+	}
+	out := diffOutput{Files: parseNumstat(stat)}
+	if in.StatOnly || len(out.Files) == 0 {
+		return out, nil
+	}
+	patch, err := ts.git(ctx, dir, concat(args, tail)...)
+	if err != nil {
+		return diffOutput{}, err
+	}
+	out.Patch, out.Truncated, out.Artifact, out.Note = ts.inline(ctx, "git_diff", ".patch", patchMimeType, patch)
+	return out, nil
+}
+
+// parseNumstat parses the output of git diff --numstat -z. A renamed file
+// is written as "added\tdeleted\t\0old\0new\0".
+func parseNumstat(out string) []diffFile {
+	files := []diffFile{}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		fields := strings.SplitN(entries[i], "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := diffFile{Path: fields[2]}
+		if fields[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		if f.Path == "" && i+2 < len(entries) {
+			f.OldPath, f.Path = entries[i+1], entries[i+2]
+			i += 2
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+type logInput struct {
+	Worktree string   `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Ref      string   `json:"ref,omitempty" jsonschema:"description=Revision or range to list; default HEAD"`
+	Paths    []string `json:"paths,omitempty" jsonschema:"description=Only list commits that touch these files or directories"`
+	MaxCount int      `json:"max_count,omitempty" jsonschema:"description=Maximum number of commits; default 20 and at most 200"`
+	Skip     int      `json:"skip,omitempty" jsonschema:"description=Number of commits to skip when paging"`
+}
+
+type logOutput struct {
+	Commits []commitInfo `json:"commits"`
+	More    bool         `json:"more,omitempty"`
+}
+
+// commitInfo describes a commit. Body is only set by show.
+type commitInfo struct {
+	Hash    string   `json:"hash"`
+	Parents []string `json:"parents,omitempty"`
+	Author  string   `json:"author"`
+	Email   string   `json:"email"`
+	Date    string   `json:"date"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body,omitempty"`
+}
+
+// commitFormat prints the fields of commitInfo in order.
+const commitFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1e"
+
+func (ts *toolSet) log(ctx context.Context, in logInput) (logOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return logOutput{}, err
+	}
+	count := in.MaxCount
+	if count == 0 {
+		count = defaultLogCount
+	}
+	if count < 0 || count > maxLogCount || in.Skip < 0 {
+		return logOutput{}, fmt.Errorf("max_count must be between 1 and %d and skip must not be negative", maxLogCount)
+	}
+	args := []string{"log", "--format=" + commitFormat, "-n", strconv.Itoa(count + 1), "--skip", strconv.Itoa(in.Skip)}
+	if in.Ref != "" {
+		if err := checkArg("ref", in.Ref); err != nil {
+			return logOutput{}, err
+		}
+		args = append(args, in.Ref)
+	}
+	if err := checkPaths(in.Paths); err != nil {
+		return logOutput{}, err
+	}
+	out, err := ts.git(ctx, dir, concat(args, []string{"--"}, in.Paths)...)
+	if err != nil {
+		return logOutput{}, err
+	}
+	commits := parseCommits(out)
+	for i := range commits {
+		commits[i].Body = ""
+	}
+	result := logOutput{Commits: commits}
+	if len(commits) > count {
+		result.Commits, result.More = commits[:count], true
+	}
+	return result, nil
+}
+
+// parseCommits parses commits printed with commitFormat.
+func parseCommits(out string) []commitInfo {
+	commits := []commitInfo{}
+	for _, record := range strings.Split(out, recordSep) {
+		record = strings.TrimLeft(record, "\n")
+		fields := strings.SplitN(record, fieldSep, 7)
+		if len(fields) != 7 {
+			continue
+		}
+		commits = append(commits, commitInfo{
+			Hash:    fields[0],
+			Parents: strings.Fields(fields[1]),
+			Author:  fields[2],
+			Email:   fields[3],
+			Date:    fields[4],
+			Subject: fields[5],
+			Body:    strings.TrimSpace(fields[6]),
+		})
+	}
+	return commits
+}
+
+type showInput struct {
+	Worktree string `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Rev      string `json:"rev,omitempty" jsonschema:"description=Commit or branch or tag; default HEAD"`
+	Path     string `json:"path,omitempty" jsonschema:"description=Return the content of this file at the revision instead of the commit"`
+}
+
+type showOutput struct {
+	Commit    *commitInfo  `json:"commit,omitempty"`
+	Files     []diffFile   `json:"files,omitempty"`
+	Patch     string       `json:"patch,omitempty"`
+	Content   string       `json:"content,omitempty"`
+	Truncated bool         `json:"truncated,omitempty"`
+	Artifact  *artifactRef `json:"artifact,omitempty"`
+	Note      string       `json:"note,omitempty"`
+}
+
+func (ts *toolSet) show(ctx context.Context, in showInput) (showOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return showOutput{}, err
+	}
+	rev := in.Rev
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if err := checkArg("rev", rev); err != nil {
+		return showOutput{}, err
+	}
+	if in.Path != "" {
+		if strings.ContainsAny(in.Path, "\x00\n") {
+			return showOutput{}, fmt.Errorf("invalid path %q", in.Path)
+		}
+		// "./" makes the path relative to the working directory rather
+		// than to the top of the repository.
+		content, err := ts.git(ctx, dir, "show", "--no-textconv", rev+":./"+strings.TrimPrefix(in.Path, "./"))
+		if err != nil {
+			return showOutput{}, err
+		}
+		var out showOutput
+		out.Content, out.Truncated, out.Artifact, out.Note = ts.inline(ctx, "git_show", ".txt", textMimeType, content)
+		return out, nil
+	}
+
+	meta, err := ts.git(ctx, dir, "show", "-s", "--format="+commitFormat, rev, "--")
+	if err != nil {
+		return showOutput{}, err
+	}
+	commits := parseCommits(meta)
+	if len(commits) != 1 {
+		return showOutput{}, fmt.Errorf("%s is not a commit", rev)
+	}
+	stat, err := ts.git(ctx, dir, "show", "--format=", "--no-ext-diff", "-M", "--numstat", "-z", rev, "--")
+	if err != nil {
+		return showOutput{}, err
+	}
+	patch, err := ts.git(ctx, dir, "show", "--format=", "--no-ext-diff", "--no-textconv", "-M", rev, "--")
+	if err != nil {
+		return showOutput{}, err
+	}
+	out := showOutput{Commit: &commits[0], Files: parseNumstat(stat)}
+	out.Patch, out.Truncated, out.Artifact, out.Note = ts.inline(ctx, "git_show", ".patch", patchMimeType, patch)
+	return out, nil
+}
+
+type blameInput struct {
+	Worktree  string `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Path      string `json:"path" jsonschema:"description=File to blame"`
+	Rev       string `json:"rev,omitempty" jsonschema:"description=Blame the file as of this revision; default the working tree"`
+	StartLine int    `json:"start_line,omitempty" jsonschema:"description=First line to blame; lines start at 1"`
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"description=Last line to blame"`
+}
+
+type blameOutput struct {
+	Lines []blameLine `json:"lines"`
+	Note  string      `json:"note,omitempty"`
+}
+
+type blameLine struct {
+	Line    int    `json:"line"`
+	Commit  string `json:"commit"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
+}
+
+func (ts *toolSet) blame(ctx context.Context, in blameInput) (blameOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return blameOutput{}, err
+	}
+	if err := checkPaths([]string{in.Path}); err != nil {
+		return blameOutput{}, err
+	}
+	if in.StartLine < 0 || in.EndLine < 0 || (in.EndLine > 0 && in.EndLine < in.StartLine) {
+		return blameOutput{}, fmt.Errorf("invalid line range %d-%d", in.StartLine, in.EndLine)
+	}
+	start, end := max(in.StartLine, 1), in.EndLine
+	if end == 0 || end-start+1 > maxBlameLines {
+		end = start + maxBlameLines - 1
+	}
+	args := []string{"blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", start, end)}
+	if in.Rev != "" {
+		if err := checkArg("rev", in.Rev); err != nil {
+			return blameOutput{}, err
+		}
+		args = append(args, in.Rev)
+	}
+	out, err := ts.git(ctx, dir, append(args, "--", in.Path)...)
+	if err != nil && strings.Contains(err.Error(), "has only") {
+		// The range ends past the end of the file; blame to the end.
+		args[3] = fmt.Sprintf("%d,", start)
+		out, err = ts.git(ctx, dir, append(args, "--", in.Path)...)
+	}
+	if err != nil {
+		return blameOutput{}, err
+	}
+	result := blameOutput{Lines: parseBlame(out)}
+	if n := len(result.Lines); n == maxBlameLines && (in.EndLine == 0 || in.EndLine > end) {
+		result.Note = fmt.Sprintf("Only lines %d to %d are shown; use start_line to see more.",
+			start, result.Lines[n-1].Line)
+	}
+	return result, nil
+}
+
+// parseBlame parses the output of git blame --porcelain, in which the
+// details of a commit are only printed for its first line.
+func parseBlame(out string) []blameLine {
+	type commitDetails struct{ author, date, summary string }
+	var (
+		lines   = []blameLine{}
+		commits = make(map[string]*commitDetails)
+		cur     *commitDetails
+		line    blameLine
+	)
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(l, "\t") {
+			line.Text = l[1:]
+			line.Author, line.Date, line.Summary = cur.author, cur.date, cur.summary
+			lines = append(lines, line)
+			continue
+		}
+		key, value, _ := strings.Cut(l, " ")
+		switch key {
+		case "author":
+			cur.author = value
+		case "author-time":
+			cur.date = unixDate(value)
+		case "summary":
+			cur.summary = value
+		default:
+			fields := strings.Fields(l)
+			if len(fields) < 3 || len(fields[0]) < 40 {
+				continue
+			}
+			if commits[fields[0]] == nil {
+				commits[fields[0]] = &commitDetails{}
+			}
+			cur = commits[fields[0]]
+			n, _ := strconv.Atoi(fields[2])
+			line = blameLine{Line: n, Commit: fields[0]}
+		}
+	}
+	return lines
+}
+
+func concat(parts ...[]string) []string {
+	var out []string
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
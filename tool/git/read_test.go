@@ -0,0 +1,74 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := strings.Join([]string{
+		"# branch.oid 1234567890abcdef",
+		"# branch.head topic",
+		"# branch.upstream origin/topic",
+		"# branch.ab +2 -1",
+		"2 R. N... 100644 100644 100644 aaa bbb R100 new name.go",
+		"old name.go",
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go",
+		"1 .D N... 100644 100644 000000 aaa aaa gone.go",
+		"? new.txt",
+		"",
+	}, "\x00")
+	assert.Equal(t, statusOutput{
+		Branch:   "topic",
+		Commit:   "1234567890abcdef",
+		Upstream: "origin/topic",
+		Ahead:    2,
+		Behind:   1,
+		Files: []fileStatus{
+			{Path: "new name.go", OrigPath: "old name.go", Staged: "R"},
+			{Path: "conflict.go", Staged: "U", Unstaged: "U", Conflicted: true},
+			{Path: "gone.go", Unstaged: "D"},
+			{Path: "new.txt", Untracked: true},
+		},
+	}, parseStatus(out))
+
+	assert.Equal(t, statusOutput{Branch: "main", Clean: true},
+		parseStatus("# branch.oid (initial)\x00# branch.head main\x00"))
+}
+
+func TestParseNumstat(t *testing.T) {
+	out := "3\t1\tmain.go\x00-\t-\tlogo.png\x000\t0\t\x00old.go\x00new.go\x00"
+	assert.Equal(t, []diffFile{
+		{Path: "main.go", Added: 3, Deleted: 1},
+		{Path: "logo.png", Binary: true},
+		{Path: "new.go", OldPath: "old.go"},
+	}, parseNumstat(out))
+	assert.Equal(t, []diffFile{}, parseNumstat(""))
+}
+
+func TestParseBlame(t *testing.T) {
+	sha1 := strings.Repeat("a", 40)
+	sha2 := strings.Repeat("b", 40)
+	out := sha1 + " 1 1 2\n" +
+		"author Ann\nauthor-mail <ann@example.com>\nauthor-time 1700000000\nauthor-tz +0000\n" +
+		"summary First commit\nfilename f.go\n\tpackage f\n" +
+		sha1 + " 2 2\n\t\n" +
+		sha2 + " 3 3 1\n" +
+		"author Bob\nauthor-time 1700003600\nsummary Add func\nprevious " + sha1 + " f.go\nfilename f.go\n\tfunc F() {}\n"
+	assert.Equal(t, []blameLine{
+		{Line: 1, Commit: sha1, Author: "Ann", Date: "2023-11-14T22:13:20Z", Summary: "First commit", Text: "package f"},
+		{Line: 2, Commit: sha1, Author: "Ann", Date: "2023-11-14T22:13:20Z", Summary: "First commit", Text: ""},
+		{Line: 3, Commit: sha2, Author: "Bob", Date: "2023-11-14T23:13:20Z", Summary: "Add func", Text: "func F() {}"},
+	}, parseBlame(out))
+}
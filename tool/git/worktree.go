@@ -0,0 +1,128 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"trpc.group/trpc-go/trpc-agent-go/internal/gitworktree"
+)
+
+type worktreeCreateInput struct {
+	ID         string `json:"id" jsonschema:"description=Short name of the worktree; also used in its branch name"`
+	AllowDirty bool   `json:"allow_dirty,omitempty" jsonschema:"description=Create the worktree even when the main checkout has uncommitted changes (they are not copied)"`
+}
+
+type worktreeInfo struct {
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	Branch     string `json:"branch"`
+	BaseCommit string `json:"base_commit"`
+}
+
+func newWorktreeInfo(lease gitworktree.Lease) worktreeInfo {
+	return worktreeInfo{ID: lease.ID, Path: lease.Path, Branch: lease.Branch, BaseCommit: lease.BaseCommit}
+}
+
+func (ts *toolSet) worktreeCreate(ctx context.Context, in worktreeCreateInput) (worktreeInfo, error) {
+	if in.ID == "" {
+		return worktreeInfo{}, errors.New("id is required")
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if _, ok := ts.leases[in.ID]; ok {
+		return worktreeInfo{}, fmt.Errorf("worktree %q already exists", in.ID)
+	}
+	lease, err := ts.worktree.Create(ctx, gitworktree.CreateRequest{
+		ID:         in.ID,
+		Workdir:    ts.repoDir,
+		AllowDirty: in.AllowDirty,
+	})
+	if errors.Is(err, gitworktree.ErrDirtySource) {
+		return worktreeInfo{}, errors.New("the main checkout has uncommitted changes, which a new worktree " +
+			"would not contain; commit them first or set allow_dirty")
+	}
+	if err != nil {
+		return worktreeInfo{}, err
+	}
+	ts.leases[in.ID] = lease
+	return newWorktreeInfo(lease), nil
+}
+
+type worktreeListInput struct{}
+
+type worktreeListOutput struct {
+	Worktrees []worktreeInfo `json:"worktrees"`
+}
+
+func (ts *toolSet) worktreeList(_ context.Context, _ worktreeListInput) (worktreeListOutput, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	out := worktreeListOutput{Worktrees: make([]worktreeInfo, 0, len(ts.leases))}
+	for _, lease := range ts.leases {
+		out.Worktrees = append(out.Worktrees, newWorktreeInfo(lease))
+	}
+	sort.Slice(out.Worktrees, func(i, j int) bool { return out.Worktrees[i].ID < out.Worktrees[j].ID })
+	return out, nil
+}
+
+type worktreeRemoveInput struct {
+	ID    string `json:"id" jsonschema:"description=Id of the worktree"`
+	Force bool   `json:"force,omitempty" jsonschema:"description=Remove the worktree and its branch even if they have changes; needs a policy that allows forced operations"`
+}
+
+type worktreeRemoveOutput struct {
+	ID         string `json:"id"`
+	Removed    bool   `json:"removed"`
+	HasChanges bool   `json:"has_changes,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (ts *toolSet) worktreeRemove(ctx context.Context, in worktreeRemoveInput) (worktreeRemoveOutput, error) {
+	if in.Force && !ts.config.policy.AllowForce {
+		return worktreeRemoveOutput{}, errForce
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	lease, ok := ts.leases[in.ID]
+	if !ok {
+		return worktreeRemoveOutput{}, fmt.Errorf("unknown worktree %q", in.ID)
+	}
+	result, err := ts.worktree.Finalize(ctx, lease)
+	if err != nil {
+		return worktreeRemoveOutput{}, err
+	}
+	out := worktreeRemoveOutput{ID: in.ID, Removed: result.Removed, HasChanges: result.HasChanges}
+	if result.Removed {
+		delete(ts.leases, in.ID)
+		out.Message = fmt.Sprintf("Removed worktree %s and branch %s.", in.ID, lease.Branch)
+		return out, nil
+	}
+	if !in.Force {
+		out.Message = fmt.Sprintf("Kept worktree %s because it has uncommitted changes or new commits on %s. "+
+			"Merge or discard them, or remove it with force.", in.ID, result.Branch)
+		return out, nil
+	}
+	if _, err := ts.git(ctx, lease.RepoRoot, "worktree", "remove", "--force", lease.Path); err != nil {
+		return worktreeRemoveOutput{}, err
+	}
+	delete(ts.leases, in.ID)
+	if _, err := ts.git(ctx, lease.RepoRoot, "rev-parse", "--verify", "--quiet", "refs/heads/"+lease.Branch); err == nil {
+		if _, err := ts.git(ctx, lease.RepoRoot, "branch", "--delete", "--force", lease.Branch); err != nil {
+			return worktreeRemoveOutput{}, err
+		}
+	}
+	out.Removed = true
+	out.Message = fmt.Sprintf("Removed worktree %s and branch %s, discarding their changes.", in.ID, lease.Branch)
+	return out, nil
+}
@@ -0,0 +1,368 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Branch actions.
+const (
+	branchList   = "list"
+	branchCreate = "create"
+	branchDelete = "delete"
+	branchSwitch = "switch"
+)
+
+// defaultRemote is the remote push uses by default.
+const defaultRemote = "origin"
+
+var errForce = errors.New("the policy does not allow forced operations")
+
+type branchInput struct {
+	Worktree   string `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Action     string `json:"action,omitempty" jsonschema:"description=What to do; default list,enum=list,enum=create,enum=delete,enum=switch"`
+	Name       string `json:"name,omitempty" jsonschema:"description=Branch to create or delete or switch to"`
+	StartPoint string `json:"start_point,omitempty" jsonschema:"description=Commit a new branch starts at; default HEAD"`
+	Force      bool   `json:"force,omitempty" jsonschema:"description=Reset an existing branch on create; delete an unmerged branch; or discard local changes on switch"`
+}
+
+type branchOutput struct {
+	Current  string       `json:"current,omitempty"`
+	Branches []branchInfo `json:"branches,omitempty"`
+	Message  string       `json:"message,omitempty"`
+}
+
+type branchInfo struct {
+	Name      string `json:"name"`
+	Commit    string `json:"commit"`
+	Upstream  string `json:"upstream,omitempty"`
+	Current   bool   `json:"current,omitempty"`
+	Protected bool   `json:"protected,omitempty"`
+}
+
+func (ts *toolSet) branch(ctx context.Context, in branchInput) (branchOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return branchOutput{}, err
+	}
+	policy := ts.config.policy
+	action := in.Action
+	if action == "" {
+		action = branchList
+	}
+	if action == branchList {
+		return ts.listBranches(ctx, dir)
+	}
+	switch action {
+	case branchCreate, branchDelete, branchSwitch:
+	default:
+		return branchOutput{}, fmt.Errorf("unknown action %q", action)
+	}
+	if err := ts.checkBranchName(ctx, dir, in.Name); err != nil {
+		return branchOutput{}, err
+	}
+	if in.Force && !policy.AllowForce {
+		return branchOutput{}, errForce
+	}
+
+	var args []string
+	switch action {
+	case branchCreate:
+		if in.Force && policy.protected(in.Name) {
+			return branchOutput{}, fmt.Errorf("branch %q is protected and cannot be reset", in.Name)
+		}
+		args = []string{"branch"}
+		if in.Force {
+			args = append(args, "--force")
+		}
+		args = append(args, in.Name)
+		if in.StartPoint != "" {
+			if err := checkArg("start point", in.StartPoint); err != nil {
+				return branchOutput{}, err
+			}
+			args = append(args, in.StartPoint)
+		}
+	case branchDelete:
+		if policy.protected(in.Name) {
+			return branchOutput{}, fmt.Errorf("branch %q is protected and cannot be deleted", in.Name)
+		}
+		args = []string{"branch", "--delete"}
+		if in.Force {
+			args = append(args, "--force")
+		}
+		args = append(args, in.Name)
+	case branchSwitch:
+		args = []string{"switch", "--no-guess"}
+		if in.Force {
+			args = append(args, "--discard-changes")
+		}
+		args = append(args, in.Name)
+	}
+	if _, err := ts.git(ctx, dir, args...); err != nil {
+		return branchOutput{}, err
+	}
+	current, err := ts.currentBranch(ctx, dir)
+	if err != nil {
+		return branchOutput{}, err
+	}
+	return branchOutput{
+		Current: current,
+		Message: fmt.Sprintf("%s branch %s.", map[string]string{
+			branchCreate: "Created", branchDelete: "Deleted", branchSwitch: "Switched to",
+		}[action], in.Name),
+	}, nil
+}
+
+func (ts *toolSet) listBranches(ctx context.Context, dir string) (branchOutput, error) {
+	out, err := ts.git(ctx, dir, "for-each-ref",
+		"--format=%(refname:short)%1f%(objectname)%1f%(upstream:short)%1f%(HEAD)", "refs/heads")
+	if err != nil {
+		return branchOutput{}, err
+	}
+	result := branchOutput{Branches: []branchInfo{}}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, fieldSep)
+		if len(fields) != 4 {
+			continue
+		}
+		b := branchInfo{
+			Name:      fields[0],
+			Commit:    fields[1],
+			Upstream:  fields[2],
+			Current:   fields[3] == "*",
+			Protected: ts.config.policy.protected(fields[0]),
+		}
+		if b.Current {
+			result.Current = b.Name
+		}
+		result.Branches = append(result.Branches, b)
+	}
+	if result.Current == "" {
+		// An unborn branch has no ref yet.
+		if result.Current, err = ts.currentBranch(ctx, dir); err != nil {
+			return branchOutput{}, err
+		}
+	}
+	return result, nil
+}
+
+// checkBranchName validates a branch name with git's own rules.
+func (ts *toolSet) checkBranchName(ctx context.Context, dir, name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if err := checkArg("branch name", name); err != nil {
+		return err
+	}
+	if _, err := ts.git(ctx, dir, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
+
+// checkRemote rejects a remote that is not configured in the repository or
+// not allowed by the policy. URLs and paths are rejected outright, so push
+// only reaches remotes set up by the user.
+func (ts *toolSet) checkRemote(ctx context.Context, dir, name string) error {
+	if err := checkArg("remote", name); err != nil {
+		return err
+	}
+	if strings.Contains(name, "://") || strings.Contains(name, "::") ||
+		strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("remote %q must be the name of a configured remote", name)
+	}
+	if allowed := ts.config.policy.Remotes; len(allowed) > 0 && !slices.Contains(allowed, name) {
+		return fmt.Errorf("the policy does not allow pushes to remote %q", name)
+	}
+	out, err := ts.git(ctx, dir, "remote")
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(strings.Fields(out), name) {
+		return fmt.Errorf("unknown remote %q", name)
+	}
+	return nil
+}
+
+type stageInput struct {
+	Worktree string   `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Paths    []string `json:"paths,omitempty" jsonschema:"description=Files or directories to stage or unstage"`
+	All      bool     `json:"all,omitempty" jsonschema:"description=Stage or unstage every change including new and deleted files"`
+	Unstage  bool     `json:"unstage,omitempty" jsonschema:"description=Remove the paths from the index instead of adding them"`
+}
+
+func (ts *toolSet) stage(ctx context.Context, in stageInput) (statusOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return statusOutput{}, err
+	}
+	if len(in.Paths) == 0 && !in.All {
+		return statusOutput{}, errors.New("paths or all is required")
+	}
+	if err := checkPaths(in.Paths); err != nil {
+		return statusOutput{}, err
+	}
+	paths := in.Paths
+	if in.All {
+		paths = []string{":/"}
+	}
+	args := append([]string{"add", "--all", "--"}, paths...)
+	if in.Unstage {
+		args = append([]string{"restore", "--staged", "--"}, paths...)
+		if _, err := ts.git(ctx, dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+			// Without a commit the index is emptied instead.
+			args = append([]string{"rm", "--cached", "-r", "-q", "--ignore-unmatch", "--"}, paths...)
+		}
+	}
+	if _, err := ts.git(ctx, dir, args...); err != nil {
+		return statusOutput{}, err
+	}
+	return ts.status(ctx, statusInput{Worktree: in.Worktree})
+}
+
+type commitInput struct {
+	Worktree string   `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Message  string   `json:"message" jsonschema:"description=Commit message"`
+	Paths    []string `json:"paths,omitempty" jsonschema:"description=Commit only these tracked files with their current content instead of the staged changes"`
+	All      bool     `json:"all,omitempty" jsonschema:"description=Stage changes to all tracked files before committing"`
+	Amend    bool     `json:"amend,omitempty" jsonschema:"description=Replace the last commit; needs a policy that allows forced operations"`
+}
+
+type commitOutput struct {
+	Commit  string     `json:"commit"`
+	Branch  string     `json:"branch,omitempty"`
+	Summary string     `json:"summary"`
+	Files   []diffFile `json:"files"`
+}
+
+func (ts *toolSet) commit(ctx context.Context, in commitInput) (commitOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return commitOutput{}, err
+	}
+	if strings.TrimSpace(in.Message) == "" {
+		return commitOutput{}, errors.New("message is required")
+	}
+	if in.All && len(in.Paths) > 0 {
+		return commitOutput{}, errors.New("all and paths cannot be used together")
+	}
+	if err := checkPaths(in.Paths); err != nil {
+		return commitOutput{}, err
+	}
+	if in.Amend && !ts.config.policy.AllowForce {
+		return commitOutput{}, errForce
+	}
+	branch, err := ts.currentBranch(ctx, dir)
+	if err != nil {
+		return commitOutput{}, err
+	}
+	if branch != "" && ts.config.policy.protected(branch) {
+		return commitOutput{}, fmt.Errorf("branch %q is protected; create or switch to another branch and commit there", branch)
+	}
+
+	args := []string{"commit", "--quiet", "--message=" + in.Message}
+	if in.All {
+		args = append(args, "--all")
+	}
+	if in.Amend {
+		args = append(args, "--amend")
+	}
+	if _, err := ts.git(ctx, dir, concat(args, []string{"--"}, in.Paths)...); err != nil {
+		return commitOutput{}, err
+	}
+	out, err := ts.git(ctx, dir, "show", "-s", "--format="+commitFormat, "HEAD", "--")
+	if err != nil {
+		return commitOutput{}, err
+	}
+	commits := parseCommits(out)
+	if len(commits) != 1 {
+		return commitOutput{}, errors.New("read the new commit")
+	}
+	stat, err := ts.git(ctx, dir, "show", "--format=", "--no-ext-diff", "-M", "--numstat", "-z", "HEAD", "--")
+	if err != nil {
+		return commitOutput{}, err
+	}
+	return commitOutput{
+		Commit:  commits[0].Hash,
+		Branch:  branch,
+		Summary: commits[0].Subject,
+		Files:   parseNumstat(stat),
+	}, nil
+}
+
+type pushInput struct {
+	Worktree    string `json:"worktree,omitempty" jsonschema:"description=Id of a worktree to use instead of the main checkout"`
+	Remote      string `json:"remote,omitempty" jsonschema:"description=Remote to push to; default origin"`
+	Branch      string `json:"branch,omitempty" jsonschema:"description=Local branch to push to the branch of the same name; default the current branch"`
+	SetUpstream bool   `json:"set_upstream,omitempty" jsonschema:"description=Make the remote branch the upstream of the local one"`
+	Force       bool   `json:"force,omitempty" jsonschema:"description=Overwrite the remote branch if it has not changed since it was last fetched"`
+}
+
+type pushOutput struct {
+	Remote string `json:"remote"`
+	Branch string `json:"branch"`
+	Output string `json:"output,omitempty"`
+}
+
+func (ts *toolSet) push(ctx context.Context, in pushInput) (pushOutput, error) {
+	dir, err := ts.dir(in.Worktree)
+	if err != nil {
+		return pushOutput{}, err
+	}
+	policy := ts.config.policy
+	if !policy.AllowPush {
+		return pushOutput{}, errors.New("the policy does not allow pushes")
+	}
+	if in.Force && !policy.AllowForce {
+		return pushOutput{}, errForce
+	}
+	remote := in.Remote
+	if remote == "" {
+		remote = defaultRemote
+	}
+	if err := ts.checkRemote(ctx, dir, remote); err != nil {
+		return pushOutput{}, err
+	}
+	branch := in.Branch
+	if branch == "" {
+		if branch, err = ts.currentBranch(ctx, dir); err != nil {
+			return pushOutput{}, err
+		}
+		if branch == "" {
+			return pushOutput{}, errors.New("HEAD is detached; name the branch to push")
+		}
+	}
+	if err := ts.checkBranchName(ctx, dir, branch); err != nil {
+		return pushOutput{}, err
+	}
+	if policy.protected(branch) {
+		return pushOutput{}, fmt.Errorf("branch %q is protected and cannot be pushed", branch)
+	}
+
+	args := []string{"push", "--porcelain"}
+	if in.Force {
+		args = append(args, "--force-with-lease")
+	}
+	if in.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	ref := "refs/heads/" + branch
+	args = append(args, remote, ref+":"+ref)
+	out, err := ts.run(ctx, dir, max(ts.config.commandTimeout, defaultPushTimeout), false, args...)
+	if err != nil {
+		return pushOutput{}, err
+	}
+	return pushOutput{Remote: remote, Branch: branch, Output: strings.TrimSpace(out)}, nil
+}
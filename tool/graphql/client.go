@@ -0,0 +1,125 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody bounds how much of a failed response is quoted in errors.
+const maxErrorBody = 1024
+
+// client posts GraphQL requests over HTTP.
+type client struct {
+	endpoint       string
+	httpClient     *http.Client
+	headers        http.Header
+	headerProvider HeaderProvider
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []responseError `json:"errors"`
+}
+
+type responseError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// hasData reports whether the response carries a non-null data member.
+func (r *response) hasData() bool {
+	return len(r.Data) > 0 && string(r.Data) != "null"
+}
+
+// errorMessage joins the messages of the response errors.
+func (r *response) errorMessage() string {
+	messages := make([]string, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// post sends a request and decodes the response. GraphQL errors are
+// returned in the response, not as an error.
+func (c *client) post(ctx context.Context, req *request) (*response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for key, values := range c.headers {
+		httpReq.Header[key] = append([]string(nil), values...)
+	}
+	if c.headerProvider != nil {
+		extra, err := c.headerProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("request headers: %w", err)
+		}
+		for key, values := range extra {
+			for _, v := range values {
+				httpReq.Header.Add(key, v)
+			}
+		}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/graphql-response+json, application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	resp := &response{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(resp); err != nil || (!resp.hasData() && len(resp.Errors) == 0) {
+		// Servers following the GraphQL over HTTP specification answer
+		// errors with a GraphQL response and a 4xx status; anything else
+		// is reported with its status.
+		if len(data) > maxErrorBody {
+			data = data[:maxErrorBody]
+		}
+		return nil, fmt.Errorf("unexpected response (status %d): %s", httpResp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+// do sends a request and returns its data, failing on any GraphQL error.
+func (c *client) do(ctx context.Context, query, operationName string, variables map[string]any) (json.RawMessage, error) {
+	resp, err := c.post(ctx, &request{Query: query, OperationName: operationName, Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("graphql errors: %s", resp.errorMessage())
+	}
+	return resp.Data, nil
+}
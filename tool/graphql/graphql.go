@@ -0,0 +1,294 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package graphql provides a toolset that exposes GraphQL queries and
+// mutations as tools.
+//
+// The schema is introspected from the endpoint or loaded from an SDL
+// document. Each selected root field becomes a tool whose input schema is
+// derived from the field arguments. The selection set of the result is
+// generated up to a bounded depth, or chosen by the model among the fields
+// of the result type within the same limits. Mutations are only exposed
+// when they are allowlisted, so a tool set is read-only by default.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	// defaultToolSetName is the default name for the GraphQL tool set.
+	defaultToolSetName = "graphql"
+	// defaultTimeout is the default timeout of the HTTP client.
+	defaultTimeout = 30 * time.Second
+	// defaultMaxDepth is the default depth of selection sets.
+	defaultMaxDepth = 2
+	// defaultMaxFields is the default number of fields in a selection set.
+	defaultMaxFields = 50
+)
+
+// HeaderProvider returns headers for a request, such as per-request
+// authentication tokens.
+type HeaderProvider func(ctx context.Context) (http.Header, error)
+
+// Option is a functional option for configuring the GraphQL tool set.
+type Option func(*config)
+
+// config holds the configuration for the GraphQL tool set.
+type config struct {
+	name           string
+	endpoint       string
+	httpClient     *http.Client
+	headers        http.Header
+	headerProvider HeaderProvider
+
+	sdl     string
+	sdlFile string
+
+	queries   []string
+	mutations []string
+
+	maxDepth       int
+	maxFields      int
+	fieldSelection bool
+}
+
+// WithName sets the name of the tool set.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithEndpoint sets the URL of the GraphQL endpoint. Without an SDL
+// document the schema is introspected from it.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. The default client
+// has a 30 second timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header sent with every request, including
+// introspection.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithHeaderProvider sets a function that returns headers for each request,
+// sent in addition to WithHeader.
+func WithHeaderProvider(provider HeaderProvider) Option {
+	return func(c *config) {
+		c.headerProvider = provider
+	}
+}
+
+// WithSDL loads the schema from an SDL document instead of introspecting
+// the endpoint.
+func WithSDL(sdl string) Option {
+	return func(c *config) {
+		c.sdl = sdl
+	}
+}
+
+// WithSDLFile loads the schema from an SDL file instead of introspecting
+// the endpoint.
+func WithSDLFile(path string) Option {
+	return func(c *config) {
+		c.sdlFile = path
+	}
+}
+
+// WithQueries limits the query tools to the named query fields. By default
+// every query field is exposed.
+func WithQueries(names ...string) Option {
+	return func(c *config) {
+		c.queries = append(c.queries, names...)
+	}
+}
+
+// WithMutations allowlists mutation fields to expose as tools. Mutations
+// are not exposed unless named here.
+func WithMutations(names ...string) Option {
+	return func(c *config) {
+		c.mutations = append(c.mutations, names...)
+	}
+}
+
+// WithMaxDepth sets how many levels of nested objects a selection set
+// reaches below the root field. The default is 2.
+func WithMaxDepth(depth int) Option {
+	return func(c *config) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMaxFields sets the maximum number of leaf fields in a selection set.
+// The default is 50.
+func WithMaxFields(n int) Option {
+	return func(c *config) {
+		c.maxFields = n
+	}
+}
+
+// WithFieldSelection lets the model choose the fields of the result with
+// an optional "_fields" argument listing dot-separated paths such as
+// "author.name". Paths are checked against the schema and the depth and
+// field limits; without the argument the generated selection set is used.
+func WithFieldSelection(enabled bool) Option {
+	return func(c *config) {
+		c.fieldSelection = enabled
+	}
+}
+
+// toolSet is a set of GraphQL operation tools.
+type toolSet struct {
+	config *config
+	client *client
+	schema *schema
+	tools  []tool.Tool
+}
+
+// NewToolSet creates a GraphQL tool set with the provided options.
+func NewToolSet(ctx context.Context, opts ...Option) (tool.ToolSet, error) {
+	c := &config{
+		name:       defaultToolSetName,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		headers:    make(http.Header),
+		maxDepth:   defaultMaxDepth,
+		maxFields:  defaultMaxFields,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.endpoint == "" {
+		return nil, errors.New("graphql tool set: no endpoint configured")
+	}
+	if c.sdl != "" && c.sdlFile != "" {
+		return nil, errors.New("graphql tool set: use either an SDL document or an SDL file, not both")
+	}
+	if c.maxDepth < 1 || c.maxFields < 1 {
+		return nil, errors.New("graphql tool set: max depth and max fields must be positive")
+	}
+
+	ts := &toolSet{
+		config: c,
+		client: &client{
+			endpoint:       c.endpoint,
+			httpClient:     c.httpClient,
+			headers:        c.headers,
+			headerProvider: c.headerProvider,
+		},
+	}
+	s, err := ts.loadSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.index(); err != nil {
+		return nil, fmt.Errorf("graphql tool set: %w", err)
+	}
+	ts.schema = s
+
+	queries, err := selectFields(s.rootFields(false), c.queries, true, "query")
+	if err != nil {
+		return nil, err
+	}
+	mutations, err := selectFields(s.rootFields(true), c.mutations, false, "mutation")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range queries {
+		ts.tools = append(ts.tools, newOperationTool(ts, operationQuery, f))
+	}
+	for _, f := range mutations {
+		ts.tools = append(ts.tools, newOperationTool(ts, operationMutation, f))
+	}
+	return ts, nil
+}
+
+// Tools implements the ToolSet interface.
+func (ts *toolSet) Tools(ctx context.Context) []tool.Tool {
+	return ts.tools
+}
+
+// Close implements the ToolSet interface.
+func (ts *toolSet) Close() error {
+	return nil
+}
+
+// Name implements the ToolSet interface.
+func (ts *toolSet) Name() string {
+	return ts.config.name
+}
+
+func (ts *toolSet) loadSchema(ctx context.Context) (*schema, error) {
+	sdl := ts.config.sdl
+	if ts.config.sdlFile != "" {
+		content, err := os.ReadFile(ts.config.sdlFile)
+		if err != nil {
+			return nil, fmt.Errorf("graphql tool set: read SDL file: %w", err)
+		}
+		sdl = string(content)
+	}
+	if sdl != "" {
+		s, err := parseSDL(sdl)
+		if err != nil {
+			return nil, fmt.Errorf("graphql tool set: %w", err)
+		}
+		return s, nil
+	}
+	s, err := introspect(ctx, ts.client)
+	if err != nil {
+		return nil, fmt.Errorf("graphql tool set: introspect schema: %w", err)
+	}
+	return s, nil
+}
+
+// selectFields returns the named root fields in schema order, or all of
+// them when none are named and all is set. Deprecated fields are only
+// returned when named.
+func selectFields(fields []*field, names []string, all bool, kind string) ([]*field, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var selected []*field
+	for _, f := range fields {
+		if wanted[f.Name] || (len(names) == 0 && all && !f.IsDeprecated) {
+			selected = append(selected, f)
+			delete(wanted, f.Name)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for name := range wanted {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("graphql tool set: %s %s not found", kind, missing[0])
+	}
+	return selected, nil
+}
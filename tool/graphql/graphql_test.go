@@ -0,0 +1,313 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const librarySDL = `"""
+Library schema.
+"""
+schema { query: RootQuery mutation: Mutation }
+
+directive @auth(role: String = "reader") repeatable on FIELD_DEFINITION | OBJECT
+
+scalar DateTime @specifiedBy(url: "https://example.com/datetime")
+
+type RootQuery {
+  "Look up a book."
+  book(id: ID!): Book
+  books(filter: BookFilter, first: Int = 10): [Book!]!
+  oldBooks: [Book] @deprecated(reason: "use books")
+}
+
+extend type RootQuery {
+  search(text: String!): [SearchResult!]!
+}
+
+"""
+  A book
+  in the library.
+"""
+type Book implements Node & Named @auth {
+  id: ID!
+  title: String!
+  genre: Genre
+  published: DateTime
+  author: Author
+  # Fields with required arguments are not selected by default.
+  reviews(first: Int!): [Review]
+  legacyCode: String @deprecated
+}
+
+type Author implements Node {
+  id: ID!
+  name: String
+  books: [Book!]
+}
+
+type Review { stars: Int }
+
+interface Node { id: ID! }
+
+interface Named { title: String! }
+
+union SearchResult = | Book | Author
+
+enum Genre { FICTION SCIENCE OLD @deprecated }
+
+input BookFilter {
+  genre: Genre
+  "Title substring."
+  title: String
+  page: Page = {sort: "title", limit: 5}
+  and: [BookFilter!]
+}
+
+input Page { sort: String, limit: Int }
+
+type Mutation {
+  addBook(title: String!, genre: Genre = FICTION): Book
+  deleteBook(id: ID!): Boolean!
+}
+`
+
+// graphQLServer answers introspection from librarySDL and other requests
+// with respond, recording them.
+type graphQLServer struct {
+	*httptest.Server
+	requests []request
+	headers  []http.Header
+	respond  func(req request) string
+}
+
+func newGraphQLServer(t *testing.T) *graphQLServer {
+	t.Helper()
+	s, err := parseSDL(librarySDL)
+	require.NoError(t, err)
+	introspection, err := json.Marshal(map[string]any{"data": map[string]any{"__schema": s}})
+	require.NoError(t, err)
+
+	srv := &graphQLServer{
+		respond: func(request) string { return `{"data":{"book":{"id":"b1","title":"Dune"}}}` },
+	}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "__schema") {
+			w.Write(introspection)
+			return
+		}
+		srv.requests = append(srv.requests, req)
+		srv.headers = append(srv.headers, r.Header.Clone())
+		w.Write([]byte(srv.respond(req)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func toolByName(t *testing.T, ts tool.ToolSet, name string) tool.CallableTool {
+	t.Helper()
+	for _, tl := range ts.Tools(context.Background()) {
+		if tl.Declaration().Name == name {
+			return tl.(tool.CallableTool)
+		}
+	}
+	require.Failf(t, "tool not found", name)
+	return nil
+}
+
+func toolNames(ts tool.ToolSet) []string {
+	var names []string
+	for _, tl := range ts.Tools(context.Background()) {
+		names = append(names, tl.Declaration().Name)
+	}
+	return names
+}
+
+func TestToolSet_SDL(t *testing.T) {
+	srv := newGraphQLServer(t)
+	ts, err := NewToolSet(context.Background(),
+		WithEndpoint(srv.URL),
+		WithSDL(librarySDL),
+		WithHeader("Authorization", "Bearer t"),
+		WithHeaderProvider(func(context.Context) (http.Header, error) {
+			return http.Header{"X-Tenant": []string{"acme"}}, nil
+		}),
+	)
+	require.NoError(t, err)
+	defer ts.Close()
+	// Deprecated queries and all mutations are left out.
+	assert.Equal(t, []string{"query_book", "query_books", "query_search"}, toolNames(ts))
+
+	book := toolByName(t, ts, "query_book")
+	assert.Equal(t, "Look up a book.", book.Declaration().Description)
+	assert.Equal(t, []string{"id"}, book.Declaration().InputSchema.Required)
+
+	result, err := book.Call(context.Background(), []byte(`{"id":"b1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "b1", "title": "Dune"}, result)
+	require.Len(t, srv.requests, 1)
+	assert.Equal(t, "query query_book($id: ID!) { book(id: $id) "+
+		"{ id title genre published author { id name } } }", srv.requests[0].Query)
+	assert.Equal(t, "query_book", srv.requests[0].OperationName)
+	assert.Equal(t, map[string]any{"id": "b1"}, srv.requests[0].Variables)
+	assert.Equal(t, "Bearer t", srv.headers[0].Get("Authorization"))
+	assert.Equal(t, "acme", srv.headers[0].Get("X-Tenant"))
+
+	search := toolByName(t, ts, "query_search").(*operationTool)
+	assert.Equal(t, "{ __typename "+
+		"... on Book { id title genre published author { id name } } "+
+		"... on Author { id name books { id title genre published } } }", search.selection)
+
+	// Omitted optional arguments are not declared.
+	_, err = toolByName(t, ts, "query_books").Call(context.Background(), []byte(`{"filter":{"title":"D"}}`))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(srv.requests[1].Query,
+		"query query_books($filter: BookFilter) { books(filter: $filter) { id"))
+
+	_, err = book.Call(context.Background(), []byte(`{}`))
+	require.ErrorContains(t, err, "missing required argument id")
+	_, err = book.Call(context.Background(), []byte(`{"id":"b1","isbn":"978"}`))
+	require.ErrorContains(t, err, "unknown argument isbn")
+}
+
+func TestToolSet_Introspection(t *testing.T) {
+	srv := newGraphQLServer(t)
+	srv.respond = func(request) string {
+		return `{"data":{"addBook":{"id":"b2","title":"Emma"}}}`
+	}
+	ts, err := NewToolSet(context.Background(),
+		WithEndpoint(srv.URL),
+		WithQueries("book"),
+		WithMutations("addBook"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query_book", "mutation_addBook"}, toolNames(ts))
+
+	addBook := toolByName(t, ts, "mutation_addBook")
+	assert.Equal(t, "Runs the GraphQL mutation addBook.", addBook.Declaration().Description)
+	result, err := addBook.Call(context.Background(), []byte(`{"title":"Emma","genre":"FICTION"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "b2", "title": "Emma"}, result)
+	assert.True(t, strings.HasPrefix(srv.requests[0].Query,
+		"mutation mutation_addBook($title: String!, $genre: Genre) { addBook(title: $title, genre: $genre) {"))
+
+	_, err = NewToolSet(context.Background(), WithEndpoint(srv.URL), WithMutations("dropTables"))
+	require.ErrorContains(t, err, "mutation dropTables not found")
+	_, err = NewToolSet(context.Background(), WithEndpoint(srv.URL), WithQueries("missing"))
+	require.ErrorContains(t, err, "query missing not found")
+	_, err = NewToolSet(context.Background(), WithSDL(librarySDL))
+	require.ErrorContains(t, err, "no endpoint")
+	_, err = NewToolSet(context.Background(), WithEndpoint(srv.URL), WithMaxDepth(0))
+	require.ErrorContains(t, err, "must be positive")
+}
+
+func TestToolSet_FieldSelection(t *testing.T) {
+	srv := newGraphQLServer(t)
+	ts, err := NewToolSet(context.Background(),
+		WithEndpoint(srv.URL),
+		WithSDL(librarySDL),
+		WithFieldSelection(true),
+		WithMaxFields(3),
+	)
+	require.NoError(t, err)
+	book := toolByName(t, ts, "query_book")
+	fields := book.Declaration().InputSchema.Properties[fieldsArgument]
+	require.NotNil(t, fields)
+	assert.Contains(t, fields.Description, "Fields of Book: id, title, genre, published, author.")
+	assert.NotContains(t, toolByName(t, ts, "query_search").Declaration().InputSchema.Properties, fieldsArgument)
+
+	_, err = book.Call(context.Background(), []byte(`{"id":"b1","_fields":["title","author","author.name"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "query query_book($id: ID!) { book(id: $id) { title author { name } } }", srv.requests[0].Query)
+	_, err = book.Call(context.Background(), []byte(`{"id":"b1","_fields":["title","author"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "query query_book($id: ID!) { book(id: $id) { title author { id name } } }", srv.requests[1].Query)
+
+	for args, want := range map[string]string{
+		`{"id":"b1","_fields":["author.books.id"]}`:                "deeper than 2 levels",
+		`{"id":"b1","_fields":["reviews"]}`:                        "requires arguments",
+		`{"id":"b1","_fields":["isbn"]}`:                           "Book has no field isbn",
+		`{"id":"b1","_fields":["title.size"]}`:                     "String has no fields",
+		`{"id":"b1","_fields":["id","title","genre","published"]}`: "more than 3 fields",
+		`{"id":"b1","_fields":"title"}`:                            "array of strings",
+		`{"id":"b1","_fields":[]}`:                                 "no field paths",
+	} {
+		_, err := book.Call(context.Background(), []byte(args))
+		assert.ErrorContains(t, err, want, args)
+	}
+}
+
+func TestToolSet_GraphQLErrors(t *testing.T) {
+	srv := newGraphQLServer(t)
+	ts, err := NewToolSet(context.Background(), WithEndpoint(srv.URL), WithSDL(librarySDL))
+	require.NoError(t, err)
+	book := toolByName(t, ts, "query_book")
+
+	srv.respond = func(request) string { return `{"errors":[{"message":"not authorized"}]}` }
+	_, err = book.Call(context.Background(), []byte(`{"id":"b1"}`))
+	require.ErrorContains(t, err, "not authorized")
+
+	srv.respond = func(request) string {
+		return `{"data":{"book":{"id":"b1","author":null}},"errors":[{"message":"author unavailable","path":["book","author"]}]}`
+	}
+	result, err := book.Call(context.Background(), []byte(`{"id":"b1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "b1", "author": nil}, result.(map[string]any)["data"])
+	assert.Equal(t, "author unavailable", result.(map[string]any)["errors"].([]responseError)[0].Message)
+
+	srv.respond = func(request) string { return `<html>bad gateway</html>` }
+	_, err = book.Call(context.Background(), []byte(`{"id":"b1"}`))
+	require.ErrorContains(t, err, "unexpected response (status 200)")
+}
+
+func TestArgumentsSchema(t *testing.T) {
+	s, err := parseSDL(librarySDL)
+	require.NoError(t, err)
+	require.NoError(t, s.index())
+	books := findField(s.byName["RootQuery"], "books")
+	out := s.argumentsSchema(books.Args)
+
+	assert.Equal(t, "object", out.Type)
+	assert.Empty(t, out.Required)
+	assert.Equal(t, "integer", out.Properties["first"].Type)
+	assert.Equal(t, "Defaults to 10.", out.Properties["first"].Description)
+
+	filter := out.Properties["filter"]
+	assert.Equal(t, "object", filter.Type)
+	assert.Equal(t, []any{"FICTION", "SCIENCE"}, filter.Properties["genre"].Enum)
+	assert.Equal(t, "Title substring.", filter.Properties["title"].Description)
+	assert.Equal(t, `Defaults to {sort: "title", limit: 5}.`, filter.Properties["page"].Description)
+	assert.Equal(t, "array", filter.Properties["and"].Type)
+	assert.Equal(t, "#/$defs/BookFilter", filter.Properties["and"].Items.Ref)
+	require.Contains(t, out.Defs, "BookFilter")
+	assert.Equal(t, "#/$defs/BookFilter", out.Defs["BookFilter"].Properties["and"].Items.Ref)
+
+	addBook := findField(s.byName["Mutation"], "addBook")
+	assert.Equal(t, []string{"title"}, s.argumentsSchema(addBook.Args).Required)
+	published := &fullType{Kind: kindScalar, Name: "DateTime"}
+	assert.Equal(t, "Custom scalar DateTime.", scalarSchema(published).Description)
+}
@@ -0,0 +1,133 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const defsPrefix = "#/$defs/"
+
+// argumentsSchema returns the JSON schema of the variables for a field's
+// arguments. Non-null arguments without a default are required, and
+// recursive input objects are referenced from $defs.
+func (s *schema) argumentsSchema(args []*inputValue) *tool.Schema {
+	b := &inputSchemaBuilder{
+		schema:   s,
+		defs:     make(map[string]*tool.Schema),
+		building: make(map[string]bool),
+	}
+	out := b.values(args, "")
+	if len(b.defs) > 0 {
+		out.Defs = b.defs
+	}
+	return out
+}
+
+type inputSchemaBuilder struct {
+	schema   *schema
+	defs     map[string]*tool.Schema
+	building map[string]bool
+}
+
+func (b *inputSchemaBuilder) values(values []*inputValue, description string) *tool.Schema {
+	s := &tool.Schema{
+		Type:        "object",
+		Description: description,
+		Properties:  make(map[string]*tool.Schema),
+	}
+	for _, v := range values {
+		p := b.ref(v.Type)
+		if p.Ref == "" {
+			p.Description = joinSentences(v.Description, p.Description)
+			if v.DefaultValue != nil {
+				p.Description = joinSentences(p.Description, "Defaults to "+*v.DefaultValue+".")
+			}
+		}
+		s.Properties[v.Name] = p
+		if v.required() {
+			s.Required = append(s.Required, v.Name)
+		}
+	}
+	return s
+}
+
+func (b *inputSchemaBuilder) ref(t *typeRef) *tool.Schema {
+	switch t.Kind {
+	case kindNonNull:
+		// Nullability is expressed by the required list of the parent.
+		return b.ref(t.OfType)
+	case kindList:
+		return &tool.Schema{Type: "array", Items: b.ref(t.OfType)}
+	}
+	named := b.schema.byName[t.Name]
+	if named == nil {
+		return &tool.Schema{}
+	}
+	switch named.Kind {
+	case kindEnum:
+		s := &tool.Schema{Type: "string", Description: named.Description}
+		for _, v := range named.EnumValues {
+			if !v.IsDeprecated {
+				s.Enum = append(s.Enum, v.Name)
+			}
+		}
+		return s
+	case kindInputObject:
+		return b.inputObject(named)
+	default:
+		return scalarSchema(named)
+	}
+}
+
+func (b *inputSchemaBuilder) inputObject(t *fullType) *tool.Schema {
+	if b.building[t.Name] {
+		if _, ok := b.defs[t.Name]; !ok {
+			// Reserve the name first: building the definition meets the
+			// recursion again.
+			def := &tool.Schema{}
+			b.defs[t.Name] = def
+			*def = *b.values(t.InputFields, t.Description)
+		}
+		return &tool.Schema{Ref: defsPrefix + t.Name}
+	}
+	b.building[t.Name] = true
+	defer delete(b.building, t.Name)
+	return b.values(t.InputFields, t.Description)
+}
+
+func scalarSchema(t *fullType) *tool.Schema {
+	switch t.Name {
+	case "Int":
+		return &tool.Schema{Type: "integer"}
+	case "Float":
+		return &tool.Schema{Type: "number"}
+	case "String":
+		return &tool.Schema{Type: "string"}
+	case "Boolean":
+		return &tool.Schema{Type: "boolean"}
+	case "ID":
+		return &tool.Schema{Type: "string", Description: "Unique identifier."}
+	default:
+		// Custom scalars accept whatever the server coerces.
+		return &tool.Schema{Description: joinSentences("Custom scalar "+t.Name+".", t.Description)}
+	}
+}
+
+func joinSentences(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + " " + b
+	}
+}
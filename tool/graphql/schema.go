@@ -0,0 +1,206 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Type kinds, as reported by introspection.
+const (
+	kindScalar      = "SCALAR"
+	kindObject      = "OBJECT"
+	kindInterface   = "INTERFACE"
+	kindUnion       = "UNION"
+	kindEnum        = "ENUM"
+	kindInputObject = "INPUT_OBJECT"
+	kindList        = "LIST"
+	kindNonNull     = "NON_NULL"
+)
+
+// introspectionQuery fetches everything the tool set needs. Type references
+// are unwrapped seven levels deep, enough for types such as [[T!]!]!.
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types {
+      kind name description
+      fields(includeDeprecated: true) {
+        name description isDeprecated
+        args { name description defaultValue type { ...TypeRef } }
+        type { ...TypeRef }
+      }
+      inputFields { name description defaultValue type { ...TypeRef } }
+      enumValues(includeDeprecated: true) { name description isDeprecated }
+      possibleTypes { kind name }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// schema is a GraphQL schema in the shape of the introspection result, so
+// introspection responses decode into it directly and SDL files are parsed
+// into the same shape.
+type schema struct {
+	QueryType    *namedType  `json:"queryType"`
+	MutationType *namedType  `json:"mutationType"`
+	Types        []*fullType `json:"types"`
+
+	byName map[string]*fullType
+}
+
+type namedType struct {
+	Name string `json:"name"`
+}
+
+type fullType struct {
+	Kind          string        `json:"kind"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	Fields        []*field      `json:"fields,omitempty"`
+	InputFields   []*inputValue `json:"inputFields,omitempty"`
+	EnumValues    []*enumValue  `json:"enumValues,omitempty"`
+	PossibleTypes []*typeRef    `json:"possibleTypes,omitempty"`
+}
+
+type field struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Args         []*inputValue `json:"args"`
+	Type         *typeRef      `json:"type"`
+	IsDeprecated bool          `json:"isDeprecated"`
+}
+
+type inputValue struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Type         *typeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+type enumValue struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	IsDeprecated bool   `json:"isDeprecated"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name,omitempty"`
+	OfType *typeRef `json:"ofType,omitempty"`
+}
+
+// named returns the name of the innermost named type.
+func (t *typeRef) named() string {
+	for t != nil && t.Name == "" {
+		t = t.OfType
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// String renders the reference in GraphQL syntax, such as [String!]!.
+func (t *typeRef) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.Kind == kindNonNull:
+		return t.OfType.String() + "!"
+	case t.Kind == kindList:
+		return "[" + t.OfType.String() + "]"
+	default:
+		return t.Name
+	}
+}
+
+// required reports whether a value must be provided for an argument or
+// input field.
+func (v *inputValue) required() bool {
+	return v.Type != nil && v.Type.Kind == kindNonNull && v.DefaultValue == nil
+}
+
+// index builds the type lookup, adds the built-in scalars and checks the
+// root types.
+func (s *schema) index() error {
+	s.byName = make(map[string]*fullType, len(s.Types))
+	for _, t := range s.Types {
+		s.byName[t.Name] = t
+	}
+	for _, name := range []string{"Int", "Float", "String", "Boolean", "ID"} {
+		if _, ok := s.byName[name]; !ok {
+			t := &fullType{Kind: kindScalar, Name: name}
+			s.Types = append(s.Types, t)
+			s.byName[name] = t
+		}
+	}
+	if s.QueryType == nil || s.byName[s.QueryType.Name] == nil {
+		return fmt.Errorf("schema has no query type")
+	}
+	if s.MutationType != nil && s.byName[s.MutationType.Name] == nil {
+		return fmt.Errorf("mutation type %s not defined", s.MutationType.Name)
+	}
+	return nil
+}
+
+func (s *schema) typeOf(ref *typeRef) *fullType {
+	return s.byName[ref.named()]
+}
+
+func (s *schema) rootFields(mutation bool) []*field {
+	root := s.QueryType
+	if mutation {
+		root = s.MutationType
+	}
+	if root == nil {
+		return nil
+	}
+	return s.byName[root.Name].Fields
+}
+
+func isLeaf(t *fullType) bool {
+	return t != nil && (t.Kind == kindScalar || t.Kind == kindEnum)
+}
+
+// introspect loads the schema of an endpoint with the introspection query.
+func introspect(ctx context.Context, c *client) (*schema, error) {
+	data, err := c.do(ctx, introspectionQuery, "IntrospectionQuery", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Schema *schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("decode introspection result: %w", err)
+	}
+	if result.Schema == nil {
+		return nil, fmt.Errorf("introspection returned no schema")
+	}
+	// Introspection lists its own types; they are never exposed as tools.
+	types := result.Schema.Types[:0]
+	for _, t := range result.Schema.Types {
+		if !strings.HasPrefix(t.Name, "__") {
+			types = append(types, t)
+		}
+	}
+	result.Schema.Types = types
+	return result.Schema, nil
+}
@@ -0,0 +1,592 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// parseSDL parses a schema definition document. Directive definitions and
+// directives are accepted and ignored, except @deprecated on fields and
+// enum values.
+func parseSDL(src string) (*schema, error) {
+	p := &sdlParser{lex: lexer{src: src}}
+	p.next()
+	s, err := p.document()
+	if err != nil {
+		return nil, fmt.Errorf("parse SDL: line %d: %w", p.tok.line, err)
+	}
+	return s, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	err  error
+}
+
+func (l *lexer) next() token {
+	l.skipIgnored()
+	line := l.line + 1
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, line: line}
+	}
+	c := l.src[l.pos]
+	switch {
+	case c == '"':
+		s, err := l.string()
+		if err != nil {
+			l.err = err
+			return token{kind: tokEOF, line: line}
+		}
+		return token{kind: tokString, value: s, line: line}
+	case c == '.' && strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, value: "...", line: line}
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, value: string(c), line: line}
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && strings.IndexByte("0123456789.eE+-", l.src[l.pos]) >= 0 {
+			l.pos++
+		}
+		return token{kind: tokNumber, value: l.src[start:l.pos], line: line}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isAlnum(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokName, value: l.src[start:l.pos], line: line}
+	default:
+		l.err = fmt.Errorf("unexpected character %q", c)
+		return token{kind: tokEOF, line: line}
+	}
+}
+
+func isAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// skipIgnored skips white space, commas and comments.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) string() (string, error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated block string")
+		}
+		raw := l.src[l.pos+3 : l.pos+3+end]
+		l.line += strings.Count(raw, "\n")
+		l.pos += end + 6
+		return blockStringValue(raw), nil
+	}
+	var b strings.Builder
+	for i := l.pos + 1; i < len(l.src); i++ {
+		switch c := l.src[i]; c {
+		case '"':
+			l.pos = i + 1
+			return b.String(), nil
+		case '\n':
+			return "", fmt.Errorf("unterminated string")
+		case '\\':
+			i++
+			if i >= len(l.src) {
+				return "", fmt.Errorf("unterminated string")
+			}
+			switch e := l.src[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// blockStringValue removes the common indentation and surrounding blank
+// lines of a block string.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+type sdlParser struct {
+	lex lexer
+	tok token
+}
+
+func (p *sdlParser) next() {
+	p.tok = p.lex.next()
+}
+
+func (p *sdlParser) is(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *sdlParser) skip(kind tokenKind, value string) bool {
+	if p.is(kind, value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *sdlParser) expect(value string) error {
+	if !p.skip(tokPunct, value) {
+		return p.unexpected("%q", value)
+	}
+	return nil
+}
+
+func (p *sdlParser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.unexpected("a name")
+	}
+	name := p.tok.value
+	p.next()
+	return name, nil
+}
+
+func (p *sdlParser) unexpected(format string, args ...any) error {
+	if p.lex.err != nil {
+		return p.lex.err
+	}
+	got := p.tok.value
+	if p.tok.kind == tokEOF {
+		got = "end of input"
+	}
+	return fmt.Errorf("expected %s, got %q", fmt.Sprintf(format, args...), got)
+}
+
+func (p *sdlParser) description() string {
+	if p.tok.kind != tokString {
+		return ""
+	}
+	d := p.tok.value
+	p.next()
+	return d
+}
+
+func (p *sdlParser) document() (*schema, error) {
+	s := &schema{}
+	types := make(map[string]*fullType)
+	var order []string
+	define := func(t *fullType, extend bool) error {
+		existing, ok := types[t.Name]
+		switch {
+		case !ok:
+			types[t.Name] = t
+			order = append(order, t.Name)
+		case extend || existing.Kind == t.Kind:
+			existing.Fields = append(existing.Fields, t.Fields...)
+			existing.InputFields = append(existing.InputFields, t.InputFields...)
+			existing.EnumValues = append(existing.EnumValues, t.EnumValues...)
+			existing.PossibleTypes = append(existing.PossibleTypes, t.PossibleTypes...)
+		default:
+			return fmt.Errorf("type %s defined twice", t.Name)
+		}
+		return nil
+	}
+	for p.tok.kind != tokEOF {
+		desc := p.description()
+		extend := p.skip(tokName, "extend")
+		if p.tok.kind != tokName {
+			return nil, p.unexpected("a definition")
+		}
+		keyword := p.tok.value
+		p.next()
+		switch keyword {
+		case "schema":
+			if err := p.schemaDefinition(s); err != nil {
+				return nil, err
+			}
+		case "directive":
+			if err := p.directiveDefinition(); err != nil {
+				return nil, err
+			}
+		case "scalar", "type", "interface", "union", "enum", "input":
+			t, err := p.typeDefinition(keyword)
+			if err != nil {
+				return nil, err
+			}
+			t.Description = desc
+			if err := define(t, extend); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported definition %q", keyword)
+		}
+	}
+	if p.lex.err != nil {
+		return nil, p.lex.err
+	}
+	for _, name := range order {
+		s.Types = append(s.Types, types[name])
+	}
+	if s.QueryType == nil && types["Query"] != nil {
+		s.QueryType = &namedType{Name: "Query"}
+	}
+	if s.MutationType == nil && types["Mutation"] != nil {
+		s.MutationType = &namedType{Name: "Mutation"}
+	}
+	return s, nil
+}
+
+func (p *sdlParser) schemaDefinition(s *schema) error {
+	if _, err := p.directives(); err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.skip(tokPunct, "}") {
+		op, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "query":
+			s.QueryType = &namedType{Name: name}
+		case "mutation":
+			s.MutationType = &namedType{Name: name}
+		}
+	}
+	return nil
+}
+
+func (p *sdlParser) directiveDefinition() error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.is(tokPunct, "(") {
+		if _, err := p.inputValues("(", ")"); err != nil {
+			return err
+		}
+	}
+	p.skip(tokName, "repeatable")
+	if !p.skip(tokName, "on") {
+		return p.unexpected("%q", "on")
+	}
+	p.skip(tokPunct, "|")
+	for {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if !p.skip(tokPunct, "|") {
+			return nil
+		}
+	}
+}
+
+func (p *sdlParser) typeDefinition(keyword string) (*fullType, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	t := &fullType{Name: name}
+	switch keyword {
+	case "scalar":
+		t.Kind = kindScalar
+		_, err = p.directives()
+	case "type", "interface":
+		t.Kind = kindObject
+		if keyword == "interface" {
+			t.Kind = kindInterface
+		}
+		if p.skip(tokName, "implements") {
+			p.skip(tokPunct, "&")
+			for p.tok.kind == tokName {
+				p.next()
+				if !p.skip(tokPunct, "&") {
+					break
+				}
+			}
+		}
+		if _, err = p.directives(); err == nil && p.is(tokPunct, "{") {
+			t.Fields, err = p.fields()
+		}
+	case "union":
+		t.Kind = kindUnion
+		if _, err = p.directives(); err == nil && p.skip(tokPunct, "=") {
+			p.skip(tokPunct, "|")
+			for {
+				var member string
+				if member, err = p.name(); err != nil {
+					break
+				}
+				t.PossibleTypes = append(t.PossibleTypes, &typeRef{Kind: kindObject, Name: member})
+				if !p.skip(tokPunct, "|") {
+					break
+				}
+			}
+		}
+	case "enum":
+		t.Kind = kindEnum
+		if _, err = p.directives(); err == nil && p.is(tokPunct, "{") {
+			t.EnumValues, err = p.enumValues()
+		}
+	case "input":
+		t.Kind = kindInputObject
+		if _, err = p.directives(); err == nil && p.is(tokPunct, "{") {
+			t.InputFields, err = p.inputValues("{", "}")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *sdlParser) fields() ([]*field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []*field
+	for !p.skip(tokPunct, "}") {
+		f := &field{Description: p.description(), Args: []*inputValue{}}
+		var err error
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if p.is(tokPunct, "(") {
+			if f.Args, err = p.inputValues("(", ")"); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if f.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if f.IsDeprecated, err = p.directives(); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (p *sdlParser) inputValues(open, closing string) ([]*inputValue, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+	values := []*inputValue{}
+	for !p.skip(tokPunct, closing) {
+		v := &inputValue{Description: p.description()}
+		var err error
+		if v.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if v.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if p.skip(tokPunct, "=") {
+			def, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			v.DefaultValue = &def
+		}
+		if _, err := p.directives(); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func (p *sdlParser) enumValues() ([]*enumValue, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var values []*enumValue
+	for !p.skip(tokPunct, "}") {
+		v := &enumValue{Description: p.description()}
+		var err error
+		if v.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if v.IsDeprecated, err = p.directives(); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func (p *sdlParser) typeRef() (*typeRef, error) {
+	var t *typeRef
+	if p.skip(tokPunct, "[") {
+		inner, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		t = &typeRef{Kind: kindList, OfType: inner}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		// The kind of a named reference is not known while parsing and is
+		// not needed: lookups go through the type name.
+		t = &typeRef{Name: name}
+	}
+	if p.skip(tokPunct, "!") {
+		t = &typeRef{Kind: kindNonNull, OfType: t}
+	}
+	return t, nil
+}
+
+// directives skips directives and reports whether @deprecated was among
+// them.
+func (p *sdlParser) directives() (bool, error) {
+	deprecated := false
+	for p.skip(tokPunct, "@") {
+		name, err := p.name()
+		if err != nil {
+			return false, err
+		}
+		if name == "deprecated" {
+			deprecated = true
+		}
+		if p.skip(tokPunct, "(") {
+			for !p.skip(tokPunct, ")") {
+				if _, err := p.name(); err != nil {
+					return false, err
+				}
+				if err := p.expect(":"); err != nil {
+					return false, err
+				}
+				if _, err := p.value(); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+	return deprecated, nil
+}
+
+// value parses a constant value and returns it in GraphQL syntax.
+func (p *sdlParser) value() (string, error) {
+	switch {
+	case p.tok.kind == tokString:
+		v := fmt.Sprintf("%q", p.tok.value)
+		p.next()
+		return v, nil
+	case p.tok.kind == tokNumber || p.tok.kind == tokName:
+		v := p.tok.value
+		p.next()
+		return v, nil
+	case p.skip(tokPunct, "["):
+		var items []string
+		for !p.skip(tokPunct, "]") {
+			item, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case p.skip(tokPunct, "{"):
+		var items []string
+		for !p.skip(tokPunct, "}") {
+			name, err := p.name()
+			if err != nil {
+				return "", err
+			}
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+			item, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, name+": "+item)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	default:
+		return "", p.unexpected("a value")
+	}
+}
@@ -0,0 +1,72 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSDL(t *testing.T) {
+	s, err := parseSDL(librarySDL)
+	require.NoError(t, err)
+	require.NoError(t, s.index())
+
+	assert.Equal(t, "RootQuery", s.QueryType.Name)
+	assert.Equal(t, "Mutation", s.MutationType.Name)
+
+	query := s.byName["RootQuery"]
+	require.NotNil(t, query)
+	// extend type appends fields.
+	require.Len(t, query.Fields, 4)
+	book := query.Fields[0]
+	assert.Equal(t, "book", book.Name)
+	assert.Equal(t, "Look up a book.", book.Description)
+	assert.Equal(t, "Book", book.Type.String())
+	require.Len(t, book.Args, 1)
+	assert.Equal(t, "ID!", book.Args[0].Type.String())
+	assert.True(t, book.Args[0].required())
+
+	books := query.Fields[1]
+	assert.Equal(t, "[Book!]!", books.Type.String())
+	require.NotNil(t, books.Args[1].DefaultValue)
+	assert.Equal(t, "10", *books.Args[1].DefaultValue)
+	assert.False(t, books.Args[1].required())
+	assert.True(t, query.Fields[2].IsDeprecated)
+
+	assert.Equal(t, "A book\nin the library.", s.byName["Book"].Description)
+	assert.Equal(t, kindUnion, s.byName["SearchResult"].Kind)
+	assert.Len(t, s.byName["SearchResult"].PossibleTypes, 2)
+	assert.Equal(t, kindEnum, s.byName["Genre"].Kind)
+	assert.True(t, s.byName["Genre"].EnumValues[2].IsDeprecated)
+	assert.Equal(t, kindInputObject, s.byName["BookFilter"].Kind)
+	assert.Equal(t, `{sort: "title", limit: 5}`, *s.byName["BookFilter"].InputFields[2].DefaultValue)
+	assert.Equal(t, kindScalar, s.byName["Int"].Kind)
+}
+
+func TestParseSDL_Errors(t *testing.T) {
+	for _, sdl := range []string{
+		`type Query { a: }`,
+		`type Query { a: String`,
+		`type Query { a: String } enum Query { A }`,
+		`"unterminated`,
+		`query { a }`,
+		`type Query { a: String ^ }`,
+	} {
+		_, err := parseSDL(sdl)
+		assert.Error(t, err, sdl)
+	}
+
+	s, err := parseSDL(`type Book { id: ID }`)
+	require.NoError(t, err)
+	assert.ErrorContains(t, s.index(), "no query type")
+}
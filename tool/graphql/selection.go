@@ -0,0 +1,195 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const typenameField = "__typename"
+
+// selectionSet generates the selection set of a composite type: its leaf
+// fields, then its object fields while depth allows, within the field
+// budget. Deprecated fields and fields with required arguments are
+// skipped. It returns "" for leaf types and for types with nothing to
+// select.
+func (s *schema) selectionSet(t *fullType, depth int, budget *int) string {
+	var parts []string
+	switch t.Kind {
+	case kindObject, kindInterface:
+		var nested []*field
+		for _, f := range t.Fields {
+			if f.IsDeprecated || hasRequiredArgs(f) {
+				continue
+			}
+			ft := s.typeOf(f.Type)
+			switch {
+			case ft == nil:
+			case isLeaf(ft):
+				if *budget > 0 {
+					parts = append(parts, f.Name)
+					*budget--
+				}
+			default:
+				nested = append(nested, f)
+			}
+		}
+		for _, f := range nested {
+			if depth == 0 || *budget == 0 {
+				break
+			}
+			if sub := s.selectionSet(s.typeOf(f.Type), depth-1, budget); sub != "" {
+				parts = append(parts, f.Name+" "+sub)
+			}
+		}
+		if len(parts) > 0 && t.Kind == kindInterface {
+			parts = append([]string{typenameField}, parts...)
+		}
+	case kindUnion:
+		parts = append(parts, typenameField)
+		for _, member := range t.PossibleTypes {
+			if mt := s.byName[member.Name]; mt != nil {
+				if sub := s.selectionSet(mt, depth, budget); sub != "" {
+					parts = append(parts, "... on "+member.Name+" "+sub)
+				}
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(parts, " ") + " }"
+}
+
+func hasRequiredArgs(f *field) bool {
+	for _, arg := range f.Args {
+		if arg.required() {
+			return true
+		}
+	}
+	return false
+}
+
+// selectionNode is a field chosen by path, with the fields chosen below it.
+// A node without children whose type is composite selects the leaf fields
+// of that type.
+type selectionNode struct {
+	order     []string
+	children  map[string]*selectionNode
+	composite *fullType
+}
+
+func (n *selectionNode) child(name string) *selectionNode {
+	if n.children == nil {
+		n.children = make(map[string]*selectionNode)
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &selectionNode{}
+		n.children[name] = c
+		n.order = append(n.order, name)
+	}
+	return c
+}
+
+// render returns the selection set of the node, counting the leaf fields
+// against the budget.
+func (n *selectionNode) render(s *schema, budget *int) (string, error) {
+	parts := make([]string, 0, len(n.order))
+	for _, name := range n.order {
+		c := n.children[name]
+		switch {
+		case len(c.order) > 0:
+			sub, err := c.render(s, budget)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, name+" "+sub)
+		case c.composite != nil:
+			if *budget <= 0 {
+				return "", errTooManyFields
+			}
+			sub := s.selectionSet(c.composite, 0, budget)
+			if sub == "" {
+				return "", fmt.Errorf("%s has no leaf fields to select", c.composite.Name)
+			}
+			parts = append(parts, name+" "+sub)
+		default:
+			if name != typenameField {
+				*budget--
+			}
+			parts = append(parts, name)
+		}
+	}
+	if *budget < 0 {
+		return "", errTooManyFields
+	}
+	return "{ " + strings.Join(parts, " ") + " }", nil
+}
+
+var errTooManyFields = errors.New("field paths select too many fields")
+
+// pathSelection builds the selection set for dot-separated field paths
+// below the result type. A path may reach maxDepth object levels below
+// the root field; a path ending at a composite type selects its leaf
+// fields. The number of leaf fields is limited to maxFields.
+func (s *schema) pathSelection(result *fullType, paths []string, maxDepth, maxFields int) (string, error) {
+	root := &selectionNode{}
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		if len(segments) > maxDepth {
+			return "", fmt.Errorf("field path %q is deeper than %d levels", path, maxDepth)
+		}
+		node, t := root, result
+		for i, name := range segments {
+			if t.Kind != kindObject && t.Kind != kindInterface {
+				return "", fmt.Errorf("field path %q: %s has no fields to select", path, t.Name)
+			}
+			if name == typenameField && i == len(segments)-1 {
+				node.child(name)
+				break
+			}
+			f := findField(t, name)
+			if f == nil {
+				return "", fmt.Errorf("field path %q: %s has no field %s", path, t.Name, name)
+			}
+			if hasRequiredArgs(f) {
+				return "", fmt.Errorf("field path %q: field %s.%s requires arguments", path, t.Name, name)
+			}
+			node, t = node.child(name), s.typeOf(f.Type)
+			if t == nil {
+				return "", fmt.Errorf("field path %q: unknown type of %s", path, name)
+			}
+			if i == len(segments)-1 && !isLeaf(t) {
+				node.composite = t
+			}
+		}
+	}
+	if len(root.order) == 0 {
+		return "", errors.New("no field paths given")
+	}
+	budget := maxFields
+	sel, err := root.render(s, &budget)
+	if errors.Is(err, errTooManyFields) {
+		return "", fmt.Errorf("field paths select more than %d fields", maxFields)
+	}
+	return sel, err
+}
+
+func findField(t *fullType, name string) *field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
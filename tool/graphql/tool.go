@@ -0,0 +1,207 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	operationQuery    = "query"
+	operationMutation = "mutation"
+
+	// fieldsArgument is the argument listing the result fields chosen by
+	// the model. The leading underscore keeps it apart from the arguments
+	// of the field.
+	fieldsArgument = "_fields"
+)
+
+type operationTool struct {
+	ts        *toolSet
+	operation string
+	field     *field
+	result    *fullType
+	selection string
+	decl      *tool.Declaration
+}
+
+func newOperationTool(ts *toolSet, operation string, f *field) tool.CallableTool {
+	c, s := ts.config, ts.schema
+	name := operation + "_" + f.Name
+	description := f.Description
+	if description == "" {
+		description = fmt.Sprintf("Runs the GraphQL %s %s.", operation, f.Name)
+	}
+	t := &operationTool{
+		ts:        ts,
+		operation: operation,
+		field:     f,
+		result:    s.typeOf(f.Type),
+	}
+	inputSchema := s.argumentsSchema(f.Args)
+	if t.result != nil && !isLeaf(t.result) {
+		budget := c.maxFields
+		t.selection = s.selectionSet(t.result, c.maxDepth-1, &budget)
+		if t.selection == "" {
+			t.selection = "{ " + typenameField + " }"
+		}
+		if c.fieldSelection && t.result.Kind != kindUnion {
+			inputSchema.Properties[fieldsArgument] = &tool.Schema{
+				Type:  "array",
+				Items: &tool.Schema{Type: "string"},
+				Description: fmt.Sprintf("Result fields to return as dot-separated paths, "+
+					"such as \"id\" or \"author.name\", at most %d levels deep and %d fields. "+
+					"Fields of %s: %s. Omit to return a default selection.",
+					c.maxDepth, c.maxFields, t.result.Name, fieldNames(t.result)),
+			}
+		}
+	}
+	t.decl = &tool.Declaration{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+	return t
+}
+
+// Declaration implements tool.Tool.
+func (t *operationTool) Declaration() *tool.Declaration {
+	return t.decl
+}
+
+// Call runs the operation with the JSON arguments as variables and returns
+// the value of the root field. When the server reports errors alongside
+// data, both are returned.
+func (t *operationTool) Call(ctx context.Context, jsonArgs []byte) (any, error) {
+	name := t.decl.Name
+	log.Debug("Calling GraphQL tool", "tool", name)
+	args := make(map[string]any)
+	if len(bytes.TrimSpace(jsonArgs)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(jsonArgs))
+		dec.UseNumber()
+		if err := dec.Decode(&args); err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", name, err)
+		}
+	}
+	selection, err := t.resultSelection(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for %s: %w", name, err)
+	}
+	query, variables, err := t.document(args, selection)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for %s: %w", name, err)
+	}
+
+	resp, err := t.ts.client.post(ctx, &request{Query: query, OperationName: name, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("graphql %s failed: %w", name, err)
+	}
+	if !resp.hasData() {
+		return nil, fmt.Errorf("graphql %s failed: %s", name, resp.errorMessage())
+	}
+	var data map[string]any
+	dec := json.NewDecoder(bytes.NewReader(resp.Data))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode response of %s: %w", name, err)
+	}
+	if len(resp.Errors) > 0 {
+		return map[string]any{"data": data[t.field.Name], "errors": resp.Errors}, nil
+	}
+	return data[t.field.Name], nil
+}
+
+// resultSelection returns the selection set chosen with the fields
+// argument, or the generated one.
+func (t *operationTool) resultSelection(args map[string]any) (string, error) {
+	raw, ok := args[fieldsArgument]
+	if !ok || !t.ts.config.fieldSelection {
+		return t.selection, nil
+	}
+	delete(args, fieldsArgument)
+	if t.selection == "" || t.result.Kind == kindUnion {
+		return "", fmt.Errorf("%s is not supported by this tool", fieldsArgument)
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return "", fmt.Errorf("%s must be an array of strings", fieldsArgument)
+	}
+	paths := make([]string, 0, len(list))
+	for _, item := range list {
+		path, ok := item.(string)
+		if !ok {
+			return "", fmt.Errorf("%s must be an array of strings", fieldsArgument)
+		}
+		paths = append(paths, path)
+	}
+	c := t.ts.config
+	return t.ts.schema.pathSelection(t.result, paths, c.maxDepth, c.maxFields)
+}
+
+// document builds the operation, declaring a variable for each provided
+// argument so that omitted arguments take their server-side defaults.
+func (t *operationTool) document(args map[string]any, selection string) (string, map[string]any, error) {
+	known := make(map[string]bool, len(t.field.Args))
+	var (
+		declarations []string
+		arguments    []string
+		variables    = make(map[string]any)
+	)
+	for _, arg := range t.field.Args {
+		known[arg.Name] = true
+		value, ok := args[arg.Name]
+		if !ok {
+			if arg.required() {
+				return "", nil, fmt.Errorf("missing required argument %s", arg.Name)
+			}
+			continue
+		}
+		declarations = append(declarations, "$"+arg.Name+": "+arg.Type.String())
+		arguments = append(arguments, arg.Name+": $"+arg.Name)
+		variables[arg.Name] = value
+	}
+	for name := range args {
+		if !known[name] {
+			return "", nil, fmt.Errorf("unknown argument %s", name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(t.operation + " " + t.decl.Name)
+	if len(declarations) > 0 {
+		b.WriteString("(" + strings.Join(declarations, ", ") + ")")
+	}
+	b.WriteString(" { " + t.field.Name)
+	if len(arguments) > 0 {
+		b.WriteString("(" + strings.Join(arguments, ", ") + ")")
+	}
+	if selection != "" {
+		b.WriteString(" " + selection)
+	}
+	b.WriteString(" }")
+	return b.String(), variables, nil
+}
+
+func fieldNames(t *fullType) string {
+	names := make([]string, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		if !f.IsDeprecated && !hasRequiredArgs(f) {
+			names = append(names, f.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
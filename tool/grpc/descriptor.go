@@ -0,0 +1,225 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	ggrpc "google.golang.org/grpc"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// compileProtoFiles compiles the files and registers them with their
+// imports.
+func compileProtoFiles(
+	ctx context.Context,
+	importPaths []string,
+	sources map[string]string,
+	files []string,
+) (*protoregistry.Files, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: importPaths,
+			Accessor: func(path string) (io.ReadCloser, error) {
+				if content, ok := sources[path]; ok {
+					return io.NopCloser(strings.NewReader(content)), nil
+				}
+				return os.Open(path)
+			},
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	compiled, err := compiler.Compile(ctx, files...)
+	if err != nil {
+		return nil, err
+	}
+	registry := new(protoregistry.Files)
+	for _, fd := range compiled {
+		if err := registerWithImports(registry, fd); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+func registerWithImports(registry *protoregistry.Files, fd protoreflect.FileDescriptor) error {
+	if _, err := registry.FindFileByPath(fd.Path()); err == nil {
+		return nil
+	}
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if err := registerWithImports(registry, imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+	if err := registry.RegisterFile(fd); err != nil {
+		return fmt.Errorf("register %s: %w", fd.Path(), err)
+	}
+	return nil
+}
+
+// loadReflection fetches the files defining every service the server lists,
+// with their dependencies, over one reflection stream. It also returns the
+// listed services, since the files may define services that are not served.
+func loadReflection(
+	ctx context.Context,
+	conn ggrpc.ClientConnInterface,
+) (*protoregistry.Files, map[string]bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.CloseSend()
+	ask := func(req *reflectionv1.ServerReflectionRequest) (*reflectionv1.ServerReflectionResponse, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, fmt.Errorf("reflection error %d: %s", e.GetErrorCode(), e.GetErrorMessage())
+		}
+		return resp, nil
+	}
+
+	resp, err := ask(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list services: %w", err)
+	}
+	protos := make(map[string]*descriptorpb.FileDescriptorProto)
+	served := make(map[string]bool)
+	addFiles := func(resp *reflectionv1.ServerReflectionResponse) error {
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := new(descriptorpb.FileDescriptorProto)
+			if err := proto.Unmarshal(raw, fdp); err != nil {
+				return fmt.Errorf("unmarshal file descriptor: %w", err)
+			}
+			protos[fdp.GetName()] = fdp
+		}
+		return nil
+	}
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if isReflectionService(service.GetName()) {
+			continue
+		}
+		served[service.GetName()] = true
+		resp, err := ask(&reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{
+				FileContainingSymbol: service.GetName(),
+			},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("file containing %s: %w", service.GetName(), err)
+		}
+		if err := addFiles(resp); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Servers usually send dependencies along; fetch any that are missing
+	// and not known locally.
+	for {
+		missing := ""
+		for _, fdp := range protos {
+			for _, dep := range fdp.GetDependency() {
+				if _, ok := protos[dep]; ok {
+					continue
+				}
+				if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					continue
+				}
+				missing = dep
+			}
+		}
+		if missing == "" {
+			break
+		}
+		resp, err := ask(&reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: missing},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("file %s: %w", missing, err)
+		}
+		before := len(protos)
+		if err := addFiles(resp); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := protos[missing]; !ok || len(protos) == before {
+			return nil, nil, fmt.Errorf("file %s: not returned by the server", missing)
+		}
+	}
+	files, err := buildFiles(protos)
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, served, nil
+}
+
+// buildFiles links file descriptor protos in dependency order. Dependencies
+// that are not among protos are taken from the global registry, which holds
+// the well-known types.
+func buildFiles(protos map[string]*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	registry := new(protoregistry.Files)
+	visiting := make(map[string]bool)
+	var register func(name string) error
+	register = func(name string) error {
+		if _, err := registry.FindFileByPath(name); err == nil {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("import cycle through %s", name)
+		}
+		fdp, ok := protos[name]
+		if !ok {
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return fmt.Errorf("dependency %s not found", name)
+			}
+			return registerWithImports(registry, fd)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		for _, dep := range fdp.GetDependency() {
+			if err := register(dep); err != nil {
+				return err
+			}
+		}
+		fd, err := protodesc.NewFile(fdp, registry)
+		if err != nil {
+			return fmt.Errorf("link %s: %w", name, err)
+		}
+		return registry.RegisterFile(fd)
+	}
+	for name := range protos {
+		if err := register(name); err != nil {
+			return nil, err
+		}
+	}
+	if registry.NumFiles() == 0 {
+		return nil, errors.New("server returned no service definitions")
+	}
+	return registry, nil
+}
@@ -0,0 +1,374 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package grpc provides a toolset that exposes unary gRPC methods as tools.
+//
+// Service definitions are loaded from .proto files or queried from the
+// server with gRPC server reflection. Each selected unary method becomes a
+// tool whose input schema is generated from the request message. Calls are
+// encoded with the protobuf JSON mapping and invoked dynamically, so no
+// generated code is needed.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const (
+	// defaultToolSetName is the default name for the gRPC tool set.
+	defaultToolSetName = "grpc"
+	// defaultTimeout is the default timeout for a method call.
+	defaultTimeout = 30 * time.Second
+)
+
+// MetadataProvider returns outgoing metadata for a call, such as
+// per-request authentication tokens.
+type MetadataProvider func(ctx context.Context) (metadata.MD, error)
+
+// Option is a functional option for configuring the gRPC tool set.
+type Option func(*config)
+
+// config holds the configuration for the gRPC tool set.
+type config struct {
+	name        string
+	target      string
+	conn        ggrpc.ClientConnInterface
+	dialOptions []ggrpc.DialOption
+	timeout     time.Duration
+
+	protoFiles   []string
+	importPaths  []string
+	protoSources map[string]string
+	reflection   bool
+
+	services map[string]bool
+	methods  map[string]bool
+
+	metadata         metadata.MD
+	metadataProvider MetadataProvider
+}
+
+// WithName sets the name of the tool set.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithTarget sets the server address to dial, such as "localhost:50051".
+func WithTarget(target string) Option {
+	return func(c *config) {
+		c.target = target
+	}
+}
+
+// WithDialOptions sets the options used to dial the target. Without dial
+// options the connection uses plaintext transport credentials.
+func WithDialOptions(opts ...ggrpc.DialOption) Option {
+	return func(c *config) {
+		c.dialOptions = append(c.dialOptions, opts...)
+	}
+}
+
+// WithClientConn uses an existing connection instead of dialing a target.
+// The tool set does not close it.
+func WithClientConn(conn ggrpc.ClientConnInterface) Option {
+	return func(c *config) {
+		c.conn = conn
+	}
+}
+
+// WithTimeout sets the timeout of each method call. The default is 30
+// seconds; zero or a negative value disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithProtoFiles loads service definitions from .proto files, resolved
+// against the import paths. Well-known types such as
+// google/protobuf/timestamp.proto are always available.
+func WithProtoFiles(files ...string) Option {
+	return func(c *config) {
+		c.protoFiles = append(c.protoFiles, files...)
+	}
+}
+
+// WithImportPaths sets the directories searched for .proto files and their
+// imports.
+func WithImportPaths(paths ...string) Option {
+	return func(c *config) {
+		c.importPaths = append(c.importPaths, paths...)
+	}
+}
+
+// WithProtoSource provides the content of a .proto file by path, for files
+// that are not on disk. Paths given here take precedence over the import
+// paths. Load it with WithProtoFiles.
+func WithProtoSource(path, content string) Option {
+	return func(c *config) {
+		if c.protoSources == nil {
+			c.protoSources = make(map[string]string)
+		}
+		c.protoSources[path] = content
+	}
+}
+
+// WithReflection loads service definitions from the server with the gRPC
+// server reflection service (grpc.reflection.v1) instead of .proto files.
+func WithReflection() Option {
+	return func(c *config) {
+		c.reflection = true
+	}
+}
+
+// WithServices limits the tools to the methods of the named services, given
+// by fully-qualified name such as "helloworld.Greeter".
+func WithServices(services ...string) Option {
+	return func(c *config) {
+		if c.services == nil {
+			c.services = make(map[string]bool)
+		}
+		for _, s := range services {
+			c.services[strings.TrimPrefix(s, ".")] = true
+		}
+	}
+}
+
+// WithMethods limits the tools to the named methods, given as
+// "helloworld.Greeter/SayHello" or "helloworld.Greeter.SayHello". Named
+// methods must be unary.
+func WithMethods(methods ...string) Option {
+	return func(c *config) {
+		if c.methods == nil {
+			c.methods = make(map[string]bool)
+		}
+		for _, m := range methods {
+			c.methods[normalizeMethodName(m)] = true
+		}
+	}
+}
+
+// WithMetadata adds metadata sent with every call.
+func WithMetadata(md metadata.MD) Option {
+	return func(c *config) {
+		c.metadata = metadata.Join(c.metadata, md)
+	}
+}
+
+// WithMetadataProvider sets a function that returns metadata for each call,
+// sent in addition to WithMetadata.
+func WithMetadataProvider(provider MetadataProvider) Option {
+	return func(c *config) {
+		c.metadataProvider = provider
+	}
+}
+
+// toolSet is a set of gRPC method tools.
+type toolSet struct {
+	config *config
+	conn   ggrpc.ClientConnInterface
+	owned  *ggrpc.ClientConn
+	types  *dynamicpb.Types
+	tools  []tool.Tool
+}
+
+// NewToolSet creates a gRPC tool set with the provided options.
+func NewToolSet(ctx context.Context, opts ...Option) (tool.ToolSet, error) {
+	c := &config{
+		name:    defaultToolSetName,
+		timeout: defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.protoFiles) > 0 && c.reflection {
+		return nil, errors.New("grpc tool set: use either proto files or reflection, not both")
+	}
+	if len(c.protoFiles) == 0 && !c.reflection {
+		return nil, errors.New("grpc tool set: no proto files or reflection configured")
+	}
+
+	ts := &toolSet{config: c, conn: c.conn}
+	if ts.conn == nil {
+		if c.target == "" {
+			return nil, errors.New("grpc tool set: no target or client connection configured")
+		}
+		dialOptions := c.dialOptions
+		if len(dialOptions) == 0 {
+			dialOptions = []ggrpc.DialOption{
+				ggrpc.WithTransportCredentials(insecure.NewCredentials()),
+			}
+		}
+		conn, err := ggrpc.NewClient(c.target, dialOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("grpc tool set: dial %s: %w", c.target, err)
+		}
+		ts.conn, ts.owned = conn, conn
+	}
+
+	files, served, err := ts.loadFiles(ctx)
+	if err != nil {
+		ts.Close()
+		return nil, err
+	}
+	ts.types = dynamicpb.NewTypes(files)
+	methods, err := c.selectMethods(files, served)
+	if err != nil {
+		ts.Close()
+		return nil, err
+	}
+	names := toolNames(methods)
+	for _, md := range methods {
+		ts.tools = append(ts.tools, newMethodTool(ts, names[md.FullName()], md))
+	}
+	return ts, nil
+}
+
+// Tools implements the ToolSet interface.
+func (ts *toolSet) Tools(ctx context.Context) []tool.Tool {
+	return ts.tools
+}
+
+// Close implements the ToolSet interface. It closes the connection if the
+// tool set dialed it.
+func (ts *toolSet) Close() error {
+	if ts.owned == nil {
+		return nil
+	}
+	return ts.owned.Close()
+}
+
+// Name implements the ToolSet interface.
+func (ts *toolSet) Name() string {
+	return ts.config.name
+}
+
+// loadFiles returns the service definitions and, when loaded by
+// reflection, the services the server serves.
+func (ts *toolSet) loadFiles(ctx context.Context) (*protoregistry.Files, map[string]bool, error) {
+	if ts.config.reflection {
+		files, served, err := loadReflection(ctx, ts.conn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("grpc tool set: load descriptors by reflection: %w", err)
+		}
+		return files, served, nil
+	}
+	files, err := compileProtoFiles(ctx, ts.config.importPaths, ts.config.protoSources, ts.config.protoFiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("grpc tool set: compile proto files: %w", err)
+	}
+	return files, nil, nil
+}
+
+// selectMethods returns the unary methods chosen by the service and method
+// filters, in file and declaration order. Without filters every unary
+// method of the served services, or of all services when served is nil, is
+// chosen, except those of the reflection service.
+func (c *config) selectMethods(
+	files *protoregistry.Files,
+	served map[string]bool,
+) ([]protoreflect.MethodDescriptor, error) {
+	selected := func(service, method string) bool {
+		if len(c.services) == 0 && len(c.methods) == 0 {
+			return !isReflectionService(service) && (served == nil || served[service])
+		}
+		return c.services[service] || c.methods[method]
+	}
+	var (
+		methods []protoreflect.MethodDescriptor
+		found   = make(map[string]bool)
+		err     error
+	)
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			serviceName := string(sd.FullName())
+			found[serviceName] = true
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				name := string(md.FullName())
+				found[name] = true
+				if !selected(serviceName, name) {
+					continue
+				}
+				if md.IsStreamingClient() || md.IsStreamingServer() {
+					if c.methods[name] {
+						err = fmt.Errorf("grpc tool set: method %s is streaming, only unary methods are supported", name)
+						return false
+					}
+					log.Debugf("grpc tool set: skip streaming method %s", name)
+					continue
+				}
+				methods = append(methods, md)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name := range c.services {
+		if !found[name] {
+			return nil, fmt.Errorf("grpc tool set: service %s not found", name)
+		}
+	}
+	for name := range c.methods {
+		if !found[name] {
+			return nil, fmt.Errorf("grpc tool set: method %s not found", name)
+		}
+	}
+	return methods, nil
+}
+
+// toolNames names each tool "<Service>_<Method>", falling back to the
+// fully-qualified name with dots replaced when short names collide.
+func toolNames(methods []protoreflect.MethodDescriptor) map[protoreflect.FullName]string {
+	count := make(map[string]int)
+	short := func(md protoreflect.MethodDescriptor) string {
+		return string(md.Parent().Name()) + "_" + string(md.Name())
+	}
+	for _, md := range methods {
+		count[short(md)]++
+	}
+	names := make(map[protoreflect.FullName]string, len(methods))
+	for _, md := range methods {
+		name := short(md)
+		if count[name] > 1 {
+			name = strings.ReplaceAll(string(md.FullName()), ".", "_")
+		}
+		names[md.FullName()] = name
+	}
+	return names
+}
+
+func normalizeMethodName(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	return strings.Replace(name, "/", ".", 1)
+}
+
+func isReflectionService(name string) bool {
+	return strings.HasPrefix(name, "grpc.reflection.")
+}
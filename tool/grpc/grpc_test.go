@@ -0,0 +1,297 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const libraryProto = `syntax = "proto3";
+
+package demo.v1;
+
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+// Library manages books.
+service Library {
+  // GetBook returns a book by ID.
+  rpc GetBook(GetBookRequest) returns (Book);
+  rpc WatchBooks(GetBookRequest) returns (stream Book);
+}
+
+service Admin {
+  rpc GetBook(GetBookRequest) returns (Book);
+}
+
+enum Genre {
+  GENRE_UNSPECIFIED = 0;
+  GENRE_FICTION = 1;
+}
+
+message GetBookRequest {
+  // ID of the book.
+  string id = 1;
+  oneof lookup {
+    string isbn = 2;
+    int64 legacy_id = 3;
+  }
+  google.protobuf.Timestamp as_of = 4;
+  google.protobuf.StringValue locale = 5;
+  map<string, int32> hints = 6;
+  repeated Genre genres = 7;
+  Node tree = 8;
+  google.protobuf.Struct extra = 9;
+  bytes token = 10;
+}
+
+message Node {
+  string name = 1;
+  repeated Node children = 2;
+}
+
+message Book {
+  string id = 1;
+  string title = 2;
+  Genre genre = 3;
+  int32 pages = 4;
+}
+`
+
+const libraryV2Proto = `syntax = "proto3";
+
+package demo.v2;
+
+service Library {
+  rpc GetBook(Request) returns (Response);
+}
+
+message Request {}
+
+message Response {}
+`
+
+func compileLibrary(t *testing.T) *protoregistry.Files {
+	t.Helper()
+	files, err := compileProtoFiles(context.Background(), nil,
+		map[string]string{"demo/v1/library.proto": libraryProto}, []string{"demo/v1/library.proto"})
+	require.NoError(t, err)
+	return files
+}
+
+// startServer serves Library.GetBook, which echoes the request ID and the
+// authorization metadata, with v1 reflection over an in-memory listener.
+func startServer(t *testing.T, files *protoregistry.Files) *ggrpc.ClientConn {
+	t.Helper()
+	desc, err := files.FindDescriptorByName("demo.v1.Library")
+	require.NoError(t, err)
+	sd := desc.(protoreflect.ServiceDescriptor)
+	md := sd.Methods().ByName("GetBook")
+
+	server := ggrpc.NewServer()
+	server.RegisterService(&ggrpc.ServiceDesc{
+		ServiceName: string(sd.FullName()),
+		HandlerType: (*any)(nil),
+		Methods: []ggrpc.MethodDesc{{
+			MethodName: "GetBook",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ ggrpc.UnaryServerInterceptor) (any, error) {
+				req := dynamicpb.NewMessage(md.Input())
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				id := req.Get(md.Input().Fields().ByName("id")).String()
+				if id == "missing" {
+					return nil, status.Error(codes.NotFound, "no such book")
+				}
+				title := "untitled"
+				if in, ok := metadata.FromIncomingContext(ctx); ok && len(in.Get("authorization")) > 0 {
+					title = in.Get("authorization")[0] + "/" + in.Get("x-tenant")[0]
+				}
+				resp := dynamicpb.NewMessage(md.Output())
+				fields := md.Output().Fields()
+				resp.Set(fields.ByName("id"), protoreflect.ValueOfString(id))
+				resp.Set(fields.ByName("title"), protoreflect.ValueOfString(title))
+				resp.Set(fields.ByName("genre"), protoreflect.ValueOfEnum(1))
+				return resp, nil
+			},
+		}},
+		Streams: []ggrpc.StreamDesc{{
+			StreamName:    "WatchBooks",
+			ServerStreams: true,
+			Handler:       func(any, ggrpc.ServerStream) error { return nil },
+		}},
+	}, struct{}{})
+	reflectionv1.RegisterServerReflectionServer(server, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           server,
+		DescriptorResolver: files,
+	}))
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	conn, err := ggrpc.NewClient("passthrough:///bufnet",
+		ggrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		ggrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func toolByName(t *testing.T, ts tool.ToolSet, name string) tool.CallableTool {
+	t.Helper()
+	for _, tl := range ts.Tools(context.Background()) {
+		if tl.Declaration().Name == name {
+			return tl.(tool.CallableTool)
+		}
+	}
+	require.Failf(t, "tool not found", name)
+	return nil
+}
+
+func TestToolSet_ProtoFiles(t *testing.T) {
+	files := compileLibrary(t)
+	conn := startServer(t, files)
+	ts, err := NewToolSet(context.Background(),
+		WithClientConn(conn),
+		WithProtoSource("demo/v1/library.proto", libraryProto),
+		WithProtoSource("demo/v2/library.proto", libraryV2Proto),
+		WithProtoFiles("demo/v1/library.proto", "demo/v2/library.proto"),
+		WithMetadata(metadata.Pairs("authorization", "Bearer t")),
+		WithMetadataProvider(func(context.Context) (metadata.MD, error) {
+			return metadata.Pairs("x-tenant", "acme"), nil
+		}),
+	)
+	require.NoError(t, err)
+	defer ts.Close()
+
+	var names []string
+	for _, tl := range ts.Tools(context.Background()) {
+		names = append(names, tl.Declaration().Name)
+	}
+	// Short names collide, streaming methods are skipped.
+	assert.ElementsMatch(t, []string{"demo_v1_Library_GetBook", "Admin_GetBook", "demo_v2_Library_GetBook"}, names)
+
+	getBook := toolByName(t, ts, "demo_v1_Library_GetBook")
+	assert.Equal(t, "GetBook returns a book by ID.", getBook.Declaration().Description)
+	result, err := getBook.Call(context.Background(), []byte(`{"id":"b1","as_of":"2024-01-02T15:04:05Z","legacy_id":"7"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id": "b1", "title": "Bearer t/acme", "genre": "GENRE_FICTION", "pages": json.Number("0"),
+	}, result)
+
+	_, err = getBook.Call(context.Background(), []byte(`{"id":"missing"}`))
+	require.ErrorContains(t, err, "NotFound")
+	_, err = getBook.Call(context.Background(), []byte(`{"unknown":1}`))
+	require.ErrorContains(t, err, "invalid arguments")
+}
+
+func TestToolSet_Reflection(t *testing.T) {
+	conn := startServer(t, compileLibrary(t))
+	ts, err := NewToolSet(context.Background(),
+		WithClientConn(conn),
+		WithReflection(),
+	)
+	require.NoError(t, err)
+	defer ts.Close()
+	tools := ts.Tools(context.Background())
+	require.Len(t, tools, 1)
+	assert.Equal(t, "Library_GetBook", tools[0].Declaration().Name)
+	assert.Equal(t, "GetBook returns a book by ID.", tools[0].Declaration().Description)
+
+	result, err := tools[0].(tool.CallableTool).Call(context.Background(), []byte(`{"id":"b2"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "untitled", result.(map[string]any)["title"])
+}
+
+func TestToolSet_Selection(t *testing.T) {
+	files := compileLibrary(t)
+	opts := []Option{
+		WithClientConn(startServer(t, files)),
+		WithProtoSource("demo/v1/library.proto", libraryProto),
+		WithProtoFiles("demo/v1/library.proto"),
+	}
+	ts, err := NewToolSet(context.Background(), append(opts, WithServices("demo.v1.Admin"))...)
+	require.NoError(t, err)
+	require.Len(t, ts.Tools(context.Background()), 1)
+	assert.Equal(t, "Admin_GetBook", ts.Tools(context.Background())[0].Declaration().Name)
+
+	ts, err = NewToolSet(context.Background(), append(opts, WithMethods("/demo.v1.Library/GetBook"))...)
+	require.NoError(t, err)
+	require.Len(t, ts.Tools(context.Background()), 1)
+
+	_, err = NewToolSet(context.Background(), append(opts, WithMethods("demo.v1.Library.WatchBooks"))...)
+	require.ErrorContains(t, err, "streaming")
+	_, err = NewToolSet(context.Background(), append(opts, WithServices("demo.v1.Missing"))...)
+	require.ErrorContains(t, err, "not found")
+	_, err = NewToolSet(context.Background(), append(opts, WithReflection())...)
+	require.ErrorContains(t, err, "not both")
+	_, err = NewToolSet(context.Background(), WithProtoFiles("demo/v1/library.proto"))
+	require.ErrorContains(t, err, "no target")
+}
+
+func TestMessageSchema(t *testing.T) {
+	files := compileLibrary(t)
+	desc, err := files.FindDescriptorByName("demo.v1.GetBookRequest")
+	require.NoError(t, err)
+	s := messageSchema(desc.(protoreflect.MessageDescriptor))
+
+	assert.Equal(t, "object", s.Type)
+	assert.Contains(t, s.Description, "Set at most one of isbn, legacy_id.")
+	assert.Equal(t, "ID of the book.", s.Properties["id"].Description)
+	assert.Equal(t, "string", s.Properties["legacy_id"].Type)
+	assert.Equal(t, `^-?[0-9]+$`, s.Properties["legacy_id"].Pattern)
+	assert.Equal(t, "string", s.Properties["as_of"].Type)
+	assert.Contains(t, s.Properties["as_of"].Description, "RFC 3339")
+	assert.Equal(t, "string", s.Properties["locale"].Type)
+	assert.Equal(t, "object", s.Properties["hints"].Type)
+	assert.Equal(t, "integer", s.Properties["hints"].AdditionalProperties.(*tool.Schema).Type)
+	assert.Equal(t, "array", s.Properties["genres"].Type)
+	assert.Equal(t, []any{"GENRE_UNSPECIFIED", "GENRE_FICTION"}, s.Properties["genres"].Items.Enum)
+	assert.Equal(t, "object", s.Properties["extra"].Type)
+	assert.Contains(t, s.Properties["token"].Description, "Base64")
+
+	tree := s.Properties["tree"]
+	assert.Equal(t, "object", tree.Type)
+	assert.Equal(t, "#/$defs/demo.v1.Node", tree.Properties["children"].Items.Ref)
+	require.Contains(t, s.Defs, "demo.v1.Node")
+	assert.Equal(t, "#/$defs/demo.v1.Node", s.Defs["demo.v1.Node"].Properties["children"].Items.Ref)
+
+	// Arguments valid for the schema decode with the protobuf JSON mapping.
+	msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	require.NoError(t, protojson.Unmarshal([]byte(`{
+		"id": "b1", "isbn": "978", "as_of": "2024-01-02T15:04:05Z", "locale": "en",
+		"hints": {"a": 1}, "genres": ["GENRE_FICTION"], "token": "AQI=",
+		"tree": {"name": "root", "children": [{"name": "leaf"}]}, "extra": {"k": [1, "x"]}
+	}`), msg))
+}
@@ -0,0 +1,226 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const defsPrefix = "#/$defs/"
+
+// messageSchema returns the JSON schema of the protobuf JSON mapping of md,
+// using proto field names. Recursive messages are referenced from $defs.
+func messageSchema(md protoreflect.MessageDescriptor) *tool.Schema {
+	b := &schemaBuilder{
+		defs:     make(map[string]*tool.Schema),
+		building: make(map[protoreflect.FullName]bool),
+	}
+	s := b.message(md)
+	if len(b.defs) > 0 {
+		s.Defs = b.defs
+	}
+	return s
+}
+
+type schemaBuilder struct {
+	defs     map[string]*tool.Schema
+	building map[protoreflect.FullName]bool
+}
+
+func (b *schemaBuilder) message(md protoreflect.MessageDescriptor) *tool.Schema {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+	name := md.FullName()
+	if b.building[name] {
+		if _, ok := b.defs[string(name)]; !ok {
+			// Reserve the name first: building the definition meets the
+			// recursion again.
+			def := &tool.Schema{}
+			b.defs[string(name)] = def
+			*def = *b.object(md)
+		}
+		return &tool.Schema{Ref: defsPrefix + string(name)}
+	}
+	b.building[name] = true
+	defer delete(b.building, name)
+	return b.object(md)
+}
+
+func (b *schemaBuilder) object(md protoreflect.MessageDescriptor) *tool.Schema {
+	s := &tool.Schema{
+		Type:        "object",
+		Description: comment(md),
+		Properties:  make(map[string]*tool.Schema),
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s.Properties[string(fd.Name())] = b.field(fd)
+	}
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		names := make([]string, 0, od.Fields().Len())
+		for j := 0; j < od.Fields().Len(); j++ {
+			names = append(names, string(od.Fields().Get(j).Name()))
+		}
+		s.Description = joinSentences(s.Description,
+			"Set at most one of "+strings.Join(names, ", ")+".")
+	}
+	return s
+}
+
+func (b *schemaBuilder) field(fd protoreflect.FieldDescriptor) *tool.Schema {
+	var s *tool.Schema
+	switch {
+	case fd.IsMap():
+		s = &tool.Schema{
+			Type:                 "object",
+			AdditionalProperties: b.singular(fd.MapValue()),
+		}
+	case fd.IsList():
+		s = &tool.Schema{Type: "array", Items: b.singular(fd)}
+	default:
+		s = b.singular(fd)
+	}
+	if c := comment(fd); c != "" {
+		if s.Ref != "" {
+			// Siblings of $ref are ignored by many validators.
+			return s
+		}
+		s.Description = joinSentences(c, s.Description)
+	}
+	return s
+}
+
+func (b *schemaBuilder) singular(fd protoreflect.FieldDescriptor) *tool.Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &tool.Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &tool.Schema{Type: "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int64Schema()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &tool.Schema{Type: "number"}
+	case protoreflect.StringKind:
+		return &tool.Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return bytesSchema()
+	case protoreflect.EnumKind:
+		return enumSchema(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.message(fd.Message())
+	default:
+		return &tool.Schema{}
+	}
+}
+
+func enumSchema(ed protoreflect.EnumDescriptor) *tool.Schema {
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return &tool.Schema{Type: "null"}
+	}
+	values := ed.Values()
+	s := &tool.Schema{Type: "string", Description: comment(ed)}
+	for i := 0; i < values.Len(); i++ {
+		s.Enum = append(s.Enum, string(values.Get(i).Name()))
+	}
+	return s
+}
+
+func int64Schema() *tool.Schema {
+	return &tool.Schema{
+		Type:        "string",
+		Pattern:     `^-?[0-9]+$`,
+		Description: "64-bit integer as a decimal string.",
+	}
+}
+
+func bytesSchema() *tool.Schema {
+	return &tool.Schema{Type: "string", Description: "Base64-encoded bytes."}
+}
+
+// wellKnownSchema returns the schema of the special JSON mapping of a
+// well-known type, or nil for other messages.
+func wellKnownSchema(md protoreflect.MessageDescriptor) *tool.Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &tool.Schema{Type: "string", Description: "RFC 3339 timestamp, such as 2024-01-02T15:04:05Z."}
+	case "google.protobuf.Duration":
+		return &tool.Schema{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?s$`,
+			Description: "Duration in seconds with an s suffix, such as 1.5s."}
+	case "google.protobuf.FieldMask":
+		return &tool.Schema{Type: "string", Description: "Comma-separated lowerCamelCase field paths."}
+	case "google.protobuf.Struct":
+		return &tool.Schema{Type: "object"}
+	case "google.protobuf.ListValue":
+		return &tool.Schema{Type: "array", Items: &tool.Schema{}}
+	case "google.protobuf.Value":
+		return &tool.Schema{Description: "Any JSON value."}
+	case "google.protobuf.Empty":
+		return &tool.Schema{Type: "object"}
+	case "google.protobuf.Any":
+		return &tool.Schema{
+			Type:        "object",
+			Description: "A message of the type named by @type, with its fields alongside.",
+			Properties:  map[string]*tool.Schema{"@type": {Type: "string"}},
+			Required:    []string{"@type"},
+		}
+	case "google.protobuf.BoolValue":
+		return &tool.Schema{Type: "boolean"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &tool.Schema{Type: "integer"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return int64Schema()
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return &tool.Schema{Type: "number"}
+	case "google.protobuf.StringValue":
+		return &tool.Schema{Type: "string"}
+	case "google.protobuf.BytesValue":
+		return bytesSchema()
+	default:
+		return nil
+	}
+}
+
+// comment returns the leading comment of d in its source file, if any.
+func comment(d protoreflect.Descriptor) string {
+	file := d.ParentFile()
+	if file == nil {
+		return ""
+	}
+	loc := file.SourceLocations().ByDescriptor(d)
+	lines := strings.Split(strings.TrimSpace(loc.LeadingComments), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.TrimSpace(strings.Join(lines, " "))
+}
+
+func joinSentences(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + " " + b
+	}
+}
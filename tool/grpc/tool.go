@@ -0,0 +1,114 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+type methodTool struct {
+	ts         *toolSet
+	method     protoreflect.MethodDescriptor
+	fullMethod string
+	decl       *tool.Declaration
+}
+
+func newMethodTool(ts *toolSet, name string, md protoreflect.MethodDescriptor) tool.CallableTool {
+	description := comment(md)
+	if description == "" {
+		description = fmt.Sprintf("Calls the gRPC method %s.", md.FullName())
+	}
+	return &methodTool{
+		ts:         ts,
+		method:     md,
+		fullMethod: fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name()),
+		decl: &tool.Declaration{
+			Name:         name,
+			Description:  description,
+			InputSchema:  messageSchema(md.Input()),
+			OutputSchema: messageSchema(md.Output()),
+		},
+	}
+}
+
+// Declaration implements tool.Tool.
+func (t *methodTool) Declaration() *tool.Declaration {
+	return t.decl
+}
+
+// Call invokes the method with the JSON arguments as the request message and
+// returns the response message as decoded JSON.
+func (t *methodTool) Call(ctx context.Context, jsonArgs []byte) (any, error) {
+	log.Debug("Calling gRPC tool", "method", t.fullMethod)
+	req := dynamicpb.NewMessage(t.method.Input())
+	if len(bytes.TrimSpace(jsonArgs)) > 0 {
+		unmarshal := protojson.UnmarshalOptions{Resolver: t.ts.types}
+		if err := unmarshal.Unmarshal(jsonArgs, req); err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", t.fullMethod, err)
+		}
+	}
+
+	c := t.ts.config
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	md := c.metadata
+	if c.metadataProvider != nil {
+		extra, err := c.metadataProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("metadata for %s: %w", t.fullMethod, err)
+		}
+		md = metadata.Join(md, extra)
+	}
+	if len(md) > 0 {
+		if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+			md = metadata.Join(existing, md)
+		}
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	resp := dynamicpb.NewMessage(t.method.Output())
+	if err := t.ts.conn.Invoke(ctx, t.fullMethod, req, resp); err != nil {
+		if st, ok := status.FromError(err); ok {
+			return nil, fmt.Errorf("gRPC %s failed: %s: %s", t.fullMethod, st.Code(), st.Message())
+		}
+		return nil, fmt.Errorf("gRPC %s failed: %w", t.fullMethod, err)
+	}
+	marshal := protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+		Resolver:        t.ts.types,
+	}
+	data, err := marshal.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("marshal response of %s: %w", t.fullMethod, err)
+	}
+	var result any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response of %s: %w", t.fullMethod, err)
+	}
+	return result, nil
+}
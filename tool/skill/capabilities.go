@@ -0,0 +1,102 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/codeexecutor/sandbox"
+	"trpc.group/trpc-go/trpc-agent-go/skill"
+)
+
+// WithCapabilityPolicy makes skill_run honor the capabilities declared in
+// SKILL.md front matter, bounded by policy.
+//
+// A run is rejected when the skill declares more than policy allows.
+// Otherwise the declaration becomes the per-run sandbox permission
+// profile: declared write paths are granted, network access is enabled
+// when hosts are declared, declared env vars are forwarded from the host
+// environment, and the declared timeout is the default run timeout.
+// Requested timeouts are capped at policy.MaxTimeout.
+//
+// The sandbox network mode is all or nothing, so declared hosts are
+// checked against policy but not enforced per host at run time.
+// Without this option declared capabilities are ignored and every skill
+// runs with the executor's own permissions.
+func WithCapabilityPolicy(policy skill.CapabilityPolicy) func(*RunTool) {
+	return func(t *RunTool) {
+		t.capPolicy = &policy
+	}
+}
+
+// WithLoadToolCapabilityPolicy makes skill_load fail when a skill declares
+// capabilities beyond policy, so the model learns early that the skill is
+// unusable here.
+func WithLoadToolCapabilityPolicy(
+	policy skill.CapabilityPolicy,
+) LoadToolOption {
+	return func(o *loadToolOptions) {
+		o.capPolicy = &policy
+	}
+}
+
+// applyCapabilities checks the declared capabilities of in.Skill and
+// returns the context and input the run executes with.
+func (t *RunTool) applyCapabilities(
+	ctx context.Context,
+	in runInput,
+) (context.Context, runInput, error) {
+	if t.capPolicy == nil || t.repo == nil {
+		return ctx, in, nil
+	}
+	sk, err := skill.GetForContext(ctx, t.repo, in.Skill)
+	if err != nil {
+		return ctx, in, fmt.Errorf("skill_run: %w", err)
+	}
+	caps := sk.Capabilities
+	if err := t.capPolicy.Check(in.Skill, caps); err != nil {
+		return ctx, in, fmt.Errorf("skill_run: %w", err)
+	}
+
+	add := sandbox.AdditionalPermissions{WritePaths: caps.WritePaths}
+	if len(caps.Network) > 0 {
+		add.Network = &sandbox.NetworkPolicy{Mode: sandbox.NetworkEnabled}
+	}
+	ctx = sandbox.WithAdditionalPermissions(ctx, add)
+
+	if len(caps.Env) > 0 {
+		env := cloneStringMap(in.Env)
+		for _, key := range caps.Env {
+			if _, ok := env[key]; ok || isBlockedSkillEnvKey(key) {
+				continue
+			}
+			if v, ok := os.LookupEnv(key); ok {
+				env[key] = v
+			}
+		}
+		in.Env = env
+	}
+
+	timeout := time.Duration(in.Timeout) * time.Second
+	if in.Timeout <= 0 {
+		timeout = defaultSkillRunTimeout
+		if caps.Timeout > 0 {
+			timeout = caps.Timeout
+		}
+	}
+	if limit := t.capPolicy.MaxTimeout; limit > 0 && timeout > limit {
+		timeout = limit
+	}
+	in.Timeout = int((timeout + time.Second - 1) / time.Second)
+	return ctx, in, nil
+}
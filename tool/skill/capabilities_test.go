@@ -0,0 +1,108 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package skill
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	localexec "trpc.group/trpc-go/trpc-agent-go/codeexecutor/local"
+	"trpc.group/trpc-go/trpc-agent-go/skill"
+)
+
+const capsSkillMD = `---
+name: notes
+description: release notes
+capabilities:
+  network: [api.github.com]
+  write_paths: [out]
+  env: [NOTES_TOKEN]
+  binaries: [git]
+  timeout: 10m
+---
+body
+`
+
+func newCapsRepo(t *testing.T) skill.Repository {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "notes")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, skillFileName),
+		[]byte(capsSkillMD), 0o644))
+	repo, err := skill.NewFSRepository(root)
+	require.NoError(t, err)
+	return repo
+}
+
+var notesPolicy = skill.CapabilityPolicy{
+	AllowedHosts:    []string{"*.github.com"},
+	AllowedEnv:      []string{"NOTES_*"},
+	AllowedBinaries: []string{"git"},
+	MaxTimeout:      15 * time.Minute,
+}
+
+func TestRunTool_ApplyCapabilities(t *testing.T) {
+	t.Setenv("NOTES_TOKEN", "secret")
+	repo := newCapsRepo(t)
+	rt := NewRunTool(repo, localexec.New(),
+		WithCapabilityPolicy(notesPolicy))
+
+	_, in, err := rt.applyCapabilities(context.Background(),
+		runInput{Skill: "notes"})
+	require.NoError(t, err)
+	require.Equal(t, "secret", in.Env["NOTES_TOKEN"])
+	require.Equal(t, 600, in.Timeout)
+
+	// Explicit values win; requested timeouts are capped.
+	_, in, err = rt.applyCapabilities(context.Background(), runInput{
+		Skill:   "notes",
+		Env:     map[string]string{"NOTES_TOKEN": "mine"},
+		Timeout: 3600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "mine", in.Env["NOTES_TOKEN"])
+	require.Equal(t, 900, in.Timeout)
+
+	// Without a policy declarations are ignored.
+	_, in, err = NewRunTool(repo, localexec.New()).applyCapabilities(
+		context.Background(), runInput{Skill: "notes"})
+	require.NoError(t, err)
+	require.Empty(t, in.Env)
+	require.Zero(t, in.Timeout)
+}
+
+func TestCapabilityPolicy_RejectsRunAndLoad(t *testing.T) {
+	repo := newCapsRepo(t)
+	strict := notesPolicy
+	strict.AllowedHosts = nil
+	strict.MaxTimeout = time.Minute
+
+	rt := NewRunTool(repo, localexec.New(), WithCapabilityPolicy(strict))
+	_, err := rt.Call(context.Background(),
+		[]byte(`{"skill":"notes","command":"echo hi"}`))
+	require.ErrorIs(t, err, skill.ErrCapabilityDenied)
+	require.ErrorContains(t, err, `network host "api.github.com"`)
+	require.ErrorContains(t, err, "timeout 10m0s (max 1m0s)")
+
+	lt := NewLoadToolWithOptions(repo, WithLoadToolCapabilityPolicy(strict))
+	_, err = lt.Call(context.Background(), []byte(`{"skill":"notes"}`))
+	require.ErrorIs(t, err, skill.ErrCapabilityDenied)
+
+	lt = NewLoadToolWithOptions(repo,
+		WithLoadToolCapabilityPolicy(notesPolicy))
+	_, err = lt.Call(context.Background(), []byte(`{"skill":"notes"}`))
+	require.NoError(t, err)
+}
@@ -33,6 +33,7 @@ type stateDeltaProvider interface {
 type LoadTool struct {
 	repo        skill.Repository
 	description string
+	capPolicy   *skill.CapabilityPolicy
 }
 
 const defaultLoadToolDescription = "Load a skill body and optional docs. " +
@@ -45,6 +46,7 @@ const defaultLoadToolDescription = "Load a skill body and optional docs. " +
 
 type loadToolOptions struct {
 	description string
+	capPolicy   *skill.CapabilityPolicy
 }
 
 // LoadToolOption configures LoadTool.
@@ -80,6 +82,7 @@ func NewLoadToolWithOptions(
 	return &LoadTool{
 		repo:        repo,
 		description: options.description,
+		capPolicy:   options.capPolicy,
 	}
 }
 
@@ -134,9 +137,15 @@ func (t *LoadTool) Call(ctx context.Context, args []byte) (any, error) {
 	}
 	if t.repo != nil {
 		// validate existence
-		if _, err := skill.GetForContext(ctx, t.repo, in.Skill); err != nil {
+		sk, err := skill.GetForContext(ctx, t.repo, in.Skill)
+		if err != nil {
 			return nil, fmt.Errorf("unknown skill: %s", in.Skill)
 		}
+		if t.capPolicy != nil {
+			if err := t.capPolicy.Check(in.Skill, sk.Capabilities); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return fmt.Sprintf("loaded: %s", in.Skill), nil
 }
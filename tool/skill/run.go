@@ -61,6 +61,8 @@ type RunTool struct {
 	forceSaveArtifacts bool
 	requireSkillLoaded bool
 	outputLimits       RunOutputLimits
+
+	capPolicy *skill.CapabilityPolicy
 }
 
 // RunOutputLimits controls how much inline text skill_run returns.
@@ -479,6 +481,10 @@ func (t *RunTool) Call(
 			in.Skill,
 		)
 	}
+	ctx, in, err = t.applyCapabilities(ctx, in)
+	if err != nil {
+		return nil, err
+	}
 	in, saveRequested, outputsSaveSkipReason := t.applyArtifactSaveOverrides(
 		ctx,
 		in,
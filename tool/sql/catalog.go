@@ -0,0 +1,205 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type listTablesInput struct{}
+
+type listTablesOutput struct {
+	Tables []tableInfo `json:"tables"`
+}
+
+type tableInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type describeTableInput struct {
+	Table      string `json:"table" jsonschema:"description=Name of the table as returned by list_tables"`
+	SampleRows *int   `json:"sample_rows,omitempty" jsonschema:"description=Number of sample rows to return (0 to 20)"`
+}
+
+type describeTableOutput struct {
+	Table      string       `json:"table"`
+	Columns    []columnInfo `json:"columns"`
+	SampleRows [][]any      `json:"sample_rows,omitempty"`
+}
+
+type columnInfo struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+}
+
+// listTables lists the tables and views of the schema that the allowlist
+// permits.
+func (ts *toolSet) listTables(ctx context.Context, _ listTablesInput) (listTablesOutput, error) {
+	ctx, cancel := ts.withTimeout(ctx)
+	defer cancel()
+	var query string
+	schema, args := ts.schemaExpr(1)
+	switch ts.config.dialect {
+	case DialectSQLite:
+		query = `SELECT name, type FROM sqlite_master
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`
+		args = nil
+	case DialectClickHouse:
+		query = "SELECT name, engine FROM system.tables WHERE database = " + schema + " ORDER BY name"
+	default:
+		query = "SELECT table_name, table_type FROM information_schema.tables WHERE table_schema = " +
+			schema + " ORDER BY table_name"
+	}
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return listTablesOutput{}, fmt.Errorf("list tables: %w", err)
+	}
+	defer rows.Close()
+	out := listTablesOutput{Tables: []tableInfo{}}
+	for rows.Next() {
+		var t tableInfo
+		if err := rows.Scan(&t.Name, &t.Type); err != nil {
+			return listTablesOutput{}, fmt.Errorf("list tables: %w", err)
+		}
+		if ts.guard.tableAllowed(strings.ToLower(t.Name)) {
+			out.Tables = append(out.Tables, t)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return listTablesOutput{}, fmt.Errorf("list tables: %w", err)
+	}
+	return out, nil
+}
+
+// describeTable returns the columns of an allowed table and sample rows.
+func (ts *toolSet) describeTable(ctx context.Context, in describeTableInput) (describeTableOutput, error) {
+	if in.Table == "" {
+		return describeTableOutput{}, fmt.Errorf("table is required")
+	}
+	if !ts.guard.tableAllowed(strings.ToLower(in.Table)) {
+		return describeTableOutput{}, fmt.Errorf("table %s is not allowed", in.Table)
+	}
+	samples := ts.config.sampleRows
+	if in.SampleRows != nil {
+		samples = *in.SampleRows
+	}
+	if samples < 0 || samples > maxSampleRows {
+		return describeTableOutput{}, fmt.Errorf("sample_rows must be between 0 and %d", maxSampleRows)
+	}
+	ctx, cancel := ts.withTimeout(ctx)
+	defer cancel()
+
+	query, args := ts.columnsQuery(in.Table)
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return describeTableOutput{}, fmt.Errorf("describe table %s: %w", in.Table, err)
+	}
+	defer rows.Close()
+	out := describeTableOutput{Table: in.Table}
+	for rows.Next() {
+		var c columnInfo
+		if err := rows.Scan(&c.Name, &c.Type, &c.Nullable, &c.PrimaryKey); err != nil {
+			return describeTableOutput{}, fmt.Errorf("describe table %s: %w", in.Table, err)
+		}
+		out.Columns = append(out.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return describeTableOutput{}, fmt.Errorf("describe table %s: %w", in.Table, err)
+	}
+	if len(out.Columns) == 0 {
+		return describeTableOutput{}, fmt.Errorf("table %s not found", in.Table)
+	}
+	if samples == 0 {
+		return out, nil
+	}
+
+	// The name is quoted as found in the catalog, so it cannot inject SQL.
+	sample := "SELECT * FROM " + ts.quotedTable(in.Table) + " LIMIT " + strconv.Itoa(samples)
+	result, err := ts.query(ctx, sample, samples)
+	if err != nil {
+		return describeTableOutput{}, fmt.Errorf("sample rows of %s: %w", in.Table, err)
+	}
+	out.SampleRows = result.rows
+	return out, nil
+}
+
+// schemaExpr returns the SQL expression for the configured schema, as the
+// placeholder numbered n with its argument, or the current schema.
+func (ts *toolSet) schemaExpr(n int) (string, []any) {
+	d := ts.config.dialect
+	if ts.config.schema != "" {
+		return placeholder(d, n), []any{ts.config.schema}
+	}
+	switch d {
+	case DialectMySQL:
+		return "DATABASE()", nil
+	case DialectClickHouse:
+		return "currentDatabase()", nil
+	default:
+		return "current_schema()", nil
+	}
+}
+
+func (ts *toolSet) columnsQuery(table string) (string, []any) {
+	d := ts.config.dialect
+	if d == DialectSQLite {
+		return `SELECT name, type, "notnull" = 0, pk > 0 FROM pragma_table_info(?) ORDER BY cid`, []any{table}
+	}
+	schema, args := ts.schemaExpr(1)
+	args = append(args, table)
+	name := placeholder(d, len(args))
+	switch d {
+	case DialectClickHouse:
+		return "SELECT name, type, startsWith(type, 'Nullable('), is_in_primary_key FROM system.columns " +
+			"WHERE database = " + schema + " AND table = " + name + " ORDER BY position", args
+	case DialectMySQL:
+		return "SELECT column_name, column_type, is_nullable = 'YES', column_key = 'PRI' " +
+			"FROM information_schema.columns WHERE table_schema = " + schema +
+			" AND table_name = " + name + " ORDER BY ordinal_position", args
+	default:
+		return `SELECT c.column_name, c.data_type, c.is_nullable = 'YES', EXISTS (
+  SELECT 1 FROM information_schema.table_constraints tc
+  JOIN information_schema.key_column_usage k
+    ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema
+  WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+    AND tc.table_name = c.table_name AND k.column_name = c.column_name)
+FROM information_schema.columns c
+WHERE c.table_schema = ` + schema + ` AND c.table_name = ` + name + `
+ORDER BY c.ordinal_position`, args
+	}
+}
+
+func (ts *toolSet) quotedTable(table string) string {
+	if ts.config.schema == "" || ts.config.dialect == DialectSQLite {
+		return quoteIdent(ts.config.dialect, table)
+	}
+	return quoteIdent(ts.config.dialect, ts.config.schema) + "." + quoteIdent(ts.config.dialect, table)
+}
+
+func quoteIdent(d Dialect, name string) string {
+	q := `"`
+	if d == DialectMySQL || d == DialectClickHouse {
+		q = "`"
+	}
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
+
+func placeholder(d Dialect, n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
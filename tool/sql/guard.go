@@ -0,0 +1,531 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// deniedKeywords may not appear anywhere in a read-only statement. The
+// statement must start with SELECT or WITH, so these are the keywords that
+// can still write or lock from inside one: data-modifying CTEs, SELECT
+// INTO, locking clauses and the TABLE shorthand, which bypasses the table
+// allowlist.
+var deniedKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true,
+	"UPSERT": true, "INTO": true, "SHARE": true, "LOCK": true,
+	"TABLE": true, "OUTFILE": true, "DUMPFILE": true,
+}
+
+// defaultDeniedFunctions have side effects or reach outside the database:
+// sleeping, locking, sequences, settings, file and network access.
+var defaultDeniedFunctions = []string{
+	// PostgreSQL.
+	"pg_sleep", "pg_sleep_for", "pg_sleep_until", "pg_read_file",
+	"pg_read_binary_file", "pg_ls_dir", "pg_stat_file", "lo_import",
+	"lo_export", "lo_from_bytea", "lo_put", "dblink", "dblink_exec",
+	"set_config", "nextval", "setval", "pg_terminate_backend",
+	"pg_cancel_backend", "pg_advisory_lock", "pg_advisory_xact_lock",
+	"pg_reload_conf", "query_to_xml",
+	// MySQL.
+	"sleep", "benchmark", "load_file", "get_lock", "release_lock",
+	"release_all_locks",
+	// SQLite.
+	"load_extension", "writefile", "readfile", "fts3_tokenizer",
+	// ClickHouse table functions.
+	"url", "file", "s3", "s3cluster", "hdfs", "remote", "remotesecure",
+	"cluster", "clusterallreplicas", "mysql", "postgresql", "jdbc", "odbc",
+	"executable", "input",
+}
+
+// clause keywords end a table reference list or cannot be an alias.
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "EXCEPT": true,
+	"INTERSECT": true, "WINDOW": true, "ON": true, "USING": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true,
+	"FULL": true, "CROSS": true, "NATURAL": true, "OUTER": true,
+	"FETCH": true, "FOR": true, "SETTINGS": true, "FORMAT": true,
+	"PREWHERE": true, "SAMPLE": true, "FINAL": true, "ARRAY": true,
+	"GLOBAL": true, "ANY": true, "ALL": true, "ASOF": true, "SEMI": true,
+	"ANTI": true, "QUALIFY": true, "STRAIGHT_JOIN": true, "LATERAL": true,
+}
+
+type sqlTokenKind int
+
+const (
+	tokWord sqlTokenKind = iota
+	tokQuoted
+	tokString
+	tokNumber
+	tokPunct
+	tokParam
+)
+
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	start int
+	// depth is the parenthesis depth the token is at.
+	depth int
+}
+
+// upper returns the keyword form of a word.
+func (t sqlToken) upper() string {
+	if t.kind != tokWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// ident returns the identifier a word or quoted token names. Unquoted
+// identifiers compare case-insensitively and are lowercased.
+func (t sqlToken) ident() string {
+	if t.kind == tokQuoted {
+		return t.text
+	}
+	return strings.ToLower(t.text)
+}
+
+// tokenize splits a statement into tokens, dropping comments and white
+// space. Quoted identifiers carry their unquoted name.
+func tokenize(src string, d Dialect) ([]sqlToken, error) {
+	var (
+		tokens []sqlToken
+		depth  int
+	)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case isLineComment(src[i:], d):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			// MySQL runs the content of /*! ... */ comments.
+			if d == DialectMySQL && strings.HasPrefix(src[i:], "/*!") {
+				return nil, errors.New("executable comments are not allowed")
+			}
+			i += end + 4
+		case c == '\'' || (d == DialectPostgres && (c == 'E' || c == 'e') &&
+			i+1 < len(src) && src[i+1] == '\''):
+			// Backslashes escape in MySQL and ClickHouse strings and in
+			// PostgreSQL E'...' strings.
+			backslash := d == DialectMySQL || d == DialectClickHouse || c != '\''
+			start := i
+			if c != '\'' {
+				i++
+			}
+			end, err := quotedEnd(src, i, '\'', backslash)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: tokString, text: src[start:end], start: start, depth: depth})
+			i = end
+		case c == '"' || c == '`':
+			if c == '"' && d == DialectMySQL {
+				end, err := quotedEnd(src, i, '"', true)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, sqlToken{kind: tokString, text: src[i:end], start: i, depth: depth})
+				i = end
+				continue
+			}
+			end, err := quotedEnd(src, i, c, false)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.ReplaceAll(src[i+1:end-1], string([]byte{c, c}), string(c))
+			tokens = append(tokens, sqlToken{kind: tokQuoted, text: name, start: i, depth: depth})
+			i = end
+		case c == '[' && d == DialectSQLite:
+			end := strings.IndexByte(src[i:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated quoted identifier")
+			}
+			tokens = append(tokens, sqlToken{kind: tokQuoted, text: src[i+1 : i+end], start: i, depth: depth})
+			i += end + 1
+		case c == '$' && d == DialectPostgres && i+1 < len(src) && !isDigit(src[i+1]):
+			// Dollar-quoted string: $tag$ ... $tag$.
+			tagEnd := strings.IndexByte(src[i+1:], '$')
+			if tagEnd < 0 {
+				return nil, errors.New("unterminated dollar-quoted string")
+			}
+			tag := src[i : i+tagEnd+2]
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, errors.New("unterminated dollar-quoted string")
+			}
+			stop := i + len(tag) + end + len(tag)
+			tokens = append(tokens, sqlToken{kind: tokString, text: src[i:stop], start: i, depth: depth})
+			i = stop
+		case c == '?' || c == '$' || (c == ':' && i+1 < len(src) && isWordByte(src[i+1]) && !isDigit(src[i+1])):
+			start := i
+			i++
+			for i < len(src) && isWordByte(src[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokParam, text: src[start:i], start: start, depth: depth})
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			for i < len(src) && (isWordByte(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokNumber, text: src[start:i], start: start, depth: depth})
+		case isWordByte(c) || c >= 0x80:
+			start := i
+			for i < len(src) && (isWordByte(src[i]) || src[i] >= 0x80 || src[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokWord, text: src[start:i], start: start, depth: depth})
+		default:
+			if c == ')' {
+				depth--
+				if depth < 0 {
+					return nil, errors.New("unbalanced parentheses")
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: tokPunct, text: string(c), start: i, depth: depth})
+			if c == '(' {
+				depth++
+			}
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+	return tokens, nil
+}
+
+// isLineComment reports whether src starts with a comment running to the
+// end of the line. MySQL needs white space after "--" and also accepts "#".
+func isLineComment(src string, d Dialect) bool {
+	if d != DialectMySQL {
+		return strings.HasPrefix(src, "--")
+	}
+	if src[0] == '#' {
+		return true
+	}
+	return strings.HasPrefix(src, "--") && (len(src) == 2 || src[2] <= ' ')
+}
+
+// quotedEnd returns the index after the closing quote of the quoted text
+// starting at i. Doubled quotes are escapes; so are backslashes when
+// backslash is set.
+func quotedEnd(src string, i int, quote byte, backslash bool) (int, error) {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if backslash {
+				j++
+			}
+		case quote:
+			if j+1 < len(src) && src[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1, nil
+		}
+	}
+	return 0, errors.New("unterminated quoted text")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// guard checks statements before they run.
+type guard struct {
+	dialect         Dialect
+	schema          string
+	allowedTables   map[string]bool
+	deniedFunctions map[string]bool
+}
+
+// checkedQuery is a statement that passed the guard.
+type checkedQuery struct {
+	tokens []sqlToken
+	sql    string
+	tables []string
+}
+
+// check accepts a single read-only SELECT or WITH statement that calls no
+// denied function and, with an allowlist, reads only allowed tables.
+func (g *guard) check(query string) (*checkedQuery, error) {
+	tokens, err := tokenize(query, g.dialect)
+	if err != nil {
+		return nil, err
+	}
+	// Drop trailing semicolons; any other one separates statements.
+	end := len(query)
+	for len(tokens) > 0 && tokens[len(tokens)-1].text == ";" && tokens[len(tokens)-1].kind == tokPunct {
+		end = tokens[len(tokens)-1].start
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty statement")
+	}
+	// Token offsets index into sql, so only trailing space is trimmed.
+	q := &checkedQuery{tokens: tokens, sql: strings.TrimRight(query[:end], " \t\r\n")}
+	switch first := tokens[0].upper(); first {
+	case "SELECT", "WITH":
+	default:
+		return nil, fmt.Errorf("only SELECT and WITH statements are allowed, got %q", tokens[0].text)
+	}
+	for i, t := range tokens {
+		if t.kind == tokPunct && t.text == ";" {
+			return nil, errors.New("only one statement is allowed")
+		}
+		if t.kind == tokParam {
+			return nil, errors.New("statement parameters are not supported")
+		}
+		if kw := t.upper(); deniedKeywords[kw] {
+			return nil, fmt.Errorf("%s is not allowed in a read-only query", kw)
+		}
+		// Quoted names call the function too, e.g. "pg_sleep"(1) or
+		// `sleep`(1).
+		if (t.kind == tokWord || t.kind == tokQuoted) && i+1 < len(tokens) &&
+			tokens[i+1].text == "(" && tokens[i+1].kind == tokPunct {
+			if name := strings.ToLower(t.ident()); g.deniedFunctions[name] {
+				return nil, fmt.Errorf("function %s is not allowed", name)
+			}
+		}
+	}
+	ctes := cteNames(tokens)
+	tables, functions, err := tableRefs(tokens, ctes)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.allowedTables) > 0 && len(functions) > 0 {
+		// Table functions can read any table, so they would bypass the
+		// allowlist.
+		return nil, fmt.Errorf("table function %s is not allowed with a table allowlist", functions[0])
+	}
+	for _, table := range tables {
+		if !g.tableAllowed(table) {
+			return nil, fmt.Errorf("table %s is not allowed", table)
+		}
+	}
+	q.tables = tables
+	return q, nil
+}
+
+// tableAllowed reports whether a possibly qualified table name is on the
+// allowlist. An unqualified allowlist entry matches the table in the
+// configured schema only.
+func (g *guard) tableAllowed(table string) bool {
+	if len(g.allowedTables) == 0 {
+		return true
+	}
+	if g.allowedTables[table] {
+		return true
+	}
+	dot := strings.LastIndexByte(table, '.')
+	if dot < 0 || g.schema == "" {
+		return false
+	}
+	return strings.EqualFold(table[:dot], g.schema) && g.allowedTables[table[dot+1:]]
+}
+
+// cteNames returns the names defined by the leading WITH clause with the
+// token index from which each may be used in place of a table: after its
+// body, or within it for recursive ones. Names defined by nested WITH
+// clauses are treated as tables, which only errs on the side of refusing.
+func cteNames(tokens []sqlToken) map[string]int {
+	names := make(map[string]int)
+	if tokens[0].upper() != "WITH" {
+		return names
+	}
+	j := 1
+	recursive := j < len(tokens) && tokens[j].upper() == "RECURSIVE"
+	if recursive {
+		j++
+	}
+	for j < len(tokens) && (tokens[j].kind == tokWord || tokens[j].kind == tokQuoted) {
+		name := tokens[j].ident()
+		j++
+		if j < len(tokens) && tokens[j].text == "(" {
+			j = skipParens(tokens, j)
+		}
+		// Skip AS and MATERIALIZED to the body, then past it.
+		for j < len(tokens) && tokens[j].text != "(" {
+			j++
+		}
+		if recursive {
+			names[name] = j
+		}
+		j = skipParens(tokens, j)
+		if !recursive {
+			names[name] = j
+		}
+		if j >= len(tokens) || tokens[j].text != "," {
+			break
+		}
+		j++
+	}
+	return names
+}
+
+// skipParens returns the index after the parenthesis group opening at i.
+func skipParens(tokens []sqlToken, i int) int {
+	if i >= len(tokens) {
+		return i
+	}
+	depth := tokens[i].depth
+	for j := i + 1; j < len(tokens); j++ {
+		if tokens[j].kind == tokPunct && tokens[j].text == ")" && tokens[j].depth == depth {
+			return j + 1
+		}
+	}
+	return len(tokens)
+}
+
+// tableRefs returns the tables named after FROM and JOIN anywhere in the
+// statement, including subqueries, except references to CTEs, and the
+// table functions called there.
+func tableRefs(tokens []sqlToken, ctes map[string]int) (tables, functions []string, err error) {
+	type group struct {
+		call, query bool
+	}
+	var (
+		seen   = make(map[string]bool)
+		groups = []group{{}}
+	)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.kind == tokPunct && t.text == "(":
+			groups = append(groups, group{call: i > 0 && tokens[i-1].kind == tokWord})
+			continue
+		case t.kind == tokPunct && t.text == ")":
+			groups = groups[:len(groups)-1]
+			continue
+		}
+		switch t.upper() {
+		case "SELECT", "WITH":
+			groups[len(groups)-1].query = true
+			continue
+		case "FROM":
+			// EXTRACT(x FROM y), SUBSTRING(x FROM y) and IS DISTINCT FROM
+			// do not name tables.
+			g := groups[len(groups)-1]
+			if (g.call && !g.query) || (i > 0 && tokens[i-1].upper() == "DISTINCT") {
+				continue
+			}
+		case "JOIN", "STRAIGHT_JOIN":
+		default:
+			continue
+		}
+		j := i + 1
+		for {
+			for j < len(tokens) && (tokens[j].upper() == "LATERAL" || tokens[j].upper() == "ONLY") {
+				j++
+			}
+			if j >= len(tokens) {
+				return nil, nil, errors.New("missing table after FROM or JOIN")
+			}
+			if tokens[j].text == "(" && tokens[j].kind == tokPunct {
+				// A subquery, scanned on its own.
+				j = skipParens(tokens, j)
+			} else {
+				var name string
+				name, j, err = qualifiedName(tokens, j)
+				if err != nil {
+					return nil, nil, err
+				}
+				if j < len(tokens) && tokens[j].text == "(" && tokens[j].kind == tokPunct {
+					functions = append(functions, name)
+					j = skipParens(tokens, j)
+				} else if from, ok := ctes[name]; (!ok || i < from) && !seen[name] {
+					seen[name] = true
+					tables = append(tables, name)
+				}
+			}
+			// Skip the alias and any column aliases.
+			if j < len(tokens) && tokens[j].upper() == "AS" {
+				j++
+			}
+			if j < len(tokens) && (tokens[j].kind == tokQuoted ||
+				(tokens[j].kind == tokWord && !clauseKeywords[tokens[j].upper()])) {
+				j++
+				if j < len(tokens) && tokens[j].text == "(" {
+					j = skipParens(tokens, j)
+				}
+			}
+			if j < len(tokens) && tokens[j].text == "," && tokens[j].depth == tokens[i].depth {
+				j++
+				continue
+			}
+			break
+		}
+	}
+	return tables, functions, nil
+}
+
+// qualifiedName reads a dotted name starting at i.
+func qualifiedName(tokens []sqlToken, i int) (string, int, error) {
+	var parts []string
+	for {
+		if i >= len(tokens) || (tokens[i].kind != tokWord && tokens[i].kind != tokQuoted) {
+			return "", i, errors.New("expected a table name after FROM or JOIN")
+		}
+		parts = append(parts, tokens[i].ident())
+		i++
+		if i < len(tokens) && tokens[i].text == "." && tokens[i].kind == tokPunct {
+			i++
+			continue
+		}
+		return strings.Join(parts, "."), i, nil
+	}
+}
+
+// withLimit returns the statement with a LIMIT clause when it has none at
+// the top level. The clause goes before a top-level OFFSET, and before
+// ClickHouse SETTINGS and FORMAT clauses.
+func (q *checkedQuery) withLimit(limit int, d Dialect) string {
+	insert := len(q.sql)
+	for _, t := range q.tokens {
+		if t.depth != 0 {
+			continue
+		}
+		switch t.upper() {
+		case "LIMIT", "FETCH":
+			return q.sql
+		case "OFFSET":
+			if insert == len(q.sql) {
+				insert = t.start
+			}
+		case "SETTINGS", "FORMAT":
+			if d == DialectClickHouse && insert == len(q.sql) {
+				insert = t.start
+			}
+		}
+	}
+	clause := "LIMIT " + strconv.Itoa(limit)
+	if insert == len(q.sql) {
+		return q.sql + "\n" + clause
+	}
+	return q.sql[:insert] + clause + " " + q.sql[insert:]
+}
@@ -0,0 +1,139 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGuard(d Dialect, allowed ...string) *guard {
+	g := &guard{
+		dialect:         d,
+		schema:          "public",
+		allowedTables:   make(map[string]bool),
+		deniedFunctions: make(map[string]bool),
+	}
+	for _, t := range allowed {
+		g.allowedTables[t] = true
+	}
+	for _, f := range defaultDeniedFunctions {
+		g.deniedFunctions[f] = true
+	}
+	return g
+}
+
+func TestGuard_Accepts(t *testing.T) {
+	g := newTestGuard(DialectPostgres, "orders", "customers")
+	for query, tables := range map[string][]string{
+		"SELECT * FROM orders;": {"orders"},
+		"select o.id from Orders o join public.customers c on c.id = o.cid":        {"orders", "public.customers"},
+		"SELECT * FROM orders, customers AS c WHERE true":                          {"orders", "customers"},
+		`SELECT "id" FROM "orders" WHERE note = 'DELETE FROM secrets'`:             {"orders"},
+		"SELECT extract(year FROM created_at), substring(name FROM 2) FROM orders": {"orders"},
+		"SELECT * FROM orders WHERE a IS DISTINCT FROM b":                          {"orders"},
+		"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent r":             {"orders"},
+		"SELECT (SELECT count(*) FROM customers) FROM orders -- FROM secrets":      {"customers", "orders"},
+		"SELECT $$ ; DROP TABLE orders $$ FROM orders":                             {"orders"},
+		"SELECT E'it\\'s; DELETE' FROM orders":                                     {"orders"},
+	} {
+		q, err := g.check(query)
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, tables, q.tables, query)
+		}
+	}
+}
+
+func TestGuard_Rejects(t *testing.T) {
+	g := newTestGuard(DialectPostgres, "orders")
+	for query, want := range map[string]string{
+		"":                            "empty statement",
+		"DELETE FROM orders":          "only SELECT and WITH",
+		"SELECT 1; DROP TABLE orders": "only one statement",
+		"WITH d AS (DELETE FROM orders RETURNING *) SELECT * FROM d":    "DELETE is not allowed",
+		"SELECT * INTO copy FROM orders":                                "INTO is not allowed",
+		"SELECT * FROM orders FOR UPDATE":                               "UPDATE is not allowed",
+		"SELECT * FROM orders FOR SHARE":                                "SHARE is not allowed",
+		"SELECT pg_sleep(10) FROM orders":                               "function pg_sleep",
+		"SELECT * FROM secrets":                                         "table secrets is not allowed",
+		"SELECT * FROM other.orders":                                    "table other.orders is not allowed",
+		"SELECT * FROM orders o JOIN (SELECT * FROM secrets) s ON true": "table secrets",
+		"SELECT * FROM orders, secrets":                                 "table secrets",
+		"SELECT * FROM generate_series(1, 3)":                           "table function generate_series",
+		"WITH secrets AS (SELECT * FROM secrets) SELECT * FROM secrets": "table secrets",
+		"WITH x AS (TABLE secrets) SELECT * FROM x":                     "TABLE is not allowed",
+		"SELECT * FROM orders WHERE id = $1":                            "parameters",
+		"SELECT (1":                                                     "unbalanced",
+		"SELECT 'open":                                                  "unterminated",
+	} {
+		_, err := g.check(query)
+		assert.ErrorContains(t, err, want, query)
+	}
+}
+
+func TestGuard_Dialects(t *testing.T) {
+	// SQLite does not escape with backslashes, so the string ends early and
+	// the subquery is seen.
+	_, err := newTestGuard(DialectSQLite, "orders").check(`SELECT 'a\', (SELECT x FROM secrets), '' FROM orders`)
+	assert.ErrorContains(t, err, "table secrets")
+
+	// In MySQL "--" needs a space to start a comment.
+	_, err = newTestGuard(DialectMySQL, "orders").check("SELECT 1 --1 UNION SELECT x FROM secrets\nFROM orders")
+	assert.ErrorContains(t, err, "table secrets")
+	_, err = newTestGuard(DialectMySQL, "orders").check("SELECT /*!50000 SLEEP(1) */ 1 FROM orders")
+	assert.ErrorContains(t, err, "executable comments")
+	q, err := newTestGuard(DialectMySQL, "orders").check("SELECT `id` FROM `orders` # FROM secrets")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, q.tables)
+
+	_, err = newTestGuard(DialectClickHouse).check("SELECT * FROM url('http://example.com/x', CSV)")
+	assert.ErrorContains(t, err, "function url")
+}
+
+func TestGuard_QuotedFunctionNames(t *testing.T) {
+	for _, tt := range []struct {
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{DialectPostgres, `SELECT "pg_sleep"(10)`, "function pg_sleep"},
+		{DialectPostgres, `SELECT "dblink_exec"('host=x', 'DROP TABLE t')`, "function dblink_exec"},
+		{DialectPostgres, `SELECT pg_catalog."PG_SLEEP"(1)`, "function pg_sleep"},
+		{DialectSQLite, `SELECT "load_extension"('/tmp/x.so')`, "function load_extension"},
+		{DialectSQLite, "SELECT `load_extension`('/tmp/x.so')", "function load_extension"},
+		{DialectSQLite, `SELECT [load_extension]('/tmp/x.so')`, "function load_extension"},
+		{DialectMySQL, "SELECT `sleep`(10)", "function sleep"},
+		{DialectClickHouse, "SELECT * FROM `url`('http://example.com/x', CSV)", "function url"},
+	} {
+		_, err := newTestGuard(tt.dialect).check(tt.query)
+		assert.ErrorContains(t, err, tt.want, tt.query)
+	}
+}
+
+func TestWithLimit(t *testing.T) {
+	g := newTestGuard(DialectPostgres)
+	for query, want := range map[string]string{
+		"SELECT * FROM orders;":                          "SELECT * FROM orders\nLIMIT 10",
+		"SELECT * FROM orders -- note":                   "SELECT * FROM orders -- note\nLIMIT 10",
+		"SELECT * FROM orders LIMIT 5":                   "SELECT * FROM orders LIMIT 5",
+		"SELECT * FROM orders OFFSET 5":                  "SELECT * FROM orders LIMIT 10 OFFSET 5",
+		"SELECT * FROM (SELECT * FROM orders LIMIT 3) o": "SELECT * FROM (SELECT * FROM orders LIMIT 3) o\nLIMIT 10",
+		"SELECT * FROM orders FETCH FIRST 3 ROWS ONLY":   "SELECT * FROM orders FETCH FIRST 3 ROWS ONLY",
+	} {
+		q, err := g.check(query)
+		require.NoError(t, err, query)
+		assert.Equal(t, want, q.withLimit(10, DialectPostgres), query)
+	}
+	q, err := newTestGuard(DialectClickHouse).check("SELECT * FROM events SETTINGS max_threads = 1")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events LIMIT 10 SETTINGS max_threads = 1", q.withLimit(10, DialectClickHouse))
+}
@@ -0,0 +1,311 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	gosql "database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/artifact"
+	"trpc.group/trpc-go/trpc-agent-go/log"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+const csvMimeType = "text/csv"
+
+type runQueryInput struct {
+	Query   string `json:"query" jsonschema:"description=A single read-only SELECT or WITH statement"`
+	MaxRows *int   `json:"max_rows,omitempty" jsonschema:"description=Maximum number of rows to return; at most the configured limit"`
+}
+
+type runQueryOutput struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	RowCount  int      `json:"row_count"`
+	Truncated bool     `json:"truncated,omitempty"`
+	// Artifact holds the full result when only a preview is in Rows.
+	Artifact *artifactRef `json:"artifact,omitempty"`
+	Note     string       `json:"note,omitempty"`
+}
+
+type artifactRef struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	MimeType string `json:"mime_type"`
+}
+
+// queryResult holds the rows read from a statement.
+type queryResult struct {
+	columns   []string
+	rows      [][]any
+	truncated bool
+}
+
+// runQuery checks and runs a query, returning small results inline and
+// saving large ones as a CSV artifact.
+func (ts *toolSet) runQuery(ctx context.Context, in runQueryInput) (runQueryOutput, error) {
+	c := ts.config
+	limit := c.maxRows
+	if in.MaxRows != nil {
+		if *in.MaxRows < 1 || *in.MaxRows > c.maxRows {
+			return runQueryOutput{}, fmt.Errorf("max_rows must be between 1 and %d", c.maxRows)
+		}
+		limit = *in.MaxRows
+	}
+	checked, err := ts.guard.check(in.Query)
+	if err != nil {
+		return runQueryOutput{}, fmt.Errorf("query rejected: %w", err)
+	}
+	// One extra row tells whether the result was truncated.
+	query := checked.withLimit(limit+1, c.dialect)
+	log.Debug("Running SQL tool query", "query", query)
+
+	ctx, cancel := ts.withTimeout(ctx)
+	defer cancel()
+	result, err := ts.readOnlyQuery(ctx, query, limit)
+	if err != nil {
+		return runQueryOutput{}, err
+	}
+	out := runQueryOutput{
+		Columns:   result.columns,
+		Rows:      result.rows,
+		RowCount:  len(result.rows),
+		Truncated: result.truncated,
+	}
+	if out.Truncated {
+		out.Note = fmt.Sprintf("The query returned more than %d rows; only the first %d are included.",
+			limit, limit)
+	}
+	preview := inlineRows(result.rows, c.maxInlineRows, c.maxInlineBytes)
+	if preview == len(result.rows) {
+		return out, nil
+	}
+
+	out.Rows = result.rows[:preview]
+	ref, err := saveCSV(ctx, result)
+	if err != nil {
+		log.Warnf("sql tool set: save query result as artifact: %v", err)
+		out.Note = joinNotes(out.Note, fmt.Sprintf(
+			"Only the first %d of %d rows are shown; the full result could not be saved: %v.",
+			preview, out.RowCount, err))
+		return out, nil
+	}
+	out.Artifact = ref
+	out.Note = joinNotes(out.Note, fmt.Sprintf(
+		"Only the first %d of %d rows are shown; all rows are saved as the CSV artifact %s.",
+		preview, out.RowCount, ref.Name))
+	return out, nil
+}
+
+// readOnlyQuery runs the query in a read-only transaction where the
+// dialect supports one, or on a query_only connection for SQLite, as a
+// second line of defense behind the guard.
+func (ts *toolSet) readOnlyQuery(ctx context.Context, query string, limit int) (*queryResult, error) {
+	switch ts.config.dialect {
+	case DialectPostgres, DialectMySQL:
+	case DialectSQLite:
+		return ts.queryOnlyQuery(ctx, query, limit)
+	default:
+		result, err := ts.query(ctx, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("run query: %w", err)
+		}
+		return result, nil
+	}
+	tx, err := ts.db.BeginTx(ctx, &gosql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("begin read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+	result, err := queryRows(ctx, tx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("run query: %w", err)
+	}
+	return result, nil
+}
+
+// queryOnlyQuery runs the query on a SQLite connection with PRAGMA
+// query_only set. The pragma is cleared before the connection returns to
+// the pool, since other users of the database may write through it; a
+// connection where that fails is discarded.
+func (ts *toolSet) queryOnlyQuery(ctx context.Context, query string, limit int) (*queryResult, error) {
+	conn, err := ts.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, fmt.Errorf("enable query_only: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only = OFF"); err != nil {
+			log.Warnf("sql tool set: disable query_only: %v", err)
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+	result, err := queryRows(ctx, conn, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("run query: %w", err)
+	}
+	return result, nil
+}
+
+func (ts *toolSet) query(ctx context.Context, query string, limit int) (*queryResult, error) {
+	return queryRows(ctx, ts.db, query, limit)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*gosql.Rows, error)
+}
+
+// queryRows reads up to limit rows, noting whether more were available.
+func queryRows(ctx context.Context, q queryer, query string, limit int) (*queryResult, error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &queryResult{columns: columns, rows: [][]any{}}
+	for rows.Next() {
+		if len(result.rows) == limit {
+			result.truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = jsonValue(v)
+		}
+		result.rows = append(result.rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (ts *toolSet) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ts.config.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, ts.config.timeout)
+}
+
+// jsonValue converts a scanned value to one that encodes well as JSON.
+func jsonValue(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		if utf8.Valid(x) {
+			return string(x)
+		}
+		return base64.StdEncoding.EncodeToString(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return x
+	}
+}
+
+// inlineRows returns how many leading rows fit within the row and size
+// limits.
+func inlineRows(rows [][]any, maxRows, maxBytes int) int {
+	size := 0
+	for i, row := range rows {
+		if i == maxRows {
+			return i
+		}
+		b, err := json.Marshal(row)
+		if err != nil {
+			return i
+		}
+		if size += len(b) + 1; size > maxBytes {
+			return i
+		}
+	}
+	return len(rows)
+}
+
+// saveCSV saves the result through the artifact service of the invocation.
+func saveCSV(ctx context.Context, result *queryResult) (*artifactRef, error) {
+	cc, err := agent.NewCallbackContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(result.columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(result.columns))
+	for _, row := range result.rows {
+		for i, v := range row {
+			record[i] = csvValue(v)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	id, ok := tool.ToolCallIDFromContext(ctx)
+	if !ok || id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	name := "sql_result_" + id + ".csv"
+	version, err := cc.SaveArtifact(name, &artifact.Artifact{
+		Data:     buf.Bytes(),
+		MimeType: csvMimeType,
+		Name:     name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &artifactRef{Name: name, Version: version, MimeType: csvMimeType}, nil
+}
+
+func csvValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func joinNotes(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
@@ -0,0 +1,274 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+// Package sql provides a read-only toolset over a database/sql database.
+//
+// The toolset offers three tools: list_tables, describe_table, which also
+// returns sample rows, and run_query. Queries pass a guard before they
+// run: it tokenizes the statement in the configured dialect, accepts a
+// single SELECT or WITH statement, rejects writes, locks and functions
+// with side effects, and checks every table read against an optional
+// allowlist. A LIMIT is added when the query has none, each statement runs
+// under a timeout, and results larger than the inline limit are saved as
+// CSV artifacts with only a preview returned to the model.
+//
+// The guard complements, and does not replace, database credentials that
+// only grant read access.
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/function"
+)
+
+// Dialect selects the SQL syntax and catalog queries of a database.
+type Dialect string
+
+// Supported dialects.
+const (
+	DialectSQLite     Dialect = "sqlite"
+	DialectMySQL      Dialect = "mysql"
+	DialectPostgres   Dialect = "postgres"
+	DialectClickHouse Dialect = "clickhouse"
+)
+
+const (
+	// defaultToolSetName is the default name for the SQL tool set.
+	defaultToolSetName = "sql"
+	// defaultMaxRows is the default number of rows a query returns.
+	defaultMaxRows = 1000
+	// defaultMaxInlineRows is the default number of rows returned inline.
+	defaultMaxInlineRows = 50
+	// defaultMaxInlineBytes is the default size of rows returned inline.
+	defaultMaxInlineBytes = 16 * 1024
+	// defaultSampleRows is the default number of rows describe_table shows.
+	defaultSampleRows = 3
+	// maxSampleRows bounds the sample rows the model can ask for.
+	maxSampleRows = 20
+	// defaultTimeout is the default timeout of a statement.
+	defaultTimeout = 30 * time.Second
+)
+
+// Option is a functional option for configuring the SQL tool set.
+type Option func(*config)
+
+// config holds the configuration for the SQL tool set.
+type config struct {
+	name            string
+	dialect         Dialect
+	schema          string
+	allowedTables   []string
+	deniedFunctions []string
+	maxRows         int
+	maxInlineRows   int
+	maxInlineBytes  int
+	sampleRows      int
+	timeout         time.Duration
+}
+
+// WithName sets the name of the tool set.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithDialect sets the dialect. Without it the dialect is detected from
+// the driver type.
+func WithDialect(d Dialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
+
+// WithSchema sets the schema, or database for MySQL and ClickHouse, whose
+// tables are listed and described. The default is the connection's
+// current schema.
+func WithSchema(schema string) Option {
+	return func(c *config) {
+		c.schema = schema
+	}
+}
+
+// WithAllowedTables limits the tables the tools may read. Names are
+// matched case-insensitively; an unqualified name matches the table in the
+// schema set with WithSchema. Table functions are rejected when an
+// allowlist is set.
+func WithAllowedTables(tables ...string) Option {
+	return func(c *config) {
+		c.allowedTables = append(c.allowedTables, tables...)
+	}
+}
+
+// WithDeniedFunctions adds functions queries may not call, in addition to
+// built-in ones with side effects such as pg_sleep and load_extension.
+func WithDeniedFunctions(names ...string) Option {
+	return func(c *config) {
+		c.deniedFunctions = append(c.deniedFunctions, names...)
+	}
+}
+
+// WithMaxRows sets the maximum number of rows a query returns, used as
+// the LIMIT of queries without one. The default is 1000.
+func WithMaxRows(n int) Option {
+	return func(c *config) {
+		c.maxRows = n
+	}
+}
+
+// WithMaxInlineRows sets how many rows are returned in the tool result.
+// Larger results are saved as a CSV artifact when an artifact service is
+// available. The default is 50.
+func WithMaxInlineRows(n int) Option {
+	return func(c *config) {
+		c.maxInlineRows = n
+	}
+}
+
+// WithMaxInlineBytes sets the JSON size of rows returned in the tool
+// result, past which the result is saved as a CSV artifact. The default
+// is 16 KiB.
+func WithMaxInlineBytes(n int) Option {
+	return func(c *config) {
+		c.maxInlineBytes = n
+	}
+}
+
+// WithSampleRows sets how many rows describe_table returns by default.
+// The default is 3; zero disables samples.
+func WithSampleRows(n int) Option {
+	return func(c *config) {
+		c.sampleRows = n
+	}
+}
+
+// WithTimeout sets the timeout of each statement, including reading its
+// rows. The default is 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// toolSet is a set of read-only database tools.
+type toolSet struct {
+	config *config
+	db     *gosql.DB
+	guard  *guard
+	tools  []tool.Tool
+}
+
+// NewToolSet creates a SQL tool set over db with the provided options. The
+// tool set does not close db.
+func NewToolSet(db *gosql.DB, opts ...Option) (tool.ToolSet, error) {
+	if db == nil {
+		return nil, errors.New("sql tool set: db is nil")
+	}
+	c := &config{
+		name:           defaultToolSetName,
+		maxRows:        defaultMaxRows,
+		maxInlineRows:  defaultMaxInlineRows,
+		maxInlineBytes: defaultMaxInlineBytes,
+		sampleRows:     defaultSampleRows,
+		timeout:        defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.dialect == "" {
+		c.dialect = detectDialect(db)
+		if c.dialect == "" {
+			return nil, fmt.Errorf("sql tool set: cannot detect the dialect of driver %T, use WithDialect", db.Driver())
+		}
+	}
+	switch c.dialect {
+	case DialectSQLite, DialectMySQL, DialectPostgres, DialectClickHouse:
+	default:
+		return nil, fmt.Errorf("sql tool set: unsupported dialect %q", c.dialect)
+	}
+	if c.maxRows < 1 || c.maxInlineRows < 0 || c.sampleRows < 0 || c.sampleRows > maxSampleRows {
+		return nil, fmt.Errorf("sql tool set: max rows must be positive, "+
+			"inline rows non-negative and sample rows between 0 and %d", maxSampleRows)
+	}
+
+	g := &guard{
+		dialect:         c.dialect,
+		schema:          strings.ToLower(c.schema),
+		allowedTables:   make(map[string]bool),
+		deniedFunctions: make(map[string]bool),
+	}
+	for _, t := range c.allowedTables {
+		g.allowedTables[strings.ToLower(t)] = true
+	}
+	for _, f := range append(append([]string(nil), defaultDeniedFunctions...), c.deniedFunctions...) {
+		g.deniedFunctions[strings.ToLower(f)] = true
+	}
+	ts := &toolSet{config: c, db: db, guard: g}
+	ts.tools = []tool.Tool{
+		function.NewFunctionTool(ts.listTables,
+			function.WithName("list_tables"),
+			function.WithDescription("Lists the tables and views the agent may query."),
+		),
+		function.NewFunctionTool(ts.describeTable,
+			function.WithName("describe_table"),
+			function.WithDescription("Describes the columns of a table and returns a few sample rows."),
+		),
+		function.NewFunctionTool(ts.runQuery,
+			function.WithName("run_query"),
+			function.WithDescription(fmt.Sprintf("Runs a read-only %s SELECT query and returns its rows. "+
+				"At most %d rows are returned; large results are saved as a CSV artifact "+
+				"and only the first rows are shown.", c.dialect, c.maxRows)),
+		),
+	}
+	return ts, nil
+}
+
+// Tools implements the ToolSet interface.
+func (ts *toolSet) Tools(ctx context.Context) []tool.Tool {
+	return ts.tools
+}
+
+// Close implements the ToolSet interface.
+func (ts *toolSet) Close() error {
+	return nil
+}
+
+// Name implements the ToolSet interface.
+func (ts *toolSet) Name() string {
+	return ts.config.name
+}
+
+// detectDialect guesses the dialect from the package of the driver type.
+func detectDialect(db *gosql.DB) Dialect {
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	pkg := strings.ToLower(t.PkgPath())
+	switch {
+	case strings.Contains(pkg, "sqlite"):
+		return DialectSQLite
+	case strings.Contains(pkg, "mysql"):
+		return DialectMySQL
+	case strings.Contains(pkg, "lib/pq"), strings.Contains(pkg, "pgx"):
+		return DialectPostgres
+	case strings.Contains(pkg, "clickhouse"):
+		return DialectClickHouse
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,202 @@
+//
+// Tencent is pleased to support the open source community by making trpc-agent-go available.
+//
+// Copyright (C) 2025 Tencent.  All rights reserved.
+//
+// trpc-agent-go is licensed under the Apache License Version 2.0.
+//
+//
+
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	"trpc.group/trpc-go/trpc-agent-go/artifact"
+	"trpc.group/trpc-go/trpc-agent-go/artifact/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/session"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+func openTestDB(t *testing.T) *gosql.DB {
+	t.Helper()
+	db, err := gosql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer TEXT NOT NULL, amount REAL, note BLOB);
+CREATE TABLE secrets (token TEXT);
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE amount > 50;
+INSERT INTO secrets VALUES ('s3cr3t');`)
+	require.NoError(t, err)
+	for i := 1; i <= 30; i++ {
+		_, err := db.Exec("INSERT INTO orders (customer, amount, note) VALUES (?, ?, ?)",
+			fmt.Sprintf("c%d", i), float64(i*10), []byte{0xff, byte(i)})
+		require.NoError(t, err)
+	}
+	return db
+}
+
+func callTool(t *testing.T, ctx context.Context, ts tool.ToolSet, name, args string) (any, error) {
+	t.Helper()
+	for _, tl := range ts.Tools(ctx) {
+		if tl.Declaration().Name == name {
+			return tl.(tool.CallableTool).Call(ctx, []byte(args))
+		}
+	}
+	require.Failf(t, "tool not found", name)
+	return nil, nil
+}
+
+func TestToolSet_Catalog(t *testing.T) {
+	db := openTestDB(t)
+	ts, err := NewToolSet(db, WithAllowedTables("orders", "big_orders"))
+	require.NoError(t, err)
+	assert.Equal(t, DialectSQLite, ts.(*toolSet).config.dialect)
+
+	out, err := callTool(t, context.Background(), ts, "list_tables", `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, []tableInfo{{Name: "big_orders", Type: "view"}, {Name: "orders", Type: "table"}},
+		out.(listTablesOutput).Tables)
+
+	out, err = callTool(t, context.Background(), ts, "describe_table", `{"table":"orders","sample_rows":2}`)
+	require.NoError(t, err)
+	desc := out.(describeTableOutput)
+	assert.Equal(t, []columnInfo{
+		{Name: "id", Type: "INTEGER", Nullable: true, PrimaryKey: true},
+		{Name: "customer", Type: "TEXT", Nullable: false},
+		{Name: "amount", Type: "REAL", Nullable: true},
+		{Name: "note", Type: "BLOB", Nullable: true},
+	}, desc.Columns)
+	require.Len(t, desc.SampleRows, 2)
+	assert.Equal(t, []any{int64(1), "c1", float64(10), "/wE="}, desc.SampleRows[0])
+
+	_, err = callTool(t, context.Background(), ts, "describe_table", `{"table":"secrets"}`)
+	assert.ErrorContains(t, err, "not allowed")
+	ts, err = NewToolSet(db)
+	require.NoError(t, err)
+	_, err = callTool(t, context.Background(), ts, "describe_table", `{"table":"missing"}`)
+	assert.ErrorContains(t, err, "not found")
+	_, err = callTool(t, context.Background(), ts, "describe_table", `{"table":"orders","sample_rows":50}`)
+	assert.ErrorContains(t, err, "sample_rows")
+}
+
+func TestToolSet_RunQuery(t *testing.T) {
+	db := openTestDB(t)
+	ts, err := NewToolSet(db, WithAllowedTables("orders"), WithMaxRows(20))
+	require.NoError(t, err)
+
+	out, err := callTool(t, context.Background(), ts, "run_query",
+		`{"query":"SELECT customer, amount FROM orders WHERE id <= 2 ORDER BY id"}`)
+	require.NoError(t, err)
+	result := out.(runQueryOutput)
+	assert.Equal(t, []string{"customer", "amount"}, result.Columns)
+	assert.Equal(t, [][]any{{"c1", float64(10)}, {"c2", float64(20)}}, result.Rows)
+	assert.False(t, result.Truncated)
+	assert.Nil(t, result.Artifact)
+
+	// The automatic LIMIT fetches one row past max_rows to detect truncation.
+	out, err = callTool(t, context.Background(), ts, "run_query", `{"query":"SELECT id FROM orders","max_rows":3}`)
+	require.NoError(t, err)
+	result = out.(runQueryOutput)
+	assert.Equal(t, 3, result.RowCount)
+	assert.Contains(t, result.Note, "first 3")
+
+	// A LIMIT larger than max_rows is capped while reading.
+	out, err = callTool(t, context.Background(), ts, "run_query", `{"query":"SELECT id FROM orders LIMIT 100"}`)
+	require.NoError(t, err)
+	result = out.(runQueryOutput)
+	assert.Equal(t, 20, result.RowCount)
+	assert.True(t, result.Truncated)
+
+	for args, want := range map[string]string{
+		`{"query":"DELETE FROM orders"}`:                     "only SELECT and WITH",
+		`{"query":"SELECT * FROM secrets"}`:                  "table secrets is not allowed",
+		`{"query":"SELECT load_extension('x') FROM orders"}`: "function load_extension",
+		`{"query":"SELECT 1 FROM orders","max_rows":50}`:     "max_rows",
+	} {
+		_, err := callTool(t, context.Background(), ts, "run_query", args)
+		assert.ErrorContains(t, err, want, args)
+	}
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM orders").Scan(&count))
+	assert.Equal(t, 30, count)
+}
+
+func TestToolSet_SQLiteQueryOnly(t *testing.T) {
+	db := openTestDB(t)
+	// One connection, so the pragma would leak into the next use if it
+	// were not cleared.
+	db.SetMaxOpenConns(1)
+	ts, err := NewToolSet(db)
+	require.NoError(t, err)
+
+	// A write that got past the guard still fails.
+	_, err = ts.(*toolSet).readOnlyQuery(context.Background(), "INSERT INTO secrets VALUES ('x') RETURNING token", 10)
+	assert.ErrorContains(t, err, "readonly")
+
+	_, err = db.Exec("INSERT INTO secrets VALUES ('y')")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM secrets").Scan(&count))
+	assert.Equal(t, 2, count)
+}
+
+func TestToolSet_LargeResultArtifact(t *testing.T) {
+	db := openTestDB(t)
+	ts, err := NewToolSet(db, WithMaxInlineRows(5))
+	require.NoError(t, err)
+
+	service := inmemory.NewService()
+	inv := agent.NewInvocation(
+		agent.WithInvocationSession(session.NewSession("app", "user", "s1")),
+		agent.WithInvocationArtifactService(service),
+	)
+	ctx := context.WithValue(agent.NewInvocationContext(context.Background(), inv),
+		tool.ContextKeyToolCallID{}, "call-1")
+
+	out, err := callTool(t, ctx, ts, "run_query", `{"query":"SELECT id, customer FROM orders ORDER BY id"}`)
+	require.NoError(t, err)
+	result := out.(runQueryOutput)
+	assert.Equal(t, 30, result.RowCount)
+	assert.Len(t, result.Rows, 5)
+	require.NotNil(t, result.Artifact)
+	assert.Equal(t, "sql_result_call-1.csv", result.Artifact.Name)
+	assert.Contains(t, result.Note, "saved as the CSV artifact")
+
+	saved, err := service.LoadArtifact(context.Background(),
+		artifact.SessionInfo{AppName: "app", UserID: "user", SessionID: "s1"}, result.Artifact.Name, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", saved.MimeType)
+	assert.Contains(t, string(saved.Data), "id,customer\n1,c1\n2,c2\n")
+	assert.Contains(t, string(saved.Data), "30,c30\n")
+
+	// Without an artifact service a preview is returned with a note.
+	out, err = callTool(t, context.Background(), ts, "run_query", `{"query":"SELECT id FROM orders"}`)
+	require.NoError(t, err)
+	result = out.(runQueryOutput)
+	assert.Len(t, result.Rows, 5)
+	assert.Nil(t, result.Artifact)
+	assert.Contains(t, result.Note, "could not be saved")
+}
+
+func TestNewToolSet_Validation(t *testing.T) {
+	db := openTestDB(t)
+	_, err := NewToolSet(nil)
+	assert.ErrorContains(t, err, "db is nil")
+	_, err = NewToolSet(db, WithDialect("oracle"))
+	assert.ErrorContains(t, err, "unsupported dialect")
+	_, err = NewToolSet(db, WithMaxRows(0))
+	assert.ErrorContains(t, err, "max rows")
+	_, err = NewToolSet(db, WithSampleRows(maxSampleRows+1))
+	assert.ErrorContains(t, err, "sample rows")
+}